@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/bench"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage/factory"
+)
+
+// 🧩 student-api bench [flags]
+// ---------------------------------------------------------
+// Fires concurrent CRUD traffic and prints p50/p95/p99 latency per operation.
+//   - with -target   → HTTP against a running instance, authenticated with
+//     -api-key or -token (STUDENT_API_KEY / STUDENT_API_TOKEN) when it has auth on
+//   - without        → in-process against the storage from -config / CONFIG_PATH
+//
+// Exits 1 when the overall error rate exceeds -max-error-rate.
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	target := fs.String("target", "", "Base URL of a running instance (e.g. http://localhost:8082)")
+	configPath := fs.String("config", os.Getenv("CONFIG_PATH"), "Config file for in-process mode")
+	concurrency := fs.Int("concurrency", 10, "Number of concurrent workers")
+	duration := fs.Duration("duration", 10*time.Second, "How long to generate load")
+	timeout := fs.Duration("timeout", 5*time.Second, "Per-request timeout (HTTP mode)")
+	mix := fs.String("mix", "create=20,get=50,list=10,update=20", "Operation weights")
+	apiKey := fs.String("api-key", os.Getenv("STUDENT_API_KEY"), "API key for a target with auth enabled (HTTP mode)")
+	token := fs.String("token", os.Getenv("STUDENT_API_TOKEN"), "Bearer token instead of an API key (HTTP mode)")
+	maxErrorRate := fs.Float64("max-error-rate", 0.01, "Fail when errors/requests exceeds this ratio")
+	fs.Parse(args)
+
+	weights, err := parseMix(*mix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	if *apiKey != "" && *token != "" {
+		fmt.Fprintln(os.Stderr, "❌ -api-key and -token are mutually exclusive")
+		return 2
+	}
+
+	var tgt bench.Target
+	if *target != "" {
+		// without credentials, a server with auth on answers every request 401
+		httpTarget := bench.NewHTTPTarget(*target, *timeout)
+		httpTarget.APIKey, httpTarget.Token = *apiKey, *token
+		tgt = httpTarget
+		fmt.Printf("🏋️ Benchmarking %s with %d workers for %s\n", *target, *concurrency, *duration)
+	} else {
+		if *configPath == "" {
+			fmt.Fprintln(os.Stderr, "❌ either -target or -config (CONFIG_PATH) is required")
+			return 2
+		}
+		cfg := config.MustLoadPath(*configPath)
+		store, err := factory.NewStorage(*cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to initialize database: %v\n", err)
+			return 1
+		}
+		tgt = bench.StorageTarget{Storage: store}
+		fmt.Printf("🏋️ Benchmarking %s storage in-process with %d workers for %s\n", cfg.DBType, *concurrency, *duration)
+	}
+
+	report, err := bench.Run(tgt, bench.Options{
+		Concurrency: *concurrency,
+		Duration:    *duration,
+		Mix:         weights,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	report.Print(os.Stdout)
+
+	count, errors := report.Total()
+	if count > 0 && float64(errors)/float64(count) > *maxErrorRate {
+		fmt.Fprintf(os.Stderr, "❌ error rate %.2f%% exceeds %.2f%%\n",
+			float64(errors)/float64(count)*100, *maxErrorRate*100)
+		return 1
+	}
+	return 0
+}
+
+// parseMix reads "create=20,get=50" into a weight map.
+func parseMix(s string) (map[string]int, error) {
+	weights := map[string]int{}
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q (want op=weight)", part)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %s: %w", name, err)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
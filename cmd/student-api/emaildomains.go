@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/emaildomain"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/factory"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/validate"
+)
+
+// 🧩 student-api email-domains -from <domain> -to <domain> [-tenant <slug>] [-dry-run] [flags]
+// ---------------------------------------------------------
+// Moves the student emails on -from to -to in the database from -config /
+// CONFIG_PATH, like POST /api/admin/email-domains/migrate, and prints what
+// changed per student. Audit entries name "cli" as the actor.
+// Exits 1 when some students were left alone because of conflicts.
+func runEmailDomains(args []string) int {
+	fs := flag.NewFlagSet("email-domains", flag.ExitOnError)
+	from := fs.String("from", "", "Domain to move students off, e.g. old-school.edu")
+	to := fs.String("to", "", "Domain to move them to, e.g. school.edu")
+	slug := fs.String("tenant", "", "Tenant slug (default tenant when empty)")
+	dryRun := fs.Bool("dry-run", false, "Only print what would change")
+	configPath := fs.String("config", os.Getenv("CONFIG_PATH"), "Config file of the database")
+	fs.Parse(args)
+
+	if *from == "" || *to == "" || *configPath == "" {
+		fmt.Fprintln(os.Stderr, "❌ -from, -to and -config (CONFIG_PATH) are required")
+		return 2
+	}
+	cfg := config.MustLoadPath(*configPath)
+	validate.Apply(cfg.Validation)
+	store, err := factory.NewStorage(*cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to initialize database: %v\n", err)
+		return 1
+	}
+	if *slug != "" {
+		tenants, ok := storage.As[storage.TenantStore](store)
+		scoper, scoped := storage.As[storage.TenantScoper](store)
+		if !ok || !scoped {
+			fmt.Fprintf(os.Stderr, "❌ db_type %s does not support tenants\n", cfg.DBType)
+			return 1
+		}
+		t, err := tenants.GetTenantBySlug(*slug)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ unknown tenant %q\n", *slug)
+			return 1
+		}
+		store = scoper.ForTenant(t.ID)
+	}
+
+	migration, err := emaildomain.Migrate(store, *from, *to, *dryRun, types.AuditEntry{Actor: "cli"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	for _, change := range migration.Changes {
+		line := fmt.Sprintf("  %-8s %d %s → %s", change.Status, change.ID, change.Email, change.NewEmail)
+		if change.Status == types.DomainConflict {
+			line += fmt.Sprintf(" (held by student %d)", change.ConflictID)
+		}
+		fmt.Println(line)
+	}
+	verb := "Moved"
+	if migration.DryRun {
+		verb = "Would move"
+	}
+	fmt.Printf("📧 %s %d of %d students from %s to %s (%d conflicts)\n", verb, migration.Moved, migration.Matched, migration.From, migration.To, migration.Conflicts)
+	if migration.Conflicts > 0 {
+		return 1
+	}
+	return 0
+}
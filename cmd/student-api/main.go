@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"io"
 	"log"
 	"log/slog"
 	"net/http"
@@ -9,16 +10,121 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // ✅ time.display_timezone works on images without a zoneinfo database
 
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/buildinfo"
+	"github.com/manish-npx/go-student-api/internal/challenge"
+	"github.com/manish-npx/go-student-api/internal/chaos"
+	"github.com/manish-npx/go-student-api/internal/clock"
 	"github.com/manish-npx/go-student-api/internal/config"
-	"github.com/manish-npx/go-student-api/internal/http/handlers/student"
+	"github.com/manish-npx/go-student-api/internal/export"
+	"github.com/manish-npx/go-student-api/internal/featureflag"
+	"github.com/manish-npx/go-student-api/internal/health"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/debug"
+	"github.com/manish-npx/go-student-api/internal/http/routes"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/logger"
+	"github.com/manish-npx/go-student-api/internal/maintenance"
+	"github.com/manish-npx/go-student-api/internal/metrics"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/oidc"
+	"github.com/manish-npx/go-student-api/internal/outbox"
+	"github.com/manish-npx/go-student-api/internal/paging"
+	"github.com/manish-npx/go-student-api/internal/portal"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/roster"
+	"github.com/manish-npx/go-student-api/internal/scheduler"
+	"github.com/manish-npx/go-student-api/internal/share"
+	"github.com/manish-npx/go-student-api/internal/snapshot"
+	storagepkg "github.com/manish-npx/go-student-api/internal/storage"
 	"github.com/manish-npx/go-student-api/internal/storage/factory"
+	"github.com/manish-npx/go-student-api/internal/storage/retry"
+	"github.com/manish-npx/go-student-api/internal/token"
+	"github.com/manish-npx/go-student-api/internal/upgrade"
+	"github.com/manish-npx/go-student-api/internal/validate"
+	"github.com/manish-npx/go-student-api/internal/verification"
+	"github.com/manish-npx/go-student-api/internal/webhook"
 )
 
 func main() {
+	// 🕰️ Everything runs in UTC; time.display_timezone only shapes reports
+	clock.UseUTC()
+
+	// 🧩 Sub-commands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "bench":
+			os.Exit(runBench(os.Args[2:]))
+		case "backup":
+			os.Exit(runBackup(os.Args[2:]))
+		case "restore":
+			os.Exit(runRestore(os.Args[2:]))
+		case "email-domains":
+			os.Exit(runEmailDomains(os.Args[2:]))
+		}
+	}
+
 	// 🧩 Load config
 	cfg := config.MustLoad()
 
+	// 🧩 Setup logger
+	if err := logger.Apply(cfg.Logger); err != nil {
+		log.Fatalf("❌ Invalid logger config: %v", err)
+	}
+
+	// 📝 Log to a file instead of stderr (rotated by size/age, reopened on SIGHUP/SIGUSR1)
+	if cfg.Logger.File != "" {
+		logFile, err := logger.OpenFile(cfg.Logger.File, cfg.Logger.Rotation)
+		if err != nil {
+			log.Fatalf("❌ Invalid logger config: %v", err)
+		}
+		defer logFile.Close()
+		log.SetOutput(logFile)
+		logFile.WatchReopen(context.Background())
+	}
+
+	// 📜 Access log lines go apart from the app logs: their own file, or stdout
+	var accessOut io.Writer
+	if access := cfg.Logger.Access; access.Enabled && access.File != "" {
+		accessFile, err := logger.OpenFile(access.File, cfg.Logger.Rotation)
+		if err != nil {
+			log.Fatalf("❌ Invalid logger config: %v", err)
+		}
+		defer accessFile.Close()
+		accessFile.WatchReopen(context.Background())
+		accessOut = accessFile
+	}
+
+	// 🏷️ Which build this is, first thing in the log
+	build := buildinfo.Get()
+	slog.Info("🚀 Starting go-student-api",
+		slog.String("version", build.Version),
+		slog.String("commit", build.Commit),
+		slog.String("build_time", build.BuildTime),
+		slog.Bool("dirty", build.Dirty),
+		slog.String("go_version", build.GoVersion),
+		slog.String("env", cfg.Env),
+	)
+
+	slog.Info("🧾 Config loaded", slog.Any("config", cfg))
+
+	// 🧩 Hot reload of tunable settings on SIGHUP
+	reloader := config.NewReloader(cfg)
+	reloader.OnReload(func(c *config.Config) error {
+		return logger.Apply(c.Logger)
+	})
+
+	// 🧪 The deployment's validation policy (age range, phone, email domains); follows reloads
+	validate.Apply(cfg.Validation)
+	reloader.OnReload(func(c *config.Config) error {
+		return validate.Apply(c.Validation)
+	})
+	// appCtx lives as long as the process; background workers stop with it
+	appCtx, stopApp := context.WithCancel(context.Background())
+	defer stopApp()
+	reloader.Watch(appCtx)
+
 	// Load database (COMPLETED)
 
 	/// 🧩 Choose database based on config
@@ -30,47 +136,261 @@ func main() {
 		log.Fatalf("❌ Failed to initialize database: %v", err)
 	}
 
-	// 🧩 Setup routes
-	route := http.NewServeMux()
-	route.HandleFunc("POST /api/student", student.New(storage))
-	route.HandleFunc("GET /api/student/{id}", student.GetById(storage))
-	route.HandleFunc("GET /api/students", student.GetList(storage))
-	route.HandleFunc("PUT /api/student/{id}", student.UpdateById(storage))
+	// 💥 Dev-only fault injection (nil unless chaos.enabled); rates follow reloads
+	injector := chaos.New(cfg.Chaos, cfg.Env)
+	storage = injector.Wrap(storage)
+	reloader.OnReload(func(c *config.Config) error {
+		return injector.Apply(c.Chaos)
+	})
+
+	// 📈 Count and time every storage call (nil unless metrics.enabled)
+	storeMetrics := metrics.NewStorage(cfg.Metrics, cfg.DBType)
+	storage = storeMetrics.Wrap(storage)
+
+	// 🔁 Rerun writes that hit a transient error (each attempt is counted above)
+	storage = retry.Wrap(cfg.StorageRetry, storage)
+
+	// 🔑 Answer API key and token revocation lookups from memory once warm
+	storage = storagepkg.CacheAuth(storage, cfg.Auth.Cache.Size, cfg.Auth.Cache.TTL)
+
+	// 🩺 Ping the database in the background; /readyz reports what it sees
+	monitor := health.New(storage, cfg.Health)
+	monitor.Start(appCtx)
+
+	// 🖼️ Blob store for uploaded files
+	blobs, err := blob.New(cfg.Blob)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize blob store: %v", err)
+	}
+
+	// 📧 Email notifications (nil when notify.provider is none)
+	notifier, err := notify.New(cfg.Notify)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize notifier: %v", err)
+	}
+
+	// ⚙️ Background job queue (emails and async purges run through it)
+	queue := jobs.New(storage, cfg.Jobs)
+	jobs.RegisterDefaults(queue, storage, blobs, notifier)
+	// 🪝 Webhook deliveries run as jobs (nil when the queue is disabled)
+	hooks := webhook.New(storage, queue, cfg.Webhooks)
+	// 📤 Student exports are written by jobs into the blob store
+	exporter := export.New(storage, queue, blobs)
+	// 📸 Student snapshots too, for audit diffs
+	snapshots := snapshot.New(storage, queue, blobs)
+	queue.Start(appCtx)
+	// 📤 Student events are committed with their changes and relayed to webhooks from the outbox
+	relay := outbox.New(storage, hooks, cfg.Outbox)
+	relay.Start(appCtx)
+
+	// 🎟️ Bearer tokens (nil without auth.token_secret)
+	tokens := token.New(cfg.Auth, storage)
+
+	// 🪪 Staff sign-in through the identity provider (nil without auth.oidc.issuer)
+	idp := oidc.New(cfg.Auth.OIDC)
+
+	// 🔢 Daily per-key / per-user quotas (nil unless quotas.enabled)
+	quotas := quota.New(cfg.Quotas, storage)
+
+	// 🔄 Roster sync connectors (nil without sync.connectors)
+	syncer, err := roster.New(cfg, storage, notifier)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize roster sync: %v", err)
+	}
+
+	// ⏰ Periodic tasks (trash purge, ...) from scheduler.tasks
+	sched := scheduler.New(cfg)
+	taskMetrics := metrics.NewTasks(cfg.Metrics)
+	sched.UseMetrics(taskMetrics)
+	scheduler.RegisterDefaults(sched, storage, blobs, cfg.Trash.Retention)
+	scheduler.RegisterSync(sched, syncer)
+	scheduler.RegisterSnapshots(sched, snapshots, storage)
+	scheduler.RegisterExports(sched, exporter, storage)
+	if err := sched.Start(appCtx); err != nil {
+		log.Fatalf("❌ Failed to start scheduler: %v", err)
+	}
+
+	// 🚩 Feature flags from the file (follow reloads) and the flag service, if any
+	flags := featureflag.New(cfg.FeatureFlags)
+	reloader.OnReload(func(c *config.Config) error {
+		return flags.Apply(c.FeatureFlags)
+	})
+	if remote := cfg.FeatureFlags.Remote; remote.URL != "" {
+		flags.Poll(appCtx, featureflag.NewHTTPProvider(remote), remote.PollInterval)
+	}
+
+	// 🔢 List limits (pagination); follow reloads
+	limits := paging.New(cfg.Pagination)
+	reloader.OnReload(func(c *config.Config) error {
+		return limits.Apply(c.Pagination)
+	})
 
 	// 🧩 Setup server
-	server := &http.Server{
-		Addr:    cfg.HttpServer.Addr,
-		Handler: route,
+	deps := routes.Deps{Storage: storage, Blobs: blobs, Notifier: notifier, Jobs: queue, Scheduler: sched, Webhooks: hooks, Exports: exporter, Snapshots: snapshots, Tokens: tokens, OIDC: idp, Challenge: challenge.New(cfg.Challenge), Quotas: quotas, Portal: portal.New(cfg.Portal), Sharer: share.New(cfg.Sharing, cfg.HttpServer.BaseURL), Verifier: verification.New(cfg.Verification, cfg.HttpServer.BaseURL, notifier), Sync: syncer, Health: monitor, Metrics: storeMetrics, TaskMetrics: taskMetrics, Maintenance: maintenance.New(cfg.Maintenance), Flags: flags, Paging: limits, AccessLog: accessOut}
+	server := newServer(cfg.HttpServer, routes.New(cfg, deps))
+	server.Addr = cfg.HttpServer.Addr
+
+	// 🔒 Admin API, dashboard, metrics and probes on their own port (nil unless http_server.admin_address)
+	var adminServer *http.Server
+	if cfg.HttpServer.AdminAddr != "" {
+		public, admin := routes.Split(cfg, deps)
+		server.Handler = public
+		adminServer = newServer(cfg.HttpServer, admin)
 	}
 
 	slog.Info("Server started", slog.String("address", cfg.HttpServer.Addr))
 
-	slog.Info("💾 Database initialized",
-		slog.String("driver", cfg.DBType),
-	)
+	dbInfo := []any{slog.String("driver", cfg.DBType), slog.String("blob_driver", cfg.Blob.Driver)}
+	if schema, ok := storagepkg.As[storagepkg.SchemaStore](storage); ok {
+		if version, err := schema.SchemaVersion(); err == nil {
+			dbInfo = append(dbInfo, slog.Int("schema_version", version))
+		}
+	}
+	slog.Info("💾 Database initialized", dbInfo...)
+
+	// 🗂️ Warn when an index the student queries rely on is missing
+	if indexes, ok := storagepkg.As[storagepkg.IndexStore](storage); ok {
+		report, err := indexes.CheckIndexes()
+		if err != nil {
+			slog.Warn("🗂️ Could not check indexes", slog.String("error", err.Error()))
+		}
+		for _, check := range report.Indexes {
+			if check.Missing {
+				slog.Warn("🗂️ Missing index, queries will scan the table",
+					slog.String("table", check.Table),
+					slog.Any("columns", check.Columns),
+					slog.String("for", check.For),
+				)
+			}
+		}
+	}
 
 	// Channel for graceful shutdown
 	// 🧩 Graceful shutdown
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	// 🔁 Sockets survive upgrades: SIGUSR2 starts the binary again on them
+	upgrader, err := upgrade.New(cfg.HttpServer)
+	if err != nil {
+		log.Fatalf("❌ Failed to prepare upgrades: %v", err)
+	}
+	defer upgrader.Stop()
+
+	listener, err := upgrader.Listen(cfg.HttpServer.Addr)
+	if err != nil {
+		log.Fatalf("❌ Failed to start server: %v", err)
+	}
+
 	// Start server in background
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := upgrader.Serve(server, listener); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("❌ Failed to start server: %v", err)
 		}
 	}()
 
-	<-done // Block until shutdown signal
+	if adminServer != nil {
+		adminListener, err := upgrader.Listen(cfg.HttpServer.AdminAddr)
+		if err != nil {
+			log.Fatalf("❌ Failed to start admin server: %v", err)
+		}
+		go func() {
+			if err := upgrader.Serve(adminServer, adminListener); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("❌ Failed to start admin server: %v", err)
+			}
+		}()
+		slog.Info("🔒 Admin server started", slog.String("address", cfg.HttpServer.AdminAddr))
+	}
+
+	// 🔬 pprof/expvar on their own port, kept off the public listener
+	var debugServer *http.Server
+	if cfg.Debug.Enabled && cfg.Debug.Addr != "" {
+		debugListener, err := upgrader.Listen(cfg.Debug.Addr)
+		if err != nil {
+			log.Fatalf("❌ Failed to start debug server: %v", err)
+		}
+		debugServer = newServer(cfg.HttpServer, debug.Handler())
+		// profiles and traces run for as long as ?seconds= asks
+		debugServer.WriteTimeout = 0
+		go func() {
+			if err := upgrader.Serve(debugServer, debugListener); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("❌ Failed to start debug server: %v", err)
+			}
+		}()
+		slog.Info("🔬 Debug server started", slog.String("address", cfg.Debug.Addr))
+	}
+
+	// 🔁 Serving: let the process we replace (if any) go, then watch for upgrades
+	if err := upgrader.Ready(); err != nil {
+		log.Fatalf("❌ Failed to finish startup: %v", err)
+	}
+	upgrader.Watch(appCtx)
+
+	// Block until shutdown signal, or until a new process took over
+	select {
+	case <-done:
+	case <-upgrader.Exit():
+	}
 
 	slog.Info("📴 Shutting down server...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// 🔁 Stop accepting first, so no connection is caught between accept and read
+	upgrader.Drain(ctx)
+
 	if err := server.Shutdown(ctx); err != nil {
 		slog.Error("❌ Failed to gracefully shutdown server", slog.String("error", err.Error()))
 	} else {
 		slog.Info("✅ Server shutdown successfully")
 	}
+
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			slog.Error("❌ Failed to gracefully shutdown admin server", slog.String("error", err.Error()))
+		}
+	}
+
+	if debugServer != nil {
+		debugServer.Close()
+	}
+
+	// ⏰ Stop timers and let running tasks finish
+	if err := sched.Stop(ctx); err != nil {
+		slog.Error("❌ Failed to stop scheduler", slog.String("error", err.Error()))
+	}
+
+	// 📤 Hand the events in flight to the queue; the rest go out on next start
+	if err := relay.Stop(ctx); err != nil {
+		slog.Error("❌ Failed to stop outbox relay", slog.String("error", err.Error()))
+	}
+
+	// ⚙️ Let running jobs finish; unfinished ones are requeued on next start
+	if err := queue.Stop(ctx); err != nil {
+		slog.Error("❌ Failed to stop job workers", slog.String("error", err.Error()))
+	}
+
+	// 📧 Let queued emails go out before exiting
+	if err := notifier.Close(ctx); err != nil {
+		slog.Error("❌ Failed to flush email queue", slog.String("error", err.Error()))
+	}
+
+	if err := monitor.Stop(ctx); err != nil {
+		slog.Error("❌ Failed to stop storage health monitor", slog.String("error", err.Error()))
+	}
+}
+
+// -------------------------------------------------------------
+// newServer() → http.Server with the http_server timeouts and limits
+// -------------------------------------------------------------
+func newServer(cfg config.HttpServer, handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
 }
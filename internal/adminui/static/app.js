@@ -0,0 +1,176 @@
+// Admin dashboard: plain JS on top of the public /api endpoints.
+// The API key and tenant live in sessionStorage and go out as headers.
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+let students = [];
+let nextCursor = "";
+
+// ---------------------------------------------------------
+// api() → fetch with credentials; throws the API's error message
+// ---------------------------------------------------------
+async function api(method, path, body) {
+  const headers = { "Accept": "application/json" };
+  const key = sessionStorage.getItem("apiKey");
+  const tenant = sessionStorage.getItem("tenant");
+  if (key) headers["X-API-Key"] = key;
+  if (tenant) headers["X-Tenant"] = tenant;
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+
+  const res = await fetch(path, { method, headers, body: body === undefined ? undefined : JSON.stringify(body) });
+  const data = await res.json().catch(() => ({}));
+  if (!res.ok) {
+    throw new Error(data.error || `${method} ${path} failed with ${res.status}`);
+  }
+  return data;
+}
+
+function showError(el, err) {
+  el.textContent = err ? err.message : "";
+  el.hidden = !err;
+}
+
+// 📊 Stats need the admin scope; without it the section just says so
+async function loadStats() {
+  const body = $("statsBody");
+  try {
+    const stats = await api("GET", "/api/admin/stats");
+    const cards = [["Students", stats.total_students]].concat(
+      (stats.by_age || []).map((b) => [`Age ${b.label}`, b.count]),
+    );
+    body.className = "cards";
+    body.replaceChildren(...cards.map(([label, value]) => {
+      const card = document.createElement("div");
+      card.className = "card";
+      const b = document.createElement("b");
+      b.textContent = value;
+      card.append(b, label);
+      return card;
+    }));
+  } catch (err) {
+    body.className = "muted";
+    body.textContent = `Stats unavailable: ${err.message}`;
+  }
+}
+
+// 🧑‍🎓 Students are paged by cursor (server default page size); search filters what is loaded
+async function loadStudents(reset) {
+  if (reset) {
+    students = [];
+    nextCursor = "";
+  }
+  try {
+    const page = await api("GET", `/api/students?cursor=${encodeURIComponent(nextCursor)}`);
+    students = students.concat(page.data || []);
+    nextCursor = page.next_cursor || "";
+    showError($("error"), null);
+  } catch (err) {
+    showError($("error"), err);
+  }
+  $("more").hidden = !nextCursor;
+  render();
+}
+
+function render() {
+  const q = $("search").value.trim().toLowerCase();
+  const visible = students.filter((s) => !q || s.name.toLowerCase().includes(q) || s.email.toLowerCase().includes(q));
+
+  $("rows").replaceChildren(...visible.map((s) => {
+    const tr = document.createElement("tr");
+    for (const value of [s.id, s.name, s.email, s.age]) {
+      const td = document.createElement("td");
+      td.textContent = value;
+      tr.append(td);
+    }
+    const actions = document.createElement("td");
+    const edit = document.createElement("button");
+    edit.type = "button";
+    edit.className = "secondary";
+    edit.textContent = "Edit";
+    edit.onclick = () => openEditor(s);
+    const del = document.createElement("button");
+    del.type = "button";
+    del.className = "danger";
+    del.textContent = "Delete";
+    del.onclick = () => remove(s);
+    actions.append(edit, " ", del);
+    tr.append(actions);
+    return tr;
+  }));
+}
+
+// the address has no form fields; an edit sends the stored one back unchanged
+let editingAddress = null;
+
+function openEditor(student) {
+  const form = $("studentForm");
+  form.reset();
+  showError($("formError"), null);
+  $("editorTitle").textContent = student ? `Edit student #${student.id}` : "New student";
+  form.elements.id.value = student ? student.id : "";
+  if (student) {
+    form.elements.name.value = student.name;
+    form.elements.email.value = student.email;
+    form.elements.date_of_birth.value = student.date_of_birth || "";
+    form.elements.phone.value = student.phone || "";
+    form.elements.gender.value = student.gender || "";
+  }
+  editingAddress = student ? student.address || null : null;
+  $("editor").showModal();
+}
+
+async function save(event) {
+  event.preventDefault();
+  const form = event.target;
+  const id = form.elements.id.value;
+  const body = {
+    name: form.elements.name.value,
+    email: form.elements.email.value,
+    date_of_birth: form.elements.date_of_birth.value,
+    phone: form.elements.phone.value,
+    gender: form.elements.gender.value,
+  };
+  if (editingAddress) {
+    body.address = editingAddress;
+  }
+  try {
+    if (id) {
+      await api("PUT", `/api/student/${id}`, body);
+    } else {
+      await api("POST", "/api/student", body);
+    }
+    $("editor").close();
+    await Promise.all([loadStudents(true), loadStats()]);
+  } catch (err) {
+    showError($("formError"), err);
+  }
+}
+
+async function remove(student) {
+  if (!confirm(`Move ${student.name} to the trash?`)) return;
+  try {
+    await api("DELETE", `/api/student/${student.id}`);
+    await Promise.all([loadStudents(true), loadStats()]);
+  } catch (err) {
+    showError($("error"), err);
+  }
+}
+
+function connect(event) {
+  if (event) event.preventDefault();
+  sessionStorage.setItem("apiKey", $("apiKey").value.trim());
+  sessionStorage.setItem("tenant", $("tenant").value.trim());
+  loadStudents(true);
+  loadStats();
+}
+
+$("apiKey").value = sessionStorage.getItem("apiKey") || "";
+$("tenant").value = sessionStorage.getItem("tenant") || "";
+$("settings").addEventListener("submit", connect);
+$("search").addEventListener("input", render);
+$("more").addEventListener("click", () => loadStudents(false));
+$("newStudent").addEventListener("click", () => openEditor(null));
+$("cancel").addEventListener("click", () => $("editor").close());
+$("studentForm").addEventListener("submit", save);
+connect();
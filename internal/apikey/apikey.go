@@ -0,0 +1,79 @@
+// Package apikey generates, hashes and checks the long-lived keys machine
+// clients send in the X-API-Key header instead of a user session.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Header carries the key on every request
+const Header = "X-API-Key"
+
+// Keys look like sk_<40 hex chars>; the prefix shown in listings is
+// "sk_" plus the first 8 of them
+const (
+	keyPrefix   = "sk_"
+	shownLength = len(keyPrefix) + 8
+)
+
+var (
+	ErrRevoked = errors.New("API key has been revoked")
+	ErrExpired = errors.New("API key has expired")
+)
+
+// -------------------------------------------------------------
+// Generate() → New plaintext key plus what gets stored: prefix and hash
+// -------------------------------------------------------------
+func Generate() (key, prefix, hash string) {
+	b := make([]byte, 20)
+	rand.Read(b)
+	key = keyPrefix + hex.EncodeToString(b)
+	return key, key[:shownLength], Hash(key)
+}
+
+// Hash is the stored form of a key. Keys are random, so a plain SHA-256
+// is enough; a slow hash would only slow every request down.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// -------------------------------------------------------------
+// Check() → ErrRevoked / ErrExpired when key can no longer be used
+// -------------------------------------------------------------
+func Check(key types.APIKey, now time.Time) error {
+	if key.RevokedAt != nil {
+		return ErrRevoked
+	}
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// RequiredScope is the scope a request needs: admin for /api/admin,
+// /debug and /metrics, otherwise read for safe methods and write for the rest.
+func RequiredScope(r *http.Request) string {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/admin/"), strings.HasPrefix(r.URL.Path, "/debug/"), r.URL.Path == "/metrics":
+		return types.ScopeAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return types.ScopeRead
+	default:
+		return types.ScopeWrite
+	}
+}
+
+// Allows reports whether scopes grant scope; admin grants everything
+func Allows(scopes []string, scope string) bool {
+	return slices.Contains(scopes, scope) || slices.Contains(scopes, types.ScopeAdmin)
+}
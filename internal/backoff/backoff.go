@@ -0,0 +1,30 @@
+// Package backoff computes retry delays shared by the notifier, the job
+// queue and anything else that retries.
+package backoff
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// -------------------------------------------------------------
+// Exponential() → base·2^(attempt-1), ±20% jitter, capped at limit
+// -------------------------------------------------------------
+// attempt starts at 1. Jitter keeps many clients from retrying in lockstep.
+func Exponential(base time.Duration, attempt int, limit time.Duration) time.Duration {
+	if base <= 0 {
+		base = time.Second
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > limit {
+		delay = limit
+	}
+	jitter := time.Duration(rand.Int64N(int64(delay)/5 + 1))
+	if rand.IntN(2) == 0 {
+		return delay - jitter
+	}
+	return delay + jitter
+}
@@ -0,0 +1,259 @@
+// Package bench fires concurrent CRUD traffic at the API (over HTTP) or
+// straight at a storage backend and reports latency percentiles.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Target is whatever the benchmark drives — a running server or a storage backend.
+type Target interface {
+	Create(student types.Student) (int64, error)
+	Get(id int64) error
+	List() error
+	Update(id int64, student types.Student) error
+}
+
+type Options struct {
+	Concurrency int
+	Duration    time.Duration
+	// Mix is the relative weight of each operation (create/get/list/update)
+	Mix map[string]int
+}
+
+// DefaultMix favours reads, like real dashboard traffic.
+var DefaultMix = map[string]int{"create": 20, "get": 50, "list": 10, "update": 20}
+
+// OpStats summarises one operation type.
+type OpStats struct {
+	Name   string
+	Count  int
+	Errors int
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+}
+
+func (o OpStats) ErrorRate() float64 {
+	if o.Count == 0 {
+		return 0
+	}
+	return float64(o.Errors) / float64(o.Count)
+}
+
+type Report struct {
+	Elapsed time.Duration
+	Ops     []OpStats
+}
+
+// Total returns request and error counts across all operations.
+func (r Report) Total() (count, errors int) {
+	for _, op := range r.Ops {
+		count += op.Count
+		errors += op.Errors
+	}
+	return count, errors
+}
+
+type sample struct {
+	op      string
+	latency time.Duration
+	err     bool
+}
+
+// -------------------------------------------------------------
+// Run() → Spawn workers until Duration elapses, then aggregate samples
+// -------------------------------------------------------------
+func Run(target Target, opts Options) (Report, error) {
+	if opts.Concurrency < 1 {
+		return Report{}, fmt.Errorf("concurrency must be >= 1, got %d", opts.Concurrency)
+	}
+	if opts.Duration <= 0 {
+		return Report{}, fmt.Errorf("duration must be > 0, got %s", opts.Duration)
+	}
+	mix := opts.Mix
+	if len(mix) == 0 {
+		mix = DefaultMix
+	}
+	pick, err := picker(mix)
+	if err != nil {
+		return Report{}, err
+	}
+
+	// Unique per run so repeated runs never collide on UNIQUE(email)
+	runId := time.Now().UnixNano()
+	var seq atomic.Int64
+	newStudent := func() types.Student {
+		n := seq.Add(1)
+		return types.Student{
+			Name:        fmt.Sprintf("Bench %d", n),
+			Email:       fmt.Sprintf("bench-%d-%d@example.com", runId, n),
+			DateOfBirth: time.Now().UTC().AddDate(-(18 + int(n%40)), 0, -1).Format(types.DateLayout),
+		}
+	}
+
+	// Seed one record per worker so reads/updates have something to hit
+	var idsMu sync.Mutex
+	var ids []int64
+	for i := 0; i < opts.Concurrency; i++ {
+		id, err := target.Create(newStudent())
+		if err != nil {
+			return Report{}, fmt.Errorf("seed failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	randomId := func(rng *rand.Rand) int64 {
+		idsMu.Lock()
+		defer idsMu.Unlock()
+		return ids[rng.Intn(len(ids))]
+	}
+
+	results := make([][]sample, opts.Concurrency)
+	deadline := time.Now().Add(opts.Duration)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(runId + int64(w)))
+
+			for time.Now().Before(deadline) {
+				op := pick(rng)
+				began := time.Now()
+
+				var err error
+				switch op {
+				case "create":
+					var id int64
+					if id, err = target.Create(newStudent()); err == nil {
+						idsMu.Lock()
+						ids = append(ids, id)
+						idsMu.Unlock()
+					}
+				case "get":
+					err = target.Get(randomId(rng))
+				case "list":
+					err = target.List()
+				case "update":
+					err = target.Update(randomId(rng), newStudent())
+				}
+
+				results[w] = append(results[w], sample{op: op, latency: time.Since(began), err: err != nil})
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	return aggregate(results, time.Since(start)), nil
+}
+
+// picker turns weights into a weighted random choice function.
+func picker(mix map[string]int) (func(*rand.Rand) string, error) {
+	var names []string
+	total := 0
+	for name, weight := range mix {
+		switch name {
+		case "create", "get", "list", "update":
+		default:
+			return nil, fmt.Errorf("unknown operation in mix: %s", name)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("negative weight for %s", name)
+		}
+		if weight > 0 {
+			names = append(names, name)
+			total += weight
+		}
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("operation mix has no positive weights")
+	}
+	sort.Strings(names) // deterministic for a given seed
+
+	return func(rng *rand.Rand) string {
+		n := rng.Intn(total)
+		for _, name := range names {
+			if n < mix[name] {
+				return name
+			}
+			n -= mix[name]
+		}
+		return names[len(names)-1]
+	}, nil
+}
+
+func aggregate(results [][]sample, elapsed time.Duration) Report {
+	byOp := map[string][]sample{}
+	for _, worker := range results {
+		for _, s := range worker {
+			byOp[s.op] = append(byOp[s.op], s)
+		}
+	}
+
+	report := Report{Elapsed: elapsed}
+	for op, samples := range byOp {
+		latencies := make([]time.Duration, len(samples))
+		stats := OpStats{Name: op, Count: len(samples)}
+		for i, s := range samples {
+			latencies[i] = s.latency
+			if s.err {
+				stats.Errors++
+			}
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		stats.P50 = percentile(latencies, 0.50)
+		stats.P95 = percentile(latencies, 0.95)
+		stats.P99 = percentile(latencies, 0.99)
+		stats.Max = latencies[len(latencies)-1]
+		report.Ops = append(report.Ops, stats)
+	}
+	sort.Slice(report.Ops, func(i, j int) bool { return report.Ops[i].Name < report.Ops[j].Name })
+
+	return report
+}
+
+// percentile expects sorted input (nearest-rank method).
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// -------------------------------------------------------------
+// Print() → Human-readable table
+// -------------------------------------------------------------
+func (r Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "%-8s %8s %8s %10s %10s %10s %10s\n", "op", "count", "errors", "p50", "p95", "p99", "max")
+	for _, op := range r.Ops {
+		fmt.Fprintf(w, "%-8s %8d %7.2f%% %10s %10s %10s %10s\n",
+			op.Name, op.Count, op.ErrorRate()*100,
+			op.P50.Round(time.Microsecond), op.P95.Round(time.Microsecond),
+			op.P99.Round(time.Microsecond), op.Max.Round(time.Microsecond),
+		)
+	}
+
+	count, errors := r.Total()
+	rps := float64(count) / r.Elapsed.Seconds()
+	fmt.Fprintf(w, "\ntotal: %d requests, %d errors in %s (%.1f req/s)\n",
+		count, errors, r.Elapsed.Round(time.Millisecond), rps)
+}
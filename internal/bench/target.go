@@ -0,0 +1,134 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// HTTPTarget → Drives a running instance through its public API
+// -------------------------------------------------------------
+type HTTPTarget struct {
+	BaseURL string
+	Client  *http.Client
+	// APIKey is sent as X-API-Key, for servers with auth enabled
+	APIKey string
+	// Token is sent as a bearer token instead of an API key
+	Token string
+}
+
+func NewHTTPTarget(baseURL string, timeout time.Duration) *HTTPTarget {
+	return &HTTPTarget{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Client: &http.Client{
+			Timeout: timeout,
+			// Default keeps only 2 idle conns per host which skews latency under load
+			Transport: &http.Transport{MaxIdleConnsPerHost: 256},
+		},
+	}
+}
+
+func (h *HTTPTarget) do(method, path string, body any, wantStatus int) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, h.BaseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.APIKey != "" {
+		req.Header.Set(apikey.Header, h.APIKey)
+	}
+	if h.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.Token)
+	}
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != wantStatus {
+		return nil, fmt.Errorf("%s %s: status %d", method, path, res.StatusCode)
+	}
+	return data, nil
+}
+
+func (h *HTTPTarget) Create(student types.Student) (int64, error) {
+	data, err := h.do(http.MethodPost, "/api/student", student, http.StatusCreated)
+	if err != nil {
+		return 0, err
+	}
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(data, &created); err != nil {
+		return 0, fmt.Errorf("decode create response: %w", err)
+	}
+	return created.ID, nil
+}
+
+func (h *HTTPTarget) Get(id int64) error {
+	_, err := h.do(http.MethodGet, fmt.Sprintf("/api/student/%d", id), nil, http.StatusOK)
+	return err
+}
+
+// List fetches the first page: past pagination.max_limit students the
+// unpaged list answers 400, and the run would time the rejection
+func (h *HTTPTarget) List() error {
+	_, err := h.do(http.MethodGet, "/api/students?limit=100", nil, http.StatusOK)
+	return err
+}
+
+func (h *HTTPTarget) Update(id int64, student types.Student) error {
+	_, err := h.do(http.MethodPut, fmt.Sprintf("/api/student/%d", id), student, http.StatusOK)
+	return err
+}
+
+// -------------------------------------------------------------
+// StorageTarget → Calls a backend directly, bypassing HTTP entirely
+// -------------------------------------------------------------
+type StorageTarget struct {
+	Storage storage.Storage
+}
+
+func (s StorageTarget) Create(student types.Student) (int64, error) {
+	return s.Storage.CreateStudent(student)
+}
+
+func (s StorageTarget) Get(id int64) error {
+	_, err := s.Storage.GetStudentById(id)
+	return err
+}
+
+func (s StorageTarget) List() error {
+	_, err := s.Storage.GetStudents()
+	return err
+}
+
+func (s StorageTarget) Update(id int64, student types.Student) error {
+	_, err := s.Storage.UpdateStudentById(id, student)
+	return err
+}
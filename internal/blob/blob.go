@@ -0,0 +1,71 @@
+// Package blob stores uploaded files (student photos) behind one small
+// interface so the filesystem, S3 and in-memory backends are interchangeable.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Object describes a stored blob.
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+	// ETag is quoted, ready for the ETag response header
+	ETag string
+}
+
+type Store interface {
+	// Put stores size bytes from r under key, replacing any previous blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound when key does not exist; the caller closes the reader
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Presigner is implemented by stores that can hand out direct, time-limited
+// download links (S3/MinIO). Other stores are served through the API.
+type Presigner interface {
+	PresignGet(key string, expires time.Duration) (string, error)
+}
+
+// PhotoKey is where a student's photo lives in the blob store.
+// Student IDs are unique across tenants, so the key needs no tenant prefix.
+func PhotoKey(id int64) string {
+	return fmt.Sprintf("students/%d/photo", id)
+}
+
+// -------------------------------------------------------------
+// New() → Pick the backend configured in blob.driver
+// -------------------------------------------------------------
+func New(cfg config.Blob) (Store, error) {
+	switch cfg.Driver {
+	case "filesystem":
+		return NewFilesystem(cfg.Dir)
+	case "s3":
+		return NewS3(cfg.S3)
+	case "memory":
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unsupported blob driver: %s (supported: filesystem, s3, memory)", cfg.Driver)
+	}
+}
+
+// checkKey rejects keys that could escape the store root ("../", "/etc")
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
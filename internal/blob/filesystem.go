@@ -0,0 +1,110 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Filesystem stores each blob as a file under Root, mirroring the key's path.
+// It keeps no metadata: the content type is sniffed when the file is read.
+type Filesystem struct {
+	Root string
+}
+
+func NewFilesystem(root string) (*Filesystem, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+	return &Filesystem{Root: root}, nil
+}
+
+func (f *Filesystem) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(f.Root, filepath.FromSlash(key)), nil
+}
+
+// -------------------------------------------------------------
+// Put() → Write to a temp file, then rename so readers never see half a blob
+// -------------------------------------------------------------
+func (f *Filesystem) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	dst, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("create blob dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, io.LimitReader(r, size)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("store blob: %w", err)
+	}
+	return nil
+}
+
+func (f *Filesystem) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	src, err := f.path(key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	file, err := os.Open(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Object{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, Object{}, fmt.Errorf("open blob: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, Object{}, fmt.Errorf("stat blob: %w", err)
+	}
+
+	// 🔍 Sniff the content type, then rewind
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, Object{}, fmt.Errorf("rewind blob: %w", err)
+	}
+
+	return file, Object{
+		Key:         key,
+		ContentType: http.DetectContentType(head[:n]),
+		Size:        info.Size(),
+		ModTime:     info.ModTime().UTC(),
+		ETag:        fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+	}, nil
+}
+
+func (f *Filesystem) Delete(ctx context.Context, key string) error {
+	dst, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Memory keeps blobs in a map. Meant for tests — data is lost on restart.
+type Memory struct {
+	mu    sync.RWMutex
+	blobs map[string]memoryBlob
+}
+
+type memoryBlob struct {
+	data []byte
+	obj  Object
+}
+
+func NewMemory() *Memory {
+	return &Memory{blobs: make(map[string]memoryBlob)}
+}
+
+func (m *Memory) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return fmt.Errorf("read blob: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = memoryBlob{
+		data: data,
+		obj: Object{
+			Key:         key,
+			ContentType: contentType,
+			Size:        int64(len(data)),
+			ModTime:     time.Now().UTC(),
+			ETag:        fmt.Sprintf(`"%x"`, sha256.Sum256(data)),
+		},
+	}
+	return nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	b, ok := m.blobs[key]
+	if !ok {
+		return nil, Object{}, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b.data)), b.obj, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blobs, key)
+	return nil
+}
@@ -0,0 +1,166 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/awsauth"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+// S3 talks to AWS S3 or any S3-compatible server with SigV4-signed
+// requests (no SDK). Bodies are sent as UNSIGNED-PAYLOAD so uploads stream.
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	pathStyle bool
+	creds     awsauth.Credentials
+	client    *http.Client
+}
+
+func NewS3(cfg config.S3) (*S3, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", endpoint)
+	}
+
+	creds := awsauth.Credentials{AccessKeyID: cfg.AccessKeyID, SecretAccessKey: cfg.SecretAccessKey}
+	if creds.AccessKeyID == "" {
+		if creds, err = awsauth.CredentialsFromEnv(); err != nil {
+			return nil, fmt.Errorf("s3 credentials: %w", err)
+		}
+	}
+
+	return &S3{
+		endpoint:  u,
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		pathStyle: cfg.PathStyle,
+		creds:     creds,
+		// PUT bodies are streamed, so only reads and deletes get retried
+		client: httpclient.New(httpclient.Options{Timeout: 60 * time.Second, Retries: 2}),
+	}, nil
+}
+
+// -------------------------------------------------------------
+// objectURL() → Path-style (<endpoint>/<bucket>/<key>) or virtual-hosted
+// -------------------------------------------------------------
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	p := "/" + key
+	if s.pathStyle {
+		p = "/" + s.bucket + p
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = p
+	u.RawPath = awsauth.EscapePath(p)
+	return &u
+}
+
+func (s *S3) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	awsauth.Sign(req, awsauth.UnsignedPayload, s.creds, s.region, "s3", time.Now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", method, key, err)
+	}
+	return res, nil
+}
+
+// s3Error turns a non-2xx response into an error carrying S3's XML message
+func s3Error(res *http.Response, method, key string) error {
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s: %s", method, key, res.Status, strings.TrimSpace(string(msg)))
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	res, err := s.do(ctx, http.MethodPut, key, io.LimitReader(r, size), size, header)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return s3Error(res, http.MethodPut, key)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	res, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, Object{}, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, Object{}, s3Error(res, http.MethodGet, key)
+	}
+
+	obj := Object{
+		Key:         key,
+		ContentType: res.Header.Get("Content-Type"),
+		ETag:        res.Header.Get("ETag"),
+	}
+	obj.Size, _ = strconv.ParseInt(res.Header.Get("Content-Length"), 10, 64)
+	obj.ModTime, _ = http.ParseTime(res.Header.Get("Last-Modified"))
+
+	return res.Body, obj, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	res, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// S3 answers 204 whether or not the key existed
+	if res.StatusCode/100 != 2 {
+		return s3Error(res, http.MethodDelete, key)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// PresignGet() → Direct download link, valid for `expires`
+// -------------------------------------------------------------
+func (s *S3) PresignGet(key string, expires time.Duration) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return awsauth.Presign(http.MethodGet, s.objectURL(key), s.creds, s.region, "s3", time.Now(), expires), nil
+}
@@ -0,0 +1,56 @@
+// Package buildinfo reports what binary is running. Release builds stamp
+// it through ldflags:
+//
+//	go build -ldflags "-X github.com/manish-npx/go-student-api/internal/buildinfo.Version=v1.4.0 \
+//	  -X github.com/manish-npx/go-student-api/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/manish-npx/go-student-api/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+//	  ./cmd/student-api
+//
+// Unstamped builds fall back to the VCS details the go tool embeds: the
+// commit, and its time as the build time.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Set with -ldflags "-X ..."; see the package doc
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+var (
+	once sync.Once
+	info types.Version
+)
+
+// -------------------------------------------------------------
+// Get() → Version, commit and build time of this binary
+// -------------------------------------------------------------
+// Storage and SchemaVersion are left for the caller, which knows the backend.
+func Get() types.Version {
+	once.Do(func() {
+		info = types.Version{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+		build, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			case setting.Key == "vcs.modified" && setting.Value == "true":
+				info.Dirty = true
+			}
+		}
+	})
+	return info
+}
@@ -0,0 +1,162 @@
+// Package challenge tells people from bots on routes anyone can call: a
+// CAPTCHA widget token checked with its provider (Cloudflare Turnstile or
+// hCaptcha) and a honeypot field only bots fill in.
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+var (
+	ErrMissing = errors.New("missing challenge token")
+	ErrFailed  = errors.New("challenge failed, try again")
+)
+
+// siteverify endpoints of the supported providers
+var verifyURLs = map[string]string{
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+}
+
+// Provider checks a widget token with the CAPTCHA service.
+// Verify returns ErrFailed for tokens the service rejects; other errors
+// mean the service couldn't be asked.
+type Provider interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// Guard holds what one deployment checks.
+// A nil *Guard (no provider and no honeypot) challenges nothing.
+type Guard struct {
+	provider Provider
+	header   string
+	honeypot string
+	routes   []string
+}
+
+// -------------------------------------------------------------
+// New() → Guard, or nil when there is nothing to check
+// -------------------------------------------------------------
+func New(cfg config.Challenge) *Guard {
+	var provider Provider
+	if verifyURL, ok := verifyURLs[cfg.Provider]; ok {
+		if cfg.VerifyURL != "" {
+			verifyURL = cfg.VerifyURL
+		}
+		provider = NewSiteVerify(verifyURL, cfg.Secret)
+	}
+	if provider == nil && cfg.Honeypot == "" {
+		return nil
+	}
+	return &Guard{provider: provider, header: cfg.Header, honeypot: cfg.Honeypot, routes: cfg.Routes}
+}
+
+// Guards reports whether the route pattern is challenged
+func (g *Guard) Guards(pattern string) bool {
+	return g != nil && slices.Contains(g.routes, pattern)
+}
+
+// Routes are the challenged route patterns
+func (g *Guard) Routes() []string {
+	if g == nil {
+		return nil
+	}
+	return g.routes
+}
+
+// Honeypot is the field to leave empty; "" when there is none
+func (g *Guard) Honeypot() string {
+	return g.honeypot
+}
+
+// -------------------------------------------------------------
+// Check() → Verify r's widget token; nil without a provider
+// -------------------------------------------------------------
+func (g *Guard) Check(r *http.Request) error {
+	if g.provider == nil {
+		return nil
+	}
+	token := strings.TrimSpace(r.Header.Get(g.header))
+	if token == "" {
+		return fmt.Errorf("%w (set the %s header)", ErrMissing, g.header)
+	}
+	return g.provider.Verify(r.Context(), token, remoteIP(r))
+}
+
+// SiteVerify is the siteverify API Turnstile and hCaptcha share: the
+// secret and token are posted as a form, the answer says success or not.
+type SiteVerify struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// -------------------------------------------------------------
+// NewSiteVerify() → Provider asking the siteverify endpoint at url
+// -------------------------------------------------------------
+// Tokens are single-use, so calls are never retried.
+func NewSiteVerify(url, secret string) *SiteVerify {
+	return &SiteVerify{
+		url:    url,
+		secret: secret,
+		client: httpclient.New(httpclient.Options{Timeout: 10 * time.Second, UserAgent: "go-student-api-challenge"}),
+	}
+}
+
+func (s *SiteVerify) Verify(ctx context.Context, token, remoteIP string) error {
+	form := url.Values{"secret": {s.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("siteverify request failed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("siteverify answered %s", res.Status)
+	}
+
+	var answer struct {
+		Success bool     `json:"success"`
+		Errors  []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&answer); err != nil {
+		return fmt.Errorf("decode siteverify answer: %w", err)
+	}
+	if !answer.Success {
+		// a bad secret is our misconfiguration, not the caller's failure
+		if slices.Contains(answer.Errors, "invalid-input-secret") || slices.Contains(answer.Errors, "missing-input-secret") {
+			return fmt.Errorf("siteverify rejected the secret: %v", answer.Errors)
+		}
+		return ErrFailed
+	}
+	return nil
+}
+
+// remoteIP is the peer address of r without its port
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	return host
+}
@@ -0,0 +1,101 @@
+// Package chaos injects faults into storage calls, for resilience testing
+// in dev: slow and failing calls show how handlers map errors, how request
+// timeouts and retries behave and when the health monitor gives up.
+package chaos
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+)
+
+// ErrInjected is the error of every call failed on purpose. It counts as
+// transient (storage.ErrTransient), so injected write failures are retried
+// like real ones (see storage_retry).
+var ErrInjected error = injectedError{}
+
+type injectedError struct{}
+
+func (injectedError) Error() string {
+	return "injected storage fault"
+}
+
+func (injectedError) Is(target error) bool {
+	return target == storage.ErrTransient
+}
+
+// Injector slows down and fails storage calls as chaos.* says.
+// A nil *Injector (chaos disabled) injects nothing.
+type Injector struct {
+	mu  sync.RWMutex
+	cfg config.Chaos
+}
+
+// New returns nil unless chaos is enabled and env is dev, so a config that
+// slipped past validation still can't break another environment.
+func New(cfg config.Chaos, env string) *Injector {
+	if !cfg.Enabled || env != "dev" {
+		return nil
+	}
+	slog.Warn("💥 Chaos enabled, storage calls are slowed down and failed on purpose",
+		slog.Duration("latency", cfg.Latency),
+		slog.Duration("jitter", cfg.Jitter),
+		slog.Float64("error_rate", cfg.ErrorRate),
+		slog.Any("methods", cfg.Methods),
+	)
+	return &Injector{cfg: cfg}
+}
+
+// -------------------------------------------------------------
+// Wrap() → backend with faults injected (backend itself when disabled)
+// -------------------------------------------------------------
+// Wrap it inside the metrics decorator, so injected latency and errors show
+// up in the metrics as if the database caused them.
+func (i *Injector) Wrap(backend storage.Storage) storage.Storage {
+	if i == nil {
+		return backend
+	}
+	return storage.Decorate(backend, i.intercept)
+}
+
+// -------------------------------------------------------------
+// Apply() → Take new latency, error rate and methods (config reloads)
+// -------------------------------------------------------------
+// chaos.enabled itself needs a restart.
+func (i *Injector) Apply(cfg config.Chaos) error {
+	if i == nil {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cfg = cfg
+	return nil
+}
+
+func (i *Injector) intercept(method string, _ []any, call func() error) error {
+	i.mu.RLock()
+	cfg := i.cfg
+	i.mu.RUnlock()
+	if len(cfg.Methods) > 0 && !slices.Contains(cfg.Methods, method) {
+		return call()
+	}
+
+	delay := cfg.Latency
+	if cfg.Jitter > 0 {
+		delay += time.Duration(rand.Int64N(int64(cfg.Jitter) + 1))
+	}
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if cfg.ErrorRate > 0 && rand.Float64() < cfg.ErrorRate {
+		slog.Warn("💥 Injected storage fault", slog.String("method", method))
+		return fmt.Errorf("%s: %w", method, ErrInjected)
+	}
+	return call()
+}
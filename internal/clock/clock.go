@@ -0,0 +1,57 @@
+// Package clock is where the service reads and renders the time.
+// Timestamps are kept in UTC at the whole-second precision every storage
+// backend stores, so the API serves them as RFC3339 ("2026-01-02T15:04:05Z")
+// whatever the backend or the server's timezone. Only reports that group by
+// day look at the deployment's display timezone.
+package clock
+
+import (
+	"time"
+)
+
+// Layout is how timestamps are rendered outside JSON (which uses RFC3339 too)
+const Layout = time.RFC3339
+
+// DayLayout is how report days are rendered
+const DayLayout = "2006-01-02"
+
+// -------------------------------------------------------------
+// Now() → Current time in UTC, truncated to the second
+// -------------------------------------------------------------
+func Now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// -------------------------------------------------------------
+// Format() → t as RFC3339 in UTC
+// -------------------------------------------------------------
+func Format(t time.Time) string {
+	return t.UTC().Format(Layout)
+}
+
+// -------------------------------------------------------------
+// UseUTC() → Make UTC the process's local timezone
+// -------------------------------------------------------------
+// Called first thing in main, so database drivers, log lines and any
+// time.Now() that escapes this package agree with what is stored.
+func UseUTC() {
+	time.Local = time.UTC
+}
+
+// -------------------------------------------------------------
+// Zone() → Location of an IANA timezone name; "" is UTC
+// -------------------------------------------------------------
+func Zone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// -------------------------------------------------------------
+// Day() → Start of the day t falls on in loc
+// -------------------------------------------------------------
+func Day(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
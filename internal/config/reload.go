@@ -0,0 +1,159 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// ReloadFunc applies a freshly loaded config. Returning an error rejects
+// the reload and rolls back every handler that already applied it.
+type ReloadFunc func(cfg *Config) error
+
+// Reloader holds the live config and swaps it on SIGHUP.
+// Only the sections listed in reloadable change at runtime; the rest keep
+// their startup values until the next restart.
+type Reloader struct {
+	mu       sync.Mutex
+	current  atomic.Pointer[Config]
+	handlers []ReloadFunc
+}
+
+func NewReloader(cfg *Config) *Reloader {
+	r := &Reloader{}
+	r.current.Store(cfg)
+	return r
+}
+
+// Current returns the active config. Treat it as read-only.
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// OnReload registers a handler, called in registration order on each reload.
+func (r *Reloader) OnReload(fn ReloadFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, fn)
+}
+
+// -------------------------------------------------------------
+// Reload() → Re-read the file, apply tunables, roll back on any failure
+// -------------------------------------------------------------
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.current.Load()
+	next, err := Load(old.Path)
+	if err != nil {
+		return fmt.Errorf("reload aborted, can not read %s: %w", old.Path, err)
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("reload aborted, invalid config:\n%w", err)
+	}
+
+	for _, field := range keepStatic(old, next) {
+		slog.Warn("⚠️ Config change ignored until restart", slog.String("field", field))
+	}
+
+	for i, fn := range r.handlers {
+		if err := fn(next); err != nil {
+			// Re-apply the previous config to everything touched so far
+			for j := i; j >= 0; j-- {
+				if rbErr := r.handlers[j](old); rbErr != nil {
+					slog.Error("❌ Config rollback failed", slog.String("error", rbErr.Error()))
+				}
+			}
+			return fmt.Errorf("reload rejected: %w", err)
+		}
+	}
+
+	r.current.Store(next)
+	return nil
+}
+
+// reloadable are the sections a reload re-applies (see the OnReload
+// handlers in main); every other section keeps its startup value
+var reloadable = map[string]bool{
+	"logger":        true,
+	"validation":    true,
+	"chaos":         true,
+	"feature_flags": true,
+	"pagination":    true,
+}
+
+// keepStatic copies settings that need a restart from old into next and
+// reports which of them were changed in the file.
+func keepStatic(old, next *Config) []string {
+	var changed []string
+
+	oldValue, nextValue := reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		name, _, _ := strings.Cut(oldValue.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" || reloadable[name] {
+			continue
+		}
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+		nextValue.Field(i).Set(oldValue.Field(i))
+	}
+
+	// 🔀 Reloadable sections with parts that still need a restart
+	if old.Logger.File != next.Logger.File || old.Logger.Rotation != next.Logger.Rotation {
+		changed = append(changed, "logger.file")
+	}
+	if old.Logger.Access != next.Logger.Access {
+		changed = append(changed, "logger.access")
+	}
+	if !reflect.DeepEqual(old.Logger.Payloads, next.Logger.Payloads) {
+		changed = append(changed, "logger.payloads")
+	}
+	if old.Chaos.Enabled != next.Chaos.Enabled {
+		changed = append(changed, "chaos.enabled")
+	}
+	if old.FeatureFlags.Remote != next.FeatureFlags.Remote {
+		changed = append(changed, "feature_flags.remote")
+	}
+
+	next.Logger.Payloads = old.Logger.Payloads
+	next.Logger.File = old.Logger.File
+	next.Logger.Rotation = old.Logger.Rotation
+	next.Logger.Access = old.Logger.Access
+	next.Chaos.Enabled = old.Chaos.Enabled
+	next.FeatureFlags.Remote = old.FeatureFlags.Remote
+
+	return changed
+}
+
+// -------------------------------------------------------------
+// Watch() → Reload on every SIGHUP until ctx is cancelled
+// -------------------------------------------------------------
+func (r *Reloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				if err := r.Reload(); err != nil {
+					slog.Error("❌ Config reload failed, keeping previous settings", slog.String("error", err.Error()))
+					continue
+				}
+				slog.Info("🔄 Config reloaded", slog.String("path", r.Current().Path))
+			}
+		}
+	}()
+}
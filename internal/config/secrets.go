@@ -0,0 +1,155 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretProvider resolves references such as "vault://secret/data/api#pg_password".
+// Register custom providers with RegisterSecretProvider before MustLoad runs.
+type SecretProvider interface {
+	// Scheme is the prefix before "://" this provider handles
+	Scheme() string
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]SecretProvider{}
+)
+
+func RegisterSecretProvider(p SecretProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[p.Scheme()] = p
+}
+
+func init() {
+	RegisterSecretProvider(fileProvider{})
+	RegisterSecretProvider(envProvider{})
+	RegisterSecretProvider(&vaultProvider{})
+	RegisterSecretProvider(&awsSecretsProvider{})
+}
+
+// secretField is a config value that may come from a file or a provider.
+type secretField struct {
+	name  string // yaml path, for error messages
+	env   string // base env var; <env>_FILE is checked too
+	value *string
+}
+
+// secretFields lists every secret setting; add new ones here.
+func (c *Config) secretFields() []secretField {
+	fields := []secretField{
+		{name: "postgres.password", env: "PG_PASSWORD", value: &c.Postgres.Password},
+		{name: "blob.s3.secret_access_key", env: "S3_SECRET_ACCESS_KEY", value: &c.Blob.S3.SecretAccessKey},
+		{name: "notify.smtp.password", env: "SMTP_PASSWORD", value: &c.Notify.SMTP.Password},
+		{name: "auth.bootstrap_key", env: "AUTH_BOOTSTRAP_KEY", value: &c.Auth.BootstrapKey},
+		{name: "auth.token_secret", env: "AUTH_TOKEN_SECRET", value: &c.Auth.TokenSecret},
+		{name: "challenge.secret", env: "CHALLENGE_SECRET", value: &c.Challenge.Secret},
+		{name: "sharing.secret", env: "SHARE_SECRET", value: &c.Sharing.Secret},
+		{name: "enumeration.hashid_salt", env: "HASHID_SALT", value: &c.Enumeration.HashIDSalt},
+		{name: "encryption.keys", env: "ENCRYPTION_KEYS", value: &c.Encryption.Keys},
+		{name: "encryption.index_key", env: "ENCRYPTION_INDEX_KEY", value: &c.Encryption.IndexKey},
+		{name: "feature_flags.remote.token", env: "FEATURE_FLAGS_TOKEN", value: &c.FeatureFlags.Remote.Token},
+	}
+	// SYNC_<NAME>_TOKEN(_FILE), e.g. SYNC_DISTRICT_SIS_TOKEN_FILE
+	for i, conn := range c.Sync.Connectors {
+		fields = append(fields, secretField{
+			name:  fmt.Sprintf("sync.connectors[%d].token", i),
+			env:   "SYNC_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(conn.Name)) + "_TOKEN",
+			value: &c.Sync.Connectors[i].Token,
+		})
+	}
+	return fields
+}
+
+// -------------------------------------------------------------
+// resolveSecrets() → Apply <ENV>_FILE overrides, then provider references
+// -------------------------------------------------------------
+func (c *Config) resolveSecrets() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, field := range c.secretFields() {
+		// Docker/K8s secrets: PG_PASSWORD_FILE=/run/secrets/pg_password
+		if path := os.Getenv(field.env + "_FILE"); path != "" {
+			secret, err := readSecretFile(path)
+			if err != nil {
+				return fmt.Errorf("%s: %s_FILE: %w", field.name, field.env, err)
+			}
+			*field.value = secret
+			continue
+		}
+
+		secret, err := resolveRef(ctx, *field.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+		*field.value = secret
+	}
+	return nil
+}
+
+// resolveRef returns value untouched unless it starts with a registered scheme.
+func resolveRef(ctx context.Context, value string) (string, error) {
+	scheme, ref, ok := strings.Cut(value, "://")
+	if !ok {
+		return value, nil
+	}
+
+	providersMu.RLock()
+	provider, found := providers[scheme]
+	providersMu.RUnlock()
+	if !found {
+		return value, nil
+	}
+
+	secret, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s secret: %w", scheme, err)
+	}
+	return secret, nil
+}
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	// Secret files almost always end with a newline
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// splitKey separates "path#key" into its parts (key may be empty).
+func splitKey(ref string) (string, string) {
+	path, key, _ := strings.Cut(ref, "#")
+	return path, key
+}
+
+// -------------------------------------------------------------
+// Built-in providers: file:// and env://
+// -------------------------------------------------------------
+type fileProvider struct{}
+
+func (fileProvider) Scheme() string { return "file" }
+
+func (fileProvider) Resolve(_ context.Context, ref string) (string, error) {
+	return readSecretFile(ref)
+}
+
+type envProvider struct{}
+
+func (envProvider) Scheme() string { return "env" }
+
+func (envProvider) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/awsauth"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+var secretsHTTPClient = httpclient.New(httpclient.Options{Timeout: 10 * time.Second, Retries: 2})
+
+// -------------------------------------------------------------
+// vault://<kv path>#<key> → HashiCorp Vault KV (v1 or v2)
+// -------------------------------------------------------------
+// Uses the standard VAULT_ADDR, VAULT_TOKEN and optional VAULT_NAMESPACE.
+//
+//	password: "vault://secret/data/student-api#pg_password"
+type vaultProvider struct{}
+
+func (*vaultProvider) Scheme() string { return "vault" }
+
+func (*vaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	addr := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+
+	path, key := splitKey(ref)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	body, err := doSecretRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	var payload struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data
+	data := payload.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		data = inner
+	}
+	return pickKey(data, key)
+}
+
+// -------------------------------------------------------------
+// awssm://<secret id>#<json key> → AWS Secrets Manager
+// -------------------------------------------------------------
+// Uses AWS_REGION (or AWS_DEFAULT_REGION) and the AWS_* credential variables.
+// AWS_ENDPOINT_URL_SECRETS_MANAGER overrides the endpoint (e.g. LocalStack).
+type awsSecretsProvider struct{}
+
+func (*awsSecretsProvider) Scheme() string { return "awssm" }
+
+func (*awsSecretsProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return "", fmt.Errorf("AWS_REGION must be set")
+	}
+	creds, err := awsauth.CredentialsFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	secretId, key := splitKey(ref)
+	payload, _ := json.Marshal(map[string]string{"SecretId": secretId})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awsauth.Sign(req, awsauth.HashPayload(payload), creds, region, "secretsmanager", time.Now())
+
+	body, err := doSecretRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("decode secrets manager response: %w", err)
+	}
+	if key == "" {
+		return out.SecretString, nil
+	}
+
+	// Secrets Manager commonly stores a JSON object of key/value pairs
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(out.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object, can not pick key %q", secretId, key)
+	}
+	return pickKey(fields, key)
+}
+
+func doSecretRequest(req *http.Request) ([]byte, error) {
+	res, err := secretsHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: status %d", req.Method, req.URL.Host, res.StatusCode)
+	}
+	return body, nil
+}
+
+// pickKey returns data[key]; with no key, the only value in data.
+func pickKey(data map[string]any, key string) (string, error) {
+	if key == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("secret has %d fields, add #<key> to the reference", len(data))
+		}
+		for _, v := range data {
+			key = fmt.Sprint(v)
+		}
+		return key, nil
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret", key)
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,691 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/cron"
+	"github.com/manish-npx/go-student-api/internal/http/pattern"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "******"
+
+// -------------------------------------------------------------
+// ScheduledTasks() → scheduler.tasks plus the legacy trash.purge_interval
+// -------------------------------------------------------------
+// trash.purge_interval keeps working: it becomes a "trash-purge" task unless
+// scheduler.tasks already schedules trash.purge itself.
+func (c *Config) ScheduledTasks() []ScheduledTask {
+	tasks := c.Scheduler.Tasks
+	if c.Trash.PurgeInterval <= 0 {
+		return tasks
+	}
+	for _, task := range tasks {
+		if task.Task == "trash.purge" {
+			return tasks
+		}
+	}
+	return append(slices.Clone(tasks), ScheduledTask{
+		Name:     "trash-purge",
+		Task:     "trash.purge",
+		Schedule: "@every " + c.Trash.PurgeInterval.String(),
+	})
+}
+
+// -------------------------------------------------------------
+// Validate() → Cross-check fields and report every problem at once
+// -------------------------------------------------------------
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env == "" {
+		add("env is required (yaml: env, env: ENV)")
+	}
+	if c.HttpServer.Addr == "" {
+		add("http_server.address is required (env: HTTP_ADDRESS)")
+	} else if scheme, _, ok := strings.Cut(c.HttpServer.Addr, "://"); ok && scheme != "unix" && scheme != "systemd" {
+		add("http_server.address must be host:port, unix://<path> or systemd://[name], got %q", c.HttpServer.Addr)
+	}
+	if admin := c.HttpServer.AdminAddr; admin != "" {
+		if scheme, _, ok := strings.Cut(admin, "://"); ok && scheme != "unix" && scheme != "systemd" {
+			add("http_server.admin_address must be host:port, unix://<path> or systemd://[name], got %q", admin)
+		}
+		if admin == c.HttpServer.Addr || admin == c.Debug.Addr {
+			add("http_server.admin_address %q is already used by another listener", admin)
+		}
+	}
+	if c.HttpServer.MaxBodyBytes < 1 {
+		add("http_server.max_body_bytes must be positive, got %d", c.HttpServer.MaxBodyBytes)
+	}
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read_header_timeout", c.HttpServer.ReadHeaderTimeout},
+		{"read_timeout", c.HttpServer.ReadTimeout},
+		{"write_timeout", c.HttpServer.WriteTimeout},
+		{"idle_timeout", c.HttpServer.IdleTimeout},
+	} {
+		if timeout.value < 0 {
+			add("http_server.%s must not be negative (0 means none), got %s", timeout.name, timeout.value)
+		}
+	}
+	if c.HttpServer.MaxHeaderBytes < 4096 {
+		add("http_server.max_header_bytes must be at least 4096, got %d", c.HttpServer.MaxHeaderBytes)
+	}
+	if mode := c.HttpServer.SocketMode; mode != "" {
+		if _, err := strconv.ParseUint(mode, 8, 32); err != nil {
+			add("http_server.socket_mode must be octal permissions such as 0660, got %q", mode)
+		}
+	}
+	if base := c.HttpServer.BaseURL; base != "" {
+		if u, err := url.Parse(base); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("http_server.base_url must be an absolute http(s) URL, got %q", base)
+		}
+	}
+
+	switch c.DBType {
+	case "sqlite":
+		if c.StoragePath == "" {
+			add("storage_path is required when db_type is sqlite (env: STORAGE_PATH)")
+		} else if err := checkWritable(c.StoragePath); err != nil {
+			add("storage_path %s is not usable: %v", c.StoragePath, err)
+		}
+		errs = append(errs, c.Sqlite.validate()...)
+	case "postgres":
+		errs = append(errs, c.Postgres.validate()...)
+	case "memory":
+	default:
+		add("db_type %q is not supported (use sqlite, postgres or memory)", c.DBType)
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Logger.Level)); err != nil {
+		add("logger.level %q is invalid (use debug, info, warn or error)", c.Logger.Level)
+	}
+	if _, err := time.LoadLocation(c.Time.DisplayTimezone); err != nil {
+		add("time.display_timezone %q is not an IANA timezone (e.g. Europe/Berlin): %v", c.Time.DisplayTimezone, err)
+	}
+
+	if c.Trash.Retention <= 0 {
+		add("trash.retention must be positive, got %s", c.Trash.Retention)
+	}
+	if c.Trash.PurgeInterval < 0 {
+		add("trash.purge_interval must not be negative, got %s", c.Trash.PurgeInterval)
+	}
+
+	switch c.Blob.Driver {
+	case "filesystem":
+		if c.Blob.Dir == "" {
+			add("blob.dir is required when blob.driver is filesystem (env: BLOB_DIR)")
+		}
+	case "s3":
+		if c.Blob.S3.Bucket == "" {
+			add("blob.s3.bucket is required when blob.driver is s3 (env: S3_BUCKET)")
+		}
+		if (c.Blob.S3.AccessKeyID == "") != (c.Blob.S3.SecretAccessKey == "") {
+			add("blob.s3.access_key_id and blob.s3.secret_access_key must be set together")
+		}
+	case "memory":
+	default:
+		add("blob.driver %q is not supported (use filesystem, s3 or memory)", c.Blob.Driver)
+	}
+	if c.Blob.PresignExpiry < time.Second || c.Blob.PresignExpiry > 7*24*time.Hour {
+		add("blob.presign_expiry must be between 1s and 168h, got %s", c.Blob.PresignExpiry)
+	}
+	if c.Photos.MaxBytes <= 0 {
+		add("photos.max_bytes must be positive, got %d", c.Photos.MaxBytes)
+	}
+	if c.Documents.MaxBytes <= 0 {
+		add("documents.max_bytes must be positive, got %d", c.Documents.MaxBytes)
+	}
+
+	switch c.Challenge.Provider {
+	case "", "none":
+	case "turnstile", "hcaptcha":
+		if c.Challenge.Secret == "" {
+			add("challenge.secret is required for challenge.provider %s (env: CHALLENGE_SECRET)", c.Challenge.Provider)
+		}
+	default:
+		add("challenge.provider must be none, turnstile or hcaptcha, got %q", c.Challenge.Provider)
+	}
+	if c.Challenge.Provider != "none" && c.Challenge.Provider != "" && c.Challenge.Header == "" {
+		add("challenge.header must not be empty")
+	}
+
+	if c.Enumeration.UniformNotFound && (c.Enumeration.NotFoundDelay < 0 || c.Enumeration.NotFoundDelay > 5*time.Second) {
+		add("enumeration.not_found_delay must be between 0 and 5s, got %s", c.Enumeration.NotFoundDelay)
+	}
+	if c.Enumeration.HashIDSalt != "" {
+		if len(c.Enumeration.HashIDSalt) < 16 {
+			add("enumeration.hashid_salt must be at least 16 characters (env: HASHID_SALT)")
+		}
+		if c.Enumeration.HashIDMinLength < 0 || c.Enumeration.HashIDMinLength > 20 {
+			add("enumeration.hashid_min_length must be between 0 and 20, got %d", c.Enumeration.HashIDMinLength)
+		}
+	}
+
+	if c.Notify.Provider != "none" && c.Notify.Provider != "" {
+		if _, err := mail.ParseAddress(c.Notify.From); err != nil {
+			add("notify.from %q is not a valid address (env: NOTIFY_FROM)", c.Notify.From)
+		}
+		if c.Notify.Workers < 1 || c.Notify.QueueSize < 1 || c.Notify.MaxAttempts < 1 {
+			add("notify.workers, queue_size and max_attempts must be at least 1")
+		}
+	}
+	if c.Notify.Provider == "smtp" {
+		if c.Notify.SMTP.Host == "" {
+			add("notify.smtp.host is required when notify.provider is smtp (env: SMTP_HOST)")
+		}
+		if c.Notify.SMTP.Port < 1 || c.Notify.SMTP.Port > 65535 {
+			add("notify.smtp.port %d is out of range (1-65535)", c.Notify.SMTP.Port)
+		}
+	}
+
+	if c.Verification.Enabled {
+		if c.HttpServer.BaseURL == "" {
+			add("verification needs http_server.base_url for the links it emails (env: HTTP_BASE_URL)")
+		}
+		if c.Notify.Provider == "none" || c.Notify.Provider == "" {
+			add("verification needs a notify.provider to send its links")
+		}
+		if c.Verification.TTL <= 0 {
+			add("verification.ttl must be positive, got %s", c.Verification.TTL)
+		}
+	}
+
+	if c.Portal.Enabled {
+		if c.Portal.SessionTTL <= 0 || c.Portal.Lockout <= 0 {
+			add("portal.session_ttl and portal.lockout must be positive")
+		}
+		if c.Portal.MinPasswordLength < 8 {
+			add("portal.min_password_length must be at least 8, got %d", c.Portal.MinPasswordLength)
+		}
+		if c.Portal.MaxFailedLogins < 1 {
+			add("portal.max_failed_logins must be at least 1, got %d", c.Portal.MaxFailedLogins)
+		}
+	}
+
+	if c.Sharing.Secret != "" {
+		if len(c.Sharing.Secret) < 32 {
+			add("sharing.secret must be at least 32 characters (env: SHARE_SECRET)")
+		}
+		if c.HttpServer.BaseURL == "" {
+			add("sharing needs http_server.base_url for the links it signs (env: HTTP_BASE_URL)")
+		}
+		if c.Sharing.DefaultTTL <= 0 || c.Sharing.MaxTTL < c.Sharing.DefaultTTL {
+			add("sharing.default_ttl must be positive and at most sharing.max_ttl")
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, task := range c.ScheduledTasks() {
+		switch {
+		case task.Name == "" || task.Task == "":
+			add("scheduler.tasks[%d] needs a name and a task", i)
+		case seen[task.Name]:
+			add("scheduler.tasks: duplicate name %q", task.Name)
+		}
+		seen[task.Name] = true
+		if _, err := cron.Parse(task.Schedule); err != nil {
+			add("scheduler.tasks %q: %v", task.Name, err)
+		}
+		if task.Jitter < 0 {
+			add("scheduler.tasks %q: jitter must not be negative", task.Name)
+		}
+	}
+
+	keys := map[string]bool{}
+	for i, field := range c.CustomFields {
+		switch {
+		case !types.ValidCustomFieldKey(field.Key):
+			add("custom_fields[%d]: key %q must be lower snake_case, at most 63 characters", i, field.Key)
+		case keys[field.Key]:
+			add("custom_fields: duplicate key %q", field.Key)
+		}
+		keys[field.Key] = true
+		switch field.Type {
+		case types.CustomString, types.CustomNumber, types.CustomBoolean, types.CustomDate:
+		default:
+			add("custom_fields %q: type %q is not supported (use string, number, boolean or date)", field.Key, field.Type)
+		}
+	}
+
+	connectors := map[string]bool{}
+	for i, conn := range c.Sync.Connectors {
+		switch {
+		case conn.Name == "" || conn.Type == "" || conn.URL == "":
+			add("sync.connectors[%d] needs a name, a type and a url", i)
+		case connectors[conn.Name]:
+			add("sync.connectors: duplicate name %q", conn.Name)
+		}
+		connectors[conn.Name] = true
+		if conn.Timeout < 0 {
+			add("sync.connectors %q: timeout must not be negative", conn.Name)
+		}
+	}
+
+	if c.Compression.Enabled {
+		if c.Compression.Level < -1 || c.Compression.Level > 9 {
+			add("compression.level must be between -1 and 9, got %d", c.Compression.Level)
+		}
+		if c.Compression.MinSize < 0 {
+			add("compression.min_size must not be negative, got %d", c.Compression.MinSize)
+		}
+	}
+
+	switch c.JSONNaming.Default {
+	case "snake_case", "camelCase":
+	default:
+		add("json_naming.default %q is not supported (use snake_case or camelCase)", c.JSONNaming.Default)
+	}
+
+	cacheRoutes := make([]string, 0, len(c.Caching.Rules))
+	for i, rule := range c.Caching.Rules {
+		if rule.CacheControl == "" {
+			add("caching.rules[%d].cache_control is required", i)
+		}
+		cacheRoutes = append(cacheRoutes, rule.Route)
+	}
+	if _, err := pattern.NewSet(cacheRoutes...); err != nil {
+		add("caching.rules: %v", err)
+	}
+
+	if c.Maintenance.RetryAfter <= 0 {
+		add("maintenance.retry_after must be positive, got %s", c.Maintenance.RetryAfter)
+	}
+	for name, flag := range c.FeatureFlags.Flags {
+		if flag.Rollout < 0 || flag.Rollout > 100 {
+			add("feature_flags.flags.%s.rollout must be between 0 and 100, got %d", name, flag.Rollout)
+		}
+	}
+	if c.FeatureFlags.Remote.URL != "" && c.FeatureFlags.Remote.PollInterval <= 0 {
+		add("feature_flags.remote.poll_interval must be positive, got %s", c.FeatureFlags.Remote.PollInterval)
+	}
+	if c.Collapse.Enabled {
+		if _, err := pattern.NewSet(c.Collapse.Routes...); err != nil {
+			add("collapse.routes: %v", err)
+		}
+	}
+
+	if c.Metrics.SlowQueryThreshold < 0 {
+		add("metrics.slow_query_threshold must not be negative, got %s", c.Metrics.SlowQueryThreshold)
+	}
+
+	if ch := c.Chaos; ch.Enabled {
+		if c.Env != "dev" {
+			add("chaos.enabled is only allowed when env is dev, got env %q", c.Env)
+		}
+		if ch.Latency < 0 || ch.Jitter < 0 {
+			add("chaos.latency and chaos.jitter must not be negative")
+		}
+		if ch.ErrorRate < 0 || ch.ErrorRate > 1 {
+			add("chaos.error_rate must be between 0 and 1, got %v", ch.ErrorRate)
+		}
+	}
+
+	if r := c.StorageRetry; r.Enabled {
+		if r.MaxAttempts < 1 {
+			add("storage_retry.max_attempts must be at least 1, got %d", r.MaxAttempts)
+		}
+		if r.Backoff <= 0 || r.MaxBackoff < r.Backoff {
+			add("storage_retry needs 0 < backoff <= max_backoff, got %s and %s", r.Backoff, r.MaxBackoff)
+		}
+	}
+
+	if c.Encryption.Enabled {
+		if _, err := c.Encryption.KeyRing(); err != nil {
+			errs = append(errs, err)
+		}
+		if _, err := c.Encryption.IndexSecret(); err != nil {
+			errs = append(errs, err)
+		}
+		if c.DBType == "memory" {
+			add("encryption needs a persistent db_type (sqlite or postgres)")
+		}
+	}
+
+	if p := c.Pagination; p.DefaultLimit < 1 || p.MaxLimit < p.DefaultLimit {
+		add("pagination needs 1 <= default_limit <= max_limit, got %d and %d", p.DefaultLimit, p.MaxLimit)
+	}
+
+	if c.Reports.CacheTTL < 0 {
+		add("reports.cache_ttl must not be negative, got %s", c.Reports.CacheTTL)
+	}
+
+	if v := c.Validation; v.MinAge < 0 || v.MaxAge < 1 || v.MinAge > v.MaxAge {
+		add("validation needs 0 <= min_age <= max_age and max_age >= 1, got %d and %d", v.MinAge, v.MaxAge)
+	}
+	for _, domain := range c.Validation.EmailDomains {
+		if domain == "" || strings.ContainsAny(domain, "@ ") {
+			add("validation.email_domains entries must be bare domains like school.edu, got %q", domain)
+		}
+	}
+	for _, domain := range c.Validation.BlockedEmailDomains {
+		if domain == "" || strings.ContainsAny(domain, "@ ") {
+			add("validation.blocked_email_domains entries must be bare domains like example.com, got %q", domain)
+		}
+	}
+	if c.Validation.CheckEmailMX && c.Validation.MXTimeout <= 0 {
+		add("validation.mx_timeout must be positive with check_email_mx, got %s", c.Validation.MXTimeout)
+	}
+
+	if r := c.Logger.Rotation; r.MaxSizeMB < 0 || r.MaxAge < 0 || r.MaxBackups < 0 {
+		add("logger.rotation values must not be negative (0 disables each limit)")
+	}
+	if c.Logger.File != "" {
+		if err := checkWritable(c.Logger.File); err != nil {
+			add("logger.file %s is not usable: %v", c.Logger.File, err)
+		}
+	}
+	if a := c.Logger.Access; a.Enabled {
+		if !slices.Contains([]string{"json", "common", "combined"}, a.Format) {
+			add("logger.access.format %q is invalid (use json, common or combined)", a.Format)
+		}
+		if a.File != "" && a.File == c.Logger.File {
+			add("logger.access.file must differ from logger.file")
+		} else if a.File != "" {
+			if err := checkWritable(a.File); err != nil {
+				add("logger.access.file %s is not usable: %v", a.File, err)
+			}
+		}
+	}
+	if p := c.Logger.Payloads; p.Enabled || p.Header != "" {
+		if p.SampleRate <= 0 || p.SampleRate > 1 {
+			add("logger.payloads.sample_rate must be in (0, 1], got %v", p.SampleRate)
+		}
+		if p.MaxBytes <= 0 {
+			add("logger.payloads.max_bytes must be positive, got %d", p.MaxBytes)
+		}
+	}
+
+	// a short bootstrap key is guessable, and it grants everything
+	if c.Auth.Enabled && c.Auth.BootstrapKey != "" && len(c.Auth.BootstrapKey) < 32 {
+		add("auth.bootstrap_key must be at least 32 characters (env: AUTH_BOOTSTRAP_KEY)")
+	}
+	if c.Auth.TokenSecret != "" {
+		if len(c.Auth.TokenSecret) < 32 {
+			add("auth.token_secret must be at least 32 characters (env: AUTH_TOKEN_SECRET)")
+		}
+		if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
+			add("auth.access_token_ttl must be positive and shorter than auth.refresh_token_ttl")
+		}
+	}
+
+	if imp := c.Auth.Impersonation; imp.TTL <= 0 || imp.MaxTTL < imp.TTL || imp.MaxTTL > 24*time.Hour {
+		add("auth.impersonation.ttl must be positive and at most auth.impersonation.max_ttl, itself at most 24h")
+	}
+
+	if c.Auth.Cache.Size < 0 || c.Auth.Cache.TTL < 0 {
+		add("auth.cache.size and auth.cache.ttl must not be negative (0 disables the cache)")
+	}
+	errs = append(errs, c.Auth.OIDC.validate()...)
+	if c.Quotas.Enabled && !c.Auth.Enabled {
+		add("quotas.enabled needs auth.enabled: quotas are tracked per API key or user")
+	}
+	if c.Debug.Enabled && c.Debug.Addr == "" && !c.Auth.Enabled {
+		add("debug.enabled without debug.addr needs auth.enabled: profiles would be public on the API port")
+	}
+	if c.Quotas.DailyRequests < 0 || c.Quotas.DailyWrites < 0 {
+		add("quotas.daily_requests and quotas.daily_writes must not be negative (0 means unlimited)")
+	}
+
+	if c.Webhooks.Timeout <= 0 {
+		add("webhooks.timeout must be positive, got %s", c.Webhooks.Timeout)
+	}
+
+	if c.Outbox.PollInterval <= 0 || c.Outbox.Lease <= 0 || c.Outbox.BatchSize < 1 {
+		add("outbox.poll_interval and outbox.lease must be positive and outbox.batch_size at least 1")
+	}
+
+	if c.Health.Interval <= 0 || c.Health.Timeout <= 0 || c.Health.MaxBackoff <= 0 || c.Health.FailureThreshold < 1 {
+		add("health.interval, health.timeout and health.max_backoff must be positive and health.failure_threshold at least 1")
+	}
+
+	if c.Jobs.Enabled {
+		if c.Jobs.Workers < 1 || c.Jobs.MaxAttempts < 1 {
+			add("jobs.workers and jobs.max_attempts must be at least 1")
+		}
+		if c.Jobs.PollInterval <= 0 || c.Jobs.Backoff <= 0 || c.Jobs.Timeout <= 0 {
+			add("jobs.poll_interval, jobs.backoff and jobs.timeout must be positive")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Only checked when an issuer is set
+func (o OIDC) validate() []error {
+	if o.Issuer == "" {
+		return nil
+	}
+	var errs []error
+	if u, err := url.Parse(o.Issuer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("auth.oidc.issuer must be an absolute http(s) URL, got %q", o.Issuer))
+	}
+	if o.ClientID == "" {
+		errs = append(errs, errors.New("auth.oidc.client_id is required when auth.oidc.issuer is set (env: OIDC_CLIENT_ID)"))
+	}
+	if o.RolesClaim == "" {
+		errs = append(errs, errors.New("auth.oidc.roles_claim must not be empty"))
+	}
+	check := func(where, scope string) {
+		switch strings.TrimSpace(scope) {
+		case "read", "write", "admin":
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown scope %q (use read, write or admin)", where, scope))
+		}
+	}
+	for role, scopes := range o.Roles {
+		for _, scope := range strings.Split(scopes, ",") {
+			check(fmt.Sprintf("auth.oidc.roles[%s]", role), scope)
+		}
+	}
+	for _, scope := range o.DefaultScopes {
+		check("auth.oidc.default_scopes", scope)
+	}
+	return errs
+}
+
+// Only checked when db_type is sqlite
+func (s Sqlite) validate() []error {
+	var errs []error
+	switch s.JournalMode {
+	case "wal", "delete", "truncate", "persist", "memory", "off":
+	default:
+		errs = append(errs, fmt.Errorf("sqlite.journal_mode %q is invalid (use wal, delete, truncate, persist, memory or off)", s.JournalMode))
+	}
+	switch s.Synchronous {
+	case "off", "normal", "full":
+	default:
+		errs = append(errs, fmt.Errorf("sqlite.synchronous %q is invalid (use off, normal or full)", s.Synchronous))
+	}
+	if s.BusyTimeout < 0 {
+		errs = append(errs, fmt.Errorf("sqlite.busy_timeout must not be negative, got %s", s.BusyTimeout))
+	}
+	if s.MaxReaders < 0 {
+		errs = append(errs, fmt.Errorf("sqlite.max_readers must not be negative, got %d", s.MaxReaders))
+	}
+	return errs
+}
+
+// Only checked when db_type is postgres, so sqlite setups may omit the section
+func (p Postgres) validate() []error {
+	var errs []error
+	required := []struct{ field, value string }{
+		{"postgres.host (env: PG_HOST)", p.Host},
+		{"postgres.user (env: PG_USER)", p.User},
+		{"postgres.dbname (env: PG_DBNAME)", p.DBName},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			errs = append(errs, fmt.Errorf("%s is required when db_type is postgres", r.field))
+		}
+	}
+
+	if p.Port < 1 || p.Port > 65535 {
+		errs = append(errs, fmt.Errorf("postgres.port %d is out of range (1-65535)", p.Port))
+	}
+
+	switch p.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, fmt.Errorf("postgres.sslmode %q is invalid", p.SSLMode))
+	}
+
+	for i, dsn := range p.Replicas {
+		u, err := url.Parse(dsn)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("postgres.replicas[%d] must be a postgres:// URL", i))
+		}
+	}
+	if len(p.Replicas) > 0 && p.ReplicaCooldown <= 0 {
+		errs = append(errs, fmt.Errorf("postgres.replica_cooldown must be positive, got %s", p.ReplicaCooldown))
+	}
+
+	return errs
+}
+
+// checkWritable makes sure SQLite will be able to create/open the file.
+func checkWritable(path string) error {
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory %s does not exist", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return fmt.Errorf("file is not writable: %w", err)
+		}
+		return f.Close()
+	}
+
+	f, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable", dir)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// -------------------------------------------------------------
+// Redacted() → Copy with secrets masked, safe to print or log
+// -------------------------------------------------------------
+func (c Config) Redacted() Config {
+	if c.Postgres.Password != "" {
+		c.Postgres.Password = redacted
+	}
+	c.Postgres.Replicas = slices.Clone(c.Postgres.Replicas)
+	for i, dsn := range c.Postgres.Replicas {
+		if u, err := url.Parse(dsn); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), redacted)
+				c.Postgres.Replicas[i] = u.String()
+			}
+		}
+	}
+	if c.Blob.S3.SecretAccessKey != "" {
+		c.Blob.S3.SecretAccessKey = redacted
+	}
+	if c.Notify.SMTP.Password != "" {
+		c.Notify.SMTP.Password = redacted
+	}
+	if c.Auth.BootstrapKey != "" {
+		c.Auth.BootstrapKey = redacted
+	}
+	if c.Auth.TokenSecret != "" {
+		c.Auth.TokenSecret = redacted
+	}
+	if c.Challenge.Secret != "" {
+		c.Challenge.Secret = redacted
+	}
+	if c.Enumeration.HashIDSalt != "" {
+		c.Enumeration.HashIDSalt = redacted
+	}
+	if c.Sharing.Secret != "" {
+		c.Sharing.Secret = redacted
+	}
+	if c.Encryption.Keys != "" {
+		c.Encryption.Keys = redacted
+	}
+	if c.Encryption.IndexKey != "" {
+		c.Encryption.IndexKey = redacted
+	}
+	c.Sync.Connectors = slices.Clone(c.Sync.Connectors)
+	for i := range c.Sync.Connectors {
+		if c.Sync.Connectors[i].Token != "" {
+			c.Sync.Connectors[i].Token = redacted
+		}
+	}
+	if c.FeatureFlags.Remote.Token != "" {
+		c.FeatureFlags.Remote.Token = redacted
+	}
+	return c
+}
+
+// Print writes the resolved config (secrets redacted) as YAML.
+func (c *Config) Print(w io.Writer) {
+	out, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		fmt.Fprintf(w, "can not render config: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "# resolved config from %s\n%s", c.Path, out)
+}
+
+// LogValue lets slog render the config as grouped, redacted attributes.
+func (c *Config) LogValue() slog.Value {
+	r := c.Redacted()
+	attrs := []slog.Attr{
+		slog.String("path", r.Path),
+		slog.String("env", r.Env),
+		slog.String("http_server.address", r.HttpServer.Addr),
+		slog.String("http_server.admin_address", r.HttpServer.AdminAddr),
+		slog.String("db_type", r.DBType),
+		slog.String("logger.level", strings.ToLower(r.Logger.Level)),
+	}
+	switch r.DBType {
+	case "sqlite":
+		attrs = append(attrs, slog.String("storage_path", r.StoragePath))
+	case "postgres":
+		attrs = append(attrs,
+			slog.String("postgres.host", r.Postgres.Host),
+			slog.Int("postgres.port", r.Postgres.Port),
+			slog.String("postgres.user", r.Postgres.User),
+			slog.String("postgres.password", r.Postgres.Password),
+			slog.String("postgres.dbname", r.Postgres.DBName),
+			slog.String("postgres.sslmode", r.Postgres.SSLMode),
+			slog.Int("postgres.replicas", len(r.Postgres.Replicas)),
+		)
+	}
+	attrs = append(attrs,
+		slog.String("blob.driver", r.Blob.Driver),
+		slog.String("notify.provider", r.Notify.Provider),
+		slog.Bool("auth.enabled", r.Auth.Enabled),
+		slog.Bool("quotas.enabled", r.Quotas.Enabled),
+		slog.Bool("encryption.enabled", r.Encryption.Enabled),
+		slog.Bool("logger.payloads.enabled", r.Logger.Payloads.Enabled),
+		slog.Bool("logger.access.enabled", r.Logger.Access.Enabled),
+	)
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,106 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{spec: "* * * * *"},
+		{spec: "  */15 9-17 * * 1-5  "},
+		{spec: "0,30 */2 1,15 1-12/3 7"},
+		{spec: "5/20 * * * *"},
+		{spec: "@daily"},
+		{spec: "@every 90m"},
+		{spec: "", wantErr: "want 5 fields"},
+		{spec: "* * * *", wantErr: "want 5 fields"},
+		{spec: "@fortnightly", wantErr: "want 5 fields"},
+		{spec: "@every 500ms", wantErr: "at least 1s"},
+		{spec: "@every soon", wantErr: "at least 1s"},
+		{spec: "60 * * * *", wantErr: "out of range 0-59"},
+		{spec: "* * 0 * *", wantErr: "out of range 1-31"},
+		{spec: "* * * 13 *", wantErr: "out of range 1-12"},
+		{spec: "* * * * 8", wantErr: "out of range 0-7"},
+		{spec: "5-1 * * * *", wantErr: "out of range"},
+		{spec: "*/0 * * * *", wantErr: "bad step"},
+		{spec: "1-x * * * *", wantErr: "bad range"},
+		{spec: "a * * * *", wantErr: "bad value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := Parse(tt.spec)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse(%q) = %v", tt.spec, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse(%q) error = %v, want it to contain %q", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want string // empty: never fires again
+	}{
+		{name: "every keeps the seconds", spec: "@every 90m", from: "2026-10-16 10:00:30", want: "2026-10-16 11:30:30"},
+		{name: "strictly after", spec: "* * * * *", from: "2026-10-16 10:00:00", want: "2026-10-16 10:01:00"},
+		{name: "minute step", spec: "*/15 * * * *", from: "2026-10-16 10:07:59", want: "2026-10-16 10:15:00"},
+		{name: "step from a value", spec: "5/20 * * * *", from: "2026-10-16 10:26:00", want: "2026-10-16 10:45:00"},
+		{name: "hour range with step", spec: "0 9-17/4 * * *", from: "2026-10-16 10:00:00", want: "2026-10-16 13:00:00"},
+		{name: "list", spec: "0 0 1,15 * *", from: "2026-10-02 00:00:00", want: "2026-10-15 00:00:00"},
+		{name: "day rollover", spec: "30 2 * * *", from: "2026-01-31 03:00:00", want: "2026-02-01 02:30:00"},
+		{name: "month rollover", spec: "@monthly", from: "2026-01-31 12:00:00", want: "2026-02-01 00:00:00"},
+		{name: "year rollover", spec: "@yearly", from: "2026-12-31 23:59:00", want: "2027-01-01 00:00:00"},
+		{name: "skips short months", spec: "0 0 31 * *", from: "2026-04-01 00:00:00", want: "2026-05-31 00:00:00"},
+		{name: "leap day", spec: "0 0 29 2 *", from: "2026-03-01 00:00:00", want: "2028-02-29 00:00:00"},
+		{name: "never", spec: "0 0 30 2 *", from: "2026-01-01 00:00:00"},
+		{name: "7 is sunday", spec: "0 0 * * 7", from: "2026-10-16 00:00:00", want: "2026-10-18 00:00:00"},
+		{name: "weekdays only", spec: "0 9 * * 1-5", from: "2026-10-16 10:00:00", want: "2026-10-19 09:00:00"},
+		{name: "day of month only", spec: "0 0 13 * *", from: "2026-10-16 00:00:00", want: "2026-11-13 00:00:00"},
+		// both restricted: either matching is enough
+		{name: "day of month or weekday", spec: "0 0 13 * 5", from: "2026-10-16 00:00:00", want: "2026-10-23 00:00:00"},
+		// a stepped * still counts as unrestricted: both must match
+		{name: "stepped weekday and day of month", spec: "0 0 13 * */2", from: "2026-10-16 00:00:00", want: "2026-12-13 00:00:00"},
+		{name: "stepped day of month and weekday", spec: "0 0 */10 * 1", from: "2026-10-16 00:00:00", want: "2026-12-21 00:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := s.Next(at(tt.from))
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Fatalf("Next(%s) = %s, want never", tt.from, got)
+				}
+				return
+			}
+			if want := at(tt.want); !got.Equal(want) {
+				t.Fatalf("%q: Next(%s) = %s, want %s", tt.spec, tt.from, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Package customfield checks students' extra attributes against their
+// definitions: the deployment's (custom_fields in config) plus the ones each
+// tenant adds through the admin API.
+package customfield
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Definition sources
+const (
+	SourceConfig = "config"
+	SourceAPI    = "api"
+)
+
+// FilterPrefix starts list query parameters that filter on a custom field,
+// e.g. ?custom.house=red
+const FilterPrefix = "custom."
+
+// Registry holds the config definitions; tenant ones are read per request.
+type Registry struct {
+	static []types.CustomField
+}
+
+// -------------------------------------------------------------
+// New() → Registry over the custom_fields config section
+// -------------------------------------------------------------
+func New(fields []config.CustomField) *Registry {
+	r := &Registry{}
+	for _, f := range fields {
+		r.static = append(r.static, types.CustomField{Key: f.Key, Type: f.Type, Required: f.Required, Source: SourceConfig})
+	}
+	return r
+}
+
+// Static reports whether key is defined in config (and so can't be changed
+// through the API)
+func (r *Registry) Static(key string) bool {
+	_, ok := find(r.static, key)
+	return ok
+}
+
+// -------------------------------------------------------------
+// Definitions() → Config definitions, then the tenant's own, by key
+// -------------------------------------------------------------
+// store must already be scoped to the tenant; backends without custom
+// field tables only get the config ones.
+func (r *Registry) Definitions(store storage.Storage) ([]types.CustomField, error) {
+	defs := slices.Clone(r.static)
+	fields, ok := storage.As[storage.CustomFieldStore](store)
+	if !ok {
+		return defs, nil
+	}
+	stored, err := fields.GetCustomFields()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range stored {
+		f.Source = SourceAPI
+		defs = append(defs, f)
+	}
+	return defs, nil
+}
+
+// -------------------------------------------------------------
+// Check() → Validate a student's custom values against defs
+// -------------------------------------------------------------
+// Returns the values to store: JSON nulls are dropped (an unset field),
+// everything else must be a defined key holding its type. All problems
+// are reported at once.
+func Check(defs []types.CustomField, values map[string]any) (map[string]any, error) {
+	var problems response.FieldErrors
+	clean := make(map[string]any, len(values))
+	for key, value := range values {
+		if value == nil {
+			continue
+		}
+		def, ok := find(defs, key)
+		if !ok {
+			problems = append(problems, fieldError(key, "is not defined"))
+			continue
+		}
+		if !valid(def.Type, value) {
+			problems = append(problems, fieldError(key, "must be a "+describe(def.Type)))
+			continue
+		}
+		clean[key] = value
+	}
+	for _, def := range defs {
+		if def.Required && values[def.Key] == nil {
+			problems = append(problems, fieldError(def.Key, "is required"))
+		}
+	}
+	if len(problems) > 0 {
+		slices.SortFunc(problems, func(a, b response.FieldError) int { return strings.Compare(a.Message, b.Message) })
+		return nil, problems
+	}
+	if len(clean) == 0 {
+		return nil, nil
+	}
+	return clean, nil
+}
+
+// fieldError is a problem with the custom value under key
+func fieldError(key, problem string) response.FieldError {
+	return response.FieldError{Field: FilterPrefix + key, Message: fmt.Sprintf("custom field %s %s", key, problem)}
+}
+
+// -------------------------------------------------------------
+// Filter() → custom.<key>=<value> query parameters as a storage filter
+// -------------------------------------------------------------
+// Values are parsed by the field's type, so ?custom.year=2024 matches the
+// number 2024. nil when the query has no custom filters.
+func Filter(defs []types.CustomField, query url.Values) (map[string]any, error) {
+	var filter map[string]any
+	var problems []string
+	for param, raw := range query {
+		key, ok := strings.CutPrefix(param, FilterPrefix)
+		if !ok {
+			continue
+		}
+		def, ok := find(defs, key)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: %s is not a defined custom field", param, key))
+			continue
+		}
+		value, err := parse(def.Type, raw[0])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s must be a %s (got %q)", param, describe(def.Type), raw[0]))
+			continue
+		}
+		if filter == nil {
+			filter = map[string]any{}
+		}
+		filter[key] = value
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return nil, errors.New(strings.Join(problems, ","))
+	}
+	return filter, nil
+}
+
+// HasFilter reports whether query holds any custom.<key> parameter
+func HasFilter(query url.Values) bool {
+	for param := range query {
+		if strings.HasPrefix(param, FilterPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func find(defs []types.CustomField, key string) (types.CustomField, bool) {
+	i := slices.IndexFunc(defs, func(d types.CustomField) bool { return d.Key == key })
+	if i < 0 {
+		return types.CustomField{}, false
+	}
+	return defs[i], true
+}
+
+// valid checks a JSON-decoded value against a field type
+func valid(typ string, value any) bool {
+	switch typ {
+	case types.CustomString:
+		_, ok := value.(string)
+		return ok
+	case types.CustomNumber:
+		_, ok := value.(float64)
+		return ok
+	case types.CustomBoolean:
+		_, ok := value.(bool)
+		return ok
+	case types.CustomDate:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(types.DateLayout, s)
+		return err == nil
+	}
+	return false
+}
+
+// parse reads a query parameter as a value of the field type
+func parse(typ, raw string) (any, error) {
+	switch typ {
+	case types.CustomNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, errors.New("not a finite number")
+		}
+		return n, nil
+	case types.CustomBoolean:
+		return strconv.ParseBool(raw)
+	case types.CustomDate:
+		if _, err := time.Parse(types.DateLayout, raw); err != nil {
+			return nil, err
+		}
+	}
+	return raw, nil
+}
+
+func describe(typ string) string {
+	if typ == types.CustomDate {
+		return "date (YYYY-MM-DD)"
+	}
+	return typ
+}
@@ -0,0 +1,53 @@
+// Package emaildomain moves a tenant's student emails from one domain to
+// another (a school renamed its domain), for the admin endpoint and the
+// email-domains command.
+package emaildomain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/validate"
+)
+
+// ErrUnsupported is returned for storage backends without EmailDomainStore
+var ErrUnsupported = errors.New("email domain migration not supported by this storage backend")
+
+// ErrInvalidDomain marks a from or to that isn't a usable domain
+var ErrInvalidDomain = errors.New("invalid email domain")
+
+// -------------------------------------------------------------
+// Migrate() → Move the students of store's tenant from one domain to another
+// -------------------------------------------------------------
+// from and to are bare domains (school.edu), compared case-insensitively;
+// to must pass the validation policy's domain rules
+// (validate.ErrEmailRejected otherwise). entry carries who asked; each
+// moved student gets it as a student.email_domain_migrated audit entry.
+// With dryRun nothing is written and the result shows what would be.
+func Migrate(store storage.Storage, from, to string, dryRun bool, entry types.AuditEntry) (types.EmailDomainMigration, error) {
+	domains, ok := storage.As[storage.EmailDomainStore](store)
+	if !ok {
+		return types.EmailDomainMigration{}, ErrUnsupported
+	}
+
+	from, to = strings.ToLower(strings.TrimSpace(from)), strings.ToLower(strings.TrimSpace(to))
+	check := validator.New()
+	for _, domain := range []string{from, to} {
+		if err := check.Var(domain, "required,fqdn"); err != nil {
+			return types.EmailDomainMigration{}, fmt.Errorf("%w: %q, want a domain like school.edu", ErrInvalidDomain, domain)
+		}
+	}
+	if from == to {
+		return types.EmailDomainMigration{}, fmt.Errorf("%w: from and to are both %s", ErrInvalidDomain, from)
+	}
+	if err := validate.CheckEmailDomain("student@" + to); err != nil {
+		return types.EmailDomainMigration{}, err
+	}
+
+	// 💾 One transaction: conflicts are found against the unique emails as they are
+	return domains.MigrateEmailDomain(from, to, dryRun, entry)
+}
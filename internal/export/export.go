@@ -0,0 +1,287 @@
+// Package export writes a tenant's students to a file in the blob store.
+// Exports run on the job queue, so a large roster never times out an HTTP
+// request; the export's ID is the ID of the job that writes it.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// TypeExport is the job type that writes one export
+const TypeExport = "students.export"
+
+// Supported file formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var Formats = []string{FormatCSV, FormatJSON}
+
+var contentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatJSON: "application/json",
+}
+
+var ErrNotFound = errors.New("export not found")
+
+// payload is the job payload; exports belong to the tenant that asked
+type payload struct {
+	TenantID int64  `json:"tenant_id"`
+	Format   string `json:"format"`
+}
+
+// Exporter queues exports and runs them as students.export jobs.
+// A nil *Exporter (no job queue) rejects everything with jobs.ErrDisabled.
+type Exporter struct {
+	backend storage.Storage
+	queue   *jobs.Queue
+	blobs   blob.Store
+}
+
+// -------------------------------------------------------------
+// New() → Exporter registered as the students.export job handler
+// -------------------------------------------------------------
+// Returns nil when there is no job queue.
+func New(backend storage.Storage, queue *jobs.Queue, blobs blob.Store) *Exporter {
+	if queue == nil {
+		return nil
+	}
+	e := &Exporter{backend: backend, queue: queue, blobs: blobs}
+	queue.Register(TypeExport, e.run)
+	return e
+}
+
+// Key is where an export's file lives in the blob store
+func Key(tenantID, id int64, format string) string {
+	return fmt.Sprintf("exports/%d/%d.%s", tenantID, id, format)
+}
+
+// ContentType is the media type of a file in format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// -------------------------------------------------------------
+// Start() → Queue an export of the request's tenant
+// -------------------------------------------------------------
+func (e *Exporter) Start(ctx context.Context, format string) (types.Export, error) {
+	if e == nil {
+		return types.Export{}, jobs.ErrDisabled
+	}
+	id, err := e.queue.Enqueue(TypeExport, payload{TenantID: tenantID(ctx), Format: format})
+	if err != nil {
+		return types.Export{}, err
+	}
+	return e.Get(ctx, id)
+}
+
+// -------------------------------------------------------------
+// Get() → The export's status, read from its job
+// -------------------------------------------------------------
+// Other tenants' exports (and other job types) are ErrNotFound.
+func (e *Exporter) Get(ctx context.Context, id int64) (types.Export, error) {
+	if e == nil {
+		return types.Export{}, jobs.ErrDisabled
+	}
+	job, err := e.backend.(storage.JobStore).GetJobById(id)
+	if err != nil {
+		return types.Export{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	var p payload
+	if job.Type != TypeExport || json.Unmarshal(job.Payload, &p) != nil || p.TenantID != tenantID(ctx) {
+		return types.Export{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+
+	return types.Export{
+		ID:         job.ID,
+		Format:     p.Format,
+		Status:     job.Status,
+		Attempts:   job.Attempts,
+		LastError:  job.LastError,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}, nil
+}
+
+// -------------------------------------------------------------
+// Open() → Reader over a finished export's file; the caller closes it
+// -------------------------------------------------------------
+func (e *Exporter) Open(ctx context.Context, export types.Export) (io.ReadCloser, blob.Object, error) {
+	if e == nil {
+		return nil, blob.Object{}, jobs.ErrDisabled
+	}
+	return e.blobs.Get(ctx, Key(tenantID(ctx), export.ID, export.Format))
+}
+
+// -------------------------------------------------------------
+// PresignURL() → Direct download link, when the blob store can make one
+// -------------------------------------------------------------
+func (e *Exporter) PresignURL(ctx context.Context, export types.Export, expires time.Duration) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+	presigner, ok := e.blobs.(blob.Presigner)
+	if !ok {
+		return "", false
+	}
+	url, err := presigner.PresignGet(Key(tenantID(ctx), export.ID, export.Format), expires)
+	return url, err == nil
+}
+
+// run is the students.export job: page through the tenant's students into a
+// temp file, then upload it. A retry simply overwrites the blob.
+func (e *Exporter) run(ctx context.Context, job types.Job) error {
+	var p payload
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode export payload: %w", err))
+	}
+	if _, ok := contentTypes[p.Format]; !ok {
+		return jobs.Permanent(fmt.Errorf("unsupported export format %q", p.Format))
+	}
+
+	scoped := e.backend
+	if scoper, ok := storage.As[storage.TenantScoper](e.backend); ok {
+		scoped = scoper.ForTenant(p.TenantID)
+	}
+
+	// 🧠 Spool to disk so memory stays flat however big the roster is
+	file, err := os.CreateTemp("", "student-export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	rows, err := Write(ctx, file, scoped, p.Format)
+	if err != nil {
+		return err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// 💾 Upload
+	key := Key(p.TenantID, job.ID, p.Format)
+	if err := e.blobs.Put(ctx, key, file, size, ContentType(p.Format)); err != nil {
+		return fmt.Errorf("store export: %w", err)
+	}
+
+	slog.Info("📤 Export written",
+		slog.Int64("id", job.ID),
+		slog.Int64("tenant_id", p.TenantID),
+		slog.String("format", p.Format),
+		slog.Int("rows", rows),
+		slog.Int64("bytes", size),
+	)
+	return nil
+}
+
+// Write streams every live student to w in format, read through
+// GetStudentsIter, and returns how many it wrote; format must be one of
+// Formats
+func Write(ctx context.Context, w io.Writer, store storage.Storage, format string) (int, error) {
+	var (
+		rows   int
+		encode func(types.Student) error
+		finish func() error
+	)
+
+	switch format {
+	case FormatCSV:
+		out := csv.NewWriter(w)
+		header := []string{
+			"id", "name", "email", "age", "phone", "date_of_birth", "gender",
+			"address_line1", "address_line2", "address_city", "address_region", "address_postal_code", "address_country", "custom",
+		}
+		if err := out.Write(header); err != nil {
+			return 0, err
+		}
+		encode = func(s types.Student) error {
+			// the address is flattened into columns, blank when unset
+			var a types.Address
+			if s.Address != nil {
+				a = *s.Address
+			}
+			// custom fields stay one JSON object, blank when unset
+			var custom string
+			if len(s.Custom) > 0 {
+				encoded, err := json.Marshal(s.Custom)
+				if err != nil {
+					return err
+				}
+				custom = string(encoded)
+			}
+			return out.Write([]string{
+				strconv.FormatInt(s.ID, 10), s.Name, s.Email, strconv.Itoa(s.Age), s.Phone, s.DateOfBirth, s.Gender,
+				a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.Country, custom,
+			})
+		}
+		finish = func() error {
+			out.Flush()
+			return out.Error()
+		}
+	case FormatJSON:
+		// a JSON array, written one element at a time
+		out := json.NewEncoder(w)
+		sep := "["
+		encode = func(s types.Student) error {
+			if _, err := io.WriteString(w, sep); err != nil {
+				return err
+			}
+			sep = ","
+			return out.Encode(s)
+		}
+		finish = func() error {
+			if sep == "[" {
+				_, err := io.WriteString(w, "[]\n")
+				return err
+			}
+			_, err := io.WriteString(w, "]\n")
+			return err
+		}
+	}
+
+	var encodeErr error
+	err := store.GetStudentsIter(ctx, nil, func(student types.Student) error {
+		if encodeErr = encode(student); encodeErr != nil {
+			return encodeErr
+		}
+		rows++
+		return nil
+	})
+	if err != nil && err != encodeErr {
+		return rows, fmt.Errorf("read students: %w", err)
+	}
+	if err != nil {
+		return rows, err
+	}
+	return rows, finish()
+}
+
+// tenantID is the request's tenant, or the default one when tenancy is off
+func tenantID(ctx context.Context) int64 {
+	if t, ok := tenant.FromContext(ctx); ok {
+		return t.ID
+	}
+	return storage.DefaultTenantID
+}
@@ -0,0 +1,116 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+// Provider reads flags from outside the config file
+type Provider interface {
+	// Fetch returns every flag the source defines; they replace the
+	// previous fetch as a whole
+	Fetch(ctx context.Context) (map[string]config.FeatureFlag, error)
+}
+
+// maxRemoteBytes caps one answer of the flag service
+const maxRemoteBytes = 1 << 20
+
+// httpProvider GETs {"flags": {...}} from a flag service
+type httpProvider struct {
+	url, token string
+	client     *http.Client
+}
+
+// -------------------------------------------------------------
+// NewHTTPProvider() → Provider for feature_flags.remote
+// -------------------------------------------------------------
+func NewHTTPProvider(cfg config.FeatureFlagsRemote) Provider {
+	return &httpProvider{
+		url:    cfg.URL,
+		token:  cfg.Token,
+		client: httpclient.New(httpclient.Options{Timeout: 10 * time.Second, Retries: 2, UserAgent: "go-student-api-flags"}),
+	}
+}
+
+func (p *httpProvider) Fetch(ctx context.Context) (map[string]config.FeatureFlag, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("flag service answered %s", res.Status)
+	}
+
+	var payload struct {
+		Flags map[string]config.FeatureFlag `json:"flags"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxRemoteBytes)).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode flag service response: %w", err)
+	}
+	for name, flag := range payload.Flags {
+		if flag.Rollout < 0 || flag.Rollout > 100 {
+			return nil, fmt.Errorf("flag %s: rollout must be between 0 and 100, got %d", name, flag.Rollout)
+		}
+	}
+	return payload.Flags, nil
+}
+
+// -------------------------------------------------------------
+// Poll() → Refresh the remote flags every interval until ctx is cancelled
+// -------------------------------------------------------------
+// The first fetch runs before Poll returns, so the server starts with the
+// remote flags when the service is up. A failed fetch keeps the flags of
+// the last good one.
+func (s *Set) Poll(ctx context.Context, provider Provider, interval time.Duration) {
+	s.sync(ctx, provider)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sync(ctx, provider)
+			}
+		}
+	}()
+}
+
+// sync runs one fetch and records its outcome
+func (s *Set) sync(ctx context.Context, provider Provider) {
+	flags, err := provider.Fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		if s.syncErr == "" {
+			slog.Warn("⚠️ Feature flag fetch failed, keeping the last flags", slog.String("error", err.Error()))
+		}
+		s.syncErr = err.Error()
+		return
+	}
+	if s.syncErr != "" || s.syncedAt.IsZero() {
+		slog.Info("🚩 Feature flags fetched", slog.Int("flags", len(flags)))
+	}
+	s.remote, s.syncedAt, s.syncErr = flags, time.Now().UTC(), ""
+}
@@ -0,0 +1,211 @@
+// Package fieldcrypt seals individual PII columns with AES-256-GCM before
+// they reach the database, and opens them again on read. Sealed values are
+// self-describing text, "enc2:<key id>:<base64 nonce+ciphertext>", so several
+// keys can coexist while a rotation is re-encrypting old rows.
+//
+// Each value is bound to its column and row (the additional data is the key
+// id, column and row id), so a sealed value copied into another row or
+// column fails to open instead of passing for that row's data.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// Prefix starts every sealed value
+const Prefix = "enc2:"
+
+// legacyPrefix starts values sealed before they were bound to their column
+// and row. They still open, and are Stale so a re-encryption binds them.
+const legacyPrefix = "enc:"
+
+// ErrDisabled is returned by operations that need encryption.enabled
+var ErrDisabled = errors.New("field encryption is disabled (encryption.enabled)")
+
+// Cipher seals with the active key and opens with any configured key.
+// A nil *Cipher (encryption disabled) stores values as plain text.
+type Cipher struct {
+	active string
+	aeads  map[string]cipher.AEAD
+	index  []byte
+}
+
+// -------------------------------------------------------------
+// New() → Cipher from config, nil when encryption is disabled
+// -------------------------------------------------------------
+func New(cfg config.Encryption) (*Cipher, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	ring, err := cfg.KeyRing()
+	if err != nil {
+		return nil, err
+	}
+	index, err := cfg.IndexSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cipher{active: ring[0].ID, aeads: map[string]cipher.AEAD{}, index: index}
+	for _, k := range ring {
+		block, err := aes.NewCipher(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", k.ID, err)
+		}
+		if c.aeads[k.ID], err = cipher.NewGCM(block); err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", k.ID, err)
+		}
+	}
+	return c, nil
+}
+
+// Seal encrypts plain with the active key, bound to column ("students.email")
+// and row (the row's stable id); plain is returned as-is when c is nil
+func (c *Cipher) Seal(column, row, plain string) (string, error) {
+	if c == nil {
+		return plain, nil
+	}
+	aead := c.aeads[c.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("encryption nonce failed: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), additionalData(c.active, column, row))
+	return Prefix + c.active + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value sealed for column and row; values stored before
+// encryption was enabled come back unchanged
+func (c *Cipher) Open(column, row, stored string) (string, error) {
+	rest, bound := strings.CutPrefix(stored, Prefix)
+	if !bound {
+		var legacy bool
+		if rest, legacy = strings.CutPrefix(stored, legacyPrefix); !legacy {
+			return stored, nil
+		}
+	}
+	if c == nil {
+		return "", fmt.Errorf("stored value is encrypted: %w", ErrDisabled)
+	}
+	id, encoded, _ := strings.Cut(rest, ":")
+	aead, ok := c.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("value sealed with unknown encryption key %q", id)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	data := []byte(id)
+	if bound {
+		data = additionalData(id, column, row)
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], data)
+	if err != nil {
+		return "", fmt.Errorf("decrypt %s with key %q failed: %w", column, id, err)
+	}
+	return string(plain), nil
+}
+
+// additionalData is what a sealed value is bound to; the parts are
+// NUL-separated so no two (column, row) pairs run together alike
+func additionalData(keyID, column, row string) []byte {
+	return []byte(keyID + "\x00" + column + "\x00" + row)
+}
+
+// Stale reports whether stored should be re-encrypted: plain text, sealed
+// with a key other than the active one, or sealed unbound (legacy)
+func (c *Cipher) Stale(stored string) bool {
+	if c == nil {
+		return false
+	}
+	return !strings.HasPrefix(stored, Prefix+c.active+":")
+}
+
+// Reseal opens stored and seals it again under the active key, for the
+// same column and row; an empty value (an unset optional column) stays empty
+func (c *Cipher) Reseal(column, row, stored string) (string, error) {
+	if stored == "" {
+		return "", nil
+	}
+	plain, err := c.Open(column, row, stored)
+	if err != nil {
+		return "", err
+	}
+	return c.Seal(column, row, plain)
+}
+
+// -------------------------------------------------------------
+// Index() → Blind index of a column value for equality lookups and uniqueness
+// -------------------------------------------------------------
+// HMAC-SHA256 under the index key, over the column name and value so equal
+// values in different columns don't match; nil (SQL NULL) when c is nil, so
+// the plain column keeps doing the job.
+func (c *Cipher) Index(column, plain string) *string {
+	if c == nil {
+		return nil
+	}
+	mac := hmac.New(sha256.New, c.index)
+	mac.Write([]byte(column))
+	mac.Write([]byte{0})
+	mac.Write([]byte(plain))
+	sum := hex.EncodeToString(mac.Sum(nil))
+	return &sum
+}
+
+// Lookup returns the column and value that find a row by a sealed column's
+// plain value: "<column>_hash" and the blind index when encryption is on,
+// column and plain otherwise
+func (c *Cipher) Lookup(column, plain string) (string, any) {
+	if c == nil {
+		return column, plain
+	}
+	return column + "_hash", *c.Index(column, plain)
+}
+
+// Field is a Scan target that opens column into dst. row is called at
+// Scan time: Scan fills targets in column order, so a row id selected
+// before the sealed column is already set.
+func (c *Cipher) Field(column string, row func() string, dst *string) sql.Scanner {
+	return &field{c: c, column: column, row: row, dst: dst}
+}
+
+type field struct {
+	c      *Cipher
+	column string
+	row    func() string
+	dst    *string
+}
+
+func (f *field) Scan(src any) error {
+	var stored string
+	switch v := src.(type) {
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	case nil:
+		*f.dst = ""
+		return nil
+	default:
+		return fmt.Errorf("unsupported encrypted column type %T", src)
+	}
+	plain, err := f.c.Open(f.column, f.row(), stored)
+	if err != nil {
+		return err
+	}
+	*f.dst = plain
+	return nil
+}
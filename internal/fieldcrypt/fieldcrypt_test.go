@@ -0,0 +1,274 @@
+package fieldcrypt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+var (
+	keyA  = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	keyB  = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	index = base64.StdEncoding.EncodeToString([]byte("an index key of thirty-two bytes"))
+)
+
+func newCipher(t *testing.T, keys string) *Cipher {
+	t.Helper()
+	c, err := New(config.Encryption{Enabled: true, Keys: keys, IndexKey: index})
+	if err != nil {
+		t.Fatalf("New(%q): %v", keys, err)
+	}
+	return c
+}
+
+func TestSealOpen(t *testing.T) {
+	c := newCipher(t, "a:"+keyA)
+	tests := []struct {
+		name   string
+		column string
+		row    string
+		plain  string
+	}{
+		{name: "email", column: "students.email", row: "01J0000000000000000000000A", plain: "ada@example.com"},
+		{name: "empty", column: "students.phone", row: "01J0000000000000000000000A", plain: ""},
+		{name: "unicode", column: "students.address", row: "01J0000000000000000000000B", plain: `{"city":"Zürich"}`},
+		{name: "numeric row", column: "guardians.email", row: "42", plain: "parent@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := c.Seal(tt.column, tt.row, tt.plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(sealed, Prefix+"a:") {
+				t.Fatalf("sealed = %q, want prefix %q", sealed, Prefix+"a:")
+			}
+			if tt.plain != "" && strings.Contains(sealed, tt.plain) {
+				t.Fatalf("sealed value %q contains the plain text", sealed)
+			}
+			if again, _ := c.Seal(tt.column, tt.row, tt.plain); again == sealed {
+				t.Error("two seals of the same value are identical; nonce reused")
+			}
+			got, err := c.Open(tt.column, tt.row, sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.plain {
+				t.Errorf("Open = %q, want %q", got, tt.plain)
+			}
+			if c.Stale(sealed) {
+				t.Error("freshly sealed value is stale")
+			}
+		})
+	}
+}
+
+func TestOpenBinding(t *testing.T) {
+	c := newCipher(t, "a:"+keyA)
+	sealed, err := c.Seal("students.email", "row-1", "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		column string
+		row    string
+	}{
+		{name: "other row", column: "students.email", row: "row-2"},
+		{name: "other column", column: "students.phone", row: "row-1"},
+		{name: "other table", column: "guardians.email", row: "row-1"},
+		{name: "no row", column: "students.email", row: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := c.Open(tt.column, tt.row, sealed); err == nil {
+				t.Fatalf("Open(%q, %q) = %q, want an error", tt.column, tt.row, got)
+			}
+		})
+	}
+}
+
+func TestOpenPlainAndMalformed(t *testing.T) {
+	c := newCipher(t, "a:"+keyA)
+	tests := []struct {
+		name    string
+		c       *Cipher
+		stored  string
+		want    string
+		wantErr string
+	}{
+		{name: "plain passes through", c: c, stored: "ada@example.com", want: "ada@example.com"},
+		{name: "plain without cipher", c: nil, stored: "ada@example.com", want: "ada@example.com"},
+		{name: "sealed without cipher", c: nil, stored: Prefix + "a:AAAA", wantErr: "encryption.enabled"},
+		{name: "unknown key", c: c, stored: Prefix + "z:AAAA", wantErr: `unknown encryption key "z"`},
+		{name: "bad base64", c: c, stored: Prefix + "a:!!!", wantErr: "malformed"},
+		{name: "too short", c: c, stored: Prefix + "a:AAAA", wantErr: "malformed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.Open("students.email", "row-1", tt.stored)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Open error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Open = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilCipherStoresPlain(t *testing.T) {
+	var c *Cipher
+	sealed, err := c.Seal("students.email", "row-1", "ada@example.com")
+	if err != nil || sealed != "ada@example.com" {
+		t.Fatalf("Seal = %q, %v; want the plain value", sealed, err)
+	}
+	if c.Stale(sealed) {
+		t.Error("nothing is stale without encryption")
+	}
+	if c.Index("email", "ada@example.com") != nil {
+		t.Error("Index without encryption should be nil")
+	}
+	if key, value := c.Lookup("email", "ada@example.com"); key != "email" || value != "ada@example.com" {
+		t.Errorf("Lookup = %q, %v; want the plain column", key, value)
+	}
+}
+
+// After a rotation the old key still opens what it sealed, and Stale and
+// Reseal move those values to the new key.
+func TestKeyRotation(t *testing.T) {
+	before := newCipher(t, "a:"+keyA)
+	after := newCipher(t, "b:"+keyB+",a:"+keyA)
+	dropped := newCipher(t, "b:"+keyB)
+
+	old, err := before.Seal("students.phone", "row-1", "+1 555 0100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.Stale(old) {
+		t.Error("value sealed with the retired key is not stale")
+	}
+	if got, err := after.Open("students.phone", "row-1", old); err != nil || got != "+1 555 0100" {
+		t.Fatalf("Open with the retired key = %q, %v", got, err)
+	}
+	if _, err := dropped.Open("students.phone", "row-1", old); err == nil {
+		t.Error("a key removed from the ring still opens its values")
+	}
+
+	resealed, err := after.Reseal("students.phone", "row-1", old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resealed, Prefix+"b:") || after.Stale(resealed) {
+		t.Errorf("Reseal = %q, want it sealed with the active key", resealed)
+	}
+	if got, err := dropped.Open("students.phone", "row-1", resealed); err != nil || got != "+1 555 0100" {
+		t.Errorf("Open of the resealed value = %q, %v", got, err)
+	}
+	if got, _ := after.Reseal("students.phone", "row-1", ""); got != "" {
+		t.Errorf("Reseal of an empty column = %q, want it left empty", got)
+	}
+	if !after.Stale("+1 555 0100") {
+		t.Error("plain text is not stale once encryption is on")
+	}
+}
+
+// Values sealed before the column and row were bound in still open, and are
+// stale so a re-encryption binds them.
+func TestLegacyValues(t *testing.T) {
+	c := newCipher(t, "a:"+keyA)
+	aead := c.aeads["a"]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	legacy := legacyPrefix + "a:" + base64.RawStdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte("ada@example.com"), []byte("a")))
+
+	if got, err := c.Open("students.email", "row-1", legacy); err != nil || got != "ada@example.com" {
+		t.Fatalf("Open(legacy) = %q, %v", got, err)
+	}
+	if !c.Stale(legacy) {
+		t.Error("legacy value is not stale")
+	}
+	bound, err := c.Reseal("students.email", "row-1", legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(bound, Prefix) || c.Stale(bound) {
+		t.Errorf("Reseal(legacy) = %q, want a bound value", bound)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	c := newCipher(t, "a:"+keyA)
+	rotated := newCipher(t, "b:"+keyB+",a:"+keyA)
+	ada := *c.Index("email", "ada@example.com")
+
+	tests := []struct {
+		name   string
+		c      *Cipher
+		column string
+		value  string
+		same   bool
+	}{
+		{name: "same value", c: c, column: "email", value: "ada@example.com", same: true},
+		{name: "after key rotation", c: rotated, column: "email", value: "ada@example.com", same: true},
+		{name: "other value", c: c, column: "email", value: "bob@example.com"},
+		{name: "other column", c: c, column: "phone", value: "ada@example.com"},
+		{name: "case matters", c: c, column: "email", value: "Ada@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := *tt.c.Index(tt.column, tt.value)
+			if (got == ada) != tt.same {
+				t.Errorf("Index(%q, %q) = %s; same as ada's = %v, want %v", tt.column, tt.value, got, got == ada, tt.same)
+			}
+		})
+	}
+
+	key, value := c.Lookup("email", "ada@example.com")
+	if key != "email_hash" || value != ada {
+		t.Errorf("Lookup = %q, %v; want email_hash and the index", key, value)
+	}
+}
+
+func TestField(t *testing.T) {
+	c := newCipher(t, "a:"+keyA)
+	sealed, err := c.Seal("students.email", "row-1", "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// row is read at Scan time, like a public_id scanned just before
+	row := ""
+	var got string
+	field := c.Field("students.email", func() string { return row }, &got)
+	row = "row-1"
+	if err := field.Scan([]byte(sealed)); err != nil {
+		t.Fatal(err)
+	}
+	if got != "ada@example.com" {
+		t.Errorf("Scan = %q, want the plain email", got)
+	}
+	if err := field.Scan(nil); err != nil || got != "" {
+		t.Errorf("Scan(NULL) = %q, %v; want empty", got, err)
+	}
+	row = "row-2"
+	if err := field.Scan(sealed); err == nil {
+		t.Error("Scan opened a value sealed for another row")
+	}
+}
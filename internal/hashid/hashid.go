@@ -0,0 +1,184 @@
+// Package hashid encodes numeric ids as short strings with the Hashids
+// algorithm (https://hashids.org): a salted alphabet shuffle, so the
+// strings can't be counted through or decoded without the salt, but any
+// Hashids library with the same salt, minimum length and default alphabet
+// decodes them. It hides ids, it doesn't encrypt them.
+package hashid
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	defaultAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+	defaultSeps     = "cfhistuCFHISTU"
+	sepDiv          = 3.5
+	guardDiv        = 12
+)
+
+// Codec encodes and decodes ids for one salt.
+// A nil *Codec (no enumeration.hashid_salt) means ids stay numeric.
+type Codec struct {
+	salt      string
+	minLength int
+	alphabet  string
+	seps      string
+	guards    string
+}
+
+// -------------------------------------------------------------
+// New() → Codec for salt padding to minLength, or nil when salt is empty
+// -------------------------------------------------------------
+func New(salt string, minLength int) *Codec {
+	if salt == "" {
+		return nil
+	}
+	alphabet, seps := defaultAlphabet, ""
+	// the separators are taken out of the alphabet
+	for _, c := range defaultSeps {
+		if strings.ContainsRune(alphabet, c) {
+			seps += string(c)
+			alphabet = strings.ReplaceAll(alphabet, string(c), "")
+		}
+	}
+	seps = shuffle(seps, salt)
+
+	if len(seps) == 0 || float64(len(alphabet))/float64(len(seps)) > sepDiv {
+		sepsLength := int(math.Ceil(float64(len(alphabet)) / sepDiv))
+		if sepsLength == 1 {
+			sepsLength++
+		}
+		if sepsLength > len(seps) {
+			diff := sepsLength - len(seps)
+			seps += alphabet[:diff]
+			alphabet = alphabet[diff:]
+		} else {
+			seps = seps[:sepsLength]
+		}
+	}
+	alphabet = shuffle(alphabet, salt)
+
+	guardCount := int(math.Ceil(float64(len(alphabet)) / guardDiv))
+	var guards string
+	if len(alphabet) < 3 {
+		guards, seps = seps[:guardCount], seps[guardCount:]
+	} else {
+		guards, alphabet = alphabet[:guardCount], alphabet[guardCount:]
+	}
+
+	return &Codec{salt: salt, minLength: max(minLength, 0), alphabet: alphabet, seps: seps, guards: guards}
+}
+
+// -------------------------------------------------------------
+// Encode() → Hashid of a non-negative id
+// -------------------------------------------------------------
+func (c *Codec) Encode(id int64) string {
+	if id < 0 {
+		return ""
+	}
+	alphabet := c.alphabet
+	numbersID := id % 100
+
+	lottery := alphabet[numbersID%int64(len(alphabet))]
+	buffer := string(lottery) + c.salt + alphabet
+	alphabet = shuffle(alphabet, buffer[:len(alphabet)])
+	out := string(lottery) + toAlphabet(id, alphabet)
+
+	if len(out) < c.minLength {
+		guard := c.guards[(numbersID+int64(out[0]))%int64(len(c.guards))]
+		out = string(guard) + out
+		if len(out) < c.minLength {
+			guard = c.guards[(numbersID+int64(out[2]))%int64(len(c.guards))]
+			out += string(guard)
+		}
+	}
+
+	half := len(alphabet) / 2
+	for len(out) < c.minLength {
+		alphabet = shuffle(alphabet, alphabet)
+		out = alphabet[half:] + out + alphabet[:half]
+		if excess := len(out) - c.minLength; excess > 0 {
+			out = out[excess/2 : excess/2+c.minLength]
+		}
+	}
+	return out
+}
+
+// -------------------------------------------------------------
+// Decode() → id of a hashid from Encode; false for anything else
+// -------------------------------------------------------------
+// Strings that merely look like one (a typo, another salt) don't decode:
+// the id is encoded again and must give back s exactly.
+func (c *Codec) Decode(s string) (int64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	// guards pad the id on one or both sides
+	parts := strings.Split(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(c.guards, r) {
+			return ' '
+		}
+		return r
+	}, s), " ")
+	breakdown := parts[0]
+	if len(parts) == 2 || len(parts) == 3 {
+		breakdown = parts[1]
+	}
+	if len(breakdown) < 2 || strings.ContainsAny(breakdown, c.seps) {
+		return 0, false
+	}
+
+	lottery := breakdown[:1]
+	buffer := lottery + c.salt + c.alphabet
+	alphabet := shuffle(c.alphabet, buffer[:len(c.alphabet)])
+	id, ok := fromAlphabet(breakdown[1:], alphabet)
+	if !ok || c.Encode(id) != s {
+		return 0, false
+	}
+	return id, true
+}
+
+// shuffle is the Hashids consistent shuffle of alphabet by salt
+func shuffle(alphabet, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+	chars := []byte(alphabet)
+	for i, v, p := len(chars)-1, 0, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		n := int(salt[v])
+		p += n
+		j := (n + v + p) % i
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return string(chars)
+}
+
+// toAlphabet writes n in base len(alphabet) with alphabet as digits
+func toAlphabet(n int64, alphabet string) string {
+	base := int64(len(alphabet))
+	var out []byte
+	for {
+		out = append([]byte{alphabet[n%base]}, out...)
+		n /= base
+		if n == 0 {
+			return string(out)
+		}
+	}
+}
+
+// fromAlphabet reads what toAlphabet wrote; false on other characters or
+// an int64 overflow
+func fromAlphabet(s, alphabet string) (int64, bool) {
+	base := int64(len(alphabet))
+	var n int64
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(alphabet, s[i])
+		if digit < 0 || n > (math.MaxInt64-int64(digit))/base {
+			return 0, false
+		}
+		n = n*base + int64(digit)
+	}
+	return n, true
+}
@@ -0,0 +1,76 @@
+package hashid
+
+import (
+	"math"
+	"testing"
+)
+
+// Vectors from the reference implementation, so other Hashids libraries
+// decode what we encode
+func TestEncodeReference(t *testing.T) {
+	tests := []struct {
+		salt      string
+		minLength int
+		id        int64
+		want      string
+	}{
+		{salt: "this is my salt", id: 12345, want: "NkK9"},
+		{salt: "this is my salt", minLength: 8, id: 1, want: "gB0NV05e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := New(tt.salt, tt.minLength).Encode(tt.id); got != tt.want {
+				t.Errorf("Encode(%d) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := New("student-api", 6)
+	for _, id := range []int64{0, 1, 42, 99, 100, 12345, 1 << 40, math.MaxInt64} {
+		s := c.Encode(id)
+		if len(s) < 6 {
+			t.Errorf("Encode(%d) = %q, shorter than the minimum", id, s)
+		}
+		if got, ok := c.Decode(s); !ok || got != id {
+			t.Errorf("Decode(Encode(%d)) = %d, %v", id, got, ok)
+		}
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	c := New("student-api", 6)
+	one := c.Encode(1)
+	typo := []byte(one)
+	typo[len(typo)-1] ^= 1
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{name: "empty", s: ""},
+		{name: "numeric id", s: "1"},
+		{name: "other salt", s: New("another salt", 6).Encode(1)},
+		{name: "typo", s: string(typo)},
+		{name: "foreign characters", s: "ab-cd_ef"},
+		{name: "too long", s: one + one},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id, ok := c.Decode(tt.s); ok {
+				t.Errorf("Decode(%q) = %d, want it rejected", tt.s, id)
+			}
+		})
+	}
+}
+
+func TestNilCodec(t *testing.T) {
+	if New("", 8) != nil {
+		t.Fatal("New without a salt should be nil")
+	}
+	if got := New("salt", 0).Encode(-1); got != "" {
+		t.Errorf("Encode(-1) = %q, want empty", got)
+	}
+}
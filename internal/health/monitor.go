@@ -0,0 +1,166 @@
+// Package health watches the database in the background. Requests that hit
+// a dead database fail one by one with whatever error the driver gives; the
+// monitor notices the outage once, reports the instance not ready (so load
+// balancers route around it) and, for postgres, reconnects with backoff
+// until the database answers again.
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/backoff"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Monitor pings the database every health.interval.
+// A nil *Monitor (backend with nothing to ping) is always ready.
+type Monitor struct {
+	store     storage.HealthStore
+	reconnect storage.ReconnectStore
+	cfg       config.Health
+
+	mu     sync.Mutex
+	status types.Health
+
+	done chan struct{}
+	stop context.CancelFunc
+}
+
+// New returns nil when the backend can't be pinged (memory).
+func New(backend storage.Storage, cfg config.Health) *Monitor {
+	store, ok := storage.As[storage.HealthStore](backend)
+	if !ok {
+		return nil
+	}
+	reconnect, _ := storage.As[storage.ReconnectStore](backend)
+	return &Monitor{store: store, reconnect: reconnect, cfg: cfg, status: types.Health{Status: types.HealthOK}}
+}
+
+// -------------------------------------------------------------
+// Start() → Ping now, then keep pinging until Stop (or ctx ends)
+// -------------------------------------------------------------
+func (m *Monitor) Start(ctx context.Context) {
+	if m == nil {
+		return
+	}
+	ctx, m.stop = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go m.run(ctx)
+	slog.Info("🩺 Storage health monitor started", slog.Duration("interval", m.cfg.Interval))
+}
+
+// -------------------------------------------------------------
+// Stop() → Stop pinging; waits for a ping in flight
+// -------------------------------------------------------------
+func (m *Monitor) Stop(ctx context.Context) error {
+	if m == nil || m.stop == nil {
+		return nil
+	}
+	m.stop()
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("storage health check still running at shutdown")
+	}
+}
+
+// Ready reports false while the database is considered down
+func (m *Monitor) Ready() bool {
+	return m.Status().Status == types.HealthOK
+}
+
+// Status is a copy of the last check's outcome
+func (m *Monitor) Status() types.Health {
+	if m == nil {
+		return types.Health{Status: types.HealthOK}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// RetryAfter is a hint for clients turned away while degraded
+func (m *Monitor) RetryAfter() time.Duration {
+	if m == nil {
+		return 0
+	}
+	return m.cfg.Interval
+}
+
+func (m *Monitor) run(ctx context.Context) {
+	defer close(m.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		timer.Reset(m.check(ctx))
+	}
+}
+
+// check pings (or, once degraded, reconnects) and returns the wait before
+// the next check: the interval while healthy, a growing backoff while not
+func (m *Monitor) check(ctx context.Context) time.Duration {
+	degraded := !m.Ready()
+
+	pingCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+	var err error
+	if degraded && m.reconnect != nil {
+		err = m.reconnect.Reconnect(pingCtx)
+	} else {
+		err = m.store.Ping(pingCtx)
+	}
+	if ctx.Err() != nil {
+		// shutting down; a ping cut short says nothing about the database
+		return m.cfg.Interval
+	}
+
+	now := time.Now().UTC()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.CheckedAt = &now
+	if degraded && m.reconnect != nil {
+		m.status.Reconnects++
+	}
+
+	if err == nil {
+		if degraded {
+			slog.Info("✅ Storage is reachable again, instance is ready",
+				slog.Int("failed_checks", m.status.Failures),
+				slog.Duration("downtime", now.Sub(*m.status.DegradedSince)),
+			)
+		}
+		m.status.Status, m.status.Failures, m.status.LastError, m.status.DegradedSince = types.HealthOK, 0, "", nil
+		return m.cfg.Interval
+	}
+
+	m.status.Failures++
+	m.status.LastError = err.Error()
+	slog.Error("❌ Storage health check failed",
+		slog.Int("failures", m.status.Failures),
+		slog.String("error", err.Error()),
+	)
+	if m.status.Failures < m.cfg.FailureThreshold {
+		return m.cfg.Interval
+	}
+	if !degraded {
+		m.status.Status, m.status.DegradedSince = types.HealthDegraded, &now
+		slog.Warn("⚠️ Storage unreachable, instance is not ready until it answers again",
+			slog.Int("failures", m.status.Failures),
+		)
+	}
+	// the first attempt after the threshold waits one interval, then longer
+	return backoff.Exponential(m.cfg.Interval, m.status.Failures-m.cfg.FailureThreshold+1, m.cfg.MaxBackoff)
+}
@@ -0,0 +1,244 @@
+// Package bind parses and validates query parameters into typed values.
+// Every problem is collected, so one 400 response lists them all:
+//
+//	q := bind.NewQuery(r)
+//	limit := q.Int("limit", 20, 1, 100)
+//	status := q.OneOf("status", "", "queued", "failed")
+//	if err := q.Err(); err != nil {
+//		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+//		return
+//	}
+package bind
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// FieldError describes one invalid query parameter.
+type FieldError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s (got %q)", e.Field, e.Reason, e.Value)
+}
+
+// Errors is every FieldError of a request; use errors.As to inspect them.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// FieldErrors lists e in the "errors" of the 400 problem response
+func (e Errors) FieldErrors() []response.FieldError {
+	fields := make([]response.FieldError, len(e))
+	for i, err := range e {
+		fields[i] = response.FieldError{Field: err.Field, Message: err.Error()}
+	}
+	return fields
+}
+
+// Sort is a parsed ?sort= value: "name" ascending, "-name" descending.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query reads typed values from a request's query string.
+type Query struct {
+	values url.Values
+	errs   Errors
+}
+
+func NewQuery(r *http.Request) *Query {
+	return &Query{values: r.URL.Query()}
+}
+
+// Err returns nil or the Errors collected so far
+func (q *Query) Err() error {
+	if len(q.errs) == 0 {
+		return nil
+	}
+	return q.errs
+}
+
+// Has reports whether the parameter is present (even if empty)
+func (q *Query) Has(name string) bool {
+	return q.values.Has(name)
+}
+
+func (q *Query) fail(name, value, format string, args ...any) {
+	q.errs = append(q.errs, &FieldError{Field: name, Value: value, Reason: fmt.Sprintf(format, args...)})
+}
+
+// raw returns the trimmed value; ok is false when missing or empty
+func (q *Query) raw(name string) (string, bool) {
+	value := strings.TrimSpace(q.values.Get(name))
+	return value, value != ""
+}
+
+// -------------------------------------------------------------
+// String() → Raw value, or def when missing
+// -------------------------------------------------------------
+func (q *Query) String(name, def string) string {
+	if value, ok := q.raw(name); ok {
+		return value
+	}
+	return def
+}
+
+// -------------------------------------------------------------
+// Int() → Integer within [min, max], or def when missing
+// -------------------------------------------------------------
+func (q *Query) Int(name string, def, min, max int) int {
+	value, ok := q.raw(name)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		q.fail(name, value, "must be between %d and %d", min, max)
+		return def
+	}
+	return n
+}
+
+// -------------------------------------------------------------
+// Bool() → true/false/1/0, or def when missing
+// -------------------------------------------------------------
+func (q *Query) Bool(name string, def bool) bool {
+	value, ok := q.raw(name)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		q.fail(name, value, "must be true or false")
+		return def
+	}
+	return b
+}
+
+// -------------------------------------------------------------
+// Duration() → Go duration (e.g. 720h) of at least min, or def when missing
+// -------------------------------------------------------------
+func (q *Query) Duration(name string, def, min time.Duration) time.Duration {
+	value, ok := q.raw(name)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < min {
+		q.fail(name, value, "must be a duration of at least %s (e.g. 720h)", min)
+		return def
+	}
+	return d
+}
+
+// -------------------------------------------------------------
+// OneOf() → One of the allowed values, or def when missing
+// -------------------------------------------------------------
+func (q *Query) OneOf(name, def string, allowed ...string) string {
+	value, ok := q.raw(name)
+	if !ok {
+		return def
+	}
+	if !slices.Contains(allowed, value) {
+		q.fail(name, value, "must be one of %s", strings.Join(allowed, ", "))
+		return def
+	}
+	return value
+}
+
+// -------------------------------------------------------------
+// Sort() → "field" or "-field" where field is allowed, or def when missing
+// -------------------------------------------------------------
+func (q *Query) Sort(name string, def Sort, fields ...string) Sort {
+	value, ok := q.raw(name)
+	if !ok {
+		return def
+	}
+	sort := Sort{Field: strings.TrimPrefix(value, "-"), Desc: strings.HasPrefix(value, "-")}
+	if !slices.Contains(fields, sort.Field) {
+		q.fail(name, value, "must be one of %s (prefix - for descending)", strings.Join(fields, ", "))
+		return def
+	}
+	return sort
+}
+
+// -------------------------------------------------------------
+// Fields() → Comma-separated subset of allowed, or nil when missing
+// -------------------------------------------------------------
+// Duplicates are dropped and the client's order is kept.
+func (q *Query) Fields(name string, allowed ...string) []string {
+	value, ok := q.raw(name)
+	if !ok {
+		return nil
+	}
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(allowed, field) {
+			q.fail(name, value, "must list fields from %s", strings.Join(allowed, ", "))
+			return nil
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		q.fail(name, value, "must name at least one field")
+	}
+	return fields
+}
+
+// -------------------------------------------------------------
+// IDs() → Comma-separated positive IDs (at most max), or nil when missing
+// -------------------------------------------------------------
+// Duplicates are dropped and the client's order is kept.
+func (q *Query) IDs(name string, max int) []int64 {
+	value, ok := q.raw(name)
+	if !ok {
+		return nil
+	}
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id < 1 {
+			q.fail(name, value, "must be a comma-separated list of positive ids")
+			return nil
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+		// stop early: a long list would make the duplicate check quadratic
+		if len(ids) > max {
+			q.fail(name, value, "must list at most %d ids", max)
+			return nil
+		}
+	}
+	if len(ids) == 0 {
+		q.fail(name, value, "must name at least one id")
+	}
+	return ids
+}
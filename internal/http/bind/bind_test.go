@@ -0,0 +1,151 @@
+package bind
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func query(raw string) *Query {
+	return NewQuery(httptest.NewRequest("GET", "/?"+raw, nil))
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "", want: 20},
+		{raw: "limit=", want: 20},
+		{raw: "limit=%20%2050%20", want: 50},
+		{raw: "limit=1", want: 1},
+		{raw: "limit=100", want: 100},
+		{raw: "limit=0", want: 20, wantErr: true},
+		{raw: "limit=101", want: 20, wantErr: true},
+		{raw: "limit=ten", want: 20, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			q := query(tt.raw)
+			if got := q.Int("limit", 20, 1, 100); got != tt.want {
+				t.Errorf("Int = %d, want %d", got, tt.want)
+			}
+			if (q.Err() != nil) != tt.wantErr {
+				t.Errorf("Err = %v, want error %v", q.Err(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScalars(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		get     func(q *Query) any
+		want    any
+		wantErr bool
+	}{
+		{name: "string", raw: "q=%20ada%20", get: func(q *Query) any { return q.String("q", "x") }, want: "ada"},
+		{name: "string default", raw: "", get: func(q *Query) any { return q.String("q", "x") }, want: "x"},
+		{name: "bool", raw: "dry_run=1", get: func(q *Query) any { return q.Bool("dry_run", false) }, want: true},
+		{name: "bad bool", raw: "dry_run=yes", get: func(q *Query) any { return q.Bool("dry_run", false) }, want: false, wantErr: true},
+		{name: "duration", raw: "older_than=720h", get: func(q *Query) any { return q.Duration("older_than", time.Hour, 0) }, want: 720 * time.Hour},
+		{name: "duration below min", raw: "older_than=1s", get: func(q *Query) any { return q.Duration("older_than", time.Hour, time.Minute) }, want: time.Hour, wantErr: true},
+		{name: "one of", raw: "status=failed", get: func(q *Query) any { return q.OneOf("status", "", "queued", "failed") }, want: "failed"},
+		{name: "not one of", raw: "status=lost", get: func(q *Query) any { return q.OneOf("status", "", "queued", "failed") }, want: "", wantErr: true},
+		{name: "sort", raw: "sort=name", get: func(q *Query) any { return q.Sort("sort", Sort{Field: "id"}, "id", "name") }, want: Sort{Field: "name"}},
+		{name: "sort descending", raw: "sort=-name", get: func(q *Query) any { return q.Sort("sort", Sort{Field: "id"}, "id", "name") }, want: Sort{Field: "name", Desc: true}},
+		{name: "sort unknown field", raw: "sort=-email", get: func(q *Query) any { return q.Sort("sort", Sort{Field: "id"}, "id", "name") }, want: Sort{Field: "id"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := query(tt.raw)
+			if got := tt.get(q); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if (q.Err() != nil) != tt.wantErr {
+				t.Errorf("Err = %v, want error %v", q.Err(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLists(t *testing.T) {
+	fields := []struct {
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{raw: ""},
+		{raw: "fields=name,%20email,name", want: []string{"name", "email"}},
+		{raw: "fields=email,,age", want: []string{"email", "age"}},
+		{raw: "fields=name,password", wantErr: true},
+		{raw: "fields=,", wantErr: true},
+	}
+	for _, tt := range fields {
+		t.Run("fields "+tt.raw, func(t *testing.T) {
+			q := query(tt.raw)
+			if got := q.Fields("fields", "name", "email", "age"); !slices.Equal(got, tt.want) {
+				t.Errorf("Fields = %v, want %v", got, tt.want)
+			}
+			if (q.Err() != nil) != tt.wantErr {
+				t.Errorf("Err = %v, want error %v", q.Err(), tt.wantErr)
+			}
+		})
+	}
+
+	ids := []struct {
+		raw     string
+		want    []int64
+		wantErr bool
+	}{
+		{raw: ""},
+		{raw: "ids=3,1,3", want: []int64{3, 1}},
+		{raw: "ids=1,2,3", want: []int64{1, 2, 3}},
+		{raw: "ids=1,2,3,4", wantErr: true},
+		{raw: "ids=1,1,1,1,2", want: []int64{1, 2}},
+		{raw: "ids=0", wantErr: true},
+		{raw: "ids=1,x", wantErr: true},
+		{raw: "ids=,", wantErr: true},
+	}
+	for _, tt := range ids {
+		t.Run("ids "+tt.raw, func(t *testing.T) {
+			q := query(tt.raw)
+			if got := q.IDs("ids", 3); !slices.Equal(got, tt.want) {
+				t.Errorf("IDs = %v, want %v", got, tt.want)
+			}
+			if (q.Err() != nil) != tt.wantErr {
+				t.Errorf("Err = %v, want error %v", q.Err(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// Every bad parameter is reported, not just the first
+func TestErrorsCollected(t *testing.T) {
+	q := query("limit=0&dry_run=maybe&status=ok")
+	q.Int("limit", 20, 1, 100)
+	q.Bool("dry_run", false)
+	q.OneOf("status", "", "queued")
+	if !q.Has("status") || q.Has("cursor") {
+		t.Error("Has doesn't match the query string")
+	}
+
+	var errs Errors
+	if !errors.As(q.Err(), &errs) || len(errs) != 3 {
+		t.Fatalf("Err = %v, want three field errors", q.Err())
+	}
+	if got := errs[0].Error(); got != `limit must be between 1 and 100 (got "0")` {
+		t.Errorf("first error = %q", got)
+	}
+	fields := errs.FieldErrors()
+	if len(fields) != 3 || fields[1].Field != "dry_run" || !strings.Contains(fields[1].Message, "true or false") {
+		t.Errorf("FieldErrors = %+v", fields)
+	}
+}
@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/admin/api-keys
+// ---------------------------------------------------------
+// Issues a key for a machine client.
+// 1. Decodes {name, scopes, tenant_id?, expires_at?}; scopes are read,
+// write and admin
+// 2. Binds the key to tenant_id (default tenant when absent); an unknown
+// tenant → 400
+// 3. Generates the key and stores only its hash
+// 4. Returns the record including `key` (shown only here)
+func CreateAPIKey(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, ok := apiKeysFrom(w, store)
+		if !ok {
+			return
+		}
+
+		var req types.APIKey
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("expires_at must be in the future")))
+			return
+		}
+
+		// 🏫 The key reaches this tenant's students only
+		if req.TenantID == 0 {
+			req.TenantID = storage.DefaultTenantID
+		}
+		if !tenantExists(w, store, req.TenantID) {
+			return
+		}
+
+		// 🔑 Only the hash is stored; the plaintext is returned once
+		key := types.APIKey{Name: req.Name, TenantID: req.TenantID, ExpiresAt: req.ExpiresAt}
+		slices.Sort(req.Scopes)
+		key.Scopes = slices.Compact(req.Scopes)
+		plaintext, prefix, hash := apikey.Generate()
+		key.Prefix, key.Hash = prefix, hash
+
+		// 💾 Save key
+		key.ID, err = keys.CreateAPIKey(key)
+		if err != nil {
+			slog.Error("Error creating API key", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if saved, err := keys.GetAPIKeyById(key.ID); err == nil {
+			key = saved
+		}
+		key.Key = plaintext
+
+		slog.Info("Created API key",
+			slog.Int64("id", key.ID),
+			slog.String("name", key.Name),
+			slog.String("prefix", key.Prefix),
+			slog.Any("scopes", key.Scopes),
+			slog.Int64("tenant_id", key.TenantID),
+		)
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusCreated, key)
+	}
+}
+
+// 🧩 GET /api/admin/api-keys
+// ---------------------------------------------------------
+// Lists every key, revoked and expired ones included (no secrets).
+func GetAPIKeys(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, ok := apiKeysFrom(w, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Fetch keys
+		list, err := keys.GetAPIKeys()
+		if err != nil {
+			slog.Error("Error listing API keys", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if list == nil {
+			list = []types.APIKey{}
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
+
+// 🧩 DELETE /api/admin/api-keys/{id}
+// ---------------------------------------------------------
+// Revokes a key at once; the record stays listed with `revoked_at`.
+func RevokeAPIKey(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, ok := apiKeysFrom(w, store)
+		if !ok {
+			return
+		}
+
+		raw := r.PathValue("id")
+
+		// 🔢 Convert id from string → int64
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid API key id %v", raw)))
+			return
+		}
+
+		// 💾 Revoke
+		key, err := keys.RevokeAPIKey(id)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Revoked API key", slog.Int64("id", key.ID), slog.String("prefix", key.Prefix))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      key.ID,
+			"message": "API key revoked successfully",
+		})
+	}
+}
+
+// apiKeysFrom writes a 501 when the backend has no api_keys table
+func apiKeysFrom(w http.ResponseWriter, store storage.Storage) (storage.APIKeyStore, bool) {
+	keys, ok := storage.As[storage.APIKeyStore](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("API keys not supported by this storage backend")))
+	}
+	return keys, ok
+}
+
+// tenantExists answers 400 unless id is a tenant; backends without
+// tenants only have the default one
+func tenantExists(w http.ResponseWriter, store storage.Storage, id int64) bool {
+	tenants, ok := storage.As[storage.TenantStore](store)
+	if !ok {
+		if id == storage.DefaultTenantID {
+			return true
+		}
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("unknown tenant_id %d", id)))
+		return false
+	}
+	all, err := tenants.GetTenants()
+	if err != nil {
+		slog.Error("Error listing tenants", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return false
+	}
+	if !slices.ContainsFunc(all, func(t types.Tenant) bool { return t.ID == id }) {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("unknown tenant_id %d", id)))
+		return false
+	}
+	return true
+}
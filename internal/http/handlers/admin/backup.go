@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// BackupContentType is the media type of an SQLite database file
+const BackupContentType = "application/vnd.sqlite3"
+
+// 🧩 GET /api/admin/backup
+// ---------------------------------------------------------
+// Downloads a consistent copy of the whole database, every tenant included.
+// 1. Writes the backup to a temporary file while the API keeps serving
+// 2. Streams it as student-api-<UTC time>.db and deletes the temporary file
+//
+// Neither step is held to http_server.write_timeout: a big database takes
+// as long as it takes.
+//
+// Restore it with `student-api restore -in <file>` while the API is stopped.
+func Backup(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		backups, ok := storage.As[storage.BackupStore](store)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("backups not supported by this storage backend")))
+			return
+		}
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		dir, err := os.MkdirTemp("", "student-api-backup-")
+		if err != nil {
+			slog.Error("Error creating backup directory", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		defer os.RemoveAll(dir)
+
+		// 💾 Copy the database
+		started := time.Now().UTC()
+		path := filepath.Join(dir, "backup.db")
+		if err := backups.Backup(r.Context(), path); err != nil {
+			slog.Error("Error backing up database", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		slog.Info("💾 Database backed up", slog.Int64("bytes", info.Size()), slog.Duration("took", time.Since(started)))
+
+		// 🚀 Stream the file
+		filename := fmt.Sprintf("student-api-%s.db", started.Format("20060102-150405"))
+		w.Header().Set("Content-Type", BackupContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, file); err != nil {
+			slog.Error("Error sending backup", slog.String("error", err.Error()))
+		}
+	}
+}
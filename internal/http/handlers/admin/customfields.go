@@ -0,0 +1,155 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/custom-fields?tenant=<slug>
+// ---------------------------------------------------------
+// Lists the custom student fields: those from config (source "config")
+// and the tenant's own (source "api").
+func GetCustomFields(store storage.Storage, custom *customfield.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scoped, status, err := scopeFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		// 💾 Config definitions plus stored ones
+		defs, err := custom.Definitions(scoped)
+		if err != nil {
+			slog.Error("Error listing custom fields", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if defs == nil {
+			defs = []types.CustomField{}
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, defs)
+	}
+}
+
+// 🧩 POST /api/admin/custom-fields?tenant=<slug>
+// ---------------------------------------------------------
+// Defines a custom student field for the tenant.
+// 1. Decodes {key, type, required}; key is lower snake_case
+// 2. Refuses (409) a key already defined in config or by the tenant
+// 3. Saves it; create/update bodies are checked against it from now on
+func CreateCustomField(store storage.Storage, custom *customfield.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scoped, fields, ok := customFieldsFrom(w, r, store)
+		if !ok {
+			return
+		}
+
+		var field types.CustomField
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&field)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(field); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+		if !types.ValidCustomFieldKey(field.Key) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("key %q must be lower snake_case, e.g. enrollment_year", field.Key)))
+			return
+		}
+
+		// 🔁 Keys are unique across config and the tenant's own fields
+		defs, err := custom.Definitions(scoped)
+		if err != nil {
+			slog.Error("Error listing custom fields", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if slices.ContainsFunc(defs, func(d types.CustomField) bool { return d.Key == field.Key }) {
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(fmt.Errorf("custom field %s already exists", field.Key)))
+			return
+		}
+
+		// 💾 Save definition
+		if err := fields.CreateCustomField(field); err != nil {
+			slog.Error("Error creating custom field", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		field.Source = customfield.SourceAPI
+
+		slog.Info("Created custom field", slog.String("key", field.Key), slog.String("type", field.Type))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusCreated, field)
+	}
+}
+
+// 🧩 DELETE /api/admin/custom-fields/{key}?tenant=<slug>
+// ---------------------------------------------------------
+// Drops one of the tenant's custom fields. Students keep their stored
+// values, but updates can no longer set it. Config fields are refused (409).
+func DeleteCustomField(store storage.Storage, custom *customfield.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, fields, ok := customFieldsFrom(w, r, store)
+		if !ok {
+			return
+		}
+
+		key := r.PathValue("key")
+		if custom.Static(key) {
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(fmt.Errorf("custom field %s is defined in config", key)))
+			return
+		}
+
+		// 💾 Delete definition
+		if err := fields.DeleteCustomField(key); err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"key":     key,
+			"message": "Custom field deleted successfully",
+		})
+	}
+}
+
+// customFieldsFrom resolves ?tenant and writes a 501 when the backend has no custom field table
+func customFieldsFrom(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.Storage, storage.CustomFieldStore, bool) {
+	scoped, status, err := scopeFromQuery(r, store)
+	if err != nil {
+		response.WriteJson(w, status, response.GeneralError(err))
+		return nil, nil, false
+	}
+
+	fields, ok := storage.As[storage.CustomFieldStore](scoped)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("custom fields not supported by this storage backend")))
+	}
+	return scoped, fields, ok
+}
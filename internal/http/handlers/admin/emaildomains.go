@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/emaildomain"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/validate"
+)
+
+// emailDomainRequest is the body of POST /api/admin/email-domains/migrate
+type emailDomainRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// 🧩 POST /api/admin/email-domains/migrate?tenant=<slug>&dry_run=true
+// ---------------------------------------------------------
+// Moves every student email on one domain to another, e.g. after the
+// school renamed its domain, answering what changed per student. With
+// `dry_run=true` nothing is written; the result says what would change.
+// 1. Decodes {from, to}: bare domains (school.edu); to must pass the
+// validation policy's domain rules (422 otherwise)
+// 2. Scopes to ?tenant=<slug> (default tenant when omitted)
+// 3. In one transaction, students whose new email is taken (trashed ones
+// count) are conflicts and left alone; the others are rewritten, each
+// with a student.updated event and an audit entry
+func MigrateEmailDomain(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := bind.NewQuery(r)
+		dryRun := query.Bool("dry_run", false)
+		slug := query.String("tenant", "")
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+		scoped, status, err := scopeFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		var req emailDomainRequest
+
+		// 🧠 Decode request body JSON → Go struct
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 💾 Rewrite (or preview) in the database
+		migration, err := emaildomain.Migrate(scoped, req.From, req.To, dryRun, types.AuditEntry{
+			Actor:        middleware.Subject(r.Context()),
+			Impersonator: middleware.Impersonator(r.Context()),
+		})
+		switch {
+		case errors.Is(err, emaildomain.ErrUnsupported):
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+			return
+		case errors.Is(err, emaildomain.ErrInvalidDomain):
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		case errors.Is(err, validate.ErrEmailRejected):
+			response.WriteJson(w, http.StatusUnprocessableEntity, response.GeneralError(err))
+			return
+		case err != nil:
+			slog.Error("Error migrating email domain", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		migration.Tenant = slug
+
+		slog.Warn("📧 Migrated email domain",
+			slog.String("from", migration.From),
+			slog.String("to", migration.To),
+			slog.String("tenant", migration.Tenant),
+			slog.Bool("dry_run", migration.DryRun),
+			slog.Int("moved", migration.Moved),
+			slog.Int("conflicts", migration.Conflicts),
+			slog.String("by", middleware.Subject(r.Context())),
+		)
+
+		// 🚀 Send the result
+		response.WriteJson(w, http.StatusOK, migration)
+	}
+}
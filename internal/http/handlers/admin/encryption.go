@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/admin/encryption/reencrypt?async=true
+// ---------------------------------------------------------
+// Re-seals every student's PII under the active encryption key, across all
+// tenants: run it after putting a new key first in encryption.keys (or
+// after enabling encryption on existing data), then drop the old key.
+// With `async=true` it runs as a background job: 202 + job id.
+func Reencrypt(store storage.Storage, queue *jobs.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sealed, ok := storage.As[storage.EncryptionStore](store)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("field encryption not supported by this storage backend")))
+			return
+		}
+
+		query := bind.NewQuery(r)
+		async := query.Bool("async", false)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// ⚙️ Hand off to the job queue
+		if async {
+			id, err := queue.Enqueue(jobs.TypeReencrypt, struct{}{})
+			if errors.Is(err, jobs.ErrDisabled) {
+				response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+				return
+			}
+			if err != nil {
+				slog.Error("Error queueing re-encryption", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+				return
+			}
+			response.WriteJson(w, http.StatusAccepted, map[string]any{
+				"success": true,
+				"job_id":  id,
+				"message": "Re-encryption queued",
+			})
+			return
+		}
+
+		// 🔐 Re-seal stale rows
+		changed, err := sealed.ReencryptStudents()
+		if errors.Is(err, fieldcrypt.ErrDisabled) {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+			return
+		}
+		if err != nil {
+			slog.Error("Error re-encrypting students", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success":     true,
+			"reencrypted": changed,
+		})
+	}
+}
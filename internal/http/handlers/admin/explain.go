@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/paging"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/explain/students?tenant=<slug>&custom.<key>=<value>&verified=&limit=&cursor=
+// ---------------------------------------------------------
+// Shows how the database runs the query GET /api/students makes for the
+// same filters, to decide which indexes to add.
+// 1. Scopes to ?tenant=<slug> (default tenant when omitted)
+// 2. Takes the list's filters: ?custom.<key>=<value> or ?verified=, and
+// ?limit= / ?cursor= for one page (the whole list without them)
+// 3. Returns the SQL and its plan: EXPLAIN QUERY PLAN on sqlite,
+// EXPLAIN (ANALYZE, BUFFERS) on postgres, which runs the query
+func ExplainStudents(store storage.Storage, custom *customfield.Registry, limits *paging.Limits) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scoped, status, err := scopeFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		explain, ok := storage.As[storage.ExplainStore](scoped)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("query plans not supported by this storage backend")))
+			return
+		}
+
+		// 🔎 The list's filters
+		query := bind.NewQuery(r)
+		q := storage.StudentQuery{Limit: query.Int("limit", 0, 1, limits.Get().MaxLimit)}
+		if query.Has("verified") {
+			verified := query.Bool("verified", false)
+			q.Verified = &verified
+		}
+		at, cursorErr := storage.DecodeCursor(query.String("cursor", ""))
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+		if cursorErr != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(cursorErr))
+			return
+		}
+		if at.Before {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("only forward pages can be explained")))
+			return
+		}
+		q.AfterID = at.ID
+
+		if customfield.HasFilter(r.URL.Query()) {
+			if q.Verified != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("verified cannot be combined with custom filters")))
+				return
+			}
+			defs, err := custom.Definitions(scoped)
+			if err != nil {
+				slog.Error("Error loading custom fields", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+				return
+			}
+			if q.Filter, err = customfield.Filter(defs, r.URL.Query()); err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+				return
+			}
+		}
+
+		// 💾 Ask the database for its plan
+		plan, err := explain.ExplainStudents(q)
+		if err != nil {
+			slog.Error("Error explaining students query", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send the plan
+		response.WriteJson(w, http.StatusOK, plan)
+	}
+}
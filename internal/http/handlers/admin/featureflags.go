@@ -0,0 +1,19 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/featureflag"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/feature-flags
+// ---------------------------------------------------------
+// Lists every known feature flag with the rule in force and where it came
+// from (default, config or remote), plus the remote provider's last sync.
+// Change flags in the config file (then SIGHUP) or in the flag service.
+func GetFeatureFlags(flags *featureflag.Set) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, flags.State())
+	}
+}
@@ -0,0 +1,249 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/portal"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/token"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// impersonationRequest is the body of POST /api/admin/impersonations
+type impersonationRequest struct {
+	// Subject is "key:<id>", "user:<sub>" or "student:<id>"
+	Subject string   `json:"subject" validate:"required"`
+	Reason  string   `json:"reason" validate:"required"`
+	Scopes  []string `json:"scopes" validate:"dive,oneof=read write"`
+	// TTL is a duration ("30m"); empty means auth.impersonation.ttl
+	TTL string `json:"ttl"`
+}
+
+// tokenID matches the ids (jti) of our access tokens
+var tokenID = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// 🧩 POST /api/admin/impersonations?tenant=<slug>
+// ---------------------------------------------------------
+// Issues a token acting as a staff member or student, for support
+// debugging.
+// 1. Decodes {subject, reason, scopes?, ttl?}; scopes are read and write
+// (default read), never admin, and an API key's can't exceed its own
+// 2. The key must be usable and the student must exist in the tenant;
+// student tokens need the portal, where they work
+// 3. ttl beyond auth.impersonation.max_ttl → 400
+// 4. Records a student.impersonated audit entry for a student
+// 5. Returns the token (shown only here) with its id and expiry
+//
+// The token works in one tenant: an API key's own, else ?tenant=<slug>
+// (default tenant when omitted). Every request made with the token is
+// logged with the admin who asked for it, and audit entries it causes
+// carry them as the impersonator.
+func CreateImpersonation(store storage.Storage, tokens *token.Issuer, p *portal.Portal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !impersonationEnabled(w, tokens) {
+			return
+		}
+		// 🏫 Students are looked up in the ?tenant= tenant, the one the token is for
+		ctx, status, err := tenantFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+		scoped := tenant.Scope(ctx, store)
+		tenantID := storage.DefaultTenantID
+		picked, fromQuery := tenant.FromContext(ctx)
+		if fromQuery {
+			tenantID = picked.ID
+		}
+
+		var req impersonationRequest
+
+		// 🧠 Decode request body JSON → Go struct
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+		var ttl time.Duration
+		if req.TTL != "" {
+			if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("ttl must be a positive duration, got %q", req.TTL)))
+				return
+			}
+		}
+		if len(req.Scopes) == 0 {
+			req.Scopes = []string{types.ScopeRead}
+		}
+		slices.Sort(req.Scopes)
+		req.Scopes = slices.Compact(req.Scopes)
+
+		// 🎭 Whom the token acts as
+		var studentID int64
+		kind, id, _ := strings.Cut(req.Subject, ":")
+		switch {
+		case kind == "student":
+			studentID, err = strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid student id %q", id)))
+				return
+			}
+			if p == nil {
+				response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("student portal is disabled (set portal.enabled)")))
+				return
+			}
+			if _, err := scoped.GetStudentById(studentID); err != nil {
+				response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+				return
+			}
+			req.Subject = middleware.StudentSubject(studentID)
+		case kind == "key" && quota.ValidSubject(req.Subject):
+			keyID, _ := strconv.ParseInt(id, 10, 64)
+			keys, ok := apiKeysFrom(w, store)
+			if !ok {
+				return
+			}
+			key, err := keys.GetAPIKeyById(keyID)
+			if err != nil {
+				response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+				return
+			}
+			if err := apikey.Check(key, time.Now()); err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+				return
+			}
+			// a key acts in its own tenant only
+			if fromQuery && key.TenantID != tenantID {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("API key %d belongs to another tenant", key.ID)))
+				return
+			}
+			tenantID = key.TenantID
+			for _, scope := range req.Scopes {
+				if !apikey.Allows(key.Scopes, scope) {
+					response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("API key %d lacks the %s scope", key.ID, scope)))
+					return
+				}
+			}
+		case kind == "user" && quota.ValidSubject(req.Subject):
+		default:
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("subject must be key:<id>, user:<sub> or student:<id>, got %q", req.Subject)))
+			return
+		}
+
+		// 🔑 Sign the token; the bootstrap key (and auth off) has no subject
+		impersonator := middleware.Subject(r.Context())
+		if impersonator == "" {
+			impersonator = "admin"
+		}
+		imp, err := tokens.Impersonate(types.Impersonation{
+			Subject:      req.Subject,
+			Impersonator: impersonator,
+			Scopes:       req.Scopes,
+			Reason:       req.Reason,
+		}, tenantID, ttl)
+		if errors.Is(err, token.ErrTooLong) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+		if err != nil {
+			slog.Error("Error issuing impersonation token", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 📜 The student's audit trail shows who looked at their account and why
+		if privacy, ok := storage.As[storage.PrivacyStore](scoped); ok && studentID != 0 {
+			detail, _ := json.Marshal(map[string]any{"reason": imp.Reason, "token_id": imp.ID, "scopes": imp.Scopes, "expires_at": imp.ExpiresAt})
+			if _, err := privacy.CreateAuditEntry(types.AuditEntry{
+				StudentID: studentID,
+				Action:    types.AuditImpersonated,
+				Actor:     impersonator,
+				Detail:    detail,
+			}); err != nil {
+				slog.Error("Error recording impersonation", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+				return
+			}
+		}
+
+		slog.Warn("🎭 Issued impersonation token",
+			slog.String("id", imp.ID),
+			slog.String("impersonator", imp.Impersonator),
+			slog.String("subject", imp.Subject),
+			slog.Any("scopes", imp.Scopes),
+			slog.String("reason", imp.Reason),
+			slog.Time("expires_at", imp.ExpiresAt),
+		)
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusCreated, imp)
+	}
+}
+
+// 🧩 DELETE /api/admin/impersonations/{id}
+// ---------------------------------------------------------
+// Ends an impersonation before it expires: the token with this id is
+// revoked at once.
+func EndImpersonation(tokens *token.Issuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !impersonationEnabled(w, tokens) {
+			return
+		}
+
+		id := r.PathValue("id")
+		if !tokenID.MatchString(id) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid impersonation id %q", id)))
+			return
+		}
+
+		// 💾 Deny-list the token
+		if err := tokens.EndImpersonation(id); err != nil {
+			slog.Error("Error ending impersonation", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Warn("🎭 Ended impersonation", slog.String("id", id), slog.String("by", middleware.Subject(r.Context())))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      id,
+			"message": "Impersonation ended",
+		})
+	}
+}
+
+// impersonationEnabled writes a 501 when bearer tokens are off
+func impersonationEnabled(w http.ResponseWriter, tokens *token.Issuer) bool {
+	if tokens == nil {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("impersonation needs bearer tokens (set auth.token_secret)")))
+		return false
+	}
+	return true
+}
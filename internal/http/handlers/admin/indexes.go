@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/indexes
+// ---------------------------------------------------------
+// Checks that the indexes the student queries rely on exist (the same
+// check logs a warning at startup).
+// 1. Calls `storage.CheckIndexes()`, which reads the database's catalog
+// 2. Returns each wanted index with the one serving it, and the number
+// missing; see GET /api/admin/explain/students for a query's plan
+func Indexes(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		indexes, ok := storage.As[storage.IndexStore](store)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("index checks not supported by this storage backend")))
+			return
+		}
+
+		// 💾 Compare with the catalog
+		report, err := indexes.CheckIndexes()
+		if err != nil {
+			slog.Error("Error checking indexes", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, report)
+	}
+}
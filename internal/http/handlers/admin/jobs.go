@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Job list page size when ?limit is omitted, and its ceiling
+const (
+	defaultJobLimit = 50
+	maxJobLimit     = 500
+)
+
+var jobStatuses = []string{types.JobQueued, types.JobRunning, types.JobSucceeded, types.JobFailed}
+
+// 🧩 GET /api/admin/jobs?status=<status>&limit=<n>
+// ---------------------------------------------------------
+// Lists background jobs, newest first.
+// 1. Optional filter ?status=queued|running|succeeded|failed
+// 2. ?limit (default 50, max 500)
+func GetJobs(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobStore, ok := jobsFrom(w, store)
+		if !ok {
+			return
+		}
+
+		query := bind.NewQuery(r)
+		status := query.OneOf("status", "", jobStatuses...)
+		limit := query.Int("limit", defaultJobLimit, 1, maxJobLimit)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// 💾 Fetch jobs
+		jobs, err := jobStore.GetJobs(status, limit)
+		if err != nil {
+			slog.Error("Error listing jobs", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if jobs == nil {
+			jobs = []types.Job{}
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, jobs)
+	}
+}
+
+// 🧩 GET /api/admin/jobs/{id}
+// ---------------------------------------------------------
+// Returns one job with its status, attempts and last error.
+func GetJobById(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobStore, ok := jobsFrom(w, store)
+		if !ok {
+			return
+		}
+		id, ok := jobIdFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		job, err := jobStore.GetJobById(id)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, job)
+	}
+}
+
+// 🧩 POST /api/admin/jobs/{id}/retry
+// ---------------------------------------------------------
+// Puts a failed job back in the queue with a fresh attempt budget.
+func RetryJob(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobStore, ok := jobsFrom(w, store)
+		if !ok {
+			return
+		}
+		id, ok := jobIdFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		// 💾 Only failed jobs can be retried
+		job, err := jobStore.RequeueJob(id)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Requeued job", slog.Int64("id", id), slog.String("type", job.Type))
+		response.WriteJson(w, http.StatusOK, job)
+	}
+}
+
+// jobsFrom writes a 501 when the backend has no job table
+func jobsFrom(w http.ResponseWriter, store storage.Storage) (storage.JobStore, bool) {
+	jobStore, ok := storage.As[storage.JobStore](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("jobs not supported by this storage backend")))
+	}
+	return jobStore, ok
+}
+
+func jobIdFromPath(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.PathValue("id")
+
+	// 🔢 Convert id from string → int64
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid job id %v", raw)))
+		return 0, false
+	}
+	return id, true
+}
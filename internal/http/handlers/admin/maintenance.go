@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/maintenance"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// maintenanceRequest is the body of PUT /api/admin/maintenance
+type maintenanceRequest struct {
+	Enabled *bool  `json:"enabled" validate:"required"`
+	Message string `json:"message" validate:"max=500"`
+	// RetryAfter is a duration ("10m"); empty means maintenance.retry_after
+	RetryAfter string `json:"retry_after"`
+}
+
+// 🧩 GET /api/admin/maintenance
+// ---------------------------------------------------------
+// Shows whether maintenance mode is on, since when and what clients are told.
+func GetMaintenance(mode *maintenance.Mode) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, mode.State())
+	}
+}
+
+// 🧩 PUT /api/admin/maintenance
+// ---------------------------------------------------------
+// Switches maintenance mode on or off for this instance.
+// 1. Decodes {enabled, message, retry_after}; enabled is required
+// 2. While on, every non-admin request answers 503 with Retry-After
+// 3. Returns the new state
+func SetMaintenance(mode *maintenance.Mode) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req maintenanceRequest
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+		var retryAfter time.Duration
+		if req.RetryAfter != "" {
+			retryAfter, err = time.ParseDuration(req.RetryAfter)
+			if err != nil || retryAfter <= 0 {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("retry_after must be a positive duration such as 10m (got %q)", req.RetryAfter)))
+				return
+			}
+		}
+
+		// 🚧 Switch
+		state := mode.Set(*req.Enabled, req.Message, retryAfter)
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, state)
+	}
+}
@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/trash"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/admin/purge?older_than=<duration>&async=true
+// ---------------------------------------------------------
+// Hard-deletes students soft-deleted longer ago than `older_than`
+// (defaults to the configured trash retention), across all tenants.
+// With `async=true` the purge runs as a background job: 202 + job id.
+func Purge(store storage.Storage, blobs blob.Store, retention time.Duration, queue *jobs.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		trashStore, ok := storage.As[storage.TrashStore](store)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("trash not supported by this storage backend")))
+			return
+		}
+
+		query := bind.NewQuery(r)
+		olderThan := query.Duration("older_than", retention, 0)
+		async := query.Bool("async", false)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// ⚙️ Hand off to the job queue
+		if async {
+			id, err := queue.Enqueue(jobs.TypePurgeTrash, jobs.PurgePayload{OlderThan: olderThan.String()})
+			if errors.Is(err, jobs.ErrDisabled) {
+				response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+				return
+			}
+			if err != nil {
+				slog.Error("Error queueing purge", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+				return
+			}
+			response.WriteJson(w, http.StatusAccepted, map[string]any{
+				"success": true,
+				"job_id":  id,
+				"message": "Purge queued",
+			})
+			return
+		}
+
+		// 💾 Hard delete
+		purged, err := trash.Purge(r.Context(), trashStore, blobs, olderThan)
+		if err != nil {
+			slog.Error("Error purging trash", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success":    true,
+			"purged":     purged,
+			"older_than": olderThan.String(),
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// quotaRequest is the body of PUT /api/admin/quotas/{subject}; both limits
+// are required so a forgotten field can't silently mean "unlimited"
+type quotaRequest struct {
+	DailyRequests *int64 `json:"daily_requests" validate:"required,min=0"`
+	DailyWrites   *int64 `json:"daily_writes" validate:"required,min=0"`
+}
+
+// 🧩 GET /api/admin/quotas
+// ---------------------------------------------------------
+// Lists the subjects with custom limits; everyone else gets quotas.* defaults.
+func GetQuotas(tracker *quota.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !quotasEnabled(w, tracker) {
+			return
+		}
+
+		// 💾 Fetch overrides
+		list, err := tracker.Overrides()
+		if err != nil {
+			slog.Error("Error listing quotas", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if list == nil {
+			list = []types.QuotaLimit{}
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
+
+// 🧩 GET /api/admin/quotas/{subject}
+// ---------------------------------------------------------
+// Shows a subject's effective limits and today's usage.
+// Subjects are key:<api key id> or user:<OIDC sub>.
+func GetQuota(tracker *quota.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subject, ok := quotaSubject(w, r, tracker)
+		if !ok {
+			return
+		}
+
+		q, err := tracker.Get(subject)
+		if err != nil {
+			slog.Error("Error loading quota", slog.String("subject", subject), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, q)
+	}
+}
+
+// 🧩 PUT /api/admin/quotas/{subject}
+// ---------------------------------------------------------
+// Overrides a subject's limits.
+// 1. Decodes {daily_requests, daily_writes}; both required, 0 means unlimited
+// 2. Saves the override; it applies from the next request on
+// 3. Returns the effective quota with today's usage
+func SetQuota(tracker *quota.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subject, ok := quotaSubject(w, r, tracker)
+		if !ok {
+			return
+		}
+
+		var req quotaRequest
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+
+		// 💾 Save override
+		q, err := tracker.Set(types.QuotaLimit{Subject: subject, DailyRequests: *req.DailyRequests, DailyWrites: *req.DailyWrites})
+		if err != nil {
+			slog.Error("Error saving quota", slog.String("subject", subject), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Quota updated",
+			slog.String("subject", subject),
+			slog.Int64("daily_requests", q.DailyRequests),
+			slog.Int64("daily_writes", q.DailyWrites),
+		)
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, q)
+	}
+}
+
+// 🧩 DELETE /api/admin/quotas/{subject}
+// ---------------------------------------------------------
+// Drops a subject's override so the quotas.* defaults apply again.
+func ResetQuota(tracker *quota.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subject, ok := quotaSubject(w, r, tracker)
+		if !ok {
+			return
+		}
+
+		// 💾 Delete override
+		found, err := tracker.Reset(subject)
+		if err != nil {
+			slog.Error("Error resetting quota", slog.String("subject", subject), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if !found {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(fmt.Errorf("no custom quota for %s", subject)))
+			return
+		}
+
+		slog.Info("Quota reset to defaults", slog.String("subject", subject))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"subject": subject,
+			"message": "Quota reset to defaults",
+		})
+	}
+}
+
+// quotasEnabled writes a 501 when quotas are off
+func quotasEnabled(w http.ResponseWriter, tracker *quota.Tracker) bool {
+	if tracker == nil {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("quotas are disabled (quotas.enabled)")))
+		return false
+	}
+	return true
+}
+
+// quotaSubject validates {subject}.
+// Writes the error response itself; ok is false when the handler should stop.
+func quotaSubject(w http.ResponseWriter, r *http.Request, tracker *quota.Tracker) (string, bool) {
+	if !quotasEnabled(w, tracker) {
+		return "", false
+	}
+	subject := r.PathValue("subject")
+	if !quota.ValidSubject(subject) {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(quota.ErrSubject))
+		return "", false
+	}
+	return subject, true
+}
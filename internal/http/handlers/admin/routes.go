@@ -0,0 +1,19 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/routes
+// ---------------------------------------------------------
+// Lists every registered route with what it declared: public (no
+// credentials), the scope it needs, its quota class and timeout. Empty
+// fields are the defaults: scope and quota class by method, no timeout.
+func Routes(reg *registry.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, reg.Routes())
+	}
+}
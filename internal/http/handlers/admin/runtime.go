@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// started is roughly when the process started (package init)
+var started = time.Now().UTC()
+
+// 🧩 GET /api/admin/runtime
+// ---------------------------------------------------------
+// A quick look at the process for production debugging; /debug/pprof/
+// has the full profiles.
+// 1. Goroutine count, CPUs and uptime
+// 2. Memory and GC stats (briefly stops the world, like any ReadMemStats)
+// 3. Connection pool stats of the SQL backends (primary and replicas)
+func Runtime(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		info := types.Runtime{
+			GoVersion:  runtime.Version(),
+			Goroutines: runtime.NumGoroutine(),
+			CPUs:       runtime.NumCPU(),
+			GOMAXPROCS: runtime.GOMAXPROCS(0),
+			StartedAt:  started,
+			Uptime:     time.Since(started).Round(time.Second).String(),
+			Memory: types.RuntimeMemory{
+				Alloc:       mem.Alloc,
+				TotalAlloc:  mem.TotalAlloc,
+				Sys:         mem.Sys,
+				HeapAlloc:   mem.HeapAlloc,
+				HeapInuse:   mem.HeapInuse,
+				HeapObjects: mem.HeapObjects,
+				StackInuse:  mem.StackInuse,
+				NumGC:       mem.NumGC,
+				PauseTotal:  time.Duration(mem.PauseTotalNs).String(),
+			},
+			Pools: map[string]types.PoolStats{},
+		}
+		if mem.LastGC > 0 {
+			last := time.Unix(0, int64(mem.LastGC)).UTC()
+			info.Memory.LastGC = &last
+		}
+		if pools, ok := storage.As[storage.PoolStore](store); ok {
+			info.Pools = pools.PoolStats()
+		}
+
+		response.WriteJson(w, http.StatusOK, info)
+	}
+}
@@ -0,0 +1,18 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/scheduler"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/schedules
+// ---------------------------------------------------------
+// Lists scheduled tasks with their next run, last outcome and
+// run / failure / skipped counters.
+func Schedules(sched *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, sched.Status())
+	}
+}
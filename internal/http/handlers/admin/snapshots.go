@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/snapshot"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+var errSnapshotsDisabled = errors.New("snapshots need the job queue (jobs.enabled)")
+
+// 🧩 POST /api/admin/snapshots?tenant=<slug>
+// ---------------------------------------------------------
+// Queues a snapshot of every student of the tenant (default tenant when omitted).
+// 1. Enqueues a students.snapshot job; its ID is the snapshot ID
+// 2. Answers 202 with Location: /api/admin/snapshots/{id} to poll
+func CreateSnapshot(store storage.Storage, snapshots *snapshot.Snapshotter, links *links.Builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, status, err := tenantFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		// ⚙️ Hand off to the job queue
+		snap, err := snapshots.Start(ctx)
+		if errors.Is(err, jobs.ErrDisabled) {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errSnapshotsDisabled))
+			return
+		}
+		if err != nil {
+			slog.Error("Error queueing snapshot", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Queued snapshot", slog.Int64("id", snap.ID))
+
+		// 🚀 Send response
+		w.Header().Set("Location", links.Href(fmt.Sprintf("/api/admin/snapshots/%d", snap.ID), r.URL.Query()))
+		response.WriteJson(w, http.StatusAccepted, snap)
+	}
+}
+
+// 🧩 GET /api/admin/snapshots?tenant=<slug>
+// ---------------------------------------------------------
+// Lists the tenant's snapshots, newest first (among the latest 1000 jobs).
+func GetSnapshots(store storage.Storage, snapshots *snapshot.Snapshotter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, status, err := tenantFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		list, err := snapshots.List(ctx)
+		if errors.Is(err, jobs.ErrDisabled) {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errSnapshotsDisabled))
+			return
+		}
+		if err != nil {
+			slog.Error("Error listing snapshots", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
+
+// 🧩 GET /api/admin/snapshots/{id}?tenant=<slug>
+// ---------------------------------------------------------
+// Reports a snapshot's status: queued, running, succeeded or failed.
+func GetSnapshotById(store storage.Storage, snapshots *snapshot.Snapshotter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, status, err := tenantFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+		id, ok := snapshotID(w, r, "id")
+		if !ok {
+			return
+		}
+
+		snap, err := snapshots.Get(ctx, id)
+		if errors.Is(err, jobs.ErrDisabled) {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errSnapshotsDisabled))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, snap)
+	}
+}
+
+// 🧩 GET /api/admin/snapshots/{a}/{b}/diff?tenant=<slug>
+// ---------------------------------------------------------
+// Compares two snapshots of the tenant, matching students by id.
+//  1. Both must have succeeded (409 otherwise)
+//  2. Lists students added and removed from a to b, and the changed ones
+//     with the fields that differ and both versions
+func DiffSnapshots(store storage.Storage, snapshots *snapshot.Snapshotter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, status, err := tenantFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+		a, ok := snapshotID(w, r, "a")
+		if !ok {
+			return
+		}
+		b, ok := snapshotID(w, r, "b")
+		if !ok {
+			return
+		}
+
+		diff, err := snapshots.Diff(ctx, a, b)
+		switch {
+		case errors.Is(err, jobs.ErrDisabled):
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errSnapshotsDisabled))
+			return
+		case errors.Is(err, snapshot.ErrNotFound):
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		case errors.Is(err, snapshot.ErrNotReady):
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(err))
+			return
+		case err != nil:
+			slog.Error("Error diffing snapshots", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Diffed snapshots",
+			slog.Int64("from", a),
+			slog.Int64("to", b),
+			slog.Int("added", len(diff.Added)),
+			slog.Int("removed", len(diff.Removed)),
+			slog.Int("changed", len(diff.Changed)),
+		)
+		response.WriteJson(w, http.StatusOK, diff)
+	}
+}
+
+// snapshotID parses the {name} path value. Writes the error response
+// itself; ok is false when the handler should stop.
+func snapshotID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	raw := r.PathValue(name)
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid snapshot id %v", raw)))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/stats?tenant=<slug>&tz=<timezone>
+// ---------------------------------------------------------
+// Aggregate counts for dashboards, computed by the backend in SQL.
+// 1. Scopes to ?tenant=<slug> (default tenant when omitted)
+// 2. Calls `storage.GetStats()` with ?tz (an IANA name), else display
+// (time.display_timezone)
+// 3. Returns totals, age histogram and creations per day (last 30 days)
+func Stats(store storage.Storage, display *time.Location) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scoped, status, err := scopeFromQuery(r, store)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		loc := display
+		if name := bind.NewQuery(r).String("tz", ""); name != "" {
+			if loc, err = clock.Zone(name); err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("unknown timezone %q (use an IANA name like Europe/Berlin)", name)))
+				return
+			}
+		}
+
+		statsStore, ok := storage.As[storage.StatsStore](scoped)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("stats not supported by this storage backend")))
+			return
+		}
+
+		// 💾 Aggregate in the database
+		stats, err := statsStore.GetStats(loc)
+		if err != nil {
+			slog.Error("Error computing stats", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, stats)
+	}
+}
+
+// -------------------------------------------------------------
+// scopeFromQuery() → Admin routes pick their tenant via ?tenant=<slug>
+// -------------------------------------------------------------
+func scopeFromQuery(r *http.Request, store storage.Storage) (storage.Storage, int, error) {
+	ctx, status, err := tenantFromQuery(r, store)
+	if err != nil {
+		return nil, status, err
+	}
+	if _, ok := tenant.FromContext(ctx); !ok {
+		return store, http.StatusOK, nil
+	}
+	return tenant.Scope(ctx, store), http.StatusOK, nil
+}
+
+// tenantFromQuery is scopeFromQuery for code that takes the tenant from
+// the context: the request's context, carrying the ?tenant=<slug> tenant
+func tenantFromQuery(r *http.Request, store storage.Storage) (context.Context, int, error) {
+	slug := bind.NewQuery(r).String("tenant", "")
+	if slug == "" {
+		return r.Context(), http.StatusOK, nil
+	}
+
+	tenants, ok := storage.As[storage.TenantStore](store)
+	if !ok {
+		return nil, http.StatusBadRequest, errors.New("storage backend does not support tenants")
+	}
+	t, err := tenants.GetTenantBySlug(slug)
+	if err != nil {
+		return nil, http.StatusNotFound, fmt.Errorf("unknown tenant %q", slug)
+	}
+	return tenant.WithTenant(r.Context(), t), http.StatusOK, nil
+}
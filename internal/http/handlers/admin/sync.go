@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/roster"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/admin/sync
+// ---------------------------------------------------------
+// Lists the roster sync connectors (sync.connectors) with the report of
+// their last run.
+func GetSyncConnectors(syncer *roster.Syncer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, syncer.Connectors())
+	}
+}
+
+// 🧩 POST /api/admin/sync/{name}?dry_run=true
+// ---------------------------------------------------------
+// Pulls the connector's roster and applies the creates and updates it
+// implies, answering the report. With `dry_run=true` nothing is written;
+// the report says what would change.
+// 1. 404 for an unknown connector, 409 while it is already running
+// 2. 502 when the roster can't be fetched (nothing changes)
+func RunSync(syncer *roster.Syncer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := bind.NewQuery(r)
+		dryRun := query.Bool("dry_run", false)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// 🔄 A client hanging up must not stop a sync halfway
+		report, err := syncer.Run(context.WithoutCancel(r.Context()), r.PathValue("name"), dryRun)
+		switch {
+		case errors.Is(err, roster.ErrUnknownConnector):
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		case errors.Is(err, roster.ErrRunning):
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(err))
+			return
+		case err != nil:
+			slog.Error("Error syncing roster", slog.String("connector", report.Connector), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusBadGateway, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send the report
+		response.WriteJson(w, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,223 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/webhook"
+)
+
+// Delivery history page size when ?limit is omitted, and its ceiling
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// 🧩 POST /api/admin/webhooks?tenant=<slug>
+// ---------------------------------------------------------
+// Subscribes a URL to student lifecycle events.
+// 1. Decodes {url, events, secret?}; events may contain "*"
+// 2. Generates a signing secret when none is given
+// 3. Returns the webhook including its secret (shown only here)
+func CreateWebhook(store storage.Storage, dispatcher *webhook.Dispatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks, ok := webhooksFrom(w, r, store)
+		if !ok {
+			return
+		}
+		if dispatcher == nil {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("webhooks need the job queue (jobs.enabled)")))
+			return
+		}
+
+		var hook types.Webhook
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&hook)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(hook); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+		if hook.Secret == "" {
+			hook.Secret = webhook.NewSecret()
+		}
+
+		// 💾 Save subscription
+		hook.ID, err = hooks.CreateWebhook(hook)
+		if err != nil {
+			slog.Error("Error creating webhook", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if saved, err := hooks.GetWebhookById(hook.ID); err == nil {
+			hook.CreatedAt = saved.CreatedAt
+		}
+
+		slog.Info("Created webhook", slog.Int64("id", hook.ID), slog.String("url", hook.URL))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusCreated, hook)
+	}
+}
+
+// 🧩 GET /api/admin/webhooks?tenant=<slug>
+// ---------------------------------------------------------
+// Lists the tenant's webhooks (secrets omitted).
+func GetWebhooks(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks, ok := webhooksFrom(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Fetch subscriptions
+		list, err := hooks.GetWebhooks()
+		if err != nil {
+			slog.Error("Error listing webhooks", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if list == nil {
+			list = []types.Webhook{}
+		}
+		for i := range list {
+			list[i].Secret = ""
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
+
+// 🧩 GET /api/admin/webhooks/{id}?tenant=<slug>
+// ---------------------------------------------------------
+// Returns one webhook (secret omitted).
+func GetWebhookById(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, hook, ok := webhookFromPath(w, r, store)
+		if !ok {
+			return
+		}
+
+		hook.Secret = ""
+		response.WriteJson(w, http.StatusOK, hook)
+	}
+}
+
+// 🧩 DELETE /api/admin/webhooks/{id}?tenant=<slug>
+// ---------------------------------------------------------
+// Unsubscribes; pending deliveries are dropped with the history.
+func DeleteWebhookById(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks, hook, ok := webhookFromPath(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Delete subscription and history
+		if err := hooks.DeleteWebhookById(hook.ID); err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      hook.ID,
+			"message": "Webhook deleted successfully",
+		})
+	}
+}
+
+// 🧩 GET /api/admin/webhooks/{id}/deliveries?limit=<n>&tenant=<slug>
+// ---------------------------------------------------------
+// Delivery history, newest first: status, attempts, last HTTP status
+// and error. `?limit` defaults to 50 (max 500).
+func GetWebhookDeliveries(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks, hook, ok := webhookFromPath(w, r, store)
+		if !ok {
+			return
+		}
+
+		query := bind.NewQuery(r)
+		limit := query.Int("limit", defaultDeliveryLimit, 1, maxDeliveryLimit)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// 💾 Fetch history
+		deliveries, err := hooks.GetWebhookDeliveries(hook.ID, limit)
+		if err != nil {
+			slog.Error("Error listing webhook deliveries", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if deliveries == nil {
+			deliveries = []types.WebhookDelivery{}
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, deliveries)
+	}
+}
+
+// webhooksFrom resolves ?tenant and writes a 501 when the backend has no webhook tables
+func webhooksFrom(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.WebhookStore, bool) {
+	scoped, status, err := scopeFromQuery(r, store)
+	if err != nil {
+		response.WriteJson(w, status, response.GeneralError(err))
+		return nil, false
+	}
+
+	hooks, ok := storage.As[storage.WebhookStore](scoped)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("webhooks not supported by this storage backend")))
+	}
+	return hooks, ok
+}
+
+// webhookFromPath resolves {id} to a webhook of the selected tenant
+func webhookFromPath(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.WebhookStore, types.Webhook, bool) {
+	hooks, ok := webhooksFrom(w, r, store)
+	if !ok {
+		return nil, types.Webhook{}, false
+	}
+
+	raw := r.PathValue("id")
+
+	// 🔢 Convert id from string → int64
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid webhook id %v", raw)))
+		return nil, types.Webhook{}, false
+	}
+
+	hook, err := hooks.GetWebhookById(id)
+	if err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return nil, types.Webhook{}, false
+	}
+	return hooks, hook, true
+}
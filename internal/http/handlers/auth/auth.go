@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/token"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// refreshRequest is the body of /api/auth/refresh
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// 🧩 POST /api/auth/token
+// ---------------------------------------------------------
+// Exchanges the X-API-Key header for an access + refresh token pair.
+// The bootstrap key can't start sessions: create a stored key first.
+func Token(store storage.Storage, tokens *token.Issuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !enabled(w, tokens) {
+			return
+		}
+		keys := store.(storage.APIKeyStore)
+
+		presented := r.Header.Get(apikey.Header)
+		if presented == "" {
+			response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(fmt.Errorf("missing API key (set the %s header)", apikey.Header)))
+			return
+		}
+		key, err := keys.GetAPIKeyByHash(apikey.Hash(presented))
+		if err != nil {
+			response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(errors.New("invalid API key")))
+			return
+		}
+
+		// 🔑 Start a session
+		pair, err := tokens.Issue(key)
+		if err != nil {
+			writeTokenError(w, err)
+			return
+		}
+
+		slog.Info("Issued access token", slog.Int64("key_id", key.ID))
+		response.WriteJson(w, http.StatusOK, pair)
+	}
+}
+
+// 🧩 POST /api/auth/refresh
+// ---------------------------------------------------------
+// Trades {refresh_token} for a new pair; the old refresh token is used up.
+// Replaying a used refresh token revokes the whole session.
+func Refresh(tokens *token.Issuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !enabled(w, tokens) {
+			return
+		}
+
+		var req refreshRequest
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) || (err == nil && req.RefreshToken == "") {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("refresh_token is required")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🔄 Rotate
+		pair, err := tokens.Refresh(req.RefreshToken)
+		if err != nil {
+			if errors.Is(err, token.ErrReplayed) {
+				slog.Warn("Refresh token replayed; session revoked")
+			}
+			writeTokenError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, pair)
+	}
+}
+
+// 🧩 POST /api/auth/logout
+// ---------------------------------------------------------
+// Revokes the bearer access token and every refresh token of its session.
+func Logout(tokens *token.Issuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !enabled(w, tokens) {
+			return
+		}
+
+		bearer, ok := middleware.BearerToken(r)
+		if !ok {
+			response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(errors.New("missing bearer token")))
+			return
+		}
+		claims, err := tokens.Verify(bearer)
+		if err != nil {
+			writeTokenError(w, err)
+			return
+		}
+
+		// 💾 Deny-list the access token, revoke the refresh chain
+		if err := tokens.Logout(claims); err != nil {
+			slog.Error("Error revoking tokens", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Logged out", slog.Int64("key_id", claims.KeyID))
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"message": "Logged out successfully",
+		})
+	}
+}
+
+// enabled writes a 501 when no token secret is configured
+func enabled(w http.ResponseWriter, tokens *token.Issuer) bool {
+	if tokens == nil {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("tokens are disabled (set auth.token_secret)")))
+		return false
+	}
+	return true
+}
+
+// writeTokenError answers 401 for credential problems, 500 for the rest
+func writeTokenError(w http.ResponseWriter, err error) {
+	for _, unauthorized := range []error{token.ErrInvalid, token.ErrExpired, token.ErrRevoked, token.ErrReplayed, apikey.ErrRevoked, apikey.ErrExpired} {
+		if errors.Is(err, unauthorized) {
+			response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(err))
+			return
+		}
+	}
+	slog.Error("Error issuing tokens", slog.String("error", err.Error()))
+	response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+}
@@ -0,0 +1,27 @@
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+)
+
+// 🧩 GET /debug/pprof/, /debug/vars
+// ---------------------------------------------------------
+// Go's profiler and expvar on their own mux, so nothing leaks through
+// http.DefaultServeMux. Mounted behind auth (admin scope) on the API port,
+// or alone on debug.addr.
+//
+//	curl -H "X-API-Key: $KEY" -o cpu.out "http://host/debug/pprof/profile?seconds=30"
+//	go tool pprof cpu.out
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	return mux
+}
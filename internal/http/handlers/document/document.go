@@ -0,0 +1,294 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Document types we accept, detected from the bytes (never the client's header)
+var documentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+}
+
+// Uploads bigger than this are spooled to a temp file instead of memory
+const maxMemory = 1 << 20
+
+// 🧩 POST /api/student/{id}/documents
+// ---------------------------------------------------------
+// Attaches a file (transcript, ID scan, ...) to a student.
+// 1. Checks the student exists in the caller's tenant
+// 2. Parses multipart fields `kind` and `file` (at most maxBytes)
+// 3. Sniffs the type: pdf, jpeg, png or webp only
+// 4. Stores the bytes via `blob.Store.Put()`, then the record via `CreateDocument()`
+func New(store storage.Storage, blobs blob.Store, maxBytes int64, expiry time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docs, studentID, ok := scope(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 🧠 Parse the form; large files are spooled to disk by net/http
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes+maxMemory)
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.WriteJson(w, http.StatusRequestEntityTooLarge, response.GeneralError(fmt.Errorf("document exceeds %d bytes", maxBytes)))
+				return
+			}
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("expected multipart/form-data with kind and file fields: %v", err)))
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("missing file field")))
+			return
+		}
+		defer file.Close()
+
+		if header.Size > maxBytes {
+			response.WriteJson(w, http.StatusRequestEntityTooLarge, response.GeneralError(fmt.Errorf("document exceeds %d bytes", maxBytes)))
+			return
+		}
+
+		doc := types.Document{
+			StudentID: studentID,
+			Kind:      r.FormValue("kind"),
+			Filename:  filepath.Base(header.Filename),
+			Size:      header.Size,
+			BlobKey:   fmt.Sprintf("students/%d/documents/%s", studentID, uuid.NewString()),
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(doc); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+
+		// 🔍 Sniff the content type, then rewind
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(file, head)
+		doc.ContentType = http.DetectContentType(head[:n])
+		if !documentTypes[doc.ContentType] {
+			response.WriteJson(w, http.StatusUnsupportedMediaType, response.GeneralError(fmt.Errorf("unsupported document type %s (use pdf, jpeg, png or webp)", doc.ContentType)))
+			return
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 💾 Bytes first, then the record; undo the blob if the insert fails
+		if err := blobs.Put(r.Context(), doc.BlobKey, file, doc.Size, doc.ContentType); err != nil {
+			slog.Error("Error storing document", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		doc.ID, err = docs.CreateDocument(doc)
+		if err != nil {
+			if delErr := blobs.Delete(r.Context(), doc.BlobKey); delErr != nil {
+				slog.Error("Error removing orphaned document blob", slog.String("key", doc.BlobKey), slog.String("error", delErr.Error()))
+			}
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		doc.CreatedAt = clock.Now()
+
+		slog.Info("Stored student document",
+			slog.Int64("student_id", studentID),
+			slog.Int64("id", doc.ID),
+			slog.String("kind", doc.Kind),
+			slog.Int64("bytes", doc.Size),
+		)
+
+		// 🚀 Send response
+		withURL(&doc, blobs, expiry)
+		response.WriteJson(w, http.StatusCreated, doc)
+	}
+}
+
+// 🧩 GET /api/student/{id}/documents
+// ---------------------------------------------------------
+// Lists a student's documents; each carries a download `url`
+// (presigned when the blob store supports it).
+func GetList(store storage.Storage, blobs blob.Store, expiry time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docs, studentID, ok := scope(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Fetch records
+		list, err := docs.GetDocuments(studentID)
+		if err != nil {
+			slog.Error("Error getting documents", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if list == nil {
+			list = []types.Document{}
+		}
+		for i := range list {
+			withURL(&list[i], blobs, expiry)
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
+
+// 🧩 GET /api/student/{id}/documents/{docId}
+// ---------------------------------------------------------
+// Returns one document's metadata with a fresh download `url`.
+func GetById(store storage.Storage, blobs blob.Store, expiry time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, doc, ok := documentFromPath(w, r, store)
+		if !ok {
+			return
+		}
+
+		withURL(&doc, blobs, expiry)
+		response.WriteJson(w, http.StatusOK, doc)
+	}
+}
+
+// 🧩 GET /api/student/{id}/documents/{docId}/content
+// ---------------------------------------------------------
+// Streams the file through the API (used when the store can't presign).
+func Download(store storage.Storage, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, doc, ok := documentFromPath(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Fetch from the blob store
+		body, _, err := blobs.Get(r.Context(), doc.BlobKey)
+		if err != nil {
+			slog.Error("Error reading document", slog.String("key", doc.BlobKey), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		defer body.Close()
+
+		// 🚀 Stream the file
+		w.Header().Set("Content-Type", doc.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(doc.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": doc.Filename}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, body); err != nil {
+			slog.Error("Error sending document", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// 🧩 DELETE /api/student/{id}/documents/{docId}
+// ---------------------------------------------------------
+// Removes the record, then the file.
+func DeleteById(store storage.Storage, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docs, doc, ok := documentFromPath(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Record first: a leftover blob is harmless, a dangling record is not
+		if err := docs.DeleteDocumentById(doc.StudentID, doc.ID); err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+		if err := blobs.Delete(r.Context(), doc.BlobKey); err != nil {
+			slog.Error("Error deleting document blob", slog.String("key", doc.BlobKey), slog.String("error", err.Error()))
+		}
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      doc.ID,
+			"message": "Document deleted successfully",
+		})
+	}
+}
+
+// -------------------------------------------------------------
+// scope() → Tenant-scoped DocumentStore + the {id} student, checked to exist
+// -------------------------------------------------------------
+// Writes the error response itself; ok is false when the handler should stop.
+func scope(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.DocumentStore, int64, bool) {
+	// 🏫 Restrict every query to the caller's tenant
+	scoped := tenant.Scope(r.Context(), store)
+
+	docs, ok := storage.As[storage.DocumentStore](scoped)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("documents not supported by this storage backend")))
+		return nil, 0, false
+	}
+
+	// 🔢 Convert id from string → int64
+	id := r.PathValue("id")
+	studentID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+		return nil, 0, false
+	}
+	if _, err := scoped.GetStudentById(studentID); err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return nil, 0, false
+	}
+	return docs, studentID, true
+}
+
+// documentFromPath resolves {id}/{docId} to a record of the caller's tenant
+func documentFromPath(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.DocumentStore, types.Document, bool) {
+	docs, studentID, ok := scope(w, r, store)
+	if !ok {
+		return nil, types.Document{}, false
+	}
+
+	raw := r.PathValue("docId")
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid document id %v", raw)))
+		return nil, types.Document{}, false
+	}
+
+	doc, err := docs.GetDocumentById(studentID, id)
+	if err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return nil, types.Document{}, false
+	}
+	return docs, doc, true
+}
+
+// withURL sets doc.URL: a presigned link when the blob store can make one,
+// otherwise the API's own download route.
+func withURL(doc *types.Document, blobs blob.Store, expiry time.Duration) {
+	if presigner, ok := blobs.(blob.Presigner); ok {
+		if url, err := presigner.PresignGet(doc.BlobKey, expiry); err == nil {
+			doc.URL = url
+			return
+		}
+	}
+	doc.URL = fmt.Sprintf("/api/student/%d/documents/%d/content", doc.StudentID, doc.ID)
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/export"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/exports?format=csv|json
+// ---------------------------------------------------------
+// Queues an export of every student in the caller's tenant.
+// 1. ?format picks csv (default) or json
+// 2. Enqueues a students.export job; its ID is the export ID
+// 3. Answers 202 with Location: /api/exports/{id} to poll
+func New(exporter *export.Exporter, links *links.Builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := bind.NewQuery(r)
+		format := query.OneOf("format", export.FormatCSV, export.Formats...)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// ⚙️ Hand off to the job queue
+		exp, err := exporter.Start(r.Context(), format)
+		if errors.Is(err, jobs.ErrDisabled) {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("exports need the job queue (jobs.enabled)")))
+			return
+		}
+		if err != nil {
+			slog.Error("Error queueing export", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Queued export", slog.Int64("id", exp.ID), slog.String("format", format))
+
+		// 🚀 Send response
+		w.Header().Set("Location", links.Href(fmt.Sprintf("/api/exports/%d", exp.ID), nil))
+		response.WriteJson(w, http.StatusAccepted, exp)
+	}
+}
+
+// 🧩 GET /api/exports/{id}
+// ---------------------------------------------------------
+// Reports an export's status; once it has succeeded the response carries a
+// download `url` (presigned when the blob store supports it).
+func GetById(exporter *export.Exporter, links *links.Builder, expiry time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		exp, ok := exportFromPath(w, r, exporter)
+		if !ok {
+			return
+		}
+
+		if exp.Status == types.JobSucceeded {
+			if url, ok := exporter.PresignURL(r.Context(), exp, expiry); ok {
+				exp.URL = url
+			} else {
+				exp.URL = links.Href(fmt.Sprintf("/api/exports/%d/download", exp.ID), nil)
+			}
+		}
+
+		response.WriteJson(w, http.StatusOK, exp)
+	}
+}
+
+// 🧩 GET /api/exports/{id}/download
+// ---------------------------------------------------------
+// Streams a finished export through the API (used when the store can't presign).
+func Download(exporter *export.Exporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		exp, ok := exportFromPath(w, r, exporter)
+		if !ok {
+			return
+		}
+		if exp.Status != types.JobSucceeded {
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(fmt.Errorf("export %d is %s, not ready for download", exp.ID, exp.Status)))
+			return
+		}
+
+		// 💾 Fetch from the blob store
+		body, obj, err := exporter.Open(r.Context(), exp)
+		if errors.Is(err, blob.ErrNotFound) {
+			response.WriteJson(w, http.StatusGone, response.GeneralError(fmt.Errorf("export %d file is no longer available", exp.ID)))
+			return
+		}
+		if err != nil {
+			slog.Error("Error reading export", slog.Int64("id", exp.ID), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		defer body.Close()
+
+		// 🚀 Stream the file
+		filename := fmt.Sprintf("students-%d.%s", exp.ID, exp.Format)
+		w.Header().Set("Content-Type", export.ContentType(exp.Format))
+		if obj.Size > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(obj.Size, 10))
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		// a whole roster may outlast http_server.write_timeout on a slow link
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		if _, err := io.Copy(w, body); err != nil {
+			slog.Error("Error sending export", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// exportFromPath resolves {id} to an export of the caller's tenant.
+// Writes the error response itself; ok is false when the handler should stop.
+func exportFromPath(w http.ResponseWriter, r *http.Request, exporter *export.Exporter) (types.Export, bool) {
+	raw := r.PathValue("id")
+
+	// 🔢 Convert id from string → int64
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid export id %v", raw)))
+		return types.Export{}, false
+	}
+
+	exp, err := exporter.Get(r.Context(), id)
+	if errors.Is(err, jobs.ErrDisabled) {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("exports need the job queue (jobs.enabled)")))
+		return types.Export{}, false
+	}
+	if err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return types.Export{}, false
+	}
+	return exp, true
+}
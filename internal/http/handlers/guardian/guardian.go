@@ -0,0 +1,337 @@
+package guardian
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/guardians
+// ---------------------------------------------------------
+// Creates a guardian: {"name": "...", "email": "...", "phone": "+14155550123"}.
+// Link them to students with PUT /api/student/{id}/guardians/{guardianId}.
+func New(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, ok := guardianStore(w, r, store)
+		if !ok {
+			return
+		}
+		g, ok := decode[types.Guardian](w, r)
+		if !ok {
+			return
+		}
+
+		// 💾 Save, then read back what was stored
+		id, err := guardians.CreateGuardian(g)
+		if err != nil {
+			slog.Error("Error creating guardian", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		g, err = guardians.GetGuardianById(id)
+		if err != nil {
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Created guardian", slog.Int64("id", id))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusCreated, g)
+	}
+}
+
+// 🧩 GET /api/guardians
+// ---------------------------------------------------------
+// Lists the tenant's guardians by name.
+func GetList(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, ok := guardianStore(w, r, store)
+		if !ok {
+			return
+		}
+
+		list, err := guardians.GetGuardians()
+		if err != nil {
+			slog.Error("Error getting guardians", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if list == nil {
+			list = []types.Guardian{}
+		}
+
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
+
+// 🧩 GET /api/guardians/{id}
+// ---------------------------------------------------------
+// Returns a guardian with the students they are linked to.
+func GetById(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, g, ok := guardianFromPath(w, r, store, "id")
+		if !ok {
+			return
+		}
+
+		students, err := guardians.GetGuardianStudents(g.ID)
+		if err != nil {
+			slog.Error("Error getting guardian students", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		g.Students = students
+		if g.Students == nil {
+			g.Students = []types.GuardianLink{}
+		}
+
+		response.WriteJson(w, http.StatusOK, g)
+	}
+}
+
+// 🧩 PUT /api/guardians/{id}
+// ---------------------------------------------------------
+// Replaces a guardian's name, email and phone.
+func UpdateById(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, ok := guardianStore(w, r, store)
+		if !ok {
+			return
+		}
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		g, ok := decode[types.Guardian](w, r)
+		if !ok {
+			return
+		}
+
+		updated, err := guardians.UpdateGuardianById(id, g)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Updated guardian", slog.Int64("id", id))
+		response.WriteJson(w, http.StatusOK, updated)
+	}
+}
+
+// 🧩 DELETE /api/guardians/{id}
+// ---------------------------------------------------------
+// Deletes a guardian and unlinks them from every student.
+func DeleteById(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, ok := guardianStore(w, r, store)
+		if !ok {
+			return
+		}
+		id, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+
+		if err := guardians.DeleteGuardianById(id); err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Deleted guardian", slog.Int64("id", id))
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      id,
+			"message": "Guardian deleted successfully",
+		})
+	}
+}
+
+// 🧩 GET /api/student/{id}/guardians
+// ---------------------------------------------------------
+// Lists a student's guardians by name, each with its relationship.
+func GetForStudent(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, studentID, ok := studentScope(w, r, store)
+		if !ok {
+			return
+		}
+
+		byStudent, err := guardians.GetStudentGuardians([]int64{studentID})
+		if err != nil {
+			slog.Error("Error getting student guardians", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		list := byStudent[studentID]
+		if list == nil {
+			list = []types.StudentGuardian{}
+		}
+
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
+
+// 🧩 PUT /api/student/{id}/guardians/{guardianId}
+// ---------------------------------------------------------
+// Links a guardian to a student: {"relationship": "mother"}. Linking again
+// only changes the relationship.
+func Link(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, studentID, ok := studentScope(w, r, store)
+		if !ok {
+			return
+		}
+		guardianID, ok := pathID(w, r, "guardianId")
+		if !ok {
+			return
+		}
+		link, ok := decode[types.GuardianRelationship](w, r)
+		if !ok {
+			return
+		}
+
+		if _, err := guardians.GetGuardianById(guardianID); err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+		if err := guardians.LinkGuardian(guardianID, studentID, link.Relationship); err != nil {
+			slog.Error("Error linking guardian", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Linked guardian",
+			slog.Int64("student_id", studentID),
+			slog.Int64("guardian_id", guardianID),
+			slog.String("relationship", link.Relationship),
+		)
+		response.WriteJson(w, http.StatusOK, types.GuardianLink{StudentID: studentID, Relationship: link.Relationship})
+	}
+}
+
+// 🧩 DELETE /api/student/{id}/guardians/{guardianId}
+// ---------------------------------------------------------
+// Unlinks a guardian from a student; the guardian itself stays.
+func Unlink(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guardians, studentID, ok := studentScope(w, r, store)
+		if !ok {
+			return
+		}
+		guardianID, ok := pathID(w, r, "guardianId")
+		if !ok {
+			return
+		}
+
+		unlinked, err := guardians.UnlinkGuardian(guardianID, studentID)
+		if err != nil {
+			slog.Error("Error unlinking guardian", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if !unlinked {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(fmt.Errorf("guardian %d is not linked to student %d", guardianID, studentID)))
+			return
+		}
+
+		slog.Info("Unlinked guardian", slog.Int64("student_id", studentID), slog.Int64("guardian_id", guardianID))
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"message": "Guardian unlinked successfully",
+		})
+	}
+}
+
+// -------------------------------------------------------------
+// guardianStore() → Tenant-scoped GuardianStore
+// -------------------------------------------------------------
+// Writes the error response itself; ok is false when the handler should stop.
+func guardianStore(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.GuardianStore, bool) {
+	// 🏫 Restrict every query to the caller's tenant
+	guardians, ok := storage.As[storage.GuardianStore](tenant.Scope(r.Context(), store))
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("guardians not supported by this storage backend")))
+		return nil, false
+	}
+	return guardians, true
+}
+
+// studentScope is guardianStore plus the {id} student, checked to exist
+func studentScope(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.GuardianStore, int64, bool) {
+	guardians, ok := guardianStore(w, r, store)
+	if !ok {
+		return nil, 0, false
+	}
+	studentID, ok := pathID(w, r, "id")
+	if !ok {
+		return nil, 0, false
+	}
+	if _, err := tenant.Scope(r.Context(), store).GetStudentById(studentID); err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return nil, 0, false
+	}
+	return guardians, studentID, true
+}
+
+// guardianFromPath resolves the {name} path value to a guardian of the caller's tenant
+func guardianFromPath(w http.ResponseWriter, r *http.Request, store storage.Storage, name string) (storage.GuardianStore, types.Guardian, bool) {
+	guardians, ok := guardianStore(w, r, store)
+	if !ok {
+		return nil, types.Guardian{}, false
+	}
+	id, ok := pathID(w, r, name)
+	if !ok {
+		return nil, types.Guardian{}, false
+	}
+	g, err := guardians.GetGuardianById(id)
+	if err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return nil, types.Guardian{}, false
+	}
+	return guardians, g, true
+}
+
+// pathID parses the {name} path value
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	raw := r.PathValue(name)
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", raw)))
+		return 0, false
+	}
+	return id, true
+}
+
+// decode reads and validates a JSON body
+func decode[T any](w http.ResponseWriter, r *http.Request) (T, bool) {
+	var body T
+
+	// 🧠 Decode request body JSON → Go struct
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if errors.Is(err, io.EOF) {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+		return body, false
+	}
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+		return body, false
+	}
+
+	// 🧩 Request validation
+	if err := validator.New().Struct(body); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+		return body, false
+	}
+	return body, true
+}
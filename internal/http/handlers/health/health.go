@@ -0,0 +1,33 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/health"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /healthz
+// ---------------------------------------------------------
+// Liveness: the process is up and serving. It never looks at the
+// database, so an outage doesn't get the instance restarted for nothing.
+func Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, map[string]any{"status": "ok"})
+	}
+}
+
+// 🧩 GET /readyz
+// ---------------------------------------------------------
+// Readiness, as the storage health monitor last saw it.
+// 1. 200 with the monitor's status while the database answers
+// 2. 503 (same body) once health.failure_threshold pings in a row failed
+func Ready(monitor *health.Monitor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		if !monitor.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		response.WriteJson(w, status, monitor.Status())
+	}
+}
@@ -0,0 +1,280 @@
+package invoice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/student/{id}/invoices
+// ---------------------------------------------------------
+// Bills a student: {"amount": 12500, "currency": "EUR", "due_date": "2026-09-30"}.
+// 1. Checks the student exists in the caller's tenant
+// 2. Validates amount (minor units, > 0), currency (ISO 4217) and due_date
+// 3. Saves the invoice as open via `CreateInvoice()`
+func New(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invoices, studentID, ok := scope(w, r, store)
+		if !ok {
+			return
+		}
+
+		var inv types.Invoice
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&inv)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(inv); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+
+		// 💾 Save, then read back what was stored
+		inv.StudentID = studentID
+		id, err := invoices.CreateInvoice(inv)
+		if err != nil {
+			slog.Error("Error creating invoice", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		inv, err = invoices.GetInvoiceById(id)
+		if err != nil {
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		inv.DeriveOverdue(time.Now())
+
+		slog.Info("Created invoice",
+			slog.Int64("student_id", studentID),
+			slog.Int64("id", id),
+			slog.Int64("amount", inv.Amount),
+			slog.String("currency", inv.Currency),
+		)
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusCreated, inv)
+	}
+}
+
+// 🧩 GET /api/student/{id}/invoices
+// ---------------------------------------------------------
+// Lists a student's invoices, oldest first.
+func GetList(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invoices, studentID, ok := scope(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Fetch records
+		list, err := invoices.GetInvoices(studentID)
+		if err != nil {
+			slog.Error("Error getting invoices", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, derived(list))
+	}
+}
+
+// 🧩 GET /api/invoices/{id}
+// ---------------------------------------------------------
+// Returns one invoice of the caller's tenant.
+func GetById(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invoices, ok := invoiceStore(w, r, store)
+		if !ok {
+			return
+		}
+		id, ok := invoiceID(w, r)
+		if !ok {
+			return
+		}
+
+		inv, err := invoices.GetInvoiceById(id)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+		inv.DeriveOverdue(time.Now())
+
+		response.WriteJson(w, http.StatusOK, inv)
+	}
+}
+
+// 🧩 POST /api/invoices/{id}/pay
+// ---------------------------------------------------------
+// Marks an invoice paid: {"payment_reference": "pi_3Nx..."}.
+//  1. Paying again with the same reference answers 200 with the invoice
+//     unchanged, so clients can retry safely
+//  2. 409 when it was paid with another reference, or the reference
+//     already paid another invoice
+func Pay(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invoices, ok := invoiceStore(w, r, store)
+		if !ok {
+			return
+		}
+		id, ok := invoiceID(w, r)
+		if !ok {
+			return
+		}
+
+		var payment types.InvoicePayment
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&payment)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(payment); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+
+		// 💾 Settle
+		inv, err := invoices.PayInvoice(id, payment.Reference)
+		switch {
+		case errors.Is(err, storage.ErrInvoicePaid), errors.Is(err, storage.ErrPaymentReferenceUsed):
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(err))
+			return
+		case err != nil:
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+		inv.DeriveOverdue(time.Now())
+
+		slog.Info("Invoice paid", slog.Int64("id", id), slog.String("payment_reference", inv.PaymentReference))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, inv)
+	}
+}
+
+// 🧩 GET /api/invoices/overdue?as_of=YYYY-MM-DD
+// ---------------------------------------------------------
+// Reports the open invoices due before as_of (today, UTC, by default),
+// oldest due date first, with the outstanding total per currency.
+func Overdue(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invoices, ok := invoiceStore(w, r, store)
+		if !ok {
+			return
+		}
+
+		asOf := time.Now().UTC().Format(types.DateLayout)
+		if raw := r.URL.Query().Get("as_of"); raw != "" {
+			if _, err := time.Parse(types.DateLayout, raw); err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("as_of must be a YYYY-MM-DD date (got %q)", raw)))
+				return
+			}
+			asOf = raw
+		}
+
+		// 💾 Fetch records
+		list, err := invoices.GetOverdueInvoices(asOf)
+		if err != nil {
+			slog.Error("Error getting overdue invoices", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		report := types.OverdueReport{AsOf: asOf, Count: len(list), Totals: map[string]int64{}, Invoices: []types.Invoice{}}
+		for _, inv := range list {
+			// overdue as of the report's date, whatever today is
+			inv.Overdue = true
+			report.Totals[inv.Currency] += inv.Amount
+			report.Invoices = append(report.Invoices, inv)
+		}
+
+		// 🚀 Send report
+		response.WriteJson(w, http.StatusOK, report)
+	}
+}
+
+// -------------------------------------------------------------
+// invoiceStore() → Tenant-scoped InvoiceStore
+// -------------------------------------------------------------
+// Writes the error response itself; ok is false when the handler should stop.
+func invoiceStore(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.InvoiceStore, bool) {
+	// 🏫 Restrict every query to the caller's tenant
+	invoices, ok := storage.As[storage.InvoiceStore](tenant.Scope(r.Context(), store))
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("invoices not supported by this storage backend")))
+		return nil, false
+	}
+	return invoices, true
+}
+
+// scope is invoiceStore plus the {id} student, checked to exist
+func scope(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.InvoiceStore, int64, bool) {
+	invoices, ok := invoiceStore(w, r, store)
+	if !ok {
+		return nil, 0, false
+	}
+
+	// 🔢 Convert id from string → int64
+	id := r.PathValue("id")
+	studentID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+		return nil, 0, false
+	}
+	if _, err := tenant.Scope(r.Context(), store).GetStudentById(studentID); err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return nil, 0, false
+	}
+	return invoices, studentID, true
+}
+
+// invoiceID parses the {id} path value
+func invoiceID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.PathValue("id")
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid invoice id %v", raw)))
+		return 0, false
+	}
+	return id, true
+}
+
+// derived sets Overdue on every invoice (and never returns nil)
+func derived(list []types.Invoice) []types.Invoice {
+	if list == nil {
+		return []types.Invoice{}
+	}
+	now := time.Now()
+	for i := range list {
+		list[i].DeriveOverdue(now)
+	}
+	return list
+}
@@ -0,0 +1,283 @@
+package portal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/portal"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// loginRequest is the body of POST /api/me/login
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// passwordRequest is the body of PUT /api/me/password and PUT /api/student/{id}/password
+type passwordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password"`
+}
+
+// 🧩 POST /api/me/login
+// ---------------------------------------------------------
+// Signs a student in with {email, password} and answers the session's
+// bearer token for the other /api/me routes.
+// 1. Wrong email or password → 401 (the same answer for both)
+// 2. Locked after too many failures → 429
+func Login(store storage.Storage, p *portal.Portal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		store := tenant.Scope(r.Context(), store)
+
+		if !enabled(w, p) {
+			return
+		}
+
+		var req loginRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) || (err == nil && (req.Email == "" || req.Password == "")) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("email and password are required")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🔐 Check the password and start a session
+		raw, session, err := p.Login(store, req.Email, req.Password)
+		if err != nil {
+			writePortalError(w, err)
+			return
+		}
+
+		slog.Info("Student signed in to the portal", slog.Int64("student_id", session.StudentID))
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"access_token": raw,
+			"token_type":   "Bearer",
+			"expires_in":   int64(time.Until(session.ExpiresAt).Seconds()),
+			"student_id":   session.StudentID,
+		})
+	}
+}
+
+// 🧩 POST /api/me/logout
+// ---------------------------------------------------------
+// Ends the caller's session; other sessions of the student stay.
+// Impersonations end through the admin API or POST /api/auth/logout.
+func Logout(store storage.Storage, p *portal.Portal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		store := tenant.Scope(r.Context(), store)
+
+		if middleware.Impersonator(r.Context()) != "" {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("end an impersonation with DELETE /api/admin/impersonations/{id} or POST /api/auth/logout")))
+			return
+		}
+
+		bearer, _ := middleware.BearerToken(r)
+		if err := p.Logout(store, bearer); err != nil {
+			writePortalError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"message": "Logged out successfully",
+		})
+	}
+}
+
+// 🧩 GET /api/me
+// ---------------------------------------------------------
+// The signed-in student's own record. The id comes from the session
+// (middleware.Portal), never from the request.
+func Me(store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		store := tenant.Scope(r.Context(), store)
+
+		session, _ := portal.FromContext(r.Context())
+		student, err := store.GetStudentById(session.StudentID)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, student)
+	}
+}
+
+// 🧩 PUT /api/me/password
+// ---------------------------------------------------------
+// Changes the signed-in student's password: {current_password, password}.
+// Every session of the student ends, this one too; sign in again.
+func ChangePassword(store storage.Storage, p *portal.Portal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		store := tenant.Scope(r.Context(), store)
+
+		req, ok := decodePassword(w, r)
+		if !ok {
+			return
+		}
+
+		session, _ := portal.FromContext(r.Context())
+		student, err := store.GetStudentById(session.StudentID)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+		if err := p.ChangePassword(store, student, req.CurrentPassword, req.Password); err != nil {
+			writePortalError(w, err)
+			return
+		}
+
+		slog.Info("Student changed their portal password", slog.Int64("student_id", student.ID))
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"message": "Password changed, sign in again",
+		})
+	}
+}
+
+// 🧩 PUT /api/student/{id}/password
+// ---------------------------------------------------------
+// Staff give a student a portal password: {password}. Also the way to
+// reset a forgotten one or lift a lockout; the student's sessions end.
+func SetPassword(store storage.Storage, p *portal.Portal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		store := tenant.Scope(r.Context(), store)
+
+		if !enabled(w, p) {
+			return
+		}
+		id, ok := studentID(w, r)
+		if !ok {
+			return
+		}
+		req, ok := decodePassword(w, r)
+		if !ok {
+			return
+		}
+		if _, err := store.GetStudentById(id); err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		if err := p.SetPassword(store, id, req.Password); err != nil {
+			writePortalError(w, err)
+			return
+		}
+
+		slog.Info("Set student portal password", slog.Int64("student_id", id))
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      id,
+			"message": "Portal password set",
+		})
+	}
+}
+
+// 🧩 DELETE /api/student/{id}/password
+// ---------------------------------------------------------
+// Takes a student's portal access away and ends their sessions.
+func DeletePassword(store storage.Storage, p *portal.Portal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		store := tenant.Scope(r.Context(), store)
+
+		if !enabled(w, p) {
+			return
+		}
+		id, ok := studentID(w, r)
+		if !ok {
+			return
+		}
+		logins, ok := storage.As[storage.PortalStore](store)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(portal.ErrUnsupported))
+			return
+		}
+
+		deleted, err := logins.DeleteStudentPassword(id)
+		if err != nil {
+			slog.Error("Error deleting portal password", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if !deleted {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(fmt.Errorf("student %d has no portal password", id)))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      id,
+			"message": "Portal access removed",
+		})
+	}
+}
+
+// enabled writes a 501 when the portal is off
+func enabled(w http.ResponseWriter, p *portal.Portal) bool {
+	if p == nil {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("student portal is disabled (set portal.enabled)")))
+		return false
+	}
+	return true
+}
+
+// studentID parses the {id} path parameter; writes the 400 itself
+func studentID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", r.PathValue("id"))))
+		return 0, false
+	}
+	return id, true
+}
+
+// decodePassword reads a passwordRequest; writes the 400 itself
+func decodePassword(w http.ResponseWriter, r *http.Request) (passwordRequest, bool) {
+	var req passwordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if errors.Is(err, io.EOF) || (err == nil && req.Password == "") {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("password is required")))
+		return req, false
+	}
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+		return req, false
+	}
+	return req, true
+}
+
+// writePortalError maps portal errors to statuses
+func writePortalError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, portal.ErrInvalidLogin):
+		response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(err))
+	case errors.Is(err, portal.ErrLocked):
+		response.WriteJson(w, http.StatusTooManyRequests, response.GeneralError(err))
+	case errors.Is(err, portal.ErrWeakPassword):
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+	case errors.Is(err, portal.ErrUnsupported):
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+	default:
+		slog.Error("Error in student portal", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+	}
+}
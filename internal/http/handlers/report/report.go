@@ -0,0 +1,88 @@
+package report
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/report"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/reports/age-distribution
+// ---------------------------------------------------------
+// Counts the students per age bucket (1-12, 13-17, ... 65+), with those
+// outside every bucket as "other".
+// 1. Calls `storage.AgeDistribution()`, one GROUP BY in the database
+// 2. Reuses the result for reports.cache_ttl (per tenant)
+func AgeDistribution(store storage.Storage, reports *report.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reportStore, ok := reportStoreFrom(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Aggregate in the database, unless cached
+		dist, err := reports.AgeDistribution(r.Context(), reportStore)
+		if err != nil {
+			slog.Error("Error computing age distribution", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send report
+		response.WriteJson(w, http.StatusOK, dist)
+	}
+}
+
+// 🧩 GET /api/reports/birthdays?month=1-12
+// ---------------------------------------------------------
+// Counts the students born in month, per day of it, e.g. to plan the
+// month's birthday cards.
+// 1. month defaults to the current one in time.display_timezone
+// 2. Calls `storage.Birthdays()`, one GROUP BY in the database; students
+// without a date_of_birth are left out
+// 3. Reuses the result for reports.cache_ttl (per tenant and month)
+func Birthdays(store storage.Storage, reports *report.Cache, display *time.Location) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := bind.NewQuery(r)
+		month := query.Int("month", int(time.Now().In(display).Month()), 1, 12)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		reportStore, ok := reportStoreFrom(w, r, store)
+		if !ok {
+			return
+		}
+
+		// 💾 Aggregate in the database, unless cached
+		birthdays, err := reports.Birthdays(r.Context(), reportStore, month)
+		if err != nil {
+			slog.Error("Error computing birthdays", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send report
+		response.WriteJson(w, http.StatusOK, birthdays)
+	}
+}
+
+// -------------------------------------------------------------
+// reportStoreFrom() → Tenant-scoped ReportStore
+// -------------------------------------------------------------
+// Writes the error response itself; ok is false when the handler should stop.
+func reportStoreFrom(w http.ResponseWriter, r *http.Request, store storage.Storage) (storage.ReportStore, bool) {
+	reports, ok := storage.As[storage.ReportStore](tenant.Scope(r.Context(), store))
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("reports not supported by this storage backend")))
+		return nil, false
+	}
+	return reports, true
+}
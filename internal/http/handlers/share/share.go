@@ -0,0 +1,229 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/share"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+var errDisabled = errors.New("sharing is disabled (set sharing.secret)")
+
+// 🧩 POST /api/student/{id}/share?ttl=<duration>
+// ---------------------------------------------------------
+// Signs a link showing the student record, read-only, to anyone who has
+// it until `ttl` (default sharing.default_ttl, at most sharing.max_ttl).
+// 1. Extracts `id` path param and checks the student exists
+// 2. Signs the link for the caller's tenant; answers 201 with url + expiry
+func Student(store storage.Storage, signer *share.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), store)
+
+		if signer == nil {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errDisabled))
+			return
+		}
+
+		ttl, ok := parseTTL(w, r)
+		if !ok {
+			return
+		}
+
+		// 🔢 Convert id from string → int64
+		id := r.PathValue("id")
+		intId64, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+			return
+		}
+		if _, err := storage.GetStudentById(intId64); err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		sign(w, signer, share.Link{TenantID: tenantID(r), Kind: share.KindStudent, StudentID: intId64}, ttl)
+	}
+}
+
+// 🧩 POST /api/student/{id}/documents/{docId}/share?ttl=<duration>
+// ---------------------------------------------------------
+// Like POST /api/student/{id}/share, for one of the student's documents:
+// the link downloads the file.
+func Document(store storage.Storage, signer *share.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), store)
+
+		if signer == nil {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errDisabled))
+			return
+		}
+
+		ttl, ok := parseTTL(w, r)
+		if !ok {
+			return
+		}
+
+		// 🔢 Convert both ids from string → int64
+		studentID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", r.PathValue("id"))))
+			return
+		}
+		docID, err := strconv.ParseInt(r.PathValue("docId"), 10, 64)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid document id %v", r.PathValue("docId"))))
+			return
+		}
+		if _, ok := findDocument(w, storage, studentID, docID); !ok {
+			return
+		}
+
+		sign(w, signer, share.Link{TenantID: tenantID(r), Kind: share.KindDocument, StudentID: studentID, DocumentID: docID}, ttl)
+	}
+}
+
+// 🧩 GET /api/shared/{token}
+// ---------------------------------------------------------
+// Opens a link signed by the share routes. Public and not tenant-scoped
+// by the request: the link carries its tenant.
+// 1. 404 for tampered links, 410 for expired ones
+// 2. A student link answers the student record; a document link streams
+// the file
+// 3. Records trashed or deleted since signing → 404
+func Get(store storage.Storage, blobs blob.Store, signer *share.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if signer == nil {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errDisabled))
+			return
+		}
+
+		link, err := signer.Verify(r.PathValue("token"))
+		switch {
+		case errors.Is(err, share.ErrExpired):
+			response.WriteJson(w, http.StatusGone, response.GeneralError(err))
+			return
+		case err != nil:
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		// 🏫 The link's tenant, whatever the request says
+		storage := tenant.Scope(tenant.WithTenant(r.Context(), types.Tenant{ID: link.TenantID}), store)
+
+		slog.Info("Opening shared link", slog.String("kind", link.Kind), slog.Int64("student_id", link.StudentID), slog.Int64("tenant_id", link.TenantID))
+
+		if link.Kind == share.KindStudent {
+			student, err := storage.GetStudentById(link.StudentID)
+			if err != nil {
+				response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+				return
+			}
+			w.Header().Set("Cache-Control", "private, no-store")
+			response.WriteJson(w, http.StatusOK, student)
+			return
+		}
+
+		doc, ok := findDocument(w, storage, link.StudentID, link.DocumentID)
+		if !ok {
+			return
+		}
+
+		// 💾 Fetch from the blob store
+		body, _, err := blobs.Get(r.Context(), doc.BlobKey)
+		if err != nil {
+			slog.Error("Error reading document", slog.String("key", doc.BlobKey), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		defer body.Close()
+
+		// 🚀 Stream the file
+		w.Header().Set("Content-Type", doc.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(doc.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": doc.Filename}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, body); err != nil {
+			slog.Error("Error sending document", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// parseTTL reads ?ttl; 0 when missing (the signer's default)
+func parseTTL(w http.ResponseWriter, r *http.Request) (time.Duration, bool) {
+	query := bind.NewQuery(r)
+	ttl := query.Duration("ttl", 0, time.Minute)
+	if err := query.Err(); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return 0, false
+	}
+	return ttl, true
+}
+
+// sign answers 201 with the signed URL of link and when it expires
+func sign(w http.ResponseWriter, signer *share.Signer, link share.Link, ttl time.Duration) {
+	url, expiresAt, err := signer.Sign(link, ttl)
+	if errors.Is(err, share.ErrTTL) {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return
+	}
+	if err != nil {
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return
+	}
+
+	slog.Info("Signed share link",
+		slog.String("kind", link.Kind),
+		slog.Int64("student_id", link.StudentID),
+		slog.Int64("document_id", link.DocumentID),
+		slog.Time("expires_at", expiresAt),
+	)
+
+	// 🚀 Send response
+	response.WriteJson(w, http.StatusCreated, map[string]any{
+		"success":    true,
+		"url":        url,
+		"expires_at": expiresAt,
+	})
+}
+
+// findDocument loads a document of a live student; writes the error itself
+func findDocument(w http.ResponseWriter, store storage.Storage, studentID, docID int64) (types.Document, bool) {
+	docs, ok := storage.As[storage.DocumentStore](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("documents not supported by this storage backend")))
+		return types.Document{}, false
+	}
+	if _, err := store.GetStudentById(studentID); err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return types.Document{}, false
+	}
+	doc, err := docs.GetDocumentById(studentID, docID)
+	if err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return types.Document{}, false
+	}
+	return doc, true
+}
+
+// tenantID is the request's tenant, or the default one when tenancy is off
+func tenantID(r *http.Request) int64 {
+	if t, ok := tenant.FromContext(r.Context()); ok {
+		return t.ID
+	}
+	return storage.DefaultTenantID
+}
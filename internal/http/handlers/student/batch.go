@@ -0,0 +1,105 @@
+package student
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/students/batch-get
+// ---------------------------------------------------------
+// Same as GET /api/students?ids=..., for id lists too long for a URL.
+// 1. Decodes {"ids":[1,2,3]} (1 to 100 positive ids)
+// 2. Fetches them in one query (see getBatch), embedding ?include= relations
+func BatchGet(storage storage.Storage, links *links.Builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		var req types.BatchRequest
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+		query := bind.NewQuery(r)
+		include := query.Fields("include", includable...)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		getBatch(w, storage, links, uniqueIDs(req.IDs), include)
+	}
+}
+
+// -------------------------------------------------------------
+// getBatch() → Many students in one query, missing ids listed
+// -------------------------------------------------------------
+// Found students keep the order of ids, so clients can zip the answer
+// with their request.
+func getBatch(w http.ResponseWriter, store storage.Storage, links *links.Builder, ids []int64, include []string) {
+	batch, ok := storage.As[storage.BatchStore](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("batch lookup not supported by this storage backend")))
+		return
+	}
+
+	slog.Info("Getting a batch of student records", slog.Int("ids", len(ids)))
+
+	// 💾 One query for the whole batch
+	students, err := batch.GetStudentsByIds(ids)
+	if err != nil {
+		slog.Error("Error getting students batch", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return
+	}
+
+	// 👪 Related resources, one query each for the whole batch
+	inc, err := loadIncluded(store, include, students)
+	if err != nil {
+		includeFailed(w, err)
+		return
+	}
+
+	found := make(map[int64]types.Student, len(students))
+	for _, student := range students {
+		found[student.ID] = student
+	}
+	result := types.StudentBatch{Data: []types.StudentResource{}, Missing: []int64{}}
+	for _, id := range ids {
+		if student, ok := found[id]; ok {
+			resource := links.Student(student)
+			inc.embed(&resource)
+			result.Data = append(result.Data, resource)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+
+	// 🚀 Send found + missing
+	response.WriteJson(w, http.StatusOK, result)
+}
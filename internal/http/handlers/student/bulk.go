@@ -0,0 +1,163 @@
+package student
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/validate"
+)
+
+// 🧩 PATCH /api/students/bulk
+// ---------------------------------------------------------
+// Applies the same changes to many students in one transaction.
+// 1. Decodes {"ids":[...], "changes":{...}} (1 to 1000 ids; changes can't touch name or email)
+// 2. Merges the changes into each student and validates it like a PUT would
+// 3. Stores the valid ones (and their student.updated events) together; invalid and unknown ids are reported, not fatal
+// 4. Responds with a result per id, in the order asked
+func BulkUpdate(storage storage.Storage, custom *customfield.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		bulk, ok := storageAs[storageBulk](storage)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("bulk changes not supported by this storage backend")))
+			return
+		}
+
+		var req types.BulkUpdateRequest
+
+		// 🧠 Decode request body JSON → Go struct; unknown change fields are refused
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if !decodeBulk(w, dec, &req) {
+			return
+		}
+		if req.Changes.Empty() {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("changes must set at least one of phone, date_of_birth, gender, address or custom")))
+			return
+		}
+
+		// 🧩 Definitions are loaded once for the whole batch
+		defs, err := custom.Definitions(storage)
+		if err != nil {
+			slog.Error("Error loading custom fields", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		ids := uniqueIDs(req.IDs)
+		slog.Info("Bulk updating student records", slog.Int("ids", len(ids)))
+
+		// 💾 One transaction for the whole batch
+		results, err := bulk.UpdateStudents(ids, func(student types.Student) (types.Student, error) {
+			student = req.Changes.Apply(student)
+			return student, validate.Student(defs, &student)
+		})
+		if err != nil {
+			slog.Error("Error bulk updating students", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send per-item results
+		writeBulkResults(w, results, types.BulkUpdated)
+	}
+}
+
+// 🧩 DELETE /api/students/bulk
+// ---------------------------------------------------------
+// Soft-deletes many students in one statement.
+// 1. Decodes {"ids":[...]} (1 to 1000 ids)
+// 2. Moves the live ones to the trash, recording student.deleted for each; unknown ids are reported, not fatal
+// 3. Responds with a result per id, in the order asked
+func BulkDelete(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		bulk, ok := storageAs[storageBulk](storage)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("bulk changes not supported by this storage backend")))
+			return
+		}
+
+		var req types.BulkDeleteRequest
+
+		// 🧠 Decode request body JSON → Go struct
+		if !decodeBulk(w, json.NewDecoder(r.Body), &req) {
+			return
+		}
+
+		ids := uniqueIDs(req.IDs)
+		slog.Info("Bulk deleting student records", slog.Int("ids", len(ids)))
+
+		// 💾 Soft delete
+		results, err := bulk.DeleteStudents(ids)
+		if err != nil {
+			slog.Error("Error bulk deleting students", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send per-item results
+		writeBulkResults(w, results, types.BulkDeleted)
+	}
+}
+
+// decodeBulk decodes and validates a bulk request body, writing the 400 itself
+func decodeBulk(w http.ResponseWriter, dec *json.Decoder, req any) bool {
+	err := dec.Decode(req)
+	if errors.Is(err, io.EOF) {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+		return false
+	}
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+		return false
+	}
+	if err := validator.New().Struct(req); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+		return false
+	}
+	return true
+}
+
+// writeBulkResults answers 200 with the results and how many got done
+// (status) or failed
+func writeBulkResults(w http.ResponseWriter, results []types.BulkResult, status string) {
+	done := 0
+	for _, result := range results {
+		if result.Status == status {
+			done++
+		}
+	}
+	response.WriteJson(w, http.StatusOK, map[string]any{
+		"success": true,
+		status:    done,
+		"failed":  len(results) - done,
+		"results": results,
+	})
+}
+
+// uniqueIDs drops repeated ids, keeping the first of each
+func uniqueIDs(ids []int64) []int64 {
+	var unique []int64
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
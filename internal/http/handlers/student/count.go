@@ -0,0 +1,108 @@
+package student
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// TotalCountHeader carries the number of students a list matches
+const TotalCountHeader = "X-Total-Count"
+
+// 🧩 GET /api/students/count
+// ---------------------------------------------------------
+// Counts the students GET /api/students would list.
+// 1. Takes the list's filters: ?custom.<key>=<value> or ?verified=true|false
+// 2. Calls `storage.CountStudents()`, a COUNT(*) in the database: no
+// student is loaded
+// 3. Returns {"count": n}
+func Count(store storage.Storage, custom *customfield.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		store := tenant.Scope(r.Context(), store)
+
+		filter, ok := customFilter(w, r, store, custom)
+		if !ok {
+			return
+		}
+		n, ok := countStudents(w, r, store, filter)
+		if !ok {
+			return
+		}
+
+		// 🚀 Send the count
+		response.WriteJson(w, http.StatusOK, map[string]int64{"count": n})
+	}
+}
+
+// -------------------------------------------------------------
+// headList() → HEAD /api/students: just the X-Total-Count header
+// -------------------------------------------------------------
+// Counted like GET /api/students/count; paging parameters are ignored.
+func headList(w http.ResponseWriter, r *http.Request, store storage.Storage, filter map[string]any) {
+	n, ok := countStudents(w, r, store, filter)
+	if !ok {
+		return
+	}
+	w.Header().Set(TotalCountHeader, strconv.FormatInt(n, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+// countStudents counts the students matching filter and ?verified=.
+// Writes the error itself.
+func countStudents(w http.ResponseWriter, r *http.Request, store storage.Storage, filter map[string]any) (int64, bool) {
+	counter, ok := storage.As[storage.CountStore](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("counting not supported by this storage backend")))
+		return 0, false
+	}
+
+	q := storage.StudentQuery{Filter: filter}
+	query := bind.NewQuery(r)
+	if query.Has("verified") {
+		verified := query.Bool("verified", false)
+		q.Verified = &verified
+	}
+	if err := query.Err(); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return 0, false
+	}
+	if q.Verified != nil && filter != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("verified cannot be combined with custom filters")))
+		return 0, false
+	}
+
+	// 💾 COUNT(*) in the database
+	n, err := counter.CountStudents(q)
+	if err != nil {
+		slog.Error("Error counting students", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return 0, false
+	}
+	return n, true
+}
+
+// setTotalCount sets X-Total-Count on a list or page of the students
+// matching filter or verified (nil: either), when the backend can count
+// them. Writes the error itself.
+func setTotalCount(w http.ResponseWriter, store storage.Storage, filter map[string]any, verified *bool) bool {
+	counter, ok := storage.As[storage.CountStore](store)
+	if !ok {
+		return true
+	}
+	n, err := counter.CountStudents(storage.StudentQuery{Filter: filter, Verified: verified})
+	if err != nil {
+		slog.Error("Error counting students", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return false
+	}
+	w.Header().Set(TotalCountHeader, strconv.FormatInt(n, 10))
+	return true
+}
@@ -0,0 +1,103 @@
+package student
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Sparse fieldsets: ?fields=id,name trims each student in the response to
+// those keys. Backends implementing storage.FieldStore also SELECT only
+// those columns; the rest load full rows and are trimmed here.
+
+var errStudentNotFound = response.NewStaticProblem(http.StatusNotFound, "student not found")
+
+// isStudentNotFound tells a missing student from a failed lookup; handlers
+// name their storage param storage, hiding the package
+func isStudentNotFound(err error) bool {
+	return errors.Is(err, storage.ErrStudentNotFound)
+}
+
+// isEmailTaken tells an update refused for a duplicate email from one that failed
+func isEmailTaken(err error) bool {
+	return errors.Is(err, storage.ErrEmailTaken)
+}
+
+// loadStudent fetches one student, narrowed to fields when given
+func loadStudent(store storage.Storage, id int64, fields []string) (types.Student, error) {
+	if narrow, ok := storage.As[storage.FieldStore](store); ok && fields != nil {
+		return narrow.GetStudentByIdFields(id, fields)
+	}
+	return store.GetStudentById(id)
+}
+
+// loadStudents fetches the whole list, narrowed to fields when given
+func loadStudents(store storage.Storage, fields []string) ([]types.Student, error) {
+	if narrow, ok := storage.As[storage.FieldStore](store); ok && fields != nil {
+		return narrow.GetStudentsFields(fields)
+	}
+	return store.GetStudents()
+}
+
+// loadStudentsAfter fetches one keyset page of store, narrowed to fields when given
+func loadStudentsAfter(store storage.Storage, pages storage.PageStore, afterID int64, limit int, fields []string) ([]types.Student, error) {
+	if narrow, ok := storage.As[storage.FieldStore](store); ok && fields != nil {
+		return narrow.GetStudentsAfterFields(afterID, limit, fields)
+	}
+	return pages.GetStudentsAfter(afterID, limit)
+}
+
+// -------------------------------------------------------------
+// project() → The student as a map holding only the requested keys
+// -------------------------------------------------------------
+func project(student types.Student, fields []string) map[string]any {
+	out := make(map[string]any, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "id":
+			out["id"] = student.ID
+		case "public_id":
+			out["public_id"] = student.PublicID
+		case "name":
+			out["name"] = student.Name
+		case "email":
+			out["email"] = student.Email
+		case "age":
+			out["age"] = student.Age
+		case "phone":
+			out["phone"] = student.Phone
+		case "date_of_birth":
+			out["date_of_birth"] = student.DateOfBirth
+		case "gender":
+			out["gender"] = student.Gender
+		case "address":
+			out["address"] = student.Address
+		case "custom":
+			out["custom"] = student.Custom
+		case "verified":
+			out["verified"] = student.Verified
+		}
+	}
+	return out
+}
+
+func projectAll(students []types.Student, fields []string) []map[string]any {
+	out := make([]map[string]any, 0, len(students))
+	for _, student := range students {
+		out = append(out, project(student, fields))
+	}
+	return out
+}
+
+// withField returns fields plus extra, so e.g. the sort key is loaded even
+// when the client didn't ask to see it
+func withField(fields []string, extra string) []string {
+	if fields == nil || slices.Contains(fields, extra) {
+		return fields
+	}
+	return append(slices.Clip(fields), extra)
+}
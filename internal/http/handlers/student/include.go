@@ -0,0 +1,142 @@
+package student
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Relationship expansion: ?include=guardians,invoices embeds each
+// student's related records in the response, so clients skip the
+// follow-up calls. Every relation loads with one batched query for the
+// whole list or page, never one per student.
+
+// includable are the related resources ?include= can embed in a student
+var includable = []string{"guardians", "documents", "invoices"}
+
+// errIncludeUnsupported answers ?include= of a relation the backend doesn't keep
+var errIncludeUnsupported = errors.New("not supported by this storage backend")
+
+// included holds the loaded relations, by name then student id
+type included map[string]map[int64]any
+
+// -------------------------------------------------------------
+// loadIncluded() → Every relation in include for a batch of students
+// -------------------------------------------------------------
+// One query per relation; students without related records get an empty
+// list, so the response always carries the keys that were asked for.
+func loadIncluded(storage storage.Storage, include []string, students []types.Student) (included, error) {
+	if len(include) == 0 || len(students) == 0 {
+		return nil, nil
+	}
+	ids := make([]int64, len(students))
+	for i, student := range students {
+		ids[i] = student.ID
+	}
+
+	inc := make(included, len(include))
+	for _, name := range include {
+		var (
+			byStudent map[int64]any
+			err       error
+		)
+		switch name {
+		case "guardians":
+			byStudent, err = relation(storage, name, ids, storageGuardians.GetStudentGuardians)
+		case "documents":
+			byStudent, err = relation(storage, name, ids, storageDocuments.GetStudentDocuments)
+		case "invoices":
+			byStudent, err = relation(storage, name, ids, func(invoices storageInvoices, ids []int64) (map[int64][]types.Invoice, error) {
+				byStudent, err := invoices.GetStudentInvoices(ids)
+				now := time.Now()
+				for _, list := range byStudent {
+					for i := range list {
+						list[i].DeriveOverdue(now)
+					}
+				}
+				return byStudent, err
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+		inc[name] = byStudent
+	}
+	return inc, nil
+}
+
+// relation loads one relation through the storage capability S
+func relation[S, T any](storage storage.Storage, name string, ids []int64, load func(S, []int64) (map[int64][]T, error)) (map[int64]any, error) {
+	store, ok := storageAs[S](storage)
+	if !ok {
+		return nil, fmt.Errorf("%s %w", name, errIncludeUnsupported)
+	}
+	byStudent, err := load(store, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", name, err)
+	}
+	out := make(map[int64]any, len(ids))
+	for _, id := range ids {
+		list := byStudent[id]
+		if list == nil {
+			list = []T{}
+		}
+		out[id] = list
+	}
+	return out, nil
+}
+
+// embed sets the loaded relations on a student's full resource
+func (inc included) embed(resource *types.StudentResource) {
+	for _, byStudent := range inc {
+		switch list := byStudent[resource.ID].(type) {
+		case []types.StudentGuardian:
+			resource.Guardians = list
+		case []types.Document:
+			resource.Documents = list
+		case []types.Invoice:
+			resource.Invoices = list
+		}
+	}
+}
+
+// embedAll is embed for a list, in the order of its students
+func (inc included) embedAll(resources []types.StudentResource) []types.StudentResource {
+	for i := range resources {
+		inc.embed(&resources[i])
+	}
+	return resources
+}
+
+// project adds the loaded relations to a sparse student
+func (inc included) project(out map[string]any, id int64) map[string]any {
+	for name, byStudent := range inc {
+		out[name] = byStudent[id]
+	}
+	return out
+}
+
+// projectAll is project for a list, in the order of students
+func (inc included) projectAll(out []map[string]any, students []types.Student) []map[string]any {
+	for i, student := range students {
+		inc.project(out[i], student.ID)
+	}
+	return out
+}
+
+// includeFailed answers a loadIncluded error: 501 for a relation the
+// backend doesn't keep, 500 otherwise
+func includeFailed(w http.ResponseWriter, err error) {
+	if errors.Is(err, errIncludeUnsupported) {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+		return
+	}
+	slog.Error("Error getting included resources", slog.String("error", err.Error()))
+	response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+}
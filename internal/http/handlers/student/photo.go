@@ -0,0 +1,218 @@
+package student
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Photo types we accept, detected from the bytes (never the client's header)
+var photoTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Browsers may reuse a photo for 5 minutes, then revalidate with the ETag
+const photoCacheControl = "private, max-age=300"
+
+// 🧩 POST /api/student/{id}/photo
+// ---------------------------------------------------------
+// Uploads (or replaces) a student's photo.
+// 1. Checks the student exists in the caller's tenant
+// 2. Reads the multipart field `photo`, at most maxBytes
+// 3. Sniffs the type: jpeg, png, gif or webp only
+// 4. Stores it via `blob.Store.Put()`
+func UploadPhoto(storage storage.Storage, blobs blob.Store, maxBytes int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		intId64, ok := studentFromPath(w, r, storage)
+		if !ok {
+			return
+		}
+
+		// 🧠 Stream the multipart body; reject anything too large early
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1<<20)
+		data, status, err := readPhotoPart(r, maxBytes)
+		if err != nil {
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		contentType := http.DetectContentType(data)
+		if !photoTypes[contentType] {
+			response.WriteJson(w, http.StatusUnsupportedMediaType, response.GeneralError(fmt.Errorf("unsupported photo type %s (use jpeg, png, gif or webp)", contentType)))
+			return
+		}
+
+		// 💾 Store the photo
+		if err := blobs.Put(r.Context(), blob.PhotoKey(intId64), bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+			slog.Error("Error storing photo", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Stored student photo",
+			slog.Int64("id", intId64),
+			slog.String("content_type", contentType),
+			slog.Int("bytes", len(data)),
+		)
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusCreated, map[string]any{
+			"success":      true,
+			"id":           intId64,
+			"content_type": contentType,
+			"size":         len(data),
+			"message":      "Photo uploaded successfully",
+		})
+	}
+}
+
+// 🧩 GET /api/student/{id}/photo
+// ---------------------------------------------------------
+// Serves the photo with ETag / Last-Modified so clients can revalidate
+// cheaply (304 Not Modified).
+func GetPhoto(storage storage.Storage, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		intId64, ok := studentFromPath(w, r, storage)
+		if !ok {
+			return
+		}
+
+		// 💾 Fetch from the blob store
+		body, obj, err := blobs.Get(r.Context(), blob.PhotoKey(intId64))
+		if errors.Is(err, blob.ErrNotFound) {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(fmt.Errorf("student %d has no photo", intId64)))
+			return
+		}
+		if err != nil {
+			slog.Error("Error reading photo", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		defer body.Close()
+
+		// 🗂️ Caching headers
+		w.Header().Set("Cache-Control", photoCacheControl)
+		if obj.ETag != "" {
+			w.Header().Set("ETag", obj.ETag)
+		}
+		if !obj.ModTime.IsZero() {
+			w.Header().Set("Last-Modified", obj.ModTime.UTC().Format(http.TimeFormat))
+		}
+		if notModified(r, obj) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		// 🚀 Stream the image
+		w.Header().Set("Content-Type", obj.ContentType)
+		if obj.Size > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(obj.Size, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, body); err != nil {
+			slog.Error("Error sending photo", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// -------------------------------------------------------------
+// studentFromPath() → Parse {id} and make sure the student is visible
+// -------------------------------------------------------------
+// Writes the error response itself; ok is false when the handler should stop.
+func studentFromPath(w http.ResponseWriter, r *http.Request, store storage.Storage) (int64, bool) {
+	id := r.PathValue("id")
+
+	// 🔢 Convert id from string → int64
+	intId64, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+		return 0, false
+	}
+
+	if _, err := store.GetStudentById(intId64); err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return 0, false
+	}
+	return intId64, true
+}
+
+// readPhotoPart returns the bytes of the `photo` form field plus the HTTP
+// status to use when it fails.
+func readPhotoPart(r *http.Request, maxBytes int64) ([]byte, int, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("expected multipart/form-data with a photo field: %v", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, http.StatusBadRequest, errors.New("missing photo field")
+		}
+		if err != nil {
+			return nil, uploadErrorStatus(err), fmt.Errorf("invalid multipart body: %v", err)
+		}
+		if part.FormName() != "photo" {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxBytes+1))
+		part.Close()
+		if err != nil {
+			return nil, uploadErrorStatus(err), fmt.Errorf("read photo: %v", err)
+		}
+		if int64(len(data)) > maxBytes {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("photo exceeds %d bytes", maxBytes)
+		}
+		if len(data) == 0 {
+			return nil, http.StatusBadRequest, errors.New("photo is empty")
+		}
+		return data, http.StatusOK, nil
+	}
+}
+
+func uploadErrorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// notModified implements If-None-Match (preferred) and If-Modified-Since.
+func notModified(r *http.Request, obj blob.Object) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || (obj.ETag != "" && tag == obj.ETag) {
+				return true
+			}
+		}
+		return false
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !obj.ModTime.IsZero() {
+		return !obj.ModTime.Truncate(time.Second).After(since)
+	}
+	return false
+}
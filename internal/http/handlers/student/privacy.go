@@ -0,0 +1,306 @@
+package student
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// dataExport is everything stored about one student (GDPR subject access)
+type dataExport struct {
+	ExportedAt        time.Time               `json:"exported_at"`
+	Student           types.Student           `json:"student"`
+	Documents         []types.Document        `json:"documents"`
+	Invoices          []types.Invoice         `json:"invoices"`
+	Guardians         []types.StudentGuardian `json:"guardians"`
+	Photo             *exportedPhoto          `json:"photo"`
+	WebhookDeliveries []types.WebhookDelivery `json:"webhook_deliveries"`
+	AuditLog          []types.AuditEntry      `json:"audit_log"`
+}
+
+// exportedPhoto describes the photo; the ZIP bundle holds the file itself
+type exportedPhoto struct {
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	File        string `json:"file,omitempty"`
+}
+
+// 🧩 GET /api/student/{id}/data-export?format=json|zip
+// ---------------------------------------------------------
+// Returns all data held about a student, trash included: profile,
+// documents, photo, webhook deliveries about it and its audit log.
+// 1. Records a student.data_exported audit entry (so it appears in the bundle)
+// 2. Collects the data from the tenant-scoped stores
+// 3. Sends JSON, or with `format=zip` data.json plus the photo and documents
+func DataExport(storage storage.Storage, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		privacy, ok := storageAs[storagePrivacy](storage)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("data export not supported by this storage backend")))
+			return
+		}
+
+		query := bind.NewQuery(r)
+		format := query.OneOf("format", "json", "json", "zip")
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		student, ok := anyStudentFromPath(w, r, storage)
+		if !ok {
+			return
+		}
+
+		// 🧾 Audit first, so the bundle shows this export too
+		detail, _ := json.Marshal(map[string]string{"format": format})
+		if _, err := privacy.CreateAuditEntry(types.AuditEntry{
+			StudentID:    student.ID,
+			Action:       types.AuditDataExported,
+			Actor:        middleware.Subject(r.Context()),
+			Impersonator: middleware.Impersonator(r.Context()),
+			Detail:       detail,
+		}); err != nil {
+			slog.Error("Error recording data export", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 💾 Collect
+		bundle := dataExport{ExportedAt: time.Now().UTC(), Student: student}
+		var err error
+		if docs, ok := storageAs[storageDocuments](storage); ok {
+			bundle.Documents, err = docs.GetDocuments(student.ID)
+		}
+		if invoices, ok := storageAs[storageInvoices](storage); ok && err == nil {
+			bundle.Invoices, err = invoices.GetInvoices(student.ID)
+		}
+		if guardians, ok := storageAs[storageGuardians](storage); ok && err == nil {
+			var byStudent map[int64][]types.StudentGuardian
+			byStudent, err = guardians.GetStudentGuardians([]int64{student.ID})
+			bundle.Guardians = byStudent[student.ID]
+		}
+		if err == nil {
+			bundle.WebhookDeliveries, err = privacy.GetStudentDeliveries(student.ID)
+		}
+		if err == nil {
+			bundle.AuditLog, err = privacy.GetAuditEntries(student.ID)
+		}
+		var photo io.ReadCloser
+		if err == nil {
+			photo, bundle.Photo, err = openPhoto(r, blobs, student.ID)
+		}
+		if err != nil {
+			slog.Error("Error collecting data export", slog.Int64("id", student.ID), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		if photo != nil {
+			defer photo.Close()
+		}
+		// empty lists as [], never null
+		if bundle.Documents == nil {
+			bundle.Documents = []types.Document{}
+		}
+		if bundle.Invoices == nil {
+			bundle.Invoices = []types.Invoice{}
+		}
+		if bundle.Guardians == nil {
+			bundle.Guardians = []types.StudentGuardian{}
+		}
+		for i := range bundle.Invoices {
+			bundle.Invoices[i].DeriveOverdue(bundle.ExportedAt)
+		}
+		if bundle.WebhookDeliveries == nil {
+			bundle.WebhookDeliveries = []types.WebhookDelivery{}
+		}
+
+		slog.Info("Exported student data", slog.Int64("id", student.ID), slog.String("format", format))
+
+		if format == "json" {
+			response.WriteJson(w, http.StatusOK, bundle)
+			return
+		}
+
+		// 🗜️ ZIP: data.json, the photo and every document file
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="student-%d-data.zip"`, student.ID))
+		w.WriteHeader(http.StatusOK)
+		if err := writeDataZip(r, w, blobs, bundle, photo); err != nil {
+			// headers are gone: all we can do is log and cut the archive short
+			slog.Error("Error writing data export", slog.Int64("id", student.ID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// 🧩 DELETE /api/student/{id}/erase
+// ---------------------------------------------------------
+// GDPR erasure: anonymizes the student instead of deleting the row, so
+// ids referenced elsewhere stay valid.
+// 1. Calls `EraseStudent()`, which also scrubs webhook payloads, audits and records student.erased
+// 2. Deletes the photo and document files
+func Erase(storage storage.Storage, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		privacy, ok := storageAs[storagePrivacy](storage)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("erasure not supported by this storage backend")))
+			return
+		}
+
+		student, ok := anyStudentFromPath(w, r, storage)
+		if !ok {
+			return
+		}
+
+		// 💾 Anonymize in one transaction
+		docs, err := privacy.EraseStudent(student.ID, types.AuditEntry{
+			Action:       types.AuditErased,
+			Actor:        middleware.Subject(r.Context()),
+			Impersonator: middleware.Impersonator(r.Context()),
+		})
+		if err != nil {
+			slog.Error("Error erasing student", slog.Int64("id", student.ID), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🗑️ Files last: a leftover blob can be deleted again, un-erased rows could not
+		keys := []string{blob.PhotoKey(student.ID)}
+		for _, doc := range docs {
+			keys = append(keys, doc.BlobKey)
+		}
+		for _, key := range keys {
+			if err := blobs.Delete(r.Context(), key); err != nil && !errors.Is(err, blob.ErrNotFound) {
+				slog.Error("Error deleting erased student's file", slog.String("key", key), slog.String("error", err.Error()))
+			}
+		}
+
+		slog.Warn("Erased student", slog.Int64("id", student.ID), slog.Int("documents", len(docs)))
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success":   true,
+			"id":        student.ID,
+			"documents": len(docs),
+			"message":   "Student erased",
+		})
+	}
+}
+
+// -------------------------------------------------------------
+// anyStudentFromPath() → Parse {id} and find the student, trash included
+// -------------------------------------------------------------
+// Writes the error response itself; ok is false when the handler should stop.
+func anyStudentFromPath(w http.ResponseWriter, r *http.Request, store storage.Storage) (types.Student, bool) {
+	id := r.PathValue("id")
+
+	// 🔢 Convert id from string → int64
+	intId64, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+		return types.Student{}, false
+	}
+
+	student, err := store.GetStudentById(intId64)
+	if err == nil {
+		return student, true
+	}
+	if trash, ok := storageAs[storageTrash](store); ok {
+		deleted, err := trash.GetDeletedStudents()
+		if err != nil {
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return types.Student{}, false
+		}
+		for _, trashed := range deleted {
+			if trashed.ID == intId64 {
+				return trashed, true
+			}
+		}
+	}
+	response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+	return types.Student{}, false
+}
+
+// openPhoto returns the student's photo and its description; both are nil
+// when there is none
+func openPhoto(r *http.Request, blobs blob.Store, id int64) (io.ReadCloser, *exportedPhoto, error) {
+	body, obj, err := blobs.Get(r.Context(), blob.PhotoKey(id))
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	// photos are always image/<jpeg|png|gif|webp>, see photoTypes
+	file := "photo." + strings.TrimPrefix(obj.ContentType, "image/")
+	return body, &exportedPhoto{ContentType: obj.ContentType, Size: obj.Size, File: file}, nil
+}
+
+// writeDataZip streams the ZIP bundle of DataExport
+func writeDataZip(r *http.Request, w io.Writer, blobs blob.Store, bundle dataExport, photo io.Reader) error {
+	archive := zip.NewWriter(w)
+
+	data, err := archive.Create("data.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(data)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return err
+	}
+
+	if photo != nil {
+		if err := copyToZip(archive, bundle.Photo.File, photo); err != nil {
+			return err
+		}
+	}
+
+	for _, doc := range bundle.Documents {
+		body, _, err := blobs.Get(r.Context(), doc.BlobKey)
+		if errors.Is(err, blob.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("document %d: %w", doc.ID, err)
+		}
+		err = copyToZip(archive, fmt.Sprintf("documents/%d-%s", doc.ID, path.Base(doc.Filename)), body)
+		body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// copyToZip adds one file to the archive
+func copyToZip(archive *zip.Writer, name string, r io.Reader) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -0,0 +1,253 @@
+package student
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// streamBatchSize is how many students share one ?include= query and flush
+const streamBatchSize = 500
+
+// streamBatchTimeout is how long each batch gets to go out: a stream is
+// held to it rather than to http_server.write_timeout, which a long one
+// would outlive
+const streamBatchTimeout = time.Minute
+
+// errStreamAborted ends a stream whose client stopped reading
+var errStreamAborted = errors.New("client stopped reading")
+
+// errListTooLong ends a list stream that read more rows than its cap
+var errListTooLong = errors.New("list longer than pagination.max_limit")
+
+// -------------------------------------------------------------
+// streamStudents() → GET /api/students with Accept: application/x-ndjson
+// -------------------------------------------------------------
+// One student per line, in id order, read through GetStudentsIter and
+// flushed every streamBatchSize rows, so memory stays flat however many
+// rows there are and pagination.max_limit doesn't apply. ?fields=,
+// ?include= and ?custom.<key>= work as on the JSON list.
+//
+// Errors before the first row get the usual status and JSON body; once
+// rows have gone out the status is sent, so a failure ends the stream
+// with a problem line ({"type": ..., "status": 500, "detail": ...}) instead.
+func streamStudents(w http.ResponseWriter, r *http.Request, store storage.Storage, links *links.Builder, filter map[string]any, fields, include []string) {
+	slog.Info("Streaming student records", slog.Bool("filtered", filter != nil))
+
+	stream := newStudentStream(w, store, links, fields, include, false)
+	err := stream.run(r.Context(), filter)
+
+	switch {
+	case err == nil:
+		slog.Info("Streamed student records", slog.Int("sent", stream.sent))
+	case errors.Is(err, errStreamAborted) || r.Context().Err() != nil:
+		slog.Info("Student stream cancelled", slog.Int("sent", stream.sent), slog.String("error", err.Error()))
+	case !stream.started && stream.includeErr:
+		includeFailed(w, err)
+	case !stream.started:
+		slog.Error("Error getting students", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+	default:
+		slog.Error("Error streaming students", slog.Int("sent", stream.sent), slog.String("error", err.Error()))
+		problem := response.GeneralError(err)
+		problem.Status, problem.Title = http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+		stream.enc.Encode(problem)
+		stream.write(nil)
+	}
+}
+
+// -------------------------------------------------------------
+// streamList() → GET /api/students in id order, as a JSON array
+// -------------------------------------------------------------
+// The same array the sorted list sends, written element by element as
+// GetStudentsIter reads the rows and flushed every streamBatchSize of
+// them, so the whole list is never held in memory. The count is taken
+// first: X-Total-Count is set and pagination.max_limit enforced before
+// any row goes out. Rows added after the count are held to the cap too:
+// the stream stops at the first row over it.
+//
+// A failure once rows have gone out can't be reported in the array: the
+// response ends there, without the closing ], so clients can't mistake
+// it for the whole list. Reports false, having written nothing, when the
+// backend can't count its students.
+func streamList(w http.ResponseWriter, r *http.Request, store storage.Storage, links *links.Builder, paging config.Pagination, filter map[string]any, fields, include []string) bool {
+	counter, ok := storage.As[storage.CountStore](store)
+	if !ok {
+		return false
+	}
+	slog.Info("Getting all student records", slog.Bool("streamed", true))
+
+	// 💾 COUNT(*) first, for the header and the limit
+	n, err := counter.CountStudents(storage.StudentQuery{Filter: filter})
+	if err != nil {
+		slog.Error("Error counting students", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return true
+	}
+	tooLong := fmt.Errorf("more than %d students, page through them with ?limit and ?cursor", paging.MaxLimit)
+	if n > int64(paging.MaxLimit) {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(tooLong))
+		return true
+	}
+	w.Header().Set(TotalCountHeader, strconv.FormatInt(n, 10))
+
+	stream := newStudentStream(w, store, links, fields, include, true)
+	stream.max = paging.MaxLimit
+	err = stream.run(r.Context(), filter)
+
+	switch {
+	case err == nil:
+		slog.Info("Streamed student list", slog.Int("sent", stream.sent))
+	case errors.Is(err, errStreamAborted) || r.Context().Err() != nil:
+		slog.Info("Student list cancelled", slog.Int("sent", stream.sent), slog.String("error", err.Error()))
+	case !stream.started && stream.includeErr:
+		includeFailed(w, err)
+	case !stream.started && errors.Is(err, errListTooLong):
+		w.Header().Del(TotalCountHeader)
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(tooLong))
+	case !stream.started:
+		slog.Error("Error getting students", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+	default:
+		slog.Error("Error streaming student list", slog.Int("sent", stream.sent), slog.String("error", err.Error()))
+	}
+	return true
+}
+
+// studentStream writes the lines of one NDJSON response, or the elements
+// of one JSON array
+type studentStream struct {
+	w     http.ResponseWriter
+	rc    *http.ResponseController
+	array bool
+	// max caps the rows a list streams (0: no cap, as for NDJSON)
+	max  int
+	read int
+	// buf holds the batch being encoded, written out in one go
+	buf     bytes.Buffer
+	enc     *json.Encoder
+	store   storage.Storage
+	links   *links.Builder
+	fields  []string
+	include []string
+
+	// started is set once the status and headers went out
+	started bool
+	// includeErr marks an error from loading ?include= relations
+	includeErr bool
+	sent       int
+}
+
+func newStudentStream(w http.ResponseWriter, store storage.Storage, links *links.Builder, fields, include []string, array bool) *studentStream {
+	s := &studentStream{w: w, rc: http.NewResponseController(w), array: array, store: store, links: links, fields: fields, include: include}
+	s.enc = json.NewEncoder(&s.buf)
+	s.enc.SetEscapeHTML(false)
+	return s
+}
+
+// run streams the students matching filter, a batch at a time; an array
+// is closed once the last one went out
+func (s *studentStream) run(ctx context.Context, filter map[string]any) error {
+	// 💾 Rows as the storage reads them, sent a batch at a time
+	batch := make([]types.Student, 0, streamBatchSize)
+	err := s.store.GetStudentsIter(ctx, filter, func(student types.Student) error {
+		if s.read++; s.max > 0 && s.read > s.max {
+			return errListTooLong
+		}
+		batch = append(batch, student)
+		if len(batch) < streamBatchSize {
+			return nil
+		}
+		err := s.send(batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	// the last, partial batch; also sends the headers of an empty stream
+	if err := s.send(batch); err != nil {
+		return err
+	}
+	if s.array {
+		return s.write([]byte("]\n"))
+	}
+	return nil
+}
+
+// send writes one batch of students, with their related resources, and flushes
+func (s *studentStream) send(students []types.Student) error {
+	// 👪 Related resources, one query each for the batch
+	inc, err := loadIncluded(s.store, s.include, students)
+	if err != nil {
+		s.includeErr = true
+		return err
+	}
+
+	if !s.started {
+		contentType := response.NDJSON
+		if s.array {
+			contentType = "application/json"
+			s.buf.WriteByte('[')
+		}
+		s.w.Header().Set("Content-Type", contentType)
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	// 🚀 One line, or array element, per student
+	for _, student := range students {
+		var line any
+		if s.fields != nil {
+			line = inc.project(project(student, s.fields), student.ID)
+		} else {
+			resource := s.links.Student(student)
+			inc.embed(&resource)
+			line = resource
+		}
+		if s.array && s.sent > 0 {
+			s.buf.WriteByte(',')
+		}
+		if err := s.enc.Encode(line); err != nil {
+			return err
+		}
+		if s.array {
+			// elements are comma-separated, not one per line
+			s.buf.Truncate(s.buf.Len() - 1)
+		}
+		s.sent++
+	}
+	if err := s.rc.SetWriteDeadline(time.Now().Add(streamBatchTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("%w: %v", errStreamAborted, err)
+	}
+	if err := s.write(nil); err != nil {
+		return err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("%w: %v", errStreamAborted, err)
+	}
+	return nil
+}
+
+// write sends the encoded batch, then tail
+func (s *studentStream) write(tail []byte) error {
+	s.buf.Write(tail)
+	_, err := s.w.Write(s.buf.Bytes())
+	s.buf.Reset()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errStreamAborted, err)
+	}
+	return nil
+}
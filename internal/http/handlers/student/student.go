@@ -1,34 +1,59 @@
 package student
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/featureflag"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/paging"
 	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
 	"github.com/manish-npx/go-student-api/internal/types"
 	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/validate"
+	"github.com/manish-npx/go-student-api/internal/verification"
 )
 
-// 🧩 POST /api/student
+// 🧩 POST /api/student?validate_only=true
 // ---------------------------------------------------------
 // This handler creates a new student record.
 // 1. Validates HTTP method (must be POST)
 // 2. Decodes JSON body → types.Student
-// 3. Validates fields using go-playground/validator
-// 4. Calls `storage.CreateStudent()` to persist the record
-// 5. Responds with JSON containing success info
-func New(storage storage.Storage) http.HandlerFunc {
+// 3. Validates fields using go-playground/validator; age is derived from date_of_birth
+// and the email's domain is checked (422 when turned away, MX lookup included)
+// 4. With `validate_only=true`, checks the email is free and answers 200 without writing
+// 5. Calls `storage.CreateStudent()` to persist the record and its student.created event
+// 6. Queues the welcome email and, when enabled, the verification link (async)
+// 7. Responds with JSON containing success info and the student's _links
+func New(storage storage.Storage, custom *customfield.Registry, notifier *notify.Notifier, verifier *verification.Verifier, links *links.Builder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
 
 		// ✅ Ensure correct HTTP method
 		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			response.WriteJson(w, http.StatusMethodNotAllowed, response.GeneralError(errors.New("method not allowed")))
+			return
+		}
+
+		query := bind.NewQuery(r)
+		checkOnly := query.Bool("validate_only", false)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
 			return
 		}
 
@@ -49,35 +74,46 @@ func New(storage storage.Storage) http.HandlerFunc {
 
 		// 🧩 Request validation
 		// Uses struct tags in `types.Student` (e.g., validate:"required")
-		if err := validator.New().Struct(student); err != nil {
-			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+		if !validateStudent(w, storage, custom, &student) {
+			return
+		}
+		if !checkEmailMX(w, r, student.Email) {
+			return
+		}
+		if checkOnly {
+			validateOnly(w, storage, student, 0)
 			return
 		}
 
 		// 💾 Insert student into DB via storage layer
-		lastId, err := storage.CreateStudent(
-			student.Name,
-			student.Email,
-			student.Age,
-		)
+		lastId, err := storage.CreateStudent(student)
 		if err != nil {
 			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
 			return
 		}
 
+		// 🆔 Read back what storage filled in (public_id); input can't set it
+		student.ID, student.PublicID = lastId, ""
+		if created, err := storage.GetStudentById(lastId); err == nil {
+			student = created
+		}
+
+		// 📧 Welcome email goes through the notifier's worker queue
+		notifier.Welcome(r.Context(), student)
+		sendVerification(r, storage, verifier, student)
+
 		// 📦 Build success response payload
 		data := map[string]any{
 			"success": true,
 			"id":      lastId,
-			"student": student,
+			"student": links.Student(student),
 			"message": "Student record created successfully",
 		}
 
-		// 🪵 Log structured info about the new record
+		// 🪵 Log which record was created; name and email stay out of the logs
 		slog.Info("Creating student record",
-			slog.String("name", student.Name),
-			slog.String("email", student.Email),
 			slog.Int64("id", lastId),
+			slog.String("public_id", student.PublicID),
 		)
 
 		// 🚀 Send response
@@ -91,9 +127,12 @@ func New(storage storage.Storage) http.HandlerFunc {
 // 1. Extracts `id` path param
 // 2. Converts string → int64
 // 3. Calls `storage.GetStudentById()`
-// 4. Returns the record in JSON
-func GetById(storage storage.Storage) http.HandlerFunc {
+// 4. Returns the record in JSON, with _links to its related actions
+// 5. Embeds related resources listed in ?include=guardians,documents,invoices
+func GetById(storage storage.Storage, links *links.Builder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
 		id := r.PathValue("id")
 		slog.Info("Getting a student record", slog.String("id", id))
 
@@ -104,55 +143,302 @@ func GetById(storage storage.Storage) http.HandlerFunc {
 			return
 		}
 
+		query := bind.NewQuery(r)
+		fields := query.Fields("fields", studentFields...)
+		include := query.Fields("include", includable...)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
 		// 💾 Fetch record from DB
-		student, err := storage.GetStudentById(intId64)
+		student, err := loadStudent(storage, intId64, fields)
+		if isStudentNotFound(err) {
+			// the id stays out of the body: it may have come in as a hashid
+			errStudentNotFound.Write(w)
+			return
+		}
 		if err != nil {
 			slog.Error("Error getting student record", slog.String("error", err.Error()))
 			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
 			return
 		}
 
+		// 👪 Related resources
+		inc, err := loadIncluded(storage, include, []types.Student{student})
+		if err != nil {
+			includeFailed(w, err)
+			return
+		}
+
 		// 🚀 Respond with found record
-		response.WriteJson(w, http.StatusOK, student)
+		if fields != nil {
+			response.WriteJson(w, http.StatusOK, inc.project(project(student, fields), student.ID))
+			return
+		}
+		resource := links.Student(student)
+		inc.embed(&resource)
+		response.WriteJson(w, http.StatusOK, resource)
 	}
 }
 
 // 🧩 GET /api/students
 // ---------------------------------------------------------
 // Fetches all student records.
-// 1. Calls `storage.GetStudents()`
-// 2. Returns array of students as JSON
-func GetList(storage storage.Storage) http.HandlerFunc {
+// 1. With ?ids=1,2,3, returns just those students (see getBatch)
+// 2. With ?limit= or ?cursor=, returns one keyset page (see getPage)
+// 3. Otherwise calls `storage.GetStudents()`, ordered by ?sort=name|-age|...
+// 4. Refuses (400) an unpaged list longer than pagination.max_limit
+// 5. Returns array of students as JSON, trimmed to ?fields=id,name,... if given
+// 6. Pages and lists narrow to ?custom.<key>=<value> filters when given
+// 7. Pages and lists embed related resources listed in ?include=
+// 8. With Accept: application/x-ndjson, streams every student (see streamStudents);
+// callers outside the students.ndjson flag get 406
+// 9. With ?verified=true|false, lists or pages students by verification (see getVerified)
+// 10. Lists and pages carry X-Total-Count, every matching student counted in
+// the database; HEAD answers with just that header (see headList)
+func GetList(storage storage.Storage, custom *customfield.Registry, links *links.Builder, limits *paging.Limits, flags *featureflag.Set) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// 🔢 One copy of the limits for the whole request; reloads swap them
+		paging := limits.Get()
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		filter, ok := customFilter(w, r, storage, custom)
+		if !ok {
+			return
+		}
+
+		if r.Method == http.MethodHead {
+			headList(w, r, storage, filter)
+			return
+		}
+
+		query := bind.NewQuery(r)
+		if query.Has("verified") {
+			if filter != nil || query.Has("ids") || response.WantsNDJSON(r.Header.Get("Accept")) {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("verified cannot be combined with ids, custom filters or NDJSON")))
+				return
+			}
+			getVerified(w, r, storage, links, paging)
+			return
+		}
+		if response.WantsNDJSON(r.Header.Get("Accept")) {
+			if !flags.Enabled(featureflag.StudentsNDJSON, middleware.FlagCaller(r.Context())) {
+				response.WriteJson(w, http.StatusNotAcceptable, response.GeneralError(errors.New("NDJSON streaming is not enabled for this caller, ask for application/json")))
+				return
+			}
+			fields := query.Fields("fields", studentFields...)
+			include := query.Fields("include", includable...)
+			if query.Has("ids") || query.Has("limit") || query.Has("cursor") || query.Has("sort") {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("NDJSON streams every student in id order; ids, limit, cursor and sort don't apply")))
+				return
+			}
+			if err := query.Err(); err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+				return
+			}
+			streamStudents(w, r, storage, links, filter, fields, include)
+			return
+		}
+		if query.Has("ids") {
+			ids := query.IDs("ids", maxBatchSize)
+			include := query.Fields("include", includable...)
+			if filter != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("ids cannot be combined with custom filters")))
+				return
+			}
+			if query.Has("limit") || query.Has("cursor") || query.Has("sort") {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("ids cannot be combined with limit, cursor or sort")))
+				return
+			}
+			if err := query.Err(); err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+				return
+			}
+			getBatch(w, storage, links, ids, include)
+			return
+		}
+		if query.Has("limit") || query.Has("cursor") {
+			getPage(w, r, storage, links, paging, filter)
+			return
+		}
+		order := query.Sort("sort", bind.Sort{Field: "id"}, "id", "name", "email", "age")
+		fields := query.Fields("fields", studentFields...)
+		include := query.Fields("include", includable...)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+		// 🌊 In id order nothing needs sorting: stream rows as they are read
+		if order == (bind.Sort{Field: "id"}) && streamList(w, r, storage, links, paging, filter, fields, include) {
+			return
+		}
 		slog.Info("Getting all student records")
 
-		// 💾 Retrieve all students from DB
-		students, err := storage.GetStudents()
+		// 💾 Retrieve all students from DB (plus the sort key, if not selected)
+		var students []types.Student
+		var err error
+		if filter != nil {
+			students, err = storage.(storageCustomFilter).GetStudentsWhere(filter, 0, 0)
+		} else {
+			students, err = loadStudents(storage, withField(fields, order.Field))
+		}
 		if err != nil {
 			slog.Error("Error getting students", slog.String("error", err.Error()))
 			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
 			return
 		}
+		if len(students) > paging.MaxLimit {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("more than %d students, page through them with ?limit and ?cursor", paging.MaxLimit)))
+			return
+		}
+		sortStudents(students, order)
+
+		// 👪 Related resources, one query each for the whole list
+		inc, err := loadIncluded(storage, include, students)
+		if err != nil {
+			includeFailed(w, err)
+			return
+		}
+		if !setTotalCount(w, storage, filter, nil) {
+			return
+		}
 
 		// 🚀 Send JSON list
-		response.WriteJson(w, http.StatusOK, students)
+		if fields != nil {
+			response.WriteJson(w, http.StatusOK, inc.projectAll(projectAll(students, fields), students))
+			return
+		}
+		response.WriteJson(w, http.StatusOK, inc.embedAll(links.Students(students)))
 	}
 }
 
-// 🧩 PUT /api/student/{id}
+// -------------------------------------------------------------
+// getPage() → GET /api/students?limit=20&cursor=<next_cursor>
+// -------------------------------------------------------------
+// Keyset pagination on id: unlike offsets, concurrent inserts and
+// deletes never make a client skip or repeat rows. prev/last cursors
+// walk backwards when the backend supports it.
+func getPage(w http.ResponseWriter, r *http.Request, store storage.Storage, links *links.Builder, paging config.Pagination, filter map[string]any) {
+	pages, ok := storage.As[storage.PageStore](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("cursor pagination not supported by this storage backend")))
+		return
+	}
+	reverse, canReverse := storage.As[storage.ReversePageStore](store)
+	if filter != nil {
+		// filtered pages only walk forwards
+		canReverse = false
+	}
+
+	// 🔢 Parse limit (pagination.default_limit / max_limit)
+	query := bind.NewQuery(r)
+	limit := query.Int("limit", paging.DefaultLimit, 1, paging.MaxLimit)
+	fields := query.Fields("fields", studentFields...)
+	include := query.Fields("include", includable...)
+	if query.Has("sort") {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("sort cannot be combined with limit/cursor (pages are ordered by id)")))
+		return
+	}
+	if err := query.Err(); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return
+	}
+
+	at, err := storage.DecodeCursor(query.String("cursor", ""))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return
+	}
+	if at.Before && !canReverse {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("backward pagination not supported by this storage backend")))
+		return
+	}
+
+	slog.Info("Getting a page of student records", slog.Int64("cursor_id", at.ID), slog.Bool("before", at.Before), slog.Int("limit", limit))
+
+	// 💾 Fetch one extra row to learn whether another page exists
+	var students []types.Student
+	if at.Before {
+		students, err = reverse.GetStudentsBefore(at.ID, limit+1)
+	} else if filter != nil {
+		students, err = store.(storage.CustomFilterStore).GetStudentsWhere(filter, at.ID, limit+1)
+	} else {
+		students, err = loadStudentsAfter(store, pages, at.ID, limit+1, fields)
+	}
+	if err != nil {
+		slog.Error("Error getting students page", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return
+	}
+
+	// 🔗 Page links; prev/last only when the backend can walk backwards
+	page := storage.BuildPage(students, limit, at)
+	last := ""
+	if canReverse {
+		last = storage.LastCursor()
+	} else {
+		page.PrevCursor = ""
+	}
+	pageLinks := links.Page(r, page.NextCursor, page.PrevCursor, last)
+
+	// 👪 Related resources, one query each for the whole page
+	inc, err := loadIncluded(store, include, page.Data)
+	if err != nil {
+		includeFailed(w, err)
+		return
+	}
+	if !setTotalCount(w, store, filter, nil) {
+		return
+	}
+
+	// 🚀 Send page with opaque cursors
+	if fields != nil {
+		response.WriteJson(w, http.StatusOK, types.Page[map[string]any]{
+			Data:       inc.projectAll(projectAll(page.Data, fields), page.Data),
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+			HasMore:    page.HasMore,
+			Limit:      page.Limit,
+			Links:      pageLinks,
+		})
+		return
+	}
+	response.WriteJson(w, http.StatusOK, types.Page[types.StudentResource]{
+		Data:       inc.embedAll(links.Students(page.Data)),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		HasMore:    page.HasMore,
+		Limit:      page.Limit,
+		Links:      pageLinks,
+	})
+}
+
+// 🧩 PUT /api/student/{id}?validate_only=true
 // ---------------------------------------------------------
 // This handler update creates a new student record.
 // 1. Validates HTTP method (must be PUT)
 // 2. Decodes JSON body → types.Student
-// 3. Validates fields using go-playground/validator
+// 3. Validates fields using go-playground/validator; age is derived from date_of_birth
+// 4. With `validate_only=true`, checks the student exists and the email is free, and answers 200 without writing
 
-func UpdateById(storage storage.Storage) http.HandlerFunc {
+func UpdateById(storage storage.Storage, custom *customfield.Registry, links *links.Builder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
 		slog.Info("Update student record based on Id")
 
 		// ✅ Ensure correct HTTP method
 		if r.Method != http.MethodPut {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			response.WriteJson(w, http.StatusMethodNotAllowed, response.GeneralError(errors.New("method not allowed")))
+			return
+		}
+
+		query := bind.NewQuery(r)
+		checkOnly := query.Bool("validate_only", false)
+		if err := query.Err(); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
 			return
 		}
 
@@ -180,13 +466,33 @@ func UpdateById(storage storage.Storage) http.HandlerFunc {
 			return
 		}
 
+		// 🧩 Request validation
+		if !validateStudent(w, storage, custom, &student) {
+			return
+		}
+		if checkOnly {
+			if _, err := storage.GetStudentById(intId64); isStudentNotFound(err) {
+				errStudentNotFound.Write(w)
+				return
+			} else if err != nil {
+				slog.Error("Error getting student", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+				return
+			}
+			validateOnly(w, storage, student, intId64)
+			return
+		}
+
 		// 💾 Retrieve all students from DB
-		lastId, err := storage.UpdateStudentById(
-			intId64,
-			student.Name,
-			student.Email,
-			student.Age,
-		)
+		lastId, err := storage.UpdateStudentById(intId64, student)
+		if isStudentNotFound(err) {
+			errStudentNotFound.Write(w)
+			return
+		}
+		if isEmailTaken(err) {
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(fmt.Errorf("email %s already exists", student.Email)))
+			return
+		}
 		if err != nil {
 			slog.Error("Error getting students", slog.String("error", err.Error()))
 			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
@@ -197,17 +503,262 @@ func UpdateById(storage storage.Storage) http.HandlerFunc {
 		data := map[string]any{
 			"success": true,
 			"id":      lastId.ID,
-			"student": student,
+			"student": links.Student(lastId),
 			"message": "Student record created successfully",
 		}
 
 		// 🪵 Log structured info about the new record
 		slog.Info("Updated student record",
-			slog.String("name", student.Name),
-			slog.String("email", student.Email),
+			slog.Int64("id", lastId.ID),
+			slog.String("public_id", lastId.PublicID),
 		)
 
 		// 🚀 Send response
 		response.WriteJson(w, http.StatusOK, data)
 	}
 }
+
+// 🧩 DELETE /api/student/{id}
+// ---------------------------------------------------------
+// Soft-deletes a student: it disappears from reads but stays in the trash
+// until restored or purged.
+// 1. Extracts `id` path param
+// 2. Calls `storage.DeleteStudentById()`, which records student.deleted
+func DeleteById(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		id := r.PathValue("id")
+		slog.Info("Deleting a student record", slog.String("id", id))
+
+		// 🔢 Convert id from string → int64
+		intId64, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+			return
+		}
+
+		// 💾 Soft delete
+		if err := storage.DeleteStudentById(intId64); err != nil {
+			slog.Error("Error deleting student", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      intId64,
+			"message": "Student record moved to trash",
+		})
+	}
+}
+
+// 🧩 GET /api/students/trash
+// ---------------------------------------------------------
+// Lists soft-deleted students of the caller's tenant.
+func GetTrash(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		trash, ok := storageAs[storageTrash](storage)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(fmt.Errorf("trash not supported by this storage backend")))
+			return
+		}
+
+		// 💾 Retrieve soft-deleted students
+		students, err := trash.GetDeletedStudents()
+		if err != nil {
+			slog.Error("Error getting deleted students", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send JSON list
+		response.WriteJson(w, http.StatusOK, students)
+	}
+}
+
+// 🧩 POST /api/student/{id}/restore
+// ---------------------------------------------------------
+// Brings a soft-deleted student back.
+// 1. Extracts `id` path param
+// 2. Calls `storage.RestoreStudentById()`, which records student.restored
+// 3. Returns the restored record
+func RestoreById(storage storage.Storage, links *links.Builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		trash, ok := storageAs[storageTrash](storage)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(fmt.Errorf("trash not supported by this storage backend")))
+			return
+		}
+
+		id := r.PathValue("id")
+		slog.Info("Restoring a student record", slog.String("id", id))
+
+		// 🔢 Convert id from string → int64
+		intId64, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+			return
+		}
+
+		// 💾 Restore
+		student, err := trash.RestoreStudentById(intId64)
+		if err != nil {
+			slog.Error("Error restoring student", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      student.ID,
+			"student": links.Student(student),
+			"message": "Student record restored",
+		})
+	}
+}
+
+// -------------------------------------------------------------
+// customFilter() → ?custom.<key>=<value> parameters, nil when there are none
+// -------------------------------------------------------------
+// Writes the error itself: 501 when the backend can't filter, 400 for
+// unknown keys or values that don't parse as the field's type.
+func customFilter(w http.ResponseWriter, r *http.Request, store storage.Storage, custom *customfield.Registry) (map[string]any, bool) {
+	params := r.URL.Query()
+	if !customfield.HasFilter(params) {
+		return nil, true
+	}
+	if _, ok := storage.As[storage.CustomFilterStore](store); !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("custom field filters not supported by this storage backend")))
+		return nil, false
+	}
+
+	defs, err := custom.Definitions(store)
+	if err != nil {
+		slog.Error("Error loading custom fields", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return nil, false
+	}
+	filter, err := customfield.Filter(defs, params)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return nil, false
+	}
+	return filter, true
+}
+
+// validateStudent runs validate.Student against the tenant's custom
+// field definitions: 400, or 422 for an email the policy turns away.
+// Writes the error itself.
+func validateStudent(w http.ResponseWriter, store storage.Storage, custom *customfield.Registry, student *types.Student) bool {
+	defs, err := custom.Definitions(store)
+	if err != nil {
+		slog.Error("Error loading custom fields", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return false
+	}
+	if err := validate.Student(defs, student); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, validate.ErrEmailRejected) {
+			status = http.StatusUnprocessableEntity
+		}
+		response.WriteJson(w, status, response.GeneralError(err))
+		return false
+	}
+	return true
+}
+
+// checkEmailMX runs validate.EmailMX on a new student's email (422 when
+// its domain can't receive mail). Writes the error itself.
+func checkEmailMX(w http.ResponseWriter, r *http.Request, email string) bool {
+	if err := validate.EmailMX(r.Context(), email); err != nil {
+		response.WriteJson(w, http.StatusUnprocessableEntity, response.GeneralError(err))
+		return false
+	}
+	return true
+}
+
+// validateOnly finishes a `validate_only=true` create or update whose body
+// passed validation: it runs the uniqueness check the write would hit and
+// answers the record as it would be stored. Nothing is written and no
+// event is recorded. Writes the response itself.
+func validateOnly(w http.ResponseWriter, store storage.Storage, student types.Student, id int64) {
+	uniqueness, ok := storageAs[storageUniqueness](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("validate_only not supported by this storage backend")))
+		return
+	}
+	taken, err := uniqueness.EmailTaken(student.Email, id)
+	if err != nil {
+		slog.Error("Error checking email", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return
+	}
+	if taken {
+		response.WriteJson(w, http.StatusConflict, response.GeneralError(fmt.Errorf("email %s already exists", student.Email)))
+		return
+	}
+
+	student.ID = id
+	response.WriteJson(w, http.StatusOK, map[string]any{
+		"success": true,
+		"valid":   true,
+		"student": student,
+		"message": "Student record is valid; nothing was saved",
+	})
+}
+
+// sortStudents orders the list in place; ties keep id order
+func sortStudents(students []types.Student, order bind.Sort) {
+	slices.SortStableFunc(students, func(a, b types.Student) int {
+		if order.Desc {
+			a, b = b, a
+		}
+		switch order.Field {
+		case "name":
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case "email":
+			return strings.Compare(strings.ToLower(a.Email), strings.ToLower(b.Email))
+		case "age":
+			return cmp.Compare(a.Age, b.Age)
+		default:
+			return cmp.Compare(a.ID, b.ID)
+		}
+	})
+}
+
+// Handlers name their parameter `storage`, which shadows the package
+type (
+	storageTrash        = storage.TrashStore
+	storageUpsert       = storage.UpsertStore
+	storagePrivacy      = storage.PrivacyStore
+	storageDocuments    = storage.DocumentStore
+	storageInvoices     = storage.InvoiceStore
+	storageGuardians    = storage.GuardianStore
+	storageCustomFilter = storage.CustomFilterStore
+	storageBulk         = storage.BulkStore
+	storageUniqueness   = storage.UniquenessStore
+)
+
+// storageAs is storage.As
+func storageAs[T any](s storage.Storage) (T, bool) {
+	return storage.As[T](s)
+}
+
+var (
+	studentFields                 = storage.StudentFields
+	storageErrStudentTrashed      = storage.ErrStudentTrashed
+	storageErrVerificationInvalid = storage.ErrVerificationInvalid
+	storageErrVerificationExpired = storage.ErrVerificationExpired
+)
+
+const maxBatchSize = storage.MaxBatchSize
@@ -0,0 +1,99 @@
+package student
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/verification"
+)
+
+// 🧩 PUT /api/students/by-email/{email}
+// ---------------------------------------------------------
+// Creates or updates the student with this email (roster sync).
+// 1. Decodes JSON body → types.Student; a body email must match the path
+// 2. Validates fields using go-playground/validator; age is derived from date_of_birth
+// 3. Calls `UpsertStudentByEmail()`: one INSERT ... ON CONFLICT statement, which records student.created or student.updated
+// 4. New students get the welcome email and, when enabled, a verification link
+// 5. Responds 201 (created) or 200 (updated) with `created` and the record
+func UpsertByEmail(storage storage.Storage, custom *customfield.Registry, notifier *notify.Notifier, verifier *verification.Verifier, links *links.Builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		upserts, ok := storageAs[storageUpsert](storage)
+		if !ok {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("upsert not supported by this storage backend")))
+			return
+		}
+
+		email := r.PathValue("email")
+		var student types.Student
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&student)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+		if student.Email != "" && !strings.EqualFold(student.Email, email) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("body email %s does not match %s in the path", student.Email, email)))
+			return
+		}
+		student.Email = email
+
+		// 🧩 Request validation
+		if !validateStudent(w, storage, custom, &student) {
+			return
+		}
+
+		// 💾 Insert or update in one statement
+		saved, created, err := upserts.UpsertStudentByEmail(student)
+		if errors.Is(err, storageErrStudentTrashed) {
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(fmt.Errorf("%w; restore it first", err)))
+			return
+		}
+		if err != nil {
+			slog.Error("Error upserting student", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		status, message := http.StatusOK, "Student record updated successfully"
+		if created {
+			notifier.Welcome(r.Context(), saved)
+			sendVerification(r, storage, verifier, saved)
+			status, message = http.StatusCreated, "Student record created successfully"
+		}
+
+		slog.Info("Upserted student record",
+			slog.Int64("id", saved.ID),
+			slog.String("public_id", saved.PublicID),
+			slog.Bool("created", created),
+		)
+
+		// 🚀 Send response
+		response.WriteJson(w, status, map[string]any{
+			"success": true,
+			"id":      saved.ID,
+			"created": created,
+			"student": links.Student(saved),
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package student
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/verification"
+)
+
+// 🧩 GET /api/verify?token=<token>
+// ---------------------------------------------------------
+// The link emailed to new students. Public and not tenant-scoped: the
+// token alone finds the student.
+// 1. Uses up the token and sets the student's verified flag
+// 2. 404 for unknown or used links, 410 for expired ones
+func Verify(storage storage.Storage, verifier *verification.Verifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if verifier == nil {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("email verification is disabled (set verification.enabled)")))
+			return
+		}
+
+		token, err := verifier.Verify(storage, r.URL.Query().Get("token"))
+		switch {
+		case errors.Is(err, storageErrVerificationInvalid):
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		case errors.Is(err, storageErrVerificationExpired):
+			response.WriteJson(w, http.StatusGone, response.GeneralError(err))
+			return
+		case errors.Is(err, verification.ErrUnsupported):
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+			return
+		case err != nil:
+			slog.Error("Error verifying student email", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		slog.Info("Student email verified", slog.Int64("id", token.StudentID), slog.Int64("tenant_id", token.TenantID))
+		response.WriteJson(w, http.StatusOK, map[string]any{
+			"success": true,
+			"id":      token.StudentID,
+			"message": "Email address verified, thank you",
+		})
+	}
+}
+
+// 🧩 POST /api/student/{id}/verify
+// ---------------------------------------------------------
+// Sends the student a new verification link, e.g. after theirs expired
+// or their email changed. Earlier links stop working.
+// 1. Extracts `id` path param and loads the student
+// 2. 409 when the student is already verified
+// 3. Stores a new token and queues the email; answers 202
+func ResendVerification(storage storage.Storage, verifier *verification.Verifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🏫 Restrict every query to the caller's tenant
+		storage := tenant.Scope(r.Context(), storage)
+
+		if verifier == nil {
+			response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("email verification is disabled (set verification.enabled)")))
+			return
+		}
+
+		// 🔢 Convert id from string → int64
+		id := r.PathValue("id")
+		intId64, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %v", id)))
+			return
+		}
+
+		student, err := storage.GetStudentById(intId64)
+		if err != nil {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+		if student.Verified {
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(fmt.Errorf("student %d is already verified", student.ID)))
+			return
+		}
+
+		if err := verifier.Send(r.Context(), storage, student); err != nil {
+			slog.Error("Error sending verification email", slog.String("error", err.Error()))
+			status := http.StatusInternalServerError
+			if errors.Is(err, verification.ErrUnsupported) {
+				status = http.StatusNotImplemented
+			}
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		// 🚀 Send response
+		response.WriteJson(w, http.StatusAccepted, map[string]any{
+			"success": true,
+			"id":      student.ID,
+			"message": "Verification email sent",
+		})
+	}
+}
+
+// sendVerification queues the link for a just-created student. The
+// student is saved either way, so failures are only logged.
+func sendVerification(r *http.Request, store storage.Storage, verifier *verification.Verifier, student types.Student) {
+	if err := verifier.Send(r.Context(), store, student); err != nil {
+		slog.Error("Error sending verification email", slog.Int64("id", student.ID), slog.String("error", err.Error()))
+	}
+}
+
+// -------------------------------------------------------------
+// getVerified() → GET /api/students?verified=true|false[&limit=20&cursor=...]
+// -------------------------------------------------------------
+// Students by verification status, ordered by id. Pages only walk
+// forwards; without limit/cursor it's the whole list, up to
+// pagination.max_limit like the unfiltered one.
+func getVerified(w http.ResponseWriter, r *http.Request, store storage.Storage, links *links.Builder, paging config.Pagination) {
+	verifications, ok := storage.As[storage.VerificationStore](store)
+	if !ok {
+		response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(verification.ErrUnsupported))
+		return
+	}
+
+	query := bind.NewQuery(r)
+	verified := query.Bool("verified", false)
+	paged := query.Has("limit") || query.Has("cursor")
+	limit := query.Int("limit", paging.DefaultLimit, 1, paging.MaxLimit)
+	fields := query.Fields("fields", studentFields...)
+	include := query.Fields("include", includable...)
+	if query.Has("sort") {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("sort cannot be combined with verified (students are ordered by id)")))
+		return
+	}
+	if err := query.Err(); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return
+	}
+	at, err := storage.DecodeCursor(query.String("cursor", ""))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return
+	}
+	if at.Before {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("verified lists only page forwards")))
+		return
+	}
+
+	slog.Info("Getting student records by verification", slog.Bool("verified", verified), slog.Int64("cursor_id", at.ID))
+
+	// 💾 Fetch one extra row to learn whether another page exists
+	fetch := limit + 1
+	if !paged {
+		fetch = 0
+	}
+	students, err := verifications.GetStudentsByVerified(verified, at.ID, fetch)
+	if err != nil {
+		slog.Error("Error getting students", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+		return
+	}
+	if !setTotalCount(w, store, nil, &verified) {
+		return
+	}
+
+	if !paged {
+		if len(students) > paging.MaxLimit {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("more than %d students, page through them with ?limit and ?cursor", paging.MaxLimit)))
+			return
+		}
+		inc, err := loadIncluded(store, include, students)
+		if err != nil {
+			includeFailed(w, err)
+			return
+		}
+		if fields != nil {
+			response.WriteJson(w, http.StatusOK, inc.projectAll(projectAll(students, fields), students))
+			return
+		}
+		response.WriteJson(w, http.StatusOK, inc.embedAll(links.Students(students)))
+		return
+	}
+
+	page := storage.BuildPage(students, limit, at)
+	page.PrevCursor = ""
+	pageLinks := links.Page(r, page.NextCursor, "", "")
+
+	inc, err := loadIncluded(store, include, page.Data)
+	if err != nil {
+		includeFailed(w, err)
+		return
+	}
+	if fields != nil {
+		response.WriteJson(w, http.StatusOK, types.Page[map[string]any]{
+			Data:       inc.projectAll(projectAll(page.Data, fields), page.Data),
+			NextCursor: page.NextCursor,
+			HasMore:    page.HasMore,
+			Limit:      page.Limit,
+			Links:      pageLinks,
+		})
+		return
+	}
+	response.WriteJson(w, http.StatusOK, types.Page[types.StudentResource]{
+		Data:       inc.embedAll(links.Students(page.Data)),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+		Limit:      page.Limit,
+		Links:      pageLinks,
+	})
+}
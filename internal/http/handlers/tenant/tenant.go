@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 POST /api/admin/tenants
+// ---------------------------------------------------------
+// Registers a new tenant (school).
+// 1. Decodes JSON body → types.Tenant
+// 2. Validates slug/name
+// 3. Calls `tenants.CreateTenant()`
+func New(tenants storage.TenantStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var tenant types.Tenant
+
+		// 🧠 Decode request body JSON → Go struct
+		err := json.NewDecoder(r.Body).Decode(&tenant)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid JSON: %v", err)))
+			return
+		}
+
+		// 🧩 Request validation
+		if err := validator.New().Struct(tenant); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+			return
+		}
+
+		// 💾 Insert tenant
+		id, err := tenants.CreateTenant(tenant.Slug, tenant.Name)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+		tenant.ID = id
+
+		slog.Info("Created tenant", slog.String("slug", tenant.Slug), slog.Int64("id", id))
+
+		response.WriteJson(w, http.StatusCreated, tenant)
+	}
+}
+
+// 🧩 GET /api/admin/tenants
+// ---------------------------------------------------------
+// Lists all tenants.
+func GetList(tenants storage.TenantStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := tenants.GetTenants()
+		if err != nil {
+			slog.Error("Error getting tenants", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, list)
+	}
+}
@@ -0,0 +1,34 @@
+package version
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/buildinfo"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 GET /api/version
+// ---------------------------------------------------------
+// What is deployed, for operators checking a rollout.
+// 1. Version, commit and build time stamped at build (see buildinfo)
+// 2. The storage backend (db_type) and its last applied migration
+func Get(store storage.Storage, driver string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		info := buildinfo.Get()
+		info.Storage = driver
+
+		if schema, ok := storage.As[storage.SchemaStore](store); ok {
+			version, err := schema.SchemaVersion()
+			if err != nil {
+				slog.Error("Error reading schema version", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+				return
+			}
+			info.SchemaVersion = version
+		}
+
+		response.WriteJson(w, http.StatusOK, info)
+	}
+}
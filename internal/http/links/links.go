@@ -0,0 +1,92 @@
+// Package links builds the HATEOAS `_links` of API responses. Every href is
+// prefixed with http_server.base_url (relative when unset), so clients can
+// follow them as-is instead of assembling URLs.
+package links
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Builder turns API paths into links under one base URL.
+type Builder struct {
+	base string
+}
+
+func New(baseURL string) *Builder {
+	return &Builder{base: strings.TrimRight(baseURL, "/")}
+}
+
+// -------------------------------------------------------------
+// Href() → base URL + path (+ ?query when not empty)
+// -------------------------------------------------------------
+func (b *Builder) Href(path string, query url.Values) string {
+	href := b.base + path
+	if encoded := query.Encode(); encoded != "" {
+		href += "?" + encoded
+	}
+	return href
+}
+
+// -------------------------------------------------------------
+// Student() → The record plus what a client can do with it next
+// -------------------------------------------------------------
+func (b *Builder) Student(student types.Student) types.StudentResource {
+	self := b.Href(fmt.Sprintf("/api/student/%d", student.ID), nil)
+	return types.StudentResource{
+		Student: student,
+		Links: types.Links{
+			"self":      {Href: self},
+			"update":    {Href: self, Method: http.MethodPut},
+			"delete":    {Href: self, Method: http.MethodDelete},
+			"documents": {Href: self + "/documents"},
+			"guardians": {Href: self + "/guardians"},
+			"invoices":  {Href: self + "/invoices"},
+			"photo":     {Href: self + "/photo"},
+		},
+	}
+}
+
+func (b *Builder) Students(students []types.Student) []types.StudentResource {
+	out := make([]types.StudentResource, 0, len(students))
+	for _, student := range students {
+		out = append(out, b.Student(student))
+	}
+	return out
+}
+
+// -------------------------------------------------------------
+// Page() → self / first / next / prev / last links of a cursor page
+// -------------------------------------------------------------
+// The request's other parameters (limit, fields, ...) are carried over;
+// only ?cursor= changes. last is left out when the backend can't page
+// backwards (lastCursor == "").
+func (b *Builder) Page(r *http.Request, next, prev, lastCursor string) types.Links {
+	at := func(cursor string) types.Link {
+		query := r.URL.Query()
+		query.Del("cursor")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		return types.Link{Href: b.Href(r.URL.Path, query)}
+	}
+
+	links := types.Links{
+		"self":  {Href: b.Href(r.URL.Path, r.URL.Query())},
+		"first": at(""),
+	}
+	if next != "" {
+		links["next"] = at(next)
+	}
+	if prev != "" {
+		links["prev"] = at(prev)
+	}
+	if lastCursor != "" {
+		links["last"] = at(lastCursor)
+	}
+	return links
+}
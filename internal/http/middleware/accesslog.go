@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+// clfTime is the timestamp layout of Apache's %t
+const clfTime = "02/Jan/2006:15:04:05 -0700"
+
+// 🧩 AccessLog writes one line per request to out, in format
+// ---------------------------------------------------------
+// 1. Runs the handler, counting the status and the body bytes sent
+// 2. json: a slog JSON record with the request id, duration and headers
+// 3. common: Apache's Common Log Format
+//
+//	127.0.0.1 - key:3 [16/Oct/2026:13:55:36 +0200] "GET /api/students HTTP/1.1" 200 2326
+//
+// 4. combined: common plus the quoted Referer and User-Agent
+//
+// The user is the subject Auth charged the request to ("-" for none).
+// Mount it outermost, so rejected requests are logged and the bytes are
+// the compressed ones that went out.
+func AccessLog(format string, out io.Writer) func(http.Handler) http.Handler {
+	write := func(r *http.Request, e *accessEntry) {
+		line := e.common(r)
+		if format == "combined" {
+			line = e.combined(line, r)
+		}
+		out.Write(append(line, '\n'))
+	}
+	if format == "json" {
+		log := slog.New(slog.NewJSONHandler(out, nil))
+		write = func(r *http.Request, e *accessEntry) {
+			log.Info("access",
+				slog.String("request_id", e.w.Header().Get(httpclient.HeaderRequestID)),
+				slog.String("remote_addr", remoteHost(r)),
+				slog.String("user", e.user),
+				slog.String("method", r.Method),
+				slog.String("uri", r.RequestURI),
+				slog.String("proto", r.Proto),
+				slog.Int("status", e.w.status),
+				slog.Int64("bytes", e.w.bytes),
+				slog.Duration("duration", time.Since(e.start)),
+				slog.String("referer", r.Referer()),
+				slog.String("user_agent", r.UserAgent()),
+			)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			e := &accessEntry{start: time.Now(), w: &accessWriter{ResponseWriter: w, status: http.StatusOK, head: r.Method == http.MethodHead}}
+			next.ServeHTTP(e.w, r.WithContext(context.WithValue(r.Context(), accessKey{}, e)))
+			write(r, e)
+		})
+	}
+}
+
+type accessKey struct{}
+
+// accessEntry is what AccessLog learns about a request while it's served
+type accessEntry struct {
+	start time.Time
+	w     *accessWriter
+	user  string
+}
+
+// noteAccessUser hands the subject Auth resolved out to AccessLog
+func noteAccessUser(ctx context.Context, subject string) {
+	if e, ok := ctx.Value(accessKey{}).(*accessEntry); ok {
+		e.user = subject
+	}
+}
+
+// common renders the entry as a Common Log Format line
+func (e *accessEntry) common(r *http.Request) []byte {
+	line := make([]byte, 0, 256)
+	line = append(line, orDash(remoteHost(r))...)
+	line = append(line, " - "...)
+	line = append(line, orDash(clfEscape(e.user))...)
+	line = append(line, " ["...)
+	line = e.start.AppendFormat(line, clfTime)
+	line = append(line, `] "`...)
+	line = append(line, clfEscape(r.Method+" "+r.RequestURI+" "+r.Proto)...)
+	line = append(line, `" `...)
+	line = strconv.AppendInt(line, int64(e.w.status), 10)
+	line = append(line, ' ')
+	if e.w.bytes == 0 {
+		return append(line, '-')
+	}
+	return strconv.AppendInt(line, e.w.bytes, 10)
+}
+
+// combined appends the Referer and User-Agent to a common line
+func (e *accessEntry) combined(line []byte, r *http.Request) []byte {
+	line = append(line, ` "`...)
+	line = append(line, orDash(clfEscape(r.Referer()))...)
+	line = append(line, `" "`...)
+	line = append(line, orDash(clfEscape(r.UserAgent()))...)
+	return append(line, '"')
+}
+
+// remoteHost is the peer address of r without its port ("" on a unix socket)
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// clfEscape escapes quotes, backslashes and control bytes the way Apache
+// does, so a crafted header can't forge fields or lines
+func clfEscape(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= 0x7f || c == '"' || c == '\\' {
+			return clfEscapeFrom(s, i)
+		}
+	}
+	return s
+}
+
+func clfEscapeFrom(s string, i int) string {
+	buf := []byte(s[:i])
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case c < 0x20 || c >= 0x7f:
+			buf = fmt.Appendf(buf, `\x%02x`, c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf)
+}
+
+// accessWriter notes the status a handler answers and counts the body;
+// the server drops the body of a HEAD response, so that counts as none
+type accessWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	head        bool
+	bytes       int64
+}
+
+func (aw *accessWriter) WriteHeader(status int) {
+	// 1xx responses (Early Hints) are interim; the final status follows
+	if !aw.wroteHeader && status >= 200 {
+		aw.wroteHeader = true
+		aw.status = status
+	}
+	aw.ResponseWriter.WriteHeader(status)
+}
+
+func (aw *accessWriter) Write(p []byte) (int, error) {
+	aw.wroteHeader = true
+	n, err := aw.ResponseWriter.Write(p)
+	if !aw.head {
+		aw.bytes += int64(n)
+	}
+	return n, err
+}
+
+// Flush passes streaming flushes through
+func (aw *accessWriter) Flush() {
+	if f, ok := aw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the original writer to http.ResponseController
+func (aw *accessWriter) Unwrap() http.ResponseWriter {
+	return aw.ResponseWriter
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/featureflag"
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+	"github.com/manish-npx/go-student-api/internal/oidc"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/token"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// errInvalidAPIKey answers every unknown key, the most common rejection
+// when keys are being guessed
+var errInvalidAPIKey = response.NewStaticProblem(http.StatusUnauthorized, "invalid API key")
+
+// 🧩 Auth authenticates every request by bearer token or X-API-Key header
+// ---------------------------------------------------------
+// 1. `Authorization: Bearer` tokens: signature, expiry and revocation list
+// 2. Other bearer tokens are OIDC ID tokens, checked against the provider's JWKS
+// 3. Otherwise cfg.BootstrapKey (when set) is accepted with every scope
+// 4. Otherwise the key's hash is looked up; revoked or expired keys fail
+// 5. The token or key must grant the route's scope (apikey.RequiredScope
+// when the route declares none)
+// 6. Impersonation tokens act as their subject, with their own scopes and
+// tenant; each request is logged with the impersonating admin (Impersonator)
+// 7. Notes the credential's tenant (the key's, the token's tid, the OIDC
+// tenant claim) for Tenant to enforce; the bootstrap key reaches them all
+//
+// Missing or unusable credentials → 401, missing scope → 403. Routes
+// registered as public (/api/auth/*, the dashboard's static files) skip it;
+// Routes must run first.
+func Auth(cfg config.Auth, keys storage.APIKeyStore, tokens *token.Issuer, idp *oidc.Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, _ := registry.FromContext(r.Context())
+			if route.Public {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var scopes []string
+			var principal slog.Attr
+			var subject, impersonator string
+			var bound credentialTenant
+
+			if bearer, ok := BearerToken(r); ok && idp != nil && !token.IsLocal(bearer) {
+				id, err := idp.Verify(r.Context(), bearer)
+				if errors.Is(err, oidc.ErrInvalid) || errors.Is(err, oidc.ErrExpired) {
+					response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(err))
+					return
+				}
+				if err != nil {
+					slog.Error("Error verifying ID token", slog.String("error", err.Error()))
+					response.WriteJson(w, http.StatusServiceUnavailable, response.GeneralError(errors.New("identity provider unavailable")))
+					return
+				}
+				scopes, principal = id.Scopes, slog.String("subject", id.Subject)
+				subject = quota.UserSubject(id.Subject)
+				bound = credentialTenant{slug: id.Tenant}
+			} else if ok {
+				if tokens == nil {
+					response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(errors.New("bearer tokens are not enabled (auth.token_secret)")))
+					return
+				}
+				claims, err := tokens.Verify(bearer)
+				if err != nil {
+					response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(err))
+					return
+				}
+				scopes, principal = claims.Scopes, slog.Int64("key_id", claims.KeyID)
+				subject = quota.KeySubject(claims.KeyID)
+				// tokens issued before keys had tenants carry none: the default
+				bound = credentialTenant{id: cmp.Or(claims.TenantID, storage.DefaultTenantID)}
+				if claims.Impersonator != "" {
+					// 🏫 bound above, like any token: an impersonation reaches
+					// the tenant it was issued for and Tenant refuses the rest
+					if isStudentSubject(claims.Subject) {
+						response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(errors.New("this impersonation token is for the student portal (/api/me)")))
+						return
+					}
+					principal = slog.String("subject", claims.Subject)
+					subject, impersonator = claims.Subject, claims.Impersonator
+				}
+			} else {
+				presented := r.Header.Get(apikey.Header)
+				if presented == "" {
+					response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(
+						fmt.Errorf("missing credentials (set the %s header or a bearer token)", apikey.Header),
+					))
+					return
+				}
+
+				if cfg.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(cfg.BootstrapKey)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				key, err := keys.GetAPIKeyByHash(apikey.Hash(presented))
+				if err != nil {
+					errInvalidAPIKey.Write(w)
+					return
+				}
+				if err := apikey.Check(key, time.Now()); err != nil {
+					response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(err))
+					return
+				}
+				scopes, principal = key.Scopes, slog.Int64("key_id", key.ID)
+				subject = quota.KeySubject(key.ID)
+				bound = credentialTenant{id: key.TenantID}
+			}
+
+			scope := route.Scope
+			if scope == "" {
+				scope = apikey.RequiredScope(r)
+			}
+			if !apikey.Allows(scopes, scope) {
+				slog.Warn("Credentials lack scope",
+					principal,
+					slog.String("scope", scope),
+					slog.String("path", r.URL.Path),
+				)
+				response.WriteJson(w, http.StatusForbidden, response.GeneralError(fmt.Errorf("credentials lack the %s scope", scope)))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), subjectKey{}, subject)
+			r = r.WithContext(context.WithValue(ctx, credentialTenantKey{}, bound))
+			noteAccessUser(r.Context(), subject)
+			if impersonator != "" {
+				serveImpersonated(w, r, next, impersonator, subject)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isDashboard matches the embedded UI's static files (not /api/admin)
+func isDashboard(path string) bool {
+	return path == "/admin" || strings.HasPrefix(path, "/admin/")
+}
+
+// isDebug matches pprof and expvar (see debug.enabled) and the metrics scrape
+func isDebug(path string) bool {
+	return strings.HasPrefix(path, "/debug/") || path == "/metrics"
+}
+
+type subjectKey struct{}
+
+// Subject is whom Auth charged the request to ("key:<id>" or "user:<sub>");
+// empty for the bootstrap key and when auth is off
+func Subject(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey{}).(string)
+	return subject
+}
+
+// FlagCaller is whom feature flags are decided for: the subject, else the
+// tenant, so a percentage rollout is stable per API key or user
+func FlagCaller(ctx context.Context) featureflag.Caller {
+	caller := featureflag.Caller{Key: Subject(ctx)}
+	if t, ok := tenant.FromContext(ctx); ok {
+		caller.Tenant = t.Slug
+		if caller.Key == "" {
+			caller.Key = "tenant:" + t.Slug
+		}
+	}
+	return caller
+}
+
+// BearerToken extracts the token of an `Authorization: Bearer` header
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(value) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(value), true
+}
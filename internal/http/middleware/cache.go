@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 CacheControl sets Cache-Control on every response from the policy
+// ---------------------------------------------------------
+// 1. Waits for the status: errors never get a cacheable rule
+// 2. Leaves a Cache-Control the handler set itself (photos, downloads)
+// 3. Otherwise applies the route's rule, or the read/write default
+func CacheControl(policy *response.CachePolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&cacheWriter{ResponseWriter: w, policy: policy, r: r}, r)
+		})
+	}
+}
+
+// cacheWriter applies the policy right before the headers go out
+type cacheWriter struct {
+	http.ResponseWriter
+	policy      *response.CachePolicy
+	r           *http.Request
+	wroteHeader bool
+}
+
+func (cw *cacheWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		cw.policy.Apply(cw.Header(), cw.r, status)
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cacheWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush keeps streaming responses (CSV exports) streaming
+func (cw *cacheWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection
+func (cw *cacheWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/challenge"
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Bodies bigger than this aren't searched for the honeypot
+const maxHoneypotBody = 1 << 20
+
+// 🧩 Challenge keeps bots off the routes listed in challenge.routes
+// ---------------------------------------------------------
+// 1. Skips other routes, and callers Auth let in (auth on, route not public)
+// 2. A filled-in honeypot field in the JSON body → 400
+// 3. A missing widget token → 400, one the provider rejects → 403
+// 4. Provider unreachable → 503: failing open would let the bots in
+//
+// Runs inside Auth. The body is read for the honeypot and handed on intact.
+func Challenge(guard *challenge.Guard, reg *registry.Registry, authEnabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pattern, ok := reg.Lookup(r)
+			if !ok || !guard.Guards(pattern) || (authEnabled && !route.Public) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if field := guard.Honeypot(); field != "" && honeypotFilled(r, field) {
+				slog.Warn("🍯 Honeypot filled in", slog.String("path", r.URL.Path), slog.String("remote_addr", r.RemoteAddr))
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("request rejected")))
+				return
+			}
+
+			err := guard.Check(r)
+			switch {
+			case errors.Is(err, challenge.ErrMissing):
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+				return
+			case errors.Is(err, challenge.ErrFailed):
+				slog.Warn("Challenge failed", slog.String("path", r.URL.Path), slog.String("remote_addr", r.RemoteAddr))
+				response.WriteJson(w, http.StatusForbidden, response.GeneralError(err))
+				return
+			case err != nil:
+				slog.Error("Error verifying challenge", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusServiceUnavailable, response.GeneralError(errors.New("challenge provider unavailable")))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// honeypotFilled reports whether r's JSON body sets field to anything but
+// "" or null. r.Body is replaced so the handler still reads all of it.
+func honeypotFilled(r *http.Request, field string) bool {
+	head, err := io.ReadAll(io.LimitReader(r.Body, maxHoneypotBody+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	if err != nil || len(head) > maxHoneypotBody {
+		return false
+	}
+
+	var body map[string]json.RawMessage
+	if json.Unmarshal(head, &body) != nil {
+		return false
+	}
+	value, ok := body[field]
+	if !ok {
+		return false
+	}
+	value = bytes.TrimSpace(value)
+	return !bytes.Equal(value, []byte(`""`)) && !bytes.Equal(value, []byte("null"))
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/http/pattern"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 Collapse runs identical concurrent GETs once (singleflight)
+// ---------------------------------------------------------
+//  1. Only GETs on cfg.Routes; everything else (NDJSON streams, and the
+//     student portal, whose answers depend on the session) passes straight
+//     through
+//  2. Key: tenant + path + query (parameters sorted) + Accept
+//  3. The first request runs the handler into a buffer; requests with the
+//     same key arriving meanwhile wait and all get a copy of its response
+//
+// Sits inside auth, quotas and tenancy, so each request is still checked
+// and charged on its own; only the handler (and its database call) is
+// shared. A request arriving after the response was sent runs again.
+func Collapse(cfg config.Collapse) func(http.Handler) http.Handler {
+	routes, err := pattern.NewSet(cfg.Routes...)
+	if err != nil {
+		panic(err)
+	}
+	var group singleflight.Group
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// NDJSON streams would be buffered whole; let each run its own
+			if _, ok := routes.Match(r); r.Method != http.MethodGet || !ok || response.WantsNDJSON(r.Header.Get("Accept")) || isPortal(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := collapseKey(r)
+			res, _, shared := group.Do(key, func() (any, error) {
+				// the leader's client hanging up must not fail the others
+				rec := &recordedResponse{header: http.Header{}}
+				next.ServeHTTP(rec, r.WithContext(context.WithoutCancel(r.Context())))
+				return rec, nil
+			})
+			if shared {
+				slog.Debug("Collapsed identical request", slog.String("key", key))
+			}
+			res.(*recordedResponse).writeTo(w)
+		})
+	}
+}
+
+func collapseKey(r *http.Request) string {
+	var tenantID int64
+	if t, ok := tenant.FromContext(r.Context()); ok {
+		tenantID = t.ID
+	}
+	return fmt.Sprintf("%d %s?%s %s", tenantID, r.URL.Path, r.URL.Query().Encode(), r.Header.Get("Accept"))
+}
+
+// recordedResponse buffers a response so it can be replayed to every
+// collapsed request
+type recordedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recordedResponse) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recordedResponse) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *recordedResponse) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(p)
+}
+
+func (rec *recordedResponse) writeTo(w http.ResponseWriter) {
+	maps.Copy(w.Header(), rec.header.Clone())
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	w.WriteHeader(rec.status)
+	w.Write(rec.body.Bytes())
+}
@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// 🧩 Compress gzip/deflate-encodes responses
+// ---------------------------------------------------------
+// 1. Negotiates the encoding from Accept-Encoding (gzip preferred)
+// 2. Buffers the first cfg.MinSize bytes; smaller bodies go out as-is
+// 3. Only compresses cfg.ContentTypes (JSON, CSV, ...), never images/PDFs
+//
+// Handlers need no changes; Content-Length is dropped when compressing.
+func Compress(cfg config.Compression) func(http.Handler) http.Handler {
+	pools := map[string]*sync.Pool{
+		"gzip": {New: func() any {
+			w, _ := gzip.NewWriterLevel(io.Discard, cfg.Level)
+			return w
+		}},
+		"deflate": {New: func() any {
+			w, _ := zlib.NewWriterLevel(io.Discard, cfg.Level)
+			return w
+		}},
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				cfg:            cfg,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				status:         http.StatusOK,
+			}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from Accept-Encoding ("" = identity)
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "deflate" && name != "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		if name == "*" {
+			name = "gzip"
+		}
+		// gzip wins ties
+		if q > bestQ || (q == bestQ && name == "gzip") {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressWriter holds the body back until it knows whether compressing pays off
+type compressWriter struct {
+	http.ResponseWriter
+	cfg      config.Compression
+	encoding string
+	pool     *sync.Pool
+
+	status      int
+	wroteHeader bool
+	// decided is set once the body is committed to compressed or plain
+	decided bool
+	buf     []byte
+	enc     encoder
+}
+
+// encoder is what *gzip.Writer and *zlib.Writer have in common
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	cw.wroteHeader = true
+	if cw.decided {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.cfg.MinSize {
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// decide commits to compressing (when worth it and allowed) and writes the
+// headers plus whatever was buffered
+func (cw *compressWriter) decide(bigEnough bool) error {
+	cw.decided = true
+	h := cw.Header()
+
+	compressible := cw.compressible()
+	if compressible {
+		h.Add("Vary", "Accept-Encoding")
+	}
+
+	if bigEnough && compressible {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		cw.enc = cw.pool.Get().(encoder)
+		cw.enc.Reset(cw.ResponseWriter)
+		cw.ResponseWriter.WriteHeader(cw.status)
+		_, err := cw.enc.Write(cw.buf)
+		cw.buf = nil
+		return err
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	_, err := cw.ResponseWriter.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// compressible checks status, existing encoding and the content-type allowlist
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if cw.status < 200 || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return slices.Contains(cw.cfg.ContentTypes, mediaType)
+}
+
+// Flush sends what is buffered now (streaming responses), compressed if allowed
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if err := cw.decide(true); err != nil {
+			return
+		}
+	}
+	if cw.enc != nil {
+		cw.enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the body once the handler returned
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			return nil
+		}
+		return cw.decide(false)
+	}
+	if cw.enc == nil {
+		return nil
+	}
+	err := cw.enc.Close()
+	cw.enc.Reset(io.Discard)
+	cw.pool.Put(cw.enc)
+	cw.enc = nil
+	return err
+}
+
+// Hijack lets websocket-style handlers take over the connection
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("hijacking not supported")
+}
+
+// Unwrap exposes the original writer to http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
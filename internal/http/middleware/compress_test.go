@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: ""},
+		{header: "identity", want: ""},
+		{header: "br", want: ""},
+		{header: "gzip", want: "gzip"},
+		{header: "deflate", want: "deflate"},
+		{header: "GZIP", want: "gzip"},
+		{header: "deflate, gzip", want: "gzip"},
+		{header: "gzip;q=0.5, deflate", want: "deflate"},
+		{header: "gzip; q=0.5 , deflate;q=0.8", want: "deflate"},
+		{header: "gzip;q=0, deflate;q=0", want: ""},
+		{header: "gzip;q=0", want: ""},
+		{header: "*", want: "gzip"},
+		{header: "deflate;q=0.5, *;q=0.5", want: "gzip"},
+		{header: "br, deflate;q=bad", want: "deflate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := negotiateEncoding(tt.header); got != tt.want {
+				t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	cfg := config.Compression{Enabled: true, MinSize: 64, Level: -1, ContentTypes: []string{"application/json", "text/csv"}}
+	big := `{"data":"` + strings.Repeat("a", 200) + `"}`
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		rangeHeader    string
+		contentType    string
+		status         int
+		body           string
+		wantEncoding   string
+		wantVary       bool
+	}{
+		{name: "gzip", acceptEncoding: "gzip", contentType: "application/json", body: big, wantEncoding: "gzip", wantVary: true},
+		{name: "deflate", acceptEncoding: "deflate", contentType: "text/csv; charset=utf-8", body: big, wantEncoding: "deflate", wantVary: true},
+		{name: "too small", acceptEncoding: "gzip", contentType: "application/json", body: `{"ok":true}`, wantVary: true},
+		{name: "not accepted", acceptEncoding: "", contentType: "application/json", body: big},
+		{name: "type not listed", acceptEncoding: "gzip", contentType: "image/png", body: big},
+		{name: "no content type", acceptEncoding: "gzip", body: big},
+		{name: "not modified", acceptEncoding: "gzip", contentType: "application/json", status: http.StatusNotModified},
+		{name: "error status", acceptEncoding: "gzip", contentType: "application/json", status: http.StatusBadRequest, body: big, wantEncoding: "gzip", wantVary: true},
+		{name: "head", method: http.MethodHead, acceptEncoding: "gzip", contentType: "application/json", body: big},
+		{name: "range", acceptEncoding: "gzip", rangeHeader: "bytes=0-9", contentType: "application/json", body: big},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Compress(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.Header().Set("Content-Length", "999")
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				// two writes: the first alone is under MinSize
+				io.WriteString(w, tt.body[:len(tt.body)/2])
+				io.WriteString(w, tt.body[len(tt.body)/2:])
+			}))
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			wantStatus := tt.status
+			if wantStatus == 0 {
+				wantStatus = http.StatusOK
+			}
+			if rec.Code != wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, wantStatus)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Get("Vary") == "Accept-Encoding"; got != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Encoding %v", rec.Header().Get("Vary"), tt.wantVary)
+			}
+			if tt.wantEncoding != "" && rec.Header().Get("Content-Length") != "" {
+				t.Errorf("Content-Length = %q, want it dropped", rec.Header().Get("Content-Length"))
+			}
+
+			var body io.Reader = rec.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = zr
+			case "deflate":
+				zr, err := zlib.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = zr
+			}
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.body {
+				t.Errorf("body = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
+
+// A flush commits to compressing even under MinSize, so streams stream.
+func TestCompressFlush(t *testing.T) {
+	cfg := config.Compression{MinSize: 1024, Level: -1, ContentTypes: []string{"application/x-ndjson"}}
+	handler := Compress(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		io.WriteString(w, "{\"id\":1}\n")
+		w.(http.Flusher).Flush()
+		io.WriteString(w, "{\"id\":2}\n")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("Flush did not reach the underlying writer")
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(zr)
+	if want := "{\"id\":1}\n{\"id\":2}\n"; string(got) != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/manish-npx/go-student-api/internal/health"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+var errStorageUnavailable = response.NewStaticProblem(http.StatusServiceUnavailable, "storage unavailable, try again later")
+
+// 🧩 Degraded turns API requests away while the database is down
+// ---------------------------------------------------------
+// Without it each request would wait on the dead database and fail with
+// a driver error. While the health monitor reports degraded, the API
+// answers 503 with Retry-After at once; the dashboard's static files,
+// /debug, /metrics and the probes (mounted outside) still work.
+func Degraded(monitor *health.Monitor) func(http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(int(math.Ceil(monitor.RetryAfter().Seconds())))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !monitor.Ready() && !isDashboard(r.URL.Path) && !isDebug(r.URL.Path) {
+				w.Header().Set("Retry-After", retryAfter)
+				errStorageUnavailable.Write(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
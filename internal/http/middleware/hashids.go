@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/hashid"
+	"github.com/manish-npx/go-student-api/internal/ulid"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 HashIDs shows clients hashids in place of numeric ids
+// ---------------------------------------------------------
+// 1. Path ids ({id}, {docId}, ...) must be hashids, or a student's ULID
+// public id; numbers and anything else → 404
+// 2. ?ids= and the "id", "ids", "*_id" and "*_ids" fields of JSON bodies
+// must be hashids too → 400 otherwise
+// 3. JSON responses (NDJSON a line at a time) get those fields, "missing"
+// and the numeric path segments of "href" links and Location encoded
+//
+// Handlers keep seeing and writing numbers. Group middleware: it needs the
+// matched route's path values, and runs inside Naming, so keys are
+// snake_case. A nil codec passes everything through.
+func HashIDs(codec *hashid.Codec) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if codec == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range idWildcards(r.Pattern) {
+				value := r.PathValue(name)
+				if ulid.Valid(value) {
+					continue
+				}
+				id, ok := codec.Decode(value)
+				if !ok {
+					errNotFound.Write(w)
+					return
+				}
+				r.SetPathValue(name, strconv.FormatInt(id, 10))
+			}
+
+			if query := r.URL.Query(); query.Has("ids") {
+				var ids []string
+				for _, part := range strings.Split(query.Get("ids"), ",") {
+					if part = strings.TrimSpace(part); part == "" {
+						continue
+					}
+					id, ok := codec.Decode(part)
+					if !ok {
+						response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %q in ids", part)))
+						return
+					}
+					ids = append(ids, strconv.FormatInt(id, 10))
+				}
+				query.Set("ids", strings.Join(ids, ","))
+				r.URL.RawQuery = query.Encode()
+			}
+
+			if r.Body != nil && isJSON(r.Header.Get("Content-Type")) {
+				body, err := io.ReadAll(r.Body)
+				r.Body.Close()
+				if err != nil {
+					response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("read body: %v", err)))
+					return
+				}
+				// invalid JSON is left for the handler to reject
+				decoded, err := walkJSON(body, nil, decodeIDs(codec))
+				var invalid *invalidIDError
+				if errors.As(err, &invalid) {
+					response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+					return
+				}
+				if err == nil {
+					body = decoded
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.ContentLength = int64(len(body))
+			}
+
+			rw := &rewriteWriter{ResponseWriter: w, status: http.StatusOK, lines: true, rewrite: func(body []byte, h http.Header) []byte {
+				if location := h.Get("Location"); location != "" {
+					h.Set("Location", encodeHref(codec, location))
+				}
+				if encoded, err := walkJSON(body, nil, encodeIDs(codec)); err == nil {
+					return encoded
+				}
+				return body
+			}}
+			defer rw.Close()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// invalidIDError is an id field of a request body that isn't a hashid
+type invalidIDError struct {
+	key   string
+	value any
+}
+
+func (e *invalidIDError) Error() string {
+	return fmt.Sprintf("%s must be a hashid, got %v", e.key, e.value)
+}
+
+func (e *invalidIDError) FieldErrors() []response.FieldError {
+	return []response.FieldError{{Field: e.key, Message: e.Error()}}
+}
+
+// isIDKey matches the JSON fields holding numeric ids (one or a list);
+// public_id is a ULID already
+func isIDKey(key string) bool {
+	if key == "public_id" {
+		return false
+	}
+	return key == "id" || key == "ids" || strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_ids")
+}
+
+// decodeIDs turns the hashids of a request body's id fields into numbers
+func decodeIDs(codec *hashid.Codec) func(string, any) (any, error) {
+	return func(key string, v any) (any, error) {
+		if !isIDKey(key) || v == nil {
+			return v, nil
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, &invalidIDError{key: key, value: v}
+		}
+		id, ok := codec.Decode(s)
+		if !ok {
+			return nil, &invalidIDError{key: key, value: strconv.Quote(s)}
+		}
+		return json.Number(strconv.FormatInt(id, 10)), nil
+	}
+}
+
+// encodeIDs turns the numbers of a response's id fields into hashids
+func encodeIDs(codec *hashid.Codec) func(string, any) (any, error) {
+	return func(key string, v any) (any, error) {
+		switch v := v.(type) {
+		case json.Number:
+			if !isIDKey(key) && key != "missing" {
+				return v, nil
+			}
+			if id, err := strconv.ParseInt(v.String(), 10, 64); err == nil && id >= 0 {
+				return codec.Encode(id), nil
+			}
+		case string:
+			if key == "href" {
+				return encodeHref(codec, v), nil
+			}
+		}
+		return v, nil
+	}
+}
+
+// encodeHref encodes the all-digit path segments of a link, ids in every
+// route of this API
+func encodeHref(codec *hashid.Codec, href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if id, err := strconv.ParseInt(segment, 10, 64); err == nil && id >= 0 {
+			segments[i] = codec.Encode(id)
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	return u.String()
+}
+
+// idWildcards names the wildcards of a route pattern holding ids: {id}
+// and {<thing>Id}
+func idWildcards(pattern string) []string {
+	var names []string
+	for rest := pattern; ; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return names
+		}
+		name := strings.TrimSuffix(rest[start+1:start+end], "...")
+		if name == "id" || strings.HasSuffix(name, "Id") {
+			names = append(names, name)
+		}
+		rest = rest[start+end+1:]
+	}
+}
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// opaqueKeys hold user data (custom field values) whose keys are never renamed
+var opaqueKeys = map[string]bool{"custom": true}
+
+// 🧩 Naming renders JSON bodies in the caller's key style
+// ---------------------------------------------------------
+// 1. The style is the cfg.Header value, else cfg.Default: snake_case or camelCase
+// 2. snake_case is the API's own, so those requests pass straight through
+// 3. camelCase request bodies are renamed to snake_case before the handler reads them
+// 4. JSON responses are buffered and their keys renamed to camelCase
+// 5. NDJSON streams are renamed a line at a time, so they keep streaming
+//
+// Handlers need no changes. Only object keys change: values, query
+// parameters and error texts stay as they are. Mount it inside Compress
+// so it sees plain bodies.
+func Naming(cfg config.JSONNaming) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			naming := cfg.Default
+			if cfg.Header != "" {
+				if v := r.Header.Get(cfg.Header); v != "" {
+					naming = v
+				}
+			}
+			switch {
+			case strings.EqualFold(naming, "camelCase"):
+				naming = "camelCase"
+			case strings.EqualFold(naming, "snake_case"):
+				naming = "snake_case"
+			default:
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("%s must be snake_case or camelCase (got %q)", cfg.Header, naming)))
+				return
+			}
+			if cfg.Header != "" {
+				w.Header().Set(cfg.Header, naming)
+			}
+			if naming == "snake_case" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// 🐪 camelCase body → snake_case; invalid JSON is left for the handler to reject
+			if r.Body != nil && isJSON(r.Header.Get("Content-Type")) {
+				body, err := io.ReadAll(r.Body)
+				r.Body.Close()
+				if err != nil {
+					response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("read body: %v", err)))
+					return
+				}
+				if renamed, err := renameKeys(body, snakeCase); err == nil {
+					body = renamed
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.ContentLength = int64(len(body))
+			}
+
+			rw := &rewriteWriter{ResponseWriter: w, status: http.StatusOK, lines: true, rewrite: func(body []byte, _ http.Header) []byte {
+				if renamed, err := renameKeys(body, camelCase); err == nil {
+					return renamed
+				}
+				return body
+			}}
+			defer rw.Close()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// isJSON matches application/json and the +json types
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// -------------------------------------------------------------
+// renameKeys() → The JSON in data with every object key renamed
+// -------------------------------------------------------------
+// Values under opaqueKeys are copied with their keys unchanged.
+func renameKeys(data []byte, rename func(string) string) ([]byte, error) {
+	return walkJSON(data, rename, nil)
+}
+
+// -------------------------------------------------------------
+// walkJSON() → The JSON in data with keys renamed and scalars replaced
+// -------------------------------------------------------------
+// Walks the tokens, so key order, numbers and escaping survive as they
+// were. rename (nil: keep) is given every object key; value (nil: keep)
+// every string, json.Number, bool or nil together with the key it sits
+// under (for array items the array's; "" at the top level) and returns
+// its replacement. Nothing under opaqueKeys is renamed or replaced.
+// Several top-level values (one per line) are kept one per line.
+func walkJSON(data []byte, rename func(string) string, value func(key string, v any) (any, error)) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return data, nil
+	}
+
+	// frame is one open object or array
+	type frame struct {
+		object bool
+		opaque bool
+		// key is the one this object or array sits under
+		key string
+		// wantKey is set when the next token of an object is a key
+		wantKey bool
+		// childKey and childOpaque describe the value after the last key
+		childKey    string
+		childOpaque bool
+		n           int
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out bytes.Buffer
+	out.Grow(len(data))
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	writeString := func(s string) error {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+		out.Truncate(out.Len() - 1) // Encode's newline
+		return nil
+	}
+
+	var stack []*frame
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			// the input ended inside an object or array
+			if len(stack) > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			out.WriteByte(byte(d))
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				out.WriteByte('\n')
+			}
+			continue
+		}
+
+		// separators, keys, and the key and opaqueness of the value that follows
+		opaque, key := false, ""
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.object && top.wantKey {
+				if top.n > 0 {
+					out.WriteByte(',')
+				}
+				top.n++
+				name := tok.(string)
+				top.childKey = name
+				top.childOpaque = top.opaque || opaqueKeys[name]
+				if !top.opaque && rename != nil {
+					name = rename(name)
+				}
+				if err := writeString(name); err != nil {
+					return nil, err
+				}
+				out.WriteByte(':')
+				top.wantKey = false
+				continue
+			}
+			if top.object {
+				top.wantKey = true
+				opaque, key = top.childOpaque, top.childKey
+			} else {
+				if top.n > 0 {
+					out.WriteByte(',')
+				}
+				top.n++
+				opaque, key = top.opaque, top.key
+			}
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			out.WriteByte(byte(d))
+			stack = append(stack, &frame{object: d == '{', wantKey: d == '{', opaque: opaque, key: key})
+			continue
+		}
+		if value != nil && !opaque {
+			if tok, err = value(key, tok); err != nil {
+				return nil, err
+			}
+		}
+		switch v := tok.(type) {
+		case string:
+			err = writeString(v)
+		case json.Number:
+			out.WriteString(v.String())
+		case bool:
+			fmt.Fprint(&out, v)
+		case nil:
+			out.WriteString("null")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(stack) == 0 {
+			out.WriteByte('\n')
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// camelCase renames date_of_birth → dateOfBirth; leading underscores
+// (_links) stay
+func camelCase(key string) string {
+	if !strings.Contains(strings.TrimLeft(key, "_"), "_") {
+		return key
+	}
+	var b strings.Builder
+	upper := false
+	for i, r := range key {
+		switch {
+		case r == '_' && !isLeading(key, i):
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isLeading reports whether key[:i] is all underscores
+func isLeading(key string, i int) bool {
+	return strings.Trim(key[:i], "_") == ""
+}
+
+// snakeCase renames dateOfBirth → date_of_birth
+func snakeCase(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
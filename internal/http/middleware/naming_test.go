@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+func TestCamelCase(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "name", want: "name"},
+		{key: "date_of_birth", want: "dateOfBirth"},
+		{key: "public_id", want: "publicId"},
+		{key: "_links", want: "_links"},
+		{key: "__meta_data", want: "__metaData"},
+		{key: "alreadyCamel", want: "alreadyCamel"},
+		{key: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := camelCase(tt.key); got != tt.want {
+				t.Errorf("camelCase(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "name", want: "name"},
+		{key: "dateOfBirth", want: "date_of_birth"},
+		{key: "publicId", want: "public_id"},
+		{key: "Name", want: "name"},
+		{key: "already_snake", want: "already_snake"},
+		{key: "_links", want: "_links"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := snakeCase(tt.key); got != tt.want {
+				t.Errorf("snakeCase(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "object", in: `{"date_of_birth":"2000-01-01","public_id":"x"}`, want: "{\"dateOfBirth\":\"2000-01-01\",\"publicId\":\"x\"}\n"},
+		{name: "nested and arrays", in: `{"data":[{"first_name":"Ada","guardian_list":[{"phone_number":null}]}]}`, want: "{\"data\":[{\"firstName\":\"Ada\",\"guardianList\":[{\"phoneNumber\":null}]}]}\n"},
+		{name: "values untouched", in: `{"some_key":"snake_value"}`, want: "{\"someKey\":\"snake_value\"}\n"},
+		{name: "numbers kept", in: `{"big_id":12345678901234567890,"avg_score":1.50,"is_active":true}`, want: "{\"bigId\":12345678901234567890,\"avgScore\":1.50,\"isActive\":true}\n"},
+		{name: "custom fields opaque", in: `{"custom":{"shoe_size":42,"nested_map":{"a_b":1}},"last_name":"L"}`, want: "{\"custom\":{\"shoe_size\":42,\"nested_map\":{\"a_b\":1}},\"lastName\":\"L\"}\n"},
+		{name: "escaping kept", in: `{"html_note":"<b>&</b> \"q\""}`, want: "{\"htmlNote\":\"<b>&</b> \\\"q\\\"\"}\n"},
+		{name: "ndjson lines", in: "{\"row_id\":1}\n{\"row_id\":2}\n", want: "{\"rowId\":1}\n{\"rowId\":2}\n"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renameKeys([]byte(tt.in), camelCase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("renameKeys(%s) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := renameKeys([]byte(`{"a":`), camelCase); err == nil {
+		t.Error("renameKeys of truncated JSON: want an error")
+	}
+}
+
+func TestNaming(t *testing.T) {
+	cfg := config.JSONNaming{Default: "snake_case", Header: "X-JSON-Naming"}
+
+	tests := []struct {
+		name        string
+		header      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantSeen    string
+		wantBody    string
+	}{
+		{name: "default snake passes through", contentType: "application/json", body: `{"firstName":"Ada"}`, wantStatus: http.StatusOK, wantSeen: `{"firstName":"Ada"}`, wantBody: `{"first_name":"Ada"}`},
+		{name: "camel both ways", header: "camelcase", contentType: "application/json", body: `{"firstName":"Ada"}`, wantStatus: http.StatusOK, wantSeen: "{\"first_name\":\"Ada\"}\n", wantBody: "{\"firstName\":\"Ada\"}\n"},
+		{name: "invalid json left for the handler", header: "camelCase", contentType: "application/json", body: `{"firstName":`, wantStatus: http.StatusOK, wantSeen: `{"firstName":`, wantBody: "{\"firstName\":\"Ada\"}\n"},
+		{name: "non-json body untouched", header: "camelCase", contentType: "text/csv", body: "firstName\nAda\n", wantStatus: http.StatusOK, wantSeen: "firstName\nAda\n", wantBody: "{\"firstName\":\"Ada\"}\n"},
+		{name: "bad style", header: "kebab-case", contentType: "application/json", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := Naming(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				seen = string(body)
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, `{"first_name":"Ada"}`)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.header != "" {
+				req.Header.Set(cfg.Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if !strings.Contains(rec.Body.String(), "snake_case or camelCase") {
+					t.Errorf("body = %s, want the allowed styles", rec.Body)
+				}
+				return
+			}
+			if seen != tt.wantSeen {
+				t.Errorf("handler read %q, want %q", seen, tt.wantSeen)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+			if rec.Header().Get(cfg.Header) == "" {
+				t.Errorf("%s response header not set", cfg.Header)
+			}
+		})
+	}
+}
+
+// NDJSON responses are renamed line by line as they stream.
+func TestNamingNDJSON(t *testing.T) {
+	handler := Naming(config.JSONNaming{Default: "camelCase"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		io.WriteString(w, "{\"row_id\":1}\n{\"row_")
+		io.WriteString(w, "id\":2}\n")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if want := "{\"rowId\":1}\n{\"rowId\":2}\n"; rec.Body.String() != want {
+		t.Errorf("body = %q, want %q", rec.Body, want)
+	}
+}
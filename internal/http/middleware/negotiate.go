@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 Negotiate answers in the format the Accept header asks for
+// ---------------------------------------------------------
+// 1. Picks an encoder with response.Negotiate (XML, MessagePack, ...)
+// 2. JSON (the default, and */*) passes straight through
+// 3. Otherwise JSON responses are buffered and re-encoded, Content-Type set
+//
+// Handlers keep writing JSON; CSV, images and other bodies are untouched.
+// Mount it inside Compress and outside Naming, so it encodes the renamed keys.
+func Negotiate() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			enc := response.Negotiate(r.Header.Get("Accept"))
+			if enc == response.JSON {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &rewriteWriter{ResponseWriter: w, status: http.StatusOK, rewrite: func(body []byte, h http.Header) []byte {
+				var out bytes.Buffer
+				if err := enc.Encode(&out, json.RawMessage(body)); err != nil {
+					slog.Error("Error encoding response", slog.String("content_type", enc.ContentType()), slog.String("error", err.Error()))
+					return body
+				}
+				h.Set("Content-Type", enc.ContentType())
+				return out.Bytes()
+			}}
+			defer rw.Close()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
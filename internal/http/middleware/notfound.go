@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+var errNotFound = response.NewStaticProblem(http.StatusNotFound, "not found")
+
+// 🧩 UniformNotFound makes every 404 look and take alike
+// ---------------------------------------------------------
+// 1. Whatever answered 404 (a handler, the router, tenancy), its body is
+// dropped for a problem with the detail "not found"
+// 2. The 404 goes out no sooner than delay after the request came in, so
+// a fast miss (bad id) and a slow one (a query finding another tenant's
+// record) can't be told apart by timing
+//
+// Other statuses pass through untouched. Mount it outside Auth and
+// tenancy so their 404s are covered too.
+func UniformNotFound(delay time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			nw := &notFoundWriter{ResponseWriter: w}
+			next.ServeHTTP(nw, r)
+			if !nw.notFound {
+				return
+			}
+
+			if wait := delay - time.Since(start); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-r.Context().Done():
+					timer.Stop()
+				}
+			}
+			// what the dropped body said about itself
+			for _, key := range []string{"Content-Length", "Content-Disposition", "ETag", "Last-Modified", "Link"} {
+				w.Header().Del(key)
+			}
+			errNotFound.Write(w)
+		})
+	}
+}
+
+// notFoundWriter holds back 404s and their bodies; anything else goes
+// straight out
+type notFoundWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	notFound    bool
+}
+
+func (nw *notFoundWriter) WriteHeader(status int) {
+	if nw.wroteHeader {
+		return
+	}
+	nw.wroteHeader = true
+	nw.notFound = status == http.StatusNotFound
+	if !nw.notFound {
+		nw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (nw *notFoundWriter) Write(p []byte) (int, error) {
+	if !nw.wroteHeader {
+		nw.WriteHeader(http.StatusOK)
+	}
+	if nw.notFound {
+		return len(p), nil
+	}
+	return nw.ResponseWriter.Write(p)
+}
+
+func (nw *notFoundWriter) Flush() {
+	if nw.notFound {
+		return
+	}
+	if f, ok := nw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the original writer to http.ResponseController
+func (nw *notFoundWriter) Unwrap() http.ResponseWriter {
+	return nw.ResponseWriter
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// redactedFields are always masked in logged bodies, on top of cfg.RedactFields
+var redactedFields = []string{
+	"email", "phone", "address", "dob", "date_of_birth", "password", "secret", "client_secret",
+	"key", "bootstrap_key", "token", "access_token", "refresh_token", "id_token", "authorization",
+}
+
+// emailPattern catches addresses anywhere: free text, paths, CSV rows
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// 🧩 Payloads logs request and response bodies for debugging integrations
+// ---------------------------------------------------------
+// 1. Logs a cfg.SampleRate share of requests when cfg.Enabled
+// 2. With env: dev, `<cfg.Header>: 1` logs that one request regardless
+// 3. Bodies are capped at cfg.MaxBytes; only textual types are logged verbatim
+// 4. Emails and PII/secret JSON or form fields are masked before logging
+//
+// Bodies are captured as the handler reads and writes them, so streaming
+// is unaffected. Mount it inside Compress to see plain responses.
+func Payloads(cfg config.Payloads, env string) func(http.Handler) http.Handler {
+	redact := newRedactor(append(slices.Clone(redactedFields), cfg.RedactFields...))
+	headerAllowed := env == "dev" && cfg.Header != ""
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			forced := headerAllowed && isTrue(r.Header.Get(cfg.Header))
+			if !forced && (!cfg.Enabled || rand.Float64() >= cfg.SampleRate) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			req := &capture{max: cfg.MaxBytes}
+			if r.Body != nil {
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, req), r.Body}
+			}
+			pw := &payloadWriter{ResponseWriter: w, status: http.StatusOK, body: capture{max: cfg.MaxBytes}}
+
+			next.ServeHTTP(pw, r)
+
+			query, err := url.QueryUnescape(r.URL.RawQuery)
+			if err != nil {
+				query = r.URL.RawQuery
+			}
+			slog.Info("📦 Payload",
+				slog.String("request_id", RequestIDFrom(r)),
+				slog.String("method", r.Method),
+				slog.String("path", redact(r.URL.Path)),
+				slog.String("query", redact(query)),
+				slog.Int("status", pw.status),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("request", req.describe(r.Header.Get("Content-Type"), redact)),
+				slog.String("response", pw.body.describe(pw.Header().Get("Content-Type"), redact)),
+			)
+		})
+	}
+}
+
+// newRedactor masks the named JSON and form fields, then any email address
+func newRedactor(fields []string) func(string) string {
+	quoted := make([]string, len(fields))
+	for i, f := range fields {
+		quoted[i] = regexp.QuoteMeta(f)
+	}
+	names := strings.Join(quoted, "|")
+	jsonField := regexp.MustCompile(`(?i)"(` + names + `)"\s*:\s*("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	formField := regexp.MustCompile(`(?i)(^|[&?])(` + names + `)=[^&]*`)
+
+	return func(s string) string {
+		s = jsonField.ReplaceAllString(s, `"$1":"[REDACTED]"`)
+		s = formField.ReplaceAllString(s, `$1$2=[REDACTED]`)
+		return emailPattern.ReplaceAllString(s, "[EMAIL]")
+	}
+}
+
+func isTrue(v string) bool {
+	return v == "1" || strings.EqualFold(v, "true")
+}
+
+// capture keeps the first max bytes written to it and counts the rest
+type capture struct {
+	max   int
+	buf   []byte
+	total int
+}
+
+func (c *capture) Write(p []byte) (int, error) {
+	if room := c.max - len(c.buf); room > 0 {
+		c.buf = append(c.buf, p[:min(room, len(p))]...)
+	}
+	c.total += len(p)
+	return len(p), nil
+}
+
+// describe renders the captured body for the log line
+func (c *capture) describe(contentType string, redact func(string) string) string {
+	if c.total == 0 {
+		return ""
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(c.buf)
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	textual := strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") || mediaType == "application/x-www-form-urlencoded"
+	if !textual {
+		return fmt.Sprintf("<%d bytes %s>", c.total, contentType)
+	}
+
+	body := redact(string(c.buf))
+	if c.total > len(c.buf) {
+		body += fmt.Sprintf("…(+%d bytes)", c.total-len(c.buf))
+	}
+	return body
+}
+
+// payloadWriter tees the response body into a capture
+type payloadWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        capture
+}
+
+func (pw *payloadWriter) WriteHeader(status int) {
+	if !pw.wroteHeader {
+		pw.wroteHeader = true
+		pw.status = status
+	}
+	pw.ResponseWriter.WriteHeader(status)
+}
+
+func (pw *payloadWriter) Write(p []byte) (int, error) {
+	pw.wroteHeader = true
+	pw.body.Write(p)
+	return pw.ResponseWriter.Write(p)
+}
+
+// Flush passes streaming flushes through
+func (pw *payloadWriter) Flush() {
+	if f, ok := pw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the original writer to http.ResponseController
+func (pw *payloadWriter) Unwrap() http.ResponseWriter {
+	return pw.ResponseWriter
+}
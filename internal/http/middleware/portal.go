@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/portal"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/token"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 Portal authenticates student portal sessions (the /api/me routes)
+// ---------------------------------------------------------
+// 1. Needs the `Authorization: Bearer` token of POST /api/me/login
+// 2. Looks the session up in the request's tenant; unknown, expired and
+// logged-out sessions, and those of trashed students → 401
+// 3. Stores the session in the context (portal.FromContext)
+//
+// An admin's impersonation token for a student ("student:<id>") works here
+// too, in the student's tenant and within its scopes (writes need write);
+// its requests are logged with the admin, like Auth's.
+//
+// Handlers behind it take the student id from the session only, never
+// from the request, so a student reaches nothing but their own record.
+// Staff credentials don't work here and sessions work nowhere else.
+// Portal off → 501.
+func Portal(p *portal.Portal, store storage.Storage, tokens *token.Issuer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p == nil {
+				response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("student portal is disabled (set portal.enabled)")))
+				return
+			}
+			bearer, ok := BearerToken(r)
+			if !ok {
+				response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(errors.New("missing bearer token (sign in at /api/me/login)")))
+				return
+			}
+			if tokens != nil && token.IsLocal(bearer) {
+				impersonateStudent(w, r, next, store, tokens, bearer)
+				return
+			}
+
+			session, err := p.Authenticate(tenant.Scope(r.Context(), store), bearer)
+			switch {
+			case errors.Is(err, storage.ErrNoSession):
+				response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(err))
+				return
+			case errors.Is(err, portal.ErrUnsupported):
+				response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(err))
+				return
+			case err != nil:
+				slog.Error("Error checking portal session", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(portal.WithSession(r.Context(), session)))
+		})
+	}
+}
+
+// impersonateStudent serves a portal request made with an impersonation
+// token
+func impersonateStudent(w http.ResponseWriter, r *http.Request, next http.Handler, store storage.Storage, tokens *token.Issuer, bearer string) {
+	claims, err := tokens.Verify(bearer)
+	if err != nil {
+		response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(err))
+		return
+	}
+	studentID, err := strconv.ParseInt(strings.TrimPrefix(claims.Subject, StudentSubjectPrefix), 10, 64)
+	if claims.Impersonator == "" || !isStudentSubject(claims.Subject) || err != nil {
+		response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(errors.New("staff credentials don't work on the student portal")))
+		return
+	}
+	tenantID := storage.DefaultTenantID
+	if t, ok := tenant.FromContext(r.Context()); ok {
+		tenantID = t.ID
+	}
+	if claims.TenantID != tenantID {
+		response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(errors.New("impersonation token is for another tenant")))
+		return
+	}
+	if scope := apikey.RequiredScope(r); !apikey.Allows(claims.Scopes, scope) {
+		response.WriteJson(w, http.StatusForbidden, response.GeneralError(fmt.Errorf("impersonation token lacks the %s scope", scope)))
+		return
+	}
+	// trashed students lose their sessions, impersonated ones too
+	if _, err := tenant.Scope(r.Context(), store).GetStudentById(studentID); err != nil {
+		response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(storage.ErrNoSession))
+		return
+	}
+
+	session := types.PortalSession{TenantID: tenantID, StudentID: studentID, ExpiresAt: time.Unix(claims.ExpiresAt, 0)}
+	serveImpersonated(w, r.WithContext(portal.WithSession(r.Context(), session)), next, claims.Impersonator, claims.Subject)
+}
+
+// isPortal matches the student portal routes
+func isPortal(path string) bool {
+	return path == "/api/me" || strings.HasPrefix(path, "/api/me/")
+}
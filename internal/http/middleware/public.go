@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// 🧩 PublicOnly hides the operator surface from the public listener
+// ---------------------------------------------------------
+// With http_server.admin_address the admin API, the dashboard, /debug/,
+// /metrics and the health probes are served on the admin port only; on the
+// public port they answer 404 as if they weren't routed at all.
+func PublicOnly() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isAdminSurface(r.URL.Path) {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAdminSurface matches what only the admin port serves. The path is
+// cleaned first: the router would redirect /api/./admin to /api/admin.
+func isAdminSurface(p string) bool {
+	p = path.Clean("/" + p)
+	return p == "/api/admin" || strings.HasPrefix(p, "/api/admin/") ||
+		isDashboard(p) || isDebug(p) || p == "/healthz" || p == "/readyz"
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/ulid"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 PublicID lets /api/student/{id} routes take a student's public id
+// ---------------------------------------------------------
+// 1. Skips other routes, and ids that aren't ULIDs (numeric ones)
+// 2. Looks the ULID up in the request's tenant, trashed students included
+// 3. Unknown → 404; otherwise {id} is swapped for the numeric id, so
+// handlers only ever see that
+//
+// Group middleware: needs the path values of the matched route.
+func PublicID(store storage.Storage) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.PathValue("id")
+			if !strings.HasPrefix(r.URL.Path, "/api/student/") || !ulid.Valid(id) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// 🏫 Only the caller's tenant's students
+			publicIDs, ok := storage.As[storage.PublicIDStore](tenant.Scope(r.Context(), store))
+			if !ok {
+				response.WriteJson(w, http.StatusNotImplemented, response.GeneralError(errors.New("public ids not supported by this storage backend")))
+				return
+			}
+			studentID, err := publicIDs.GetStudentIdByPublicId(ulid.Normalize(id))
+			if err != nil {
+				response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+				return
+			}
+
+			r.SetPathValue("id", strconv.FormatInt(studentID, 10))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
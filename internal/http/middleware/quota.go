@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 Quota charges every authenticated request to its subject's daily quota
+// ---------------------------------------------------------
+// 1. Skips requests without a subject (bootstrap key, /api/auth/*)
+// 2. Counts the request, and a write for anything but GET/HEAD/OPTIONS
+// unless the route declares its quota class (free routes aren't counted)
+// 3. Sets X-RateLimit-Limit/-Remaining/-Reset (and X-RateLimit-Writes-* on writes)
+// 4. Over quota → 429 with Retry-After until the next UTC midnight
+//
+// Runs inside Auth, which resolves the subject. Storage errors let the
+// request through: a counter outage shouldn't take the API down.
+func Quota(tracker *quota.Tracker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject := Subject(r.Context())
+			if subject == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			write := r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+			route, _ := registry.FromContext(r.Context())
+			switch route.Quota {
+			case registry.QuotaFree:
+				next.ServeHTTP(w, r)
+				return
+			case registry.QuotaRead:
+				write = false
+			case registry.QuotaWrite:
+				write = true
+			}
+			status, err := tracker.Charge(subject, write)
+			if err != nil {
+				slog.Error("Error charging quota", slog.String("subject", subject), slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// 🔢 Tell clients where they stand
+			limits, h := status.Limits, w.Header()
+			reset := strconv.FormatInt(status.Reset.Unix(), 10)
+			if limits.DailyRequests > 0 {
+				h.Set("X-RateLimit-Limit", strconv.FormatInt(limits.DailyRequests, 10))
+				h.Set("X-RateLimit-Remaining", strconv.FormatInt(max(limits.DailyRequests-limits.Usage.Requests, 0), 10))
+				h.Set("X-RateLimit-Reset", reset)
+			}
+			if write && limits.DailyWrites > 0 {
+				h.Set("X-RateLimit-Writes-Limit", strconv.FormatInt(limits.DailyWrites, 10))
+				h.Set("X-RateLimit-Writes-Remaining", strconv.FormatInt(max(limits.DailyWrites-limits.Usage.Writes, 0), 10))
+				h.Set("X-RateLimit-Reset", reset)
+			}
+
+			if status.Exceeded != "" {
+				limit := limits.DailyRequests
+				if status.Exceeded == "write" {
+					limit = limits.DailyWrites
+				}
+				slog.Warn("Quota exceeded", slog.String("subject", subject), slog.String("quota", status.Exceeded))
+				h.Set("Retry-After", strconv.Itoa(int(time.Until(status.Reset).Seconds())+1))
+				response.WriteJson(w, http.StatusTooManyRequests, response.GeneralError(
+					fmt.Errorf("daily %s quota of %d exceeded; resets at %s", status.Exceeded, limit, status.Reset.Format(time.RFC3339)),
+				))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+// 🧩 RequestID tags every request for tracing across services
+// ---------------------------------------------------------
+// 1. Keeps the caller's X-Request-ID, or generates one
+// 2. Joins the caller's trace from traceparent, or starts a new trace
+// 3. Stores both in the context; httpclient clients forward them on outbound calls
+// 4. Echoes X-Request-ID on the response so callers can quote it
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			trace := httpclient.IncomingTrace(
+				r.Header.Get(httpclient.HeaderRequestID),
+				r.Header.Get(httpclient.HeaderTraceParent),
+			)
+			w.Header().Set(httpclient.HeaderRequestID, trace.RequestID)
+			next.ServeHTTP(w, r.WithContext(httpclient.WithTrace(r.Context(), trace)))
+		})
+	}
+}
+
+// RequestIDFrom returns the id RequestID assigned to the request ("" outside it)
+func RequestIDFrom(r *http.Request) string {
+	return httpclient.TraceFrom(r.Context()).RequestID
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// rewriteWriter holds JSON responses back and passes them through rewrite
+// once the handler returned; anything else goes out untouched
+type rewriteWriter struct {
+	http.ResponseWriter
+	// rewrite returns the body to send; it may change the headers
+	rewrite func(body []byte, h http.Header) []byte
+	// lines also rewrites NDJSON, each complete line as it is written,
+	// so streams keep streaming; the headers are already sent by then
+	lines bool
+
+	status      int
+	wroteHeader bool
+	buffering   bool
+	streaming   bool
+	buf         bytes.Buffer
+}
+
+func (rw *rewriteWriter) WriteHeader(status int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.status = status
+	contentType := rw.Header().Get("Content-Type")
+	rw.buffering = isJSON(contentType)
+	if rw.lines && !rw.buffering {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		rw.streaming = mediaType == response.NDJSON
+		if rw.streaming {
+			rw.Header().Del("Content-Length")
+		}
+	}
+	if !rw.buffering {
+		rw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (rw *rewriteWriter) Write(p []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.buffering {
+		return rw.buf.Write(p)
+	}
+	if rw.streaming {
+		rw.buf.Write(p)
+		end := bytes.LastIndexByte(rw.buf.Bytes(), '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		if _, err := rw.ResponseWriter.Write(rw.rewrite(rw.buf.Next(end+1), rw.Header())); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	return rw.ResponseWriter.Write(p)
+}
+
+// Flush passes streaming flushes through; buffered JSON waits for Close
+func (rw *rewriteWriter) Flush() {
+	if rw.buffering {
+		return
+	}
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close sends the buffered JSON, rewritten, or the end of an unterminated
+// NDJSON line
+func (rw *rewriteWriter) Close() error {
+	if rw.streaming && rw.buf.Len() > 0 {
+		_, err := rw.ResponseWriter.Write(rw.rewrite(rw.buf.Bytes(), rw.Header()))
+		return err
+	}
+	if !rw.buffering {
+		return nil
+	}
+	body := rw.buf.Bytes()
+	if len(bytes.TrimSpace(body)) > 0 {
+		body = rw.rewrite(body, rw.Header())
+	}
+	rw.Header().Del("Content-Length")
+	rw.ResponseWriter.WriteHeader(rw.status)
+	_, err := rw.ResponseWriter.Write(body)
+	return err
+}
+
+// Unwrap exposes the original writer to http.ResponseController
+func (rw *rewriteWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
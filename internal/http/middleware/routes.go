@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+var errRouteTimeout = response.NewStaticProblem(http.StatusServiceUnavailable, "request took too long, try again later")
+
+// 🧩 Routes hands the matched route's meta to the rest of the chain
+// ---------------------------------------------------------
+// 1. Looks up the route the request is headed for, once
+// 2. Stores its registry.Meta in the context for Auth and Quota
+// 3. Answers 503 once the route's Timeout has passed, and cancels the
+// request context then (see http.TimeoutHandler); a storage call without
+// a context runs on, but its result is dropped
+//
+// Requests no route matches carry no meta and get the defaults.
+func Routes(reg *registry.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			meta, _, ok := reg.Lookup(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			r = r.WithContext(registry.WithRoute(r.Context(), meta))
+			if meta.Timeout > 0 {
+				http.TimeoutHandler(next, meta.Timeout, errRouteTimeout.String()).ServeHTTP(&timeoutWriter{ResponseWriter: w}, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// timeoutWriter labels http.TimeoutHandler's 503 body as a problem; the
+// handler's own responses come with their Content-Type
+type timeoutWriter struct {
+	http.ResponseWriter
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	if status == http.StatusServiceUnavailable && tw.Header().Get("Content-Type") == "" {
+		tw.Header().Set("Content-Type", response.ProblemJSON)
+	}
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap exposes the original writer to http.ResponseController
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// 🧩 Tenant resolves the tenant for every non-admin request
+// ---------------------------------------------------------
+// 1. Reads the slug from cfg.Header, else from the subdomain of cfg.BaseDomain
+// 2. Looks the tenant up in storage
+// 3. Checks the credential Auth accepted belongs to that tenant
+// 4. Stores it in the request context (see tenant.Scope)
+//
+// Missing slug → 400, unknown slug → 404, another tenant's credentials →
+// 403. Admin, auth, version, verify, shared-link, /debug and /metrics
+// routes and the dashboard's static files are not tenant-scoped.
+func Tenant(cfg config.Tenancy, tenants storage.TenantStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/api/admin/") || strings.HasPrefix(r.URL.Path, "/api/auth/") || r.URL.Path == "/api/version" ||
+				r.URL.Path == "/api/verify" || strings.HasPrefix(r.URL.Path, "/api/shared/") || isDashboard(r.URL.Path) || isDebug(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			slug := resolveTenantSlug(cfg, r)
+			if slug == "" {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(
+					fmt.Errorf("tenant not specified (set the %s header)", cfg.Header),
+				))
+				return
+			}
+
+			t, err := tenants.GetTenantBySlug(slug)
+			if err != nil {
+				response.WriteJson(w, http.StatusNotFound, response.GeneralError(fmt.Errorf("unknown tenant %q", slug)))
+				return
+			}
+
+			// 🔐 The header and subdomain are the client's to pick; the
+			// credential decides which tenant it may pick
+			if bound, ok := r.Context().Value(credentialTenantKey{}).(credentialTenant); ok && !bound.allows(t) {
+				response.WriteJson(w, http.StatusForbidden, response.GeneralError(fmt.Errorf("credentials are not valid for tenant %q", slug)))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenant.WithTenant(r.Context(), t)))
+		})
+	}
+}
+
+type credentialTenantKey struct{}
+
+// credentialTenant is the one tenant a credential reaches: by id for API
+// keys and tokens, by slug for OIDC identities. An identity without a
+// tenant claim reaches none. Without one in the context (the bootstrap
+// key, auth off) every tenant is open.
+type credentialTenant struct {
+	id   int64
+	slug string
+}
+
+func (c credentialTenant) allows(t types.Tenant) bool {
+	if c.slug != "" {
+		return c.slug == t.Slug
+	}
+	return c.id != 0 && c.id == t.ID
+}
+
+func resolveTenantSlug(cfg config.Tenancy, r *http.Request) string {
+	if slug := strings.TrimSpace(r.Header.Get(cfg.Header)); slug != "" {
+		return strings.ToLower(slug)
+	}
+
+	if cfg.BaseDomain == "" {
+		return ""
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	suffix := "." + strings.ToLower(cfg.BaseDomain)
+	host = strings.ToLower(host)
+	if !strings.HasSuffix(host, suffix) {
+		return ""
+	}
+	sub := strings.TrimSuffix(host, suffix)
+	// only the label directly left of the base domain: a.b.api.example.com → "" (ambiguous)
+	if strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
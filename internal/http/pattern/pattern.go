@@ -0,0 +1,52 @@
+// Package pattern matches requests against ServeMux patterns outside the
+// router, for policies configured per route ("GET /api/students",
+// "GET /api/student/{id}"): a pattern matches exactly as the route does,
+// and the most specific one wins.
+package pattern
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// Set is a list of patterns; the zero value matches nothing.
+type Set struct {
+	mux *http.ServeMux
+}
+
+// registeredAt is the source location ServeMux adds to conflict panics
+var registeredAt = regexp.MustCompile(` \(registered at [^)]*\)`)
+
+// -------------------------------------------------------------
+// NewSet() → Set over patterns; an invalid or clashing one is an error
+// -------------------------------------------------------------
+func NewSet(patterns ...string) (*Set, error) {
+	s := &Set{mux: http.NewServeMux()}
+	for _, p := range patterns {
+		if err := s.add(p); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// add registers p; ServeMux panics on bad or conflicting patterns
+func (s *Set) add(p string) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("invalid route %q: %s", p, registeredAt.ReplaceAllString(fmt.Sprint(v), ""))
+		}
+	}()
+	s.mux.Handle(p, http.NotFoundHandler())
+	return nil
+}
+
+// Match returns the pattern r matches, as it was given to NewSet
+func (s *Set) Match(r *http.Request) (string, bool) {
+	if s == nil || s.mux == nil {
+		return "", false
+	}
+	_, p := s.mux.Handler(r)
+	return p, p != ""
+}
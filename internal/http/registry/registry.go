@@ -0,0 +1,119 @@
+// Package registry is the router plus what the middleware needs to know
+// about each route: whether it needs credentials, which scope, how it is
+// charged against quotas and how long it may run. Routes declare it where
+// they are registered; middleware.Routes looks it up once per request and
+// the rest of the chain reads it from the context.
+package registry
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Quota charge classes
+const (
+	// QuotaMethod charges GET/HEAD/OPTIONS as reads, anything else as a write
+	QuotaMethod = ""
+	QuotaRead   = "read"
+	QuotaWrite  = "write"
+	// QuotaFree isn't charged at all
+	QuotaFree = "free"
+)
+
+// Meta is what a route declares about itself; the zero value is an
+// authenticated route needing read or write scope by method
+type Meta struct {
+	// Public routes skip authentication: they need no credentials or
+	// check their own (token exchange, the dashboard's static files)
+	Public bool `json:"public,omitempty"`
+	// Scope the credentials must grant; empty is apikey.RequiredScope
+	Scope string `json:"scope,omitempty"`
+	// Quota is the charge class, one of the Quota* constants
+	Quota string `json:"quota,omitempty"`
+	// Timeout answers 503 and cancels the request context after this long;
+	// 0 is none
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// Common metas
+var (
+	// Public needs no credentials
+	Public = Meta{Public: true}
+	// Admin needs the admin scope
+	Admin = Meta{Scope: types.ScopeAdmin}
+)
+
+// Registry is an http.ServeMux that keeps each pattern's Meta
+type Registry struct {
+	mux   *http.ServeMux
+	metas map[string]Meta
+}
+
+// -------------------------------------------------------------
+// New() → Empty registry
+// -------------------------------------------------------------
+func New() *Registry {
+	return &Registry{mux: http.NewServeMux(), metas: map[string]Meta{}}
+}
+
+// Handle registers h for pattern with meta (the zero Meta when omitted).
+// Like ServeMux it panics on an invalid or clashing pattern.
+func (reg *Registry) Handle(pattern string, h http.Handler, meta ...Meta) {
+	reg.mux.Handle(pattern, h)
+	if len(meta) > 0 {
+		reg.metas[pattern] = meta[0]
+	} else {
+		reg.metas[pattern] = Meta{}
+	}
+}
+
+func (reg *Registry) HandleFunc(pattern string, h http.HandlerFunc, meta ...Meta) {
+	reg.Handle(pattern, h, meta...)
+}
+
+func (reg *Registry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reg.mux.ServeHTTP(w, r)
+}
+
+// -------------------------------------------------------------
+// Lookup() → Meta of the route r will be routed to
+// -------------------------------------------------------------
+// A request the router would redirect (/admin → /admin/) gets the meta of
+// the target; one no route matches gets ok false.
+func (reg *Registry) Lookup(r *http.Request) (meta Meta, pattern string, ok bool) {
+	_, pattern = reg.mux.Handler(r)
+	meta, ok = reg.metas[pattern]
+	return meta, pattern, ok
+}
+
+// Routes lists every registered route with its meta, sorted by pattern
+func (reg *Registry) Routes() []types.Route {
+	routes := make([]types.Route, 0, len(reg.metas))
+	for pattern, meta := range reg.metas {
+		route := types.Route{Pattern: pattern, Public: meta.Public, Scope: meta.Scope, Quota: meta.Quota}
+		if meta.Timeout > 0 {
+			route.Timeout = meta.Timeout.String()
+		}
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Pattern < routes[j].Pattern })
+	return routes
+}
+
+type ctxKey struct{}
+
+// WithRoute stores the matched route's meta in ctx
+func WithRoute(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, ctxKey{}, meta)
+}
+
+// FromContext is the meta middleware.Routes found; ok is false for
+// requests no route matches
+func FromContext(ctx context.Context) (Meta, bool) {
+	meta, ok := ctx.Value(ctxKey{}).(Meta)
+	return meta, ok
+}
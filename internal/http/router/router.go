@@ -0,0 +1,84 @@
+// Package router groups routes that share middleware and a default
+// registry.Meta, over a registry.Registry (and so a plain ServeMux): the
+// public, API and admin routes each get their own stack without a
+// third-party framework. Chain-wide middleware (auth, quotas, tenancy,
+// compression) still wraps the whole router in routes.New; group
+// middleware runs inside it, right around the handlers.
+package router
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+)
+
+// Middleware wraps a handler, like every constructor in the middleware package returns
+type Middleware = func(http.Handler) http.Handler
+
+// Router registers routes on a shared registry with its group's middleware
+// and meta. Register every route before serving.
+type Router struct {
+	reg  *registry.Registry
+	mws  []Middleware
+	meta registry.Meta
+}
+
+// -------------------------------------------------------------
+// New() → Root router on a fresh registry
+// -------------------------------------------------------------
+func New() *Router {
+	return &Router{reg: registry.New()}
+}
+
+// Registry is what the routes are registered on, for middleware.Routes
+func (rt *Router) Registry() *registry.Registry {
+	return rt.reg
+}
+
+// Use adds middleware to the routes registered on rt from now on (and to
+// groups created from now on); the first one added runs first
+func (rt *Router) Use(mws ...Middleware) {
+	rt.mws = append(rt.mws, mws...)
+}
+
+// -------------------------------------------------------------
+// Group() → Child router with rt's middleware and meta (or the given one)
+// -------------------------------------------------------------
+// Middleware the group Uses doesn't reach rt or its other groups.
+func (rt *Router) Group(meta ...registry.Meta) *Router {
+	child := &Router{reg: rt.reg, mws: slices.Clone(rt.mws), meta: rt.meta}
+	if len(meta) > 0 {
+		child.meta = meta[0]
+	}
+	return child
+}
+
+// With is a group with extra middleware, for one route or a few:
+// api.With(mw).HandleFunc(...)
+func (rt *Router) With(mws ...Middleware) *Router {
+	child := rt.Group()
+	child.Use(mws...)
+	return child
+}
+
+// Handle registers h for pattern behind the group's middleware, with the
+// group's meta unless one is given (it replaces the group's, not merges)
+func (rt *Router) Handle(pattern string, h http.Handler, meta ...registry.Meta) {
+	m := rt.meta
+	if len(meta) > 0 {
+		m = meta[0]
+	}
+	for i := len(rt.mws) - 1; i >= 0; i-- {
+		h = rt.mws[i](h)
+	}
+	rt.reg.Handle(pattern, h, m)
+}
+
+func (rt *Router) HandleFunc(pattern string, h http.HandlerFunc, meta ...registry.Meta) {
+	rt.Handle(pattern, h, meta...)
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.reg.ServeHTTP(w, r)
+}
@@ -0,0 +1,433 @@
+package routes
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/adminui"
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/challenge"
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/export"
+	"github.com/manish-npx/go-student-api/internal/featureflag"
+	"github.com/manish-npx/go-student-api/internal/hashid"
+	"github.com/manish-npx/go-student-api/internal/health"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/admin"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/auth"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/debug"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/document"
+	exports "github.com/manish-npx/go-student-api/internal/http/handlers/export"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/guardian"
+	probes "github.com/manish-npx/go-student-api/internal/http/handlers/health"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/invoice"
+	portals "github.com/manish-npx/go-student-api/internal/http/handlers/portal"
+	reports "github.com/manish-npx/go-student-api/internal/http/handlers/report"
+	shares "github.com/manish-npx/go-student-api/internal/http/handlers/share"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/student"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/tenant"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/version"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+	"github.com/manish-npx/go-student-api/internal/http/router"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/maintenance"
+	"github.com/manish-npx/go-student-api/internal/metrics"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/oidc"
+	"github.com/manish-npx/go-student-api/internal/paging"
+	"github.com/manish-npx/go-student-api/internal/portal"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/report"
+	"github.com/manish-npx/go-student-api/internal/roster"
+	"github.com/manish-npx/go-student-api/internal/scheduler"
+	"github.com/manish-npx/go-student-api/internal/share"
+	"github.com/manish-npx/go-student-api/internal/snapshot"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/token"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/verification"
+	"github.com/manish-npx/go-student-api/internal/webhook"
+)
+
+// Deps are the backends the handlers run against; main and testkit build them.
+type Deps struct {
+	Storage storage.Storage
+	// Blobs holds uploaded files (photos, documents)
+	Blobs blob.Store
+	// Notifier sends emails; nil disables them
+	Notifier *notify.Notifier
+	// Jobs is the background job queue; nil when disabled
+	Jobs *jobs.Queue
+	// Scheduler runs periodic tasks; nil when disabled
+	Scheduler *scheduler.Scheduler
+	// Webhooks delivers the student events relayed from the outbox; nil without a job queue
+	Webhooks *webhook.Dispatcher
+	// Exports runs student exports as jobs; nil without a job queue
+	Exports *export.Exporter
+	// Snapshots takes student snapshots as jobs; nil without a job queue
+	Snapshots *snapshot.Snapshotter
+	// Tokens issues bearer tokens; nil without auth.token_secret
+	Tokens *token.Issuer
+	// OIDC verifies identity provider ID tokens; nil without auth.oidc.issuer
+	OIDC *oidc.Verifier
+	// Challenge keeps bots off challenge.routes; nil when there is nothing to check
+	Challenge *challenge.Guard
+	// Quotas charges daily per-subject quotas; nil when quotas are disabled
+	Quotas *quota.Tracker
+	// Portal signs students in to /api/me; nil when the portal is disabled
+	Portal *portal.Portal
+	// Sharer signs read-only share links; nil without sharing.secret
+	Sharer *share.Signer
+	// Verifier emails verification links; nil when verification is disabled
+	Verifier *verification.Verifier
+	// Sync runs roster sync connectors; nil without sync.connectors
+	Sync *roster.Syncer
+	// Health watches the database; nil for backends without a ping (memory)
+	Health *health.Monitor
+	// Metrics counts the storage calls made through Storage; nil when disabled
+	Metrics *metrics.Storage
+	// TaskMetrics counts the Scheduler's runs; nil when disabled
+	TaskMetrics *metrics.Tasks
+	// Maintenance is the maintenance-mode switch
+	Maintenance *maintenance.Mode
+	// Flags decides which gated features callers get
+	Flags *featureflag.Set
+	// Paging holds the list limits (pagination), which follow reloads
+	Paging *paging.Limits
+	// AccessLog receives the access log lines (logger.access); nil is stdout
+	AccessLog io.Writer
+}
+
+// 🧩 New registers every API route on a fresh router, each with what the
+// middleware needs to know about it (see registry.Meta).
+// Kept outside main.go so tests can mount the exact same router.
+func New(cfg *config.Config, deps Deps) http.Handler {
+	return accessLog(cfg, deps)(newHandler(cfg, deps))
+}
+
+// newHandler is New without the access log, which Split mounts per port
+func newHandler(cfg *config.Config, deps Deps) http.Handler {
+	store := deps.Storage
+	hrefs := links.New(cfg.HttpServer.BaseURL)
+	custom := customfield.New(cfg.CustomFields)
+	reportCache := report.New(cfg.Reports)
+	// 🕰️ Validated at load; UTC is the safe fallback for hand-built configs
+	display, err := clock.Zone(cfg.Time.DisplayTimezone)
+	if err != nil {
+		display = time.UTC
+	}
+	// 🔣 nil (ids stay numeric) without enumeration.hashid_salt
+	ids := hashid.New(cfg.Enumeration.HashIDSalt, cfg.Enumeration.HashIDMinLength)
+	rt := router.New()
+	// 🧭 Public routes need no credentials, admin ones the admin scope and
+	// are audited; the rest is the API proper. Student portal routes check
+	// their own credentials, a portal session. Hashids are for clients:
+	// admin routes keep numeric ids.
+	public := rt.Group(registry.Public)
+	public.Use(middleware.HashIDs(ids))
+	ops := rt.Group(registry.Admin)
+	ops.Use(middleware.Audit())
+	me := rt.Group(registry.Public)
+	me.Use(middleware.HashIDs(ids), middleware.Portal(deps.Portal, store, deps.Tokens))
+	route := rt.Group()
+	// 🆔 /api/student/{id} takes the public id (ULID) as well
+	route.Use(middleware.HashIDs(ids), middleware.PublicID(store))
+
+	route.HandleFunc("GET /api/version", version.Get(store, cfg.DBType), registry.Meta{Quota: registry.QuotaFree})
+	route.HandleFunc("POST /api/student", student.New(store, custom, deps.Notifier, deps.Verifier, hrefs))
+	route.HandleFunc("GET /api/student/{id}", student.GetById(store, hrefs))
+	route.HandleFunc("GET /api/students", student.GetList(store, custom, hrefs, deps.Paging, deps.Flags))
+	route.HandleFunc("GET /api/students/count", student.Count(store, custom))
+	// only reads, for all it's a POST
+	route.HandleFunc("POST /api/students/batch-get", student.BatchGet(store, hrefs), registry.Meta{Scope: types.ScopeRead, Quota: registry.QuotaRead, Timeout: 30 * time.Second})
+	route.HandleFunc("PUT /api/students/by-email/{email}", student.UpsertByEmail(store, custom, deps.Notifier, deps.Verifier, hrefs))
+	route.HandleFunc("PUT /api/student/{id}", student.UpdateById(store, custom, hrefs))
+	route.HandleFunc("PATCH /api/students/bulk", student.BulkUpdate(store, custom))
+	route.HandleFunc("DELETE /api/students/bulk", student.BulkDelete(store))
+	route.HandleFunc("DELETE /api/student/{id}", student.DeleteById(store))
+	route.HandleFunc("GET /api/students/trash", student.GetTrash(store))
+	route.HandleFunc("POST /api/student/{id}/restore", student.RestoreById(store, hrefs))
+	route.HandleFunc("POST /api/student/{id}/photo", student.UploadPhoto(store, deps.Blobs, cfg.Photos.MaxBytes))
+	route.HandleFunc("GET /api/student/{id}/photo", student.GetPhoto(store, deps.Blobs))
+
+	// ✉️ Email verification; the emailed link needs no credentials
+	route.HandleFunc("POST /api/student/{id}/verify", student.ResendVerification(store, deps.Verifier))
+	public.HandleFunc("GET /api/verify", student.Verify(store, deps.Verifier))
+
+	// 🎓 Student self-service portal; staff manage the passwords
+	route.HandleFunc("PUT /api/student/{id}/password", portals.SetPassword(store, deps.Portal))
+	route.HandleFunc("DELETE /api/student/{id}/password", portals.DeletePassword(store, deps.Portal))
+	public.HandleFunc("POST /api/me/login", portals.Login(store, deps.Portal))
+	me.HandleFunc("POST /api/me/logout", portals.Logout(store, deps.Portal))
+	me.HandleFunc("GET /api/me", portals.Me(store))
+	me.HandleFunc("PUT /api/me/password", portals.ChangePassword(store, deps.Portal))
+
+	// 🛡️ GDPR (subject access and erasure)
+	route.HandleFunc("GET /api/student/{id}/data-export", student.DataExport(store, deps.Blobs))
+	route.HandleFunc("DELETE /api/student/{id}/erase", student.Erase(store, deps.Blobs))
+
+	// 📎 Documents (transcripts, ID scans)
+	route.HandleFunc("POST /api/student/{id}/documents", document.New(store, deps.Blobs, cfg.Documents.MaxBytes, cfg.Blob.PresignExpiry))
+	route.HandleFunc("GET /api/student/{id}/documents", document.GetList(store, deps.Blobs, cfg.Blob.PresignExpiry))
+	route.HandleFunc("GET /api/student/{id}/documents/{docId}", document.GetById(store, deps.Blobs, cfg.Blob.PresignExpiry))
+	route.HandleFunc("GET /api/student/{id}/documents/{docId}/content", document.Download(store, deps.Blobs))
+	route.HandleFunc("DELETE /api/student/{id}/documents/{docId}", document.DeleteById(store, deps.Blobs))
+
+	// 🔗 Signed, expiring read-only links for people without an account
+	route.HandleFunc("POST /api/student/{id}/share", shares.Student(store, deps.Sharer))
+	route.HandleFunc("POST /api/student/{id}/documents/{docId}/share", shares.Document(store, deps.Sharer))
+	public.HandleFunc("GET /api/shared/{token}", shares.Get(store, deps.Blobs, deps.Sharer))
+
+	// 👪 Guardians (parents and other contacts) and their students
+	route.HandleFunc("POST /api/guardians", guardian.New(store))
+	route.HandleFunc("GET /api/guardians", guardian.GetList(store))
+	route.HandleFunc("GET /api/guardians/{id}", guardian.GetById(store))
+	route.HandleFunc("PUT /api/guardians/{id}", guardian.UpdateById(store))
+	route.HandleFunc("DELETE /api/guardians/{id}", guardian.DeleteById(store))
+	route.HandleFunc("GET /api/student/{id}/guardians", guardian.GetForStudent(store))
+	route.HandleFunc("PUT /api/student/{id}/guardians/{guardianId}", guardian.Link(store))
+	route.HandleFunc("DELETE /api/student/{id}/guardians/{guardianId}", guardian.Unlink(store))
+
+	// 🧾 Invoices (fees billed to students)
+	route.HandleFunc("POST /api/student/{id}/invoices", invoice.New(store))
+	route.HandleFunc("GET /api/student/{id}/invoices", invoice.GetList(store))
+	route.HandleFunc("GET /api/invoices/overdue", invoice.Overdue(store))
+	route.HandleFunc("GET /api/invoices/{id}", invoice.GetById(store))
+	route.HandleFunc("POST /api/invoices/{id}/pay", invoice.Pay(store))
+
+	// 📈 Operational reports (GROUP BY in the database, cached per tenant)
+	route.HandleFunc("GET /api/reports/age-distribution", reports.AgeDistribution(store, reportCache))
+	route.HandleFunc("GET /api/reports/birthdays", reports.Birthdays(store, reportCache, display))
+
+	// 📤 Exports (async, written to the blob store)
+	route.HandleFunc("POST /api/exports", exports.New(deps.Exports, hrefs))
+	route.HandleFunc("GET /api/exports/{id}", exports.GetById(deps.Exports, hrefs, cfg.Blob.PresignExpiry))
+	route.HandleFunc("GET /api/exports/{id}/download", exports.Download(deps.Exports))
+
+	// 📊 Admin
+	ops.HandleFunc("GET /api/admin/stats", admin.Stats(store, display))
+	ops.HandleFunc("POST /api/admin/purge", admin.Purge(store, deps.Blobs, cfg.Trash.Retention, deps.Jobs))
+	ops.HandleFunc("POST /api/admin/encryption/reencrypt", admin.Reencrypt(store, deps.Jobs))
+	ops.HandleFunc("GET /api/admin/backup", admin.Backup(store))
+
+	// 📸 Snapshots (async, written to the blob store) and their diffs
+	ops.HandleFunc("POST /api/admin/snapshots", admin.CreateSnapshot(store, deps.Snapshots, hrefs))
+	ops.HandleFunc("GET /api/admin/snapshots", admin.GetSnapshots(store, deps.Snapshots))
+	ops.HandleFunc("GET /api/admin/snapshots/{id}", admin.GetSnapshotById(store, deps.Snapshots))
+	ops.HandleFunc("GET /api/admin/snapshots/{a}/{b}/diff", admin.DiffSnapshots(store, deps.Snapshots))
+
+	// 🧩 Custom student fields
+	ops.HandleFunc("GET /api/admin/custom-fields", admin.GetCustomFields(store, custom))
+	ops.HandleFunc("POST /api/admin/custom-fields", admin.CreateCustomField(store, custom))
+	ops.HandleFunc("DELETE /api/admin/custom-fields/{key}", admin.DeleteCustomField(store, custom))
+
+	// 📧 Rename an email domain across the tenant's students
+	ops.HandleFunc("POST /api/admin/email-domains/migrate", admin.MigrateEmailDomain(store))
+
+	// 🔄 Roster sync from external systems
+	ops.HandleFunc("GET /api/admin/sync", admin.GetSyncConnectors(deps.Sync))
+	ops.HandleFunc("POST /api/admin/sync/{name}", admin.RunSync(deps.Sync))
+
+	// ⚙️ Background jobs
+	ops.HandleFunc("GET /api/admin/jobs", admin.GetJobs(store))
+	ops.HandleFunc("GET /api/admin/jobs/{id}", admin.GetJobById(store))
+	ops.HandleFunc("POST /api/admin/jobs/{id}/retry", admin.RetryJob(store))
+	ops.HandleFunc("GET /api/admin/schedules", admin.Schedules(deps.Scheduler))
+
+	// 🚧 Maintenance mode
+	ops.HandleFunc("GET /api/admin/maintenance", admin.GetMaintenance(deps.Maintenance))
+	ops.HandleFunc("PUT /api/admin/maintenance", admin.SetMaintenance(deps.Maintenance))
+
+	// 🚩 Feature flags
+	ops.HandleFunc("GET /api/admin/feature-flags", admin.GetFeatureFlags(deps.Flags))
+
+	// 🔬 Runtime diagnostics; pprof/expvar only when debug is enabled
+	ops.HandleFunc("GET /api/admin/runtime", admin.Runtime(store))
+	ops.HandleFunc("GET /api/admin/routes", admin.Routes(rt.Registry()))
+	ops.HandleFunc("GET /api/admin/explain/students", admin.ExplainStudents(store, custom, deps.Paging))
+	ops.HandleFunc("GET /api/admin/indexes", admin.Indexes(store))
+	if cfg.Debug.Enabled && cfg.Debug.Addr == "" {
+		ops.Handle("/debug/", debug.Handler())
+	}
+
+	// 📈 Prometheus scrape endpoint (admin scope)
+	if deps.Metrics != nil {
+		ops.Handle("GET /metrics", metrics.Handler(deps.Metrics, deps.TaskMetrics))
+	}
+
+	// 🎟️ Bearer tokens (authenticated by the handlers themselves)
+	public.HandleFunc("POST /api/auth/token", auth.Token(store, deps.Tokens))
+	public.HandleFunc("POST /api/auth/refresh", auth.Refresh(deps.Tokens))
+	public.HandleFunc("POST /api/auth/logout", auth.Logout(deps.Tokens))
+
+	// 🔑 API keys
+	ops.HandleFunc("POST /api/admin/api-keys", admin.CreateAPIKey(store))
+	ops.HandleFunc("GET /api/admin/api-keys", admin.GetAPIKeys(store))
+	ops.HandleFunc("DELETE /api/admin/api-keys/{id}", admin.RevokeAPIKey(store))
+
+	// 🎭 Impersonation for support debugging (bearer tokens, time-limited)
+	ops.HandleFunc("POST /api/admin/impersonations", admin.CreateImpersonation(store, deps.Tokens, deps.Portal))
+	ops.HandleFunc("DELETE /api/admin/impersonations/{id}", admin.EndImpersonation(deps.Tokens))
+
+	// 🔢 Daily quotas
+	ops.HandleFunc("GET /api/admin/quotas", admin.GetQuotas(deps.Quotas))
+	ops.HandleFunc("GET /api/admin/quotas/{subject}", admin.GetQuota(deps.Quotas))
+	ops.HandleFunc("PUT /api/admin/quotas/{subject}", admin.SetQuota(deps.Quotas))
+	ops.HandleFunc("DELETE /api/admin/quotas/{subject}", admin.ResetQuota(deps.Quotas))
+
+	// 🪝 Webhooks
+	ops.HandleFunc("POST /api/admin/webhooks", admin.CreateWebhook(store, deps.Webhooks))
+	ops.HandleFunc("GET /api/admin/webhooks", admin.GetWebhooks(store))
+	ops.HandleFunc("GET /api/admin/webhooks/{id}", admin.GetWebhookById(store))
+	ops.HandleFunc("DELETE /api/admin/webhooks/{id}", admin.DeleteWebhookById(store))
+	ops.HandleFunc("GET /api/admin/webhooks/{id}/deliveries", admin.GetWebhookDeliveries(store))
+
+	// 🖥️ Embedded dashboard (static files; its API calls are authenticated as usual)
+	if cfg.AdminUI.Enabled {
+		public.Handle("GET /admin/", adminui.Handler())
+	}
+
+	var handler http.Handler = rt
+
+	// 🧵 Identical GETs in flight at once share one run, innermost so each is still authorized
+	if cfg.Collapse.Enabled {
+		handler = middleware.Collapse(cfg.Collapse)(handler)
+	}
+
+	// 🏫 Multi-tenancy
+	tenants, ok := storage.As[storage.TenantStore](store)
+	if ok {
+		ops.HandleFunc("POST /api/admin/tenants", tenant.New(tenants))
+		ops.HandleFunc("GET /api/admin/tenants", tenant.GetList(tenants))
+	}
+	if cfg.Tenancy.Enabled {
+		if !ok {
+			// refusing to start beats silently serving every tenant's data
+			panic("tenancy is enabled but the storage backend does not support tenants")
+		}
+		handler = middleware.Tenant(cfg.Tenancy, tenants)(handler)
+	}
+
+	// 🔢 Quotas need the subject Auth resolves, so they run just inside it
+	if deps.Quotas != nil {
+		handler = middleware.Quota(deps.Quotas)(handler)
+	}
+
+	// 🤖 Bots are turned away before tenant lookups, once Auth has let staff in
+	if deps.Challenge != nil {
+		for _, pattern := range deps.Challenge.Routes() {
+			if !slices.ContainsFunc(rt.Registry().Routes(), func(route types.Route) bool { return route.Pattern == pattern }) {
+				// a typo would leave the route it meant unguarded
+				panic("challenge.routes: no route " + pattern)
+			}
+		}
+		handler = middleware.Challenge(deps.Challenge, rt.Registry(), cfg.Auth.Enabled)(handler)
+	}
+
+	// 🔑 Authentication runs before tenant lookups
+	if cfg.Auth.Enabled {
+		keys, ok := storage.As[storage.APIKeyStore](store)
+		if !ok {
+			panic("auth is enabled but the storage backend does not support API keys")
+		}
+		handler = middleware.Auth(cfg.Auth, keys, deps.Tokens, deps.OIDC)(handler)
+	}
+
+	// 🗺️ The matched route's meta (public, scope, quota class, timeout) for Auth and Quota
+	handler = middleware.Routes(rt.Registry())(handler)
+
+	// 🕵️ Every 404 alike, outside Auth and tenancy so theirs are covered too
+	if cfg.Enumeration.UniformNotFound {
+		handler = middleware.UniformNotFound(cfg.Enumeration.NotFoundDelay)(handler)
+	}
+
+	// 📦 Debug payload logging, inside compression so bodies are plain
+	if p := cfg.Logger.Payloads; p.Enabled || (cfg.Env == "dev" && p.Header != "") {
+		handler = middleware.Payloads(p, cfg.Env)(handler)
+	}
+
+	// 🐪 snake_case/camelCase keys, inside compression so it rewrites plain JSON
+	handler = middleware.Naming(cfg.JSONNaming)(handler)
+
+	// 🧾 XML/MessagePack per Accept, encoding the renamed keys before compression
+	handler = middleware.Negotiate()(handler)
+
+	// 📏 Bodies are capped before any middleware reads them whole
+	handler = middleware.MaxBody(cfg.HttpServer.MaxBodyBytes)(handler)
+
+	// 🗜️ Wraps all API middleware, so every route (and error) can be compressed
+	if cfg.Compression.Enabled {
+		handler = middleware.Compress(cfg.Compression)(handler)
+	}
+
+	// 🚧 While maintenance mode is on, only operators get through
+	handler = middleware.Maintenance(deps.Maintenance)(handler)
+
+	// 🩺 While the database is down, fail fast instead of with driver errors
+	if deps.Health != nil {
+		handler = middleware.Degraded(deps.Health)(handler)
+	}
+
+	// 🩺 Probes sit outside auth and tenancy: orchestrators send no credentials
+	probed := http.NewServeMux()
+	probed.HandleFunc("GET /healthz", probes.Live())
+	probed.HandleFunc("GET /readyz", probes.Ready(deps.Health))
+	probed.Handle("/", handler)
+	handler = probed
+
+	// 🗄️ Cache-Control per route; errors from any layer stay uncacheable
+	cache, err := response.NewCachePolicy(cfg.Caching.Reads, cfg.Caching.Writes, cacheRules(cfg.Caching), "Authorization", "Accept", apikey.Header, cfg.Tenancy.Header, cfg.JSONNaming.Header)
+	if err != nil {
+		panic(err)
+	}
+	handler = middleware.CacheControl(cache)(handler)
+
+	// 🪪 Request ids wrap everything, so even rejected requests carry one
+	handler = middleware.RequestID()(handler)
+
+	return handler
+}
+
+// -------------------------------------------------------------
+// Split() → Handlers for the public and the admin listener
+// -------------------------------------------------------------
+// The admin port (http_server.admin_address) serves everything New does:
+// the dashboard calls the student API too. The public port serves the same
+// router with the admin API, dashboard, debug, metrics and probes hidden,
+// so firewalling the admin port is enough to keep them private.
+func Split(cfg *config.Config, deps Deps) (public, admin http.Handler) {
+	handler := newHandler(cfg, deps)
+	// 📜 Outside PublicOnly, so the public port logs the paths it hides too
+	log := accessLog(cfg, deps)
+	return log(middleware.PublicOnly()(handler)), log(handler)
+}
+
+// accessLog mounts the access log (logger.access) outermost, writing to
+// deps.AccessLog or stdout; without it, handlers pass through
+func accessLog(cfg *config.Config, deps Deps) func(http.Handler) http.Handler {
+	if !cfg.Logger.Access.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	out := deps.AccessLog
+	if out == nil {
+		out = os.Stdout
+	}
+	return middleware.AccessLog(cfg.Logger.Access.Format, out)
+}
+
+// cacheRules converts caching.rules for the response package
+func cacheRules(cfg config.Caching) []response.CacheRule {
+	rules := make([]response.CacheRule, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		rules = append(rules, response.CacheRule{Route: rule.Route, Value: rule.CacheControl})
+	}
+	return rules
+}
@@ -0,0 +1,144 @@
+// Package httpclient builds the HTTP clients the service uses for outbound
+// calls (webhooks, identity providers, email or storage APIs). Every client
+// has a timeout, retries transient failures with backoff, and propagates the
+// X-Request-ID and traceparent of the request that caused the call.
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/backoff"
+)
+
+// maxBackoff caps the delay between attempts; calls run inside requests or
+// jobs, so long waits only hold those up
+const maxBackoff = 10 * time.Second
+
+// Options tune one client.
+type Options struct {
+	// Timeout bounds a whole call, retries included (0 = 30s)
+	Timeout time.Duration
+	// Retries is how many times a failed call is repeated (0 = never)
+	Retries int
+	// Backoff is the first retry delay, doubled per attempt (0 = 200ms)
+	Backoff time.Duration
+	// UserAgent is set on requests that don't have one
+	UserAgent string
+}
+
+// -------------------------------------------------------------
+// New() → *http.Client with timeout, retries and trace propagation
+// -------------------------------------------------------------
+// Only requests safe to repeat are retried: idempotent methods, or any
+// method carrying an Idempotency-Key, and only when the body can be replayed.
+func New(opts Options) *http.Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 200 * time.Millisecond
+	}
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: &transport{next: http.DefaultTransport, opts: opts},
+	}
+}
+
+type transport struct {
+	next http.RoundTripper
+	opts Options
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	if tr := TraceFrom(req.Context()); tr != (Trace{}) {
+		if tr.RequestID != "" && req.Header.Get(HeaderRequestID) == "" {
+			req.Header.Set(HeaderRequestID, tr.RequestID)
+		}
+		if parent := tr.child(); parent != "" && req.Header.Get(HeaderTraceParent) == "" {
+			req.Header.Set(HeaderTraceParent, parent)
+		}
+	}
+	if t.opts.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.opts.UserAgent)
+	}
+
+	retries := t.opts.Retries
+	if !replayable(req) {
+		retries = 0
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := t.next.RoundTrip(req)
+		if attempt > retries || !transient(res, err) {
+			return res, err
+		}
+
+		delay := backoff.Exponential(t.opts.Backoff, attempt, maxBackoff)
+		if res != nil {
+			delay = max(delay, retryAfter(res))
+			// drain a little so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+			res.Body.Close()
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// replayable reports whether req may be sent more than once
+func replayable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// transient reports whether a failed attempt is worth repeating
+func transient(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads a Retry-After given in seconds, capped at maxBackoff
+func retryAfter(res *http.Response) time.Duration {
+	secs, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	return min(time.Duration(secs)*time.Second, maxBackoff)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// Propagated headers
+const (
+	HeaderRequestID = "X-Request-ID"
+	// HeaderTraceParent is the W3C Trace Context header:
+	// "00-<32 hex trace id>-<16 hex parent id>-<2 hex flags>"
+	HeaderTraceParent = "traceparent"
+)
+
+// maxRequestID bounds caller-supplied ids so they can't bloat logs
+const maxRequestID = 128
+
+// Trace identifies the request that caused an outbound call. It is JSON so
+// queued jobs (webhook deliveries, emails) can carry it past the request.
+type Trace struct {
+	RequestID   string `json:"request_id,omitempty"`
+	TraceParent string `json:"traceparent,omitempty"`
+}
+
+type traceKey struct{}
+
+// WithTrace returns ctx carrying t; outbound requests made with it propagate t
+func WithTrace(ctx context.Context, t Trace) context.Context {
+	if t == (Trace{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// TraceFrom returns the Trace stored by WithTrace (zero when there is none)
+func TraceFrom(ctx context.Context) Trace {
+	t, _ := ctx.Value(traceKey{}).(Trace)
+	return t
+}
+
+// -------------------------------------------------------------
+// IncomingTrace() → Trace of an inbound request, filling in what's missing
+// -------------------------------------------------------------
+// A usable X-Request-ID is kept, otherwise a random one is generated. A valid
+// traceparent is kept so our calls join the caller's trace; otherwise a new
+// trace is started.
+func IncomingTrace(requestID, traceParent string) Trace {
+	if !validRequestID(requestID) {
+		requestID = randomHex(16)
+	}
+	if !ValidTraceParent(traceParent) {
+		traceParent = "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+	}
+	return Trace{RequestID: requestID, TraceParent: strings.ToLower(traceParent)}
+}
+
+// ValidTraceParent reports whether s is a version-00 traceparent with
+// non-zero trace and parent ids
+func ValidTraceParent(s string) bool {
+	parts := strings.Split(s, "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return false
+	}
+	for i, n := range []int{2, 32, 16, 2} {
+		if len(parts[i]) != n || !isHex(parts[i]) {
+			return false
+		}
+	}
+	return strings.Trim(parts[1], "0") != "" && strings.Trim(parts[2], "0") != ""
+}
+
+// child keeps the trace id and flags but names a new span as the parent,
+// since each outbound call is a new hop in the trace
+func (t Trace) child() string {
+	if !ValidTraceParent(t.TraceParent) {
+		return ""
+	}
+	return t.TraceParent[:36] + randomHex(8) + t.TraceParent[52:]
+}
+
+func validRequestID(s string) bool {
+	if s == "" || len(s) > maxRequestID {
+		return false
+	}
+	for _, c := range s {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
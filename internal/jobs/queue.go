@@ -0,0 +1,243 @@
+// Package jobs runs background work (emails, purges, exports) from a
+// database-backed queue, so queued work survives restarts and failed jobs
+// are retried with exponential backoff.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/backoff"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// maxBackoff caps the delay between attempts of one job
+const maxBackoff = time.Hour
+
+// Handler runs one job. Returning an error retries it (up to MaxAttempts)
+// unless the error is wrapped with Permanent.
+type Handler func(ctx context.Context, job types.Job) error
+
+type permanentError struct{ err error }
+
+func (p permanentError) Error() string { return p.err.Error() }
+func (p permanentError) Unwrap() error { return p.err }
+
+// ErrDisabled is returned by a nil *Queue (jobs disabled or unsupported backend)
+var ErrDisabled = errors.New("job queue is disabled")
+
+// Permanent marks err as not worth retrying (bad payload, missing record, ...)
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err (or anything it wraps) came from Permanent
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+type Queue struct {
+	store storage.JobStore
+	cfg   config.Jobs
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	// wake nudges idle workers when a job is enqueued
+	wake chan struct{}
+	wg   sync.WaitGroup
+	stop context.CancelFunc
+}
+
+// New returns nil when jobs are disabled or the backend has no job table;
+// a nil *Queue is safe to use and rejects Enqueue with ErrDisabled.
+func New(backend storage.Storage, cfg config.Jobs) *Queue {
+	store, ok := storage.As[storage.JobStore](backend)
+	if !cfg.Enabled || !ok {
+		return nil
+	}
+	return &Queue{
+		store:    store,
+		cfg:      cfg,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// -------------------------------------------------------------
+// Register() → Attach the handler for a job type (before Start)
+// -------------------------------------------------------------
+func (q *Queue) Register(jobType string, handler Handler) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// -------------------------------------------------------------
+// Enqueue() → Persist a job; payload is stored as JSON
+// -------------------------------------------------------------
+func (q *Queue) Enqueue(jobType string, payload any) (int64, error) {
+	if q == nil {
+		return 0, ErrDisabled
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("encode %s payload: %w", jobType, err)
+	}
+
+	id, err := q.store.EnqueueJob(types.Job{
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: q.cfg.MaxAttempts,
+		RunAt:       time.Now(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+// -------------------------------------------------------------
+// Start() → Requeue jobs orphaned by a crash, then launch the workers
+// -------------------------------------------------------------
+func (q *Queue) Start(ctx context.Context) {
+	if q == nil {
+		return
+	}
+	if reset, err := q.store.ResetRunningJobs(); err != nil {
+		slog.Error("❌ Could not requeue interrupted jobs", slog.String("error", err.Error()))
+	} else if reset > 0 {
+		slog.Warn("⚠️ Requeued jobs interrupted by the last shutdown", slog.Int64("count", reset))
+	}
+
+	ctx, q.stop = context.WithCancel(ctx)
+	for range max(q.cfg.Workers, 1) {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+	slog.Info("⚙️ Job workers started", slog.Int("workers", max(q.cfg.Workers, 1)))
+}
+
+// -------------------------------------------------------------
+// Stop() → Stop claiming jobs and wait for running ones to finish
+// -------------------------------------------------------------
+func (q *Queue) Stop(ctx context.Context) error {
+	if q == nil || q.stop == nil {
+		return nil
+	}
+	q.stop()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("jobs still running at shutdown; they will be requeued on next start")
+	}
+}
+
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain everything that is due before sleeping again
+		for ctx.Err() == nil && q.runNext() {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// runNext claims and runs one job; false when nothing was due
+func (q *Queue) runNext() bool {
+	job, ok, err := q.store.ClaimJob(time.Now())
+	if err != nil {
+		slog.Error("❌ Claiming job failed", slog.String("error", err.Error()))
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	q.mu.RLock()
+	handler, found := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	// A running job finishes even during shutdown; Stop waits for it
+	ctx, cancel := context.WithTimeout(context.Background(), q.cfg.Timeout)
+	defer cancel()
+
+	if !found {
+		err = Permanent(fmt.Errorf("no handler registered for job type %q", job.Type))
+	} else {
+		err = runSafely(ctx, handler, job)
+	}
+	q.finish(job, err)
+	return true
+}
+
+// runSafely turns a handler panic into a job failure instead of a crash
+func runSafely(ctx context.Context, handler Handler, job types.Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// finish records the outcome: success, a scheduled retry, or final failure
+func (q *Queue) finish(job types.Job, err error) {
+	log := slog.With(slog.Int64("job_id", job.ID), slog.String("type", job.Type), slog.Int("attempt", job.Attempts))
+
+	if err == nil {
+		if err := q.store.CompleteJob(job.ID); err != nil {
+			log.Error("❌ Recording job success failed", slog.String("error", err.Error()))
+		}
+		log.Info("✅ Job succeeded")
+		return
+	}
+
+	var retryAt *time.Time
+	if !IsPermanent(err) && job.Attempts < job.MaxAttempts {
+		at := time.Now().Add(backoff.Exponential(q.cfg.Backoff, job.Attempts, maxBackoff))
+		retryAt = &at
+	}
+
+	if ferr := q.store.FailJob(job.ID, err.Error(), retryAt); ferr != nil {
+		log.Error("❌ Recording job failure failed", slog.String("error", ferr.Error()))
+	}
+	if retryAt != nil {
+		log.Warn("⚠️ Job failed, retrying", slog.Time("retry_at", *retryAt), slog.String("error", err.Error()))
+		return
+	}
+	log.Error("❌ Job failed permanently", slog.String("error", err.Error()))
+}
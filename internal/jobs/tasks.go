@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/trash"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Built-in job types
+const (
+	TypeSendEmail  = storage.EmailJobType
+	TypePurgeTrash = "trash.purge"
+	TypeReencrypt  = "encryption.reencrypt"
+)
+
+// PurgePayload is the payload of a trash.purge job
+type PurgePayload struct {
+	// OlderThan is a Go duration such as "720h"
+	OlderThan string `json:"older_than"`
+}
+
+// -------------------------------------------------------------
+// RegisterDefaults() → Handlers for the built-in job types
+// -------------------------------------------------------------
+// Emails are moved onto the queue so they survive restarts.
+// encryption.reencrypt re-seals student PII after a key rotation.
+func RegisterDefaults(q *Queue, backend storage.Storage, blobs blob.Store, notifier *notify.Notifier) {
+	if q == nil {
+		return
+	}
+	if notifier != nil {
+		q.Register(TypeSendEmail, func(ctx context.Context, job types.Job) error {
+			var msg notify.Message
+			if err := json.Unmarshal(job.Payload, &msg); err != nil {
+				return Permanent(fmt.Errorf("decode email payload: %w", err))
+			}
+			return notifier.Deliver(ctx, msg)
+		})
+		notifier.UseQueue(func(msg notify.Message) error {
+			_, err := q.Enqueue(TypeSendEmail, msg)
+			return err
+		})
+	}
+
+	if trashStore, ok := storage.As[storage.TrashStore](backend); ok {
+		q.Register(TypePurgeTrash, func(ctx context.Context, job types.Job) error {
+			var payload PurgePayload
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return Permanent(fmt.Errorf("decode purge payload: %w", err))
+			}
+			olderThan, err := time.ParseDuration(payload.OlderThan)
+			if err != nil || olderThan < 0 {
+				return Permanent(errors.New("purge payload needs a non-negative older_than duration"))
+			}
+			_, err = trash.Purge(ctx, trashStore, blobs, olderThan)
+			return err
+		})
+	}
+
+	if sealed, ok := storage.As[storage.EncryptionStore](backend); ok {
+		q.Register(TypeReencrypt, func(ctx context.Context, job types.Job) error {
+			changed, err := sealed.ReencryptStudents()
+			if errors.Is(err, fieldcrypt.ErrDisabled) {
+				return Permanent(err)
+			}
+			if err != nil {
+				return err
+			}
+			slog.Info("🔐 Re-encrypted students", slog.Int64("count", changed))
+			return nil
+		})
+	}
+}
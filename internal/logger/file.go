@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// backupLayout is appended to the file name of rotated logs
+const backupLayout = "20060102-150405.000"
+
+// File is a log file that rotates itself by size or age and can be reopened
+// after an external tool (logrotate) moved it away.
+type File struct {
+	path string
+	cfg  config.LogRotation
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// -------------------------------------------------------------
+// OpenFile() → Append to path, creating the file if needed
+// -------------------------------------------------------------
+func OpenFile(path string, cfg config.LogRotation) (*File, error) {
+	f := &File{path: path, cfg: cfg}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.due(len(p)) {
+		if err := f.rotate(); err != nil {
+			// keep logging into the old file rather than losing lines
+			fmt.Fprintf(os.Stderr, "log rotation failed: %v\n", err)
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// -------------------------------------------------------------
+// Reopen() → Close and reopen the path (after logrotate renamed it)
+// -------------------------------------------------------------
+func (f *File) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	old := f.file
+	if err := f.open(); err != nil {
+		return err
+	}
+	return old.Close()
+}
+
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// -------------------------------------------------------------
+// WatchReopen() → Reopen on SIGHUP / SIGUSR1 until ctx is cancelled
+// -------------------------------------------------------------
+// SIGHUP also reloads the config; both listeners get the signal.
+func (f *File) WatchReopen(ctx context.Context) {
+	if len(reopenSignals) == 0 {
+		return
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, reopenSignals...)
+
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-sig:
+				if err := f.Reopen(); err != nil {
+					slog.Error("❌ Reopening log file failed", slog.String("error", err.Error()))
+					continue
+				}
+				slog.Info("📝 Log file reopened", slog.String("path", f.path), slog.String("signal", s.String()))
+			}
+		}
+	}()
+}
+
+// open (re)opens the path for appending; callers hold mu
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	f.file, f.size, f.openedAt = file, info.Size(), time.Now()
+	// an existing file's age counts from when it was last written
+	if info.Size() > 0 {
+		f.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// due reports whether writing n more bytes should start a new file
+func (f *File) due(n int) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.cfg.MaxSizeMB > 0 && f.size+int64(n) > int64(f.cfg.MaxSizeMB)<<20 {
+		return true
+	}
+	return f.cfg.MaxAge > 0 && time.Since(f.openedAt) >= f.cfg.MaxAge
+}
+
+// rotate renames the current file to <path>.<timestamp> and starts a fresh
+// one. The old file is only closed once the new one is open: if either step
+// fails, logging carries on into the file already open.
+func (f *File) rotate() error {
+	backup := f.path + "." + time.Now().Format(backupLayout)
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	old := f.file
+	if err := f.open(); err != nil {
+		// put the file back where logrotate and tail -F expect it
+		if undo := os.Rename(backup, f.path); undo != nil {
+			return errors.Join(err, undo)
+		}
+		return err
+	}
+	return errors.Join(old.Close(), f.prune())
+}
+
+// prune deletes the oldest backups beyond MaxBackups
+func (f *File) prune() error {
+	if f.cfg.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return err
+	}
+	backups = slices.DeleteFunc(backups, func(p string) bool {
+		_, err := time.Parse(backupLayout, strings.TrimPrefix(p, f.path+"."))
+		return err != nil
+	})
+	// the timestamp suffix sorts chronologically
+	slices.Sort(backups)
+	for len(backups) > f.cfg.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
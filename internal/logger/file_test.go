@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// backups lists the rotated copies of path, oldest first
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(matches)
+	return matches
+}
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotation(t *testing.T) {
+	line := bytes.Repeat([]byte("x"), 300<<10) // three fit in 1 MB, the fourth rotates
+
+	tests := []struct {
+		name        string
+		cfg         config.LogRotation
+		writes      int
+		wantBackups int
+		wantSize    int
+	}{
+		{name: "no limits", cfg: config.LogRotation{}, writes: 4, wantBackups: 0, wantSize: 4 * len(line)},
+		{name: "by size", cfg: config.LogRotation{MaxSizeMB: 1}, writes: 4, wantBackups: 1, wantSize: len(line)},
+		{name: "by size, twice", cfg: config.LogRotation{MaxSizeMB: 1}, writes: 7, wantBackups: 2, wantSize: len(line)},
+		{name: "by age", cfg: config.LogRotation{MaxAge: time.Nanosecond}, writes: 3, wantBackups: 2, wantSize: len(line)},
+		{name: "backups pruned", cfg: config.LogRotation{MaxAge: time.Nanosecond, MaxBackups: 2}, writes: 5, wantBackups: 2, wantSize: len(line)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			f, err := OpenFile(path, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			for range tt.writes {
+				// backups are named to the millisecond
+				time.Sleep(2 * time.Millisecond)
+				if n, err := f.Write(line); err != nil || n != len(line) {
+					t.Fatalf("Write = %d, %v", n, err)
+				}
+			}
+
+			if got := backups(t, path); len(got) != tt.wantBackups {
+				t.Errorf("backups = %v, want %d", got, tt.wantBackups)
+			}
+			if got := len(read(t, path)); got != tt.wantSize {
+				t.Errorf("current file has %d bytes, want %d", got, tt.wantSize)
+			}
+		})
+	}
+}
+
+// Pruning keeps the newest backups and leaves other files next to the log alone.
+func TestPruneKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	other := path + ".gz-archive"
+	if err := os.WriteFile(other, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenFile(path, config.LogRotation{MaxAge: time.Nanosecond, MaxBackups: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		time.Sleep(2 * time.Millisecond)
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := slices.DeleteFunc(backups(t, path), func(p string) bool { return p == other })
+	if len(got) != 1 || read(t, got[0]) != "second\n" {
+		t.Errorf("backups = %v, want only the one holding the second line", got)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("unrelated file pruned: %v", err)
+	}
+	if got := read(t, path); got != "third\n" {
+		t.Errorf("current file = %q, want the third line", got)
+	}
+}
+
+// An existing file's age counts from its last write, not from when it was opened.
+func TestRotateOldFileOnOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("yesterday\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenFile(path, config.LogRotation{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("today\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := backups(t, path)
+	if len(got) != 1 || read(t, got[0]) != "yesterday\n" {
+		t.Errorf("backups = %v, want the old file rotated away", got)
+	}
+	if got := read(t, path); got != "today\n" {
+		t.Errorf("current file = %q, want only the new line", got)
+	}
+}
+
+// After logrotate moves the file away, Reopen starts a new one at the path.
+func TestReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenFile(path, config.LogRotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Write([]byte("before\n"))
+
+	moved := path + ".1"
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("still old\n"))
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("after\n"))
+
+	if got := read(t, moved); got != "before\nstill old\n" {
+		t.Errorf("moved file = %q", got)
+	}
+	if got := read(t, path); got != "after\n" {
+		t.Errorf("reopened file = %q, want only the line after Reopen", got)
+	}
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// 🧩 ParseLevel accepts debug/info/warn/error (case-insensitive)
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: %w", s, err)
+	}
+	return level, nil
+}
+
+// -------------------------------------------------------------
+// Apply() → Configure the default slog logger; safe to call again on reload
+// -------------------------------------------------------------
+func Apply(cfg config.Logger) error {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+
+	slog.SetLogLoggerLevel(level)
+	return nil
+}
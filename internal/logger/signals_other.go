@@ -0,0 +1,9 @@
+//go:build !unix
+
+package logger
+
+import "os"
+
+// reopenSignals is empty where SIGHUP/SIGUSR1 don't exist; size and age
+// rotation still work
+var reopenSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// reopenSignals make WatchReopen reopen the log file (logrotate's postrotate)
+var reopenSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1}
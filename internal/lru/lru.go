@@ -0,0 +1,110 @@
+// Package lru is a small in-process cache: least recently used entries go
+// first once it is full, and every entry expires after a fixed TTL.
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is safe for concurrent use. The zero value is not usable; call New.
+type Cache[K comparable, V any] struct {
+	size int
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	order *list.List // front is the most recently used
+	items map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// -------------------------------------------------------------
+// New() → Cache holding up to size entries, each for at most ttl
+// -------------------------------------------------------------
+func New[K comparable, V any](size int, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{size: size, ttl: ttl, order: list.New(), items: make(map[K]*list.Element, size)}
+}
+
+// Get returns the value of key unless it is missing or expired
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Add stores value under key for ttl, evicting the least recently used
+// entry when the cache is full
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expires = value, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Remove drops key
+func (c *Cache[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// RemoveFunc drops every entry match returns true for and reports how many
+func (c *Cache[K, V]) RemoveFunc(match func(K, V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*entry[K, V]); match(e.key, e.value) {
+			c.remove(el)
+			removed++
+		}
+		el = next
+	}
+	return removed
+}
+
+// Len counts the entries, expired ones included until they are touched
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
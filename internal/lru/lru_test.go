@@ -0,0 +1,86 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEviction(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	// reading a makes b the least recently used
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %v", v, ok)
+	}
+	c.Add("c", 3)
+
+	tests := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{key: "a", want: 1, ok: true},
+		{key: "b"},
+		{key: "c", want: 3, ok: true},
+	}
+	for _, tt := range tests {
+		if v, ok := c.Get(tt.key); ok != tt.ok || v != tt.want {
+			t.Errorf("Get(%s) = %d, %v; want %d, %v", tt.key, v, ok, tt.want, tt.ok)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestAddReplaces(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("a", 10)
+	c.Add("c", 3)
+	if v, ok := c.Get("a"); !ok || v != 10 {
+		t.Errorf("Get(a) = %d, %v; want the new value kept", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("b survived, but re-adding a should have made it the oldest")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := New[int, string](4, 20*time.Millisecond)
+	c.Add(1, "one")
+	time.Sleep(30 * time.Millisecond)
+	c.Add(2, "two")
+
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want expired entries counted until touched", c.Len())
+	}
+	if _, ok := c.Get(1); ok {
+		t.Error("expired entry returned")
+	}
+	if v, ok := c.Get(2); !ok || v != "two" {
+		t.Errorf("Get(2) = %q, %v", v, ok)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, want the expired entry dropped by Get", c.Len())
+	}
+}
+
+func TestRemove(t *testing.T) {
+	c := New[int, string](10, time.Minute)
+	for i := range 6 {
+		c.Add(i, "v")
+	}
+	c.Remove(0)
+	c.Remove(42)
+	if removed := c.RemoveFunc(func(k int, _ string) bool { return k%2 == 0 }); removed != 2 {
+		t.Errorf("RemoveFunc removed %d, want 2", removed)
+	}
+	for i := range 6 {
+		if _, ok := c.Get(i); ok != (i%2 == 1) {
+			t.Errorf("Get(%d) found = %v", i, ok)
+		}
+	}
+}
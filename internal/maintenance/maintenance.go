@@ -0,0 +1,84 @@
+// Package maintenance holds the switch that takes the API offline while
+// migrations or restores run. The state lives in the process: each
+// instance is switched on its own.
+package maintenance
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Mode is safe for concurrent use
+type Mode struct {
+	// defaultRetryAfter is maintenance.retry_after
+	defaultRetryAfter time.Duration
+
+	mu         sync.RWMutex
+	enabled    bool
+	message    string
+	retryAfter time.Duration
+	since      time.Time
+}
+
+// -------------------------------------------------------------
+// New() → Mode starting as maintenance.enabled says
+// -------------------------------------------------------------
+func New(cfg config.Maintenance) *Mode {
+	m := &Mode{defaultRetryAfter: cfg.RetryAfter, retryAfter: cfg.RetryAfter}
+	if cfg.Enabled {
+		m.Set(true, cfg.Message, 0)
+	}
+	return m
+}
+
+// Enabled reports whether requests should be turned away, with the
+// message and Retry-After to send
+func (m *Mode) Enabled() (bool, string, time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled, m.message, m.retryAfter
+}
+
+// -------------------------------------------------------------
+// Set() → Switch maintenance on or off; retryAfter 0 is the configured one
+// -------------------------------------------------------------
+// Switching on while already on keeps the original start time.
+func (m *Mode) Set(enabled bool, message string, retryAfter time.Duration) types.Maintenance {
+	if retryAfter <= 0 {
+		retryAfter = m.defaultRetryAfter
+	}
+
+	m.mu.Lock()
+	switch {
+	case enabled && !m.enabled:
+		m.since = time.Now().UTC()
+		slog.Warn("🚧 Maintenance mode on", slog.String("message", message), slog.Duration("retry_after", retryAfter))
+	case !enabled && m.enabled:
+		slog.Info("✅ Maintenance mode off", slog.Duration("lasted", time.Since(m.since)))
+	}
+	m.enabled, m.retryAfter = enabled, retryAfter
+	m.message = ""
+	if enabled {
+		m.message = message
+	}
+	m.mu.Unlock()
+
+	return m.State()
+}
+
+// State is the switch as the admin API reports it
+func (m *Mode) State() types.Maintenance {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state := types.Maintenance{Enabled: m.enabled, Message: m.message, RetryAfter: m.retryAfter.String()}
+	if m.enabled {
+		since := m.since
+		state.Since = &since
+	}
+	return state
+}
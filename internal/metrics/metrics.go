@@ -0,0 +1,52 @@
+// Package metrics keeps counters in memory and serves them to Prometheus in
+// its text exposition format. Nothing is pushed anywhere; a scraper pulls
+// /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ContentType is the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Collector writes its metrics in the text exposition format.
+type Collector interface {
+	WritePrometheus(w io.Writer)
+}
+
+// 🧩 GET /metrics
+// ---------------------------------------------------------
+// Renders every collector, for a Prometheus scrape job:
+//
+//	authorization: { credentials: <admin API key> }
+func Handler(collectors ...Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		for _, c := range collectors {
+			c.WritePrometheus(w)
+		}
+	})
+}
+
+// header writes the HELP and TYPE lines of a metric
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// labels renders name="value" pairs, escaped as the format requires
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", pairs[i], pairs[i+1])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+)
+
+// DurationBuckets are the upper bounds, in seconds, of the storage call
+// duration histogram
+var DurationBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// Storage counts and times the calls made to a storage backend, per method.
+// A nil *Storage (metrics disabled) records nothing.
+type Storage struct {
+	backend string
+	// slow is the slow query threshold; 0 logs none
+	slow time.Duration
+
+	mu      sync.Mutex
+	methods map[string]*methodStats
+}
+
+type methodStats struct {
+	calls   uint64
+	errors  uint64
+	slow    uint64
+	seconds float64
+	// buckets[i] counts calls no slower than DurationBuckets[i] (not cumulative)
+	buckets []uint64
+}
+
+// NewStorage returns nil unless metrics are enabled. backend (the db_type)
+// labels every series, so dashboards can compare backends.
+func NewStorage(cfg config.Metrics, backend string) *Storage {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Storage{backend: backend, slow: cfg.SlowQueryThreshold, methods: map[string]*methodStats{}}
+}
+
+// -------------------------------------------------------------
+// Wrap() → backend with every call counted, timed and debug-logged
+// -------------------------------------------------------------
+// Works the same for every backend, optional capabilities included. Calls
+// reaching metrics.slow_query_threshold are also logged at warn level with
+// their redacted arguments. With metrics disabled backend is returned as is.
+func (s *Storage) Wrap(backend storage.Storage) storage.Storage {
+	if s == nil {
+		return backend
+	}
+	return storage.Decorate(backend, s.intercept)
+}
+
+func (s *Storage) intercept(method string, args []any, call func() error) error {
+	start := time.Now()
+	err := call()
+	elapsed := time.Since(start)
+	slow := s.slow > 0 && elapsed >= s.slow
+	s.observe(method, elapsed, err != nil, slow)
+
+	// 🐢 Slow query log: what was asked, never the data itself
+	if slow {
+		attrs := []any{
+			slog.String("method", method),
+			slog.Duration("duration", elapsed),
+			slog.String("backend", s.backend),
+			slog.Any("args", redact(args)),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		slog.Warn("🐢 Slow storage call", attrs...)
+	}
+
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		attrs := []any{slog.String("method", method), slog.Duration("duration", elapsed)}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		slog.Debug("💾 Storage call", attrs...)
+	}
+	return err
+}
+
+func (s *Storage) observe(method string, elapsed time.Duration, failed, slow bool) {
+	seconds := elapsed.Seconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats, ok := s.methods[method]
+	if !ok {
+		stats = &methodStats{buckets: make([]uint64, len(DurationBuckets))}
+		s.methods[method] = stats
+	}
+	stats.calls++
+	if failed {
+		stats.errors++
+	}
+	if slow {
+		stats.slow++
+	}
+	stats.seconds += seconds
+	if i, _ := slices.BinarySearch(DurationBuckets, seconds); i < len(DurationBuckets) {
+		stats.buckets[i]++
+	}
+}
+
+// -------------------------------------------------------------
+// WritePrometheus() → Calls, errors, slow calls and the duration histogram per method
+// -------------------------------------------------------------
+// The error rate is errors / calls, e.g. in PromQL:
+//
+//	rate(student_api_storage_errors_total[5m]) / rate(student_api_storage_calls_total[5m])
+//
+// Every error counts, "no student found" included.
+func (s *Storage) WritePrometheus(w io.Writer) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	methods := slices.Sorted(maps.Keys(s.methods))
+
+	header(w, "student_api_storage_calls_total", "counter", "Storage calls by method.")
+	for _, method := range methods {
+		fmt.Fprintf(w, "student_api_storage_calls_total%s %d\n", labels("backend", s.backend, "method", method), s.methods[method].calls)
+	}
+	header(w, "student_api_storage_errors_total", "counter", "Storage calls that returned an error, by method.")
+	for _, method := range methods {
+		fmt.Fprintf(w, "student_api_storage_errors_total%s %d\n", labels("backend", s.backend, "method", method), s.methods[method].errors)
+	}
+	header(w, "student_api_storage_slow_queries_total", "counter", "Storage calls reaching the slow query threshold, by method.")
+	for _, method := range methods {
+		fmt.Fprintf(w, "student_api_storage_slow_queries_total%s %d\n", labels("backend", s.backend, "method", method), s.methods[method].slow)
+	}
+
+	const duration = "student_api_storage_call_duration_seconds"
+	header(w, duration, "histogram", "Storage call latency by method.")
+	for _, method := range methods {
+		stats := s.methods[method]
+		var cumulative uint64
+		for i, bound := range DurationBuckets {
+			cumulative += stats.buckets[i]
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(w, "%s_bucket%s %d\n", duration, labels("backend", s.backend, "method", method, "le", le), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", duration, labels("backend", s.backend, "method", method, "le", "+Inf"), stats.calls)
+		fmt.Fprintf(w, "%s_sum%s %g\n", duration, labels("backend", s.backend, "method", method), stats.seconds)
+		fmt.Fprintf(w, "%s_count%s %d\n", duration, labels("backend", s.backend, "method", method), stats.calls)
+	}
+}
+
+// redact describes storage call arguments without the data in them. Ids,
+// numbers, flags and times stay (they say what was asked for); strings are
+// hidden, filters keep only their keys, slices their length and records
+// their type.
+func redact(args []any) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = redactValue(reflect.ValueOf(arg))
+	}
+	return out
+}
+
+func redactValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case time.Duration:
+		return x.String()
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface())
+	case reflect.String:
+		return "[REDACTED]"
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "nil"
+		}
+		return redactValue(v.Elem())
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		slices.Sort(keys)
+		return "{" + strings.Join(keys, ",") + "}"
+	case reflect.Slice, reflect.Array:
+		return fmt.Sprintf("%s(len=%d)", v.Type(), v.Len())
+	default:
+		return v.Type().String()
+	}
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// TaskDurationBuckets are the upper bounds, in seconds, of the scheduled
+// task duration histogram
+var TaskDurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600}
+
+// Tasks counts and times the runs of scheduled tasks, per task name.
+// A nil *Tasks (metrics disabled) records nothing.
+type Tasks struct {
+	mu    sync.Mutex
+	tasks map[string]*taskStats
+}
+
+type taskStats struct {
+	// kind is the task type the name runs
+	kind     string
+	runs     uint64
+	failures uint64
+	skipped  uint64
+	seconds  float64
+	// buckets[i] counts runs no slower than TaskDurationBuckets[i] (not cumulative)
+	buckets []uint64
+}
+
+// NewTasks returns nil unless metrics are enabled.
+func NewTasks(cfg config.Metrics) *Tasks {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Tasks{tasks: map[string]*taskStats{}}
+}
+
+// Add makes the task's series appear, at zero, before its first run
+func (t *Tasks) Add(name, kind string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats(name, kind)
+}
+
+// Run records one finished run of the task
+func (t *Tasks) Run(name, kind string, took time.Duration, failed bool) {
+	if t == nil {
+		return
+	}
+	seconds := took.Seconds()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.stats(name, kind)
+	stats.runs++
+	if failed {
+		stats.failures++
+	}
+	stats.seconds += seconds
+	if i, _ := slices.BinarySearch(TaskDurationBuckets, seconds); i < len(TaskDurationBuckets) {
+		stats.buckets[i]++
+	}
+}
+
+// Skip records a run skipped because the previous one was still going
+func (t *Tasks) Skip(name, kind string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats(name, kind).skipped++
+}
+
+// stats is the task's entry, created on first use (t.mu held)
+func (t *Tasks) stats(name, kind string) *taskStats {
+	stats, ok := t.tasks[name]
+	if !ok {
+		stats = &taskStats{kind: kind, buckets: make([]uint64, len(TaskDurationBuckets))}
+		t.tasks[name] = stats
+	}
+	return stats
+}
+
+// -------------------------------------------------------------
+// WritePrometheus() → Runs, failures, skips and the duration histogram per task
+// -------------------------------------------------------------
+// A task that stopped succeeding shows as, in PromQL:
+//
+//	increase(student_api_scheduler_failures_total[1d]) == increase(student_api_scheduler_runs_total[1d])
+func (t *Tasks) WritePrometheus(w io.Writer) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := slices.Sorted(maps.Keys(t.tasks))
+
+	header(w, "student_api_scheduler_runs_total", "counter", "Finished scheduled task runs by task.")
+	for _, name := range names {
+		fmt.Fprintf(w, "student_api_scheduler_runs_total%s %d\n", labels("task", name, "type", t.tasks[name].kind), t.tasks[name].runs)
+	}
+	header(w, "student_api_scheduler_failures_total", "counter", "Scheduled task runs that returned an error or panicked, by task.")
+	for _, name := range names {
+		fmt.Fprintf(w, "student_api_scheduler_failures_total%s %d\n", labels("task", name, "type", t.tasks[name].kind), t.tasks[name].failures)
+	}
+	header(w, "student_api_scheduler_skipped_total", "counter", "Scheduled runs skipped while the previous run was still going, by task.")
+	for _, name := range names {
+		fmt.Fprintf(w, "student_api_scheduler_skipped_total%s %d\n", labels("task", name, "type", t.tasks[name].kind), t.tasks[name].skipped)
+	}
+
+	const duration = "student_api_scheduler_run_duration_seconds"
+	header(w, duration, "histogram", "Scheduled task run time by task.")
+	for _, name := range names {
+		stats := t.tasks[name]
+		var cumulative uint64
+		for i, bound := range TaskDurationBuckets {
+			cumulative += stats.buckets[i]
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(w, "%s_bucket%s %d\n", duration, labels("task", name, "type", stats.kind, "le", le), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", duration, labels("task", name, "type", stats.kind, "le", "+Inf"), stats.runs)
+		fmt.Fprintf(w, "%s_sum%s %g\n", duration, labels("task", name, "type", stats.kind), stats.seconds)
+		fmt.Fprintf(w, "%s_count%s %d\n", duration, labels("task", name, "type", stats.kind), stats.runs)
+	}
+}
@@ -0,0 +1,249 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/backoff"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// maxBackoff caps the exponential delay between attempts
+const maxBackoff = time.Minute
+
+// Notifier renders templates and delivers messages from a buffered queue
+// drained by worker goroutines, retrying failures with exponential backoff.
+// A nil *Notifier (notify.provider: none) silently drops everything.
+type Notifier struct {
+	sender  Sender
+	cfg     config.Notify
+	queue   chan Message
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+	stop    context.CancelFunc
+	stopped context.Context
+	// durable, when set, replaces the in-memory queue (see UseQueue)
+	durable func(Message) error
+}
+
+// -------------------------------------------------------------
+// New() → Notifier for the configured provider (nil when "none")
+// -------------------------------------------------------------
+func New(cfg config.Notify) (*Notifier, error) {
+	if cfg.Provider == "" || cfg.Provider == "none" {
+		return nil, nil
+	}
+	sender, err := newSender(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithSender(sender, cfg), nil
+}
+
+// NewWithSender starts a notifier around an explicit Sender (tests, custom
+// providers). Call Close to drain the queue on shutdown.
+func NewWithSender(sender Sender, cfg config.Notify) *Notifier {
+	workers := max(cfg.Workers, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	n := &Notifier{
+		sender:  sender,
+		cfg:     cfg,
+		queue:   make(chan Message, max(cfg.QueueSize, 1)),
+		stop:    cancel,
+		stopped: ctx,
+	}
+	for range workers {
+		n.wg.Add(1)
+		go n.work()
+	}
+	return n
+}
+
+// -------------------------------------------------------------
+// Welcome() → Queue the welcome email for a newly created student
+// -------------------------------------------------------------
+// ctx is the creating request; its trace travels with the message.
+func (n *Notifier) Welcome(ctx context.Context, student types.Student) {
+	if n == nil {
+		return
+	}
+	msg, err := Render("welcome", student)
+	if err != nil {
+		slog.Error("❌ Rendering welcome email failed", slog.String("error", err.Error()))
+		return
+	}
+	msg.To = student.Email
+	msg.Trace = httpclient.TraceFrom(ctx)
+	n.Enqueue(msg)
+}
+
+// -------------------------------------------------------------
+// Verify() → Queue the email verification link for a student
+// -------------------------------------------------------------
+func (n *Notifier) Verify(ctx context.Context, student types.Student, link string, expiresAt time.Time) {
+	if n == nil {
+		return
+	}
+	msg, err := Render("verify", struct {
+		Student   types.Student
+		Link      string
+		ExpiresAt time.Time
+	}{student, link, expiresAt})
+	if err != nil {
+		slog.Error("❌ Rendering verification email failed", slog.String("error", err.Error()))
+		return
+	}
+	msg.To = student.Email
+	msg.Trace = httpclient.TraceFrom(ctx)
+	n.Enqueue(msg)
+}
+
+// -------------------------------------------------------------
+// Enqueue() → Hand a message to the workers without blocking the request
+// -------------------------------------------------------------
+// Returns false when the queue is full or closed; the message is dropped.
+func (n *Notifier) Enqueue(msg Message) bool {
+	if n == nil {
+		return false
+	}
+	if msg.From == "" {
+		msg.From = n.cfg.From
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return false
+	}
+
+	if n.durable != nil {
+		if err := n.durable(msg); err != nil {
+			slog.Error("❌ Queueing email job failed", slog.String("to", msg.To), slog.String("error", err.Error()))
+			return false
+		}
+		return true
+	}
+
+	select {
+	case n.queue <- msg:
+		return true
+	default:
+		slog.Warn("⚠️ Email queue full, dropping message",
+			slog.String("to", msg.To),
+			slog.String("subject", msg.Subject),
+		)
+		return false
+	}
+}
+
+// -------------------------------------------------------------
+// UseQueue() → Route messages through a durable queue (e.g. the job table)
+// -------------------------------------------------------------
+// The queue is then responsible for retries; it should call Deliver.
+func (n *Notifier) UseQueue(enqueue func(Message) error) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.durable = enqueue
+}
+
+// -------------------------------------------------------------
+// Deliver() → Send one message right now, a single attempt
+// -------------------------------------------------------------
+func (n *Notifier) Deliver(ctx context.Context, msg Message) error {
+	if n == nil {
+		return errors.New("email notifications are disabled")
+	}
+	if msg.From == "" {
+		msg.From = n.cfg.From
+	}
+	if err := n.sender.Send(httpclient.WithTrace(ctx, msg.Trace), msg); err != nil {
+		return err
+	}
+	slog.Info("📧 Email sent", slog.String("to", msg.To), slog.String("subject", msg.Subject))
+	return nil
+}
+
+// -------------------------------------------------------------
+// Close() → Stop accepting messages and wait for the queue to drain
+// -------------------------------------------------------------
+// When ctx expires first, pending retries are abandoned.
+func (n *Notifier) Close(ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		n.stop()
+		<-done
+		return errors.New("email queue not drained before shutdown")
+	}
+}
+
+func (n *Notifier) work() {
+	defer n.wg.Done()
+	for msg := range n.queue {
+		n.deliver(msg)
+	}
+}
+
+// deliver tries up to MaxAttempts times, sleeping Backoff·2ⁿ (± jitter) in between
+func (n *Notifier) deliver(msg Message) {
+	attempts := max(n.cfg.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(httpclient.WithTrace(n.stopped, msg.Trace), 30*time.Second)
+		err := n.sender.Send(ctx, msg)
+		cancel()
+		if err == nil {
+			slog.Info("📧 Email sent", slog.String("to", msg.To), slog.String("subject", msg.Subject))
+			return
+		}
+
+		if attempt >= attempts {
+			slog.Error("❌ Email delivery failed, giving up",
+				slog.String("to", msg.To),
+				slog.Int("attempts", attempt),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+
+		delay := backoff.Exponential(n.cfg.Backoff, attempt, maxBackoff)
+		slog.Warn("⚠️ Email delivery failed, retrying",
+			slog.String("to", msg.To),
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", delay),
+			slog.String("error", err.Error()),
+		)
+		select {
+		case <-time.After(delay):
+		case <-n.stopped.Done():
+			return
+		}
+	}
+}
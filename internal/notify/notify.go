@@ -0,0 +1,69 @@
+// Package notify sends emails (e.g. the welcome mail on student creation)
+// from a small in-process worker queue with retry and exponential backoff.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+// Message is one email. Either Text or HTML may be empty.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// Trace is the request that caused the email; HTTP providers forward it
+	// and SMTP adds it as an X-Request-ID header
+	Trace httpclient.Trace `json:",omitzero"`
+}
+
+// Sender delivers a message through one provider (SMTP, SES, SendGrid, ...).
+// Returning an error makes the notifier retry with backoff.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SenderFactory builds a Sender from config; see RegisterSender.
+type SenderFactory func(cfg config.Notify) (Sender, error)
+
+var (
+	sendersMu sync.RWMutex
+	senders   = map[string]SenderFactory{
+		"smtp": func(cfg config.Notify) (Sender, error) { return NewSMTP(cfg.SMTP) },
+		"log":  func(cfg config.Notify) (Sender, error) { return LogSender{}, nil },
+	}
+)
+
+// -------------------------------------------------------------
+// RegisterSender() → Plug in another provider (e.g. "ses", "sendgrid")
+// -------------------------------------------------------------
+// Call it from an init() before the notifier is built; notify.provider
+// then selects it by name.
+func RegisterSender(name string, factory SenderFactory) {
+	sendersMu.Lock()
+	defer sendersMu.Unlock()
+	senders[name] = factory
+}
+
+func newSender(cfg config.Notify) (Sender, error) {
+	sendersMu.RLock()
+	defer sendersMu.RUnlock()
+
+	factory, ok := senders[cfg.Provider]
+	if !ok {
+		names := make([]string, 0, len(senders))
+		for name := range senders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unsupported notify provider: %s (supported: none, %v)", cfg.Provider, names)
+	}
+	return factory(cfg)
+}
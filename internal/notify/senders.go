@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// LogSender only logs messages — handy for local development.
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	slog.Info("📧 Email (log provider, not sent)",
+		slog.String("to", msg.To),
+		slog.String("subject", msg.Subject),
+	)
+	return nil
+}
+
+// Memory records messages instead of sending them. Meant for tests.
+type Memory struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (m *Memory) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns a copy of every message delivered so far
+func (m *Memory) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
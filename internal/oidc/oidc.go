@@ -0,0 +1,269 @@
+// Package oidc validates ID tokens issued by an external identity provider
+// (Google Workspace, Azure AD, ...) against the provider's published JWKS
+// and maps their role claim onto local scopes.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+var (
+	ErrInvalid = errors.New("invalid ID token")
+	ErrExpired = errors.New("ID token has expired")
+)
+
+// leeway tolerates clock skew between us and the provider
+const leeway = time.Minute
+
+// Keys are refetched at most this often when a token names an unknown kid
+// (the provider rotated), and at least this often regardless.
+const (
+	minRefresh = time.Minute
+	maxKeyAge  = time.Hour
+)
+
+// Identity is the signed-in user of a verified ID token.
+type Identity struct {
+	Subject string
+	Email   string
+	Roles   []string
+	Scopes  []string
+	// Tenant is the slug in cfg.TenantClaim, lowercased ("" when absent)
+	Tenant string
+}
+
+// Verifier checks ID tokens of one provider and client.
+// A nil *Verifier (no auth.oidc.issuer) means OIDC login is disabled.
+type Verifier struct {
+	cfg    config.OIDC
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// -------------------------------------------------------------
+// New() → Verifier, or nil when no issuer is configured
+// -------------------------------------------------------------
+// Discovery and the JWKS are fetched lazily on the first token, so a
+// provider outage doesn't stop the API from starting.
+func New(cfg config.OIDC) *Verifier {
+	if cfg.Issuer == "" {
+		return nil
+	}
+	return &Verifier{
+		cfg:    cfg,
+		client: httpclient.New(httpclient.Options{Timeout: 10 * time.Second, Retries: 2}),
+	}
+}
+
+// header is the JOSE header of an ID token
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// -------------------------------------------------------------
+// Verify() → Identity of a validly signed, unexpired token for us
+// -------------------------------------------------------------
+func (v *Verifier) Verify(ctx context.Context, raw string) (Identity, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalid
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "RS256" {
+		return Identity{}, ErrInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, ErrInvalid
+	}
+
+	// 🔑 Signature against the provider's current keys
+	key, err := v.key(ctx, h.Kid)
+	if err != nil {
+		return Identity{}, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) != nil {
+		return Identity{}, ErrInvalid
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, ErrInvalid
+	}
+
+	// 🧠 Issued by our provider, for our client, and still valid
+	if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+		return Identity{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalid, iss)
+	}
+	if !slices.Contains(stringList(claims["aud"]), v.cfg.ClientID) {
+		return Identity{}, fmt.Errorf("%w: not issued for this client", ErrInvalid)
+	}
+	now := time.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return Identity{}, ErrInvalid
+	}
+	if now.Add(-leeway).Unix() >= int64(exp) {
+		return Identity{}, ErrExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(leeway).Unix() < int64(nbf) {
+		return Identity{}, fmt.Errorf("%w: not valid yet", ErrInvalid)
+	}
+
+	id := Identity{Roles: stringList(claims[v.cfg.RolesClaim])}
+	id.Subject, _ = claims["sub"].(string)
+	id.Email, _ = claims["email"].(string)
+	if slug, ok := claims[v.cfg.TenantClaim].(string); ok {
+		id.Tenant = strings.ToLower(strings.TrimSpace(slug))
+	}
+	id.Scopes = v.scopes(id.Roles)
+	return id, nil
+}
+
+// scopes maps roles through cfg.Roles, plus the default scopes
+func (v *Verifier) scopes(roles []string) []string {
+	scopes := slices.Clone(v.cfg.DefaultScopes)
+	for _, role := range roles {
+		mapped, ok := v.cfg.Roles[role]
+		if !ok {
+			continue
+		}
+		for _, scope := range strings.Split(mapped, ",") {
+			scopes = append(scopes, strings.TrimSpace(scope))
+		}
+	}
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
+
+// key returns the signing key kid, refreshing the JWKS when it's unknown
+func (v *Verifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	age := time.Since(v.fetchedAt)
+	if key, ok := v.keys[kid]; ok && age < maxKeyAge {
+		return key, nil
+	}
+	if v.keys == nil || age >= minRefresh {
+		keys, err := v.fetchKeys(ctx)
+		if err != nil {
+			// keep serving the cached keys through a provider hiccup
+			if key, ok := v.keys[kid]; ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		v.keys, v.fetchedAt = keys, time.Now()
+	}
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalid, kid)
+}
+
+// fetchKeys follows the discovery document to the JWKS
+func (v *Verifier) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := v.getJSON(ctx, strings.TrimSuffix(v.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	if discovery.Issuer != v.cfg.Issuer || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match auth.oidc.issuer", discovery.Issuer)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+func (v *Verifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oidc fetch %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc fetch %s failed: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("oidc decode %s failed: %w", url, err)
+	}
+	return nil
+}
+
+func decodeSegment(seg string, out any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// stringList reads a claim that is either a string or a list of strings
+func stringList(claim any) []string {
+	switch c := claim.(type) {
+	case string:
+		return []string{c}
+	case []any:
+		list := make([]string, 0, len(c))
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Package outbox relays student events from the transactional outbox to
+// webhooks. Storage writes each event in the same transaction as its
+// change, so nothing is lost when the process dies right after a write:
+// the relay finds the event on its next poll, here or on another instance.
+// Delivery is at least once; an event is only removed after it was handed
+// to the webhook dispatcher.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/webhook"
+)
+
+// Relay polls the outbox and publishes what it finds.
+// A nil *Relay (backend without an outbox) does nothing.
+type Relay struct {
+	store storage.OutboxStore
+	hooks *webhook.Dispatcher
+	cfg   config.Outbox
+
+	done chan struct{}
+	stop context.CancelFunc
+}
+
+// New returns nil when the backend has no outbox table. Without a webhook
+// dispatcher (no job queue) events are still drained, and dropped.
+func New(backend storage.Storage, hooks *webhook.Dispatcher, cfg config.Outbox) *Relay {
+	store, ok := storage.As[storage.OutboxStore](backend)
+	if !ok {
+		return nil
+	}
+	return &Relay{store: store, hooks: hooks, cfg: cfg}
+}
+
+// -------------------------------------------------------------
+// Start() → Poll the outbox until Stop (or ctx ends)
+// -------------------------------------------------------------
+func (r *Relay) Start(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	ctx, r.stop = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	go r.run(ctx)
+	slog.Info("📤 Outbox relay started", slog.Duration("poll_interval", r.cfg.PollInterval))
+}
+
+// -------------------------------------------------------------
+// Stop() → Finish the batch in flight, then stop polling
+// -------------------------------------------------------------
+// Events left behind are published on the next start.
+func (r *Relay) Stop(ctx context.Context) error {
+	if r == nil || r.stop == nil {
+		return nil
+	}
+	r.stop()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("outbox relay still publishing at shutdown; the rest goes out on next start")
+	}
+}
+
+func (r *Relay) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain full batches before sleeping again
+		for ctx.Err() == nil && r.publishBatch(ctx) == r.cfg.BatchSize {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishBatch publishes one batch and returns how many events it claimed.
+// Each event is deleted once published; one that fails stays leased and is
+// claimed again when its lease runs out.
+func (r *Relay) publishBatch(ctx context.Context) int {
+	events, err := r.store.ClaimOutboxEvents(time.Now(), r.cfg.Lease, r.cfg.BatchSize)
+	if err != nil {
+		slog.Error("❌ Claiming outbox events failed", slog.String("error", err.Error()))
+		return 0
+	}
+
+	for _, event := range events {
+		// A batch finishes even during shutdown, like a running job
+		if err := r.hooks.Publish(context.WithoutCancel(ctx), event); err != nil {
+			slog.Error("❌ Publishing outbox event failed, retrying after the lease",
+				slog.Int64("event_id", event.ID),
+				slog.String("event", event.Event),
+				slog.Duration("lease", r.cfg.Lease),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if err := r.store.DeleteOutboxEvent(event.ID); err != nil {
+			slog.Error("❌ Removing published outbox event failed", slog.Int64("event_id", event.ID), slog.String("error", err.Error()))
+		}
+	}
+	return len(events)
+}
@@ -0,0 +1,35 @@
+// Package paging holds the list limits in force (pagination). They follow
+// config reloads, so a request reads them once and keeps that copy.
+package paging
+
+import (
+	"sync/atomic"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// Limits is safe for concurrent use
+type Limits struct {
+	current atomic.Pointer[config.Pagination]
+}
+
+// -------------------------------------------------------------
+// New() → Limits starting at pagination's values
+// -------------------------------------------------------------
+func New(cfg config.Pagination) *Limits {
+	l := &Limits{}
+	l.current.Store(&cfg)
+	return l
+}
+
+// Apply swaps in new limits (config reloads); requests already running
+// keep theirs
+func (l *Limits) Apply(cfg config.Pagination) error {
+	l.current.Store(&cfg)
+	return nil
+}
+
+// Get returns the limits in force
+func (l *Limits) Get() config.Pagination {
+	return *l.current.Load()
+}
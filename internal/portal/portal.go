@@ -0,0 +1,176 @@
+// Package portal signs students in to the self-service portal with a
+// password and checks their sessions. Sessions are opaque bearer tokens
+// stored by hash, so logging out or resetting a password ends them at once.
+package portal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+var (
+	ErrInvalidLogin = errors.New("wrong email or password")
+	ErrLocked       = errors.New("too many failed logins, try again later")
+	ErrWeakPassword = errors.New("password is too short")
+	// ErrUnsupported means the storage backend keeps no portal logins
+	ErrUnsupported = errors.New("student portal not supported by this storage backend")
+)
+
+// Portal signs students in and out.
+// A nil *Portal (portal.enabled false) means the portal is off.
+type Portal struct {
+	cfg config.Portal
+}
+
+// -------------------------------------------------------------
+// New() → Portal, or nil when it is disabled
+// -------------------------------------------------------------
+func New(cfg config.Portal) *Portal {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Portal{cfg: cfg}
+}
+
+// -------------------------------------------------------------
+// SetPassword() → Give a student a (new) password, ending their sessions
+// -------------------------------------------------------------
+// store is tenant-scoped. Also clears a lockout.
+func (p *Portal) SetPassword(store storage.Storage, studentID int64, password string) error {
+	logins, ok := storage.As[storage.PortalStore](store)
+	if !ok {
+		return ErrUnsupported
+	}
+	if len(password) < p.cfg.MinPasswordLength {
+		return fmt.Errorf("%w: use at least %d characters", ErrWeakPassword, p.cfg.MinPasswordLength)
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return logins.SetStudentPassword(studentID, hash)
+}
+
+// -------------------------------------------------------------
+// Login() → Check email and password and start a session
+// -------------------------------------------------------------
+// Returns the session's bearer token. portal.max_failed_logins wrong
+// passwords in a row lock the login for portal.lockout.
+func (p *Portal) Login(store storage.Storage, email, password string) (string, types.PortalSession, error) {
+	logins, ok := storage.As[storage.PortalStore](store)
+	if !ok {
+		return "", types.PortalSession{}, ErrUnsupported
+	}
+
+	now := time.Now().UTC()
+	credential, err := logins.GetStudentCredential(email)
+	if errors.Is(err, storage.ErrNoCredential) {
+		checkPassword(dummyHash(), password)
+		return "", types.PortalSession{}, ErrInvalidLogin
+	}
+	if err != nil {
+		return "", types.PortalSession{}, err
+	}
+	if credential.LockedUntil != nil && now.Before(*credential.LockedUntil) {
+		return "", types.PortalSession{}, ErrLocked
+	}
+
+	if !checkPassword(credential.PasswordHash, password) {
+		failures, lockedUntil := credential.FailedLogins+1, (*time.Time)(nil)
+		if failures >= p.cfg.MaxFailedLogins {
+			until := now.Add(p.cfg.Lockout)
+			failures, lockedUntil = 0, &until
+		}
+		if err := logins.SetLoginFailures(credential.StudentID, failures, lockedUntil); err != nil {
+			return "", types.PortalSession{}, err
+		}
+		return "", types.PortalSession{}, ErrInvalidLogin
+	}
+	if credential.FailedLogins > 0 || credential.LockedUntil != nil {
+		if err := logins.SetLoginFailures(credential.StudentID, 0, nil); err != nil {
+			return "", types.PortalSession{}, err
+		}
+	}
+
+	raw := randomHex(32)
+	session := types.PortalSession{StudentID: credential.StudentID, Hash: apikey.Hash(raw), ExpiresAt: now.Add(p.cfg.SessionTTL)}
+	id, err := logins.CreatePortalSession(session)
+	if err != nil {
+		return "", types.PortalSession{}, err
+	}
+	session.ID, session.TenantID = id, credential.TenantID
+	return raw, session, nil
+}
+
+// -------------------------------------------------------------
+// Authenticate() → Session of a bearer token, or storage.ErrNoSession
+// -------------------------------------------------------------
+func (p *Portal) Authenticate(store storage.Storage, raw string) (types.PortalSession, error) {
+	logins, ok := storage.As[storage.PortalStore](store)
+	if !ok {
+		return types.PortalSession{}, ErrUnsupported
+	}
+	return logins.GetPortalSession(apikey.Hash(raw), time.Now().UTC())
+}
+
+// -------------------------------------------------------------
+// Logout() → End the session of a bearer token
+// -------------------------------------------------------------
+func (p *Portal) Logout(store storage.Storage, raw string) error {
+	logins, ok := storage.As[storage.PortalStore](store)
+	if !ok {
+		return ErrUnsupported
+	}
+	_, err := logins.DeletePortalSession(apikey.Hash(raw))
+	return err
+}
+
+// -------------------------------------------------------------
+// ChangePassword() → Replace a signed-in student's password
+// -------------------------------------------------------------
+// The current password must match; every session ends, this one too.
+func (p *Portal) ChangePassword(store storage.Storage, student types.Student, current, next string) error {
+	logins, ok := storage.As[storage.PortalStore](store)
+	if !ok {
+		return ErrUnsupported
+	}
+	credential, err := logins.GetStudentCredential(student.Email)
+	if errors.Is(err, storage.ErrNoCredential) {
+		return ErrInvalidLogin
+	}
+	if err != nil {
+		return err
+	}
+	if credential.StudentID != student.ID || !checkPassword(credential.PasswordHash, current) {
+		return ErrInvalidLogin
+	}
+	return p.SetPassword(store, student.ID, next)
+}
+
+type ctxKey struct{}
+
+// WithSession stores the signed-in student's session in ctx
+func WithSession(ctx context.Context, session types.PortalSession) context.Context {
+	return context.WithValue(ctx, ctxKey{}, session)
+}
+
+// FromContext is the session middleware.Portal authenticated
+func FromContext(ctx context.Context) (types.PortalSession, bool) {
+	session, ok := ctx.Value(ctxKey{}).(types.PortalSession)
+	return session, ok
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
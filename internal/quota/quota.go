@@ -0,0 +1,144 @@
+// Package quota charges requests against daily per-subject allowances
+// (API keys, signed-in users) kept in storage.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+var ErrSubject = errors.New(`subject must be "key:<id>" or "user:<sub>"`)
+
+// Status is the outcome of charging one request.
+type Status struct {
+	Limits types.Quota
+	// Reset is when the counters start over (next UTC midnight)
+	Reset time.Time
+	// Exceeded names the quota that ran out ("request" or "write"); empty when allowed
+	Exceeded string
+}
+
+// Tracker counts requests in storage.
+// A nil *Tracker (quotas.enabled false) charges nothing.
+type Tracker struct {
+	cfg   config.Quotas
+	store storage.QuotaStore
+}
+
+// -------------------------------------------------------------
+// New() → Tracker, or nil when quotas are disabled or unsupported
+// -------------------------------------------------------------
+func New(cfg config.Quotas, backend storage.Storage) *Tracker {
+	store, ok := storage.As[storage.QuotaStore](backend)
+	if !cfg.Enabled || !ok {
+		return nil
+	}
+	return &Tracker{cfg: cfg, store: store}
+}
+
+// KeySubject and UserSubject name whom a request is charged to
+func KeySubject(id int64) string    { return "key:" + strconv.FormatInt(id, 10) }
+func UserSubject(sub string) string { return "user:" + sub }
+
+// ValidSubject reports whether subject has one of the two forms
+func ValidSubject(subject string) bool {
+	kind, id, ok := strings.Cut(subject, ":")
+	switch {
+	case !ok || id == "":
+		return false
+	case kind == "key":
+		_, err := strconv.ParseInt(id, 10, 64)
+		return err == nil
+	default:
+		return kind == "user"
+	}
+}
+
+// Day is the UTC date counters are kept under
+func Day(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// -------------------------------------------------------------
+// Charge() → Count a request and check it against the limits
+// -------------------------------------------------------------
+// Rejected requests count too, so retrying harder doesn't help.
+func (t *Tracker) Charge(subject string, write bool) (Status, error) {
+	now := time.Now()
+	quota, err := t.limits(subject)
+	if err != nil {
+		return Status{}, err
+	}
+	quota.Usage, err = t.store.AddQuotaUsage(subject, Day(now), write)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Limits: quota, Reset: nextDay(now)}
+	switch {
+	case quota.DailyRequests > 0 && quota.Usage.Requests > quota.DailyRequests:
+		status.Exceeded = "request"
+	case write && quota.DailyWrites > 0 && quota.Usage.Writes > quota.DailyWrites:
+		status.Exceeded = "write"
+	}
+	return status, nil
+}
+
+// -------------------------------------------------------------
+// Get() → A subject's effective limits and today's usage
+// -------------------------------------------------------------
+func (t *Tracker) Get(subject string) (types.Quota, error) {
+	quota, err := t.limits(subject)
+	if err != nil {
+		return types.Quota{}, err
+	}
+	quota.Usage, err = t.store.GetQuotaUsage(subject, Day(time.Now()))
+	return quota, err
+}
+
+// -------------------------------------------------------------
+// Set() / Reset() → Override a subject's limits / back to defaults
+// -------------------------------------------------------------
+func (t *Tracker) Set(limit types.QuotaLimit) (types.Quota, error) {
+	if !ValidSubject(limit.Subject) {
+		return types.Quota{}, ErrSubject
+	}
+	if err := t.store.SetQuotaLimit(limit); err != nil {
+		return types.Quota{}, err
+	}
+	return t.Get(limit.Subject)
+}
+
+func (t *Tracker) Reset(subject string) (bool, error) {
+	return t.store.DeleteQuotaLimit(subject)
+}
+
+// Overrides lists every subject with custom limits
+func (t *Tracker) Overrides() ([]types.QuotaLimit, error) {
+	return t.store.GetQuotaLimits()
+}
+
+// limits resolves the override of subject, else the configured defaults
+func (t *Tracker) limits(subject string) (types.Quota, error) {
+	quota := types.Quota{Subject: subject, DailyRequests: t.cfg.DailyRequests, DailyWrites: t.cfg.DailyWrites}
+	limit, ok, err := t.store.GetQuotaLimit(subject)
+	if err != nil {
+		return types.Quota{}, fmt.Errorf("load quota: %w", err)
+	}
+	if ok {
+		quota.DailyRequests, quota.DailyWrites, quota.Custom = limit.DailyRequests, limit.DailyWrites, true
+	}
+	return quota, nil
+}
+
+func nextDay(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,90 @@
+// Package report serves the operational reports the storage layer
+// computes, keeping each result in process for reports.cache_ttl so
+// dashboards polling them don't rerun the GROUP BY queries.
+package report
+
+import (
+	"context"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/lru"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// cacheSize bounds each cache: one entry per tenant (and month)
+const cacheSize = 1024
+
+// Cache is safe for concurrent use. A nil *Cache (cache_ttl 0) computes
+// every report on every call.
+type Cache struct {
+	ages      *lru.Cache[int64, types.AgeDistribution]
+	birthdays *lru.Cache[birthdayKey, types.BirthdayReport]
+}
+
+type birthdayKey struct {
+	tenantID int64
+	month    int
+}
+
+// -------------------------------------------------------------
+// New() → Cache keeping reports for reports.cache_ttl; nil when it is 0
+// -------------------------------------------------------------
+func New(cfg config.Reports) *Cache {
+	if cfg.CacheTTL <= 0 {
+		return nil
+	}
+	return &Cache{
+		ages:      lru.New[int64, types.AgeDistribution](cacheSize, cfg.CacheTTL),
+		birthdays: lru.New[birthdayKey, types.BirthdayReport](cacheSize, cfg.CacheTTL),
+	}
+}
+
+// -------------------------------------------------------------
+// AgeDistribution() → The tenant's age histogram, cached
+// -------------------------------------------------------------
+// store must be scoped to the tenant in ctx.
+func (c *Cache) AgeDistribution(ctx context.Context, store storage.ReportStore) (types.AgeDistribution, error) {
+	if c == nil {
+		return store.AgeDistribution()
+	}
+	key := tenantID(ctx)
+	if report, ok := c.ages.Get(key); ok {
+		return report, nil
+	}
+	report, err := store.AgeDistribution()
+	if err != nil {
+		return report, err
+	}
+	c.ages.Add(key, report)
+	return report, nil
+}
+
+// -------------------------------------------------------------
+// Birthdays() → The tenant's birthdays in month, cached
+// -------------------------------------------------------------
+// store must be scoped to the tenant in ctx.
+func (c *Cache) Birthdays(ctx context.Context, store storage.ReportStore, month int) (types.BirthdayReport, error) {
+	if c == nil {
+		return store.Birthdays(month)
+	}
+	key := birthdayKey{tenantID: tenantID(ctx), month: month}
+	if report, ok := c.birthdays.Get(key); ok {
+		return report, nil
+	}
+	report, err := store.Birthdays(month)
+	if err != nil {
+		return report, err
+	}
+	c.birthdays.Add(key, report)
+	return report, nil
+}
+
+// tenantID is the id of the request's tenant, the default one without tenancy
+func tenantID(ctx context.Context) int64 {
+	if t, ok := tenant.FromContext(ctx); ok {
+		return t.ID
+	}
+	return storage.DefaultTenantID
+}
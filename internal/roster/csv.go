@@ -0,0 +1,115 @@
+package roster
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// csvFile reads students from a CSV file, local or over http(s)
+type csvFile struct {
+	source string
+	token  string
+	client *http.Client
+}
+
+// -------------------------------------------------------------
+// newCSV() → Connector over a CSV roster
+// -------------------------------------------------------------
+// Columns are matched by header name, the same ones a CSV export writes:
+// name, email, date_of_birth, phone, gender, address_line1 ...
+// address_country and custom (a JSON object). Others (id, age) are
+// ignored, so an export of one instance can be synced into another.
+func newCSV(cfg config.SyncConnector, client *http.Client) (Connector, error) {
+	return &csvFile{source: cfg.URL, token: cfg.Token, client: client}, nil
+}
+
+func (c *csvFile) Fetch(ctx context.Context) ([]types.Student, error) {
+	data, err := c.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("roster CSV is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid roster CSV: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, errors.New("roster CSV has no email column")
+	}
+
+	var students []types.Student
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return students, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid roster CSV: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		student := types.Student{
+			Name:        field("name"),
+			Email:       field("email"),
+			DateOfBirth: field("date_of_birth"),
+			Phone:       field("phone"),
+			Gender:      field("gender"),
+		}
+
+		// the address is flattened into columns; all blank means none
+		address := types.Address{
+			Line1: field("address_line1"), Line2: field("address_line2"), City: field("address_city"),
+			Region: field("address_region"), PostalCode: field("address_postal_code"), Country: field("address_country"),
+		}
+		if address != (types.Address{}) {
+			student.Address = &address
+		}
+
+		if custom := field("custom"); custom != "" {
+			if err := json.Unmarshal([]byte(custom), &student.Custom); err != nil {
+				return nil, fmt.Errorf("roster CSV line %d: custom is not a JSON object: %w", line, err)
+			}
+		}
+		students = append(students, student)
+	}
+}
+
+// read loads the whole file: http(s) URLs are fetched, anything else is a
+// local path (optionally file://)
+func (c *csvFile) read(ctx context.Context) ([]byte, error) {
+	if strings.HasPrefix(c.source, "http://") || strings.HasPrefix(c.source, "https://") {
+		return get(ctx, c.client, c.source, c.token, "text/csv")
+	}
+
+	f, err := os.Open(strings.TrimPrefix(c.source, "file://"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readAll(f)
+}
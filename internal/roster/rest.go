@@ -0,0 +1,102 @@
+package roster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// maxPages stops a REST source whose next links never run out
+const maxPages = 1000
+
+// rest reads students as JSON from an HTTP API
+type rest struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// -------------------------------------------------------------
+// newREST() → Connector over a JSON roster endpoint
+// -------------------------------------------------------------
+// The endpoint answers a JSON array of students, or a page
+// {"data":[...]} whose next page is at "next" or "_links.next.href"
+// (the shape of GET /api/students?limit=, so instances can sync from
+// each other).
+func newREST(cfg config.SyncConnector, client *http.Client) (Connector, error) {
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	return &rest{url: cfg.URL, token: cfg.Token, client: client}, nil
+}
+
+func (c *rest) Fetch(ctx context.Context) ([]types.Student, error) {
+	var students []types.Student
+	next := c.url
+	for pages := 0; next != ""; pages++ {
+		if pages == maxPages {
+			return nil, fmt.Errorf("roster has more than %d pages", maxPages)
+		}
+		body, err := get(ctx, c.client, next, c.token, "application/json")
+		if err != nil {
+			return nil, err
+		}
+		batch, link, err := decodePage(body)
+		if err != nil {
+			return nil, fmt.Errorf("GET %s: %w", next, err)
+		}
+		students = append(students, batch...)
+
+		// next links may be relative to the page they came from
+		if next, err = resolve(next, link); err != nil {
+			return nil, err
+		}
+	}
+	return students, nil
+}
+
+// decodePage reads one response: a bare array, or a page and its next link
+func decodePage(body []byte) ([]types.Student, string, error) {
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var students []types.Student
+		if err := json.Unmarshal(trimmed, &students); err != nil {
+			return nil, "", fmt.Errorf("invalid roster JSON: %w", err)
+		}
+		return students, "", nil
+	}
+
+	var page struct {
+		Data  []types.Student `json:"data"`
+		Next  string          `json:"next"`
+		Links types.Links     `json:"_links"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, "", fmt.Errorf("invalid roster JSON: %w", err)
+	}
+	if page.Next == "" {
+		page.Next = page.Links["next"].Href
+	}
+	return page.Data, page.Next, nil
+}
+
+// resolve makes link absolute against the page URL it came from
+func resolve(base, link string) (string, error) {
+	if link == "" {
+		return "", nil
+	}
+	from, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	to, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %w", link, err)
+	}
+	return from.ResolveReference(to).String(), nil
+}
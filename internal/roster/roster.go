@@ -0,0 +1,112 @@
+// Package roster syncs students from external student information systems
+// (SIS). A Connector fetches the source's whole roster; the Syncer diffs it
+// against the tenant's students and applies the creates and updates, or
+// only reports them in a dry run.
+package roster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Connector reads a roster from one external source.
+type Connector interface {
+	// Fetch returns every student the source lists. IDs and ages are
+	// ignored: students are matched by email and ages are derived.
+	Fetch(ctx context.Context) ([]types.Student, error)
+}
+
+// Factory builds a connector from its config entry; client already has
+// timeouts, retries and trace propagation.
+type Factory func(cfg config.SyncConnector, client *http.Client) (Connector, error)
+
+// Built-in connector types
+const (
+	TypeREST = "rest"
+	TypeCSV  = "csv"
+)
+
+// maxRosterBytes caps one fetched document (a CSV file or a REST page)
+const maxRosterBytes = 64 << 20
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register makes a connector type available to sync.connectors; call it
+// before New runs (e.g. from an init function)
+func Register(typ string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[typ] = factory
+}
+
+func init() {
+	Register(TypeREST, newREST)
+	Register(TypeCSV, newCSV)
+}
+
+// connect builds the connector for one config entry
+func connect(cfg config.SyncConnector) (Connector, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[cfg.Type]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("sync connector %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+	client := httpclient.New(httpclient.Options{Timeout: timeout, Retries: 2, UserAgent: "go-student-api-sync"})
+	conn, err := factory(cfg, client)
+	if err != nil {
+		return nil, fmt.Errorf("sync connector %q: %w", cfg.Name, err)
+	}
+	return conn, nil
+}
+
+// get fetches url with the connector's bearer token
+func get(ctx context.Context, client *http.Client, url, token, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, res.Status)
+	}
+	return readAll(res.Body)
+}
+
+// readAll reads a whole roster document, refusing ones over maxRosterBytes
+func readAll(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxRosterBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRosterBytes {
+		return nil, errors.New("roster document is larger than 64MiB")
+	}
+	return data, nil
+}
@@ -0,0 +1,267 @@
+package roster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/validate"
+)
+
+var (
+	// ErrUnknownConnector means no sync.connectors entry has that name
+	ErrUnknownConnector = errors.New("unknown sync connector")
+	// ErrRunning means the connector is already syncing
+	ErrRunning = errors.New("sync is already running")
+)
+
+// Syncer runs the configured connectors against the database.
+type Syncer struct {
+	backend  storage.Storage
+	custom   *customfield.Registry
+	notifier *notify.Notifier
+	sources  []*source
+}
+
+// source is one configured connector and its last run
+type source struct {
+	cfg  config.SyncConnector
+	conn Connector
+	// running serialises runs of the same connector
+	running sync.Mutex
+
+	mu   sync.Mutex
+	last *types.SyncReport
+}
+
+// -------------------------------------------------------------
+// New() → Syncer over sync.connectors; nil when there are none
+// -------------------------------------------------------------
+// New students get the welcome email, and every change records the same
+// events as the API, so webhook subscribers can't tell synced edits apart.
+func New(cfg *config.Config, backend storage.Storage, notifier *notify.Notifier) (*Syncer, error) {
+	if len(cfg.Sync.Connectors) == 0 {
+		return nil, nil
+	}
+
+	s := &Syncer{backend: backend, custom: customfield.New(cfg.CustomFields), notifier: notifier}
+	for _, connCfg := range cfg.Sync.Connectors {
+		conn, err := connect(connCfg)
+		if err != nil {
+			return nil, err
+		}
+		s.sources = append(s.sources, &source{cfg: connCfg, conn: conn})
+	}
+	return s, nil
+}
+
+// Connectors lists the configured connectors with their last run
+func (s *Syncer) Connectors() []types.SyncConnector {
+	list := []types.SyncConnector{}
+	if s == nil {
+		return list
+	}
+	for _, src := range s.sources {
+		src.mu.Lock()
+		list = append(list, types.SyncConnector{Name: src.cfg.Name, Type: src.cfg.Type, Tenant: src.cfg.Tenant, LastRun: src.last})
+		src.mu.Unlock()
+	}
+	return list
+}
+
+// -------------------------------------------------------------
+// Run() → Fetch one connector's roster and apply (or just report) it
+// -------------------------------------------------------------
+// Students are matched by email. Rows that fail validation, repeat an
+// email or belong to a trashed student are reported and skipped; the rest
+// still apply. A failed fetch changes nothing and returns its error along
+// with the report.
+func (s *Syncer) Run(ctx context.Context, name string, dryRun bool) (types.SyncReport, error) {
+	src, err := s.find(name)
+	if err != nil {
+		return types.SyncReport{}, err
+	}
+	if !src.running.TryLock() {
+		return types.SyncReport{}, fmt.Errorf("%w: %s", ErrRunning, name)
+	}
+	defer src.running.Unlock()
+
+	report := types.SyncReport{Connector: name, Tenant: src.cfg.Tenant, DryRun: dryRun, StartedAt: time.Now().UTC(), Changes: []types.SyncChange{}}
+	err = s.run(ctx, src, &report)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	src.mu.Lock()
+	src.last = &report
+	src.mu.Unlock()
+
+	slog.Info("🔄 Roster sync finished",
+		slog.String("connector", name),
+		slog.Bool("dry_run", dryRun),
+		slog.Int("fetched", report.Fetched),
+		slog.Int("created", report.Created),
+		slog.Int("updated", report.Updated),
+		slog.Int("failed", report.Failed),
+	)
+	return report, err
+}
+
+func (s *Syncer) find(name string) (*source, error) {
+	if s != nil {
+		for _, src := range s.sources {
+			if src.cfg.Name == name {
+				return src, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownConnector, name)
+}
+
+func (s *Syncer) run(ctx context.Context, src *source, report *types.SyncReport) error {
+	// 🏫 The roster belongs to the connector's tenant
+	ctx, store, err := s.scope(ctx, src.cfg.Tenant)
+	if err != nil {
+		return err
+	}
+	upserts, ok := storage.As[storage.UpsertStore](store)
+	if !ok {
+		return errors.New("roster sync needs a storage backend with upserts")
+	}
+
+	timeout := src.cfg.Timeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	incoming, err := src.conn.Fetch(fetchCtx)
+	if err != nil {
+		return fmt.Errorf("fetch roster: %w", err)
+	}
+	report.Fetched = len(incoming)
+
+	// 💾 Everything the roster is compared against
+	defs, err := s.custom.Definitions(store)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]types.Student)
+	err = store.GetStudentsIter(ctx, nil, func(student types.Student) error {
+		existing[student.Email] = student
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	trashed := map[string]bool{}
+	if trash, ok := storage.As[storage.TrashStore](store); ok {
+		deleted, err := trash.GetDeletedStudents()
+		if err != nil {
+			return err
+		}
+		for _, student := range deleted {
+			trashed[student.Email] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(incoming))
+	for _, student := range incoming {
+		student.ID = 0
+		change := types.SyncChange{Email: student.Email}
+		fail := func(err error) {
+			change.Action, change.Error = types.SyncFailed, err.Error()
+			report.Failed++
+			report.Changes = append(report.Changes, change)
+		}
+
+		if seen[student.Email] {
+			fail(errors.New("email appears more than once in the roster"))
+			continue
+		}
+		seen[student.Email] = true
+		if err := validate.Student(defs, &student); err != nil {
+			fail(err)
+			continue
+		}
+		if trashed[student.Email] {
+			fail(storage.ErrStudentTrashed)
+			continue
+		}
+
+		change.Action = types.SyncCreate
+		if old, ok := existing[student.Email]; ok {
+			change.Action, change.ID, change.Fields = types.SyncUpdate, old.ID, changedFields(old, student)
+			if len(change.Fields) == 0 {
+				report.Unchanged++
+				continue
+			}
+		}
+
+		if !report.DryRun {
+			saved, created, err := upserts.UpsertStudentByEmail(student)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			change.ID = saved.ID
+			if created {
+				s.notifier.Welcome(ctx, saved)
+			}
+		}
+		if change.Action == types.SyncCreate {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		report.Changes = append(report.Changes, change)
+	}
+	return nil
+}
+
+// scope restricts the backend (and ctx) to the tenant slug;
+// empty means the default tenant
+func (s *Syncer) scope(ctx context.Context, slug string) (context.Context, storage.Storage, error) {
+	if slug == "" {
+		return ctx, s.backend, nil
+	}
+	tenants, ok := storage.As[storage.TenantStore](s.backend)
+	if !ok {
+		return nil, nil, errors.New("storage backend does not support tenants")
+	}
+	t, err := tenants.GetTenantBySlug(slug)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown tenant %q", slug)
+	}
+	ctx = tenant.WithTenant(ctx, t)
+	return ctx, tenant.Scope(ctx, s.backend), nil
+}
+
+// changedFields names the roster fields that differ between the stored
+// student and the incoming one (age follows date_of_birth)
+func changedFields(old, updated types.Student) []string {
+	var fields []string
+	diff := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+	diff("name", old.Name != updated.Name)
+	diff("phone", old.Phone != updated.Phone)
+	diff("date_of_birth", old.DateOfBirth != updated.DateOfBirth)
+	diff("gender", old.Gender != updated.Gender)
+	diff("address", !reflect.DeepEqual(old.Address, updated.Address))
+	diff("custom", !reflect.DeepEqual(old.Custom, updated.Custom))
+	return fields
+}
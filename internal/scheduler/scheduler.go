@@ -0,0 +1,245 @@
+// Package scheduler runs periodic tasks (trash purge, exports, cleanups)
+// on cron schedules from config. A task never overlaps with itself: a run
+// that comes due while the previous one is still going is skipped.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/cron"
+	"github.com/manish-npx/go-student-api/internal/metrics"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// TaskFunc runs one scheduled execution; args come from the task's config
+type TaskFunc func(ctx context.Context, args map[string]string) error
+
+type Scheduler struct {
+	tasks    []config.ScheduledTask
+	registry map[string]TaskFunc
+	// metrics counts runs, failures and skips; nil when disabled
+	metrics *metrics.Tasks
+
+	mu      sync.Mutex
+	entries []*entry
+
+	// loops covers the timer goroutines, runs the task executions
+	loops sync.WaitGroup
+	runs  sync.WaitGroup
+	stop  context.CancelFunc
+	// abort cancels running tasks when Stop runs out of time
+	abort context.CancelFunc
+}
+
+// entry is a scheduled task plus its counters (guarded by Scheduler.mu)
+type entry struct {
+	cfg      config.ScheduledTask
+	schedule cron.Schedule
+	fn       TaskFunc
+	status   types.ScheduledTask
+}
+
+// New returns nil when the scheduler is disabled; a nil *Scheduler is a no-op.
+func New(cfg *config.Config) *Scheduler {
+	if !cfg.Scheduler.Enabled {
+		return nil
+	}
+	return &Scheduler{
+		tasks:    cfg.ScheduledTasks(),
+		registry: make(map[string]TaskFunc),
+	}
+}
+
+// -------------------------------------------------------------
+// Register() → Make a task type available to scheduler.tasks (before Start)
+// -------------------------------------------------------------
+func (s *Scheduler) Register(task string, fn TaskFunc) {
+	if s == nil {
+		return
+	}
+	s.registry[task] = fn
+}
+
+// -------------------------------------------------------------
+// UseMetrics() → Count and time every run for /metrics (before Start)
+// -------------------------------------------------------------
+func (s *Scheduler) UseMetrics(m *metrics.Tasks) {
+	if s == nil {
+		return
+	}
+	s.metrics = m
+}
+
+// -------------------------------------------------------------
+// Start() → Resolve every configured task and start its timer
+// -------------------------------------------------------------
+// Fails (starting nothing) on an unknown task type or a bad schedule.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	var entries []*entry
+	for _, task := range s.tasks {
+		fn, ok := s.registry[task.Task]
+		if !ok {
+			return fmt.Errorf("scheduled task %q: unknown task type %q", task.Name, task.Task)
+		}
+		schedule, err := cron.Parse(task.Schedule)
+		if err != nil {
+			return fmt.Errorf("scheduled task %q: %w", task.Name, err)
+		}
+		entries = append(entries, &entry{
+			cfg:      task,
+			schedule: schedule,
+			fn:       fn,
+			status:   types.ScheduledTask{Name: task.Name, Task: task.Task, Schedule: task.Schedule},
+		})
+	}
+
+	loopCtx, stop := context.WithCancel(ctx)
+	runCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	s.stop, s.abort = stop, abort
+	s.entries = entries
+
+	for _, e := range entries {
+		s.metrics.Add(e.cfg.Name, e.cfg.Task)
+		s.loops.Add(1)
+		go s.loop(loopCtx, runCtx, e)
+	}
+	if len(entries) > 0 {
+		slog.Info("⏰ Scheduler started", slog.Int("tasks", len(entries)))
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// Stop() → Stop the timers and wait for running tasks
+// -------------------------------------------------------------
+// When ctx expires first, running tasks are cancelled.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s == nil || s.stop == nil {
+		return nil
+	}
+	s.stop()
+	s.loops.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.abort()
+		<-done
+		return errors.New("scheduled tasks cancelled before finishing")
+	}
+}
+
+// -------------------------------------------------------------
+// Status() → Snapshot of every task, sorted by name
+// -------------------------------------------------------------
+func (s *Scheduler) Status() []types.ScheduledTask {
+	if s == nil {
+		return []types.ScheduledTask{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]types.ScheduledTask, 0, len(s.entries))
+	for _, e := range s.entries {
+		list = append(list, e.status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func (s *Scheduler) loop(ctx, runCtx context.Context, e *entry) {
+	defer s.loops.Done()
+
+	for {
+		next := e.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("⚠️ Scheduled task will never run again", slog.String("task", e.cfg.Name))
+			return
+		}
+		if e.cfg.Jitter > 0 {
+			next = next.Add(rand.N(e.cfg.Jitter))
+		}
+		s.mu.Lock()
+		e.status.NextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// 🔒 Overlap protection
+		s.mu.Lock()
+		if e.status.Running {
+			e.status.Skipped++
+			s.mu.Unlock()
+			s.metrics.Skip(e.cfg.Name, e.cfg.Task)
+			slog.Warn("⚠️ Skipping scheduled run, previous one still running", slog.String("task", e.cfg.Name))
+			continue
+		}
+		e.status.Running = true
+		s.mu.Unlock()
+
+		s.runs.Add(1)
+		go s.run(runCtx, e)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	defer s.runs.Done()
+
+	started := time.Now()
+	err := runSafely(ctx, e)
+	took := time.Since(started)
+
+	s.mu.Lock()
+	e.status.Running = false
+	e.status.Runs++
+	e.status.LastRun = &started
+	e.status.LastDuration = took.Round(time.Microsecond).String()
+	e.status.LastError = ""
+	if err != nil {
+		e.status.Failures++
+		e.status.LastError = err.Error()
+	}
+	s.mu.Unlock()
+	s.metrics.Run(e.cfg.Name, e.cfg.Task, took, err != nil)
+
+	log := slog.With(slog.String("task", e.cfg.Name), slog.Duration("took", took))
+	if err != nil {
+		log.Error("❌ Scheduled task failed", slog.String("error", err.Error()))
+		return
+	}
+	log.Info("⏰ Scheduled task finished")
+}
+
+// runSafely turns a task panic into a failed run instead of a crash
+func runSafely(ctx context.Context, e *entry) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("task panicked: %v", p)
+		}
+	}()
+	return e.fn(ctx, e.cfg.Args)
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/metrics"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/memory"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+func newScheduler(tasks ...config.ScheduledTask) (*Scheduler, *metrics.Tasks) {
+	s := New(&config.Config{Scheduler: config.Scheduler{Enabled: true, Tasks: tasks}})
+	m := metrics.NewTasks(config.Metrics{Enabled: true})
+	s.UseMetrics(m)
+	return s, m
+}
+
+func scrape(m *metrics.Tasks) string {
+	var out bytes.Buffer
+	m.WritePrometheus(&out)
+	return out.String()
+}
+
+func TestRunMetrics(t *testing.T) {
+	s, m := newScheduler()
+	fail := errors.New("boom")
+	tests := []struct {
+		name string
+		fn   TaskFunc
+	}{
+		{name: "ok", fn: func(context.Context, map[string]string) error { return nil }},
+		{name: "failing", fn: func(context.Context, map[string]string) error { return fail }},
+		{name: "panicking", fn: func(context.Context, map[string]string) error { panic("boom") }},
+	}
+
+	for _, tt := range tests {
+		e := &entry{cfg: config.ScheduledTask{Name: tt.name, Task: "test.task"}, fn: tt.fn}
+		s.runs.Add(1)
+		s.run(context.Background(), e)
+		if e.status.Runs != 1 || e.status.Running {
+			t.Errorf("%s: status = %+v, want one finished run", tt.name, e.status)
+		}
+	}
+
+	out := scrape(m)
+	for _, want := range []string{
+		`student_api_scheduler_runs_total{task="ok",type="test.task"} 1`,
+		`student_api_scheduler_failures_total{task="ok",type="test.task"} 0`,
+		`student_api_scheduler_failures_total{task="failing",type="test.task"} 1`,
+		`student_api_scheduler_failures_total{task="panicking",type="test.task"} 1`,
+		`student_api_scheduler_run_duration_seconds_count{task="failing",type="test.task"} 1`,
+		`student_api_scheduler_run_duration_seconds_bucket{task="ok",type="test.task",le="+Inf"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %s in:\n%s", want, out)
+		}
+	}
+}
+
+// A run that comes due while the previous one is going is skipped and
+// counted; configured tasks show up at zero before they first run.
+func TestSkipMetrics(t *testing.T) {
+	s, m := newScheduler(
+		config.ScheduledTask{Name: "slow", Task: "test.slow", Schedule: "@every 1s"},
+		config.ScheduledTask{Name: "idle", Task: "test.idle", Schedule: "@yearly"},
+	)
+	release := make(chan struct{})
+	s.Register("test.slow", func(ctx context.Context, _ map[string]string) error {
+		<-release
+		return nil
+	})
+	s.Register("test.idle", func(context.Context, map[string]string) error { return nil })
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if out := scrape(m); !strings.Contains(out, `student_api_scheduler_runs_total{task="idle",type="test.idle"} 0`) {
+		t.Errorf("idle task missing from metrics:\n%s", out)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(scrape(m), `student_api_scheduler_skipped_total{task="slow",type="test.slow"} 1`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("no skip counted:\n%s", scrape(m))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	close(release)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// another run may have started between release and Stop
+	if out := scrape(m); strings.Contains(out, `student_api_scheduler_runs_total{task="slow",type="test.slow"} 0`) {
+		t.Errorf("slow run not counted:\n%s", out)
+	}
+}
+
+func TestPurgeAuditTask(t *testing.T) {
+	store := memory.New()
+	privacy, _ := storage.As[storage.PrivacyStore](store)
+	if _, err := privacy.CreateAuditEntry(types.AuditEntry{Action: types.AuditDataExported, Actor: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := newScheduler()
+	RegisterDefaults(s, store, nil, time.Hour)
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		wantErr bool
+		left    int
+	}{
+		{name: "older_than is required", args: nil, wantErr: true, left: 1},
+		{name: "bad older_than", args: map[string]string{"older_than": "a week"}, wantErr: true, left: 1},
+		{name: "entry too recent", args: map[string]string{"older_than": "1h"}, left: 1},
+		{name: "entry old enough", args: map[string]string{"older_than": "1ns"}, left: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			time.Sleep(time.Millisecond)
+			err := s.registry[TaskPurgeAudit](context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("audit.purge error = %v, want error %v", err, tt.wantErr)
+			}
+			entries, _ := privacy.GetAuditEntries(0)
+			if len(entries) != tt.left {
+				t.Errorf("entries left = %d, want %d", len(entries), tt.left)
+			}
+		})
+	}
+}
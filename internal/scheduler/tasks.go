@@ -0,0 +1,227 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/export"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/roster"
+	"github.com/manish-npx/go-student-api/internal/snapshot"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/trash"
+)
+
+// Built-in task types
+const (
+	TaskPurgeTrash  = "trash.purge"
+	TaskPurgeTokens = "auth.purge_tokens"
+	TaskPurgeQuotas = "quotas.purge_usage"
+	TaskPurgeJobs   = "jobs.purge"
+	TaskPurgeAudit  = "audit.purge"
+	TaskReencrypt   = "encryption.reencrypt"
+	TaskRosterSync  = "roster.sync"
+	TaskSnapshot    = "students.snapshot"
+	TaskExport      = "students.export"
+)
+
+// -------------------------------------------------------------
+// RegisterDefaults() → Task types available to scheduler.tasks
+// -------------------------------------------------------------
+// trash.purge args: older_than (defaults to trash.retention)
+// auth.purge_tokens drops expired refresh tokens and revocations
+// quotas.purge_usage args: keep_days (default 30) of daily quota counters
+// jobs.purge args: older_than (default 168h) of finished jobs and their payloads
+// audit.purge args: older_than (required) of audit log entries, every tenant
+// encryption.reencrypt re-seals student PII not under the active key
+func RegisterDefaults(s *Scheduler, backend storage.Storage, blobs blob.Store, retention time.Duration) {
+	if trashStore, ok := storage.As[storage.TrashStore](backend); ok {
+		s.Register(TaskPurgeTrash, func(ctx context.Context, args map[string]string) error {
+			olderThan := retention
+			if raw := args["older_than"]; raw != "" {
+				d, err := time.ParseDuration(raw)
+				if err != nil || d < 0 {
+					return fmt.Errorf("invalid older_than %q", raw)
+				}
+				olderThan = d
+			}
+			_, err := trash.Purge(ctx, trashStore, blobs, olderThan)
+			return err
+		})
+	}
+
+	if tokenStore, ok := storage.As[storage.TokenStore](backend); ok {
+		s.Register(TaskPurgeTokens, func(ctx context.Context, args map[string]string) error {
+			purged, err := tokenStore.PurgeExpiredTokens(time.Now())
+			if err != nil {
+				return err
+			}
+			slog.Info("🎟️ Purged expired tokens", slog.Int64("count", purged))
+			return nil
+		})
+	}
+
+	if quotaStore, ok := storage.As[storage.QuotaStore](backend); ok {
+		s.Register(TaskPurgeQuotas, func(ctx context.Context, args map[string]string) error {
+			keep := 30
+			if raw := args["keep_days"]; raw != "" {
+				n, err := strconv.Atoi(raw)
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid keep_days %q", raw)
+				}
+				keep = n
+			}
+			purged, err := quotaStore.PurgeQuotaUsage(quota.Day(time.Now().AddDate(0, 0, -keep)))
+			if err != nil {
+				return err
+			}
+			slog.Info("🔢 Purged quota counters", slog.Int64("count", purged))
+			return nil
+		})
+	}
+
+	if jobStore, ok := storage.As[storage.JobStore](backend); ok {
+		s.Register(TaskPurgeJobs, func(ctx context.Context, args map[string]string) error {
+			olderThan := 7 * 24 * time.Hour
+			if raw := args["older_than"]; raw != "" {
+				d, err := time.ParseDuration(raw)
+				if err != nil || d < 0 {
+					return fmt.Errorf("invalid older_than %q", raw)
+				}
+				olderThan = d
+			}
+			purged, err := jobStore.PurgeFinishedJobs(time.Now().Add(-olderThan))
+			if err != nil {
+				return err
+			}
+			slog.Info("🧹 Purged finished jobs", slog.Int64("count", purged))
+			return nil
+		})
+	}
+
+	if privacy, ok := storage.As[storage.PrivacyStore](backend); ok {
+		s.Register(TaskPurgeAudit, func(ctx context.Context, args map[string]string) error {
+			// no default: how long the GDPR trail is kept is a policy decision
+			olderThan, err := time.ParseDuration(args["older_than"])
+			if err != nil || olderThan <= 0 {
+				return fmt.Errorf("invalid older_than %q", args["older_than"])
+			}
+			purged, err := privacy.PurgeAuditEntriesBefore(time.Now().Add(-olderThan))
+			if err != nil {
+				return err
+			}
+			slog.Info("📜 Purged audit log entries", slog.Int64("count", purged))
+			return nil
+		})
+	}
+
+	if sealed, ok := storage.As[storage.EncryptionStore](backend); ok {
+		s.Register(TaskReencrypt, func(ctx context.Context, args map[string]string) error {
+			changed, err := sealed.ReencryptStudents()
+			if err != nil {
+				return err
+			}
+			slog.Info("🔐 Re-encrypted students", slog.Int64("count", changed))
+			return nil
+		})
+	}
+}
+
+// -------------------------------------------------------------
+// RegisterSync() → roster.sync, for the connectors in sync.connectors
+// -------------------------------------------------------------
+// args: connector (required), dry_run (default false). Rows that fail are
+// only reported; the run fails when the roster can't be fetched.
+func RegisterSync(s *Scheduler, syncer *roster.Syncer) {
+	if syncer == nil {
+		return
+	}
+	s.Register(TaskRosterSync, func(ctx context.Context, args map[string]string) error {
+		dryRun := false
+		if raw := args["dry_run"]; raw != "" {
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("invalid dry_run %q", raw)
+			}
+			dryRun = b
+		}
+		_, err := syncer.Run(ctx, args["connector"], dryRun)
+		return err
+	})
+}
+
+// -------------------------------------------------------------
+// RegisterSnapshots() → students.snapshot, queued on the job queue
+// -------------------------------------------------------------
+// args: tenant (slug; the default tenant when omitted)
+func RegisterSnapshots(s *Scheduler, snapshots *snapshot.Snapshotter, backend storage.Storage) {
+	if snapshots == nil {
+		return
+	}
+	s.Register(TaskSnapshot, func(ctx context.Context, args map[string]string) error {
+		ctx, err := withTenant(ctx, backend, args["tenant"])
+		if err != nil {
+			return err
+		}
+		snap, err := snapshots.Start(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("📸 Queued scheduled snapshot", slog.Int64("id", snap.ID))
+		return nil
+	})
+}
+
+// -------------------------------------------------------------
+// RegisterExports() → students.export, queued on the job queue
+// -------------------------------------------------------------
+// args: format (csv or json, default csv), tenant (slug; the default tenant
+// when omitted). The file lands in the blob store like a requested export.
+func RegisterExports(s *Scheduler, exporter *export.Exporter, backend storage.Storage) {
+	if exporter == nil {
+		return
+	}
+	s.Register(TaskExport, func(ctx context.Context, args map[string]string) error {
+		format := export.FormatCSV
+		if raw := args["format"]; raw != "" {
+			if !slices.Contains(export.Formats, raw) {
+				return fmt.Errorf("invalid format %q", raw)
+			}
+			format = raw
+		}
+		ctx, err := withTenant(ctx, backend, args["tenant"])
+		if err != nil {
+			return err
+		}
+		exp, err := exporter.Start(ctx, format)
+		if err != nil {
+			return err
+		}
+		slog.Info("📤 Queued scheduled export", slog.Int64("id", exp.ID), slog.String("format", format))
+		return nil
+	})
+}
+
+// withTenant scopes ctx to the tenant with this slug; an empty slug keeps
+// the default tenant
+func withTenant(ctx context.Context, backend storage.Storage, slug string) (context.Context, error) {
+	if slug == "" {
+		return ctx, nil
+	}
+	tenants, ok := storage.As[storage.TenantStore](backend)
+	if !ok {
+		return nil, errors.New("storage backend does not support tenants")
+	}
+	t, err := tenants.GetTenantBySlug(slug)
+	if err != nil {
+		return nil, fmt.Errorf("unknown tenant %q", slug)
+	}
+	return tenant.WithTenant(ctx, t), nil
+}
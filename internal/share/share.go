@@ -0,0 +1,122 @@
+// Package share signs links that give people without an account a
+// read-only view of one student or document until the link expires.
+// Links are stateless (HMAC-SHA256 over the payload), so there is nothing
+// to look up or revoke one by one; rotating the secret revokes them all.
+package share
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+var (
+	ErrInvalid = errors.New("invalid or tampered share link")
+	ErrExpired = errors.New("share link has expired")
+	ErrTTL     = errors.New("ttl must be positive and at most sharing.max_ttl")
+)
+
+// What a link shows
+const (
+	KindStudent  = "student"
+	KindDocument = "document"
+)
+
+// Link is the signed payload: what is shared, of which tenant, until when.
+type Link struct {
+	TenantID  int64  `json:"tid"`
+	Kind      string `json:"kind"`
+	StudentID int64  `json:"sid"`
+	// DocumentID is set for KindDocument only
+	DocumentID int64 `json:"did,omitempty"`
+	ExpiresAt  int64 `json:"exp"`
+}
+
+// Signer signs and checks share links.
+// A nil *Signer (no sharing.secret) means sharing is disabled.
+type Signer struct {
+	secret     []byte
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	base       string
+}
+
+// -------------------------------------------------------------
+// New() → Signer, or nil when sharing is disabled
+// -------------------------------------------------------------
+// baseURL is http_server.base_url; links point at <baseURL>/api/shared/.
+func New(cfg config.Sharing, baseURL string) *Signer {
+	if cfg.Secret == "" {
+		return nil
+	}
+	return &Signer{
+		secret:     []byte(cfg.Secret),
+		defaultTTL: cfg.DefaultTTL,
+		maxTTL:     cfg.MaxTTL,
+		base:       strings.TrimRight(baseURL, "/") + "/api/shared/",
+	}
+}
+
+// -------------------------------------------------------------
+// Sign() → URL of a link for l, valid for ttl (0 → sharing.default_ttl)
+// -------------------------------------------------------------
+// l.ExpiresAt is set from ttl; the expiry is returned too.
+func (s *Signer) Sign(l Link, ttl time.Duration) (string, time.Time, error) {
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+	if ttl < 0 || ttl > s.maxTTL {
+		return "", time.Time{}, fmt.Errorf("%w, got %s", ErrTTL, ttl)
+	}
+	expiresAt := clock.Now().Add(ttl)
+	l.ExpiresAt = expiresAt.Unix()
+
+	payload, err := json.Marshal(l)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(payload)
+	return s.base + unsigned + "." + base64.RawURLEncoding.EncodeToString(s.sign(unsigned)), expiresAt, nil
+}
+
+// -------------------------------------------------------------
+// Verify() → Link of an untampered, unexpired token
+// -------------------------------------------------------------
+// token is the last path segment of a URL from Sign.
+func (s *Signer) Verify(token string) (Link, error) {
+	unsigned, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return Link{}, ErrInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.sign(unsigned)) {
+		return Link{}, ErrInvalid
+	}
+
+	var l Link
+	payload, err := base64.RawURLEncoding.DecodeString(unsigned)
+	if err != nil || json.Unmarshal(payload, &l) != nil {
+		return Link{}, ErrInvalid
+	}
+	if l.Kind != KindStudent && l.Kind != KindDocument {
+		return Link{}, ErrInvalid
+	}
+	if time.Now().Unix() >= l.ExpiresAt {
+		return Link{}, ErrExpired
+	}
+	return l, nil
+}
+
+func (s *Signer) sign(data string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,286 @@
+// Package snapshot keeps point-in-time copies of a tenant's students in the
+// blob store and diffs them, for audits ("who was enrolled on 1 March, and
+// what changed since?"). Like exports, snapshots are written by jobs and a
+// snapshot's ID is the ID of its job.
+package snapshot
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/export"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// TypeSnapshot is the job type that takes one snapshot
+const TypeSnapshot = "students.snapshot"
+
+// listScan is how many of the newest jobs List looks through
+const listScan = 1000
+
+var (
+	ErrNotFound = errors.New("snapshot not found")
+	// ErrNotReady is returned when diffing a snapshot that hasn't succeeded
+	ErrNotReady = errors.New("snapshot not ready")
+)
+
+// payload is the job payload; snapshots belong to one tenant
+type payload struct {
+	TenantID int64 `json:"tenant_id"`
+}
+
+// Snapshotter queues snapshots, runs them as students.snapshot jobs and
+// diffs them. A nil *Snapshotter (no job queue) rejects everything with
+// jobs.ErrDisabled.
+type Snapshotter struct {
+	backend storage.Storage
+	queue   *jobs.Queue
+	blobs   blob.Store
+}
+
+// -------------------------------------------------------------
+// New() → Snapshotter registered as the students.snapshot job handler
+// -------------------------------------------------------------
+// Returns nil when there is no job queue.
+func New(backend storage.Storage, queue *jobs.Queue, blobs blob.Store) *Snapshotter {
+	if queue == nil {
+		return nil
+	}
+	s := &Snapshotter{backend: backend, queue: queue, blobs: blobs}
+	queue.Register(TypeSnapshot, s.run)
+	return s
+}
+
+// Key is where a snapshot's file lives in the blob store
+func Key(tenantID, id int64) string {
+	return fmt.Sprintf("snapshots/%d/%d.json", tenantID, id)
+}
+
+// -------------------------------------------------------------
+// Start() → Queue a snapshot of the context's tenant
+// -------------------------------------------------------------
+func (s *Snapshotter) Start(ctx context.Context) (types.Snapshot, error) {
+	if s == nil {
+		return types.Snapshot{}, jobs.ErrDisabled
+	}
+	id, err := s.queue.Enqueue(TypeSnapshot, payload{TenantID: tenantID(ctx)})
+	if err != nil {
+		return types.Snapshot{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+// -------------------------------------------------------------
+// Get() → The snapshot's status, read from its job
+// -------------------------------------------------------------
+// Other tenants' snapshots (and other job types) are ErrNotFound.
+func (s *Snapshotter) Get(ctx context.Context, id int64) (types.Snapshot, error) {
+	if s == nil {
+		return types.Snapshot{}, jobs.ErrDisabled
+	}
+	job, err := s.backend.(storage.JobStore).GetJobById(id)
+	if err != nil {
+		return types.Snapshot{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	snap, ok := fromJob(job, tenantID(ctx))
+	if !ok {
+		return types.Snapshot{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	return snap, nil
+}
+
+// -------------------------------------------------------------
+// List() → The tenant's snapshots, newest first
+// -------------------------------------------------------------
+// Only the newest listScan jobs are looked through; older snapshots are
+// still reachable by ID.
+func (s *Snapshotter) List(ctx context.Context) ([]types.Snapshot, error) {
+	if s == nil {
+		return nil, jobs.ErrDisabled
+	}
+	list, err := s.backend.(storage.JobStore).GetJobs("", listScan)
+	if err != nil {
+		return nil, err
+	}
+	snaps := []types.Snapshot{}
+	for _, job := range list {
+		if snap, ok := fromJob(job, tenantID(ctx)); ok {
+			snaps = append(snaps, snap)
+		}
+	}
+	return snaps, nil
+}
+
+// -------------------------------------------------------------
+// Diff() → Students added, removed and changed from snapshot a to b
+// -------------------------------------------------------------
+// Both snapshots must have succeeded (ErrNotReady otherwise). Each list is
+// ordered by student id.
+func (s *Snapshotter) Diff(ctx context.Context, a, b int64) (types.SnapshotDiff, error) {
+	if s == nil {
+		return types.SnapshotDiff{}, jobs.ErrDisabled
+	}
+	before, err := s.load(ctx, a)
+	if err != nil {
+		return types.SnapshotDiff{}, err
+	}
+	after, err := s.load(ctx, b)
+	if err != nil {
+		return types.SnapshotDiff{}, err
+	}
+
+	diff := types.SnapshotDiff{From: a, To: b, Added: []types.Student{}, Removed: []types.Student{}, Changed: []types.SnapshotChange{}}
+	old := make(map[int64]types.Student, len(before))
+	for _, student := range before {
+		old[student.ID] = student
+	}
+	for _, student := range after {
+		previous, ok := old[student.ID]
+		if !ok {
+			diff.Added = append(diff.Added, student)
+			continue
+		}
+		delete(old, student.ID)
+		if fields := changedFields(previous, student); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, types.SnapshotChange{ID: student.ID, Fields: fields, Before: previous, After: student})
+		}
+	}
+	for _, student := range before {
+		if _, ok := old[student.ID]; ok {
+			diff.Removed = append(diff.Removed, student)
+		}
+	}
+	return diff, nil
+}
+
+// load reads a finished snapshot's students
+func (s *Snapshotter) load(ctx context.Context, id int64) ([]types.Student, error) {
+	snap, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Status != types.JobSucceeded {
+		return nil, fmt.Errorf("%w: snapshot %d is %s", ErrNotReady, id, snap.Status)
+	}
+
+	body, _, err := s.blobs.Get(ctx, Key(tenantID(ctx), id))
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, fmt.Errorf("%w: snapshot %d file is no longer available", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot %d: %w", id, err)
+	}
+	defer body.Close()
+
+	var students []types.Student
+	if err := json.NewDecoder(body).Decode(&students); err != nil {
+		return nil, fmt.Errorf("decode snapshot %d: %w", id, err)
+	}
+	// written in id order; sort anyway so Diff never depends on it
+	slices.SortFunc(students, func(x, y types.Student) int { return cmp.Compare(x.ID, y.ID) })
+	return students, nil
+}
+
+// run is the students.snapshot job: write the tenant's students as JSON to
+// a temp file, then upload it. A retry simply overwrites the blob.
+func (s *Snapshotter) run(ctx context.Context, job types.Job) error {
+	var p payload
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode snapshot payload: %w", err))
+	}
+
+	scoped := s.backend
+	if scoper, ok := storage.As[storage.TenantScoper](s.backend); ok {
+		scoped = scoper.ForTenant(p.TenantID)
+	}
+
+	// 🧠 Spool to disk so memory stays flat however big the roster is
+	file, err := os.CreateTemp("", "student-snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	rows, err := export.Write(ctx, file, scoped, export.FormatJSON)
+	if err != nil {
+		return err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// 💾 Upload
+	if err := s.blobs.Put(ctx, Key(p.TenantID, job.ID), file, size, export.ContentType(export.FormatJSON)); err != nil {
+		return fmt.Errorf("store snapshot: %w", err)
+	}
+
+	slog.Info("📸 Snapshot taken",
+		slog.Int64("id", job.ID),
+		slog.Int64("tenant_id", p.TenantID),
+		slog.Int("students", rows),
+		slog.Int64("bytes", size),
+	)
+	return nil
+}
+
+// fromJob turns a students.snapshot job of tenantID into its snapshot
+func fromJob(job types.Job, tenantID int64) (types.Snapshot, bool) {
+	var p payload
+	if job.Type != TypeSnapshot || json.Unmarshal(job.Payload, &p) != nil || p.TenantID != tenantID {
+		return types.Snapshot{}, false
+	}
+	return types.Snapshot{
+		ID:         job.ID,
+		Status:     job.Status,
+		Attempts:   job.Attempts,
+		LastError:  job.LastError,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}, true
+}
+
+// changedFields names the student fields that differ between two
+// snapshots. Age only counts for students without a date_of_birth: for the
+// others it follows the calendar, not an edit.
+func changedFields(before, after types.Student) []string {
+	var fields []string
+	diff := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+	diff("name", before.Name != after.Name)
+	diff("email", before.Email != after.Email)
+	diff("age", after.DateOfBirth == "" && before.Age != after.Age)
+	diff("phone", before.Phone != after.Phone)
+	diff("date_of_birth", before.DateOfBirth != after.DateOfBirth)
+	diff("gender", before.Gender != after.Gender)
+	diff("address", !reflect.DeepEqual(before.Address, after.Address))
+	diff("custom", !reflect.DeepEqual(before.Custom, after.Custom))
+	return fields
+}
+
+// tenantID is the context's tenant, or the default one when tenancy is off
+func tenantID(ctx context.Context) int64 {
+	if t, ok := tenant.FromContext(ctx); ok {
+		return t.ID
+	}
+	return storage.DefaultTenantID
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/lru"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// CacheAuth() → backend with the per-request auth lookups cached in process
+// -------------------------------------------------------------
+// Every authenticated request looks up its API key by hash, or checks its
+// access token against the revocation list and its key by id. Those
+// answers are kept in LRUs of size entries for up to ttl, so a warm
+// request does no query.
+//
+// Revoking a key or a token through the result drops it from the cache
+// at once. A revocation made by another process is seen within ttl. Keys
+// that were not found are never cached. size or ttl 0 returns backend as
+// it is.
+func CacheAuth(backend Storage, size int, ttl time.Duration) Storage {
+	if size <= 0 || ttl <= 0 {
+		return backend
+	}
+	return &authCached{
+		decorated: &decorated{inner: backend, intercept: passThrough},
+		keys:      lru.New[string, types.APIKey](size, ttl),
+		keysByID:  lru.New[int64, types.APIKey](size, ttl),
+		revoked:   lru.New[string, bool](size, ttl),
+	}
+}
+
+// passThrough runs every call as it is
+func passThrough(_ string, _ []any, call func() error) error {
+	return call()
+}
+
+// authCached forwards everything through decorated and answers the auth
+// lookups from its caches. Tenant and trace views share the caches.
+type authCached struct {
+	*decorated
+	// keys holds API keys by hash, keysByID the same keys by id
+	keys     *lru.Cache[string, types.APIKey]
+	keysByID *lru.Cache[int64, types.APIKey]
+	// revoked holds IsAccessTokenRevoked answers by jti
+	revoked *lru.Cache[string, bool]
+}
+
+// overriding methods must keep every capability of decorated
+var _ interface {
+	TenantScoper
+	OutboxStore
+	APIKeyStore
+	TokenStore
+} = (*authCached)(nil)
+
+// view wraps a tenant or trace view of the backend around the same caches
+func (a *authCached) view(s Storage) Storage {
+	return &authCached{decorated: &decorated{inner: s, intercept: passThrough}, keys: a.keys, keysByID: a.keysByID, revoked: a.revoked}
+}
+
+func (a *authCached) ForTenant(tenantID int64) Storage {
+	return a.view(a.inner.(TenantScoper).ForTenant(tenantID))
+}
+
+func (a *authCached) WithTrace(trace json.RawMessage) Storage {
+	return a.view(a.inner.(OutboxStore).WithTrace(trace))
+}
+
+// APIKeyStore
+
+func (a *authCached) GetAPIKeyByHash(hash string) (types.APIKey, error) {
+	if key, ok := a.keys.Get(hash); ok {
+		return key, nil
+	}
+	key, err := a.decorated.GetAPIKeyByHash(hash)
+	if err != nil {
+		return key, err
+	}
+	a.keys.Add(hash, key)
+	return key, nil
+}
+
+func (a *authCached) GetAPIKeyById(id int64) (types.APIKey, error) {
+	if key, ok := a.keysByID.Get(id); ok {
+		return key, nil
+	}
+	key, err := a.decorated.GetAPIKeyById(id)
+	if err != nil {
+		return key, err
+	}
+	a.keysByID.Add(id, key)
+	return key, nil
+}
+
+func (a *authCached) RevokeAPIKey(id int64) (types.APIKey, error) {
+	key, err := a.decorated.RevokeAPIKey(id)
+	// dropped even on error: the key may have been revoked before
+	a.keys.RemoveFunc(func(_ string, cached types.APIKey) bool { return cached.ID == id })
+	a.keysByID.Remove(id)
+	return key, err
+}
+
+// TokenStore
+
+func (a *authCached) IsAccessTokenRevoked(jti string) (bool, error) {
+	if revoked, ok := a.revoked.Get(jti); ok {
+		return revoked, nil
+	}
+	revoked, err := a.decorated.IsAccessTokenRevoked(jti)
+	if err != nil {
+		return false, err
+	}
+	a.revoked.Add(jti, revoked)
+	return revoked, nil
+}
+
+func (a *authCached) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	if err := a.decorated.RevokeAccessToken(jti, expiresAt); err != nil {
+		return err
+	}
+	a.revoked.Add(jti, true)
+	return nil
+}
@@ -0,0 +1,10 @@
+package storage
+
+import "context"
+
+// BackupStore copies the whole database, every tenant included, while the
+// API keeps serving. Only sqlite has it; postgres has pg_dump.
+type BackupStore interface {
+	// Backup writes a consistent copy to path, which must not exist yet
+	Backup(ctx context.Context, path string) error
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// BulkStore changes many students of the tenant in one transaction, for
+// admin cleanups. Results follow the order of ids; ids that aren't live
+// students of the tenant come back not_found.
+type BulkStore interface {
+	// UpdateStudents passes each student to change and stores what it
+	// returns; a change error marks that student invalid and leaves it as is
+	UpdateStudents(ids []int64, change func(types.Student) (types.Student, error)) ([]types.BulkResult, error)
+	// DeleteStudents soft-deletes the students
+	DeleteStudents(ids []int64) ([]types.BulkResult, error)
+}
+
+// BulkResults lists done's results in the order of ids, not_found for
+// the ids done has nothing for
+func BulkResults(ids []int64, done map[int64]types.BulkResult) []types.BulkResult {
+	results := make([]types.BulkResult, 0, len(ids))
+	for _, id := range ids {
+		result, ok := done[id]
+		if !ok {
+			result = types.BulkResult{ID: id, Status: types.BulkNotFound, Error: fmt.Sprintf("no student found with id: %d", id)}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// BulkChanged is what change made of student, as a result: updated with
+// the new record, or invalid with change's error
+func BulkChanged(student types.Student, change func(types.Student) (types.Student, error)) types.BulkResult {
+	updated, err := change(student)
+	if err != nil {
+		return types.BulkResult{ID: student.ID, Status: types.BulkInvalid, Error: err.Error()}
+	}
+	updated.ID, updated.PublicID = student.ID, student.PublicID
+	return types.BulkResult{ID: student.ID, Status: types.BulkUpdated, Student: &updated}
+}
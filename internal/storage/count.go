@@ -0,0 +1,8 @@
+package storage
+
+// CountStore counts students in the database instead of loading them.
+type CountStore interface {
+	// CountStudents counts the live students matching q's Filter or
+	// Verified; AfterID and Limit are ignored
+	CountStudents(q StudentQuery) (int64, error)
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// PageStore lists students with keyset pagination on id, so rows
+// inserted or deleted between requests never shift later pages.
+type PageStore interface {
+	// GetStudentsAfter returns up to limit students with id > afterID, ordered by id
+	GetStudentsAfter(afterID int64, limit int) ([]types.Student, error)
+}
+
+// ReversePageStore walks pages backwards, for prev / last links.
+type ReversePageStore interface {
+	// GetStudentsBefore returns up to limit students with id < beforeID
+	// (the ones closest to it), ordered by id
+	GetStudentsBefore(beforeID int64, limit int) ([]types.Student, error)
+}
+
+// IterBatchSize is how many students GetStudentsIter reads per query
+const IterBatchSize = 500
+
+// -------------------------------------------------------------
+// IterateStudents() → GetStudentsIter on top of a keyset batch query
+// -------------------------------------------------------------
+// fetch returns up to limit students with id > afterID, ordered by id,
+// and has finished its query when it returns. Backends implement
+// GetStudentsIter with it; fn's errors come back as they are.
+func IterateStudents(ctx context.Context, fetch func(afterID int64, limit int) ([]types.Student, error), fn func(types.Student) error) error {
+	for afterID := int64(0); ; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := fetch(afterID, IterBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, student := range batch {
+			if err := fn(student); err != nil {
+				return err
+			}
+		}
+		if len(batch) < IterBatchSize {
+			return nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+// Cursor is the payload hidden inside the opaque token: the page starts
+// after ID, or — when Before is set — ends just before it.
+type Cursor struct {
+	ID     int64 `json:"id"`
+	Before bool  `json:"before,omitempty"`
+}
+
+// lastID makes a Before cursor that points past every row (the last page)
+const lastID = math.MaxInt64
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// -------------------------------------------------------------
+// EncodeCursor() → Opaque, URL-safe token pointing after id
+// -------------------------------------------------------------
+func EncodeCursor(id int64) string {
+	return encode(Cursor{ID: id})
+}
+
+// -------------------------------------------------------------
+// EncodeBeforeCursor() → Token for the page ending just before id
+// -------------------------------------------------------------
+func EncodeBeforeCursor(id int64) string {
+	return encode(Cursor{ID: id, Before: true})
+}
+
+// LastCursor is the token for the final page
+func LastCursor() string {
+	return EncodeBeforeCursor(lastID)
+}
+
+func encode(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// -------------------------------------------------------------
+// DecodeCursor() → Inverse of the Encode functions ("" means first page)
+// -------------------------------------------------------------
+func DecodeCursor(token string) (Cursor, error) {
+	if token == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID < 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	// nothing comes after the largest id or before 0, and the cursors a page
+	// next to them hands out would overflow; no page hands these out
+	if (c.Before && c.ID == 0) || (!c.Before && c.ID == lastID) {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// -------------------------------------------------------------
+// BuildPage() → Trim a limit+1 fetch to a page and its cursors
+// -------------------------------------------------------------
+// Backends fetch one extra row so we know whether another page exists
+// without a COUNT query. For a Before cursor the extra row is the oldest
+// one and says whether a previous page exists.
+func BuildPage(students []types.Student, limit int, at Cursor) types.StudentPage {
+	page := types.StudentPage{Data: students, Limit: limit}
+	if at.Before {
+		if len(students) > limit {
+			page.Data = students[len(students)-limit:]
+			page.PrevCursor = EncodeBeforeCursor(page.Data[0].ID)
+		}
+		if at.ID != lastID {
+			page.HasMore = true
+			page.NextCursor = EncodeCursor(at.ID - 1)
+		}
+	} else {
+		if len(students) > limit {
+			page.Data = students[:limit]
+			page.HasMore = true
+			page.NextCursor = EncodeCursor(page.Data[limit-1].ID)
+		}
+		if at.ID > 0 {
+			page.PrevCursor = EncodeBeforeCursor(at.ID + 1)
+		}
+	}
+	if page.Data == nil {
+		page.Data = []types.Student{}
+	}
+	return page
+}
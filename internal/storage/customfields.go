@@ -0,0 +1,21 @@
+package storage
+
+import "github.com/manish-npx/go-student-api/internal/types"
+
+// CustomFieldStore keeps the custom field definitions a tenant added
+// through the admin API (config ones are never stored). Queries are
+// tenant-scoped like student queries.
+type CustomFieldStore interface {
+	CreateCustomField(field types.CustomField) error
+	GetCustomFields() ([]types.CustomField, error)
+	// DeleteCustomField drops the definition; students keep their values
+	DeleteCustomField(key string) error
+}
+
+// CustomFilterStore lists live students by their custom attributes.
+type CustomFilterStore interface {
+	// GetStudentsWhere returns students with id > afterID whose custom
+	// values equal every entry of filter (string, float64 or bool), ordered
+	// by id; limit 0 means no limit. Keys must be valid custom field keys.
+	GetStudentsWhere(filter map[string]any, afterID int64, limit int) ([]types.Student, error)
+}
@@ -0,0 +1,676 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Interceptor runs one storage call on behalf of a decorator (metrics,
+// fault injection, ...). method is the Go method name and args its
+// arguments, contexts and callbacks left out; the interceptor calls call
+// and returns its error, or an error of its own instead.
+type Interceptor func(method string, args []any, call func() error) error
+
+// -------------------------------------------------------------
+// Decorate() → backend with every call passed through intercept
+// -------------------------------------------------------------
+// The result has the methods of every optional capability so they can be
+// intercepted too, whether or not backend supports them: look capabilities
+// up with As, never with a plain type assertion. Views from ForTenant and
+// WithTrace are decorated the same way.
+func Decorate(backend Storage, intercept Interceptor) Storage {
+	return &decorated{inner: backend, intercept: intercept}
+}
+
+// -------------------------------------------------------------
+// As() → s as capability T, if the backend under any decorators has it
+// -------------------------------------------------------------
+//
+//	trash, ok := storage.As[storage.TrashStore](store)
+func As[T any](s Storage) (T, bool) {
+	backend := s
+	for {
+		wrapper, ok := backend.(interface{ Unwrap() Storage })
+		if !ok {
+			break
+		}
+		backend = wrapper.Unwrap()
+	}
+	capability, ok := backend.(T)
+	if !ok {
+		return capability, false
+	}
+	// go through the decorators when they forward T
+	if decorated, ok := s.(T); ok {
+		return decorated, true
+	}
+	return capability, true
+}
+
+type decorated struct {
+	inner     Storage
+	intercept Interceptor
+}
+
+// Every capability is forwarded; As hides the ones inner lacks
+var _ interface {
+	TrashStore
+	TenantScoper
+	TenantStore
+	DocumentStore
+	InvoiceStore
+	GuardianStore
+	JobStore
+	WebhookStore
+	APIKeyStore
+	TokenStore
+	QuotaStore
+	EncryptionStore
+	ReconnectStore
+	PoolStore
+	BackupStore
+	SchemaStore
+	PageStore
+	ReversePageStore
+	FieldStore
+	BatchStore
+	CustomFieldStore
+	CustomFilterStore
+	StatsStore
+	BulkStore
+	UpsertStore
+	UniquenessStore
+	PrivacyStore
+	OutboxStore
+	VerificationStore
+	PortalStore
+	PublicIDStore
+	ExplainStore
+	IndexStore
+	CountStore
+	ReportStore
+	EmailDomainStore
+} = (*decorated)(nil)
+
+// Unwrap returns the decorated backend
+func (d *decorated) Unwrap() Storage {
+	return d.inner
+}
+
+// call intercepts a call returning a value and an error
+func call[T any](d *decorated, method string, fn func() (T, error), args ...any) (T, error) {
+	var value T
+	err := d.intercept(method, args, func() (err error) {
+		value, err = fn()
+		return err
+	})
+	return value, err
+}
+
+// Storage
+
+func (d *decorated) CreateStudent(student types.Student) (int64, error) {
+	return call(d, "CreateStudent", func() (int64, error) { return d.inner.CreateStudent(student) }, student)
+}
+
+func (d *decorated) GetStudentById(id int64) (types.Student, error) {
+	return call(d, "GetStudentById", func() (types.Student, error) { return d.inner.GetStudentById(id) }, id)
+}
+
+func (d *decorated) GetStudents() ([]types.Student, error) {
+	return call(d, "GetStudents", d.inner.GetStudents)
+}
+
+// GetStudentsIter is intercepted as one call, however many batches it reads
+func (d *decorated) GetStudentsIter(ctx context.Context, filter map[string]any, fn func(types.Student) error) error {
+	return d.intercept("GetStudentsIter", []any{filter}, func() error { return d.inner.GetStudentsIter(ctx, filter, fn) })
+}
+
+func (d *decorated) UpdateStudentById(id int64, student types.Student) (types.Student, error) {
+	return call(d, "UpdateStudentById", func() (types.Student, error) { return d.inner.UpdateStudentById(id, student) }, id, student)
+}
+
+func (d *decorated) DeleteStudentById(id int64) error {
+	return d.intercept("DeleteStudentById", []any{id}, func() error { return d.inner.DeleteStudentById(id) })
+}
+
+// TrashStore
+
+func (d *decorated) GetDeletedStudents() ([]types.Student, error) {
+	return call(d, "GetDeletedStudents", d.inner.(TrashStore).GetDeletedStudents)
+}
+
+func (d *decorated) RestoreStudentById(id int64) (types.Student, error) {
+	return call(d, "RestoreStudentById", func() (types.Student, error) { return d.inner.(TrashStore).RestoreStudentById(id) }, id)
+}
+
+func (d *decorated) PurgeDeletedBefore(cutoff time.Time) (Purged, error) {
+	return call(d, "PurgeDeletedBefore", func() (Purged, error) { return d.inner.(TrashStore).PurgeDeletedBefore(cutoff) }, cutoff)
+}
+
+// TenantScoper
+
+func (d *decorated) ForTenant(tenantID int64) Storage {
+	return Decorate(d.inner.(TenantScoper).ForTenant(tenantID), d.intercept)
+}
+
+// TenantStore
+
+func (d *decorated) CreateTenant(slug string, name string) (int64, error) {
+	return call(d, "CreateTenant", func() (int64, error) { return d.inner.(TenantStore).CreateTenant(slug, name) }, slug, name)
+}
+
+func (d *decorated) GetTenantBySlug(slug string) (types.Tenant, error) {
+	return call(d, "GetTenantBySlug", func() (types.Tenant, error) { return d.inner.(TenantStore).GetTenantBySlug(slug) }, slug)
+}
+
+func (d *decorated) GetTenants() ([]types.Tenant, error) {
+	return call(d, "GetTenants", d.inner.(TenantStore).GetTenants)
+}
+
+// DocumentStore
+
+func (d *decorated) CreateDocument(doc types.Document) (int64, error) {
+	return call(d, "CreateDocument", func() (int64, error) { return d.inner.(DocumentStore).CreateDocument(doc) }, doc)
+}
+
+func (d *decorated) GetDocuments(studentID int64) ([]types.Document, error) {
+	return call(d, "GetDocuments", func() ([]types.Document, error) { return d.inner.(DocumentStore).GetDocuments(studentID) }, studentID)
+}
+
+func (d *decorated) GetStudentDocuments(studentIDs []int64) (map[int64][]types.Document, error) {
+	return call(d, "GetStudentDocuments", func() (map[int64][]types.Document, error) {
+		return d.inner.(DocumentStore).GetStudentDocuments(studentIDs)
+	}, studentIDs)
+}
+
+func (d *decorated) GetDocumentById(studentID int64, id int64) (types.Document, error) {
+	return call(d, "GetDocumentById", func() (types.Document, error) { return d.inner.(DocumentStore).GetDocumentById(studentID, id) }, studentID, id)
+}
+
+func (d *decorated) DeleteDocumentById(studentID int64, id int64) error {
+	return d.intercept("DeleteDocumentById", []any{studentID, id}, func() error { return d.inner.(DocumentStore).DeleteDocumentById(studentID, id) })
+}
+
+// InvoiceStore
+
+func (d *decorated) CreateInvoice(invoice types.Invoice) (int64, error) {
+	return call(d, "CreateInvoice", func() (int64, error) { return d.inner.(InvoiceStore).CreateInvoice(invoice) }, invoice)
+}
+
+func (d *decorated) GetInvoiceById(id int64) (types.Invoice, error) {
+	return call(d, "GetInvoiceById", func() (types.Invoice, error) { return d.inner.(InvoiceStore).GetInvoiceById(id) }, id)
+}
+
+func (d *decorated) GetInvoices(studentID int64) ([]types.Invoice, error) {
+	return call(d, "GetInvoices", func() ([]types.Invoice, error) { return d.inner.(InvoiceStore).GetInvoices(studentID) }, studentID)
+}
+
+func (d *decorated) GetStudentInvoices(studentIDs []int64) (map[int64][]types.Invoice, error) {
+	return call(d, "GetStudentInvoices", func() (map[int64][]types.Invoice, error) {
+		return d.inner.(InvoiceStore).GetStudentInvoices(studentIDs)
+	}, studentIDs)
+}
+
+func (d *decorated) PayInvoice(id int64, reference string) (types.Invoice, error) {
+	return call(d, "PayInvoice", func() (types.Invoice, error) { return d.inner.(InvoiceStore).PayInvoice(id, reference) }, id, reference)
+}
+
+func (d *decorated) GetOverdueInvoices(day string) ([]types.Invoice, error) {
+	return call(d, "GetOverdueInvoices", func() ([]types.Invoice, error) { return d.inner.(InvoiceStore).GetOverdueInvoices(day) }, day)
+}
+
+// GuardianStore
+
+func (d *decorated) CreateGuardian(guardian types.Guardian) (int64, error) {
+	return call(d, "CreateGuardian", func() (int64, error) { return d.inner.(GuardianStore).CreateGuardian(guardian) }, guardian)
+}
+
+func (d *decorated) GetGuardians() ([]types.Guardian, error) {
+	return call(d, "GetGuardians", d.inner.(GuardianStore).GetGuardians)
+}
+
+func (d *decorated) GetGuardianById(id int64) (types.Guardian, error) {
+	return call(d, "GetGuardianById", func() (types.Guardian, error) { return d.inner.(GuardianStore).GetGuardianById(id) }, id)
+}
+
+func (d *decorated) UpdateGuardianById(id int64, guardian types.Guardian) (types.Guardian, error) {
+	return call(d, "UpdateGuardianById", func() (types.Guardian, error) { return d.inner.(GuardianStore).UpdateGuardianById(id, guardian) }, id, guardian)
+}
+
+func (d *decorated) DeleteGuardianById(id int64) error {
+	return d.intercept("DeleteGuardianById", []any{id}, func() error { return d.inner.(GuardianStore).DeleteGuardianById(id) })
+}
+
+func (d *decorated) LinkGuardian(guardianID, studentID int64, relationship string) error {
+	return d.intercept("LinkGuardian", []any{guardianID, studentID, relationship}, func() error { return d.inner.(GuardianStore).LinkGuardian(guardianID, studentID, relationship) })
+}
+
+func (d *decorated) UnlinkGuardian(guardianID, studentID int64) (bool, error) {
+	return call(d, "UnlinkGuardian", func() (bool, error) { return d.inner.(GuardianStore).UnlinkGuardian(guardianID, studentID) }, guardianID, studentID)
+}
+
+func (d *decorated) GetStudentGuardians(studentIDs []int64) (map[int64][]types.StudentGuardian, error) {
+	return call(d, "GetStudentGuardians", func() (map[int64][]types.StudentGuardian, error) {
+		return d.inner.(GuardianStore).GetStudentGuardians(studentIDs)
+	}, studentIDs)
+}
+
+func (d *decorated) GetGuardianStudents(guardianID int64) ([]types.GuardianLink, error) {
+	return call(d, "GetGuardianStudents", func() ([]types.GuardianLink, error) { return d.inner.(GuardianStore).GetGuardianStudents(guardianID) }, guardianID)
+}
+
+// JobStore
+
+func (d *decorated) EnqueueJob(job types.Job) (int64, error) {
+	return call(d, "EnqueueJob", func() (int64, error) { return d.inner.(JobStore).EnqueueJob(job) }, job)
+}
+
+func (d *decorated) ClaimJob(now time.Time) (job types.Job, ok bool, err error) {
+	err = d.intercept("ClaimJob", []any{now}, func() (err error) {
+		job, ok, err = d.inner.(JobStore).ClaimJob(now)
+		return err
+	})
+	return job, ok, err
+}
+
+func (d *decorated) CompleteJob(id int64) error {
+	return d.intercept("CompleteJob", []any{id}, func() error { return d.inner.(JobStore).CompleteJob(id) })
+}
+
+func (d *decorated) FailJob(id int64, lastError string, retryAt *time.Time) error {
+	return d.intercept("FailJob", []any{id, lastError, retryAt}, func() error { return d.inner.(JobStore).FailJob(id, lastError, retryAt) })
+}
+
+func (d *decorated) RequeueJob(id int64) (types.Job, error) {
+	return call(d, "RequeueJob", func() (types.Job, error) { return d.inner.(JobStore).RequeueJob(id) }, id)
+}
+
+func (d *decorated) ResetRunningJobs() (int64, error) {
+	return call(d, "ResetRunningJobs", d.inner.(JobStore).ResetRunningJobs)
+}
+
+func (d *decorated) GetJobById(id int64) (types.Job, error) {
+	return call(d, "GetJobById", func() (types.Job, error) { return d.inner.(JobStore).GetJobById(id) }, id)
+}
+
+func (d *decorated) GetJobs(status string, limit int) ([]types.Job, error) {
+	return call(d, "GetJobs", func() ([]types.Job, error) { return d.inner.(JobStore).GetJobs(status, limit) }, status, limit)
+}
+
+func (d *decorated) PurgeFinishedJobs(cutoff time.Time) (int64, error) {
+	return call(d, "PurgeFinishedJobs", func() (int64, error) { return d.inner.(JobStore).PurgeFinishedJobs(cutoff) }, cutoff)
+}
+
+// WebhookStore
+
+func (d *decorated) CreateWebhook(hook types.Webhook) (int64, error) {
+	return call(d, "CreateWebhook", func() (int64, error) { return d.inner.(WebhookStore).CreateWebhook(hook) }, hook)
+}
+
+func (d *decorated) GetWebhooks() ([]types.Webhook, error) {
+	return call(d, "GetWebhooks", d.inner.(WebhookStore).GetWebhooks)
+}
+
+func (d *decorated) GetWebhookById(id int64) (types.Webhook, error) {
+	return call(d, "GetWebhookById", func() (types.Webhook, error) { return d.inner.(WebhookStore).GetWebhookById(id) }, id)
+}
+
+func (d *decorated) DeleteWebhookById(id int64) error {
+	return d.intercept("DeleteWebhookById", []any{id}, func() error { return d.inner.(WebhookStore).DeleteWebhookById(id) })
+}
+
+func (d *decorated) CreateWebhookDelivery(delivery types.WebhookDelivery) (int64, error) {
+	return call(d, "CreateWebhookDelivery", func() (int64, error) { return d.inner.(WebhookStore).CreateWebhookDelivery(delivery) }, delivery)
+}
+
+func (d *decorated) GetWebhookDeliveryById(id int64) (types.WebhookDelivery, error) {
+	return call(d, "GetWebhookDeliveryById", func() (types.WebhookDelivery, error) { return d.inner.(WebhookStore).GetWebhookDeliveryById(id) }, id)
+}
+
+func (d *decorated) UpdateWebhookDelivery(delivery types.WebhookDelivery) error {
+	return d.intercept("UpdateWebhookDelivery", []any{delivery}, func() error { return d.inner.(WebhookStore).UpdateWebhookDelivery(delivery) })
+}
+
+func (d *decorated) GetWebhookDeliveries(webhookID int64, limit int) ([]types.WebhookDelivery, error) {
+	return call(d, "GetWebhookDeliveries", func() ([]types.WebhookDelivery, error) {
+		return d.inner.(WebhookStore).GetWebhookDeliveries(webhookID, limit)
+	}, webhookID, limit)
+}
+
+// APIKeyStore
+
+func (d *decorated) CreateAPIKey(key types.APIKey) (int64, error) {
+	return call(d, "CreateAPIKey", func() (int64, error) { return d.inner.(APIKeyStore).CreateAPIKey(key) }, key)
+}
+
+func (d *decorated) GetAPIKeyByHash(hash string) (types.APIKey, error) {
+	return call(d, "GetAPIKeyByHash", func() (types.APIKey, error) { return d.inner.(APIKeyStore).GetAPIKeyByHash(hash) }, hash)
+}
+
+func (d *decorated) GetAPIKeyById(id int64) (types.APIKey, error) {
+	return call(d, "GetAPIKeyById", func() (types.APIKey, error) { return d.inner.(APIKeyStore).GetAPIKeyById(id) }, id)
+}
+
+func (d *decorated) GetAPIKeys() ([]types.APIKey, error) {
+	return call(d, "GetAPIKeys", d.inner.(APIKeyStore).GetAPIKeys)
+}
+
+func (d *decorated) RevokeAPIKey(id int64) (types.APIKey, error) {
+	return call(d, "RevokeAPIKey", func() (types.APIKey, error) { return d.inner.(APIKeyStore).RevokeAPIKey(id) }, id)
+}
+
+// TokenStore
+
+func (d *decorated) CreateRefreshToken(token types.RefreshToken) (int64, error) {
+	return call(d, "CreateRefreshToken", func() (int64, error) { return d.inner.(TokenStore).CreateRefreshToken(token) }, token)
+}
+
+func (d *decorated) GetRefreshTokenByHash(hash string) (types.RefreshToken, error) {
+	return call(d, "GetRefreshTokenByHash", func() (types.RefreshToken, error) { return d.inner.(TokenStore).GetRefreshTokenByHash(hash) }, hash)
+}
+
+func (d *decorated) UseRefreshToken(id int64) (bool, error) {
+	return call(d, "UseRefreshToken", func() (bool, error) { return d.inner.(TokenStore).UseRefreshToken(id) }, id)
+}
+
+func (d *decorated) RevokeRefreshFamily(family string) error {
+	return d.intercept("RevokeRefreshFamily", []any{family}, func() error { return d.inner.(TokenStore).RevokeRefreshFamily(family) })
+}
+
+func (d *decorated) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	return d.intercept("RevokeAccessToken", []any{jti, expiresAt}, func() error { return d.inner.(TokenStore).RevokeAccessToken(jti, expiresAt) })
+}
+
+func (d *decorated) IsAccessTokenRevoked(jti string) (bool, error) {
+	return call(d, "IsAccessTokenRevoked", func() (bool, error) { return d.inner.(TokenStore).IsAccessTokenRevoked(jti) }, jti)
+}
+
+func (d *decorated) PurgeExpiredTokens(now time.Time) (int64, error) {
+	return call(d, "PurgeExpiredTokens", func() (int64, error) { return d.inner.(TokenStore).PurgeExpiredTokens(now) }, now)
+}
+
+// QuotaStore
+
+func (d *decorated) AddQuotaUsage(subject, day string, write bool) (types.QuotaUsage, error) {
+	return call(d, "AddQuotaUsage", func() (types.QuotaUsage, error) { return d.inner.(QuotaStore).AddQuotaUsage(subject, day, write) }, subject, day, write)
+}
+
+func (d *decorated) GetQuotaUsage(subject, day string) (types.QuotaUsage, error) {
+	return call(d, "GetQuotaUsage", func() (types.QuotaUsage, error) { return d.inner.(QuotaStore).GetQuotaUsage(subject, day) }, subject, day)
+}
+
+func (d *decorated) GetQuotaLimit(subject string) (limit types.QuotaLimit, ok bool, err error) {
+	err = d.intercept("GetQuotaLimit", []any{subject}, func() (err error) {
+		limit, ok, err = d.inner.(QuotaStore).GetQuotaLimit(subject)
+		return err
+	})
+	return limit, ok, err
+}
+
+func (d *decorated) GetQuotaLimits() ([]types.QuotaLimit, error) {
+	return call(d, "GetQuotaLimits", d.inner.(QuotaStore).GetQuotaLimits)
+}
+
+func (d *decorated) SetQuotaLimit(limit types.QuotaLimit) error {
+	return d.intercept("SetQuotaLimit", []any{limit}, func() error { return d.inner.(QuotaStore).SetQuotaLimit(limit) })
+}
+
+func (d *decorated) DeleteQuotaLimit(subject string) (bool, error) {
+	return call(d, "DeleteQuotaLimit", func() (bool, error) { return d.inner.(QuotaStore).DeleteQuotaLimit(subject) }, subject)
+}
+
+func (d *decorated) PurgeQuotaUsage(day string) (int64, error) {
+	return call(d, "PurgeQuotaUsage", func() (int64, error) { return d.inner.(QuotaStore).PurgeQuotaUsage(day) }, day)
+}
+
+// EncryptionStore
+
+func (d *decorated) ReencryptStudents() (int64, error) {
+	return call(d, "ReencryptStudents", d.inner.(EncryptionStore).ReencryptStudents)
+}
+
+// HealthStore
+
+func (d *decorated) Ping(ctx context.Context) error {
+	return d.intercept("Ping", nil, func() error { return d.inner.(HealthStore).Ping(ctx) })
+}
+
+// ReconnectStore
+
+func (d *decorated) Reconnect(ctx context.Context) error {
+	return d.intercept("Reconnect", nil, func() error { return d.inner.(ReconnectStore).Reconnect(ctx) })
+}
+
+// PoolStore
+
+func (d *decorated) PoolStats() map[string]types.PoolStats {
+	return d.inner.(PoolStore).PoolStats()
+}
+
+// BackupStore
+
+func (d *decorated) Backup(ctx context.Context, path string) error {
+	return d.intercept("Backup", []any{path}, func() error { return d.inner.(BackupStore).Backup(ctx, path) })
+}
+
+// SchemaStore
+
+func (d *decorated) SchemaVersion() (int, error) {
+	return call(d, "SchemaVersion", d.inner.(SchemaStore).SchemaVersion)
+}
+
+// PageStore
+
+func (d *decorated) GetStudentsAfter(afterID int64, limit int) ([]types.Student, error) {
+	return call(d, "GetStudentsAfter", func() ([]types.Student, error) { return d.inner.(PageStore).GetStudentsAfter(afterID, limit) }, afterID, limit)
+}
+
+// ReversePageStore
+
+func (d *decorated) GetStudentsBefore(beforeID int64, limit int) ([]types.Student, error) {
+	return call(d, "GetStudentsBefore", func() ([]types.Student, error) { return d.inner.(ReversePageStore).GetStudentsBefore(beforeID, limit) }, beforeID, limit)
+}
+
+// FieldStore
+
+func (d *decorated) GetStudentByIdFields(id int64, fields []string) (types.Student, error) {
+	return call(d, "GetStudentByIdFields", func() (types.Student, error) { return d.inner.(FieldStore).GetStudentByIdFields(id, fields) }, id, fields)
+}
+
+func (d *decorated) GetStudentsFields(fields []string) ([]types.Student, error) {
+	return call(d, "GetStudentsFields", func() ([]types.Student, error) { return d.inner.(FieldStore).GetStudentsFields(fields) }, fields)
+}
+
+func (d *decorated) GetStudentsAfterFields(afterID int64, limit int, fields []string) ([]types.Student, error) {
+	return call(d, "GetStudentsAfterFields", func() ([]types.Student, error) {
+		return d.inner.(FieldStore).GetStudentsAfterFields(afterID, limit, fields)
+	}, afterID, limit, fields)
+}
+
+// BatchStore
+
+func (d *decorated) GetStudentsByIds(ids []int64) ([]types.Student, error) {
+	return call(d, "GetStudentsByIds", func() ([]types.Student, error) { return d.inner.(BatchStore).GetStudentsByIds(ids) }, ids)
+}
+
+// CustomFieldStore
+
+func (d *decorated) CreateCustomField(field types.CustomField) error {
+	return d.intercept("CreateCustomField", []any{field}, func() error { return d.inner.(CustomFieldStore).CreateCustomField(field) })
+}
+
+func (d *decorated) GetCustomFields() ([]types.CustomField, error) {
+	return call(d, "GetCustomFields", d.inner.(CustomFieldStore).GetCustomFields)
+}
+
+func (d *decorated) DeleteCustomField(key string) error {
+	return d.intercept("DeleteCustomField", []any{key}, func() error { return d.inner.(CustomFieldStore).DeleteCustomField(key) })
+}
+
+// CustomFilterStore
+
+func (d *decorated) GetStudentsWhere(filter map[string]any, afterID int64, limit int) ([]types.Student, error) {
+	return call(d, "GetStudentsWhere", func() ([]types.Student, error) {
+		return d.inner.(CustomFilterStore).GetStudentsWhere(filter, afterID, limit)
+	}, filter, afterID, limit)
+}
+
+// StatsStore
+
+func (d *decorated) GetStats(loc *time.Location) (types.Stats, error) {
+	return call(d, "GetStats", func() (types.Stats, error) { return d.inner.(StatsStore).GetStats(loc) }, loc)
+}
+
+// BulkStore
+
+func (d *decorated) UpdateStudents(ids []int64, change func(types.Student) (types.Student, error)) ([]types.BulkResult, error) {
+	return d.inner.(BulkStore).UpdateStudents(ids, change)
+}
+
+func (d *decorated) DeleteStudents(ids []int64) ([]types.BulkResult, error) {
+	return call(d, "DeleteStudents", func() ([]types.BulkResult, error) { return d.inner.(BulkStore).DeleteStudents(ids) }, ids)
+}
+
+// UpsertStore
+
+func (d *decorated) UpsertStudentByEmail(student types.Student) (stored types.Student, created bool, err error) {
+	err = d.intercept("UpsertStudentByEmail", []any{student}, func() (err error) {
+		stored, created, err = d.inner.(UpsertStore).UpsertStudentByEmail(student)
+		return err
+	})
+	return stored, created, err
+}
+
+// UniquenessStore
+
+func (d *decorated) EmailTaken(email string, exceptID int64) (bool, error) {
+	return call(d, "EmailTaken", func() (bool, error) { return d.inner.(UniquenessStore).EmailTaken(email, exceptID) }, email, exceptID)
+}
+
+// PrivacyStore
+
+func (d *decorated) GetStudentDeliveries(studentID int64) ([]types.WebhookDelivery, error) {
+	return call(d, "GetStudentDeliveries", func() ([]types.WebhookDelivery, error) { return d.inner.(PrivacyStore).GetStudentDeliveries(studentID) }, studentID)
+}
+
+func (d *decorated) EraseStudent(id int64, entry types.AuditEntry) ([]types.Document, error) {
+	return call(d, "EraseStudent", func() ([]types.Document, error) { return d.inner.(PrivacyStore).EraseStudent(id, entry) }, id, entry)
+}
+
+func (d *decorated) CreateAuditEntry(entry types.AuditEntry) (int64, error) {
+	return call(d, "CreateAuditEntry", func() (int64, error) { return d.inner.(PrivacyStore).CreateAuditEntry(entry) }, entry)
+}
+
+func (d *decorated) GetAuditEntries(studentID int64) ([]types.AuditEntry, error) {
+	return call(d, "GetAuditEntries", func() ([]types.AuditEntry, error) { return d.inner.(PrivacyStore).GetAuditEntries(studentID) }, studentID)
+}
+
+func (d *decorated) PurgeAuditEntriesBefore(cutoff time.Time) (int64, error) {
+	return call(d, "PurgeAuditEntriesBefore", func() (int64, error) { return d.inner.(PrivacyStore).PurgeAuditEntriesBefore(cutoff) }, cutoff)
+}
+
+// OutboxStore
+
+func (d *decorated) WithTrace(trace json.RawMessage) Storage {
+	return Decorate(d.inner.(OutboxStore).WithTrace(trace), d.intercept)
+}
+
+func (d *decorated) ClaimOutboxEvents(now time.Time, lease time.Duration, limit int) ([]types.OutboxEvent, error) {
+	return call(d, "ClaimOutboxEvents", func() ([]types.OutboxEvent, error) { return d.inner.(OutboxStore).ClaimOutboxEvents(now, lease, limit) }, now, lease, limit)
+}
+
+func (d *decorated) DeleteOutboxEvent(id int64) error {
+	return d.intercept("DeleteOutboxEvent", []any{id}, func() error { return d.inner.(OutboxStore).DeleteOutboxEvent(id) })
+}
+
+// VerificationStore
+
+func (d *decorated) CreateVerificationToken(token types.VerificationToken) (int64, error) {
+	return call(d, "CreateVerificationToken", func() (int64, error) { return d.inner.(VerificationStore).CreateVerificationToken(token) }, token)
+}
+
+func (d *decorated) VerifyStudent(hash string, now time.Time) (types.VerificationToken, error) {
+	return call(d, "VerifyStudent", func() (types.VerificationToken, error) { return d.inner.(VerificationStore).VerifyStudent(hash, now) }, hash, now)
+}
+
+func (d *decorated) GetStudentsByVerified(verified bool, afterID int64, limit int) ([]types.Student, error) {
+	return call(d, "GetStudentsByVerified", func() ([]types.Student, error) {
+		return d.inner.(VerificationStore).GetStudentsByVerified(verified, afterID, limit)
+	}, verified, afterID, limit)
+}
+
+// PortalStore
+
+func (d *decorated) SetStudentPassword(studentID int64, hash string) error {
+	return d.intercept("SetStudentPassword", []any{studentID, hash}, func() error { return d.inner.(PortalStore).SetStudentPassword(studentID, hash) })
+}
+
+func (d *decorated) DeleteStudentPassword(studentID int64) (bool, error) {
+	return call(d, "DeleteStudentPassword", func() (bool, error) { return d.inner.(PortalStore).DeleteStudentPassword(studentID) }, studentID)
+}
+
+func (d *decorated) GetStudentCredential(email string) (types.StudentCredential, error) {
+	return call(d, "GetStudentCredential", func() (types.StudentCredential, error) { return d.inner.(PortalStore).GetStudentCredential(email) }, email)
+}
+
+func (d *decorated) SetLoginFailures(studentID int64, failures int, lockedUntil *time.Time) error {
+	return d.intercept("SetLoginFailures", []any{studentID, failures, lockedUntil}, func() error { return d.inner.(PortalStore).SetLoginFailures(studentID, failures, lockedUntil) })
+}
+
+func (d *decorated) CreatePortalSession(session types.PortalSession) (int64, error) {
+	return call(d, "CreatePortalSession", func() (int64, error) { return d.inner.(PortalStore).CreatePortalSession(session) }, session)
+}
+
+func (d *decorated) GetPortalSession(hash string, now time.Time) (types.PortalSession, error) {
+	return call(d, "GetPortalSession", func() (types.PortalSession, error) { return d.inner.(PortalStore).GetPortalSession(hash, now) }, hash, now)
+}
+
+func (d *decorated) DeletePortalSession(hash string) (bool, error) {
+	return call(d, "DeletePortalSession", func() (bool, error) { return d.inner.(PortalStore).DeletePortalSession(hash) }, hash)
+}
+
+// PublicIDStore
+
+func (d *decorated) GetStudentIdByPublicId(publicID string) (int64, error) {
+	return call(d, "GetStudentIdByPublicId", func() (int64, error) { return d.inner.(PublicIDStore).GetStudentIdByPublicId(publicID) }, publicID)
+}
+
+// ExplainStore
+
+func (d *decorated) ExplainStudents(q StudentQuery) (types.QueryPlan, error) {
+	return call(d, "ExplainStudents", func() (types.QueryPlan, error) { return d.inner.(ExplainStore).ExplainStudents(q) }, q)
+}
+
+// IndexStore
+
+func (d *decorated) CheckIndexes() (types.IndexReport, error) {
+	return call(d, "CheckIndexes", d.inner.(IndexStore).CheckIndexes)
+}
+
+// CountStore
+
+func (d *decorated) CountStudents(q StudentQuery) (int64, error) {
+	return call(d, "CountStudents", func() (int64, error) { return d.inner.(CountStore).CountStudents(q) }, q)
+}
+
+// ReportStore
+
+func (d *decorated) AgeDistribution() (types.AgeDistribution, error) {
+	return call(d, "AgeDistribution", d.inner.(ReportStore).AgeDistribution)
+}
+
+func (d *decorated) Birthdays(month int) (types.BirthdayReport, error) {
+	return call(d, "Birthdays", func() (types.BirthdayReport, error) { return d.inner.(ReportStore).Birthdays(month) }, month)
+}
+
+// EmailDomainStore
+
+func (d *decorated) MigrateEmailDomain(from, to string, dryRun bool, entry types.AuditEntry) (types.EmailDomainMigration, error) {
+	return call(d, "MigrateEmailDomain", func() (types.EmailDomainMigration, error) {
+		return d.inner.(EmailDomainStore).MigrateEmailDomain(from, to, dryRun, entry)
+	}, from, to, dryRun)
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// EmailDomainStore rewrites the domain of the tenant's student emails in
+// one transaction, e.g. after a school renamed its domain.
+type EmailDomainStore interface {
+	// MigrateEmailDomain moves the live students whose email is on from
+	// (compared case-insensitively, subdomains excluded) to to. A student
+	// whose new email another student (trashed ones included) holds is a
+	// conflict and left as is. Each moved student gets a student.updated
+	// event and entry as an audit entry; with dryRun nothing is written.
+	MigrateEmailDomain(from, to string, dryRun bool, entry types.AuditEntry) (types.EmailDomainMigration, error)
+}
+
+// MovedEmail is email with its domain replaced by to; ok is false when
+// email isn't on from
+func MovedEmail(email, from, to string) (string, bool) {
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 || !strings.EqualFold(email[at+1:], from) {
+		return "", false
+	}
+	return email[:at+1] + to, true
+}
+
+// -------------------------------------------------------------
+// PlanEmailDomain() → What moving students from one domain to another does
+// -------------------------------------------------------------
+// students are the candidates in id order; those not on from are skipped.
+// holder returns the id of the student holding an email (0 for none).
+// Returns the migration (not yet counted as done) and the students to
+// rewrite, carrying their new email.
+func PlanEmailDomain(from, to string, students []types.Student, holder func(email string) (int64, error)) (types.EmailDomainMigration, []types.Student, error) {
+	migration := types.EmailDomainMigration{From: from, To: to, Changes: []types.EmailDomainChange{}}
+	claimed := map[string]int64{}
+	var moved []types.Student
+
+	for _, student := range students {
+		email, ok := MovedEmail(student.Email, from, to)
+		if !ok {
+			continue
+		}
+		change := types.EmailDomainChange{ID: student.ID, Email: student.Email, NewEmail: email, Status: types.DomainMoved}
+
+		// 🔑 The new email must be free, also among this run's own moves
+		conflict, err := holder(email)
+		if err != nil {
+			return migration, nil, err
+		}
+		if conflict == 0 {
+			conflict = claimed[strings.ToLower(email)]
+		}
+		if conflict != 0 {
+			change.Status, change.ConflictID = types.DomainConflict, conflict
+			migration.Conflicts++
+		} else {
+			claimed[strings.ToLower(email)] = student.ID
+			student.Email = email
+			student.Verified = false
+			moved = append(moved, student)
+			migration.Moved++
+		}
+		migration.Matched++
+		migration.Changes = append(migration.Changes, change)
+	}
+	return migration, moved, nil
+}
+
+// EmailDomainDetail is the audit detail of a moved email: the domains,
+// not the addresses, so the log holds no contact data after an erasure
+func EmailDomainDetail(from, to string) json.RawMessage {
+	detail, _ := json.Marshal(map[string]string{"from": from, "to": to})
+	return detail
+}
@@ -0,0 +1,21 @@
+package storage
+
+import "github.com/manish-npx/go-student-api/internal/types"
+
+// ExplainStore shows how the database runs the student list queries, to
+// decide which indexes to add. Plans are tenant-scoped like the queries.
+type ExplainStore interface {
+	// ExplainStudents plans the query GET /api/students runs for q;
+	// postgres also runs it (EXPLAIN ANALYZE), so the plan has timings
+	ExplainStudents(q StudentQuery) (types.QueryPlan, error)
+}
+
+// StudentQuery is a student list as GET /api/students asks for it: live
+// students after AfterID matching Filter (custom values) or Verified,
+// ordered by id. Limit 0 lists them all.
+type StudentQuery struct {
+	Filter   map[string]any
+	Verified *bool
+	AfterID  int64
+	Limit    int
+}
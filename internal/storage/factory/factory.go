@@ -6,6 +6,7 @@ import (
 
 	"github.com/manish-npx/go-student-api/internal/config"
 	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/memory"
 	"github.com/manish-npx/go-student-api/internal/storage/postgres"
 	"github.com/manish-npx/go-student-api/internal/storage/sqlite"
 )
@@ -18,6 +19,9 @@ var factories = map[string]func(config.Config) (storage.Storage, error){
 	"postgres": func(cfg config.Config) (storage.Storage, error) {
 		return postgres.New(cfg)
 	},
+	"memory": func(cfg config.Config) (storage.Storage, error) {
+		return memory.New(), nil
+	},
 }
 
 // 🧩 Main entrypoint for selecting DB
@@ -28,7 +32,7 @@ func NewStorage(cfg config.Config) (storage.Storage, error) {
 	createFn, ok := factories[cfg.DBType]
 	if !ok {
 		return nil, fmt.Errorf(
-			"unsupported db type: %s (supported: sqlite, postgres)",
+			"unsupported db type: %s (supported: sqlite, postgres, memory)",
 			cfg.DBType,
 		)
 	}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// StudentFields are the student columns a client may pick with ?fields=
+var StudentFields = []string{"id", "public_id", "name", "email", "age", "phone", "date_of_birth", "gender", "address", "custom", "verified"}
+
+// FieldStore loads only the selected student columns (sparse fieldsets).
+// id is always loaded (cursors need it), and public_id with any sealed
+// column (their values are bound to it); other unselected fields are left
+// zero.
+type FieldStore interface {
+	GetStudentByIdFields(id int64, fields []string) (types.Student, error)
+	GetStudentsFields(fields []string) ([]types.Student, error)
+	GetStudentsAfterFields(afterID int64, limit int, fields []string) ([]types.Student, error)
+}
+
+// -------------------------------------------------------------
+// StudentSelection() → SELECT columns for fields + matching Scan targets
+// -------------------------------------------------------------
+// Only names from StudentFields are accepted, so the columns are safe to
+// splice into SQL. Columns come in StudentFields order whatever the order
+// of fields, which keeps the number of distinct (cached) queries small.
+func StudentSelection(fields []string) ([]string, func(*types.Student) []any, error) {
+	for _, field := range fields {
+		if !slices.Contains(StudentFields, field) {
+			return nil, nil, fmt.Errorf("unknown student field %q", field)
+		}
+	}
+	columns := []string{"id"}
+	for _, field := range StudentFields[1:] {
+		if slices.Contains(fields, field) || field == "public_id" && sealsAny(fields) {
+			columns = append(columns, field)
+		}
+	}
+
+	dest := func(s *types.Student) []any {
+		targets := make([]any, len(columns))
+		for i, column := range columns {
+			switch column {
+			case "id":
+				targets[i] = &s.ID
+			case "public_id":
+				targets[i] = &s.PublicID
+			case "name":
+				targets[i] = &s.Name
+			case "email":
+				targets[i] = &s.Email
+			case "age":
+				targets[i] = &s.Age
+			case "phone":
+				targets[i] = &s.Phone
+			case "date_of_birth":
+				targets[i] = &s.DateOfBirth
+			case "gender":
+				targets[i] = &s.Gender
+			case "address":
+				// backends swap this for AddressField
+				targets[i] = &s.Address
+			case "custom":
+				targets[i] = CustomValues(&s.Custom)
+			case "verified":
+				targets[i] = &s.Verified
+			}
+		}
+		return targets
+	}
+	return columns, dest, nil
+}
+
+// sealsAny reports whether fields picks a column sealed for the public_id
+func sealsAny(fields []string) bool {
+	return slices.ContainsFunc(fields, func(field string) bool {
+		return field == "email" || field == "phone" || field == "address"
+	})
+}
+
+// Most IDs one batch lookup may ask for
+const MaxBatchSize = 100
+
+// BatchStore fetches many students in one query.
+type BatchStore interface {
+	// GetStudentsByIds returns the tenant's live students among ids, ordered
+	// by id; unknown ids are simply absent
+	GetStudentsByIds(ids []int64) ([]types.Student, error)
+}
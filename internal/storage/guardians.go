@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// GuardianStore keeps guardians (parents and other contacts) and their
+// links to students. Queries are tenant-scoped like student queries.
+type GuardianStore interface {
+	CreateGuardian(guardian types.Guardian) (int64, error)
+	// GetGuardians lists by name
+	GetGuardians() ([]types.Guardian, error)
+	GetGuardianById(id int64) (types.Guardian, error)
+	// UpdateGuardianById replaces name, email and phone
+	UpdateGuardianById(id int64, guardian types.Guardian) (types.Guardian, error)
+	// DeleteGuardianById also removes the guardian's links
+	DeleteGuardianById(id int64) error
+	// LinkGuardian links a guardian to a student, or changes the
+	// relationship of an existing link
+	LinkGuardian(guardianID, studentID int64, relationship string) error
+	// UnlinkGuardian returns false when they weren't linked
+	UnlinkGuardian(guardianID, studentID int64) (bool, error)
+	// GetStudentGuardians returns the guardians of each student, by name,
+	// in one query; students without guardians are left out
+	GetStudentGuardians(studentIDs []int64) (map[int64][]types.StudentGuardian, error)
+	// GetGuardianStudents lists the live students linked to a guardian, by id
+	GetGuardianStudents(guardianID int64) ([]types.GuardianLink, error)
+}
+
+// Sealed guardian columns, as bound into their values: each is sealed for
+// its column and the guardian's id
+const (
+	SealedGuardianEmail = "guardians.email"
+	SealedGuardianPhone = "guardians.phone"
+)
+
+// SealGuardian returns the email and phone of guardian id as stored: sealed
+// when c is set, empty values left empty
+func SealGuardian(c *fieldcrypt.Cipher, id int64, guardian types.Guardian) (email, phone string, err error) {
+	row := strconv.FormatInt(id, 10)
+	if guardian.Email != "" {
+		if email, err = c.Seal(SealedGuardianEmail, row, guardian.Email); err != nil {
+			return "", "", err
+		}
+	}
+	if guardian.Phone != "" {
+		if phone, err = c.Seal(SealedGuardianPhone, row, guardian.Phone); err != nil {
+			return "", "", err
+		}
+	}
+	return email, phone, nil
+}
+
+// GuardianFields are Scan targets that open the email and phone columns
+// into g; they read g.ID, so id must be selected before them
+func GuardianFields(c *fieldcrypt.Cipher, g *types.Guardian) (email, phone sql.Scanner) {
+	row := func() string { return strconv.FormatInt(g.ID, 10) }
+	return c.Field(SealedGuardianEmail, row, &g.Email), c.Field(SealedGuardianPhone, row, &g.Phone)
+}
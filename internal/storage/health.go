@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// HealthStore lets the health monitor check that the database answers.
+type HealthStore interface {
+	// Ping runs a trivial query against the primary
+	Ping(ctx context.Context) error
+}
+
+// ReconnectStore is a HealthStore that can recover a broken pool, e.g.
+// after a database failover left it holding dead connections.
+type ReconnectStore interface {
+	HealthStore
+	// Reconnect drops the pooled connections and pings over a fresh one
+	Reconnect(ctx context.Context) error
+}
+
+// PoolStore reports the connection pools of an SQL backend.
+type PoolStore interface {
+	// PoolStats is keyed "primary", then by read replica host (postgres)
+	// or "readers" (sqlite)
+	PoolStats() map[string]types.PoolStats
+}
+
+// -------------------------------------------------------------
+// PoolStatsOf() → JSON-friendly copy of db.Stats()
+// -------------------------------------------------------------
+func PoolStatsOf(db *sql.DB) types.PoolStats {
+	stats := db.Stats()
+	return types.PoolStats{
+		MaxOpen:           stats.MaxOpenConnections,
+		Open:              stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDuration:      stats.WaitDuration.String(),
+		MaxIdleClosed:     stats.MaxIdleClosed,
+		MaxIdleTimeClosed: stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed: stats.MaxLifetimeClosed,
+	}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// IndexStore checks that the indexes the student queries rely on exist,
+// whatever they are called: a UNIQUE constraint's index counts too.
+type IndexStore interface {
+	CheckIndexes() (types.IndexReport, error)
+}
+
+// WantIndex is an index some queries need. Columns are its leading key
+// columns as the backend's catalog spells them ("name COLLATE NOCASE" in
+// sqlite, "lower(name)" in postgres).
+type WantIndex struct {
+	Table   string
+	Columns []string
+	For     string
+}
+
+// -------------------------------------------------------------
+// CheckIndexes() → want, each matched against the indexes a backend has
+// -------------------------------------------------------------
+// have maps a table to its indexes' key columns by index name. An index
+// serves a want when its key starts with the wanted columns, so
+// (tenant_id, created_at) also serves (tenant_id); the shortest such key
+// is reported.
+func CheckIndexes(backend string, want []WantIndex, have map[string]map[string][]string) types.IndexReport {
+	report := types.IndexReport{Backend: backend, Indexes: []types.IndexCheck{}}
+	for _, w := range want {
+		check := types.IndexCheck{Table: w.Table, Columns: w.Columns, For: w.For, Missing: true}
+		indexes := have[w.Table]
+		for _, name := range slices.Sorted(maps.Keys(indexes)) {
+			columns := indexes[name]
+			if len(columns) < len(w.Columns) || !slices.EqualFunc(columns[:len(w.Columns)], w.Columns, sameColumn) {
+				continue
+			}
+			if check.Missing || len(columns) < len(indexes[check.Index]) {
+				check.Index, check.Missing = name, false
+			}
+		}
+		if check.Missing {
+			report.Missing++
+		}
+		report.Indexes = append(report.Indexes, check)
+	}
+	return report
+}
+
+// sameColumn compares index columns ignoring case and spacing
+func sameColumn(a, b string) bool {
+	squash := func(s string) string { return strings.ToLower(strings.Join(strings.Fields(s), "")) }
+	return squash(a) == squash(b)
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+var (
+	// ErrInvoicePaid is returned when paying an invoice already settled by
+	// another payment reference
+	ErrInvoicePaid = errors.New("invoice is already paid")
+	// ErrPaymentReferenceUsed is returned when the reference settled another invoice
+	ErrPaymentReferenceUsed = errors.New("payment reference already used")
+)
+
+// InvoiceStore keeps the fees billed to students. Queries are tenant-scoped
+// like student queries.
+type InvoiceStore interface {
+	CreateInvoice(invoice types.Invoice) (int64, error)
+	GetInvoiceById(id int64) (types.Invoice, error)
+	// GetInvoices lists a student's invoices, oldest first
+	GetInvoices(studentID int64) ([]types.Invoice, error)
+	// GetStudentInvoices returns the invoices of each student, oldest
+	// first, in one query; students without invoices are left out
+	GetStudentInvoices(studentIDs []int64) (map[int64][]types.Invoice, error)
+	// PayInvoice marks the invoice paid by reference. Paying again with the
+	// same reference changes nothing and returns the invoice, so clients can
+	// retry; another reference is ErrInvoicePaid, and a reference that paid
+	// another invoice is ErrPaymentReferenceUsed.
+	PayInvoice(id int64, reference string) (types.Invoice, error)
+	// GetOverdueInvoices lists the open invoices due before day
+	// (YYYY-MM-DD), oldest due date first
+	GetOverdueInvoices(day string) ([]types.Invoice, error)
+}
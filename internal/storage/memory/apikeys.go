@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// API keys → Hashed machine credentials, each bound to one tenant
+// -------------------------------------------------------------
+func (m *Memory) CreateAPIKey(key types.APIKey) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.apiKeys {
+		if existing.Hash == key.Hash {
+			return 0, fmt.Errorf("API key already exists")
+		}
+	}
+	m.lastAPIKeyId++
+	key.ID = m.lastAPIKeyId
+	key.Key = ""
+	key.Scopes = slices.Clone(key.Scopes)
+	if key.TenantID == 0 {
+		key.TenantID = storage.DefaultTenantID
+	}
+	key.CreatedAt = clock.Now()
+	key.RevokedAt = nil
+	m.apiKeys[key.ID] = key
+	return key.ID, nil
+}
+
+func (m *Memory) GetAPIKeyByHash(hash string) (types.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.apiKeys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return types.APIKey{}, fmt.Errorf("no API key found")
+}
+
+func (m *Memory) GetAPIKeyById(id int64) (types.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, ok := m.apiKeys[id]
+	if !ok {
+		return types.APIKey{}, fmt.Errorf("no API key found with id: %d", id)
+	}
+	return key, nil
+}
+
+func (m *Memory) GetAPIKeys() ([]types.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]types.APIKey, 0, len(m.apiKeys))
+	for _, key := range m.apiKeys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func (m *Memory) RevokeAPIKey(id int64) (types.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.apiKeys[id]
+	if !ok || key.RevokedAt != nil {
+		return types.APIKey{}, fmt.Errorf("no active API key found with id: %d", id)
+	}
+	now := time.Now().UTC()
+	key.RevokedAt = &now
+	m.apiKeys[id] = key
+	for tokenID, token := range m.refreshTokens {
+		if token.APIKeyID == id && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			m.refreshTokens[tokenID] = token
+		}
+	}
+	return key, nil
+}
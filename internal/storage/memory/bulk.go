@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// UpdateStudents() → Change many students under one lock
+// -------------------------------------------------------------
+func (m *Memory) UpdateStudents(ids []int64, change func(types.Student) (types.Student, error)) ([]types.BulkResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	done := map[int64]types.BulkResult{}
+	for _, id := range ids {
+		student, ok := m.get(id)
+		if !ok {
+			continue
+		}
+		result := storage.BulkChanged(student, change)
+		if result.Status == types.BulkUpdated {
+			rec := m.students[id]
+			rec.replace(id, *result.Student)
+			m.students[id] = rec
+			current := rec.current()
+			result.Student = &current
+			m.recordEvent(types.EventStudentUpdated, current)
+		}
+		done[id] = result
+	}
+	return storage.BulkResults(ids, done), nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudents() → Soft-delete many students under one lock
+// -------------------------------------------------------------
+func (m *Memory) DeleteStudents(ids []int64) ([]types.BulkResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	done := map[int64]types.BulkResult{}
+	for _, id := range ids {
+		rec, ok := m.students[id]
+		if !ok || !m.visible(rec) {
+			continue
+		}
+		rec.deletedAt = &now
+		m.students[id] = rec
+		done[id] = types.BulkResult{ID: id, Status: types.BulkDeleted}
+		m.recordEvent(types.EventStudentDeleted, storage.DeletedPayload(id))
+	}
+	return storage.BulkResults(ids, done), nil
+}
@@ -0,0 +1,26 @@
+package memory
+
+import "github.com/manish-npx/go-student-api/internal/storage"
+
+// -------------------------------------------------------------
+// CountStudents() → Live students matching q, without copying them out
+// -------------------------------------------------------------
+func (m *Memory) CountStudents(q storage.StudentQuery) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var n int64
+	for _, rec := range m.students {
+		if !m.visible(rec) {
+			continue
+		}
+		student := rec.current()
+		if q.Verified != nil && student.Verified != *q.Verified {
+			continue
+		}
+		if matches(student.Custom, q.Filter) {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// customFieldKey keys the definitions, like PRIMARY KEY (tenant_id, key)
+type customFieldKey struct {
+	tenantID int64
+	key      string
+}
+
+// -------------------------------------------------------------
+// CreateCustomField() → Define a custom student attribute for the tenant
+// -------------------------------------------------------------
+func (m *Memory) CreateCustomField(field types.CustomField) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := customFieldKey{m.tenantID, field.Key}
+	if _, ok := m.customFields[key]; ok {
+		return fmt.Errorf("insert custom field failed: key %s already exists", field.Key)
+	}
+	created := clock.Now()
+	field.Source = ""
+	field.CreatedAt = &created
+	m.customFields[key] = field
+	return nil
+}
+
+func (m *Memory) GetCustomFields() ([]types.CustomField, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var fields []types.CustomField
+	for key, field := range m.customFields {
+		if key.tenantID == m.tenantID {
+			fields = append(fields, field)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields, nil
+}
+
+func (m *Memory) DeleteCustomField(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := customFieldKey{m.tenantID, key}
+	if _, ok := m.customFields[k]; !ok {
+		return fmt.Errorf("no custom field found with key: %s", key)
+	}
+	delete(m.customFields, k)
+	return nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsWhere() → Live students whose custom values match filter
+// -------------------------------------------------------------
+func (m *Memory) GetStudentsWhere(filter map[string]any, afterID int64, limit int) ([]types.Student, error) {
+	students, _ := m.GetStudents()
+
+	var matched []types.Student
+	for _, student := range students {
+		if limit > 0 && len(matched) == limit {
+			break
+		}
+		if student.ID > afterID && matches(student.Custom, filter) {
+			matched = append(matched, student)
+		}
+	}
+	return matched, nil
+}
+
+// matches compares like the SQL backends: stored values are JSON-shaped
+// (string, float64, bool), as are the filter's
+func matches(custom, filter map[string]any) bool {
+	for key, want := range filter {
+		if got, ok := custom[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// asJSON gives custom values the types a JSON column hands back (numbers
+// become float64), so seeding with Go ints filters like real data does
+func asJSON(values map[string]any) map[string]any {
+	if len(values) == 0 {
+		return nil
+	}
+	encoded, err := storage.CustomJSON(values)
+	if err != nil {
+		return values
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+		return values
+	}
+	return decoded
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// MigrateEmailDomain() → Move student emails to another domain under one lock
+// -------------------------------------------------------------
+func (m *Memory) MigrateEmailDomain(from, to string, dryRun bool, entry types.AuditEntry) (types.EmailDomainMigration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var students []types.Student
+	for _, rec := range m.students {
+		if m.visible(rec) {
+			students = append(students, rec.current())
+		}
+	}
+	slices.SortFunc(students, func(a, b types.Student) int { return cmp.Compare(a.ID, b.ID) })
+
+	migration, moved, err := storage.PlanEmailDomain(from, to, students, func(email string) (int64, error) {
+		for id, rec := range m.students {
+			if rec.tenantID == m.tenantID && rec.student.Email == email {
+				return id, nil
+			}
+		}
+		return 0, nil
+	})
+	if err != nil {
+		return migration, err
+	}
+	migration.DryRun = dryRun
+	if dryRun {
+		return migration, nil
+	}
+
+	entry.Action, entry.Detail = types.AuditEmailDomainMigrated, storage.EmailDomainDetail(from, to)
+	for _, student := range moved {
+		rec := m.students[student.ID]
+		rec.replace(student.ID, student)
+		m.students[student.ID] = rec
+		m.recordEvent(types.EventStudentUpdated, rec.current())
+
+		m.lastAuditId++
+		entry.ID, entry.StudentID, entry.CreatedAt = m.lastAuditId, student.ID, clock.Now()
+		m.audit[entry.ID] = auditEntry{tenantID: m.tenantID, entry: entry}
+	}
+	return migration, nil
+}
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// guardian is a guardian row plus its owning tenant
+type guardian struct {
+	tenantID int64
+	guardian types.Guardian
+}
+
+// guardianLinkKey keys the links, like PRIMARY KEY (guardian_id, student_id);
+// the value is the relationship
+type guardianLinkKey struct {
+	guardianID int64
+	studentID  int64
+}
+
+// -------------------------------------------------------------
+// Guardians → Parents and other contacts, tenant-scoped
+// -------------------------------------------------------------
+func (m *Memory) CreateGuardian(g types.Guardian) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastGuardianId++
+	g.ID = m.lastGuardianId
+	g.CreatedAt = clock.Now()
+	g.Students = nil
+	m.guardians[g.ID] = guardian{tenantID: m.tenantID, guardian: g}
+	return g.ID, nil
+}
+
+func (m *Memory) GetGuardians() ([]types.Guardian, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []types.Guardian
+	for _, g := range m.guardians {
+		if g.tenantID == m.tenantID {
+			list = append(list, g.guardian)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return byName(list[i], list[j]) })
+	return list, nil
+}
+
+func (m *Memory) GetGuardianById(id int64) (types.Guardian, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	g, ok := m.guardians[id]
+	if !ok || g.tenantID != m.tenantID {
+		return types.Guardian{}, fmt.Errorf("no guardian found with id: %d", id)
+	}
+	return g.guardian, nil
+}
+
+func (m *Memory) UpdateGuardianById(id int64, update types.Guardian) (types.Guardian, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, ok := m.guardians[id]
+	if !ok || g.tenantID != m.tenantID {
+		return types.Guardian{}, fmt.Errorf("no guardian found with id: %d", id)
+	}
+	g.guardian.Name = update.Name
+	g.guardian.Email = update.Email
+	g.guardian.Phone = update.Phone
+	m.guardians[id] = g
+	return g.guardian, nil
+}
+
+func (m *Memory) DeleteGuardianById(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, ok := m.guardians[id]
+	if !ok || g.tenantID != m.tenantID {
+		return fmt.Errorf("no guardian found with id: %d", id)
+	}
+	delete(m.guardians, id)
+	for key := range m.guardianLinks {
+		if key.guardianID == id {
+			delete(m.guardianLinks, key)
+		}
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// LinkGuardian() → Link a guardian to a student (or change the relationship)
+// -------------------------------------------------------------
+func (m *Memory) LinkGuardian(guardianID, studentID int64, relationship string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if g, ok := m.guardians[guardianID]; !ok || g.tenantID != m.tenantID {
+		return fmt.Errorf("no guardian found with id: %d", guardianID)
+	}
+	if rec, ok := m.students[studentID]; !ok || !m.visible(rec) {
+		return fmt.Errorf("no student found with id: %d", studentID)
+	}
+	m.guardianLinks[guardianLinkKey{guardianID, studentID}] = relationship
+	return nil
+}
+
+func (m *Memory) UnlinkGuardian(guardianID, studentID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if g, ok := m.guardians[guardianID]; !ok || g.tenantID != m.tenantID {
+		return false, nil
+	}
+	key := guardianLinkKey{guardianID, studentID}
+	_, ok := m.guardianLinks[key]
+	delete(m.guardianLinks, key)
+	return ok, nil
+}
+
+func (m *Memory) GetStudentGuardians(studentIDs []int64) (map[int64][]types.StudentGuardian, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wanted := make(map[int64]bool, len(studentIDs))
+	for _, id := range studentIDs {
+		wanted[id] = true
+	}
+	byStudent := map[int64][]types.StudentGuardian{}
+	for key, relationship := range m.guardianLinks {
+		g, ok := m.guardians[key.guardianID]
+		if !wanted[key.studentID] || !ok || g.tenantID != m.tenantID {
+			continue
+		}
+		byStudent[key.studentID] = append(byStudent[key.studentID], types.StudentGuardian{Guardian: g.guardian, Relationship: relationship})
+	}
+	for _, list := range byStudent {
+		sort.Slice(list, func(i, j int) bool { return byName(list[i].Guardian, list[j].Guardian) })
+	}
+	return byStudent, nil
+}
+
+func (m *Memory) GetGuardianStudents(guardianID int64) ([]types.GuardianLink, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var links []types.GuardianLink
+	for key, relationship := range m.guardianLinks {
+		if rec, ok := m.students[key.studentID]; key.guardianID == guardianID && ok && m.visible(rec) {
+			links = append(links, types.GuardianLink{StudentID: key.studentID, Relationship: relationship})
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].StudentID < links[j].StudentID })
+	return links, nil
+}
+
+// byName orders guardians like ORDER BY name, id
+func byName(a, b types.Guardian) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// invoice is an invoice row plus its owning tenant
+type invoice struct {
+	tenantID int64
+	invoice  types.Invoice
+}
+
+// -------------------------------------------------------------
+// Invoices → Fees billed to students, tenant-scoped
+// -------------------------------------------------------------
+func (m *Memory) CreateInvoice(inv types.Invoice) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastInvoiceId++
+	inv.ID = m.lastInvoiceId
+	inv.Status = types.InvoiceOpen
+	inv.PaymentReference = ""
+	inv.PaidAt = nil
+	inv.CreatedAt = clock.Now()
+	m.invoices[inv.ID] = invoice{tenantID: m.tenantID, invoice: inv}
+	return inv.ID, nil
+}
+
+func (m *Memory) GetInvoiceById(id int64) (types.Invoice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	inv, ok := m.invoices[id]
+	if !ok || inv.tenantID != m.tenantID {
+		return types.Invoice{}, fmt.Errorf("no invoice found with id: %d", id)
+	}
+	return inv.invoice, nil
+}
+
+func (m *Memory) GetInvoices(studentID int64) ([]types.Invoice, error) {
+	return m.invoicesWhere(func(inv types.Invoice) bool { return inv.StudentID == studentID }, func(a, b types.Invoice) bool { return a.ID < b.ID })
+}
+
+func (m *Memory) GetStudentInvoices(studentIDs []int64) (map[int64][]types.Invoice, error) {
+	wanted := make(map[int64]bool, len(studentIDs))
+	for _, id := range studentIDs {
+		wanted[id] = true
+	}
+	list, err := m.invoicesWhere(func(inv types.Invoice) bool { return wanted[inv.StudentID] }, func(a, b types.Invoice) bool { return a.ID < b.ID })
+	if err != nil {
+		return nil, err
+	}
+	byStudent := map[int64][]types.Invoice{}
+	for _, inv := range list {
+		byStudent[inv.StudentID] = append(byStudent[inv.StudentID], inv)
+	}
+	return byStudent, nil
+}
+
+// -------------------------------------------------------------
+// PayInvoice() → Settle an invoice, idempotent per payment reference
+// -------------------------------------------------------------
+func (m *Memory) PayInvoice(id int64, reference string) (types.Invoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inv, ok := m.invoices[id]
+	if !ok || inv.tenantID != m.tenantID {
+		return types.Invoice{}, fmt.Errorf("no invoice found with id: %d", id)
+	}
+	if inv.invoice.Status == types.InvoicePaid {
+		if inv.invoice.PaymentReference == reference {
+			return inv.invoice, nil
+		}
+		return types.Invoice{}, fmt.Errorf("%w: invoice %d", storage.ErrInvoicePaid, id)
+	}
+	for _, other := range m.invoices {
+		if other.tenantID == m.tenantID && other.invoice.PaymentReference == reference {
+			return types.Invoice{}, fmt.Errorf("%w: %s paid invoice %d", storage.ErrPaymentReferenceUsed, reference, other.invoice.ID)
+		}
+	}
+
+	paid := clock.Now()
+	inv.invoice.Status = types.InvoicePaid
+	inv.invoice.PaymentReference = reference
+	inv.invoice.PaidAt = &paid
+	m.invoices[id] = inv
+	return inv.invoice, nil
+}
+
+func (m *Memory) GetOverdueInvoices(day string) ([]types.Invoice, error) {
+	return m.invoicesWhere(
+		func(inv types.Invoice) bool { return inv.Status == types.InvoiceOpen && inv.DueDate < day },
+		func(a, b types.Invoice) bool { return a.DueDate < b.DueDate || (a.DueDate == b.DueDate && a.ID < b.ID) },
+	)
+}
+
+// invoicesWhere lists the tenant's invoices matching keep, sorted by less
+func (m *Memory) invoicesWhere(keep func(types.Invoice) bool, less func(a, b types.Invoice) bool) ([]types.Invoice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []types.Invoice
+	for _, inv := range m.invoices {
+		if inv.tenantID == m.tenantID && keep(inv.invoice) {
+			list = append(list, inv.invoice)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return less(list[i], list[j]) })
+	return list, nil
+}
@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// Jobs → Background job queue (not tenant-scoped)
+// -------------------------------------------------------------
+func (m *Memory) EnqueueJob(job types.Job) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	m.lastJobId++
+	job.ID = m.lastJobId
+	job.Status = types.JobQueued
+	job.CreatedAt, job.UpdatedAt = now, now
+	m.jobs[job.ID] = job
+	return job.ID, nil
+}
+
+func (m *Memory) ClaimJob(now time.Time) (types.Job, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var next *types.Job
+	for _, job := range m.jobs {
+		if job.Status != types.JobQueued || job.RunAt.After(now) {
+			continue
+		}
+		if next == nil || job.RunAt.Before(next.RunAt) || (job.RunAt.Equal(next.RunAt) && job.ID < next.ID) {
+			next = &job
+		}
+	}
+	if next == nil {
+		return types.Job{}, false, nil
+	}
+
+	next.Status = types.JobRunning
+	next.Attempts++
+	next.UpdatedAt = now.UTC()
+	m.jobs[next.ID] = *next
+	return *next, true, nil
+}
+
+func (m *Memory) CompleteJob(id int64) error {
+	return m.updateJob(id, func(job *types.Job, now time.Time) {
+		job.Status = types.JobSucceeded
+		job.LastError = ""
+		job.FinishedAt = &now
+	})
+}
+
+func (m *Memory) FailJob(id int64, lastError string, retryAt *time.Time) error {
+	return m.updateJob(id, func(job *types.Job, now time.Time) {
+		job.LastError = lastError
+		if retryAt != nil {
+			job.Status = types.JobQueued
+			job.RunAt = *retryAt
+			return
+		}
+		job.Status = types.JobFailed
+		job.FinishedAt = &now
+	})
+}
+
+func (m *Memory) RequeueJob(id int64) (types.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok || job.Status != types.JobFailed {
+		return types.Job{}, fmt.Errorf("no failed job found with id: %d", id)
+	}
+	now := time.Now().UTC()
+	job.Status = types.JobQueued
+	job.Attempts = 0
+	job.RunAt, job.UpdatedAt = now, now
+	job.FinishedAt = nil
+	m.jobs[id] = job
+	return job, nil
+}
+
+func (m *Memory) ResetRunningJobs() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var reset int64
+	for id, job := range m.jobs {
+		if job.Status == types.JobRunning {
+			job.Status = types.JobQueued
+			m.jobs[id] = job
+			reset++
+		}
+	}
+	return reset, nil
+}
+
+func (m *Memory) PurgeFinishedJobs(cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for id, job := range m.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(m.jobs, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *Memory) GetJobById(id int64) (types.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return types.Job{}, fmt.Errorf("no job found with id: %d", id)
+	}
+	return job, nil
+}
+
+func (m *Memory) GetJobs(status string, limit int) ([]types.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var jobs []types.Job
+	for _, job := range m.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID > jobs[j].ID })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// updateJob applies fn to a job under the write lock
+func (m *Memory) updateJob(id int64, fn func(job *types.Job, now time.Time)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return fmt.Errorf("no job found with id: %d", id)
+	}
+	now := time.Now().UTC()
+	fn(&job, now)
+	job.UpdatedAt = now
+	m.jobs[id] = job
+	return nil
+}
@@ -0,0 +1,601 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/ulid"
+)
+
+// state is shared by every tenant view of the same Memory store.
+type state struct {
+	mu           sync.RWMutex
+	lastId       int64
+	students     map[int64]record
+	lastTenantId int64
+	tenants      map[int64]types.Tenant
+	lastDocId    int64
+	documents    map[int64]document
+	lastJobId    int64
+	jobs         map[int64]types.Job
+	// webhook subscriptions and their delivery history
+	lastWebhookId  int64
+	webhooks       map[int64]webhook
+	lastDeliveryId int64
+	deliveries     map[int64]delivery
+	lastAPIKeyId   int64
+	apiKeys        map[int64]types.APIKey
+	// refresh tokens and the access-token revocation list (jti → expiry)
+	lastRefreshId int64
+	refreshTokens map[int64]types.RefreshToken
+	revoked       map[string]time.Time
+	// daily quota counters and per-subject limit overrides
+	quotaUsage  map[quotaDay]types.QuotaUsage
+	quotaLimits map[string]types.QuotaLimit
+	// GDPR audit trail
+	lastAuditId int64
+	audit       map[int64]auditEntry
+	// custom field definitions added through the admin API
+	customFields map[customFieldKey]types.CustomField
+	// student events waiting for the relay
+	lastOutboxId int64
+	outbox       map[int64]outboxEvent
+	// fees billed to students
+	lastInvoiceId int64
+	invoices      map[int64]invoice
+	// guardians and their links to students
+	lastGuardianId int64
+	guardians      map[int64]guardian
+	guardianLinks  map[guardianLinkKey]string
+	// pending email verification tokens, one per student
+	lastVerificationId int64
+	verifications      map[int64]verification
+	// portal passwords by student id, and sessions
+	credentials         map[int64]types.StudentCredential
+	lastPortalSessionId int64
+	portalSessions      map[int64]types.PortalSession
+}
+
+// document is an attachment row plus its owning tenant
+type document struct {
+	tenantID int64
+	doc      types.Document
+}
+
+// record mirrors a row: the student plus the owning tenant
+type record struct {
+	tenantID  int64
+	createdAt time.Time
+	deletedAt *time.Time
+	student   types.Student
+}
+
+// current is the stored student with its age derived as of now
+func (rec record) current() types.Student {
+	student := rec.student
+	student.DeriveAge(time.Now())
+	student.Custom = maps.Clone(student.Custom)
+	return student
+}
+
+// stored copies student for keeping: the address must not alias the
+// caller's, and custom values take the shapes a JSON column gives back.
+// Verified and PublicID aren't taken from input: a new public id is made,
+// which replace swaps back for the record's.
+func stored(id int64, student types.Student) types.Student {
+	student.ID = id
+	student.PublicID = ulid.New()
+	student.Verified = false
+	student.DeriveAge(time.Now())
+	if student.Address != nil {
+		address := *student.Address
+		student.Address = &address
+	}
+	student.Custom = asJSON(student.Custom)
+	return student
+}
+
+// replace stores student over the record's, keeping its public id, and
+// keeping it verified unless the email changed, like the SQL backends' UPDATE
+func (rec *record) replace(id int64, student types.Student) {
+	publicID := rec.student.PublicID
+	verified := rec.student.Verified && rec.student.Email == student.Email
+	rec.student = stored(id, student)
+	rec.student.PublicID = publicID
+	rec.student.Verified = verified
+}
+
+// visible reports whether the record is live and owned by the tenant
+func (m *Memory) visible(rec record) bool {
+	return rec.tenantID == m.tenantID && rec.deletedAt == nil
+}
+
+// Memory keeps students in maps guarded by a RWMutex.
+// It is meant for tests and local demos — data is lost on restart.
+type Memory struct {
+	*state
+	// every student query is filtered by this tenant
+	tenantID int64
+	// trace goes on the outbox events this view records (see WithTrace)
+	trace json.RawMessage
+}
+
+func New() *Memory {
+	st := &state{
+		students:       make(map[int64]record),
+		documents:      make(map[int64]document),
+		invoices:       make(map[int64]invoice),
+		guardians:      make(map[int64]guardian),
+		guardianLinks:  make(map[guardianLinkKey]string),
+		verifications:  make(map[int64]verification),
+		credentials:    make(map[int64]types.StudentCredential),
+		portalSessions: make(map[int64]types.PortalSession),
+		jobs:           make(map[int64]types.Job),
+		webhooks:       make(map[int64]webhook),
+		deliveries:     make(map[int64]delivery),
+		apiKeys:        make(map[int64]types.APIKey),
+		refreshTokens:  make(map[int64]types.RefreshToken),
+		revoked:        make(map[string]time.Time),
+		quotaUsage:     make(map[quotaDay]types.QuotaUsage),
+		quotaLimits:    make(map[string]types.QuotaLimit),
+		audit:          make(map[int64]auditEntry),
+		customFields:   make(map[customFieldKey]types.CustomField),
+		outbox:         make(map[int64]outboxEvent),
+		tenants:        map[int64]types.Tenant{storage.DefaultTenantID: {ID: storage.DefaultTenantID, Slug: "default", Name: "Default"}},
+		lastTenantId:   storage.DefaultTenantID,
+	}
+	return &Memory{state: st, tenantID: storage.DefaultTenantID}
+}
+
+// -------------------------------------------------------------
+// ForTenant() → Same data, queries scoped to another tenant
+// -------------------------------------------------------------
+func (m *Memory) ForTenant(tenantID int64) storage.Storage {
+	return &Memory{state: m.state, tenantID: tenantID, trace: m.trace}
+}
+
+// get returns the student only if it belongs to the current tenant
+func (m *Memory) get(id int64) (types.Student, bool) {
+	rec, ok := m.students[id]
+	if !ok || !m.visible(rec) {
+		return types.Student{}, false
+	}
+	return rec.current(), true
+}
+
+// -------------------------------------------------------------
+// emailTaken() → Mirrors the SQL backends' UNIQUE(tenant_id, email), case and all
+// -------------------------------------------------------------
+func (m *Memory) emailTaken(email string, exceptId int64) bool {
+	for id, rec := range m.students {
+		if id != exceptId && rec.tenantID == m.tenantID && rec.student.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+// -------------------------------------------------------------
+// EmailTaken() → Whether a create or update with this email would conflict
+// -------------------------------------------------------------
+func (m *Memory) EmailTaken(email string, exceptID int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.emailTaken(email, exceptID), nil
+}
+
+// -------------------------------------------------------------
+// CreateStudent → Insert record
+// -------------------------------------------------------------
+func (m *Memory) CreateStudent(student types.Student) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.emailTaken(student.Email, 0) {
+		return 0, fmt.Errorf("insert failed: email %s already exists", student.Email)
+	}
+
+	m.lastId++
+	m.students[m.lastId] = record{
+		tenantID:  m.tenantID,
+		createdAt: clock.Now(),
+		student:   stored(m.lastId, student),
+	}
+	m.recordEvent(types.EventStudentCreated, m.students[m.lastId].current())
+
+	return m.lastId, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentById → Fetch a single student by ID
+// -------------------------------------------------------------
+func (m *Memory) GetStudentById(id int64) (types.Student, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	student, ok := m.get(id)
+	if !ok {
+		return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
+	}
+	return student, nil
+}
+
+// -------------------------------------------------------------
+// GetStudents → Fetch all students ordered by id
+// -------------------------------------------------------------
+func (m *Memory) GetStudents() ([]types.Student, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var students []types.Student
+	for _, rec := range m.students {
+		if m.visible(rec) {
+			students = append(students, rec.current())
+		}
+	}
+	sort.Slice(students, func(i, j int) bool { return students[i].ID < students[j].ID })
+
+	return students, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsIter() → Call fn for every student matching filter, by id
+// -------------------------------------------------------------
+// Batched like the SQL backends, and never under the lock while fn runs.
+func (m *Memory) GetStudentsIter(ctx context.Context, filter map[string]any, fn func(types.Student) error) error {
+	return storage.IterateStudents(ctx, func(afterID int64, limit int) ([]types.Student, error) {
+		return m.GetStudentsWhere(filter, afterID, limit)
+	}, fn)
+}
+
+// -------------------------------------------------------------
+// GetStudentsAfter() → Keyset page: id > afterID, ordered by id
+// -------------------------------------------------------------
+func (m *Memory) GetStudentsAfter(afterID int64, limit int) ([]types.Student, error) {
+	students, _ := m.GetStudents()
+
+	start := sort.Search(len(students), func(i int) bool { return students[i].ID > afterID })
+	students = students[start:]
+	if len(students) > limit {
+		students = students[:limit]
+	}
+	return students, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsBefore() → Reverse keyset page: the last limit with id < beforeID
+// -------------------------------------------------------------
+func (m *Memory) GetStudentsBefore(beforeID int64, limit int) ([]types.Student, error) {
+	students, _ := m.GetStudents()
+
+	end := sort.Search(len(students), func(i int) bool { return students[i].ID >= beforeID })
+	students = students[:end]
+	if len(students) > limit {
+		students = students[len(students)-limit:]
+	}
+	return students, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsByIds() → The visible students among ids, ordered by id
+// -------------------------------------------------------------
+func (m *Memory) GetStudentsByIds(ids []int64) ([]types.Student, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var students []types.Student
+	for _, id := range ids {
+		if student, ok := m.get(id); ok && !slices.ContainsFunc(students, func(s types.Student) bool { return s.ID == id }) {
+			students = append(students, student)
+		}
+	}
+	sort.Slice(students, func(i, j int) bool { return students[i].ID < students[j].ID })
+	return students, nil
+}
+
+// -------------------------------------------------------------
+// UpdateStudentById() → Update student based on id
+// -------------------------------------------------------------
+func (m *Memory) UpdateStudentById(id int64, update types.Student) (types.Student, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.get(id); !ok {
+		return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
+	}
+	if m.emailTaken(update.Email, id) {
+		return types.Student{}, fmt.Errorf("failed to update student: %w: %s", storage.ErrEmailTaken, update.Email)
+	}
+
+	rec := m.students[id]
+	rec.replace(id, update)
+	m.students[id] = rec
+	m.recordEvent(types.EventStudentUpdated, rec.current())
+
+	return rec.current(), nil
+}
+
+// -------------------------------------------------------------
+// UpsertStudentByEmail() → Insert, or update the student with this email
+// -------------------------------------------------------------
+func (m *Memory) UpsertStudentByEmail(upsert types.Student) (types.Student, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, rec := range m.students {
+		if rec.tenantID != m.tenantID || rec.student.Email != upsert.Email {
+			continue
+		}
+		if rec.deletedAt != nil {
+			return types.Student{}, false, storage.ErrStudentTrashed
+		}
+		rec.replace(id, upsert)
+		m.students[id] = rec
+		m.recordEvent(types.EventStudentUpdated, rec.current())
+		return rec.current(), false, nil
+	}
+
+	m.lastId++
+	rec := record{tenantID: m.tenantID, createdAt: clock.Now(), student: stored(m.lastId, upsert)}
+	m.students[m.lastId] = rec
+	m.recordEvent(types.EventStudentCreated, rec.current())
+	return rec.current(), true, nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudentById() → Soft delete (sets deletedAt)
+// -------------------------------------------------------------
+func (m *Memory) DeleteStudentById(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.students[id]
+	if !ok || !m.visible(rec) {
+		return fmt.Errorf("no student found with id: %d", id)
+	}
+	now := time.Now().UTC()
+	rec.deletedAt = &now
+	m.students[id] = rec
+	m.recordEvent(types.EventStudentDeleted, storage.DeletedPayload(id))
+
+	return nil
+}
+
+// -------------------------------------------------------------
+// GetDeletedStudents() → Soft-deleted students (the trash)
+// -------------------------------------------------------------
+func (m *Memory) GetDeletedStudents() ([]types.Student, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var students []types.Student
+	for _, rec := range m.students {
+		if rec.tenantID == m.tenantID && rec.deletedAt != nil {
+			students = append(students, rec.current())
+		}
+	}
+	sort.Slice(students, func(i, j int) bool { return students[i].ID < students[j].ID })
+
+	return students, nil
+}
+
+// -------------------------------------------------------------
+// RestoreStudentById() → Undo a soft delete
+// -------------------------------------------------------------
+func (m *Memory) RestoreStudentById(id int64) (types.Student, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.students[id]
+	if !ok || rec.tenantID != m.tenantID || rec.deletedAt == nil {
+		return types.Student{}, fmt.Errorf("no deleted student found with id: %d", id)
+	}
+	rec.deletedAt = nil
+	m.students[id] = rec
+	m.recordEvent(types.EventStudentRestored, rec.current())
+
+	return rec.current(), nil
+}
+
+// -------------------------------------------------------------
+// PurgeDeletedBefore() → Hard delete old trash (all tenants)
+// -------------------------------------------------------------
+func (m *Memory) PurgeDeletedBefore(cutoff time.Time) (storage.Purged, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged storage.Purged
+	for id, rec := range m.students {
+		if rec.deletedAt != nil && rec.deletedAt.Before(cutoff) {
+			delete(m.students, id)
+			purged.StudentIDs = append(purged.StudentIDs, id)
+		}
+	}
+	slices.Sort(purged.StudentIDs)
+	for id, d := range m.documents {
+		if _, ok := m.students[d.doc.StudentID]; !ok {
+			delete(m.documents, id)
+			purged.DocumentKeys = append(purged.DocumentKeys, d.doc.BlobKey)
+		}
+	}
+	for id, inv := range m.invoices {
+		if _, ok := m.students[inv.invoice.StudentID]; !ok {
+			delete(m.invoices, id)
+		}
+	}
+	for key := range m.guardianLinks {
+		if _, ok := m.students[key.studentID]; !ok {
+			delete(m.guardianLinks, key)
+		}
+	}
+	for id, v := range m.verifications {
+		if _, ok := m.students[v.token.StudentID]; !ok {
+			delete(m.verifications, id)
+		}
+	}
+	for studentID := range m.credentials {
+		if _, ok := m.students[studentID]; !ok {
+			delete(m.credentials, studentID)
+		}
+	}
+	for id, session := range m.portalSessions {
+		if _, ok := m.students[session.StudentID]; !ok {
+			delete(m.portalSessions, id)
+		}
+	}
+	return purged, nil
+}
+
+// -------------------------------------------------------------
+// Documents → Attachment records, tenant-scoped
+// -------------------------------------------------------------
+func (m *Memory) CreateDocument(doc types.Document) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastDocId++
+	doc.ID = m.lastDocId
+	doc.CreatedAt = clock.Now()
+	m.documents[doc.ID] = document{tenantID: m.tenantID, doc: doc}
+	return doc.ID, nil
+}
+
+func (m *Memory) GetDocuments(studentID int64) ([]types.Document, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var docs []types.Document
+	for _, d := range m.documents {
+		if d.tenantID == m.tenantID && d.doc.StudentID == studentID {
+			docs = append(docs, d.doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs, nil
+}
+
+func (m *Memory) GetStudentDocuments(studentIDs []int64) (map[int64][]types.Document, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wanted := make(map[int64]bool, len(studentIDs))
+	for _, id := range studentIDs {
+		wanted[id] = true
+	}
+	byStudent := map[int64][]types.Document{}
+	for _, d := range m.documents {
+		if d.tenantID == m.tenantID && wanted[d.doc.StudentID] {
+			byStudent[d.doc.StudentID] = append(byStudent[d.doc.StudentID], d.doc)
+		}
+	}
+	for _, docs := range byStudent {
+		sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	}
+	return byStudent, nil
+}
+
+func (m *Memory) GetDocumentById(studentID int64, id int64) (types.Document, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	d, ok := m.documents[id]
+	if !ok || d.tenantID != m.tenantID || d.doc.StudentID != studentID {
+		return types.Document{}, fmt.Errorf("no document found with id: %d", id)
+	}
+	return d.doc, nil
+}
+
+func (m *Memory) DeleteDocumentById(studentID int64, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.documents[id]
+	if !ok || d.tenantID != m.tenantID || d.doc.StudentID != studentID {
+		return fmt.Errorf("no document found with id: %d", id)
+	}
+	delete(m.documents, id)
+	return nil
+}
+
+// -------------------------------------------------------------
+// GetStats() → Aggregates for the admin dashboard (tenant-scoped)
+// -------------------------------------------------------------
+func (m *Memory) GetStats(loc *time.Location) (types.Stats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	since := storage.StatsSince(now, loc)
+
+	var total int64
+	byAge := map[string]int64{}
+	byDay := map[string]int64{}
+	for _, rec := range m.students {
+		if !m.visible(rec) {
+			continue
+		}
+		total++
+		for _, bucket := range storage.AgeBuckets {
+			if age := rec.current().Age; age >= bucket.Min && age <= bucket.Max {
+				byAge[bucket.Label]++
+				break
+			}
+		}
+		if !rec.createdAt.Before(since) {
+			byDay[rec.createdAt.In(loc).Format(clock.DayLayout)]++
+		}
+	}
+
+	return storage.BuildStats(total, byAge, byDay, now, loc), nil
+}
+
+// -------------------------------------------------------------
+// Tenants → Not tenant-scoped; used by the tenant middleware and admin API
+// -------------------------------------------------------------
+func (m *Memory) CreateTenant(slug string, name string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tenant := range m.tenants {
+		if tenant.Slug == slug {
+			return 0, fmt.Errorf("insert tenant failed: slug %s already exists", slug)
+		}
+	}
+
+	m.lastTenantId++
+	m.tenants[m.lastTenantId] = types.Tenant{ID: m.lastTenantId, Slug: slug, Name: name}
+	return m.lastTenantId, nil
+}
+
+func (m *Memory) GetTenantBySlug(slug string) (types.Tenant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, tenant := range m.tenants {
+		if tenant.Slug == slug {
+			return tenant, nil
+		}
+	}
+	return types.Tenant{}, fmt.Errorf("no tenant found with slug: %s", slug)
+}
+
+func (m *Memory) GetTenants() ([]types.Tenant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var tenants []types.Tenant
+	for _, tenant := range m.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+	return tenants, nil
+}
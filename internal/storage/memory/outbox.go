@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// outboxEvent is an outbox row plus the relay's lease on it
+type outboxEvent struct {
+	event        types.OutboxEvent
+	claimedUntil time.Time
+}
+
+// -------------------------------------------------------------
+// WithTrace() → Same scope, recorded events carry the request's trace
+// -------------------------------------------------------------
+func (m *Memory) WithTrace(trace json.RawMessage) storage.Storage {
+	return &Memory{state: m.state, tenantID: m.tenantID, trace: trace}
+}
+
+// recordEvent adds a student event to the outbox; callers hold the write
+// lock, which makes it part of their change
+func (m *Memory) recordEvent(event string, data any) {
+	payload, _ := json.Marshal(data)
+	m.lastOutboxId++
+	m.outbox[m.lastOutboxId] = outboxEvent{event: types.OutboxEvent{
+		ID:        m.lastOutboxId,
+		TenantID:  m.tenantID,
+		Event:     event,
+		Payload:   payload,
+		Trace:     m.trace,
+		CreatedAt: clock.Now(),
+	}}
+}
+
+// -------------------------------------------------------------
+// ClaimOutboxEvents() → Lease the oldest unclaimed events (all tenants)
+// -------------------------------------------------------------
+func (m *Memory) ClaimOutboxEvents(now time.Time, lease time.Duration, limit int) ([]types.OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []types.OutboxEvent
+	for _, row := range m.outbox {
+		if row.claimedUntil.After(now) {
+			continue
+		}
+		events = append(events, row.event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	for _, event := range events {
+		m.outbox[event.ID] = outboxEvent{event: event, claimedUntil: now.Add(lease)}
+	}
+	return events, nil
+}
+
+func (m *Memory) DeleteOutboxEvent(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.outbox, id)
+	return nil
+}
+
+// scrubOutbox leaves only the id in pending events about the student
+func (m *Memory) scrubOutbox(studentID int64) {
+	for id, row := range m.outbox {
+		var data struct {
+			ID int64 `json:"id"`
+		}
+		if row.event.TenantID != m.tenantID || json.Unmarshal(row.event.Payload, &data) != nil || data.ID != studentID {
+			continue
+		}
+		row.event.Payload, _ = json.Marshal(map[string]any{"id": studentID, "erased": true})
+		m.outbox[id] = row
+	}
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// SetStudentPassword() → Store a student's password, ending their sessions
+// -------------------------------------------------------------
+func (m *Memory) SetStudentPassword(studentID int64, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.get(studentID); !ok {
+		return fmt.Errorf("no student found with id: %d", studentID)
+	}
+	m.credentials[studentID] = types.StudentCredential{
+		StudentID:    studentID,
+		TenantID:     m.tenantID,
+		PasswordHash: hash,
+		UpdatedAt:    clock.Now(),
+	}
+	m.endPortalSessions(studentID)
+	return nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudentPassword() → Remove a student's portal login and sessions
+// -------------------------------------------------------------
+func (m *Memory) DeleteStudentPassword(studentID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	credential, ok := m.credentials[studentID]
+	if !ok || credential.TenantID != m.tenantID {
+		return false, nil
+	}
+	delete(m.credentials, studentID)
+	m.endPortalSessions(studentID)
+	return true, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentCredential() → Password of the live student with this email
+// -------------------------------------------------------------
+func (m *Memory) GetStudentCredential(email string) (types.StudentCredential, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for id, rec := range m.students {
+		if !m.visible(rec) || rec.student.Email != email {
+			continue
+		}
+		if credential, ok := m.credentials[id]; ok {
+			return credential, nil
+		}
+	}
+	return types.StudentCredential{}, storage.ErrNoCredential
+}
+
+// -------------------------------------------------------------
+// SetLoginFailures() → Count failed logins and lock the login out
+// -------------------------------------------------------------
+func (m *Memory) SetLoginFailures(studentID int64, failures int, lockedUntil *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	credential, ok := m.credentials[studentID]
+	if !ok || credential.TenantID != m.tenantID {
+		return nil
+	}
+	credential.FailedLogins = failures
+	credential.LockedUntil = nil
+	if lockedUntil != nil {
+		until := *lockedUntil
+		credential.LockedUntil = &until
+	}
+	m.credentials[studentID] = credential
+	return nil
+}
+
+// -------------------------------------------------------------
+// CreatePortalSession() → Store a session, dropping the student's expired ones
+// -------------------------------------------------------------
+func (m *Memory) CreatePortalSession(session types.PortalSession) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.get(session.StudentID); !ok {
+		return 0, fmt.Errorf("no student found with id: %d", session.StudentID)
+	}
+	now := time.Now().UTC()
+	for id, s := range m.portalSessions {
+		if s.StudentID == session.StudentID && !s.ExpiresAt.After(now) {
+			delete(m.portalSessions, id)
+		}
+	}
+
+	m.lastPortalSessionId++
+	session.ID = m.lastPortalSessionId
+	session.TenantID = m.tenantID
+	session.CreatedAt = now
+	m.portalSessions[session.ID] = session
+	return session.ID, nil
+}
+
+// -------------------------------------------------------------
+// GetPortalSession() → Unexpired session of a live student by token hash
+// -------------------------------------------------------------
+func (m *Memory) GetPortalSession(hash string, now time.Time) (types.PortalSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, session := range m.portalSessions {
+		if session.Hash != hash || session.TenantID != m.tenantID || !session.ExpiresAt.After(now) {
+			continue
+		}
+		if _, ok := m.get(session.StudentID); ok {
+			return session, nil
+		}
+	}
+	return types.PortalSession{}, storage.ErrNoSession
+}
+
+// -------------------------------------------------------------
+// DeletePortalSession() → Log a session out
+// -------------------------------------------------------------
+func (m *Memory) DeletePortalSession(hash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, session := range m.portalSessions {
+		if session.Hash == hash && session.TenantID == m.tenantID {
+			delete(m.portalSessions, id)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// endPortalSessions logs every session of the student out; callers hold mu
+func (m *Memory) endPortalSessions(studentID int64) {
+	for id, session := range m.portalSessions {
+		if session.StudentID == studentID {
+			delete(m.portalSessions, id)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// auditEntry is an audit log row plus its owning tenant
+type auditEntry struct {
+	tenantID int64
+	entry    types.AuditEntry
+}
+
+// emailTo is the recipient of an email job's payload
+func emailTo(payload json.RawMessage) string {
+	var msg struct{ To string }
+	json.Unmarshal(payload, &msg)
+	return msg.To
+}
+
+// aboutStudent reports whether a webhook payload's data.id is the student
+func aboutStudent(payload json.RawMessage, studentID int64) bool {
+	var envelope struct {
+		Data struct {
+			ID int64 `json:"id"`
+		} `json:"data"`
+	}
+	return json.Unmarshal(payload, &envelope) == nil && envelope.Data.ID == studentID
+}
+
+// -------------------------------------------------------------
+// GetStudentDeliveries() → Webhook deliveries whose payload is about a student
+// -------------------------------------------------------------
+func (m *Memory) GetStudentDeliveries(studentID int64) ([]types.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var deliveries []types.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.tenantID == m.tenantID && aboutStudent(d.delivery.Payload, studentID) {
+			deliveries = append(deliveries, d.delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	return deliveries, nil
+}
+
+// -------------------------------------------------------------
+// EraseStudent() → Anonymize a student and everything stored about it
+// -------------------------------------------------------------
+func (m *Memory) EraseStudent(id int64, entry types.AuditEntry) ([]types.Document, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.students[id]
+	if !ok || rec.tenantID != m.tenantID {
+		return nil, fmt.Errorf("no student found with id: %d", id)
+	}
+	now := time.Now().UTC()
+	current := rec.student.Email
+	rec.student = types.Student{ID: id, PublicID: rec.student.PublicID, Name: storage.ErasedName, Email: storage.ErasedEmail(id)}
+	if rec.deletedAt == nil {
+		rec.deletedAt = &now
+	}
+	m.students[id] = rec
+
+	var docs []types.Document
+	for docID, d := range m.documents {
+		if d.tenantID == m.tenantID && d.doc.StudentID == id {
+			docs = append(docs, d.doc)
+			delete(m.documents, docID)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	for key := range m.guardianLinks {
+		if key.studentID == id {
+			delete(m.guardianLinks, key)
+		}
+	}
+	for verificationID, v := range m.verifications {
+		if v.token.StudentID == id {
+			delete(m.verifications, verificationID)
+		}
+	}
+	delete(m.credentials, id)
+	m.endPortalSessions(id)
+	var emails int64
+	for jobID, job := range m.jobs {
+		if job.Type == storage.EmailJobType && emailTo(job.Payload) == current {
+			delete(m.jobs, jobID)
+			emails++
+		}
+	}
+
+	var scrubbed int64
+	for deliveryID, d := range m.deliveries {
+		if d.tenantID != m.tenantID || !aboutStudent(d.delivery.Payload, id) {
+			continue
+		}
+		var envelope map[string]any
+		json.Unmarshal(d.delivery.Payload, &envelope)
+		envelope["data"] = map[string]any{"id": id, "erased": true}
+		d.delivery.Payload, _ = json.Marshal(envelope)
+		m.deliveries[deliveryID] = d
+		scrubbed++
+	}
+	m.scrubOutbox(id)
+	m.recordEvent(types.EventStudentErased, storage.DeletedPayload(id))
+
+	m.lastAuditId++
+	entry.ID = m.lastAuditId
+	entry.StudentID = id
+	entry.Detail = storage.ErasureDetail(int64(len(docs)), scrubbed, emails)
+	entry.CreatedAt = now
+	m.audit[entry.ID] = auditEntry{tenantID: m.tenantID, entry: entry}
+
+	return docs, nil
+}
+
+// -------------------------------------------------------------
+// Audit log → GDPR exports and erasures, tenant-scoped
+// -------------------------------------------------------------
+func (m *Memory) CreateAuditEntry(entry types.AuditEntry) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastAuditId++
+	entry.ID = m.lastAuditId
+	entry.CreatedAt = clock.Now()
+	m.audit[entry.ID] = auditEntry{tenantID: m.tenantID, entry: entry}
+	return entry.ID, nil
+}
+
+func (m *Memory) GetAuditEntries(studentID int64) ([]types.AuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var entries []types.AuditEntry
+	for _, a := range m.audit {
+		if a.tenantID == m.tenantID && a.entry.StudentID == studentID {
+			entries = append(entries, a.entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+func (m *Memory) PurgeAuditEntriesBefore(cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for id, a := range m.audit {
+		if a.entry.CreatedAt.Before(cutoff) {
+			delete(m.audit, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
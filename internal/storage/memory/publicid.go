@@ -0,0 +1,18 @@
+package memory
+
+import "fmt"
+
+// -------------------------------------------------------------
+// GetStudentIdByPublicId() → id of the tenant's student with this ULID
+// -------------------------------------------------------------
+func (m *Memory) GetStudentIdByPublicId(publicID string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for id, rec := range m.students {
+		if rec.tenantID == m.tenantID && rec.student.PublicID == publicID {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("no student found with id: %s", publicID)
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// quotaDay keys the usage counters
+type quotaDay struct {
+	subject string
+	day     string
+}
+
+// -------------------------------------------------------------
+// Quotas → Daily counters and limit overrides, shared by every tenant
+// -------------------------------------------------------------
+func (m *Memory) AddQuotaUsage(subject, day string, write bool) (types.QuotaUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := quotaDay{subject, day}
+	usage := m.quotaUsage[key]
+	usage.Day = day
+	usage.Requests++
+	if write {
+		usage.Writes++
+	}
+	m.quotaUsage[key] = usage
+	return usage, nil
+}
+
+func (m *Memory) GetQuotaUsage(subject, day string) (types.QuotaUsage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	usage := m.quotaUsage[quotaDay{subject, day}]
+	usage.Day = day
+	return usage, nil
+}
+
+func (m *Memory) GetQuotaLimit(subject string) (types.QuotaLimit, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	limit, ok := m.quotaLimits[subject]
+	return limit, ok, nil
+}
+
+func (m *Memory) GetQuotaLimits() ([]types.QuotaLimit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	limits := make([]types.QuotaLimit, 0, len(m.quotaLimits))
+	for _, limit := range m.quotaLimits {
+		limits = append(limits, limit)
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Subject < limits[j].Subject })
+	return limits, nil
+}
+
+func (m *Memory) SetQuotaLimit(limit types.QuotaLimit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	limit.UpdatedAt = clock.Now()
+	m.quotaLimits[limit.Subject] = limit
+	return nil
+}
+
+func (m *Memory) DeleteQuotaLimit(subject string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.quotaLimits[subject]
+	delete(m.quotaLimits, subject)
+	return ok, nil
+}
+
+func (m *Memory) PurgeQuotaUsage(day string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for key := range m.quotaUsage {
+		if key.day < day {
+			delete(m.quotaUsage, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// AgeDistribution() → Live students per age bucket
+// -------------------------------------------------------------
+func (m *Memory) AgeDistribution() (types.AgeDistribution, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byAge := map[string]int64{}
+	for _, rec := range m.students {
+		if !m.visible(rec) {
+			continue
+		}
+		label := "other"
+		for _, bucket := range storage.AgeBuckets {
+			if age := rec.current().Age; age >= bucket.Min && age <= bucket.Max {
+				label = bucket.Label
+				break
+			}
+		}
+		byAge[label]++
+	}
+	return storage.BuildAgeDistribution(byAge), nil
+}
+
+// -------------------------------------------------------------
+// Birthdays() → Live students born in month, per day of the month
+// -------------------------------------------------------------
+func (m *Memory) Birthdays(month int) (types.BirthdayReport, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byDay := map[string]int64{}
+	for _, rec := range m.students {
+		if !m.visible(rec) {
+			continue
+		}
+		born, err := time.Parse(time.DateOnly, rec.current().DateOfBirth)
+		if err == nil && int(born.Month()) == month {
+			byDay[strconv.Itoa(born.Day())]++
+		}
+	}
+	return storage.BuildBirthdays(month, byDay)
+}
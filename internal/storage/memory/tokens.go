@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// Tokens → Refresh tokens and the revocation list, shared by every tenant
+// -------------------------------------------------------------
+func (m *Memory) CreateRefreshToken(token types.RefreshToken) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastRefreshId++
+	token.ID = m.lastRefreshId
+	token.CreatedAt = clock.Now()
+	token.UsedAt, token.RevokedAt = nil, nil
+	m.refreshTokens[token.ID] = token
+	return token.ID, nil
+}
+
+func (m *Memory) GetRefreshTokenByHash(hash string) (types.RefreshToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, token := range m.refreshTokens {
+		if token.Hash == hash {
+			return token, nil
+		}
+	}
+	return types.RefreshToken{}, fmt.Errorf("no refresh token found")
+}
+
+func (m *Memory) UseRefreshToken(id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.refreshTokens[id]
+	if !ok {
+		return false, fmt.Errorf("no refresh token found with id: %d", id)
+	}
+	if token.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	token.UsedAt = &now
+	m.refreshTokens[id] = token
+	return true, nil
+}
+
+func (m *Memory) RevokeRefreshFamily(family string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	for id, token := range m.refreshTokens {
+		if token.Family == family && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			m.refreshTokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (m *Memory) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revoked[jti] = expiresAt
+	return nil
+}
+
+func (m *Memory) IsAccessTokenRevoked(jti string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.revoked[jti]
+	return ok, nil
+}
+
+func (m *Memory) PurgeExpiredTokens(now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for id, token := range m.refreshTokens {
+		if token.ExpiresAt.Before(now) {
+			delete(m.refreshTokens, id)
+			purged++
+		}
+	}
+	for jti, expiresAt := range m.revoked {
+		if expiresAt.Before(now) {
+			delete(m.revoked, jti)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// verification is a pending token; the token carries its tenant
+type verification struct {
+	token types.VerificationToken
+}
+
+// -------------------------------------------------------------
+// CreateVerificationToken() → Store a student's token, replacing theirs
+// -------------------------------------------------------------
+func (m *Memory) CreateVerificationToken(token types.VerificationToken) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.get(token.StudentID); !ok {
+		return 0, fmt.Errorf("no student found with id: %d", token.StudentID)
+	}
+	for id, v := range m.verifications {
+		if v.token.StudentID == token.StudentID {
+			delete(m.verifications, id)
+		}
+	}
+
+	m.lastVerificationId++
+	token.ID = m.lastVerificationId
+	token.TenantID = m.tenantID
+	token.CreatedAt = clock.Now()
+	m.verifications[token.ID] = verification{token: token}
+	return token.ID, nil
+}
+
+// -------------------------------------------------------------
+// VerifyStudent() → Use up a token and mark its student verified
+// -------------------------------------------------------------
+// Whatever the tenant of this view, like the SQL backends.
+func (m *Memory) VerifyStudent(hash string, now time.Time) (types.VerificationToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, v := range m.verifications {
+		if v.token.Hash != hash {
+			continue
+		}
+		if v.token.ExpiresAt.Before(now) {
+			delete(m.verifications, id)
+			return types.VerificationToken{}, storage.ErrVerificationExpired
+		}
+		rec, ok := m.students[v.token.StudentID]
+		if !ok || rec.tenantID != v.token.TenantID || rec.deletedAt != nil {
+			// kept for when the student is restored
+			return types.VerificationToken{}, storage.ErrVerificationInvalid
+		}
+		delete(m.verifications, id)
+		rec.student.Verified = true
+		m.students[v.token.StudentID] = rec
+		return v.token, nil
+	}
+	return types.VerificationToken{}, storage.ErrVerificationInvalid
+}
+
+// -------------------------------------------------------------
+// GetStudentsByVerified() → Live students by verification status, by id
+// -------------------------------------------------------------
+func (m *Memory) GetStudentsByVerified(verified bool, afterID int64, limit int) ([]types.Student, error) {
+	students, _ := m.GetStudents()
+
+	var matched []types.Student
+	for _, student := range students {
+		if limit > 0 && len(matched) == limit {
+			break
+		}
+		if student.ID > afterID && student.Verified == verified {
+			matched = append(matched, student)
+		}
+	}
+	return matched, nil
+}
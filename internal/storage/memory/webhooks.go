@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// webhook is a subscription row plus its owning tenant
+type webhook struct {
+	tenantID int64
+	hook     types.Webhook
+}
+
+// delivery is a delivery row plus its owning tenant
+type delivery struct {
+	tenantID int64
+	delivery types.WebhookDelivery
+}
+
+// -------------------------------------------------------------
+// Webhooks → Subscriptions and delivery history, tenant-scoped
+// -------------------------------------------------------------
+func (m *Memory) CreateWebhook(hook types.Webhook) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastWebhookId++
+	hook.ID = m.lastWebhookId
+	hook.Events = slices.Clone(hook.Events)
+	hook.CreatedAt = clock.Now()
+	m.webhooks[hook.ID] = webhook{tenantID: m.tenantID, hook: hook}
+	return hook.ID, nil
+}
+
+func (m *Memory) GetWebhooks() ([]types.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var hooks []types.Webhook
+	for _, w := range m.webhooks {
+		if w.tenantID == m.tenantID {
+			hooks = append(hooks, w.hook)
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].ID < hooks[j].ID })
+	return hooks, nil
+}
+
+func (m *Memory) GetWebhookById(id int64) (types.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	w, ok := m.webhooks[id]
+	if !ok || w.tenantID != m.tenantID {
+		return types.Webhook{}, fmt.Errorf("no webhook found with id: %d", id)
+	}
+	return w.hook, nil
+}
+
+func (m *Memory) DeleteWebhookById(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, ok := m.webhooks[id]
+	if !ok || w.tenantID != m.tenantID {
+		return fmt.Errorf("no webhook found with id: %d", id)
+	}
+	delete(m.webhooks, id)
+	for deliveryID, d := range m.deliveries {
+		if d.delivery.WebhookID == id {
+			delete(m.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) CreateWebhookDelivery(d types.WebhookDelivery) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastDeliveryId++
+	d.ID = m.lastDeliveryId
+	d.CreatedAt = clock.Now()
+	m.deliveries[d.ID] = delivery{tenantID: m.tenantID, delivery: d}
+	return d.ID, nil
+}
+
+func (m *Memory) GetWebhookDeliveryById(id int64) (types.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	d, ok := m.deliveries[id]
+	if !ok || d.tenantID != m.tenantID {
+		return types.WebhookDelivery{}, fmt.Errorf("no webhook delivery found with id: %d", id)
+	}
+	return d.delivery, nil
+}
+
+func (m *Memory) UpdateWebhookDelivery(update types.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.deliveries[update.ID]
+	if !ok || d.tenantID != m.tenantID {
+		return nil
+	}
+	d.delivery.Status = update.Status
+	d.delivery.Attempts = update.Attempts
+	d.delivery.ResponseStatus = update.ResponseStatus
+	d.delivery.LastError = update.LastError
+	d.delivery.DeliveredAt = update.DeliveredAt
+	m.deliveries[update.ID] = d
+	return nil
+}
+
+func (m *Memory) GetWebhookDeliveries(webhookID int64, limit int) ([]types.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var deliveries []types.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.tenantID == m.tenantID && d.delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, d.delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,103 @@
+// Package migrate applies ordered, versioned schema migrations and records
+// them in a schema_migrations table, so SQL backends can evolve their schema
+// on startup without an external tool.
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+)
+
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+	// Backfill, when set, runs after SQL in the same transaction, for data
+	// changes SQL alone can't make (e.g. ids generated in Go)
+	Backfill func(tx *sql.Tx) error
+}
+
+// Placeholder style of the driver: "?" (sqlite) or "$" (postgres)
+type Dialect = sqlq.Dialect
+
+const (
+	Question = sqlq.Question
+	Dollar   = sqlq.Dollar
+)
+
+// -------------------------------------------------------------
+// Apply() → Run every migration newer than the recorded version
+// -------------------------------------------------------------
+// Each migration runs in its own transaction together with its bookkeeping row.
+func Apply(db *sql.DB, dialect Dialect, migrations []Migration) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	current, err := Version(db)
+	if err != nil {
+		return err
+	}
+
+	last := 0
+	for _, m := range migrations {
+		if m.Version <= last {
+			return fmt.Errorf("migrations out of order at version %d", m.Version)
+		}
+		last = m.Version
+
+		if m.Version <= current {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("migration %d: begin: %w", m.Version, err)
+		}
+		if _, err := tx.Exec(m.SQL); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if m.Backfill != nil {
+			if err := m.Backfill(tx); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) backfill failed: %w", m.Version, m.Name, err)
+			}
+		}
+		_, err = tx.Exec(
+			dialect.Bind(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`),
+			m.Version, m.Name, time.Now().UTC(),
+		)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: record version: %w", m.Version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: commit: %w", m.Version, err)
+		}
+
+		slog.Info("🗃️ Applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+
+	return nil
+}
+
+// Version returns the highest applied migration (0 when none).
+func Version(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
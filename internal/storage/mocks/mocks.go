@@ -0,0 +1,177 @@
+// Package mocks is a storage test double for handler and job tests: a
+// backend (the in-memory one by default) behind a decorator that records
+// every call and fails the ones a test scripts.
+//
+//	store := mocks.New()
+//	store.Fail("CountStudents", errors.New("disk full"))
+//	handler := student.Count(store.Storage(), custom)
+//	...
+//	store.AssertCalled(t, "CountStudents", 1)
+//
+// Every optional capability goes through the recorder too (look them up with
+// storage.As, as handlers do), and so do the views ForTenant and WithTrace
+// hand out.
+package mocks
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/memory"
+)
+
+// Call is one storage call: the Go method name, its arguments (contexts
+// and callbacks left out) and the error it returned.
+type Call struct {
+	Method string
+	Args   []any
+	Err    error
+}
+
+// Store records the calls made through Storage and fails scripted ones.
+// It is safe for concurrent use.
+type Store struct {
+	backend storage.Storage
+	// Memory is the in-memory backend under New, for seeding and
+	// inspecting data without recording; nil under Wrap
+	Memory *memory.Memory
+
+	mu    sync.Mutex
+	calls []Call
+	// next holds errors for the next calls of a method, in order
+	next map[string][]error
+	// always holds an error for every call of a method
+	always map[string]error
+}
+
+// -------------------------------------------------------------
+// New() → Store over a fresh in-memory backend
+// -------------------------------------------------------------
+func New() *Store {
+	mem := memory.New()
+	s := Wrap(mem)
+	s.Memory = mem
+	return s
+}
+
+// -------------------------------------------------------------
+// Wrap() → Store over backend, e.g. sqlite on a temp file
+// -------------------------------------------------------------
+func Wrap(backend storage.Storage) *Store {
+	s := &Store{next: map[string][]error{}, always: map[string]error{}}
+	s.backend = storage.Decorate(backend, s.intercept)
+	return s
+}
+
+// Storage is the backend to hand to the code under test
+func (s *Store) Storage() storage.Storage {
+	return s.backend
+}
+
+// FailNext makes the next calls of method return errs, one per call, in
+// order, without reaching the backend; a nil entry lets that call through.
+func (s *Store) FailNext(method string, errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next[method] = append(s.next[method], errs...)
+}
+
+// Fail makes every call of method return err until Reset; nil stops it.
+func (s *Store) Fail(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.always, method)
+		return
+	}
+	s.always[method] = err
+}
+
+// Reset forgets the recorded calls and the scripted errors
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+	s.next = map[string][]error{}
+	s.always = map[string]error{}
+}
+
+// Calls lists the recorded calls in the order made, just those of the
+// given methods when any are named
+func (s *Store) Calls(methods ...string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []Call
+	for _, c := range s.calls {
+		if len(methods) == 0 || slices.Contains(methods, c.Method) {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// AssertCalled fails t unless method was called times times
+func (s *Store) AssertCalled(t testing.TB, method string, times int) {
+	t.Helper()
+	if got := len(s.Calls(method)); got != times {
+		t.Errorf("%s called %d times, want %d (calls: %s)", method, got, times, s.methods())
+	}
+}
+
+// AssertNotCalled fails t if method was called at all, e.g. to check a
+// request was refused before it reached storage
+func (s *Store) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	s.AssertCalled(t, method, 0)
+}
+
+// AssertCalledWith fails t unless some call of method had exactly args
+// (compared with reflect.DeepEqual)
+func (s *Store) AssertCalledWith(t testing.TB, method string, args ...any) {
+	t.Helper()
+	calls := s.Calls(method)
+	for _, c := range calls {
+		if reflect.DeepEqual(c.Args, args) {
+			return
+		}
+	}
+	seen := make([]string, len(calls))
+	for i, c := range calls {
+		seen[i] = fmt.Sprintf("%v", c.Args)
+	}
+	t.Errorf("%s never called with %v (got %v)", method, args, seen)
+}
+
+// intercept records the call and fails it when scripted
+func (s *Store) intercept(method string, args []any, call func() error) error {
+	s.mu.Lock()
+	err, scripted := s.always[method]
+	if queue := s.next[method]; !scripted && len(queue) > 0 {
+		err, scripted = queue[0], queue[0] != nil
+		s.next[method] = queue[1:]
+	}
+	s.mu.Unlock()
+
+	if !scripted {
+		err = call()
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: method, Args: args, Err: err})
+	s.mu.Unlock()
+	return err
+}
+
+// methods is the recorded method names, for failure messages
+func (s *Store) methods() []string {
+	calls := s.Calls()
+	names := make([]string, len(calls))
+	for i, c := range calls {
+		names[i] = c.Method
+	}
+	return names
+}
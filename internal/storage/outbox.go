@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// OutboxStore is the transactional outbox. Every student mutation of a
+// backend implementing it records its event in the same transaction as the
+// change, so a committed change always has its event, even if the process
+// dies right after. A relay claims the events and publishes them.
+type OutboxStore interface {
+	// WithTrace returns a view whose recorded events carry trace (the
+	// httpclient.Trace of the request making the change, as JSON)
+	WithTrace(trace json.RawMessage) Storage
+	// ClaimOutboxEvents leases up to limit events of every tenant, oldest
+	// first, until now+lease; events whose lease ran out (the relay died or
+	// failed to publish them) are claimed again
+	ClaimOutboxEvents(now time.Time, lease time.Duration, limit int) ([]types.OutboxEvent, error)
+	// DeleteOutboxEvent drops a relayed event
+	DeleteOutboxEvent(id int64) error
+}
+
+// EventPayload encodes the data of a student event: the student itself, or
+// only its id (see DeletedPayload)
+func EventPayload(data any) (string, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("encode event payload failed: %w", err)
+	}
+	return string(payload), nil
+}
+
+// DeletedPayload is the data of student.deleted and student.erased events
+func DeletedPayload(id int64) map[string]int64 {
+	return map[string]int64{"id": id}
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Portal lookup outcomes
+var (
+	// ErrNoCredential means no live student with that email has a password
+	ErrNoCredential = errors.New("no portal login for this student")
+	// ErrNoSession means the session token is unknown, expired, logged out
+	// or its student is gone
+	ErrNoSession = errors.New("unknown or expired portal session")
+)
+
+// PortalStore keeps the passwords and sessions of the student
+// self-service portal. Every method is tenant-scoped like student queries
+// and only sees live students.
+type PortalStore interface {
+	// SetStudentPassword stores a student's password hash, replacing
+	// theirs, clearing failed logins and ending their sessions
+	SetStudentPassword(studentID int64, hash string) error
+	// DeleteStudentPassword removes a student's portal login and sessions;
+	// false when they had none
+	DeleteStudentPassword(studentID int64) (bool, error)
+	// GetStudentCredential returns the credential of the student with this
+	// email, or ErrNoCredential
+	GetStudentCredential(email string) (types.StudentCredential, error)
+	// SetLoginFailures records failed logins in a row and the lockout they
+	// caused (nil: none); 0 resets them after a good login
+	SetLoginFailures(studentID int64, failures int, lockedUntil *time.Time) error
+	// CreatePortalSession stores a new session of the student
+	CreatePortalSession(session types.PortalSession) (int64, error)
+	// GetPortalSession returns the session with this token hash that is
+	// still valid at now, or ErrNoSession
+	GetPortalSession(hash string, now time.Time) (types.PortalSession, error)
+	// DeletePortalSession ends a session (logout); false when it was unknown
+	DeletePortalSession(hash string) (bool, error)
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const apiKeyColumns = "id, name, tenant_id, prefix, key_hash, scopes, expires_at, created_at, revoked_at"
+
+// -------------------------------------------------------------
+// CreateAPIKey() → Store a key by hash, bound to key.TenantID
+// -------------------------------------------------------------
+func (p *Postgres) CreateAPIKey(key types.APIKey) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO api_keys (name, tenant_id, prefix, key_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		key.Name, key.TenantID, key.Prefix, key.Hash, strings.Join(key.Scopes, ","), key.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert API key: %w", err)
+	}
+	return id, nil
+}
+
+// Key lookups run on every request, so they stay on the primary: a
+// revoke must take effect at once, not after replication lag
+func (p *Postgres) GetAPIKeyByHash(hash string) (types.APIKey, error) {
+	key, err := scanAPIKey(p.stmts.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", hash))
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, fmt.Errorf("no API key found")
+	}
+	return key, err
+}
+
+func (p *Postgres) GetAPIKeyById(id int64) (types.APIKey, error) {
+	key, err := scanAPIKey(p.stmts.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, fmt.Errorf("no API key found with id: %d", id)
+	}
+	return key, err
+}
+
+func (p *Postgres) GetAPIKeys() ([]types.APIKey, error) {
+	rows, err := p.stmts.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []types.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// -------------------------------------------------------------
+// RevokeAPIKey() → Stamp revoked_at; the row stays for auditing
+// -------------------------------------------------------------
+// The refresh tokens of the key's sessions are revoked with it.
+func (p *Postgres) RevokeAPIKey(id int64) (types.APIKey, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return types.APIKey{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(
+		"UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL RETURNING "+apiKeyColumns,
+		id,
+	)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, fmt.Errorf("no active API key found with id: %d", id)
+	}
+	if err != nil {
+		return types.APIKey{}, err
+	}
+	if _, err := tx.Exec(`UPDATE refresh_tokens SET revoked_at = now() WHERE api_key_id = $1 AND revoked_at IS NULL`, id); err != nil {
+		return types.APIKey{}, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return types.APIKey{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return key, nil
+}
+
+// scanAPIKey reads apiKeyColumns from a *sql.Row or *sql.Rows
+func scanAPIKey(row interface{ Scan(...any) error }) (types.APIKey, error) {
+	var key types.APIKey
+	var scopes string
+	var expiresAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.Name, &key.TenantID, &key.Prefix, &key.Hash, &scopes, &expiresAt, &key.CreatedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, err
+	}
+	if err != nil {
+		return types.APIKey{}, fmt.Errorf("failed to scan API key: %w", err)
+	}
+	key.Scopes = strings.Split(scopes, ",")
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// UpdateStudents() → Read, change and rewrite many students in one transaction
+// -------------------------------------------------------------
+// The rows are locked (FOR UPDATE) so a concurrent PUT can't slip in
+// between the read and the write.
+func (p *Postgres) UpdateStudents(ids []int64, change func(types.Student) (types.Student, error)) ([]types.BulkResult, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		"SELECT "+studentSelect+" FROM students WHERE tenant_id = $1 AND deleted_at IS NULL AND id = ANY($2) ORDER BY id ASC FOR UPDATE",
+		p.tenantID, ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query students: %w", err)
+	}
+	students, err := scanStudents(rows, p.studentColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[int64]types.BulkResult, len(students))
+	for _, student := range students {
+		result := storage.BulkChanged(student, change)
+		if result.Status == types.BulkUpdated {
+			row, err := storage.SealStudent(p.crypt, *result.Student)
+			if err != nil {
+				return nil, err
+			}
+			_, err = tx.Exec(updateStudent, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, student.ID, p.tenantID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update student %d: %w", student.ID, err)
+			}
+			if err := p.recordEvent(tx, types.EventStudentUpdated, *result.Student); err != nil {
+				return nil, err
+			}
+		}
+		done[student.ID] = result
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return storage.BulkResults(ids, done), nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudents() → Soft-delete many students in one statement
+// -------------------------------------------------------------
+func (p *Postgres) DeleteStudents(ids []int64) ([]types.BulkResult, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`UPDATE students SET deleted_at = now()
+		 WHERE tenant_id = $1 AND deleted_at IS NULL AND id = ANY($2)
+		 RETURNING id`,
+		p.tenantID, ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete students: %w", err)
+	}
+
+	done := map[int64]types.BulkResult{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan student id: %w", err)
+		}
+		done[id] = types.BulkResult{ID: id, Status: types.BulkDeleted}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// events follow the order of ids, like the results
+	for _, id := range ids {
+		if _, ok := done[id]; ok {
+			if err := p.recordEvent(tx, types.EventStudentDeleted, storage.DeletedPayload(id)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return storage.BulkResults(ids, done), nil
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+)
+
+// -------------------------------------------------------------
+// CountStudents() → COUNT(*) of the students a list query selects
+// -------------------------------------------------------------
+func (p *Postgres) CountStudents(q storage.StudentQuery) (int64, error) {
+	q.AfterID = 0
+	query, err := p.studentsQuery(q)
+	if err != nil {
+		return 0, err
+	}
+	text, args := query.Count().Build()
+
+	var n int64
+	err = p.read(func(db *sqlq.Cache) error {
+		return db.QueryRow(text, args...).Scan(&n)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count students: %w", err)
+	}
+	return n, nil
+}
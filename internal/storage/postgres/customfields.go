@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// CreateCustomField() → Define a custom student attribute for the tenant
+// -------------------------------------------------------------
+func (p *Postgres) CreateCustomField(field types.CustomField) error {
+	_, err := p.stmts.Exec(
+		`INSERT INTO custom_fields (tenant_id, key, type, required) VALUES ($1, $2, $3, $4)`,
+		p.tenantID, field.Key, field.Type, field.Required,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert custom field: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetCustomFields() ([]types.CustomField, error) {
+	rows, err := p.stmts.Query(
+		"SELECT key, type, required, created_at FROM custom_fields WHERE tenant_id = $1 ORDER BY key ASC",
+		p.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	var fields []types.CustomField
+	for rows.Next() {
+		var field types.CustomField
+		field.CreatedAt = new(time.Time)
+		if err := rows.Scan(&field.Key, &field.Type, &field.Required, field.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+func (p *Postgres) DeleteCustomField(key string) error {
+	res, err := p.stmts.Exec(`DELETE FROM custom_fields WHERE tenant_id = $1 AND key = $2`, p.tenantID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no custom field found with key: %s", key)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsWhere() → Live students whose custom values match filter
+// -------------------------------------------------------------
+// One containment test (custom @> '{"key": value, ...}') covers every
+// key and is served by the GIN index on custom.
+func (p *Postgres) GetStudentsWhere(filter map[string]any, afterID int64, limit int) ([]types.Student, error) {
+	query, err := p.studentsWhere(filter, afterID)
+	if err != nil {
+		return nil, err
+	}
+	text, args := query.Limit(limit).Build()
+	return p.readStudents(text, p.studentColumns, args...)
+}
+
+// studentsWhere selects the live students after afterID matching filter
+// (every student when it is empty), by id
+func (p *Postgres) studentsWhere(filter map[string]any, afterID int64) (*sqlq.SelectBuilder, error) {
+	query := p.liveStudents(storage.StudentFields...).Where("id > ?", afterID)
+	if len(filter) > 0 {
+		match, err := json.Marshal(filter)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("custom @> ?::jsonb", string(match))
+	}
+	return query.OrderBy("id ASC"), nil
+}
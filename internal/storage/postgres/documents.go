@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const documentColumns = "id, student_id, kind, filename, content_type, size, blob_key, created_at"
+
+// -------------------------------------------------------------
+// CreateDocument() → Insert an attachment record for the current tenant
+// -------------------------------------------------------------
+func (p *Postgres) CreateDocument(doc types.Document) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO documents (tenant_id, student_id, kind, filename, content_type, size, blob_key)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		p.tenantID, doc.StudentID, doc.Kind, doc.Filename, doc.ContentType, doc.Size, doc.BlobKey,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert document: %w", err)
+	}
+	return id, nil
+}
+
+// -------------------------------------------------------------
+// GetDocuments() → Every attachment of one student, oldest first
+// -------------------------------------------------------------
+func (p *Postgres) GetDocuments(studentID int64) ([]types.Document, error) {
+	rows, err := p.stmts.Query(
+		"SELECT "+documentColumns+" FROM documents WHERE tenant_id = $1 AND student_id = $2 ORDER BY id ASC",
+		p.tenantID, studentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []types.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+func (p *Postgres) GetStudentDocuments(studentIDs []int64) (map[int64][]types.Document, error) {
+	rows, err := p.stmts.Query(
+		"SELECT "+documentColumns+" FROM documents WHERE tenant_id = $1 AND student_id = ANY($2) ORDER BY id ASC",
+		p.tenantID, studentIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query student documents: %w", err)
+	}
+	defer rows.Close()
+
+	byStudent := map[int64][]types.Document{}
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		byStudent[doc.StudentID] = append(byStudent[doc.StudentID], doc)
+	}
+	return byStudent, rows.Err()
+}
+
+func (p *Postgres) GetDocumentById(studentID int64, id int64) (types.Document, error) {
+	row := p.stmts.QueryRow(
+		"SELECT "+documentColumns+" FROM documents WHERE id = $1 AND student_id = $2 AND tenant_id = $3",
+		id, studentID, p.tenantID,
+	)
+	doc, err := scanDocument(row)
+	if err == sql.ErrNoRows {
+		return types.Document{}, fmt.Errorf("no document found with id: %d", id)
+	}
+	return doc, err
+}
+
+func (p *Postgres) DeleteDocumentById(studentID int64, id int64) error {
+	res, err := p.stmts.Exec(
+		`DELETE FROM documents WHERE id = $1 AND student_id = $2 AND tenant_id = $3`,
+		id, studentID, p.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no document found with id: %d", id)
+	}
+	return nil
+}
+
+// scanDocument reads documentColumns from a *sql.Row or *sql.Rows
+func scanDocument(row interface{ Scan(...any) error }) (types.Document, error) {
+	var doc types.Document
+	err := row.Scan(&doc.ID, &doc.StudentID, &doc.Kind, &doc.Filename, &doc.ContentType, &doc.Size, &doc.BlobKey, &doc.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.Document{}, err
+	}
+	if err != nil {
+		return types.Document{}, fmt.Errorf("failed to scan document: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// MigrateEmailDomain() → Move student emails to another domain in one transaction
+// -------------------------------------------------------------
+// The candidate rows are locked (FOR UPDATE) so a concurrent PUT can't
+// slip in between the read and the write.
+func (p *Postgres) MigrateEmailDomain(from, to string, dryRun bool, entry types.AuditEntry) (types.EmailDomainMigration, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return types.EmailDomainMigration{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 🔎 Sealed emails can only be matched once opened; plain ones are
+	// narrowed down in SQL first
+	query := `SELECT ` + studentSelect + ` FROM students WHERE tenant_id = $1 AND deleted_at IS NULL`
+	args := []any{p.tenantID}
+	if p.crypt == nil {
+		query += ` AND email ILIKE $2`
+		args = append(args, "%@"+from)
+	}
+	rows, err := tx.Query(query+` ORDER BY id ASC FOR UPDATE`, args...)
+	if err != nil {
+		return types.EmailDomainMigration{}, fmt.Errorf("failed to query students: %w", err)
+	}
+	students, err := scanStudents(rows, p.studentColumns)
+	if err != nil {
+		return types.EmailDomainMigration{}, err
+	}
+
+	migration, moved, err := storage.PlanEmailDomain(from, to, students, func(email string) (int64, error) {
+		key, value := p.crypt.Lookup("email", email)
+		var id int64
+		err := tx.QueryRow(`SELECT id FROM students WHERE tenant_id = $1 AND `+key+` = $2`, p.tenantID, value).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return id, err
+	})
+	if err != nil {
+		return migration, fmt.Errorf("failed to check email: %w", err)
+	}
+	migration.DryRun = dryRun
+	if dryRun {
+		return migration, nil
+	}
+
+	// 💾 Rewrite, with an event and audit entry per student
+	entry.Action, entry.Detail = types.AuditEmailDomainMigrated, storage.EmailDomainDetail(from, to)
+	for _, student := range moved {
+		row, err := storage.SealStudent(p.crypt, student)
+		if err != nil {
+			return migration, err
+		}
+		if _, err := tx.Exec(updateStudent, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, student.ID, p.tenantID); err != nil {
+			return migration, fmt.Errorf("failed to update student %d: %w", student.ID, err)
+		}
+		if err := p.recordEvent(tx, types.EventStudentUpdated, student); err != nil {
+			return migration, err
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO audit_log (tenant_id, student_id, action, actor, impersonator, detail) VALUES ($1, $2, $3, $4, $5, $6::jsonb)`,
+			p.tenantID, student.ID, entry.Action, entry.Actor, entry.Impersonator, string(entry.Detail),
+		); err != nil {
+			return migration, fmt.Errorf("failed to insert audit entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return migration, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return migration, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const explainAnalyze = "EXPLAIN (ANALYZE, BUFFERS)"
+
+// -------------------------------------------------------------
+// ExplainStudents() → EXPLAIN ANALYZE of a student list query
+// -------------------------------------------------------------
+// The query really runs (on a replica when there are some, like the list
+// itself), so the plan carries actual row counts and timings. "Seq Scan
+// on students" means every row is read.
+func (p *Postgres) ExplainStudents(q storage.StudentQuery) (types.QueryPlan, error) {
+	query, err := p.studentsQuery(q)
+	if err != nil {
+		return types.QueryPlan{}, err
+	}
+	text, args := query.Limit(q.Limit).Build()
+	plan := types.QueryPlan{Backend: "postgres", Explain: explainAnalyze, Query: text}
+
+	err = p.read(func(db *sqlq.Cache) error {
+		rows, err := db.DB().Query(explainAnalyze+" "+text, args...)
+		if err != nil {
+			return fmt.Errorf("explain failed: %w", err)
+		}
+		defer rows.Close()
+
+		plan.Plan = nil
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				return fmt.Errorf("scan failed: %w", err)
+			}
+			plan.Plan = append(plan.Plan, line)
+		}
+		return rows.Err()
+	})
+	return plan, err
+}
+
+// studentsQuery is the select GET /api/students runs for q
+func (p *Postgres) studentsQuery(q storage.StudentQuery) (*sqlq.SelectBuilder, error) {
+	if q.Verified != nil {
+		return p.studentsByVerified(*q.Verified, q.AfterID), nil
+	}
+	return p.studentsWhere(q.Filter, q.AfterID)
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// liveStudents starts a SELECT over the tenant's non-deleted students;
+// age and date_of_birth are computed (see studentSelect)
+func (p *Postgres) liveStudents(columns ...string) *sqlq.SelectBuilder {
+	exprs := slices.Clone(columns)
+	for i, column := range exprs {
+		switch column {
+		case "age":
+			exprs[i] = ageColumn + " AS age"
+		case "date_of_birth":
+			exprs[i] = dateOfBirthColumn + " AS date_of_birth"
+		}
+	}
+	return sqlq.Select(sqlq.Dollar, exprs...).From("students").
+		Where("tenant_id = ?", p.tenantID).
+		Where("deleted_at IS NULL")
+}
+
+// -------------------------------------------------------------
+// GetStudentByIdFields() → GetStudentById, selecting only fields
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentByIdFields(id int64, fields []string) (types.Student, error) {
+	columns, dest, err := storage.StudentSelection(fields)
+	if err != nil {
+		return types.Student{}, err
+	}
+
+	var student types.Student
+	query, args := p.liveStudents(columns...).Where("id = ?", id).Build()
+	err = p.read(func(db *sqlq.Cache) error {
+		return db.QueryRow(query, args...).Scan(p.opening(dest)(&student)...)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
+		}
+		return types.Student{}, fmt.Errorf("failed to fetch student: %w", err)
+	}
+
+	return student, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsFields() → GetStudents, selecting only fields
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentsFields(fields []string) ([]types.Student, error) {
+	columns, dest, err := storage.StudentSelection(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := p.liveStudents(columns...).OrderBy("id ASC").Build()
+	return p.readStudents(query, p.opening(dest), args...)
+}
+
+// -------------------------------------------------------------
+// GetStudentsAfterFields() → GetStudentsAfter, selecting only fields
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentsAfterFields(afterID int64, limit int, fields []string) ([]types.Student, error) {
+	columns, dest, err := storage.StudentSelection(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := p.liveStudents(columns...).Where("id > ?", afterID).OrderBy("id ASC").Limit(limit).Build()
+	return p.readStudents(query, p.opening(dest), args...)
+}
+
+// opening swaps the sealed-column targets of a selection for ones that decrypt
+func (p *Postgres) opening(dest func(*types.Student) []any) func(*types.Student) []any {
+	return func(st *types.Student) []any {
+		targets := dest(st)
+		for i, target := range targets {
+			switch target {
+			case any(&st.Email):
+				targets[i] = storage.EmailField(p.crypt, st)
+			case any(&st.Phone):
+				targets[i] = storage.PhoneField(p.crypt, st)
+			case any(&st.Address):
+				targets[i] = storage.AddressField(p.crypt, st)
+			}
+		}
+		return targets
+	}
+}
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const guardianColumns = "g.id, g.name, g.email, g.phone, g.created_at"
+
+// -------------------------------------------------------------
+// CreateGuardian() → Insert a guardian for the current tenant
+// -------------------------------------------------------------
+// The email and phone are sealed for the new id, so they're written right
+// after the insert, in the same transaction.
+func (p *Postgres) CreateGuardian(g types.Guardian) (int64, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow(
+		`INSERT INTO guardians (tenant_id, name, email, phone) VALUES ($1, $2, '', '') RETURNING id`,
+		p.tenantID, g.Name,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert guardian: %w", err)
+	}
+	email, phone, err := storage.SealGuardian(p.crypt, id, g)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE guardians SET email = $1, phone = $2 WHERE id = $3`, email, phone, id); err != nil {
+		return 0, fmt.Errorf("failed to update guardian: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (p *Postgres) GetGuardians() ([]types.Guardian, error) {
+	rows, err := p.stmts.Query("SELECT "+guardianColumns+" FROM guardians g WHERE g.tenant_id = $1 ORDER BY g.name ASC, g.id ASC", p.tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guardians: %w", err)
+	}
+	defer rows.Close()
+
+	var list []types.Guardian
+	for rows.Next() {
+		var g types.Guardian
+		if err := rows.Scan(p.guardianFields(&g)...); err != nil {
+			return nil, fmt.Errorf("failed to scan guardian: %w", err)
+		}
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
+
+func (p *Postgres) GetGuardianById(id int64) (types.Guardian, error) {
+	var g types.Guardian
+	err := p.stmts.QueryRow("SELECT "+guardianColumns+" FROM guardians g WHERE g.id = $1 AND g.tenant_id = $2", id, p.tenantID).
+		Scan(p.guardianFields(&g)...)
+	if err == sql.ErrNoRows {
+		return types.Guardian{}, fmt.Errorf("no guardian found with id: %d", id)
+	}
+	if err != nil {
+		return types.Guardian{}, fmt.Errorf("failed to scan guardian: %w", err)
+	}
+	return g, nil
+}
+
+func (p *Postgres) UpdateGuardianById(id int64, g types.Guardian) (types.Guardian, error) {
+	email, phone, err := storage.SealGuardian(p.crypt, id, g)
+	if err != nil {
+		return types.Guardian{}, err
+	}
+	res, err := p.stmts.Exec(
+		`UPDATE guardians SET name = $1, email = $2, phone = $3 WHERE id = $4 AND tenant_id = $5`,
+		g.Name, email, phone, id, p.tenantID,
+	)
+	if err != nil {
+		return types.Guardian{}, fmt.Errorf("failed to update guardian: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return types.Guardian{}, fmt.Errorf("no guardian found with id: %d", id)
+	}
+	return p.GetGuardianById(id)
+}
+
+// DeleteGuardianById drops the guardian; ON DELETE CASCADE takes the links
+func (p *Postgres) DeleteGuardianById(id int64) error {
+	res, err := p.stmts.Exec(`DELETE FROM guardians WHERE id = $1 AND tenant_id = $2`, id, p.tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete guardian: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no guardian found with id: %d", id)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// LinkGuardian() → Link a guardian to a student (or change the relationship)
+// -------------------------------------------------------------
+// Both must belong to the current tenant, and the student be live.
+func (p *Postgres) LinkGuardian(guardianID, studentID int64, relationship string) error {
+	res, err := p.stmts.Exec(
+		`INSERT INTO guardian_students (guardian_id, student_id, relationship)
+		 SELECT g.id, st.id, $1 FROM guardians g, students st
+		 WHERE g.id = $2 AND g.tenant_id = $3 AND st.id = $4 AND st.tenant_id = $3 AND st.deleted_at IS NULL
+		 ON CONFLICT (guardian_id, student_id) DO UPDATE SET relationship = excluded.relationship`,
+		relationship, guardianID, p.tenantID, studentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to link guardian: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no guardian %d or student %d found", guardianID, studentID)
+	}
+	return nil
+}
+
+func (p *Postgres) UnlinkGuardian(guardianID, studentID int64) (bool, error) {
+	res, err := p.stmts.Exec(
+		`DELETE FROM guardian_students WHERE guardian_id = $1 AND student_id = $2
+		 AND guardian_id IN (SELECT id FROM guardians WHERE tenant_id = $3)`,
+		guardianID, studentID, p.tenantID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to unlink guardian: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (p *Postgres) GetStudentGuardians(studentIDs []int64) (map[int64][]types.StudentGuardian, error) {
+	rows, err := p.stmts.Query(
+		`SELECT gs.student_id, gs.relationship, `+guardianColumns+` FROM guardian_students gs
+		 JOIN guardians g ON g.id = gs.guardian_id
+		 WHERE g.tenant_id = $1 AND gs.student_id = ANY($2)
+		 ORDER BY g.name ASC, g.id ASC`,
+		p.tenantID, studentIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query student guardians: %w", err)
+	}
+	defer rows.Close()
+
+	byStudent := map[int64][]types.StudentGuardian{}
+	for rows.Next() {
+		var (
+			studentID int64
+			g         types.StudentGuardian
+		)
+		if err := rows.Scan(append([]any{&studentID, &g.Relationship}, p.guardianFields(&g.Guardian)...)...); err != nil {
+			return nil, fmt.Errorf("failed to scan guardian: %w", err)
+		}
+		byStudent[studentID] = append(byStudent[studentID], g)
+	}
+	return byStudent, rows.Err()
+}
+
+func (p *Postgres) GetGuardianStudents(guardianID int64) ([]types.GuardianLink, error) {
+	rows, err := p.stmts.Query(
+		`SELECT gs.student_id, gs.relationship FROM guardian_students gs
+		 JOIN students st ON st.id = gs.student_id
+		 WHERE gs.guardian_id = $1 AND st.tenant_id = $2 AND st.deleted_at IS NULL
+		 ORDER BY gs.student_id ASC`,
+		guardianID, p.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guardian students: %w", err)
+	}
+	defer rows.Close()
+
+	var links []types.GuardianLink
+	for rows.Next() {
+		var link types.GuardianLink
+		if err := rows.Scan(&link.StudentID, &link.Relationship); err != nil {
+			return nil, fmt.Errorf("failed to scan guardian link: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// guardianFields are the Scan targets of guardianColumns, opening the
+// sealed email and phone
+func (p *Postgres) guardianFields(g *types.Guardian) []any {
+	email, phone := storage.GuardianFields(p.crypt, g)
+	return []any{&g.ID, &g.Name, email, phone, &g.CreatedAt}
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/migrate"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// idleConns is database/sql's default, which New leaves in place
+const idleConns = 2
+
+// -------------------------------------------------------------
+// Ping() → Round trip to the primary
+// -------------------------------------------------------------
+func (p *Postgres) Ping(ctx context.Context) error {
+	if err := p.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping postgres: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// Reconnect() → Drop idle connections, then dial a fresh one
+// -------------------------------------------------------------
+// After a failover or restart the pool may hold connections to a server
+// that is gone. Closing them makes the next query dial again; connections
+// in use are closed by database/sql when they fail. Prepared statements
+// are prepared again on the new connections.
+func (p *Postgres) Reconnect(ctx context.Context) error {
+	p.DB.SetMaxIdleConns(0)
+	p.DB.SetMaxIdleConns(idleConns)
+	if err := p.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to reconnect to postgres: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// PoolStats() → The primary's pool plus one per read replica
+// -------------------------------------------------------------
+func (p *Postgres) PoolStats() map[string]types.PoolStats {
+	pools := map[string]types.PoolStats{"primary": storage.PoolStatsOf(p.DB)}
+	if p.replicas != nil {
+		for _, r := range p.replicas.replicas {
+			pools[r.host] = storage.PoolStatsOf(r.stmts.DB())
+		}
+	}
+	return pools
+}
+
+// -------------------------------------------------------------
+// SchemaVersion() → Last migration recorded in schema_migrations
+// -------------------------------------------------------------
+func (p *Postgres) SchemaVersion() (int, error) {
+	return migrate.Version(p.DB)
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// requiredIndexes are the indexes the student queries rely on
+var requiredIndexes = []storage.WantIndex{
+	{Table: "students", Columns: []string{"tenant_id"}, For: "every tenant-scoped student query"},
+	{Table: "students", Columns: []string{"tenant_id", "email"}, For: "email lookups and uniqueness"},
+	{Table: "students", Columns: []string{"tenant_id", "email_hash"}, For: "email lookups with field encryption"},
+	{Table: "students", Columns: []string{"tenant_id", "lower(name)"}, For: "case-insensitive name lookups"},
+	{Table: "students", Columns: []string{"tenant_id", "created_at"}, For: "stats and created_at ranges"},
+	{Table: "students", Columns: []string{"tenant_id", "verified"}, For: "?verified= lists"},
+}
+
+// -------------------------------------------------------------
+// CheckIndexes() → Which required indexes exist, from the catalog
+// -------------------------------------------------------------
+// Key columns are spelled by pg_get_indexdef, expressions included. An
+// invalid index (a failed CREATE INDEX CONCURRENTLY) doesn't count.
+func (p *Postgres) CheckIndexes() (types.IndexReport, error) {
+	have := map[string]map[string][]string{}
+	for _, want := range requiredIndexes {
+		if have[want.Table] != nil {
+			continue
+		}
+		rows, err := p.DB.Query(
+			`SELECT c.relname, pg_get_indexdef(x.indexrelid, k.n, true)
+			 FROM pg_index x
+			 JOIN pg_class c ON c.oid = x.indexrelid
+			 CROSS JOIN LATERAL generate_series(1, x.indnkeyatts) AS k(n)
+			 WHERE x.indrelid = to_regclass($1) AND x.indisvalid
+			 ORDER BY c.relname, k.n`,
+			want.Table,
+		)
+		if err != nil {
+			return types.IndexReport{}, fmt.Errorf("failed to query indexes: %w", err)
+		}
+		indexes := map[string][]string{}
+		for rows.Next() {
+			var index, column string
+			if err := rows.Scan(&index, &column); err != nil {
+				rows.Close()
+				return types.IndexReport{}, fmt.Errorf("failed to scan index: %w", err)
+			}
+			indexes[index] = append(indexes[index], column)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return types.IndexReport{}, fmt.Errorf("failed to read indexes: %w", err)
+		}
+		have[want.Table] = indexes
+	}
+	return storage.CheckIndexes("postgres", requiredIndexes, have), nil
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const invoiceColumns = "id, student_id, amount, currency, description, to_char(due_date, 'YYYY-MM-DD'), status, payment_reference, paid_at, created_at"
+
+// -------------------------------------------------------------
+// CreateInvoice() → Bill a student of the current tenant
+// -------------------------------------------------------------
+func (p *Postgres) CreateInvoice(inv types.Invoice) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO invoices (tenant_id, student_id, amount, currency, description, due_date, status)
+		 VALUES ($1, $2, $3, $4, $5, $6::date, $7) RETURNING id`,
+		p.tenantID, inv.StudentID, inv.Amount, inv.Currency, inv.Description, inv.DueDate, types.InvoiceOpen,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert invoice: %w", err)
+	}
+	return id, nil
+}
+
+func (p *Postgres) GetInvoiceById(id int64) (types.Invoice, error) {
+	row := p.stmts.QueryRow("SELECT "+invoiceColumns+" FROM invoices WHERE id = $1 AND tenant_id = $2", id, p.tenantID)
+	inv, err := scanInvoice(row)
+	if err == sql.ErrNoRows {
+		return types.Invoice{}, fmt.Errorf("no invoice found with id: %d", id)
+	}
+	return inv, err
+}
+
+func (p *Postgres) GetInvoices(studentID int64) ([]types.Invoice, error) {
+	return p.queryInvoices(
+		"SELECT "+invoiceColumns+" FROM invoices WHERE tenant_id = $1 AND student_id = $2 ORDER BY id ASC",
+		p.tenantID, studentID,
+	)
+}
+
+func (p *Postgres) GetStudentInvoices(studentIDs []int64) (map[int64][]types.Invoice, error) {
+	list, err := p.queryInvoices(
+		"SELECT "+invoiceColumns+" FROM invoices WHERE tenant_id = $1 AND student_id = ANY($2) ORDER BY id ASC",
+		p.tenantID, studentIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoicesByStudent(list), nil
+}
+
+// -------------------------------------------------------------
+// PayInvoice() → Settle an invoice, idempotent per payment reference
+// -------------------------------------------------------------
+// The invoice row is locked (FOR UPDATE) so two payments of it are
+// serialized; the unique index on payment_reference settles two invoices
+// racing for the same reference.
+func (p *Postgres) PayInvoice(id int64, reference string) (types.Invoice, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return types.Invoice{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	inv, err := scanInvoice(tx.QueryRow("SELECT "+invoiceColumns+" FROM invoices WHERE id = $1 AND tenant_id = $2 FOR UPDATE", id, p.tenantID))
+	if err == sql.ErrNoRows {
+		return types.Invoice{}, fmt.Errorf("no invoice found with id: %d", id)
+	}
+	if err != nil {
+		return types.Invoice{}, err
+	}
+	if inv.Status == types.InvoicePaid {
+		if inv.PaymentReference == reference {
+			return inv, nil
+		}
+		return types.Invoice{}, fmt.Errorf("%w: invoice %d", storage.ErrInvoicePaid, id)
+	}
+
+	var other int64
+	err = tx.QueryRow(`SELECT id FROM invoices WHERE tenant_id = $1 AND payment_reference = $2`, p.tenantID, reference).Scan(&other)
+	if err == nil {
+		return types.Invoice{}, fmt.Errorf("%w: %s paid invoice %d", storage.ErrPaymentReferenceUsed, reference, other)
+	}
+	if err != sql.ErrNoRows {
+		return types.Invoice{}, fmt.Errorf("failed to query payment reference: %w", err)
+	}
+
+	var paid time.Time
+	err = tx.QueryRow(
+		`UPDATE invoices SET status = $1, payment_reference = $2, paid_at = now() WHERE id = $3 RETURNING paid_at`,
+		types.InvoicePaid, reference, id,
+	).Scan(&paid)
+	if err != nil {
+		return types.Invoice{}, fmt.Errorf("failed to update invoice: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Invoice{}, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	inv.Status = types.InvoicePaid
+	inv.PaymentReference = reference
+	inv.PaidAt = &paid
+	return inv, nil
+}
+
+func (p *Postgres) GetOverdueInvoices(day string) ([]types.Invoice, error) {
+	return p.queryInvoices(
+		"SELECT "+invoiceColumns+" FROM invoices WHERE tenant_id = $1 AND status = $2 AND due_date < $3::date ORDER BY due_date ASC, id ASC",
+		p.tenantID, types.InvoiceOpen, day,
+	)
+}
+
+func (p *Postgres) queryInvoices(query string, args ...any) ([]types.Invoice, error) {
+	rows, err := p.stmts.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var list []types.Invoice
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, inv)
+	}
+	return list, rows.Err()
+}
+
+// invoicesByStudent groups list by student, keeping its order
+func invoicesByStudent(list []types.Invoice) map[int64][]types.Invoice {
+	byStudent := map[int64][]types.Invoice{}
+	for _, inv := range list {
+		byStudent[inv.StudentID] = append(byStudent[inv.StudentID], inv)
+	}
+	return byStudent
+}
+
+// scanInvoice reads invoiceColumns from a *sql.Row or *sql.Rows
+func scanInvoice(row interface{ Scan(...any) error }) (types.Invoice, error) {
+	var (
+		inv       types.Invoice
+		reference sql.NullString
+		paid      sql.NullTime
+	)
+	err := row.Scan(&inv.ID, &inv.StudentID, &inv.Amount, &inv.Currency, &inv.Description, &inv.DueDate,
+		&inv.Status, &reference, &paid, &inv.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.Invoice{}, err
+	}
+	if err != nil {
+		return types.Invoice{}, fmt.Errorf("failed to scan invoice: %w", err)
+	}
+	inv.PaymentReference = reference.String
+	if paid.Valid {
+		inv.PaidAt = &paid.Time
+	}
+	return inv, nil
+}
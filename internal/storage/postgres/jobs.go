@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const jobColumns = "id, type, payload, status, attempts, max_attempts, last_error, run_at, created_at, updated_at, finished_at"
+
+// -------------------------------------------------------------
+// EnqueueJob() → Insert a queued job
+// -------------------------------------------------------------
+func (p *Postgres) EnqueueJob(job types.Job) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO jobs (type, payload, status, max_attempts, run_at)
+		 VALUES ($1, $2::jsonb, $3, $4, $5) RETURNING id`,
+		job.Type, string(job.Payload), types.JobQueued, job.MaxAttempts, job.RunAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert job: %w", err)
+	}
+	return id, nil
+}
+
+// -------------------------------------------------------------
+// ClaimJob() → Oldest due job → running
+// -------------------------------------------------------------
+// SKIP LOCKED lets many workers (and instances) claim in parallel without
+// blocking on, or double-claiming, the same row.
+func (p *Postgres) ClaimJob(now time.Time) (types.Job, bool, error) {
+	row := p.stmts.QueryRow(
+		`UPDATE jobs SET status = $1, attempts = attempts + 1, updated_at = now()
+		 WHERE id = (
+			SELECT id FROM jobs WHERE status = $2 AND run_at <= $3
+			ORDER BY run_at, id LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING `+jobColumns,
+		types.JobRunning, types.JobQueued, now,
+	)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return types.Job{}, false, nil
+	}
+	if err != nil {
+		return types.Job{}, false, fmt.Errorf("failed to claim job: %w", err)
+	}
+	return job, true, nil
+}
+
+func (p *Postgres) CompleteJob(id int64) error {
+	_, err := p.stmts.Exec(
+		`UPDATE jobs SET status = $1, last_error = '', updated_at = now(), finished_at = now() WHERE id = $2`,
+		types.JobSucceeded, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) FailJob(id int64, lastError string, retryAt *time.Time) error {
+	var err error
+	if retryAt != nil {
+		_, err = p.stmts.Exec(
+			`UPDATE jobs SET status = $1, last_error = $2, run_at = $3, updated_at = now() WHERE id = $4`,
+			types.JobQueued, lastError, *retryAt, id,
+		)
+	} else {
+		_, err = p.stmts.Exec(
+			`UPDATE jobs SET status = $1, last_error = $2, updated_at = now(), finished_at = now() WHERE id = $3`,
+			types.JobFailed, lastError, id,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fail job: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) RequeueJob(id int64) (types.Job, error) {
+	row := p.stmts.QueryRow(
+		`UPDATE jobs SET status = $1, attempts = 0, run_at = now(), updated_at = now(), finished_at = NULL
+		 WHERE id = $2 AND status = $3
+		 RETURNING `+jobColumns,
+		types.JobQueued, id, types.JobFailed,
+	)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return types.Job{}, fmt.Errorf("no failed job found with id: %d", id)
+	}
+	return job, err
+}
+
+func (p *Postgres) ResetRunningJobs() (int64, error) {
+	res, err := p.stmts.Exec(
+		`UPDATE jobs SET status = $1, updated_at = now() WHERE status = $2`,
+		types.JobQueued, types.JobRunning,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset running jobs: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (p *Postgres) PurgeFinishedJobs(cutoff time.Time) (int64, error) {
+	res, err := p.stmts.Exec(`DELETE FROM jobs WHERE finished_at IS NOT NULL AND finished_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge finished jobs: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (p *Postgres) GetJobById(id int64) (types.Job, error) {
+	job, err := scanJob(p.stmts.QueryRow("SELECT "+jobColumns+" FROM jobs WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return types.Job{}, fmt.Errorf("no job found with id: %d", id)
+	}
+	return job, err
+}
+
+func (p *Postgres) GetJobs(status string, limit int) ([]types.Job, error) {
+	query, args := sqlq.Select(sqlq.Dollar, jobColumns).From("jobs").
+		WhereIf(status != "", "status = ?", status).
+		OrderBy("id DESC").Limit(limit).Build()
+	rows, err := p.stmts.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []types.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// scanJob reads jobColumns from a *sql.Row or *sql.Rows
+func scanJob(row interface{ Scan(...any) error }) (types.Job, error) {
+	var (
+		job      types.Job
+		payload  string
+		finished sql.NullTime
+	)
+	err := row.Scan(&job.ID, &job.Type, &payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.LastError, &job.RunAt, &job.CreatedAt, &job.UpdatedAt, &finished)
+	if err == sql.ErrNoRows {
+		return types.Job{}, err
+	}
+	if err != nil {
+		return types.Job{}, fmt.Errorf("failed to scan job: %w", err)
+	}
+	job.Payload = []byte(payload)
+	if finished.Valid {
+		job.FinishedAt = &finished.Time
+	}
+	return job, nil
+}
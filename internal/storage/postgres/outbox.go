@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// WithTrace() → Same scope, recorded events carry the request's trace
+// -------------------------------------------------------------
+func (p *Postgres) WithTrace(trace json.RawMessage) storage.Storage {
+	return &Postgres{DB: p.DB, stmts: p.stmts, replicas: p.replicas, tenantID: p.tenantID, crypt: p.crypt, trace: string(trace)}
+}
+
+// recordEvent adds a student event to the outbox inside tx
+func (p *Postgres) recordEvent(tx *sql.Tx, event string, data any) error {
+	payload, err := storage.EventPayload(data)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO outbox (tenant_id, event, payload, trace) VALUES ($1, $2, $3::jsonb, $4)`,
+		p.tenantID, event, payload, p.trace,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// ClaimOutboxEvents() → Lease the oldest unclaimed events (all tenants)
+// -------------------------------------------------------------
+// SKIP LOCKED lets relays of several instances claim side by side without
+// leasing the same event twice.
+func (p *Postgres) ClaimOutboxEvents(now time.Time, lease time.Duration, limit int) ([]types.OutboxEvent, error) {
+	rows, err := p.stmts.Query(
+		`UPDATE outbox SET claimed_until = $1
+		 WHERE id IN (
+			SELECT id FROM outbox WHERE claimed_until IS NULL OR claimed_until <= $2
+			ORDER BY id ASC LIMIT $3 FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id, tenant_id, event, payload, trace, created_at`,
+		now.Add(lease), now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []types.OutboxEvent
+	for rows.Next() {
+		var event types.OutboxEvent
+		var payload []byte
+		var trace string
+		if err := rows.Scan(&event.ID, &event.TenantID, &event.Event, &payload, &trace, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		event.Payload = payload
+		if trace != "" {
+			event.Trace = json.RawMessage(trace)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING comes back in no particular order
+	slices.SortFunc(events, func(a, b types.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+func (p *Postgres) DeleteOutboxEvent(id int64) error {
+	if _, err := p.stmts.Exec(`DELETE FROM outbox WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const portalSessionColumns = "ps.id, ps.tenant_id, ps.student_id, ps.token_hash, ps.expires_at, ps.created_at"
+
+// Portal reads stay on the primary like refresh tokens: a session is used
+// right after the login that created it.
+
+// -------------------------------------------------------------
+// SetStudentPassword() → Store a student's password, ending their sessions
+// -------------------------------------------------------------
+func (p *Postgres) SetStudentPassword(studentID int64, hash string) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`INSERT INTO student_credentials (student_id, tenant_id, password_hash)
+		 SELECT id, tenant_id, $1 FROM students WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL
+		 ON CONFLICT (student_id) DO UPDATE SET password_hash = EXCLUDED.password_hash, failed_logins = 0, locked_until = NULL, updated_at = now()`,
+		hash, studentID, p.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no student found with id: %d", studentID)
+	}
+	if _, err := tx.Exec(`DELETE FROM portal_sessions WHERE student_id = $1`, studentID); err != nil {
+		return fmt.Errorf("failed to delete portal sessions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudentPassword() → Remove a student's portal login and sessions
+// -------------------------------------------------------------
+func (p *Postgres) DeleteStudentPassword(studentID int64) (bool, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM student_credentials WHERE student_id = $1 AND tenant_id = $2`, studentID, p.tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete password: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM portal_sessions WHERE student_id = $1 AND tenant_id = $2`, studentID, p.tenantID); err != nil {
+		return false, fmt.Errorf("failed to delete portal sessions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentCredential() → Password of the live student with this email
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentCredential(email string) (types.StudentCredential, error) {
+	key, value := p.crypt.Lookup("email", email)
+	var credential types.StudentCredential
+	var lockedUntil sql.NullTime
+	err := p.stmts.QueryRow(
+		`SELECT c.student_id, c.tenant_id, c.password_hash, c.failed_logins, c.locked_until, c.updated_at
+		 FROM student_credentials c JOIN students s ON s.id = c.student_id
+		 WHERE s.tenant_id = $1 AND s.`+key+` = $2 AND s.deleted_at IS NULL`,
+		p.tenantID, value,
+	).Scan(&credential.StudentID, &credential.TenantID, &credential.PasswordHash, &credential.FailedLogins, &lockedUntil, &credential.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return types.StudentCredential{}, storage.ErrNoCredential
+	}
+	if err != nil {
+		return types.StudentCredential{}, fmt.Errorf("failed to scan credential: %w", err)
+	}
+	if lockedUntil.Valid {
+		credential.LockedUntil = &lockedUntil.Time
+	}
+	return credential, nil
+}
+
+// -------------------------------------------------------------
+// SetLoginFailures() → Count failed logins and lock the login out
+// -------------------------------------------------------------
+func (p *Postgres) SetLoginFailures(studentID int64, failures int, lockedUntil *time.Time) error {
+	_, err := p.stmts.Exec(
+		`UPDATE student_credentials SET failed_logins = $1, locked_until = $2 WHERE student_id = $3 AND tenant_id = $4`,
+		failures, lockedUntil, studentID, p.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update login failures: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// CreatePortalSession() → Store a session, dropping the student's expired ones
+// -------------------------------------------------------------
+func (p *Postgres) CreatePortalSession(session types.PortalSession) (int64, error) {
+	if _, err := p.stmts.Exec(`DELETE FROM portal_sessions WHERE student_id = $1 AND expires_at <= now()`, session.StudentID); err != nil {
+		return 0, fmt.Errorf("failed to delete expired portal sessions: %w", err)
+	}
+
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO portal_sessions (tenant_id, student_id, token_hash, expires_at)
+		 SELECT tenant_id, id, $1, $2 FROM students WHERE id = $3 AND tenant_id = $4 AND deleted_at IS NULL
+		 RETURNING id`,
+		session.Hash, session.ExpiresAt, session.StudentID, p.tenantID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no student found with id: %d", session.StudentID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert portal session: %w", err)
+	}
+	return id, nil
+}
+
+// -------------------------------------------------------------
+// GetPortalSession() → Unexpired session of a live student by token hash
+// -------------------------------------------------------------
+func (p *Postgres) GetPortalSession(hash string, now time.Time) (types.PortalSession, error) {
+	var session types.PortalSession
+	err := p.stmts.QueryRow(
+		`SELECT `+portalSessionColumns+`
+		 FROM portal_sessions ps JOIN students s ON s.id = ps.student_id
+		 WHERE ps.token_hash = $1 AND ps.tenant_id = $2 AND ps.expires_at > $3 AND s.deleted_at IS NULL`,
+		hash, p.tenantID, now,
+	).Scan(&session.ID, &session.TenantID, &session.StudentID, &session.Hash, &session.ExpiresAt, &session.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.PortalSession{}, storage.ErrNoSession
+	}
+	if err != nil {
+		return types.PortalSession{}, fmt.Errorf("failed to scan portal session: %w", err)
+	}
+	return session, nil
+}
+
+// -------------------------------------------------------------
+// DeletePortalSession() → Log a session out
+// -------------------------------------------------------------
+func (p *Postgres) DeletePortalSession(hash string) (bool, error) {
+	res, err := p.stmts.Exec(`DELETE FROM portal_sessions WHERE token_hash = $1 AND tenant_id = $2`, hash, p.tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete portal session: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -1,23 +1,56 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/migrate"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
 	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/ulid"
 )
 
+// ageColumn derives age from date_of_birth in whole years; students saved
+// before date_of_birth existed keep their stored age
+const ageColumn = "COALESCE(date_part('year', age(current_date, date_of_birth))::int, age)"
+
+// studentSelect lists a full student row, in studentColumns order
+const studentSelect = "id, public_id, name, email, " + ageColumn + " AS age, phone, " + dateOfBirthColumn + " AS date_of_birth, gender, address, custom, verified"
+
+// dateOfBirthColumn reads the DATE column as YYYY-MM-DD (empty when unset)
+const dateOfBirthColumn = "COALESCE(to_char(date_of_birth, 'YYYY-MM-DD'), '')"
+
 type Postgres struct {
 	DB *sql.DB
+	// stmts prepares each hot query once and reuses it
+	stmts *sqlq.Cache
+	// replicas serve student reads and stats; nil without postgres.replicas
+	replicas *replicaSet
+	// every student query is filtered by this tenant
+	tenantID int64
+	// crypt seals PII columns; nil stores them as plain text
+	crypt *fieldcrypt.Cipher
+	// trace goes on the outbox events this view records (see WithTrace)
+	trace string
 }
 
 // -------------------------------------------------------------
 // New() → Initializes the connection and ensures the students table
 // -------------------------------------------------------------
 func New(cfg config.Config) (*Postgres, error) {
+	crypt, err := fieldcrypt.New(cfg.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+
 	// ✅ Ensure database exists (auto-create if missing)
 	if err := ensureDatabase(cfg); err != nil {
 		return nil, fmt.Errorf("failed to ensure database: %w", err)
@@ -43,21 +76,26 @@ func New(cfg config.Config) (*Postgres, error) {
 		return nil, fmt.Errorf("failed to ping postgres: %w", err)
 	}
 
-	// ✅ Create table if not exists
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS students (
-			id SERIAL PRIMARY KEY,
-			name TEXT NOT NULL,
-			email TEXT UNIQUE NOT NULL,
-			age INTEGER NOT NULL
-		);
-	`)
+	// ✅ Bring schema up to date
+	if err := migrate.Apply(db, migrate.Dollar, migrations); err != nil {
+		return nil, fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	// ✅ Read replicas (optional)
+	replicas, err := openReplicas(cfg.Postgres.Replicas, cfg.Postgres.ReplicaCooldown)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create table: %w", err)
+		return nil, fmt.Errorf("failed to open postgres replica: %w", err)
 	}
 
-	fmt.Println("✅ Connected to PostgreSQL and ensured 'students' table")
-	return &Postgres{DB: db}, nil
+	slog.Debug("✅ Connected to PostgreSQL and migrated schema")
+	return &Postgres{DB: db, stmts: sqlq.NewCache(db), replicas: replicas, tenantID: storage.DefaultTenantID, crypt: crypt}, nil
+}
+
+// -------------------------------------------------------------
+// ForTenant() → Same pool, queries scoped to another tenant
+// -------------------------------------------------------------
+func (p *Postgres) ForTenant(tenantID int64) storage.Storage {
+	return &Postgres{DB: p.DB, stmts: p.stmts, replicas: p.replicas, tenantID: tenantID, crypt: p.crypt, trace: p.trace}
 }
 
 // -------------------------------------------------------------
@@ -91,36 +129,77 @@ func ensureDatabase(cfg config.Config) error {
 // -------------------------------------------------------------
 // CreateStudent() → Insert a student and return generated ID
 // -------------------------------------------------------------
-func (p *Postgres) CreateStudent(name, email string, age int) (int64, error) {
+func (p *Postgres) CreateStudent(student types.Student) (int64, error) {
+	student.PublicID = ulid.New()
+	row, err := storage.SealStudent(p.crypt, student)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var id int64
-	err := p.DB.QueryRow(
-		`INSERT INTO students (name, email, age)
-		 VALUES ($1, $2, $3)
+	err = tx.QueryRow(
+		`INSERT INTO students (tenant_id, name, email, email_hash, age, phone, date_of_birth, gender, address, custom, public_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::date, $8, $9, $10::jsonb, $11)
 		 RETURNING id`,
-		name, email, age,
+		p.tenantID, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, student.PublicID,
 	).Scan(&id)
 
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert student: %w", err)
 	}
+
+	student.ID, student.Age = id, row.Age
+	if err := p.recordEvent(tx, types.EventStudentCreated, student); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return id, nil
 }
 
+// -------------------------------------------------------------
+// EmailTaken() → Whether a create or update with this email would conflict
+// -------------------------------------------------------------
+// Checks the same key as the unique constraint: the email, or its blind
+// index with encryption on. Trashed rows still hold their email. Always
+// asks the primary; a lagging replica could miss a fresh insert.
+func (p *Postgres) EmailTaken(email string, exceptID int64) (bool, error) {
+	key, value := p.crypt.Lookup("email", email)
+	var taken bool
+	err := p.stmts.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM students WHERE tenant_id = $1 AND `+key+` = $2 AND id <> $3)`,
+		p.tenantID, value, exceptID,
+	).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check email: %w", err)
+	}
+	return taken, nil
+}
+
 // -------------------------------------------------------------
 // GetStudentById() → Fetch single student by ID
 // -------------------------------------------------------------
 func (p *Postgres) GetStudentById(id int64) (types.Student, error) {
 	var student types.Student
-	err := p.DB.QueryRow(
-		`SELECT id, name, email, age
-		 FROM students
-		 WHERE id = $1`,
-		id,
-	).Scan(&student.ID, &student.Name, &student.Email, &student.Age)
+	err := p.read(func(db *sqlq.Cache) error {
+		return db.QueryRow(
+			`SELECT `+studentSelect+`
+			 FROM students
+			 WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL`,
+			id, p.tenantID,
+		).Scan(p.studentColumns(&student)...)
+	})
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return types.Student{}, fmt.Errorf("no student found with id: %d", id)
+			return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
 		}
 		return types.Student{}, fmt.Errorf("failed to fetch student: %w", err)
 	}
@@ -132,47 +211,486 @@ func (p *Postgres) GetStudentById(id int64) (types.Student, error) {
 // GetStudents() → Fetch all students
 // -------------------------------------------------------------
 func (p *Postgres) GetStudents() ([]types.Student, error) {
-	rows, err := p.DB.Query(`SELECT id, name, email, age FROM students ORDER BY id ASC`)
+	return p.readStudents(
+		"SELECT "+studentSelect+" FROM students WHERE tenant_id = $1 AND deleted_at IS NULL ORDER BY id ASC",
+		p.studentColumns, p.tenantID,
+	)
+}
+
+// -------------------------------------------------------------
+// GetStudentsIter() → Call fn for every student matching filter, by id
+// -------------------------------------------------------------
+// Each keyset batch is a query of its own, so a replica failing half way
+// only sends the next batch to the primary: nothing is read twice.
+func (p *Postgres) GetStudentsIter(ctx context.Context, filter map[string]any, fn func(types.Student) error) error {
+	// a filter that can't be marshalled fails here, not once per batch
+	if _, err := p.studentsWhere(filter, 0); err != nil {
+		return err
+	}
+	return storage.IterateStudents(ctx, func(afterID int64, limit int) ([]types.Student, error) {
+		query, _ := p.studentsWhere(filter, afterID)
+		text, args := query.Limit(limit).Build()
+		var students []types.Student
+		err := p.read(func(db *sqlq.Cache) error {
+			rows, err := db.QueryContext(ctx, text, args...)
+			if err != nil {
+				// a cancelled request says nothing about the replica
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to query students: %w", err)
+			}
+			students, err = scanStudents(rows, p.studentColumns)
+			return err
+		})
+		if err == nil {
+			err = ctx.Err()
+		}
+		return students, err
+	}, fn)
+}
+
+// -------------------------------------------------------------
+// GetStudentsAfter() → Keyset page: id > afterID, ordered by id
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentsAfter(afterID int64, limit int) ([]types.Student, error) {
+	return p.readStudents(
+		"SELECT "+studentSelect+" FROM students WHERE tenant_id = $1 AND deleted_at IS NULL AND id > $2 ORDER BY id ASC LIMIT $3",
+		p.studentColumns, p.tenantID, afterID, limit,
+	)
+}
+
+// -------------------------------------------------------------
+// GetStudentsBefore() → Reverse keyset page: the last limit with id < beforeID
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentsBefore(beforeID int64, limit int) ([]types.Student, error) {
+	return p.readStudents(
+		`SELECT id, public_id, name, email, age, phone, date_of_birth, gender, address, custom, verified FROM (
+			SELECT `+studentSelect+` FROM students WHERE tenant_id = $1 AND deleted_at IS NULL AND id < $2 ORDER BY id DESC LIMIT $3
+		) page ORDER BY id ASC`,
+		p.studentColumns, p.tenantID, beforeID, limit,
+	)
+}
+
+// -------------------------------------------------------------
+// GetStudentsByIds() → One query for many ids (= ANY(array)), ordered by id
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentsByIds(ids []int64) ([]types.Student, error) {
+	return p.readStudents(
+		"SELECT "+studentSelect+" FROM students WHERE tenant_id = $1 AND deleted_at IS NULL AND id = ANY($2) ORDER BY id ASC",
+		p.studentColumns, p.tenantID, ids,
+	)
+}
+
+// updateStudent replaces every field of a live student; arguments are a
+// StudentRow's fields, then id and tenant id. A new email (compared by
+// blind index when encrypted) clears verified.
+const updateStudent = `UPDATE students SET name = $1, email = $2, email_hash = $3, age = $4, phone = $5, date_of_birth = NULLIF($6, '')::date, gender = $7, address = $8,
+	custom = $9::jsonb, verified = verified AND COALESCE(email_hash, email) = COALESCE($3, $2)
+	WHERE id = $10 AND tenant_id = $11 AND deleted_at IS NULL;`
+
+// -------------------------------------------------------------
+// UpdateStudentById() → Update student based on id
+// -------------------------------------------------------------
+func (p *Postgres) UpdateStudentById(id int64, update types.Student) (types.Student, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return types.Student{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The sealed columns are bound to the public id; the lock keeps the row
+	// live until the update
+	err = tx.QueryRow(
+		"SELECT public_id FROM students WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE",
+		id, p.tenantID,
+	).Scan(&update.PublicID)
+	if err == sql.ErrNoRows {
+		return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
+	}
+	if err != nil {
+		return types.Student{}, fmt.Errorf("failed to query student: %w", err)
+	}
+	key, value := p.crypt.Lookup("email", update.Email)
+	var taken bool
+	err = tx.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM students WHERE tenant_id = $1 AND `+key+` = $2 AND id <> $3)`,
+		p.tenantID, value, id,
+	).Scan(&taken)
+	if err != nil {
+		return types.Student{}, fmt.Errorf("failed to check email: %w", err)
+	}
+	if taken {
+		return types.Student{}, fmt.Errorf("failed to update student: %w: %s", storage.ErrEmailTaken, update.Email)
+	}
+	row, err := storage.SealStudent(p.crypt, update)
+	if err != nil {
+		return types.Student{}, err
+	}
+
+	if _, err := tx.Exec(updateStudent, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, id, p.tenantID); err != nil {
+		return types.Student{}, fmt.Errorf("failed to scan student: %w", err)
+	}
+
+	// The event needs the stored record; read it on the transaction, not a replica
+	var student types.Student
+	err = tx.QueryRow(
+		"SELECT "+studentSelect+" FROM students WHERE id = $1 AND tenant_id = $2",
+		id, p.tenantID,
+	).Scan(p.studentColumns(&student)...)
+	if err != nil {
+		return types.Student{}, fmt.Errorf("failed to fetch student: %w", err)
+	}
+	if err := p.recordEvent(tx, types.EventStudentUpdated, student); err != nil {
+		return types.Student{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Student{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return student, nil
+}
+
+// -------------------------------------------------------------
+// UpsertStudentByEmail() → INSERT ... ON CONFLICT (tenant_id, email) DO UPDATE
+// -------------------------------------------------------------
+// xmax is 0 only for a freshly inserted row, which tells created from
+// updated. The sealed columns are bound to the public id, so the existing
+// row's is read (and locked) first; a row inserted meanwhile has another
+// one, matches the conflict but not the WHERE, and no row comes back.
+// With encryption on, the email's blind index is the conflict key.
+func (p *Postgres) UpsertStudentByEmail(upsert types.Student) (types.Student, bool, error) {
+	key, value := p.crypt.Lookup("email", upsert.Email)
+
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return types.Student{}, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var trashed bool
+	err = tx.QueryRow(
+		"SELECT public_id, deleted_at IS NOT NULL FROM students WHERE tenant_id = $1 AND "+key+" = $2 FOR UPDATE",
+		p.tenantID, value,
+	).Scan(&upsert.PublicID, &trashed)
+	if err == sql.ErrNoRows {
+		upsert.PublicID = ulid.New()
+	} else if err != nil {
+		return types.Student{}, false, fmt.Errorf("failed to query student: %w", err)
+	}
+	if trashed {
+		return types.Student{}, false, storage.ErrStudentTrashed
+	}
+	row, err := storage.SealStudent(p.crypt, upsert)
+	if err != nil {
+		return types.Student{}, false, err
+	}
+
+	var student types.Student
+	var created bool
+	err = tx.QueryRow(
+		`INSERT INTO students (tenant_id, name, email, email_hash, age, phone, date_of_birth, gender, address, custom, public_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::date, $8, $9, $10::jsonb, $11)
+		 ON CONFLICT (tenant_id, `+key+`) DO UPDATE SET name = EXCLUDED.name, age = EXCLUDED.age, phone = EXCLUDED.phone,
+		   date_of_birth = EXCLUDED.date_of_birth, gender = EXCLUDED.gender, address = EXCLUDED.address, custom = EXCLUDED.custom
+		 WHERE students.public_id = EXCLUDED.public_id AND students.deleted_at IS NULL
+		 RETURNING `+studentSelect+`, (xmax = 0)`,
+		p.tenantID, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, upsert.PublicID,
+	).Scan(append(p.studentColumns(&student), &created)...)
+	if err == sql.ErrNoRows {
+		return types.Student{}, false, fmt.Errorf("failed to upsert student: email %s was taken concurrently", upsert.Email)
+	}
+	if err != nil {
+		return types.Student{}, false, fmt.Errorf("failed to upsert student: %w", err)
+	}
+
+	event := types.EventStudentUpdated
+	if created {
+		event = types.EventStudentCreated
+	}
+	if err := p.recordEvent(tx, event, student); err != nil {
+		return types.Student{}, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Student{}, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return student, created, nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudentById() → Soft delete (sets deleted_at)
+// -------------------------------------------------------------
+func (p *Postgres) DeleteStudentById(id int64) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`UPDATE students SET deleted_at = now()
+		 WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL`,
+		id, p.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete student: %w", err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("no student found with id: %d", id)
+	}
+
+	if err := p.recordEvent(tx, types.EventStudentDeleted, storage.DeletedPayload(id)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// GetDeletedStudents() → Soft-deleted students (the trash)
+// -------------------------------------------------------------
+func (p *Postgres) GetDeletedStudents() ([]types.Student, error) {
+	rows, err := p.stmts.Query(
+		`SELECT `+studentSelect+` FROM students
+		 WHERE tenant_id = $1 AND deleted_at IS NOT NULL ORDER BY id ASC`,
+		p.tenantID,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query students: %w", err)
+		return nil, fmt.Errorf("failed to query deleted students: %w", err)
 	}
 	defer rows.Close()
 
 	var students []types.Student
 	for rows.Next() {
 		var student types.Student
-		if err := rows.Scan(&student.ID, &student.Name, &student.Email, &student.Age); err != nil {
+		if err := rows.Scan(p.studentColumns(&student)...); err != nil {
 			return nil, fmt.Errorf("failed to scan student: %w", err)
 		}
 		students = append(students, student)
 	}
+	return students, rows.Err()
+}
+
+// -------------------------------------------------------------
+// RestoreStudentById() → Undo a soft delete
+// -------------------------------------------------------------
+func (p *Postgres) RestoreStudentById(id int64) (types.Student, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return types.Student{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var student types.Student
+	err = tx.QueryRow(
+		`UPDATE students SET deleted_at = NULL
+		 WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL
+		 RETURNING `+studentSelect,
+		id, p.tenantID,
+	).Scan(p.studentColumns(&student)...)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return types.Student{}, fmt.Errorf("no deleted student found with id: %d", id)
+		}
+		return types.Student{}, fmt.Errorf("failed to restore student: %w", err)
+	}
 
+	if err := p.recordEvent(tx, types.EventStudentRestored, student); err != nil {
+		return types.Student{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Student{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return student, nil
+}
+
+// -------------------------------------------------------------
+// PurgeDeletedBefore() → Hard delete old trash (all tenants)
+// -------------------------------------------------------------
+func (p *Postgres) PurgeDeletedBefore(cutoff time.Time) (storage.Purged, error) {
+	// The outer SELECT still sees the documents the cascade removes
+	rows, err := p.stmts.Query(
+		`WITH gone AS (
+		   DELETE FROM students WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id
+		 )
+		 SELECT gone.id, d.blob_key FROM gone LEFT JOIN documents d ON d.student_id = gone.id
+		 ORDER BY gone.id ASC, d.id ASC`,
+		cutoff,
+	)
+	if err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge students: %w", err)
+	}
+	defer rows.Close()
+
+	var purged storage.Purged
+	for rows.Next() {
+		var id int64
+		var key sql.NullString
+		if err := rows.Scan(&id, &key); err != nil {
+			return storage.Purged{}, fmt.Errorf("failed to purge students: %w", err)
+		}
+		if n := len(purged.StudentIDs); n == 0 || purged.StudentIDs[n-1] != id {
+			purged.StudentIDs = append(purged.StudentIDs, id)
+		}
+		if key.Valid {
+			purged.DocumentKeys = append(purged.DocumentKeys, key.String)
+		}
+	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration error: %w", err)
+		return storage.Purged{}, fmt.Errorf("failed to purge students: %w", err)
 	}
+	return purged, nil
+}
 
-	return students, nil
+// -------------------------------------------------------------
+// Tenants → Not tenant-scoped; used by the tenant middleware and admin API
+// -------------------------------------------------------------
+func (p *Postgres) CreateTenant(slug string, name string) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO tenants (slug, name) VALUES ($1, $2) RETURNING id`,
+		slug, name,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert tenant: %w", err)
+	}
+	return id, nil
+}
+
+func (p *Postgres) GetTenantBySlug(slug string) (types.Tenant, error) {
+	var tenant types.Tenant
+	err := p.stmts.QueryRow(`SELECT id, slug, name FROM tenants WHERE slug = $1`, slug).
+		Scan(&tenant.ID, &tenant.Slug, &tenant.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return types.Tenant{}, fmt.Errorf("no tenant found with slug: %s", slug)
+		}
+		return types.Tenant{}, fmt.Errorf("failed to fetch tenant: %w", err)
+	}
+	return tenant, nil
+}
+
+func (p *Postgres) GetTenants() ([]types.Tenant, error) {
+	rows, err := p.stmts.Query(`SELECT id, slug, name FROM tenants ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []types.Tenant
+	for rows.Next() {
+		var tenant types.Tenant
+		if err := rows.Scan(&tenant.ID, &tenant.Slug, &tenant.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
 }
 
 // -------------------------------------------------------------
-// UpdateStudentById() → Update student based on id
+// GetStats() → Aggregates for the admin dashboard (tenant-scoped)
 // -------------------------------------------------------------
-func (p *Postgres) UpdateStudentById(id int64, name, email string, age int) (types.Student, error) {
-	query := `UPDATE students SET name = $1, email = $2, age = $3 WHERE id = $4;`
+func (p *Postgres) GetStats(loc *time.Location) (types.Stats, error) {
+	now := time.Now()
 
-	res, err := p.DB.Exec(query, name, email, age, id)
+	var stats types.Stats
+	err := p.read(func(db *sqlq.Cache) error {
+		var total int64
+		err := db.QueryRow(`SELECT COUNT(*) FROM students WHERE tenant_id = $1 AND deleted_at IS NULL`, p.tenantID).Scan(&total)
+		if err != nil {
+			return fmt.Errorf("failed to count students: %w", err)
+		}
+
+		byAge, err := countBy(db,
+			`SELECT `+storage.AgeBucketCase(ageColumn)+` AS bucket, COUNT(*)
+			 FROM students WHERE tenant_id = $1 AND deleted_at IS NULL GROUP BY bucket`,
+			p.tenantID,
+		)
+		if err != nil {
+			return err
+		}
+
+		byDay, err := countBy(db,
+			`SELECT to_char(created_at AT TIME ZONE $3, 'YYYY-MM-DD') AS day, COUNT(*)
+			 FROM students WHERE tenant_id = $1 AND deleted_at IS NULL AND created_at >= $2 GROUP BY day`,
+			p.tenantID, storage.StatsSince(now, loc), loc.String(),
+		)
+		if err != nil {
+			return err
+		}
+
+		stats = storage.BuildStats(total, byAge, byDay, now, loc)
+		return nil
+	})
 	if err != nil {
-		return types.Student{}, fmt.Errorf("failed to scan student: %w", err)
+		return types.Stats{}, err
 	}
-	// Check if any rows were updated
-	rowsAffected, _ := res.RowsAffected()
-	if rowsAffected == 0 {
-		return types.Student{}, fmt.Errorf("no student found with id: %d", id)
+	return stats, nil
+}
+
+// countBy runs a `SELECT key, COUNT(*) ... GROUP BY key` query into a map
+func countBy(db *sqlq.Cache, query string, args ...any) (map[string]int64, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var key string
+		var count int64
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregate: %w", err)
+		}
+		counts[key] = count
 	}
+	return counts, rows.Err()
+}
+
+// -------------------------------------------------------------
+// readStudents() → Run a student listing on a replica (or the primary)
+// -------------------------------------------------------------
+func (p *Postgres) readStudents(query string, dest func(*types.Student) []any, args ...any) ([]types.Student, error) {
+	var students []types.Student
+	err := p.read(func(db *sqlq.Cache) error {
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query students: %w", err)
+		}
+		students, err = scanStudents(rows, dest)
+		return err
+	})
+	return students, err
+}
 
-	student, _ := p.GetStudentById(id)
+// studentColumns are the Scan targets of a full studentSelect row
+func (p *Postgres) studentColumns(s *types.Student) []any {
+	return []any{&s.ID, &s.PublicID, &s.Name, storage.EmailField(p.crypt, s), &s.Age, storage.PhoneField(p.crypt, s), &s.DateOfBirth, &s.Gender, storage.AddressField(p.crypt, s), storage.CustomValues(&s.Custom), &s.Verified}
+}
 
-	fmt.Println("Update student record is ", res)
+// scanStudents reads every row into a student via dest's targets
+func scanStudents(rows *sql.Rows, dest func(*types.Student) []any) ([]types.Student, error) {
+	defer rows.Close()
 
-	return student, nil
+	var students []types.Student
+	for rows.Next() {
+		var student types.Student
+		if err := rows.Scan(dest(&student)...); err != nil {
+			return nil, fmt.Errorf("failed to scan student: %w", err)
+		}
+		students = append(students, student)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return students, nil
 }
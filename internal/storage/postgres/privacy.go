@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const auditColumns = "id, student_id, action, actor, impersonator, detail, created_at"
+
+// -------------------------------------------------------------
+// GetStudentDeliveries() → Webhook deliveries whose payload is about a student
+// -------------------------------------------------------------
+// Every student event carries the student id as data.id.
+func (p *Postgres) GetStudentDeliveries(studentID int64) ([]types.WebhookDelivery, error) {
+	rows, err := p.stmts.Query(
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE tenant_id = $1 AND payload->'data'->'id' = to_jsonb($2::bigint) ORDER BY id DESC",
+		p.tenantID, studentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []types.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// -------------------------------------------------------------
+// EraseStudent() → Anonymize a student and everything stored about it
+// -------------------------------------------------------------
+// One transaction: the student row (trash included) gets placeholder
+// values and stays in the trash until purged, document rows and email jobs
+// addressed to it are deleted, webhook payloads and pending events about it
+// keep only the id, and the audit entry and student.erased event are added.
+func (p *Postgres) EraseStudent(id int64, entry types.AuditEntry) ([]types.Document, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// the placeholder email is sealed for the row's public id; the current
+	// one finds the queued emails
+	var publicID, current string
+	err = tx.QueryRow(`SELECT public_id, email FROM students WHERE id = $1 AND tenant_id = $2 FOR UPDATE`, id, p.tenantID).Scan(&publicID, &current)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no student found with id: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query student: %w", err)
+	}
+	if current, err = p.crypt.Open(storage.SealedEmail, publicID, current); err != nil {
+		return nil, err
+	}
+	email := storage.ErasedEmail(id)
+	sealed, err := p.crypt.Seal(storage.SealedEmail, publicID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE students SET name = $1, email = $2, email_hash = $3, age = 0, phone = '', date_of_birth = NULL, gender = '', address = '', custom = '{}', verified = FALSE,
+		   deleted_at = COALESCE(deleted_at, now())
+		 WHERE id = $4 AND tenant_id = $5`,
+		storage.ErasedName, sealed, p.crypt.Index("email", email), id, p.tenantID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to erase student: %w", err)
+	}
+
+	// 📎 Documents: the caller deletes the blobs once this commits
+	rows, err := tx.Query("SELECT "+documentColumns+" FROM documents WHERE tenant_id = $1 AND student_id = $2 ORDER BY id ASC", p.tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	var docs []types.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read documents: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM documents WHERE tenant_id = $1 AND student_id = $2`, p.tenantID, id); err != nil {
+		return nil, fmt.Errorf("failed to delete documents: %w", err)
+	}
+	// 👪 Guardians stay (they may have siblings enrolled), the links go
+	if _, err := tx.Exec(`DELETE FROM guardian_students WHERE student_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to unlink guardians: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM verification_tokens WHERE student_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete verification token: %w", err)
+	}
+	// 🔐 No more portal logins as the erased student
+	if _, err := tx.Exec(`DELETE FROM student_credentials WHERE student_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete portal password: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM portal_sessions WHERE student_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete portal sessions: %w", err)
+	}
+	// 📧 Email jobs hold the address and the body, sent or not
+	res, err := tx.Exec(`DELETE FROM jobs WHERE type = $1 AND payload->>'To' = $2`, storage.EmailJobType, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete email jobs: %w", err)
+	}
+	emails, _ := res.RowsAffected()
+
+	// 🪝 Webhook payloads keep the event and the id, nothing else
+	res, err = tx.Exec(
+		`UPDATE webhook_deliveries SET payload = jsonb_set(payload, '{data}', jsonb_build_object('id', $1::bigint, 'erased', true))
+		 WHERE tenant_id = $2 AND payload->'data'->'id' = to_jsonb($1::bigint)`,
+		id, p.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrub webhook deliveries: %w", err)
+	}
+	scrubbed, _ := res.RowsAffected()
+
+	// 📤 So do events not relayed yet, and the erasure is one itself
+	if _, err := tx.Exec(
+		`UPDATE outbox SET payload = jsonb_build_object('id', $1::bigint, 'erased', true)
+		 WHERE tenant_id = $2 AND payload->'id' = to_jsonb($1::bigint)`,
+		id, p.tenantID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scrub outbox events: %w", err)
+	}
+	if err := p.recordEvent(tx, types.EventStudentErased, storage.DeletedPayload(id)); err != nil {
+		return nil, err
+	}
+
+	entry.StudentID = id
+	entry.Detail = storage.ErasureDetail(int64(len(docs)), scrubbed, emails)
+	if _, err := tx.Exec(
+		`INSERT INTO audit_log (tenant_id, student_id, action, actor, impersonator, detail) VALUES ($1, $2, $3, $4, $5, $6::jsonb)`,
+		p.tenantID, entry.StudentID, entry.Action, entry.Actor, entry.Impersonator, string(entry.Detail),
+	); err != nil {
+		return nil, fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return docs, nil
+}
+
+// -------------------------------------------------------------
+// Audit log → GDPR exports, erasures and impersonations, tenant-scoped
+// -------------------------------------------------------------
+func (p *Postgres) CreateAuditEntry(entry types.AuditEntry) (int64, error) {
+	detail := string(entry.Detail)
+	if detail == "" {
+		detail = "{}"
+	}
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO audit_log (tenant_id, student_id, action, actor, impersonator, detail) VALUES ($1, $2, $3, $4, $5, $6::jsonb) RETURNING id`,
+		p.tenantID, entry.StudentID, entry.Action, entry.Actor, entry.Impersonator, detail,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return id, nil
+}
+
+func (p *Postgres) GetAuditEntries(studentID int64) ([]types.AuditEntry, error) {
+	rows, err := p.stmts.Query(
+		"SELECT "+auditColumns+" FROM audit_log WHERE tenant_id = $1 AND student_id = $2 ORDER BY id ASC",
+		p.tenantID, studentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []types.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (p *Postgres) PurgeAuditEntriesBefore(cutoff time.Time) (int64, error) {
+	res, err := p.stmts.Exec(`DELETE FROM audit_log WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge audit log: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// scanAuditEntry reads auditColumns from a *sql.Row or *sql.Rows
+func scanAuditEntry(row interface{ Scan(...any) error }) (types.AuditEntry, error) {
+	var entry types.AuditEntry
+	var detail string
+	err := row.Scan(&entry.ID, &entry.StudentID, &entry.Action, &entry.Actor, &entry.Impersonator, &detail, &entry.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.AuditEntry{}, err
+	}
+	if err != nil {
+		return types.AuditEntry{}, fmt.Errorf("failed to scan audit entry: %w", err)
+	}
+	entry.Detail = []byte(detail)
+	return entry, nil
+}
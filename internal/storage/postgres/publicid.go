@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/ulid"
+)
+
+// -------------------------------------------------------------
+// GetStudentIdByPublicId() → id of the tenant's student with this ULID
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentIdByPublicId(publicID string) (int64, error) {
+	var id int64
+	err := p.read(func(db *sqlq.Cache) error {
+		return db.QueryRow(
+			`SELECT id FROM students WHERE public_id = $1 AND tenant_id = $2`,
+			publicID, p.tenantID,
+		).Scan(&id)
+	})
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no student found with id: %s", publicID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query student: %w", err)
+	}
+	return id, nil
+}
+
+// backfillPublicIDs gives every student saved before migration 20 a ULID
+// timestamped with its created_at, then makes the column required
+func backfillPublicIDs(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, created_at FROM students WHERE public_id IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to query students: %w", err)
+	}
+	created := map[int64]time.Time{}
+	for rows.Next() {
+		var id int64
+		var createdAt time.Time
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan student: %w", err)
+		}
+		created[id] = createdAt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query students: %w", err)
+	}
+
+	for id, createdAt := range created {
+		if _, err := tx.Exec(`UPDATE students SET public_id = $1 WHERE id = $2`, ulid.At(createdAt), id); err != nil {
+			return fmt.Errorf("failed to update student: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`ALTER TABLE students ALTER COLUMN public_id SET NOT NULL`); err != nil {
+		return fmt.Errorf("failed to require public_id: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// Quotas → Daily counters per subject, plus limit overrides
+// -------------------------------------------------------------
+// The upsert bumps the counters and reads them back in one statement, so
+// concurrent requests never lose a count.
+func (p *Postgres) AddQuotaUsage(subject, day string, write bool) (types.QuotaUsage, error) {
+	writes := 0
+	if write {
+		writes = 1
+	}
+	usage := types.QuotaUsage{Day: day}
+	err := p.stmts.QueryRow(
+		`INSERT INTO quota_usage (subject, day, requests, writes) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (subject, day) DO UPDATE SET requests = quota_usage.requests + 1, writes = quota_usage.writes + excluded.writes
+		RETURNING requests, writes`,
+		subject, day, writes,
+	).Scan(&usage.Requests, &usage.Writes)
+	if err != nil {
+		return types.QuotaUsage{}, fmt.Errorf("failed to count quota usage: %w", err)
+	}
+	return usage, nil
+}
+
+func (p *Postgres) GetQuotaUsage(subject, day string) (types.QuotaUsage, error) {
+	usage := types.QuotaUsage{Day: day}
+	err := p.stmts.QueryRow(`SELECT requests, writes FROM quota_usage WHERE subject = $1 AND day = $2`, subject, day).
+		Scan(&usage.Requests, &usage.Writes)
+	if err != nil && err != sql.ErrNoRows {
+		return types.QuotaUsage{}, fmt.Errorf("failed to query quota usage: %w", err)
+	}
+	return usage, nil
+}
+
+func (p *Postgres) GetQuotaLimit(subject string) (types.QuotaLimit, bool, error) {
+	var limit types.QuotaLimit
+	err := p.stmts.QueryRow(`SELECT subject, daily_requests, daily_writes, updated_at FROM quota_limits WHERE subject = $1`, subject).
+		Scan(&limit.Subject, &limit.DailyRequests, &limit.DailyWrites, &limit.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return types.QuotaLimit{}, false, nil
+	}
+	if err != nil {
+		return types.QuotaLimit{}, false, fmt.Errorf("failed to query quota limit: %w", err)
+	}
+	return limit, true, nil
+}
+
+func (p *Postgres) GetQuotaLimits() ([]types.QuotaLimit, error) {
+	rows, err := p.stmts.Query(`SELECT subject, daily_requests, daily_writes, updated_at FROM quota_limits ORDER BY subject`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quota limits: %w", err)
+	}
+	defer rows.Close()
+
+	var limits []types.QuotaLimit
+	for rows.Next() {
+		var limit types.QuotaLimit
+		if err := rows.Scan(&limit.Subject, &limit.DailyRequests, &limit.DailyWrites, &limit.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan quota limit: %w", err)
+		}
+		limits = append(limits, limit)
+	}
+	return limits, rows.Err()
+}
+
+func (p *Postgres) SetQuotaLimit(limit types.QuotaLimit) error {
+	_, err := p.stmts.Exec(
+		`INSERT INTO quota_limits (subject, daily_requests, daily_writes, updated_at) VALUES ($1, $2, $3, now())
+		ON CONFLICT (subject) DO UPDATE SET daily_requests = excluded.daily_requests, daily_writes = excluded.daily_writes, updated_at = now()`,
+		limit.Subject, limit.DailyRequests, limit.DailyWrites,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save quota limit: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) DeleteQuotaLimit(subject string) (bool, error) {
+	res, err := p.stmts.Exec(`DELETE FROM quota_limits WHERE subject = $1`, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete quota limit: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (p *Postgres) PurgeQuotaUsage(day string) (int64, error) {
+	res, err := p.stmts.Exec(`DELETE FROM quota_usage WHERE day < $1`, day)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge quota usage: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+)
+
+// replicaSet spreads reads round-robin over the read replicas. A replica
+// whose query fails is skipped for cooldown and the read is retried on the
+// primary, so a replica outage costs latency, never errors.
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	cooldown time.Duration
+}
+
+type replica struct {
+	host  string // for logs; the DSN carries credentials
+	stmts *sqlq.Cache
+	// downUntil is a unix-nano deadline; zero while healthy
+	downUntil atomic.Int64
+}
+
+// -------------------------------------------------------------
+// openReplicas() → One pool per DSN (nil when there are none)
+// -------------------------------------------------------------
+// An unreachable replica doesn't stop startup: it starts in cooldown and
+// reads use the primary until it answers.
+func openReplicas(dsns []string, cooldown time.Duration) (*replicaSet, error) {
+	if len(dsns) == 0 {
+		return nil, nil
+	}
+
+	set := &replicaSet{cooldown: cooldown}
+	for _, dsn := range dsns {
+		db, err := sql.Open("pgx", dsn)
+		if err != nil {
+			return nil, err
+		}
+		r := &replica{host: hostOf(dsn), stmts: sqlq.NewCache(db)}
+		if err := db.Ping(); err != nil {
+			set.markDown(r, err)
+		}
+		set.replicas = append(set.replicas, r)
+	}
+	slog.Info("📚 Postgres read replicas configured", slog.Int("count", len(set.replicas)))
+	return set, nil
+}
+
+// pick returns the next healthy replica, or nil when all are cooling down
+func (s *replicaSet) pick() *replica {
+	now := time.Now().UnixNano()
+	for range s.replicas {
+		r := s.replicas[s.next.Add(1)%uint64(len(s.replicas))]
+		if r.downUntil.Load() <= now {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *replicaSet) markDown(r *replica, err error) {
+	r.downUntil.Store(time.Now().Add(s.cooldown).UnixNano())
+	slog.Warn("Postgres replica unavailable, reading from primary",
+		slog.String("replica", r.host),
+		slog.Duration("cooldown", s.cooldown),
+		slog.String("error", err.Error()),
+	)
+}
+
+// -------------------------------------------------------------
+// read() → Run a read-only query on a replica, failing over to the primary
+// -------------------------------------------------------------
+// query must only read and must return sql.ErrNoRows (wrapped is fine) for
+// a missing row, which is an answer rather than a replica failure.
+func (p *Postgres) read(query func(db *sqlq.Cache) error) error {
+	if p.replicas != nil {
+		if r := p.replicas.pick(); r != nil {
+			err := query(r.stmts)
+			if err == nil || errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			p.replicas.markDown(r, err)
+		}
+	}
+	return query(p.stmts)
+}
+
+func hostOf(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil {
+		return u.Host
+	}
+	return "replica"
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// AgeDistribution() → Live students per age bucket, in one GROUP BY
+// -------------------------------------------------------------
+func (p *Postgres) AgeDistribution() (types.AgeDistribution, error) {
+	var byAge map[string]int64
+	err := p.read(func(db *sqlq.Cache) (err error) {
+		byAge, err = countBy(db,
+			`SELECT `+storage.AgeBucketCase(ageColumn)+` AS bucket, COUNT(*)
+			 FROM students WHERE tenant_id = $1 AND deleted_at IS NULL GROUP BY bucket`,
+			p.tenantID,
+		)
+		return err
+	})
+	if err != nil {
+		return types.AgeDistribution{}, err
+	}
+	return storage.BuildAgeDistribution(byAge), nil
+}
+
+// -------------------------------------------------------------
+// Birthdays() → Live students born in month, per day of the month
+// -------------------------------------------------------------
+func (p *Postgres) Birthdays(month int) (types.BirthdayReport, error) {
+	var byDay map[string]int64
+	err := p.read(func(db *sqlq.Cache) (err error) {
+		byDay, err = countBy(db,
+			`SELECT EXTRACT(DAY FROM date_of_birth)::int AS day, COUNT(*)
+			 FROM students WHERE tenant_id = $1 AND deleted_at IS NULL AND EXTRACT(MONTH FROM date_of_birth) = $2
+			 GROUP BY day`,
+			p.tenantID, month,
+		)
+		return err
+	})
+	if err != nil {
+		return types.BirthdayReport{}, err
+	}
+	return storage.BuildBirthdays(month, byDay)
+}
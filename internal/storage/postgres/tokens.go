@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const refreshTokenColumns = "id, api_key_id, family, token_hash, expires_at, created_at, used_at, revoked_at"
+
+// -------------------------------------------------------------
+// Refresh tokens → Stored by hash, one row per rotation
+// -------------------------------------------------------------
+// Like API keys these stay on the primary: revocations must apply at once.
+func (p *Postgres) CreateRefreshToken(token types.RefreshToken) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO refresh_tokens (api_key_id, family, token_hash, expires_at) VALUES ($1, $2, $3, $4) RETURNING id`,
+		token.APIKeyID, token.Family, token.Hash, token.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert refresh token: %w", err)
+	}
+	return id, nil
+}
+
+func (p *Postgres) GetRefreshTokenByHash(hash string) (types.RefreshToken, error) {
+	var token types.RefreshToken
+	var usedAt, revokedAt sql.NullTime
+	err := p.stmts.QueryRow("SELECT "+refreshTokenColumns+" FROM refresh_tokens WHERE token_hash = $1", hash).Scan(
+		&token.ID, &token.APIKeyID, &token.Family, &token.Hash, &token.ExpiresAt, &token.CreatedAt, &usedAt, &revokedAt,
+	)
+	if err == sql.ErrNoRows {
+		return types.RefreshToken{}, fmt.Errorf("no refresh token found")
+	}
+	if err != nil {
+		return types.RefreshToken{}, fmt.Errorf("failed to scan refresh token: %w", err)
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
+
+// UseRefreshToken is a compare-and-set, so two concurrent refreshes with
+// the same token can't both win
+func (p *Postgres) UseRefreshToken(id int64) (bool, error) {
+	res, err := p.stmts.Exec(`UPDATE refresh_tokens SET used_at = now() WHERE id = $1 AND used_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (p *Postgres) RevokeRefreshFamily(family string) error {
+	_, err := p.stmts.Exec(`UPDATE refresh_tokens SET revoked_at = now() WHERE family = $1 AND revoked_at IS NULL`, family)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// Revocation list → Access tokens denied until they expire anyway
+// -------------------------------------------------------------
+func (p *Postgres) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	_, err := p.stmts.Exec(`INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING`, jti, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) IsAccessTokenRevoked(jti string) (bool, error) {
+	var revoked bool
+	err := p.stmts.QueryRow(`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("failed to query revoked tokens: %w", err)
+	}
+	return revoked, nil
+}
+
+func (p *Postgres) PurgeExpiredTokens(now time.Time) (int64, error) {
+	refresh, err := p.stmts.Exec(`DELETE FROM refresh_tokens WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge refresh tokens: %w", err)
+	}
+	revoked, err := p.stmts.Exec(`DELETE FROM revoked_tokens WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge revoked tokens: %w", err)
+	}
+	a, _ := refresh.RowsAffected()
+	b, _ := revoked.RowsAffected()
+	return a + b, nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// -------------------------------------------------------------
+// IsTransient() → err left nothing behind and may not happen again
+// -------------------------------------------------------------
+// Serialization failures and deadlocks roll the transaction back; a server
+// shutting down or refusing connections, or a connection lost before the
+// statement was sent, never ran it. A connection lost after that is not
+// transient: the write may have committed.
+func (p *Postgres) IsTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		// class 08: connection exception
+		return strings.HasPrefix(pgErr.Code, "08")
+	}
+	return errors.Is(err, driver.ErrBadConn) || pgconn.SafeToRetry(err)
+}
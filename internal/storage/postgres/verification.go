@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const verificationColumns = "id, tenant_id, student_id, token_hash, expires_at, created_at"
+
+// -------------------------------------------------------------
+// CreateVerificationToken() → Store a student's token, replacing theirs
+// -------------------------------------------------------------
+// Like refresh tokens these stay on the primary: the link may be followed
+// before a replica has the row.
+func (p *Postgres) CreateVerificationToken(token types.VerificationToken) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO verification_tokens (tenant_id, student_id, token_hash, expires_at)
+		 SELECT tenant_id, id, $1, $2 FROM students WHERE id = $3 AND tenant_id = $4 AND deleted_at IS NULL
+		 ON CONFLICT (student_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, expires_at = EXCLUDED.expires_at, created_at = now()
+		 RETURNING id`,
+		token.Hash, token.ExpiresAt, token.StudentID, p.tenantID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no student found with id: %d", token.StudentID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert verification token: %w", err)
+	}
+	return id, nil
+}
+
+// -------------------------------------------------------------
+// VerifyStudent() → Use up a token and mark its student verified
+// -------------------------------------------------------------
+// DELETE ... RETURNING claims the token, so two clicks on the same link
+// can't both succeed. A token whose student is in the trash is kept, so
+// the link works again once the student is restored.
+func (p *Postgres) VerifyStudent(hash string, now time.Time) (types.VerificationToken, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return types.VerificationToken{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var token types.VerificationToken
+	err = tx.QueryRow("DELETE FROM verification_tokens WHERE token_hash = $1 RETURNING "+verificationColumns, hash).Scan(
+		&token.ID, &token.TenantID, &token.StudentID, &token.Hash, &token.ExpiresAt, &token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return types.VerificationToken{}, storage.ErrVerificationInvalid
+	}
+	if err != nil {
+		return types.VerificationToken{}, fmt.Errorf("failed to claim verification token: %w", err)
+	}
+
+	if token.ExpiresAt.Before(now) {
+		if err := tx.Commit(); err != nil {
+			return types.VerificationToken{}, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return types.VerificationToken{}, storage.ErrVerificationExpired
+	}
+	res, err := tx.Exec(
+		`UPDATE students SET verified = TRUE WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL`,
+		token.StudentID, token.TenantID,
+	)
+	if err != nil {
+		return types.VerificationToken{}, fmt.Errorf("failed to verify student: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return types.VerificationToken{}, storage.ErrVerificationInvalid
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.VerificationToken{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return token, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsByVerified() → Live students by verification status, by id
+// -------------------------------------------------------------
+func (p *Postgres) GetStudentsByVerified(verified bool, afterID int64, limit int) ([]types.Student, error) {
+	text, args := p.studentsByVerified(verified, afterID).Limit(limit).Build()
+	return p.readStudents(text, p.studentColumns, args...)
+}
+
+// studentsByVerified selects the live students after afterID by
+// verification status, by id
+func (p *Postgres) studentsByVerified(verified bool, afterID int64) *sqlq.SelectBuilder {
+	return p.liveStudents(storage.StudentFields...).
+		Where("verified = ?", verified).
+		Where("id > ?", afterID).
+		OrderBy("id ASC")
+}
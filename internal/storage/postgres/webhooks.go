@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const (
+	webhookColumns  = "id, url, events, secret, created_at"
+	deliveryColumns = "id, webhook_id, event, payload, status, attempts, response_status, last_error, created_at, delivered_at"
+)
+
+// -------------------------------------------------------------
+// CreateWebhook() → Subscribe a URL for the current tenant
+// -------------------------------------------------------------
+func (p *Postgres) CreateWebhook(hook types.Webhook) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO webhooks (tenant_id, url, events, secret) VALUES ($1, $2, $3, $4) RETURNING id`,
+		p.tenantID, hook.URL, strings.Join(hook.Events, ","), hook.Secret,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert webhook: %w", err)
+	}
+	return id, nil
+}
+
+func (p *Postgres) GetWebhooks() ([]types.Webhook, error) {
+	rows, err := p.stmts.Query("SELECT "+webhookColumns+" FROM webhooks WHERE tenant_id = $1 ORDER BY id ASC", p.tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []types.Webhook
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+func (p *Postgres) GetWebhookById(id int64) (types.Webhook, error) {
+	row := p.stmts.QueryRow("SELECT "+webhookColumns+" FROM webhooks WHERE id = $1 AND tenant_id = $2", id, p.tenantID)
+	hook, err := scanWebhook(row)
+	if err == sql.ErrNoRows {
+		return types.Webhook{}, fmt.Errorf("no webhook found with id: %d", id)
+	}
+	return hook, err
+}
+
+// -------------------------------------------------------------
+// DeleteWebhookById() → Deliveries go with it (ON DELETE CASCADE)
+// -------------------------------------------------------------
+func (p *Postgres) DeleteWebhookById(id int64) error {
+	res, err := p.stmts.Exec(`DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2`, id, p.tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no webhook found with id: %d", id)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// Deliveries → One row per event per webhook
+// -------------------------------------------------------------
+func (p *Postgres) CreateWebhookDelivery(delivery types.WebhookDelivery) (int64, error) {
+	var id int64
+	err := p.stmts.QueryRow(
+		`INSERT INTO webhook_deliveries (tenant_id, webhook_id, event, payload, status)
+		 VALUES ($1, $2, $3, $4::jsonb, $5) RETURNING id`,
+		p.tenantID, delivery.WebhookID, delivery.Event, string(delivery.Payload), delivery.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+	return id, nil
+}
+
+func (p *Postgres) GetWebhookDeliveryById(id int64) (types.WebhookDelivery, error) {
+	row := p.stmts.QueryRow("SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = $1 AND tenant_id = $2", id, p.tenantID)
+	delivery, err := scanDelivery(row)
+	if err == sql.ErrNoRows {
+		return types.WebhookDelivery{}, fmt.Errorf("no webhook delivery found with id: %d", id)
+	}
+	return delivery, err
+}
+
+func (p *Postgres) UpdateWebhookDelivery(delivery types.WebhookDelivery) error {
+	_, err := p.stmts.Exec(
+		`UPDATE webhook_deliveries
+		 SET status = $1, attempts = $2, response_status = $3, last_error = $4, delivered_at = $5
+		 WHERE id = $6 AND tenant_id = $7`,
+		delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError, delivery.DeliveredAt,
+		delivery.ID, p.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetWebhookDeliveries(webhookID int64, limit int) ([]types.WebhookDelivery, error) {
+	rows, err := p.stmts.Query(
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE webhook_id = $1 AND tenant_id = $2 ORDER BY id DESC LIMIT $3",
+		webhookID, p.tenantID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []types.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// scanWebhook reads webhookColumns from a *sql.Row or *sql.Rows
+func scanWebhook(row interface{ Scan(...any) error }) (types.Webhook, error) {
+	var hook types.Webhook
+	var events string
+	err := row.Scan(&hook.ID, &hook.URL, &events, &hook.Secret, &hook.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.Webhook{}, err
+	}
+	if err != nil {
+		return types.Webhook{}, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+	hook.Events = strings.Split(events, ",")
+	return hook, nil
+}
+
+// scanDelivery reads deliveryColumns from a *sql.Row or *sql.Rows
+func scanDelivery(row interface{ Scan(...any) error }) (types.WebhookDelivery, error) {
+	var d types.WebhookDelivery
+	var payload string
+	var deliveredAt sql.NullTime
+	err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &payload, &d.Status, &d.Attempts, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &deliveredAt)
+	if err == sql.ErrNoRows {
+		return types.WebhookDelivery{}, err
+	}
+	if err != nil {
+		return types.WebhookDelivery{}, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+	d.Payload = []byte(payload)
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return d, nil
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// ErasedName replaces the name of an erased student
+const ErasedName = "Erased student"
+
+// EmailJobType is the job type of a queued email (jobs.TypeSendEmail),
+// whose payload names the recipient in To
+const EmailJobType = "email.send"
+
+// ErasedEmail is the placeholder email of an erased student: unique per
+// student, and on a reserved TLD so nothing can ever be sent to it
+func ErasedEmail(id int64) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", id)
+}
+
+// PrivacyStore backs GDPR data exports and erasure, and keeps the audit
+// trail of both. Queries are tenant-scoped like student queries.
+type PrivacyStore interface {
+	// GetStudentDeliveries lists the webhook deliveries whose payload is
+	// about the student, newest first
+	GetStudentDeliveries(studentID int64) ([]types.WebhookDelivery, error)
+	// EraseStudent anonymizes the student (trash included) and leaves it in
+	// the trash, deletes its document records, guardian links and the email
+	// jobs addressed to it, scrubs webhook payloads and outbox events about
+	// it and records entry (and, with an outbox, the student.erased event),
+	// all in one transaction. It returns the deleted documents so the caller
+	// can remove their blobs.
+	EraseStudent(id int64, entry types.AuditEntry) ([]types.Document, error)
+	CreateAuditEntry(entry types.AuditEntry) (int64, error)
+	// GetAuditEntries lists a student's entries, oldest first
+	GetAuditEntries(studentID int64) ([]types.AuditEntry, error)
+	// PurgeAuditEntriesBefore deletes entries recorded before cutoff,
+	// across all tenants, and returns how many went
+	PurgeAuditEntriesBefore(cutoff time.Time) (int64, error)
+}
+
+// ErasureDetail is the audit detail EraseStudent records: how many related
+// rows it removed or scrubbed
+func ErasureDetail(documents, deliveries, emails int64) json.RawMessage {
+	detail, _ := json.Marshal(map[string]int64{"documents": documents, "webhook_deliveries": deliveries, "email_jobs": emails})
+	return detail
+}
@@ -0,0 +1,11 @@
+package storage
+
+// PublicIDStore resolves the ULIDs shown to clients (types.Student.PublicID)
+// to the numeric ids every other query takes. Backends give each student
+// one when it is created.
+type PublicIDStore interface {
+	// GetStudentIdByPublicId returns the id of the tenant's student with
+	// this public id, trashed or not, so routes on trashed students resolve
+	// too; callers pass an upper-case ulid.Valid id
+	GetStudentIdByPublicId(publicID string) (int64, error)
+}
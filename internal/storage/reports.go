@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// ReportStore computes the operational reports with GROUP BY queries, for
+// the current tenant scope.
+type ReportStore interface {
+	// AgeDistribution counts live students per AgeBuckets bucket
+	AgeDistribution() (types.AgeDistribution, error)
+	// Birthdays counts the live students born in month (1-12) per day;
+	// students without a date_of_birth are left out
+	Birthdays(month int) (types.BirthdayReport, error)
+}
+
+// -------------------------------------------------------------
+// BuildAgeDistribution() → Raw counts by bucket label as a histogram
+// -------------------------------------------------------------
+// byLabel is keyed by AgeBucketCase labels, "other" included.
+func BuildAgeDistribution(byLabel map[string]int64) types.AgeDistribution {
+	report := types.AgeDistribution{Other: byLabel["other"]}
+	report.Total = report.Other
+	for _, bucket := range AgeBuckets {
+		bucket.Count = byLabel[bucket.Label]
+		report.Total += bucket.Count
+		report.Buckets = append(report.Buckets, bucket)
+	}
+	return report
+}
+
+// -------------------------------------------------------------
+// BuildBirthdays() → Raw counts by day of month as a zero-filled report
+// -------------------------------------------------------------
+// byDay is keyed by the day number ("1" to "31").
+func BuildBirthdays(month int, byDay map[string]int64) (types.BirthdayReport, error) {
+	report := types.BirthdayReport{Month: month, Days: []types.DayOfMonthCount{}}
+	// a leap year, so February 29 has its day
+	days := time.Date(2000, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	counts := make([]int64, days+1)
+	for key, count := range byDay {
+		day, err := strconv.Atoi(key)
+		if err != nil || day < 1 || day > days {
+			return types.BirthdayReport{}, fmt.Errorf("unexpected day of month %q", key)
+		}
+		counts[day] += count
+	}
+	for day := 1; day <= days; day++ {
+		report.Days = append(report.Days, types.DayOfMonthCount{Day: day, Count: counts[day]})
+		report.Total += counts[day]
+	}
+	return report, nil
+}
@@ -0,0 +1,57 @@
+// Package retry reruns storage writes that failed with a transient error,
+// so a serialization failure or a connection dropped by a failover doesn't
+// reach the client as a 500. It wraps the backend like any decorator;
+// handlers don't know about it.
+package retry
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/backoff"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+)
+
+// -------------------------------------------------------------
+// Wrap() → backend whose writes are retried (backend itself when disabled)
+// -------------------------------------------------------------
+// An error is transient when it wraps storage.ErrTransient or the backend
+// says so (storage.TransientStore); other errors return at once. After the
+// last attempt the caller gets the last error as is.
+func Wrap(cfg config.StorageRetry, backend storage.Storage) storage.Storage {
+	if !cfg.Enabled || cfg.MaxAttempts < 2 {
+		return backend
+	}
+	return storage.Decorate(backend, func(method string, _ []any, call func() error) error {
+		if isRead(method) {
+			return call()
+		}
+		for attempt := 1; ; attempt++ {
+			err := call()
+			if attempt == cfg.MaxAttempts || !storage.IsTransient(backend, err) {
+				return err
+			}
+			delay := backoff.Exponential(cfg.Backoff, attempt, cfg.MaxBackoff)
+			slog.Warn("🔁 Transient storage error, retrying",
+				slog.String("method", method),
+				slog.Int("attempt", attempt),
+				slog.Duration("delay", delay),
+				slog.String("error", err.Error()),
+			)
+			time.Sleep(delay)
+		}
+	})
+}
+
+// isRead matches the calls that only read. They aren't retried: the
+// client can repeat a failed read as cheaply, and the health monitor
+// retries its pings itself.
+func isRead(method string) bool {
+	switch method {
+	case "EmailTaken", "SchemaVersion", "Backup", "Ping", "Reconnect":
+		return true
+	}
+	return strings.HasPrefix(method, "Get") || strings.HasPrefix(method, "Is")
+}
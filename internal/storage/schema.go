@@ -0,0 +1,7 @@
+package storage
+
+// SchemaStore is a backend whose schema is versioned by migrations.
+type SchemaStore interface {
+	// SchemaVersion is the highest applied migration
+	SchemaVersion() (int, error)
+}
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const apiKeyColumns = "id, name, tenant_id, prefix, key_hash, scopes, expires_at, created_at, revoked_at"
+
+// -------------------------------------------------------------
+// CreateAPIKey() → Store a key by hash, bound to key.TenantID
+// -------------------------------------------------------------
+func (s *Sqlite) CreateAPIKey(key types.APIKey) (int64, error) {
+	var expiresAt any
+	if key.ExpiresAt != nil {
+		expiresAt = timestamp(*key.ExpiresAt)
+	}
+	result, err := s.stmts.Exec(
+		`INSERT INTO api_keys (name, tenant_id, prefix, key_hash, scopes, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		key.Name, key.TenantID, key.Prefix, key.Hash, strings.Join(key.Scopes, ","), expiresAt, timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert API key failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetAPIKeyByHash(hash string) (types.APIKey, error) {
+	key, err := scanAPIKey(s.stmts.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ?", hash))
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, fmt.Errorf("no API key found")
+	}
+	return key, err
+}
+
+func (s *Sqlite) GetAPIKeyById(id int64) (types.APIKey, error) {
+	key, err := scanAPIKey(s.stmts.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, fmt.Errorf("no API key found with id: %d", id)
+	}
+	return key, err
+}
+
+func (s *Sqlite) GetAPIKeys() ([]types.APIKey, error) {
+	rows, err := s.stmts.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("query API keys failed: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []types.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// -------------------------------------------------------------
+// RevokeAPIKey() → Stamp revoked_at; the row stays for auditing
+// -------------------------------------------------------------
+// The refresh tokens of the key's sessions are revoked with it.
+func (s *Sqlite) RevokeAPIKey(id int64) (types.APIKey, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return types.APIKey{}, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := timestamp(time.Now())
+	row := tx.QueryRow(
+		"UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL RETURNING "+apiKeyColumns,
+		now, id,
+	)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, fmt.Errorf("no active API key found with id: %d", id)
+	}
+	if err != nil {
+		return types.APIKey{}, err
+	}
+	if _, err := tx.Exec(`UPDATE refresh_tokens SET revoked_at = ? WHERE api_key_id = ? AND revoked_at IS NULL`, now, id); err != nil {
+		return types.APIKey{}, fmt.Errorf("revoke refresh tokens failed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return types.APIKey{}, fmt.Errorf("commit failed: %w", err)
+	}
+	return key, nil
+}
+
+// scanAPIKey reads apiKeyColumns from a *sql.Row or *sql.Rows
+func scanAPIKey(row interface{ Scan(...any) error }) (types.APIKey, error) {
+	var key types.APIKey
+	var scopes string
+	var expiresAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.Name, &key.TenantID, &key.Prefix, &key.Hash, &scopes, &expiresAt, &key.CreatedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return types.APIKey{}, err
+	}
+	if err != nil {
+		return types.APIKey{}, fmt.Errorf("scan API key failed: %w", err)
+	}
+	key.Scopes = strings.Split(scopes, ",")
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
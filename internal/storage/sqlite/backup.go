@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage/migrate"
+	"modernc.org/sqlite"
+)
+
+// -------------------------------------------------------------
+// Backup() → VACUUM INTO a new file: consistent and compacted
+// -------------------------------------------------------------
+// Runs on the writer connection (readers are query_only), so writes wait
+// for it while reads carry on; the copy is the database as of its start.
+func (s *Sqlite) Backup(ctx context.Context, path string) error {
+	if _, err := s.Db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// CheckBackup() → Schema version of a backup file, if it is usable
+// -------------------------------------------------------------
+// The file must be an intact SQLite database written by this API, with a
+// schema no newer than this build knows.
+func CheckBackup(path string) (int, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, fmt.Errorf("backup not readable: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("open backup failed: %w", err)
+	}
+	defer db.Close()
+
+	var integrity string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return 0, fmt.Errorf("not an sqlite database: %w", err)
+	}
+	if integrity != "ok" {
+		return 0, fmt.Errorf("backup is corrupt: %s", integrity)
+	}
+
+	version, err := migrate.Version(db)
+	if err != nil {
+		return 0, fmt.Errorf("not a student-api backup: %w", err)
+	}
+	latest := migrations[len(migrations)-1].Version
+	if version < 1 || version > latest {
+		return 0, fmt.Errorf("backup schema version %d not supported (this build knows 1 to %d)", version, latest)
+	}
+	return version, nil
+}
+
+// -------------------------------------------------------------
+// Restore() → Replace the database at storage_path with a backup
+// -------------------------------------------------------------
+// Stop the API first: it would keep serving cached custom fields and
+// tenants of the old data. The backup is checked, copied page by page
+// with the SQLite backup API (safe with WAL, unlike copying the file)
+// and migrated up to this build's schema.
+func Restore(cfg config.Config, path string) error {
+	if _, err := CheckBackup(path); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", dsn(cfg.StoragePath, cfg.Sqlite, false))
+	if err != nil {
+		return fmt.Errorf("failed to open DB: %w", err)
+	}
+	defer db.Close()
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to connect to DB: %w", err)
+	}
+	err = conn.Raw(func(driverConn any) error {
+		restorer, ok := driverConn.(interface {
+			NewRestore(string) (*sqlite.Backup, error)
+		})
+		if !ok {
+			return errors.New("sqlite driver can't restore")
+		}
+		restore, err := restorer.NewRestore(path)
+		if err != nil {
+			return err
+		}
+		if _, err := restore.Step(-1); err != nil {
+			restore.Finish()
+			return err
+		}
+		return restore.Finish()
+	})
+	conn.Close()
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	if err := migrate.Apply(db, migrate.Question, migrations); err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// UpdateStudents() → Read, change and rewrite many students in one transaction
+// -------------------------------------------------------------
+func (s *Sqlite) UpdateStudents(ids []int64, change func(types.Student) (types.Student, error)) ([]types.BulkResult, error) {
+	list, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT `+studentSelect+` FROM students
+		 WHERE tenant_id = ? AND deleted_at IS NULL AND id IN (SELECT value FROM json_each(?))`,
+		s.tenantID, string(list),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	students, err := scanSelected(rows, s.studentColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[int64]types.BulkResult, len(students))
+	for _, student := range students {
+		result := storage.BulkChanged(student, change)
+		if result.Status == types.BulkUpdated {
+			row, err := storage.SealStudent(s.crypt, *result.Student)
+			if err != nil {
+				return nil, err
+			}
+			_, err = tx.Exec(updateStudent, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, student.ID, s.tenantID)
+			if err != nil {
+				return nil, fmt.Errorf("update student %d failed: %w", student.ID, err)
+			}
+			if err := s.recordEvent(tx, types.EventStudentUpdated, *result.Student); err != nil {
+				return nil, err
+			}
+		}
+		done[student.ID] = result
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return storage.BulkResults(ids, done), nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudents() → Soft-delete many students in one statement
+// -------------------------------------------------------------
+func (s *Sqlite) DeleteStudents(ids []int64) ([]types.BulkResult, error) {
+	list, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`UPDATE students SET deleted_at = ?
+		 WHERE tenant_id = ? AND deleted_at IS NULL AND id IN (SELECT value FROM json_each(?))
+		 RETURNING id`,
+		timestamp(time.Now()), s.tenantID, string(list),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("delete students failed: %w", err)
+	}
+
+	done := map[int64]types.BulkResult{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		done[id] = types.BulkResult{ID: id, Status: types.BulkDeleted}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// events follow the order of ids, like the results
+	for _, id := range ids {
+		if _, ok := done[id]; ok {
+			if err := s.recordEvent(tx, types.EventStudentDeleted, storage.DeletedPayload(id)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return storage.BulkResults(ids, done), nil
+}
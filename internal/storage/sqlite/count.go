@@ -0,0 +1,21 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+)
+
+// -------------------------------------------------------------
+// CountStudents() → COUNT(*) of the students a list query selects
+// -------------------------------------------------------------
+func (s *Sqlite) CountStudents(q storage.StudentQuery) (int64, error) {
+	q.AfterID = 0
+	text, args := s.studentsQuery(q).Count().Build()
+
+	var n int64
+	if err := s.reads.QueryRow(text, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// CreateCustomField() → Define a custom student attribute for the tenant
+// -------------------------------------------------------------
+func (s *Sqlite) CreateCustomField(field types.CustomField) error {
+	_, err := s.stmts.Exec(
+		`INSERT INTO custom_fields (tenant_id, key, type, required, created_at) VALUES (?, ?, ?, ?, ?)`,
+		s.tenantID, field.Key, field.Type, field.Required, timestamp(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("insert custom field failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Sqlite) GetCustomFields() ([]types.CustomField, error) {
+	rows, err := s.stmts.Query(
+		"SELECT key, type, required, created_at FROM custom_fields WHERE tenant_id = ? ORDER BY key ASC",
+		s.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query custom fields failed: %w", err)
+	}
+	defer rows.Close()
+
+	var fields []types.CustomField
+	for rows.Next() {
+		var field types.CustomField
+		field.CreatedAt = new(time.Time)
+		if err := rows.Scan(&field.Key, &field.Type, &field.Required, field.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+func (s *Sqlite) DeleteCustomField(key string) error {
+	res, err := s.stmts.Exec(`DELETE FROM custom_fields WHERE tenant_id = ? AND key = ?`, s.tenantID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no custom field found with key: %s", key)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsWhere() → Live students whose custom values match filter
+// -------------------------------------------------------------
+// json_extract turns JSON booleans into 1/0, which is how bool arguments
+// are bound too; numbers compare numerically whatever their stored form.
+// Keys are applied in sorted order so each key set caches one statement.
+func (s *Sqlite) GetStudentsWhere(filter map[string]any, afterID int64, limit int) ([]types.Student, error) {
+	text, args := s.studentsWhere(filter, afterID).Limit(limit).Build()
+	rows, err := s.stmts.Query(text, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return scanSelected(rows, s.studentColumns)
+}
+
+// studentsWhere selects the live students after afterID matching filter, by id
+func (s *Sqlite) studentsWhere(filter map[string]any, afterID int64) *sqlq.SelectBuilder {
+	query := s.liveStudents(storage.StudentFields...).Where("id > ?", afterID)
+	for _, key := range slices.Sorted(maps.Keys(filter)) {
+		query = query.Where("json_extract(custom, ?) = ?", "$."+key, filter[key])
+	}
+	return query.OrderBy("id ASC")
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const documentColumns = "id, student_id, kind, filename, content_type, size, blob_key, created_at"
+
+// -------------------------------------------------------------
+// CreateDocument() → Insert an attachment record for the current tenant
+// -------------------------------------------------------------
+func (s *Sqlite) CreateDocument(doc types.Document) (int64, error) {
+	result, err := s.stmts.Exec(
+		`INSERT INTO documents (tenant_id, student_id, kind, filename, content_type, size, blob_key, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.tenantID, doc.StudentID, doc.Kind, doc.Filename, doc.ContentType, doc.Size, doc.BlobKey, timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert document failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// -------------------------------------------------------------
+// GetDocuments() → Every attachment of one student, oldest first
+// -------------------------------------------------------------
+func (s *Sqlite) GetDocuments(studentID int64) ([]types.Document, error) {
+	rows, err := s.stmts.Query(
+		"SELECT "+documentColumns+" FROM documents WHERE tenant_id = ? AND student_id = ? ORDER BY id ASC",
+		s.tenantID, studentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query documents failed: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []types.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+func (s *Sqlite) GetStudentDocuments(studentIDs []int64) (map[int64][]types.Document, error) {
+	list, err := json.Marshal(studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.stmts.Query(
+		"SELECT "+documentColumns+" FROM documents WHERE tenant_id = ? AND student_id IN (SELECT value FROM json_each(?)) ORDER BY id ASC",
+		s.tenantID, string(list),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query student documents failed: %w", err)
+	}
+	defer rows.Close()
+
+	byStudent := map[int64][]types.Document{}
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		byStudent[doc.StudentID] = append(byStudent[doc.StudentID], doc)
+	}
+	return byStudent, rows.Err()
+}
+
+func (s *Sqlite) GetDocumentById(studentID int64, id int64) (types.Document, error) {
+	row := s.stmts.QueryRow(
+		"SELECT "+documentColumns+" FROM documents WHERE id = ? AND student_id = ? AND tenant_id = ?",
+		id, studentID, s.tenantID,
+	)
+	doc, err := scanDocument(row)
+	if err == sql.ErrNoRows {
+		return types.Document{}, fmt.Errorf("no document found with id: %d", id)
+	}
+	return doc, err
+}
+
+func (s *Sqlite) DeleteDocumentById(studentID int64, id int64) error {
+	res, err := s.stmts.Exec(
+		`DELETE FROM documents WHERE id = ? AND student_id = ? AND tenant_id = ?`,
+		id, studentID, s.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no document found with id: %d", id)
+	}
+	return nil
+}
+
+// scanDocument reads documentColumns from a *sql.Row or *sql.Rows
+func scanDocument(row interface{ Scan(...any) error }) (types.Document, error) {
+	var doc types.Document
+	err := row.Scan(&doc.ID, &doc.StudentID, &doc.Kind, &doc.Filename, &doc.ContentType, &doc.Size, &doc.BlobKey, &doc.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.Document{}, err
+	}
+	if err != nil {
+		return types.Document{}, fmt.Errorf("scan document failed: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// MigrateEmailDomain() → Move student emails to another domain in one transaction
+// -------------------------------------------------------------
+func (s *Sqlite) MigrateEmailDomain(from, to string, dryRun bool, entry types.AuditEntry) (types.EmailDomainMigration, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return types.EmailDomainMigration{}, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 🔎 Sealed emails can only be matched once opened; plain ones are
+	// narrowed down in SQL first (LIKE ignores ASCII case, as MovedEmail does)
+	query := `SELECT ` + studentSelect + ` FROM students WHERE tenant_id = ? AND deleted_at IS NULL`
+	args := []any{s.tenantID}
+	if s.crypt == nil {
+		query += ` AND email LIKE ?`
+		args = append(args, "%@"+from)
+	}
+	rows, err := tx.Query(query+` ORDER BY id ASC`, args...)
+	if err != nil {
+		return types.EmailDomainMigration{}, fmt.Errorf("query failed: %w", err)
+	}
+	students, err := scanSelected(rows, s.studentColumns)
+	if err != nil {
+		return types.EmailDomainMigration{}, err
+	}
+
+	migration, moved, err := storage.PlanEmailDomain(from, to, students, func(email string) (int64, error) {
+		key, value := s.crypt.Lookup("email", email)
+		var id int64
+		err := tx.QueryRow("SELECT id FROM students WHERE tenant_id = ? AND "+key+" = ?", s.tenantID, value).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return id, err
+	})
+	if err != nil {
+		return migration, fmt.Errorf("check email failed: %w", err)
+	}
+	migration.DryRun = dryRun
+	if dryRun {
+		return migration, nil
+	}
+
+	// 💾 Rewrite, with an event and audit entry per student
+	entry.Action, entry.Detail = types.AuditEmailDomainMigrated, storage.EmailDomainDetail(from, to)
+	now := timestamp(time.Now())
+	for _, student := range moved {
+		row, err := storage.SealStudent(s.crypt, student)
+		if err != nil {
+			return migration, err
+		}
+		if _, err := tx.Exec(updateStudent, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, student.ID, s.tenantID); err != nil {
+			return migration, fmt.Errorf("update student %d failed: %w", student.ID, err)
+		}
+		if err := s.recordEvent(tx, types.EventStudentUpdated, student); err != nil {
+			return migration, err
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO audit_log (tenant_id, student_id, action, actor, impersonator, detail, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			s.tenantID, student.ID, entry.Action, entry.Actor, entry.Impersonator, string(entry.Detail), now,
+		); err != nil {
+			return migration, fmt.Errorf("insert audit entry failed: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return migration, fmt.Errorf("commit failed: %w", err)
+	}
+	return migration, nil
+}
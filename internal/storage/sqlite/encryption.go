@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/storage"
+)
+
+// reencryptBatch is how many rows one pass of ReencryptStudents reads
+const reencryptBatch = 500
+
+// -------------------------------------------------------------
+// ReencryptStudents() → Seal stale PII with the active key (all tenants)
+// -------------------------------------------------------------
+// Walks the table by id in batches, trash included. Each row is updated
+// only if its sealed columns still hold the values that were read, so a
+// concurrent write is never overwritten with older data.
+func (s *Sqlite) ReencryptStudents() (int64, error) {
+	if s.crypt == nil {
+		return 0, fieldcrypt.ErrDisabled
+	}
+
+	type row struct {
+		id                    int64
+		publicID              string
+		email, phone, address string
+	}
+	// empty optional columns have nothing to seal
+	stale := func(stored string) bool { return stored != "" && s.crypt.Stale(stored) }
+	var changed, afterID int64
+	for {
+		rows, err := s.stmts.Query(`SELECT id, public_id, email, phone, address FROM students WHERE id > ? ORDER BY id ASC LIMIT ?`, afterID, reencryptBatch)
+		if err != nil {
+			return changed, fmt.Errorf("query failed: %w", err)
+		}
+		var batch []row
+		for rows.Next() {
+			var r row
+			if err := rows.Scan(&r.id, &r.publicID, &r.email, &r.phone, &r.address); err != nil {
+				rows.Close()
+				return changed, fmt.Errorf("scan failed: %w", err)
+			}
+			batch = append(batch, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return changed, fmt.Errorf("rows iteration error: %w", err)
+		}
+		if len(batch) == 0 {
+			return changed, nil
+		}
+
+		for _, r := range batch {
+			afterID = r.id
+			if !stale(r.email) && !stale(r.phone) && !stale(r.address) {
+				continue
+			}
+			plain, err := s.crypt.Open(storage.SealedEmail, r.publicID, r.email)
+			if err != nil {
+				return changed, fmt.Errorf("student %d: %w", r.id, err)
+			}
+			email, err := s.crypt.Seal(storage.SealedEmail, r.publicID, plain)
+			if err != nil {
+				return changed, err
+			}
+			phone, err := s.crypt.Reseal(storage.SealedPhone, r.publicID, r.phone)
+			if err != nil {
+				return changed, fmt.Errorf("student %d phone: %w", r.id, err)
+			}
+			address, err := s.crypt.Reseal(storage.SealedAddress, r.publicID, r.address)
+			if err != nil {
+				return changed, fmt.Errorf("student %d address: %w", r.id, err)
+			}
+			res, err := s.stmts.Exec(
+				`UPDATE students SET email = ?, email_hash = ?, phone = ?, address = ? WHERE id = ? AND email = ? AND phone = ? AND address = ?`,
+				email, s.crypt.Index("email", plain), phone, address, r.id, r.email, r.phone, r.address,
+			)
+			if err != nil {
+				return changed, fmt.Errorf("re-encrypt student %d failed: %w", r.id, err)
+			}
+			n, _ := res.RowsAffected()
+			changed += n
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+func open(t *testing.T, path string, encryption config.Encryption) *Sqlite {
+	t.Helper()
+	store, err := New(config.Config{
+		StoragePath: path,
+		Sqlite:      config.Sqlite{JournalMode: "wal", BusyTimeout: 5e9, ForeignKeys: true, Synchronous: "normal"},
+		Encryption:  encryption,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { store.Db.Close() })
+	return store
+}
+
+// Rows written before encryption was enabled, or under a retired key, are
+// rewritten sealed with the active key and still read back the same.
+func TestReencryptStudents(t *testing.T) {
+	key := func(b byte) string { return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32))) }
+	enabled := func(keys string) config.Encryption {
+		return config.Encryption{Enabled: true, Keys: keys, IndexKey: key('i')}
+	}
+	path := filepath.Join(t.TempDir(), "students.db")
+
+	plain := open(t, path, config.Encryption{})
+	ada := types.Student{Name: "Ada", Email: "ada@example.com", Age: 36, Phone: "+1 555 0100", Address: &types.Address{Line1: "1 Main St", City: "London", PostalCode: "N1", Country: "GB"}}
+	bob := types.Student{Name: "Bob", Email: "bob@example.com", Age: 20}
+	var ids []int64
+	for _, student := range []types.Student{ada, bob} {
+		id, err := plain.CreateStudent(student)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if _, err := plain.ReencryptStudents(); err != fieldcrypt.ErrDisabled {
+		t.Fatalf("ReencryptStudents without encryption = %v, want ErrDisabled", err)
+	}
+	plain.Db.Close()
+
+	tests := []struct {
+		name string
+		keys string
+		want int64
+	}{
+		{name: "plaintext rows", keys: "a:" + key('a'), want: 2},
+		{name: "already sealed", keys: "a:" + key('a'), want: 0},
+		{name: "rotated key", keys: "b:" + key('b') + ",a:" + key('a'), want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := open(t, path, enabled(tt.keys))
+			changed, err := store.ReencryptStudents()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.want {
+				t.Errorf("ReencryptStudents changed %d rows, want %d", changed, tt.want)
+			}
+
+			active, _, _ := strings.Cut(tt.keys, ":")
+			for _, id := range ids {
+				var email, phone, address string
+				var hash *string
+				err := store.Db.QueryRow(`SELECT email, email_hash, phone, address FROM students WHERE id = ?`, id).Scan(&email, &hash, &phone, &address)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, stored := range []string{email, phone, address} {
+					if stored != "" && !strings.HasPrefix(stored, fieldcrypt.Prefix+active+":") {
+						t.Errorf("student %d: stored %q, want it sealed with key %q", id, stored, active)
+					}
+				}
+				if hash == nil {
+					t.Errorf("student %d: no email blind index", id)
+				}
+			}
+
+			got, err := store.GetStudentById(ids[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Email != ada.Email || got.Phone != ada.Phone || got.Address == nil || got.Address.City != "London" {
+				t.Errorf("GetStudentById = %+v, want Ada's contact details back", got)
+			}
+			if taken, err := store.EmailTaken(bob.Email, 0); err != nil || !taken {
+				t.Errorf("EmailTaken after re-encryption = %v, %v; want found by blind index", taken, err)
+			}
+			store.Db.Close()
+		})
+	}
+}
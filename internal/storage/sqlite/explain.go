@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const explainQueryPlan = "EXPLAIN QUERY PLAN"
+
+// -------------------------------------------------------------
+// ExplainStudents() → EXPLAIN QUERY PLAN of a student list query
+// -------------------------------------------------------------
+// SQLite only plans the query: "SCAN students" means every row is read,
+// "SEARCH students USING INDEX ..." that an index narrows it down.
+func (s *Sqlite) ExplainStudents(q storage.StudentQuery) (types.QueryPlan, error) {
+	text, args := s.studentsQuery(q).Limit(q.Limit).Build()
+	plan := types.QueryPlan{Backend: "sqlite", Explain: explainQueryPlan, Query: text}
+
+	rows, err := s.reads.DB().Query(explainQueryPlan+" "+text, args...)
+	if err != nil {
+		return plan, fmt.Errorf("explain failed: %w", err)
+	}
+	defer rows.Close()
+
+	// each row names its parent; children follow their parent
+	depth := map[int]int{}
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			return plan, fmt.Errorf("scan failed: %w", err)
+		}
+		depth[id] = depth[parent] + 1
+		plan.Plan = append(plan.Plan, strings.Repeat("  ", depth[id]-1)+detail)
+	}
+	if err := rows.Err(); err != nil {
+		return plan, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return plan, nil
+}
+
+// studentsQuery is the select GET /api/students runs for q
+func (s *Sqlite) studentsQuery(q storage.StudentQuery) *sqlq.SelectBuilder {
+	if q.Verified != nil {
+		return s.studentsByVerified(*q.Verified, q.AfterID)
+	}
+	return s.studentsWhere(q.Filter, q.AfterID)
+}
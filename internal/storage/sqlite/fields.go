@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// liveStudents starts a SELECT over the tenant's non-deleted students;
+// the age column is derived (see ageColumn)
+func (s *Sqlite) liveStudents(columns ...string) *sqlq.SelectBuilder {
+	exprs := slices.Clone(columns)
+	if i := slices.Index(exprs, "age"); i >= 0 {
+		exprs[i] = ageColumn + " AS age"
+	}
+	return sqlq.Select(sqlq.Question, exprs...).From("students").
+		Where("tenant_id = ?", s.tenantID).
+		Where("deleted_at IS NULL")
+}
+
+// -------------------------------------------------------------
+// GetStudentByIdFields() → GetStudentById, selecting only fields
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentByIdFields(id int64, fields []string) (types.Student, error) {
+	columns, dest, err := storage.StudentSelection(fields)
+	if err != nil {
+		return types.Student{}, err
+	}
+
+	var student types.Student
+	query, args := s.liveStudents(columns...).Where("id = ?", id).Limit(1).Build()
+	err = s.reads.QueryRow(query, args...).Scan(s.opening(dest)(&student)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
+		}
+		return types.Student{}, fmt.Errorf("query failed: %w", err)
+	}
+
+	return student, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsFields() → GetStudents, selecting only fields
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentsFields(fields []string) ([]types.Student, error) {
+	columns, dest, err := storage.StudentSelection(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := s.liveStudents(columns...).OrderBy("id ASC").Build()
+	rows, err := s.reads.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return scanSelected(rows, s.opening(dest))
+}
+
+// -------------------------------------------------------------
+// GetStudentsAfterFields() → GetStudentsAfter, selecting only fields
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentsAfterFields(afterID int64, limit int, fields []string) ([]types.Student, error) {
+	columns, dest, err := storage.StudentSelection(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := s.liveStudents(columns...).Where("id > ?", afterID).OrderBy("id ASC").Limit(limit).Build()
+	rows, err := s.reads.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return scanSelected(rows, s.opening(dest))
+}
+
+// opening swaps the sealed-column targets of a selection for ones that decrypt
+func (s *Sqlite) opening(dest func(*types.Student) []any) func(*types.Student) []any {
+	return func(st *types.Student) []any {
+		targets := dest(st)
+		for i, target := range targets {
+			switch target {
+			case any(&st.Email):
+				targets[i] = storage.EmailField(s.crypt, st)
+			case any(&st.Phone):
+				targets[i] = storage.PhoneField(s.crypt, st)
+			case any(&st.Address):
+				targets[i] = storage.AddressField(s.crypt, st)
+			}
+		}
+		return targets
+	}
+}
+
+// scanSelected reads every row into a student via the selection's targets
+func scanSelected(rows *sql.Rows, dest func(*types.Student) []any) ([]types.Student, error) {
+	defer rows.Close()
+
+	var students []types.Student
+	for rows.Next() {
+		var student types.Student
+		if err := rows.Scan(dest(&student)...); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		students = append(students, student)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return students, nil
+}
@@ -0,0 +1,218 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const guardianColumns = "g.id, g.name, g.email, g.phone, g.created_at"
+
+// -------------------------------------------------------------
+// CreateGuardian() → Insert a guardian for the current tenant
+// -------------------------------------------------------------
+// The email and phone are sealed for the new id, so they're written right
+// after the insert, in the same transaction.
+func (s *Sqlite) CreateGuardian(g types.Guardian) (int64, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO guardians (tenant_id, name, email, phone, created_at) VALUES (?, ?, '', '', ?)`,
+		s.tenantID, g.Name, timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert guardian failed: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch last insert ID: %w", err)
+	}
+	email, phone, err := storage.SealGuardian(s.crypt, id, g)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE guardians SET email = ?, phone = ? WHERE id = ?`, email, phone, id); err != nil {
+		return 0, fmt.Errorf("update guardian failed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Sqlite) GetGuardians() ([]types.Guardian, error) {
+	rows, err := s.stmts.Query("SELECT "+guardianColumns+" FROM guardians g WHERE g.tenant_id = ? ORDER BY g.name ASC, g.id ASC", s.tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("query guardians failed: %w", err)
+	}
+	defer rows.Close()
+
+	var list []types.Guardian
+	for rows.Next() {
+		var g types.Guardian
+		if err := rows.Scan(s.guardianFields(&g)...); err != nil {
+			return nil, fmt.Errorf("scan guardian failed: %w", err)
+		}
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
+
+func (s *Sqlite) GetGuardianById(id int64) (types.Guardian, error) {
+	var g types.Guardian
+	err := s.stmts.QueryRow("SELECT "+guardianColumns+" FROM guardians g WHERE g.id = ? AND g.tenant_id = ?", id, s.tenantID).
+		Scan(s.guardianFields(&g)...)
+	if err == sql.ErrNoRows {
+		return types.Guardian{}, fmt.Errorf("no guardian found with id: %d", id)
+	}
+	if err != nil {
+		return types.Guardian{}, fmt.Errorf("scan guardian failed: %w", err)
+	}
+	return g, nil
+}
+
+func (s *Sqlite) UpdateGuardianById(id int64, g types.Guardian) (types.Guardian, error) {
+	email, phone, err := storage.SealGuardian(s.crypt, id, g)
+	if err != nil {
+		return types.Guardian{}, err
+	}
+	res, err := s.stmts.Exec(
+		`UPDATE guardians SET name = ?, email = ?, phone = ? WHERE id = ? AND tenant_id = ?`,
+		g.Name, email, phone, id, s.tenantID,
+	)
+	if err != nil {
+		return types.Guardian{}, fmt.Errorf("update guardian failed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return types.Guardian{}, fmt.Errorf("no guardian found with id: %d", id)
+	}
+	return s.GetGuardianById(id)
+}
+
+func (s *Sqlite) DeleteGuardianById(id int64) error {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM guardians WHERE id = ? AND tenant_id = ?`, id, s.tenantID)
+	if err != nil {
+		return fmt.Errorf("delete guardian failed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no guardian found with id: %d", id)
+	}
+	// ON DELETE CASCADE only fires with sqlite.foreign_keys on
+	if _, err := tx.Exec(`DELETE FROM guardian_students WHERE guardian_id = ?`, id); err != nil {
+		return fmt.Errorf("delete guardian links failed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// LinkGuardian() → Link a guardian to a student (or change the relationship)
+// -------------------------------------------------------------
+// Both must belong to the current tenant, and the student be live.
+func (s *Sqlite) LinkGuardian(guardianID, studentID int64, relationship string) error {
+	res, err := s.stmts.Exec(
+		`INSERT INTO guardian_students (guardian_id, student_id, relationship)
+		 SELECT g.id, st.id, ? FROM guardians g, students st
+		 WHERE g.id = ? AND g.tenant_id = ? AND st.id = ? AND st.tenant_id = ? AND st.deleted_at IS NULL
+		 ON CONFLICT (guardian_id, student_id) DO UPDATE SET relationship = excluded.relationship`,
+		relationship, guardianID, s.tenantID, studentID, s.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("link guardian failed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no guardian %d or student %d found", guardianID, studentID)
+	}
+	return nil
+}
+
+func (s *Sqlite) UnlinkGuardian(guardianID, studentID int64) (bool, error) {
+	res, err := s.stmts.Exec(
+		`DELETE FROM guardian_students WHERE guardian_id = ? AND student_id = ?
+		 AND guardian_id IN (SELECT id FROM guardians WHERE tenant_id = ?)`,
+		guardianID, studentID, s.tenantID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("unlink guardian failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *Sqlite) GetStudentGuardians(studentIDs []int64) (map[int64][]types.StudentGuardian, error) {
+	list, err := json.Marshal(studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.stmts.Query(
+		`SELECT gs.student_id, gs.relationship, `+guardianColumns+` FROM guardian_students gs
+		 JOIN guardians g ON g.id = gs.guardian_id
+		 WHERE g.tenant_id = ? AND gs.student_id IN (SELECT value FROM json_each(?))
+		 ORDER BY g.name ASC, g.id ASC`,
+		s.tenantID, string(list),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query student guardians failed: %w", err)
+	}
+	defer rows.Close()
+
+	byStudent := map[int64][]types.StudentGuardian{}
+	for rows.Next() {
+		var (
+			studentID int64
+			g         types.StudentGuardian
+		)
+		if err := rows.Scan(append([]any{&studentID, &g.Relationship}, s.guardianFields(&g.Guardian)...)...); err != nil {
+			return nil, fmt.Errorf("scan guardian failed: %w", err)
+		}
+		byStudent[studentID] = append(byStudent[studentID], g)
+	}
+	return byStudent, rows.Err()
+}
+
+func (s *Sqlite) GetGuardianStudents(guardianID int64) ([]types.GuardianLink, error) {
+	rows, err := s.stmts.Query(
+		`SELECT gs.student_id, gs.relationship FROM guardian_students gs
+		 JOIN students st ON st.id = gs.student_id
+		 WHERE gs.guardian_id = ? AND st.tenant_id = ? AND st.deleted_at IS NULL
+		 ORDER BY gs.student_id ASC`,
+		guardianID, s.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query guardian students failed: %w", err)
+	}
+	defer rows.Close()
+
+	var links []types.GuardianLink
+	for rows.Next() {
+		var link types.GuardianLink
+		if err := rows.Scan(&link.StudentID, &link.Relationship); err != nil {
+			return nil, fmt.Errorf("scan guardian link failed: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// guardianFields are the Scan targets of guardianColumns, opening the
+// sealed email and phone
+func (s *Sqlite) guardianFields(g *types.Guardian) []any {
+	email, phone := storage.GuardianFields(s.crypt, g)
+	return []any{&g.ID, &g.Name, email, phone, &g.CreatedAt}
+}
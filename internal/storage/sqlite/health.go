@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/migrate"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// Ping() → Read the schema, so a broken or unreadable file shows up
+// -------------------------------------------------------------
+// SQLite has no server to lose; reconnecting wouldn't help, so there is
+// no Reconnect.
+func (s *Sqlite) Ping(ctx context.Context) error {
+	var tables int
+	if err := s.Db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&tables); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// PoolStats() → The writer connection, plus the readers when separate
+// -------------------------------------------------------------
+// A high wait_count on "primary" means writes are queueing for the writer.
+func (s *Sqlite) PoolStats() map[string]types.PoolStats {
+	pools := map[string]types.PoolStats{"primary": storage.PoolStatsOf(s.Db)}
+	if s.reads != s.stmts {
+		pools["readers"] = storage.PoolStatsOf(s.reads.DB())
+	}
+	return pools
+}
+
+// -------------------------------------------------------------
+// SchemaVersion() → Last migration recorded in schema_migrations
+// -------------------------------------------------------------
+func (s *Sqlite) SchemaVersion() (int, error) {
+	return migrate.Version(s.Db)
+}
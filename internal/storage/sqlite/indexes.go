@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// requiredIndexes are the indexes the student queries rely on
+var requiredIndexes = []storage.WantIndex{
+	{Table: "students", Columns: []string{"tenant_id"}, For: "every tenant-scoped student query"},
+	{Table: "students", Columns: []string{"tenant_id", "email"}, For: "email lookups and uniqueness"},
+	{Table: "students", Columns: []string{"tenant_id", "email_hash"}, For: "email lookups with field encryption"},
+	{Table: "students", Columns: []string{"tenant_id", "name COLLATE NOCASE"}, For: "case-insensitive name lookups"},
+	{Table: "students", Columns: []string{"tenant_id", "created_at"}, For: "stats and created_at ranges"},
+	{Table: "students", Columns: []string{"tenant_id", "verified"}, For: "?verified= lists"},
+}
+
+// -------------------------------------------------------------
+// CheckIndexes() → Which required indexes exist, from the schema itself
+// -------------------------------------------------------------
+// Key columns come from pragma_index_xinfo; a collation other than the
+// default is part of the column, an expression shows as "<expression>".
+func (s *Sqlite) CheckIndexes() (types.IndexReport, error) {
+	have := map[string]map[string][]string{}
+	for _, want := range requiredIndexes {
+		if have[want.Table] != nil {
+			continue
+		}
+		rows, err := s.Db.Query(
+			`SELECT il.name, ix.name, ix.coll FROM pragma_index_list(?) AS il, pragma_index_xinfo(il.name) AS ix
+			 WHERE ix.key = 1 ORDER BY il.name, ix.seqno`,
+			want.Table,
+		)
+		if err != nil {
+			return types.IndexReport{}, fmt.Errorf("query failed: %w", err)
+		}
+		indexes := map[string][]string{}
+		for rows.Next() {
+			var index, coll string
+			var column sql.NullString
+			if err := rows.Scan(&index, &column, &coll); err != nil {
+				rows.Close()
+				return types.IndexReport{}, fmt.Errorf("scan failed: %w", err)
+			}
+			name := "<expression>"
+			if column.Valid {
+				name = column.String
+			}
+			if !strings.EqualFold(coll, "BINARY") {
+				name += " COLLATE " + coll
+			}
+			indexes[index] = append(indexes[index], name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return types.IndexReport{}, fmt.Errorf("rows iteration error: %w", err)
+		}
+		have[want.Table] = indexes
+	}
+	return storage.CheckIndexes("sqlite", requiredIndexes, have), nil
+}
@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const invoiceColumns = "id, student_id, amount, currency, description, due_date, status, payment_reference, paid_at, created_at"
+
+// -------------------------------------------------------------
+// CreateInvoice() → Bill a student of the current tenant
+// -------------------------------------------------------------
+func (s *Sqlite) CreateInvoice(inv types.Invoice) (int64, error) {
+	result, err := s.stmts.Exec(
+		`INSERT INTO invoices (tenant_id, student_id, amount, currency, description, due_date, status, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.tenantID, inv.StudentID, inv.Amount, inv.Currency, inv.Description, inv.DueDate, types.InvoiceOpen, timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert invoice failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetInvoiceById(id int64) (types.Invoice, error) {
+	row := s.stmts.QueryRow("SELECT "+invoiceColumns+" FROM invoices WHERE id = ? AND tenant_id = ?", id, s.tenantID)
+	inv, err := scanInvoice(row)
+	if err == sql.ErrNoRows {
+		return types.Invoice{}, fmt.Errorf("no invoice found with id: %d", id)
+	}
+	return inv, err
+}
+
+func (s *Sqlite) GetInvoices(studentID int64) ([]types.Invoice, error) {
+	return s.queryInvoices(
+		"SELECT "+invoiceColumns+" FROM invoices WHERE tenant_id = ? AND student_id = ? ORDER BY id ASC",
+		s.tenantID, studentID,
+	)
+}
+
+func (s *Sqlite) GetStudentInvoices(studentIDs []int64) (map[int64][]types.Invoice, error) {
+	ids, err := json.Marshal(studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.queryInvoices(
+		"SELECT "+invoiceColumns+" FROM invoices WHERE tenant_id = ? AND student_id IN (SELECT value FROM json_each(?)) ORDER BY id ASC",
+		s.tenantID, string(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoicesByStudent(list), nil
+}
+
+// -------------------------------------------------------------
+// PayInvoice() → Settle an invoice, idempotent per payment reference
+// -------------------------------------------------------------
+// The writer's transactions take the lock up front (_txlock=immediate),
+// so the checks and the update can't interleave with another payment.
+func (s *Sqlite) PayInvoice(id int64, reference string) (types.Invoice, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return types.Invoice{}, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	inv, err := scanInvoice(tx.QueryRow("SELECT "+invoiceColumns+" FROM invoices WHERE id = ? AND tenant_id = ?", id, s.tenantID))
+	if err == sql.ErrNoRows {
+		return types.Invoice{}, fmt.Errorf("no invoice found with id: %d", id)
+	}
+	if err != nil {
+		return types.Invoice{}, err
+	}
+	if inv.Status == types.InvoicePaid {
+		if inv.PaymentReference == reference {
+			return inv, nil
+		}
+		return types.Invoice{}, fmt.Errorf("%w: invoice %d", storage.ErrInvoicePaid, id)
+	}
+
+	var other int64
+	err = tx.QueryRow(`SELECT id FROM invoices WHERE tenant_id = ? AND payment_reference = ?`, s.tenantID, reference).Scan(&other)
+	if err == nil {
+		return types.Invoice{}, fmt.Errorf("%w: %s paid invoice %d", storage.ErrPaymentReferenceUsed, reference, other)
+	}
+	if err != sql.ErrNoRows {
+		return types.Invoice{}, fmt.Errorf("query payment reference failed: %w", err)
+	}
+
+	paid := clock.Now()
+	if _, err := tx.Exec(
+		`UPDATE invoices SET status = ?, payment_reference = ?, paid_at = ? WHERE id = ?`,
+		types.InvoicePaid, reference, timestamp(paid), id,
+	); err != nil {
+		return types.Invoice{}, fmt.Errorf("update invoice failed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Invoice{}, fmt.Errorf("commit failed: %w", err)
+	}
+
+	inv.Status = types.InvoicePaid
+	inv.PaymentReference = reference
+	inv.PaidAt = &paid
+	return inv, nil
+}
+
+func (s *Sqlite) GetOverdueInvoices(day string) ([]types.Invoice, error) {
+	return s.queryInvoices(
+		"SELECT "+invoiceColumns+" FROM invoices WHERE tenant_id = ? AND status = ? AND due_date < ? ORDER BY due_date ASC, id ASC",
+		s.tenantID, types.InvoiceOpen, day,
+	)
+}
+
+func (s *Sqlite) queryInvoices(query string, args ...any) ([]types.Invoice, error) {
+	rows, err := s.stmts.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query invoices failed: %w", err)
+	}
+	defer rows.Close()
+
+	var list []types.Invoice
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, inv)
+	}
+	return list, rows.Err()
+}
+
+// invoicesByStudent groups list by student, keeping its order
+func invoicesByStudent(list []types.Invoice) map[int64][]types.Invoice {
+	byStudent := map[int64][]types.Invoice{}
+	for _, inv := range list {
+		byStudent[inv.StudentID] = append(byStudent[inv.StudentID], inv)
+	}
+	return byStudent
+}
+
+// scanInvoice reads invoiceColumns from a *sql.Row or *sql.Rows
+func scanInvoice(row interface{ Scan(...any) error }) (types.Invoice, error) {
+	var (
+		inv       types.Invoice
+		reference sql.NullString
+		paid      sql.NullTime
+	)
+	err := row.Scan(&inv.ID, &inv.StudentID, &inv.Amount, &inv.Currency, &inv.Description, &inv.DueDate,
+		&inv.Status, &reference, &paid, &inv.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.Invoice{}, err
+	}
+	if err != nil {
+		return types.Invoice{}, fmt.Errorf("scan invoice failed: %w", err)
+	}
+	inv.PaymentReference = reference.String
+	if paid.Valid {
+		inv.PaidAt = &paid.Time
+	}
+	return inv, nil
+}
@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const jobColumns = "id, type, payload, status, attempts, max_attempts, last_error, run_at, created_at, updated_at, finished_at"
+
+// -------------------------------------------------------------
+// EnqueueJob() → Insert a queued job
+// -------------------------------------------------------------
+func (s *Sqlite) EnqueueJob(job types.Job) (int64, error) {
+	now := timestamp(time.Now())
+	result, err := s.stmts.Exec(
+		`INSERT INTO jobs (type, payload, status, max_attempts, run_at, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		job.Type, string(job.Payload), types.JobQueued, job.MaxAttempts, timestamp(job.RunAt), now, now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert job failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// -------------------------------------------------------------
+// ClaimJob() → Oldest due job → running, in one statement
+// -------------------------------------------------------------
+// A single UPDATE ... RETURNING is atomic, so two workers never get the same job.
+func (s *Sqlite) ClaimJob(now time.Time) (types.Job, bool, error) {
+	row := s.stmts.QueryRow(
+		`UPDATE jobs SET status = ?, attempts = attempts + 1, updated_at = ?
+		 WHERE id = (
+			SELECT id FROM jobs WHERE status = ? AND run_at <= ? ORDER BY run_at, id LIMIT 1
+		 )
+		 RETURNING `+jobColumns,
+		types.JobRunning, timestamp(now), types.JobQueued, timestamp(now),
+	)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return types.Job{}, false, nil
+	}
+	if err != nil {
+		return types.Job{}, false, fmt.Errorf("claim job failed: %w", err)
+	}
+	return job, true, nil
+}
+
+func (s *Sqlite) CompleteJob(id int64) error {
+	now := timestamp(time.Now())
+	_, err := s.stmts.Exec(
+		`UPDATE jobs SET status = ?, last_error = '', updated_at = ?, finished_at = ? WHERE id = ?`,
+		types.JobSucceeded, now, now, id,
+	)
+	if err != nil {
+		return fmt.Errorf("complete job failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Sqlite) FailJob(id int64, lastError string, retryAt *time.Time) error {
+	now := timestamp(time.Now())
+	var err error
+	if retryAt != nil {
+		_, err = s.stmts.Exec(
+			`UPDATE jobs SET status = ?, last_error = ?, run_at = ?, updated_at = ? WHERE id = ?`,
+			types.JobQueued, lastError, timestamp(*retryAt), now, id,
+		)
+	} else {
+		_, err = s.stmts.Exec(
+			`UPDATE jobs SET status = ?, last_error = ?, updated_at = ?, finished_at = ? WHERE id = ?`,
+			types.JobFailed, lastError, now, now, id,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("fail job failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Sqlite) RequeueJob(id int64) (types.Job, error) {
+	now := timestamp(time.Now())
+	row := s.stmts.QueryRow(
+		`UPDATE jobs SET status = ?, attempts = 0, run_at = ?, updated_at = ?, finished_at = NULL
+		 WHERE id = ? AND status = ?
+		 RETURNING `+jobColumns,
+		types.JobQueued, now, now, id, types.JobFailed,
+	)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return types.Job{}, fmt.Errorf("no failed job found with id: %d", id)
+	}
+	return job, err
+}
+
+func (s *Sqlite) ResetRunningJobs() (int64, error) {
+	res, err := s.stmts.Exec(
+		`UPDATE jobs SET status = ?, updated_at = ? WHERE status = ?`,
+		types.JobQueued, timestamp(time.Now()), types.JobRunning,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("reset running jobs failed: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (s *Sqlite) PurgeFinishedJobs(cutoff time.Time) (int64, error) {
+	res, err := s.stmts.Exec(`DELETE FROM jobs WHERE finished_at IS NOT NULL AND finished_at < ?`, timestamp(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("purge finished jobs failed: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (s *Sqlite) GetJobById(id int64) (types.Job, error) {
+	job, err := scanJob(s.stmts.QueryRow("SELECT "+jobColumns+" FROM jobs WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return types.Job{}, fmt.Errorf("no job found with id: %d", id)
+	}
+	return job, err
+}
+
+func (s *Sqlite) GetJobs(status string, limit int) ([]types.Job, error) {
+	query, args := sqlq.Select(sqlq.Question, jobColumns).From("jobs").
+		WhereIf(status != "", "status = ?", status).
+		OrderBy("id DESC").Limit(limit).Build()
+	rows, err := s.stmts.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query jobs failed: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []types.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// scanJob reads jobColumns from a *sql.Row or *sql.Rows
+func scanJob(row interface{ Scan(...any) error }) (types.Job, error) {
+	var (
+		job      types.Job
+		payload  string
+		finished sql.NullTime
+	)
+	err := row.Scan(&job.ID, &job.Type, &payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.LastError, &job.RunAt, &job.CreatedAt, &job.UpdatedAt, &finished)
+	if err == sql.ErrNoRows {
+		return types.Job{}, err
+	}
+	if err != nil {
+		return types.Job{}, fmt.Errorf("scan job failed: %w", err)
+	}
+	job.Payload = []byte(payload)
+	if finished.Valid {
+		job.FinishedAt = &finished.Time
+	}
+	return job, nil
+}
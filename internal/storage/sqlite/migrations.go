@@ -0,0 +1,388 @@
+package sqlite
+
+import "github.com/manish-npx/go-student-api/internal/storage/migrate"
+
+// 🗃️ Schema history — append only, never edit an applied migration
+var migrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create students",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS students (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL,
+				email TEXT UNIQUE NOT NULL,
+				age INTEGER NOT NULL
+			);
+		`,
+	},
+	{
+		Version: 2,
+		Name:    "tenants",
+		// SQLite can't alter a column-level UNIQUE, so students is rebuilt
+		// with email unique per tenant instead of globally.
+		SQL: `
+			CREATE TABLE tenants (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				slug TEXT UNIQUE NOT NULL,
+				name TEXT NOT NULL
+			);
+			INSERT INTO tenants (id, slug, name) VALUES (1, 'default', 'Default');
+
+			CREATE TABLE students_new (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+				name TEXT NOT NULL,
+				email TEXT NOT NULL,
+				age INTEGER NOT NULL,
+				UNIQUE (tenant_id, email)
+			);
+			INSERT INTO students_new (id, tenant_id, name, email, age)
+				SELECT id, 1, name, email, age FROM students;
+			DROP TABLE students;
+			ALTER TABLE students_new RENAME TO students;
+			CREATE INDEX idx_students_tenant ON students(tenant_id);
+		`,
+	},
+	{
+		Version: 3,
+		Name:    "students created_at",
+		// ADD COLUMN can't default to CURRENT_TIMESTAMP, so backfill instead
+		SQL: `
+			ALTER TABLE students ADD COLUMN created_at TIMESTAMP;
+			UPDATE students SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+			CREATE INDEX idx_students_created_at ON students(tenant_id, created_at);
+		`,
+	},
+	{
+		Version: 4,
+		Name:    "students soft delete",
+		SQL: `
+			ALTER TABLE students ADD COLUMN deleted_at TIMESTAMP;
+			CREATE INDEX idx_students_deleted_at ON students(deleted_at);
+		`,
+	},
+	{
+		Version: 5,
+		Name:    "documents",
+		SQL: `
+			CREATE TABLE documents (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+				kind TEXT NOT NULL,
+				filename TEXT NOT NULL,
+				content_type TEXT NOT NULL,
+				size INTEGER NOT NULL,
+				blob_key TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX idx_documents_student ON documents(tenant_id, student_id);
+		`,
+	},
+	{
+		Version: 6,
+		Name:    "jobs",
+		SQL: `
+			CREATE TABLE jobs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				type TEXT NOT NULL,
+				payload TEXT NOT NULL DEFAULT '{}',
+				status TEXT NOT NULL DEFAULT 'queued',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				max_attempts INTEGER NOT NULL,
+				last_error TEXT NOT NULL DEFAULT '',
+				run_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL,
+				finished_at TIMESTAMP
+			);
+			CREATE INDEX idx_jobs_claim ON jobs(status, run_at);
+		`,
+	},
+	{
+		Version: 7,
+		Name:    "webhooks",
+		// events is a comma-separated list of event names (or *)
+		SQL: `
+			CREATE TABLE webhooks (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				url TEXT NOT NULL,
+				events TEXT NOT NULL,
+				secret TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX idx_webhooks_tenant ON webhooks(tenant_id);
+
+			CREATE TABLE webhook_deliveries (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+				event TEXT NOT NULL,
+				payload TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT 'pending',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				response_status INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL,
+				delivered_at TIMESTAMP
+			);
+			CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);
+		`,
+	},
+	{
+		Version: 8,
+		Name:    "api keys",
+		// scopes is a comma-separated list; only the SHA-256 of a key is kept
+		SQL: `
+			CREATE TABLE api_keys (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL,
+				prefix TEXT NOT NULL,
+				key_hash TEXT UNIQUE NOT NULL,
+				scopes TEXT NOT NULL,
+				expires_at TIMESTAMP,
+				created_at TIMESTAMP NOT NULL,
+				revoked_at TIMESTAMP
+			);
+		`,
+	},
+	{
+		Version: 9,
+		Name:    "auth tokens",
+		// revoked_tokens is the access-token deny list, kept until each token expires
+		SQL: `
+			CREATE TABLE refresh_tokens (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				api_key_id INTEGER NOT NULL REFERENCES api_keys(id),
+				family TEXT NOT NULL,
+				token_hash TEXT UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP,
+				revoked_at TIMESTAMP
+			);
+			CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family);
+
+			CREATE TABLE revoked_tokens (
+				jti TEXT PRIMARY KEY,
+				expires_at TIMESTAMP NOT NULL
+			);
+		`,
+	},
+	{
+		Version: 10,
+		Name:    "quotas",
+		// day is the UTC date as YYYY-MM-DD; one counter row per subject and day
+		SQL: `
+			CREATE TABLE quota_usage (
+				subject TEXT NOT NULL,
+				day TEXT NOT NULL,
+				requests INTEGER NOT NULL DEFAULT 0,
+				writes INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (subject, day)
+			);
+			CREATE INDEX idx_quota_usage_day ON quota_usage(day);
+
+			CREATE TABLE quota_limits (
+				subject TEXT PRIMARY KEY,
+				daily_requests INTEGER NOT NULL,
+				daily_writes INTEGER NOT NULL,
+				updated_at TIMESTAMP NOT NULL
+			);
+		`,
+	},
+	{
+		Version: 11,
+		Name:    "students_email_hash",
+		// With field encryption on, email holds ciphertext and uniqueness and
+		// lookups move to this HMAC blind index (NULLs never collide)
+		SQL: `
+			ALTER TABLE students ADD COLUMN email_hash TEXT;
+			CREATE UNIQUE INDEX idx_students_email_hash ON students(tenant_id, email_hash);
+		`,
+	},
+	{
+		Version: 12,
+		Name:    "audit_log",
+		// GDPR exports and erasures; student_id has no foreign key so the
+		// trail outlives the (purged) student
+		SQL: `
+			CREATE TABLE audit_log (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				student_id INTEGER NOT NULL,
+				action TEXT NOT NULL,
+				actor TEXT NOT NULL DEFAULT '',
+				detail TEXT NOT NULL DEFAULT '{}',
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX idx_audit_log_student ON audit_log(tenant_id, student_id, id);
+		`,
+	},
+	{
+		Version: 13,
+		Name:    "students_profile",
+		// phone and address hold sealed text when field encryption is on;
+		// address is JSON, date_of_birth is YYYY-MM-DD
+		SQL: `
+			ALTER TABLE students ADD COLUMN phone TEXT NOT NULL DEFAULT '';
+			ALTER TABLE students ADD COLUMN date_of_birth TEXT NOT NULL DEFAULT '';
+			ALTER TABLE students ADD COLUMN gender TEXT NOT NULL DEFAULT '';
+			ALTER TABLE students ADD COLUMN address TEXT NOT NULL DEFAULT '';
+		`,
+	},
+	{
+		Version: 14,
+		Name:    "custom_fields",
+		// custom is a JSON object of the student's custom attribute values;
+		// custom_fields holds the definitions added through the admin API
+		SQL: `
+			ALTER TABLE students ADD COLUMN custom TEXT NOT NULL DEFAULT '{}';
+			CREATE TABLE custom_fields (
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				key TEXT NOT NULL,
+				type TEXT NOT NULL,
+				required INTEGER NOT NULL DEFAULT 0,
+				created_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (tenant_id, key)
+			);
+		`,
+	},
+	{
+		Version: 15,
+		Name:    "outbox",
+		// student events, written in the same transaction as the change and
+		// deleted once relayed; claimed_until is the relay's lease
+		SQL: `
+			CREATE TABLE outbox (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				event TEXT NOT NULL,
+				payload TEXT NOT NULL,
+				trace TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL,
+				claimed_until TIMESTAMP
+			);
+		`,
+	},
+	{
+		Version: 16,
+		Name:    "invoices",
+		// amount is in minor units; a payment reference settles at most one
+		// invoice per tenant
+		SQL: `
+			CREATE TABLE invoices (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+				amount INTEGER NOT NULL,
+				currency TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				due_date TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT 'open',
+				payment_reference TEXT,
+				paid_at TIMESTAMP,
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX idx_invoices_student ON invoices(tenant_id, student_id);
+			CREATE INDEX idx_invoices_due ON invoices(tenant_id, status, due_date);
+			CREATE UNIQUE INDEX idx_invoices_payment ON invoices(tenant_id, payment_reference);
+		`,
+	},
+	{
+		Version: 17,
+		Name:    "guardians",
+		// email and phone hold sealed text when field encryption is on
+		SQL: `
+			CREATE TABLE guardians (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				name TEXT NOT NULL,
+				email TEXT NOT NULL DEFAULT '',
+				phone TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX idx_guardians_name ON guardians(tenant_id, name);
+			CREATE TABLE guardian_students (
+				guardian_id INTEGER NOT NULL REFERENCES guardians(id) ON DELETE CASCADE,
+				student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+				relationship TEXT NOT NULL,
+				PRIMARY KEY (guardian_id, student_id)
+			);
+			CREATE INDEX idx_guardian_students_student ON guardian_students(student_id);
+		`,
+	},
+	{
+		Version: 18,
+		Name:    "email_verification",
+		// one pending token per student, looked up by hash from the link
+		SQL: `
+			ALTER TABLE students ADD COLUMN verified INTEGER NOT NULL DEFAULT 0;
+			CREATE INDEX idx_students_verified ON students(tenant_id, verified);
+			CREATE TABLE verification_tokens (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				student_id INTEGER NOT NULL UNIQUE REFERENCES students(id) ON DELETE CASCADE,
+				token_hash TEXT UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL
+			);
+		`,
+	},
+	{
+		Version: 19,
+		Name:    "student_portal",
+		// portal passwords, one per student, and their sessions by token hash
+		SQL: `
+			CREATE TABLE student_credentials (
+				student_id INTEGER PRIMARY KEY REFERENCES students(id) ON DELETE CASCADE,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				password_hash TEXT NOT NULL,
+				failed_logins INTEGER NOT NULL DEFAULT 0,
+				locked_until TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL
+			);
+			CREATE TABLE portal_sessions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+				student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+				token_hash TEXT UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX idx_portal_sessions_student ON portal_sessions(student_id);
+		`,
+	},
+	{
+		Version: 20,
+		Name:    "student_public_ids",
+		// ULIDs for clients; existing students get one from their created_at
+		SQL: `
+			ALTER TABLE students ADD COLUMN public_id TEXT;
+			CREATE UNIQUE INDEX idx_students_public_id ON students(public_id);
+		`,
+		Backfill: backfillPublicIDs,
+	},
+	{
+		Version: 21,
+		Name:    "audit_impersonator",
+		// the admin behind an action taken with an impersonation token
+		SQL: `ALTER TABLE audit_log ADD COLUMN impersonator TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		Version: 22,
+		Name:    "student_name_index",
+		// case-insensitive name lookups; email (UNIQUE), created_at and
+		// tenant_id have been indexed since versions 2 and 3
+		SQL: `CREATE INDEX idx_students_name ON students(tenant_id, name COLLATE NOCASE);`,
+	},
+	{
+		Version: 23,
+		Name:    "api_key_tenants",
+		// a key reaches one tenant's students; existing keys get the
+		// default tenant (SQLite can't add a REFERENCES column with a default)
+		SQL: `ALTER TABLE api_keys ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;`,
+	},
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// WithTrace() → Same scope, recorded events carry the request's trace
+// -------------------------------------------------------------
+func (s *Sqlite) WithTrace(trace json.RawMessage) storage.Storage {
+	return &Sqlite{Db: s.Db, stmts: s.stmts, reads: s.reads, tenantID: s.tenantID, crypt: s.crypt, trace: string(trace)}
+}
+
+// recordEvent adds a student event to the outbox inside tx
+func (s *Sqlite) recordEvent(tx *sql.Tx, event string, data any) error {
+	payload, err := storage.EventPayload(data)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO outbox (tenant_id, event, payload, trace, created_at) VALUES (?, ?, ?, ?, ?)`,
+		s.tenantID, event, payload, s.trace, timestamp(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("insert outbox event failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// ClaimOutboxEvents() → Lease the oldest unclaimed events (all tenants)
+// -------------------------------------------------------------
+// One UPDATE ... RETURNING, so two relays never lease the same event.
+func (s *Sqlite) ClaimOutboxEvents(now time.Time, lease time.Duration, limit int) ([]types.OutboxEvent, error) {
+	rows, err := s.stmts.Query(
+		`UPDATE outbox SET claimed_until = ?
+		 WHERE id IN (SELECT id FROM outbox WHERE claimed_until IS NULL OR claimed_until <= ? ORDER BY id ASC LIMIT ?)
+		 RETURNING id, tenant_id, event, payload, trace, created_at`,
+		timestamp(now.Add(lease)), timestamp(now), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox events failed: %w", err)
+	}
+	defer rows.Close()
+
+	var events []types.OutboxEvent
+	for rows.Next() {
+		var event types.OutboxEvent
+		var payload, trace string
+		if err := rows.Scan(&event.ID, &event.TenantID, &event.Event, &payload, &trace, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		event.Payload = json.RawMessage(payload)
+		if trace != "" {
+			event.Trace = json.RawMessage(trace)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	// RETURNING comes back in no particular order
+	slices.SortFunc(events, func(a, b types.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+func (s *Sqlite) DeleteOutboxEvent(id int64) error {
+	if _, err := s.stmts.Exec(`DELETE FROM outbox WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete outbox event failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const portalSessionColumns = "ps.id, ps.tenant_id, ps.student_id, ps.token_hash, ps.expires_at, ps.created_at"
+
+// -------------------------------------------------------------
+// SetStudentPassword() → Store a student's password, ending their sessions
+// -------------------------------------------------------------
+func (s *Sqlite) SetStudentPassword(studentID int64, hash string) error {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`INSERT INTO student_credentials (student_id, tenant_id, password_hash, failed_logins, locked_until, updated_at)
+		 SELECT id, tenant_id, ?, 0, NULL, ? FROM students WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL
+		 ON CONFLICT (student_id) DO UPDATE SET password_hash = excluded.password_hash, failed_logins = 0, locked_until = NULL, updated_at = excluded.updated_at`,
+		hash, timestamp(time.Now()), studentID, s.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("set password failed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no student found with id: %d", studentID)
+	}
+	if _, err := tx.Exec(`DELETE FROM portal_sessions WHERE student_id = ?`, studentID); err != nil {
+		return fmt.Errorf("delete portal sessions failed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudentPassword() → Remove a student's portal login and sessions
+// -------------------------------------------------------------
+func (s *Sqlite) DeleteStudentPassword(studentID int64) (bool, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM student_credentials WHERE student_id = ? AND tenant_id = ?`, studentID, s.tenantID)
+	if err != nil {
+		return false, fmt.Errorf("delete password failed: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM portal_sessions WHERE student_id = ? AND tenant_id = ?`, studentID, s.tenantID); err != nil {
+		return false, fmt.Errorf("delete portal sessions failed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit failed: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentCredential() → Password of the live student with this email
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentCredential(email string) (types.StudentCredential, error) {
+	key, value := s.crypt.Lookup("email", email)
+	var credential types.StudentCredential
+	var lockedUntil sql.NullTime
+	err := s.stmts.QueryRow(
+		`SELECT c.student_id, c.tenant_id, c.password_hash, c.failed_logins, c.locked_until, c.updated_at
+		 FROM student_credentials c JOIN students s ON s.id = c.student_id
+		 WHERE s.tenant_id = ? AND s.`+key+` = ? AND s.deleted_at IS NULL`,
+		s.tenantID, value,
+	).Scan(&credential.StudentID, &credential.TenantID, &credential.PasswordHash, &credential.FailedLogins, &lockedUntil, &credential.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return types.StudentCredential{}, storage.ErrNoCredential
+	}
+	if err != nil {
+		return types.StudentCredential{}, fmt.Errorf("scan credential failed: %w", err)
+	}
+	if lockedUntil.Valid {
+		credential.LockedUntil = &lockedUntil.Time
+	}
+	return credential, nil
+}
+
+// -------------------------------------------------------------
+// SetLoginFailures() → Count failed logins and lock the login out
+// -------------------------------------------------------------
+func (s *Sqlite) SetLoginFailures(studentID int64, failures int, lockedUntil *time.Time) error {
+	var until any
+	if lockedUntil != nil {
+		until = timestamp(*lockedUntil)
+	}
+	_, err := s.stmts.Exec(
+		`UPDATE student_credentials SET failed_logins = ?, locked_until = ? WHERE student_id = ? AND tenant_id = ?`,
+		failures, until, studentID, s.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("update login failures failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// CreatePortalSession() → Store a session, dropping the student's expired ones
+// -------------------------------------------------------------
+func (s *Sqlite) CreatePortalSession(session types.PortalSession) (int64, error) {
+	now := timestamp(time.Now())
+	if _, err := s.stmts.Exec(`DELETE FROM portal_sessions WHERE student_id = ? AND expires_at <= ?`, session.StudentID, now); err != nil {
+		return 0, fmt.Errorf("delete expired portal sessions failed: %w", err)
+	}
+
+	var id int64
+	err := s.stmts.QueryRow(
+		`INSERT INTO portal_sessions (tenant_id, student_id, token_hash, expires_at, created_at)
+		 SELECT tenant_id, id, ?, ?, ? FROM students WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL
+		 RETURNING id`,
+		session.Hash, timestamp(session.ExpiresAt), now, session.StudentID, s.tenantID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no student found with id: %d", session.StudentID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("insert portal session failed: %w", err)
+	}
+	return id, nil
+}
+
+// -------------------------------------------------------------
+// GetPortalSession() → Unexpired session of a live student by token hash
+// -------------------------------------------------------------
+func (s *Sqlite) GetPortalSession(hash string, now time.Time) (types.PortalSession, error) {
+	var session types.PortalSession
+	err := s.stmts.QueryRow(
+		`SELECT `+portalSessionColumns+`
+		 FROM portal_sessions ps JOIN students s ON s.id = ps.student_id
+		 WHERE ps.token_hash = ? AND ps.tenant_id = ? AND ps.expires_at > ? AND s.deleted_at IS NULL`,
+		hash, s.tenantID, timestamp(now),
+	).Scan(&session.ID, &session.TenantID, &session.StudentID, &session.Hash, &session.ExpiresAt, &session.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.PortalSession{}, storage.ErrNoSession
+	}
+	if err != nil {
+		return types.PortalSession{}, fmt.Errorf("scan portal session failed: %w", err)
+	}
+	return session, nil
+}
+
+// -------------------------------------------------------------
+// DeletePortalSession() → Log a session out
+// -------------------------------------------------------------
+func (s *Sqlite) DeletePortalSession(hash string) (bool, error) {
+	res, err := s.stmts.Exec(`DELETE FROM portal_sessions WHERE token_hash = ? AND tenant_id = ?`, hash, s.tenantID)
+	if err != nil {
+		return false, fmt.Errorf("delete portal session failed: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
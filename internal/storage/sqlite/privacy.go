@@ -0,0 +1,222 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const auditColumns = "id, student_id, action, actor, impersonator, detail, created_at"
+
+// -------------------------------------------------------------
+// GetStudentDeliveries() → Webhook deliveries whose payload is about a student
+// -------------------------------------------------------------
+// Every student event carries the student id as data.id.
+func (s *Sqlite) GetStudentDeliveries(studentID int64) ([]types.WebhookDelivery, error) {
+	rows, err := s.stmts.Query(
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE tenant_id = ? AND json_extract(payload, '$.data.id') = ? ORDER BY id DESC",
+		s.tenantID, studentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook deliveries failed: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []types.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// -------------------------------------------------------------
+// EraseStudent() → Anonymize a student and everything stored about it
+// -------------------------------------------------------------
+// One transaction: the student row (trash included) gets placeholder
+// values and stays in the trash until purged, document rows and email jobs
+// addressed to it are deleted, webhook payloads and pending events about it
+// keep only the id, and the audit entry and student.erased event are added.
+func (s *Sqlite) EraseStudent(id int64, entry types.AuditEntry) ([]types.Document, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	// the placeholder email is sealed for the row's public id; the current
+	// one finds the queued emails
+	var publicID, current string
+	err = tx.QueryRow(`SELECT public_id, email FROM students WHERE id = ? AND tenant_id = ?`, id, s.tenantID).Scan(&publicID, &current)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no student found with id: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	if current, err = s.crypt.Open(storage.SealedEmail, publicID, current); err != nil {
+		return nil, err
+	}
+	email := storage.ErasedEmail(id)
+	sealed, err := s.crypt.Seal(storage.SealedEmail, publicID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timestamp(time.Now())
+	if _, err := tx.Exec(
+		`UPDATE students SET name = ?, email = ?, email_hash = ?, age = 0, phone = '', date_of_birth = '', gender = '', address = '', custom = '{}', verified = 0,
+		   deleted_at = COALESCE(deleted_at, ?)
+		 WHERE id = ? AND tenant_id = ?`,
+		storage.ErasedName, sealed, s.crypt.Index("email", email), now, id, s.tenantID,
+	); err != nil {
+		return nil, fmt.Errorf("erase student failed: %w", err)
+	}
+
+	// 📎 Documents: the caller deletes the blobs once this commits
+	rows, err := tx.Query("SELECT "+documentColumns+" FROM documents WHERE tenant_id = ? AND student_id = ? ORDER BY id ASC", s.tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("query documents failed: %w", err)
+	}
+	var docs []types.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM documents WHERE tenant_id = ? AND student_id = ?`, s.tenantID, id); err != nil {
+		return nil, fmt.Errorf("delete documents failed: %w", err)
+	}
+	// 👪 Guardians stay (they may have siblings enrolled), the links go
+	if _, err := tx.Exec(`DELETE FROM guardian_students WHERE student_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("unlink guardians failed: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM verification_tokens WHERE student_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("delete verification token failed: %w", err)
+	}
+	// 🔐 No more portal logins as the erased student
+	if _, err := tx.Exec(`DELETE FROM student_credentials WHERE student_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("delete portal password failed: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM portal_sessions WHERE student_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("delete portal sessions failed: %w", err)
+	}
+	// 📧 Email jobs hold the address and the body, sent or not
+	res, err := tx.Exec(`DELETE FROM jobs WHERE type = ? AND json_extract(payload, '$.To') = ?`, storage.EmailJobType, current)
+	if err != nil {
+		return nil, fmt.Errorf("delete email jobs failed: %w", err)
+	}
+	emails, _ := res.RowsAffected()
+
+	// 🪝 Webhook payloads keep the event and the id, nothing else
+	res, err = tx.Exec(
+		`UPDATE webhook_deliveries SET payload = json_set(payload, '$.data', json_object('id', ?, 'erased', json('true')))
+		 WHERE tenant_id = ? AND json_extract(payload, '$.data.id') = ?`,
+		id, s.tenantID, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scrub webhook deliveries failed: %w", err)
+	}
+	scrubbed, _ := res.RowsAffected()
+
+	// 📤 So do events not relayed yet, and the erasure is one itself
+	if _, err := tx.Exec(
+		`UPDATE outbox SET payload = json_object('id', ?, 'erased', json('true'))
+		 WHERE tenant_id = ? AND json_extract(payload, '$.id') = ?`,
+		id, s.tenantID, id,
+	); err != nil {
+		return nil, fmt.Errorf("scrub outbox events failed: %w", err)
+	}
+	if err := s.recordEvent(tx, types.EventStudentErased, storage.DeletedPayload(id)); err != nil {
+		return nil, err
+	}
+
+	entry.StudentID = id
+	entry.Detail = storage.ErasureDetail(int64(len(docs)), scrubbed, emails)
+	if _, err := tx.Exec(
+		`INSERT INTO audit_log (tenant_id, student_id, action, actor, impersonator, detail, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		s.tenantID, entry.StudentID, entry.Action, entry.Actor, entry.Impersonator, string(entry.Detail), now,
+	); err != nil {
+		return nil, fmt.Errorf("insert audit entry failed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return docs, nil
+}
+
+// -------------------------------------------------------------
+// Audit log → GDPR exports, erasures and impersonations, tenant-scoped
+// -------------------------------------------------------------
+func (s *Sqlite) CreateAuditEntry(entry types.AuditEntry) (int64, error) {
+	detail := string(entry.Detail)
+	if detail == "" {
+		detail = "{}"
+	}
+	result, err := s.stmts.Exec(
+		`INSERT INTO audit_log (tenant_id, student_id, action, actor, impersonator, detail, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		s.tenantID, entry.StudentID, entry.Action, entry.Actor, entry.Impersonator, detail, timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert audit entry failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetAuditEntries(studentID int64) ([]types.AuditEntry, error) {
+	rows, err := s.stmts.Query(
+		"SELECT "+auditColumns+" FROM audit_log WHERE tenant_id = ? AND student_id = ? ORDER BY id ASC",
+		s.tenantID, studentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query audit log failed: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []types.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (s *Sqlite) PurgeAuditEntriesBefore(cutoff time.Time) (int64, error) {
+	res, err := s.stmts.Exec(`DELETE FROM audit_log WHERE created_at < ?`, timestamp(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("purge audit log failed: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// scanAuditEntry reads auditColumns from a *sql.Row or *sql.Rows
+func scanAuditEntry(row interface{ Scan(...any) error }) (types.AuditEntry, error) {
+	var entry types.AuditEntry
+	var detail string
+	err := row.Scan(&entry.ID, &entry.StudentID, &entry.Action, &entry.Actor, &entry.Impersonator, &detail, &entry.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.AuditEntry{}, err
+	}
+	if err != nil {
+		return types.AuditEntry{}, fmt.Errorf("scan audit entry failed: %w", err)
+	}
+	entry.Detail = []byte(detail)
+	return entry, nil
+}
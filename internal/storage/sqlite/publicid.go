@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/ulid"
+)
+
+// -------------------------------------------------------------
+// GetStudentIdByPublicId() → id of the tenant's student with this ULID
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentIdByPublicId(publicID string) (int64, error) {
+	var id int64
+	err := s.reads.QueryRow(
+		"SELECT id FROM students WHERE public_id = ? AND tenant_id = ?",
+		publicID, s.tenantID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no student found with id: %s", publicID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+	return id, nil
+}
+
+// backfillPublicIDs gives every student saved before migration 20 a ULID
+// timestamped with its created_at
+func backfillPublicIDs(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, created_at FROM students WHERE public_id IS NULL`)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	created := map[int64]time.Time{}
+	for rows.Next() {
+		var id int64
+		var createdAt sql.NullTime
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		created[id] = createdAt.Time
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	for id, createdAt := range created {
+		if _, err := tx.Exec(`UPDATE students SET public_id = ? WHERE id = ?`, ulid.At(createdAt), id); err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// Quotas → Daily counters per subject, plus limit overrides
+// -------------------------------------------------------------
+// The upsert bumps the counters and reads them back in one statement, so
+// concurrent requests never lose a count.
+func (s *Sqlite) AddQuotaUsage(subject, day string, write bool) (types.QuotaUsage, error) {
+	writes := 0
+	if write {
+		writes = 1
+	}
+	usage := types.QuotaUsage{Day: day}
+	err := s.stmts.QueryRow(
+		`INSERT INTO quota_usage (subject, day, requests, writes) VALUES (?, ?, 1, ?)
+		ON CONFLICT (subject, day) DO UPDATE SET requests = requests + 1, writes = writes + excluded.writes
+		RETURNING requests, writes`,
+		subject, day, writes,
+	).Scan(&usage.Requests, &usage.Writes)
+	if err != nil {
+		return types.QuotaUsage{}, fmt.Errorf("count quota usage failed: %w", err)
+	}
+	return usage, nil
+}
+
+func (s *Sqlite) GetQuotaUsage(subject, day string) (types.QuotaUsage, error) {
+	usage := types.QuotaUsage{Day: day}
+	err := s.stmts.QueryRow(`SELECT requests, writes FROM quota_usage WHERE subject = ? AND day = ?`, subject, day).
+		Scan(&usage.Requests, &usage.Writes)
+	if err != nil && err != sql.ErrNoRows {
+		return types.QuotaUsage{}, fmt.Errorf("query quota usage failed: %w", err)
+	}
+	return usage, nil
+}
+
+func (s *Sqlite) GetQuotaLimit(subject string) (types.QuotaLimit, bool, error) {
+	var limit types.QuotaLimit
+	err := s.stmts.QueryRow(`SELECT subject, daily_requests, daily_writes, updated_at FROM quota_limits WHERE subject = ?`, subject).
+		Scan(&limit.Subject, &limit.DailyRequests, &limit.DailyWrites, &limit.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return types.QuotaLimit{}, false, nil
+	}
+	if err != nil {
+		return types.QuotaLimit{}, false, fmt.Errorf("query quota limit failed: %w", err)
+	}
+	return limit, true, nil
+}
+
+func (s *Sqlite) GetQuotaLimits() ([]types.QuotaLimit, error) {
+	rows, err := s.stmts.Query(`SELECT subject, daily_requests, daily_writes, updated_at FROM quota_limits ORDER BY subject`)
+	if err != nil {
+		return nil, fmt.Errorf("query quota limits failed: %w", err)
+	}
+	defer rows.Close()
+
+	var limits []types.QuotaLimit
+	for rows.Next() {
+		var limit types.QuotaLimit
+		if err := rows.Scan(&limit.Subject, &limit.DailyRequests, &limit.DailyWrites, &limit.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan quota limit failed: %w", err)
+		}
+		limits = append(limits, limit)
+	}
+	return limits, rows.Err()
+}
+
+func (s *Sqlite) SetQuotaLimit(limit types.QuotaLimit) error {
+	_, err := s.stmts.Exec(
+		`INSERT INTO quota_limits (subject, daily_requests, daily_writes, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (subject) DO UPDATE SET daily_requests = excluded.daily_requests, daily_writes = excluded.daily_writes, updated_at = excluded.updated_at`,
+		limit.Subject, limit.DailyRequests, limit.DailyWrites, timestamp(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("save quota limit failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Sqlite) DeleteQuotaLimit(subject string) (bool, error) {
+	res, err := s.stmts.Exec(`DELETE FROM quota_limits WHERE subject = ?`, subject)
+	if err != nil {
+		return false, fmt.Errorf("delete quota limit failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *Sqlite) PurgeQuotaUsage(day string) (int64, error) {
+	res, err := s.stmts.Exec(`DELETE FROM quota_usage WHERE day < ?`, day)
+	if err != nil {
+		return 0, fmt.Errorf("purge quota usage failed: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// -------------------------------------------------------------
+// AgeDistribution() → Live students per age bucket, in one GROUP BY
+// -------------------------------------------------------------
+func (s *Sqlite) AgeDistribution() (types.AgeDistribution, error) {
+	byAge, err := s.countBy(
+		"SELECT "+storage.AgeBucketCase(ageColumn)+" AS bucket, COUNT(*) FROM students WHERE tenant_id = ? AND deleted_at IS NULL GROUP BY bucket",
+		s.tenantID,
+	)
+	if err != nil {
+		return types.AgeDistribution{}, err
+	}
+	return storage.BuildAgeDistribution(byAge), nil
+}
+
+// -------------------------------------------------------------
+// Birthdays() → Live students born in month, per day of the month
+// -------------------------------------------------------------
+// date_of_birth is YYYY-MM-DD text, so month and day are substrings.
+func (s *Sqlite) Birthdays(month int) (types.BirthdayReport, error) {
+	byDay, err := s.countBy(
+		`SELECT CAST(substr(date_of_birth, 9, 2) AS INTEGER) AS day, COUNT(*) FROM students
+		 WHERE tenant_id = ? AND deleted_at IS NULL AND date_of_birth != '' AND substr(date_of_birth, 6, 2) = ?
+		 GROUP BY day`,
+		s.tenantID, fmt.Sprintf("%02d", month),
+	)
+	if err != nil {
+		return types.BirthdayReport{}, err
+	}
+	return storage.BuildBirthdays(month, byDay)
+}
@@ -1,16 +1,57 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
 
+	"github.com/manish-npx/go-student-api/internal/clock"
 	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/migrate"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
 	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/ulid"
 	_ "modernc.org/sqlite" // ✅ Pure-Go driver (no CGO)
 )
 
 type Sqlite struct {
+	// Db is the writer: one connection, so writes queue up in Go instead
+	// of racing for the file lock
 	Db *sql.DB
+	// stmts prepares each hot query once and reuses it
+	stmts *sqlq.Cache
+	// reads runs student reads on read-only connections (stmts when
+	// sqlite.max_readers is 0)
+	reads *sqlq.Cache
+	// every student query is filtered by this tenant
+	tenantID int64
+	// crypt seals PII columns; nil stores them as plain text
+	crypt *fieldcrypt.Cipher
+	// trace goes on the outbox events this view records (see WithTrace)
+	trace string
+}
+
+// SQLite has no native timestamp type; store UTC text like CURRENT_TIMESTAMP
+const timeLayout = "2006-01-02 15:04:05"
+
+// ageColumn derives age from date_of_birth in whole years (UTC); students
+// saved before date_of_birth existed keep their stored age
+const ageColumn = `COALESCE(CAST(strftime('%Y', 'now') AS INTEGER) - CAST(strftime('%Y', NULLIF(date_of_birth, '')) AS INTEGER)
+	- (strftime('%m-%d', 'now') < strftime('%m-%d', NULLIF(date_of_birth, ''))), age)`
+
+// studentSelect lists a full student row, in studentColumns order
+const studentSelect = "id, public_id, name, email, " + ageColumn + " AS age, phone, date_of_birth, gender, address, custom, verified"
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timeLayout)
 }
 
 func New(cfg config.Config) (*Sqlite, error) {
@@ -18,46 +59,97 @@ func New(cfg config.Config) (*Sqlite, error) {
 		return nil, fmt.Errorf("storage path not provided in config")
 	}
 
-	// ✅ Open or create SQLite DB file
-	db, err := sql.Open("sqlite", cfg.StoragePath)
+	crypt, err := fieldcrypt.New(cfg.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+
+	// ✅ Open or create SQLite DB file; one writer connection
+	db, err := sql.Open("sqlite", dsn(cfg.StoragePath, cfg.Sqlite, false))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open DB: %w", err)
 	}
+	db.SetMaxOpenConns(1)
 
 	// ✅ Check connection
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to connect to DB: %w", err)
 	}
 
-	// ✅ Create table if not exists
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS students (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL,
-			email TEXT UNIQUE NOT NULL,
-			age INTEGER NOT NULL
-		);
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create table: %w", err)
+	// ✅ Bring schema up to date
+	if err := migrate.Apply(db, migrate.Question, migrations); err != nil {
+		return nil, fmt.Errorf("failed to migrate: %w", err)
 	}
 
-	fmt.Println("✅ SQLite connected and 'students' table ensured")
+	stmts := sqlq.NewCache(db)
+	reads := stmts
+	// an in-memory database exists once per connection, readers wouldn't see it
+	if cfg.Sqlite.MaxReaders > 0 && cfg.StoragePath != ":memory:" {
+		readers, err := sql.Open("sqlite", dsn(cfg.StoragePath, cfg.Sqlite, true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open DB readers: %w", err)
+		}
+		readers.SetMaxOpenConns(cfg.Sqlite.MaxReaders)
+		reads = sqlq.NewCache(readers)
+	}
 
-	return &Sqlite{Db: db}, nil
+	slog.Debug("✅ SQLite connected and schema migrated")
+
+	return &Sqlite{Db: db, stmts: stmts, reads: reads, tenantID: storage.DefaultTenantID, crypt: crypt}, nil
 }
 
 // -------------------------------------------------------------
-// CreateStudent → Insert record
+// dsn() → Database path plus the sqlite.* pragmas
+// -------------------------------------------------------------
+// The driver runs each _pragma on every new connection. Write transactions
+// begin IMMEDIATE, taking the lock up front, so busy_timeout covers them
+// instead of an upgrade failing halfway.
+func dsn(path string, cfg config.Sqlite, readOnly bool) string {
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	params.Add("_pragma", "journal_mode("+cfg.JournalMode+")")
+	params.Add("_pragma", "synchronous("+cfg.Synchronous+")")
+	foreignKeys := 0
+	if cfg.ForeignKeys {
+		foreignKeys = 1
+	}
+	params.Add("_pragma", fmt.Sprintf("foreign_keys(%d)", foreignKeys))
+	if readOnly {
+		params.Add("_pragma", "query_only(1)")
+	} else {
+		params.Set("_txlock", "immediate")
+	}
+	return path + "?" + params.Encode()
+}
+
 // -------------------------------------------------------------
-func (s *Sqlite) CreateStudent(name string, email string, age int) (int64, error) {
-	stmt, err := s.Db.Prepare("INSERT INTO students (name, email, age) VALUES (?, ?, ?)")
+// ForTenant() → Same connections, queries scoped to another tenant
+// -------------------------------------------------------------
+func (s *Sqlite) ForTenant(tenantID int64) storage.Storage {
+	return &Sqlite{Db: s.Db, stmts: s.stmts, reads: s.reads, tenantID: tenantID, crypt: s.crypt, trace: s.trace}
+}
+
+// -------------------------------------------------------------
+// CreateStudent → Insert record (and its student.created event)
+// -------------------------------------------------------------
+func (s *Sqlite) CreateStudent(student types.Student) (int64, error) {
+	student.PublicID = ulid.New()
+	row, err := storage.SealStudent(s.crypt, student)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.Db.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("prepare insert failed: %w", err)
+		return 0, fmt.Errorf("begin failed: %w", err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	result, err := stmt.Exec(name, email, age)
+	result, err := tx.Exec(
+		`INSERT INTO students (tenant_id, public_id, name, email, email_hash, age, phone, date_of_birth, gender, address, custom, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.tenantID, student.PublicID, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, timestamp(time.Now()),
+	)
 	if err != nil {
 		return 0, fmt.Errorf("insert exec failed: %w", err)
 	}
@@ -67,24 +159,46 @@ func (s *Sqlite) CreateStudent(name string, email string, age int) (int64, error
 		return 0, fmt.Errorf("failed to fetch last insert ID: %w", err)
 	}
 
+	student.ID, student.Age = lastId, row.Age
+	if err := s.recordEvent(tx, types.EventStudentCreated, student); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
 	return lastId, nil
 }
 
 // -------------------------------------------------------------
-// GetStudentById → Fetch a single student by ID
+// EmailTaken() → Whether a create or update with this email would conflict
 // -------------------------------------------------------------
-func (s *Sqlite) GetStudentById(id int64) (types.Student, error) {
-	stmt, err := s.Db.Prepare("SELECT id, name, email, age FROM students WHERE id = ? LIMIT 1")
+// Checks the same key as the UNIQUE index: the email, or its blind index
+// with encryption on. Trashed rows still hold their email.
+func (s *Sqlite) EmailTaken(email string, exceptID int64) (bool, error) {
+	key, value := s.crypt.Lookup("email", email)
+	var taken bool
+	err := s.stmts.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM students WHERE tenant_id = ? AND "+key+" = ? AND id <> ?)",
+		s.tenantID, value, exceptID,
+	).Scan(&taken)
 	if err != nil {
-		return types.Student{}, fmt.Errorf("prepare failed: %w", err)
+		return false, fmt.Errorf("query failed: %w", err)
 	}
-	defer stmt.Close()
+	return taken, nil
+}
 
+// -------------------------------------------------------------
+// GetStudentById → Fetch a single student by ID
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentById(id int64) (types.Student, error) {
 	var student types.Student
-	err = stmt.QueryRow(id).Scan(&student.ID, &student.Name, &student.Email, &student.Age)
+	err := s.reads.QueryRow(
+		"SELECT "+studentSelect+" FROM students WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL LIMIT 1",
+		id, s.tenantID,
+	).Scan(s.studentColumns(&student)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return types.Student{}, fmt.Errorf("no student found with id: %d", id)
+			return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
 		}
 		return types.Student{}, fmt.Errorf("query failed: %w", err)
 	}
@@ -96,13 +210,56 @@ func (s *Sqlite) GetStudentById(id int64) (types.Student, error) {
 // GetStudents → Fetch all students
 // -------------------------------------------------------------
 func (s *Sqlite) GetStudents() ([]types.Student, error) {
-	stmt, err := s.Db.Prepare("SELECT id, name, email, age FROM students ORDER BY id ASC")
+	rows, err := s.reads.Query(
+		"SELECT "+studentSelect+" FROM students WHERE tenant_id = ? AND deleted_at IS NULL ORDER BY id ASC",
+		s.tenantID,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("prepare failed: %w", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var students []types.Student
+	for rows.Next() {
+		var student types.Student
+		if err := rows.Scan(s.studentColumns(&student)...); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		students = append(students, student)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
-	defer stmt.Close()
 
-	rows, err := stmt.Query()
+	return students, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsIter() → Call fn for every student matching filter, by id
+// -------------------------------------------------------------
+// Reads through the read connections in keyset batches; each batch's
+// rows are closed before fn sees them, so fn can query (or write) the
+// database without waiting on its own cursor.
+func (s *Sqlite) GetStudentsIter(ctx context.Context, filter map[string]any, fn func(types.Student) error) error {
+	return storage.IterateStudents(ctx, func(afterID int64, limit int) ([]types.Student, error) {
+		text, args := s.studentsWhere(filter, afterID).Limit(limit).Build()
+		rows, err := s.reads.QueryContext(ctx, text, args...)
+		if err != nil {
+			return nil, fmt.Errorf("query failed: %w", err)
+		}
+		return scanSelected(rows, s.studentColumns)
+	}, fn)
+}
+
+// -------------------------------------------------------------
+// GetStudentsAfter() → Keyset page: id > afterID, ordered by id
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentsAfter(afterID int64, limit int) ([]types.Student, error) {
+	rows, err := s.reads.Query(
+		"SELECT "+studentSelect+" FROM students WHERE tenant_id = ? AND deleted_at IS NULL AND id > ? ORDER BY id ASC LIMIT ?",
+		s.tenantID, afterID, limit,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -111,7 +268,7 @@ func (s *Sqlite) GetStudents() ([]types.Student, error) {
 	var students []types.Student
 	for rows.Next() {
 		var student types.Student
-		if err := rows.Scan(&student.ID, &student.Name, &student.Email, &student.Age); err != nil {
+		if err := rows.Scan(s.studentColumns(&student)...); err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
 		students = append(students, student)
@@ -124,33 +281,457 @@ func (s *Sqlite) GetStudents() ([]types.Student, error) {
 	return students, nil
 }
 
+// -------------------------------------------------------------
+// GetStudentsBefore() → Reverse keyset page: the last limit with id < beforeID
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentsBefore(beforeID int64, limit int) ([]types.Student, error) {
+	rows, err := s.reads.Query(
+		`SELECT id, public_id, name, email, age, phone, date_of_birth, gender, address, custom, verified FROM (
+			SELECT `+studentSelect+` FROM students WHERE tenant_id = ? AND deleted_at IS NULL AND id < ? ORDER BY id DESC LIMIT ?
+		) ORDER BY id ASC`,
+		s.tenantID, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var students []types.Student
+	for rows.Next() {
+		var student types.Student
+		if err := rows.Scan(s.studentColumns(&student)...); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		students = append(students, student)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return students, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsByIds() → One IN query for many ids, ordered by id
+// -------------------------------------------------------------
+// The ids travel as a single JSON array, so the statement text is the
+// same for any batch size and stays cached.
+func (s *Sqlite) GetStudentsByIds(ids []int64) ([]types.Student, error) {
+	list, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reads.Query(
+		`SELECT `+studentSelect+` FROM students
+		 WHERE tenant_id = ? AND deleted_at IS NULL AND id IN (SELECT value FROM json_each(?))
+		 ORDER BY id ASC`,
+		s.tenantID, string(list),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return scanSelected(rows, s.studentColumns)
+}
+
+// updateStudent replaces every field of a live student; arguments are a
+// StudentRow's fields, then id and tenant id. A new email (compared by
+// blind index when encrypted) clears verified.
+const updateStudent = `UPDATE students SET name = ?1, email = ?2, email_hash = ?3, age = ?4, phone = ?5, date_of_birth = ?6, gender = ?7, address = ?8, custom = ?9,
+	verified = verified AND COALESCE(email_hash, email) = COALESCE(?3, ?2)
+	WHERE id = ?10 AND tenant_id = ?11 AND deleted_at IS NULL`
+
 // -------------------------------------------------------------
 // UpdateStudentById() → Update student based on id
 // -------------------------------------------------------------
-func (s *Sqlite) UpdateStudentById(id int64, name, email string, age int) (types.Student, error) {
-	// Perform the update
-	query := `UPDATE students SET name = ?, email = ?, age = ? WHERE id = ?`
-	res, err := s.Db.Exec(query, name, email, age, id)
+func (s *Sqlite) UpdateStudentById(id int64, update types.Student) (types.Student, error) {
+	tx, err := s.Db.Begin()
 	if err != nil {
-		return types.Student{}, fmt.Errorf("failed to update student: %w", err)
+		return types.Student{}, fmt.Errorf("begin failed: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, _ := res.RowsAffected()
-	if rowsAffected == 0 {
-		return types.Student{}, fmt.Errorf("no student found with id: %d", id)
+	// The sealed columns are bound to the public id
+	err = tx.QueryRow("SELECT public_id FROM students WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL", id, s.tenantID).Scan(&update.PublicID)
+	if err == sql.ErrNoRows {
+		return types.Student{}, fmt.Errorf("%w with id: %d", storage.ErrStudentNotFound, id)
+	}
+	if err != nil {
+		return types.Student{}, fmt.Errorf("query failed: %w", err)
+	}
+	key, value := s.crypt.Lookup("email", update.Email)
+	var taken bool
+	err = tx.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM students WHERE tenant_id = ? AND "+key+" = ? AND id <> ?)",
+		s.tenantID, value, id,
+	).Scan(&taken)
+	if err != nil {
+		return types.Student{}, fmt.Errorf("query failed: %w", err)
+	}
+	if taken {
+		return types.Student{}, fmt.Errorf("failed to update student: %w: %s", storage.ErrEmailTaken, update.Email)
+	}
+	row, err := storage.SealStudent(s.crypt, update)
+	if err != nil {
+		return types.Student{}, err
+	}
+
+	// Perform the update
+	if _, err := tx.Exec(updateStudent, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, id, s.tenantID); err != nil {
+		return types.Student{}, fmt.Errorf("failed to update student: %w", err)
 	}
 
 	// Fetch the updated record
 	var student types.Student
-	err = s.Db.QueryRow(
-		`SELECT id, name, email, age FROM students WHERE id = ?`,
-		id,
-	).Scan(&student.ID, &student.Name, &student.Email, &student.Age)
+	err = tx.QueryRow(
+		"SELECT "+studentSelect+" FROM students WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL",
+		id, s.tenantID,
+	).Scan(s.studentColumns(&student)...)
 
 	if err != nil {
 		return types.Student{}, fmt.Errorf("failed to fetch updated student: %w", err)
 	}
 
-	fmt.Printf("✅ Updated student record (SQLite): %+v\n", student)
+	if err := s.recordEvent(tx, types.EventStudentUpdated, student); err != nil {
+		return types.Student{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Student{}, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return student, nil
+}
+
+// -------------------------------------------------------------
+// UpsertStudentByEmail() → INSERT ... ON CONFLICT (tenant_id, email) DO UPDATE
+// -------------------------------------------------------------
+// SQLite can't tell an insert from an update in RETURNING, so a read in
+// the same transaction checks first; the write itself is one statement.
+// With encryption on, the email's blind index is the conflict key.
+func (s *Sqlite) UpsertStudentByEmail(upsert types.Student) (types.Student, bool, error) {
+	key, value := s.crypt.Lookup("email", upsert.Email)
+
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return types.Student{}, false, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	var trashed bool
+	err = tx.QueryRow(
+		"SELECT public_id, deleted_at IS NOT NULL FROM students WHERE tenant_id = ? AND "+key+" = ?",
+		s.tenantID, value,
+	).Scan(&upsert.PublicID, &trashed)
+	created := err == sql.ErrNoRows
+	if err != nil && !created {
+		return types.Student{}, false, fmt.Errorf("query failed: %w", err)
+	}
+	if trashed {
+		return types.Student{}, false, storage.ErrStudentTrashed
+	}
+	// the sealed columns are bound to the public id, the existing one on update
+	if created {
+		upsert.PublicID = ulid.New()
+	}
+	row, err := storage.SealStudent(s.crypt, upsert)
+	if err != nil {
+		return types.Student{}, false, err
+	}
+
+	var student types.Student
+	err = tx.QueryRow(
+		`INSERT INTO students (tenant_id, public_id, name, email, email_hash, age, phone, date_of_birth, gender, address, custom, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (tenant_id, `+key+`) DO UPDATE SET name = excluded.name, age = excluded.age, phone = excluded.phone,
+		   date_of_birth = excluded.date_of_birth, gender = excluded.gender, address = excluded.address, custom = excluded.custom
+		 RETURNING `+studentSelect,
+		s.tenantID, upsert.PublicID, row.Name, row.Email, row.EmailHash, row.Age, row.Phone, row.DateOfBirth, row.Gender, row.Address, row.Custom, timestamp(time.Now()),
+	).Scan(s.studentColumns(&student)...)
+	if err != nil {
+		return types.Student{}, false, fmt.Errorf("upsert failed: %w", err)
+	}
+
+	event := types.EventStudentUpdated
+	if created {
+		event = types.EventStudentCreated
+	}
+	if err := s.recordEvent(tx, event, student); err != nil {
+		return types.Student{}, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Student{}, false, fmt.Errorf("commit failed: %w", err)
+	}
+	return student, created, nil
+}
+
+// -------------------------------------------------------------
+// DeleteStudentById() → Soft delete (sets deleted_at)
+// -------------------------------------------------------------
+func (s *Sqlite) DeleteStudentById(id int64) error {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`UPDATE students SET deleted_at = ? WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`,
+		timestamp(time.Now()), id, s.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete student: %w", err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("no student found with id: %d", id)
+	}
+
+	if err := s.recordEvent(tx, types.EventStudentDeleted, storage.DeletedPayload(id)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// GetDeletedStudents() → Soft-deleted students (the trash)
+// -------------------------------------------------------------
+func (s *Sqlite) GetDeletedStudents() ([]types.Student, error) {
+	rows, err := s.reads.Query(
+		"SELECT "+studentSelect+" FROM students WHERE tenant_id = ? AND deleted_at IS NOT NULL ORDER BY id ASC",
+		s.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var students []types.Student
+	for rows.Next() {
+		var student types.Student
+		if err := rows.Scan(s.studentColumns(&student)...); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		students = append(students, student)
+	}
+	return students, rows.Err()
+}
+
+// -------------------------------------------------------------
+// RestoreStudentById() → Undo a soft delete
+// -------------------------------------------------------------
+func (s *Sqlite) RestoreStudentById(id int64) (types.Student, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return types.Student{}, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	var student types.Student
+	err = tx.QueryRow(
+		`UPDATE students SET deleted_at = NULL WHERE id = ? AND tenant_id = ? AND deleted_at IS NOT NULL
+		 RETURNING `+studentSelect,
+		id, s.tenantID,
+	).Scan(s.studentColumns(&student)...)
+	if err == sql.ErrNoRows {
+		return types.Student{}, fmt.Errorf("no deleted student found with id: %d", id)
+	}
+	if err != nil {
+		return types.Student{}, fmt.Errorf("failed to restore student: %w", err)
+	}
+
+	if err := s.recordEvent(tx, types.EventStudentRestored, student); err != nil {
+		return types.Student{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Student{}, fmt.Errorf("commit failed: %w", err)
+	}
 	return student, nil
 }
+
+// -------------------------------------------------------------
+// PurgeDeletedBefore() → Hard delete old trash (all tenants)
+// -------------------------------------------------------------
+func (s *Sqlite) PurgeDeletedBefore(cutoff time.Time) (storage.Purged, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return storage.Purged{}, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 📎 Document keys first: with sqlite.foreign_keys on, the cascade takes the rows
+	var purged storage.Purged
+	rows, err := tx.Query(
+		`SELECT d.blob_key FROM documents d JOIN students st ON st.id = d.student_id
+		 WHERE st.deleted_at IS NOT NULL AND st.deleted_at < ? ORDER BY d.id ASC`,
+		timestamp(cutoff),
+	)
+	if err != nil {
+		return storage.Purged{}, fmt.Errorf("query documents failed: %w", err)
+	}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return storage.Purged{}, fmt.Errorf("failed to scan document: %w", err)
+		}
+		purged.DocumentKeys = append(purged.DocumentKeys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return storage.Purged{}, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	rows, err = tx.Query(
+		`DELETE FROM students WHERE deleted_at IS NOT NULL AND deleted_at < ? RETURNING id`,
+		timestamp(cutoff),
+	)
+	if err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge students: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return storage.Purged{}, fmt.Errorf("failed to purge students: %w", err)
+		}
+		purged.StudentIDs = append(purged.StudentIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge students: %w", err)
+	}
+	slices.Sort(purged.StudentIDs)
+
+	// ON DELETE CASCADE only fires with sqlite.foreign_keys on
+	if _, err := tx.Exec(`DELETE FROM documents WHERE student_id NOT IN (SELECT id FROM students)`); err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge documents: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM invoices WHERE student_id NOT IN (SELECT id FROM students)`); err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge invoices: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM verification_tokens WHERE student_id NOT IN (SELECT id FROM students)`); err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge verification tokens: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM student_credentials WHERE student_id NOT IN (SELECT id FROM students)`); err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge portal passwords: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM portal_sessions WHERE student_id NOT IN (SELECT id FROM students)`); err != nil {
+		return storage.Purged{}, fmt.Errorf("failed to purge portal sessions: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return storage.Purged{}, fmt.Errorf("commit failed: %w", err)
+	}
+	return purged, nil
+}
+
+// -------------------------------------------------------------
+// Tenants → Not tenant-scoped; used by the tenant middleware and admin API
+// -------------------------------------------------------------
+func (s *Sqlite) CreateTenant(slug string, name string) (int64, error) {
+	result, err := s.stmts.Exec("INSERT INTO tenants (slug, name) VALUES (?, ?)", slug, name)
+	if err != nil {
+		return 0, fmt.Errorf("insert tenant failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetTenantBySlug(slug string) (types.Tenant, error) {
+	var tenant types.Tenant
+	err := s.stmts.QueryRow("SELECT id, slug, name FROM tenants WHERE slug = ?", slug).
+		Scan(&tenant.ID, &tenant.Slug, &tenant.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return types.Tenant{}, fmt.Errorf("no tenant found with slug: %s", slug)
+		}
+		return types.Tenant{}, fmt.Errorf("query failed: %w", err)
+	}
+	return tenant, nil
+}
+
+func (s *Sqlite) GetTenants() ([]types.Tenant, error) {
+	rows, err := s.stmts.Query("SELECT id, slug, name FROM tenants ORDER BY id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []types.Tenant
+	for rows.Next() {
+		var tenant types.Tenant
+		if err := rows.Scan(&tenant.ID, &tenant.Slug, &tenant.Name); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+// -------------------------------------------------------------
+// GetStats() → Aggregates for the admin dashboard (tenant-scoped)
+// -------------------------------------------------------------
+func (s *Sqlite) GetStats(loc *time.Location) (types.Stats, error) {
+	now := time.Now()
+
+	var total int64
+	if err := s.reads.QueryRow("SELECT COUNT(*) FROM students WHERE tenant_id = ? AND deleted_at IS NULL", s.tenantID).Scan(&total); err != nil {
+		return types.Stats{}, fmt.Errorf("count failed: %w", err)
+	}
+
+	byAge, err := s.countBy(
+		"SELECT "+storage.AgeBucketCase(ageColumn)+" AS bucket, COUNT(*) FROM students WHERE tenant_id = ? AND deleted_at IS NULL GROUP BY bucket",
+		s.tenantID,
+	)
+	if err != nil {
+		return types.Stats{}, err
+	}
+
+	// 🕰️ SQLite knows no timezones: count per quarter hour (every UTC
+	// offset is a multiple of one) and fold those into days in loc
+	byQuarter, err := s.countBy(
+		"SELECT CAST(strftime('%s', created_at) AS INTEGER) / 900 * 900 AS quarter, COUNT(*) FROM students WHERE tenant_id = ? AND deleted_at IS NULL AND created_at >= ? GROUP BY quarter",
+		s.tenantID, timestamp(storage.StatsSince(now, loc)),
+	)
+	if err != nil {
+		return types.Stats{}, err
+	}
+	byDay := map[string]int64{}
+	for quarter, count := range byQuarter {
+		unix, err := strconv.ParseInt(quarter, 10, 64)
+		if err != nil {
+			return types.Stats{}, fmt.Errorf("parse quarter %q failed: %w", quarter, err)
+		}
+		byDay[time.Unix(unix, 0).In(loc).Format(clock.DayLayout)] += count
+	}
+
+	return storage.BuildStats(total, byAge, byDay, now, loc), nil
+}
+
+// countBy runs a `SELECT key, COUNT(*) ... GROUP BY key` query into a map
+func (s *Sqlite) countBy(query string, args ...any) (map[string]int64, error) {
+	rows, err := s.reads.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate query failed: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var key string
+		var count int64
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		counts[key] = count
+	}
+	return counts, rows.Err()
+}
+
+// studentColumns are the Scan targets of a full studentSelect row
+func (s *Sqlite) studentColumns(st *types.Student) []any {
+	return []any{&st.ID, &st.PublicID, &st.Name, storage.EmailField(s.crypt, st), &st.Age, storage.PhoneField(s.crypt, st), &st.DateOfBirth, &st.Gender, storage.AddressField(s.crypt, st), storage.CustomValues(&st.Custom), &st.Verified}
+}
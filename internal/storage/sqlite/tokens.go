@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const refreshTokenColumns = "id, api_key_id, family, token_hash, expires_at, created_at, used_at, revoked_at"
+
+// -------------------------------------------------------------
+// Refresh tokens → Stored by hash, one row per rotation
+// -------------------------------------------------------------
+func (s *Sqlite) CreateRefreshToken(token types.RefreshToken) (int64, error) {
+	result, err := s.stmts.Exec(
+		`INSERT INTO refresh_tokens (api_key_id, family, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?)`,
+		token.APIKeyID, token.Family, token.Hash, timestamp(token.ExpiresAt), timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert refresh token failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetRefreshTokenByHash(hash string) (types.RefreshToken, error) {
+	var token types.RefreshToken
+	var usedAt, revokedAt sql.NullTime
+	err := s.stmts.QueryRow("SELECT "+refreshTokenColumns+" FROM refresh_tokens WHERE token_hash = ?", hash).Scan(
+		&token.ID, &token.APIKeyID, &token.Family, &token.Hash, &token.ExpiresAt, &token.CreatedAt, &usedAt, &revokedAt,
+	)
+	if err == sql.ErrNoRows {
+		return types.RefreshToken{}, fmt.Errorf("no refresh token found")
+	}
+	if err != nil {
+		return types.RefreshToken{}, fmt.Errorf("scan refresh token failed: %w", err)
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
+
+// UseRefreshToken is a compare-and-set, so two concurrent refreshes with
+// the same token can't both win
+func (s *Sqlite) UseRefreshToken(id int64) (bool, error) {
+	res, err := s.stmts.Exec(`UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`, timestamp(time.Now()), id)
+	if err != nil {
+		return false, fmt.Errorf("use refresh token failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *Sqlite) RevokeRefreshFamily(family string) error {
+	_, err := s.stmts.Exec(`UPDATE refresh_tokens SET revoked_at = ? WHERE family = ? AND revoked_at IS NULL`, timestamp(time.Now()), family)
+	if err != nil {
+		return fmt.Errorf("revoke refresh tokens failed: %w", err)
+	}
+	return nil
+}
+
+// -------------------------------------------------------------
+// Revocation list → Access tokens denied until they expire anyway
+// -------------------------------------------------------------
+func (s *Sqlite) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	_, err := s.stmts.Exec(`INSERT INTO revoked_tokens (jti, expires_at) VALUES (?, ?) ON CONFLICT (jti) DO NOTHING`, jti, timestamp(expiresAt))
+	if err != nil {
+		return fmt.Errorf("revoke access token failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Sqlite) IsAccessTokenRevoked(jti string) (bool, error) {
+	var revoked bool
+	err := s.stmts.QueryRow(`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = ?)`, jti).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("query revoked tokens failed: %w", err)
+	}
+	return revoked, nil
+}
+
+func (s *Sqlite) PurgeExpiredTokens(now time.Time) (int64, error) {
+	cutoff := timestamp(now)
+	refresh, err := s.stmts.Exec(`DELETE FROM refresh_tokens WHERE expires_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge refresh tokens failed: %w", err)
+	}
+	revoked, err := s.stmts.Exec(`DELETE FROM revoked_tokens WHERE expires_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge revoked tokens failed: %w", err)
+	}
+	a, _ := refresh.RowsAffected()
+	b, _ := revoked.RowsAffected()
+	return a + b, nil
+}
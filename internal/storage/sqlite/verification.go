@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlq"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const verificationColumns = "id, tenant_id, student_id, token_hash, expires_at, created_at"
+
+// -------------------------------------------------------------
+// CreateVerificationToken() → Store a student's token, replacing theirs
+// -------------------------------------------------------------
+func (s *Sqlite) CreateVerificationToken(token types.VerificationToken) (int64, error) {
+	var id int64
+	err := s.stmts.QueryRow(
+		`INSERT INTO verification_tokens (tenant_id, student_id, token_hash, expires_at, created_at)
+		 SELECT tenant_id, id, ?, ?, ? FROM students WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL
+		 ON CONFLICT (student_id) DO UPDATE SET token_hash = excluded.token_hash, expires_at = excluded.expires_at, created_at = excluded.created_at
+		 RETURNING id`,
+		token.Hash, timestamp(token.ExpiresAt), timestamp(time.Now()), token.StudentID, s.tenantID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no student found with id: %d", token.StudentID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("insert verification token failed: %w", err)
+	}
+	return id, nil
+}
+
+// -------------------------------------------------------------
+// VerifyStudent() → Use up a token and mark its student verified
+// -------------------------------------------------------------
+// A token whose student is in the trash is kept, so the link works again
+// once the student is restored.
+func (s *Sqlite) VerifyStudent(hash string, now time.Time) (types.VerificationToken, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return types.VerificationToken{}, fmt.Errorf("begin failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	var token types.VerificationToken
+	err = tx.QueryRow("SELECT "+verificationColumns+" FROM verification_tokens WHERE token_hash = ?", hash).Scan(
+		&token.ID, &token.TenantID, &token.StudentID, &token.Hash, &token.ExpiresAt, &token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return types.VerificationToken{}, storage.ErrVerificationInvalid
+	}
+	if err != nil {
+		return types.VerificationToken{}, fmt.Errorf("scan verification token failed: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM verification_tokens WHERE id = ?`, token.ID); err != nil {
+		return types.VerificationToken{}, fmt.Errorf("delete verification token failed: %w", err)
+	}
+
+	if token.ExpiresAt.Before(now) {
+		if err := tx.Commit(); err != nil {
+			return types.VerificationToken{}, fmt.Errorf("commit failed: %w", err)
+		}
+		return types.VerificationToken{}, storage.ErrVerificationExpired
+	}
+	res, err := tx.Exec(
+		`UPDATE students SET verified = 1 WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`,
+		token.StudentID, token.TenantID,
+	)
+	if err != nil {
+		return types.VerificationToken{}, fmt.Errorf("verify student failed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return types.VerificationToken{}, storage.ErrVerificationInvalid
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.VerificationToken{}, fmt.Errorf("commit failed: %w", err)
+	}
+	return token, nil
+}
+
+// -------------------------------------------------------------
+// GetStudentsByVerified() → Live students by verification status, by id
+// -------------------------------------------------------------
+func (s *Sqlite) GetStudentsByVerified(verified bool, afterID int64, limit int) ([]types.Student, error) {
+	text, args := s.studentsByVerified(verified, afterID).Limit(limit).Build()
+	rows, err := s.reads.Query(text, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return scanSelected(rows, s.studentColumns)
+}
+
+// studentsByVerified selects the live students after afterID by
+// verification status, by id
+func (s *Sqlite) studentsByVerified(verified bool, afterID int64) *sqlq.SelectBuilder {
+	return s.liveStudents(storage.StudentFields...).
+		Where("verified = ?", verified).
+		Where("id > ?", afterID).
+		OrderBy("id ASC")
+}
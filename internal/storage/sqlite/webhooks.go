@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+const (
+	webhookColumns  = "id, url, events, secret, created_at"
+	deliveryColumns = "id, webhook_id, event, payload, status, attempts, response_status, last_error, created_at, delivered_at"
+)
+
+// -------------------------------------------------------------
+// CreateWebhook() → Subscribe a URL for the current tenant
+// -------------------------------------------------------------
+func (s *Sqlite) CreateWebhook(hook types.Webhook) (int64, error) {
+	result, err := s.stmts.Exec(
+		`INSERT INTO webhooks (tenant_id, url, events, secret, created_at) VALUES (?, ?, ?, ?, ?)`,
+		s.tenantID, hook.URL, strings.Join(hook.Events, ","), hook.Secret, timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert webhook failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetWebhooks() ([]types.Webhook, error) {
+	rows, err := s.stmts.Query("SELECT "+webhookColumns+" FROM webhooks WHERE tenant_id = ? ORDER BY id ASC", s.tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("query webhooks failed: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []types.Webhook
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+func (s *Sqlite) GetWebhookById(id int64) (types.Webhook, error) {
+	row := s.stmts.QueryRow("SELECT "+webhookColumns+" FROM webhooks WHERE id = ? AND tenant_id = ?", id, s.tenantID)
+	hook, err := scanWebhook(row)
+	if err == sql.ErrNoRows {
+		return types.Webhook{}, fmt.Errorf("no webhook found with id: %d", id)
+	}
+	return hook, err
+}
+
+// -------------------------------------------------------------
+// DeleteWebhookById() → Remove the subscription and its history
+// -------------------------------------------------------------
+func (s *Sqlite) DeleteWebhookById(id int64) error {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM webhooks WHERE id = ? AND tenant_id = ?`, id, s.tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no webhook found with id: %d", id)
+	}
+
+	// ON DELETE CASCADE only fires with sqlite.foreign_keys on
+	if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return tx.Commit()
+}
+
+// -------------------------------------------------------------
+// Deliveries → One row per event per webhook
+// -------------------------------------------------------------
+func (s *Sqlite) CreateWebhookDelivery(delivery types.WebhookDelivery) (int64, error) {
+	result, err := s.stmts.Exec(
+		`INSERT INTO webhook_deliveries (tenant_id, webhook_id, event, payload, status, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		s.tenantID, delivery.WebhookID, delivery.Event, string(delivery.Payload), delivery.Status, timestamp(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert webhook delivery failed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetWebhookDeliveryById(id int64) (types.WebhookDelivery, error) {
+	row := s.stmts.QueryRow("SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = ? AND tenant_id = ?", id, s.tenantID)
+	delivery, err := scanDelivery(row)
+	if err == sql.ErrNoRows {
+		return types.WebhookDelivery{}, fmt.Errorf("no webhook delivery found with id: %d", id)
+	}
+	return delivery, err
+}
+
+func (s *Sqlite) UpdateWebhookDelivery(delivery types.WebhookDelivery) error {
+	var deliveredAt any
+	if delivery.DeliveredAt != nil {
+		deliveredAt = timestamp(*delivery.DeliveredAt)
+	}
+	_, err := s.stmts.Exec(
+		`UPDATE webhook_deliveries
+		 SET status = ?, attempts = ?, response_status = ?, last_error = ?, delivered_at = ?
+		 WHERE id = ? AND tenant_id = ?`,
+		delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError, deliveredAt,
+		delivery.ID, s.tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("update webhook delivery failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Sqlite) GetWebhookDeliveries(webhookID int64, limit int) ([]types.WebhookDelivery, error) {
+	rows, err := s.stmts.Query(
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE webhook_id = ? AND tenant_id = ? ORDER BY id DESC LIMIT ?",
+		webhookID, s.tenantID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook deliveries failed: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []types.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// scanWebhook reads webhookColumns from a *sql.Row or *sql.Rows
+func scanWebhook(row interface{ Scan(...any) error }) (types.Webhook, error) {
+	var hook types.Webhook
+	var events string
+	err := row.Scan(&hook.ID, &hook.URL, &events, &hook.Secret, &hook.CreatedAt)
+	if err == sql.ErrNoRows {
+		return types.Webhook{}, err
+	}
+	if err != nil {
+		return types.Webhook{}, fmt.Errorf("scan webhook failed: %w", err)
+	}
+	hook.Events = strings.Split(events, ",")
+	return hook, nil
+}
+
+// scanDelivery reads deliveryColumns from a *sql.Row or *sql.Rows
+func scanDelivery(row interface{ Scan(...any) error }) (types.WebhookDelivery, error) {
+	var d types.WebhookDelivery
+	var payload string
+	var deliveredAt sql.NullTime
+	err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &payload, &d.Status, &d.Attempts, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &deliveredAt)
+	if err == sql.ErrNoRows {
+		return types.WebhookDelivery{}, err
+	}
+	if err != nil {
+		return types.WebhookDelivery{}, fmt.Errorf("scan webhook delivery failed: %w", err)
+	}
+	d.Payload = []byte(payload)
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return d, nil
+}
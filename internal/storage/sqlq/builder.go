@@ -0,0 +1,123 @@
+package sqlq
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Dialect is the driver's placeholder style: "?" (sqlite) or "$n" (postgres).
+type Dialect int
+
+const (
+	Question Dialect = iota
+	Dollar
+)
+
+// -------------------------------------------------------------
+// Bind() → Rewrite ? placeholders for the dialect
+// -------------------------------------------------------------
+// Queries are written with ? everywhere; Dollar numbers them $1, $2, ...
+// Question marks inside string literals are not expected.
+func (d Dialect) Bind(query string) string {
+	if d == Question {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SelectBuilder assembles a SELECT whose filters depend on the request.
+// Values always travel as arguments; only identifiers (columns, table,
+// ORDER BY) are spliced in, and those must be constants or whitelisted.
+//
+//	query, args := sqlq.Select(sqlq.Dollar, "id", "status").From("jobs").
+//		WhereIf(status != "", "status = ?", status).
+//		OrderBy("id DESC").Limit(50).Build()
+type SelectBuilder struct {
+	dialect Dialect
+	columns []string
+	table   string
+	where   []string
+	args    []any
+	orderBy string
+	limit   int
+}
+
+func Select(dialect Dialect, columns ...string) *SelectBuilder {
+	return &SelectBuilder{dialect: dialect, columns: columns}
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+// Where adds a condition (ANDed with the others) using ? placeholders
+func (b *SelectBuilder) Where(cond string, args ...any) *SelectBuilder {
+	b.where = append(b.where, cond)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// WhereIf adds the condition only when ok, e.g. for optional filters
+func (b *SelectBuilder) WhereIf(ok bool, cond string, args ...any) *SelectBuilder {
+	if ok {
+		return b.Where(cond, args...)
+	}
+	return b
+}
+
+func (b *SelectBuilder) OrderBy(order string) *SelectBuilder {
+	b.orderBy = order
+	return b
+}
+
+// Count selects COUNT(*) of the same rows instead: the columns, ORDER BY
+// and LIMIT are dropped
+func (b *SelectBuilder) Count() *SelectBuilder {
+	b.columns = []string{"COUNT(*)"}
+	b.orderBy = ""
+	b.limit = 0
+	return b
+}
+
+// Limit of 0 means no limit
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+// -------------------------------------------------------------
+// Build() → SQL for the dialect plus its arguments, in order
+// -------------------------------------------------------------
+func (b *SelectBuilder) Build() (string, []any) {
+	var q strings.Builder
+	q.WriteString("SELECT ")
+	q.WriteString(strings.Join(b.columns, ", "))
+	q.WriteString(" FROM ")
+	q.WriteString(b.table)
+	if len(b.where) > 0 {
+		q.WriteString(" WHERE ")
+		q.WriteString(strings.Join(b.where, " AND "))
+	}
+	if b.orderBy != "" {
+		q.WriteString(" ORDER BY ")
+		q.WriteString(b.orderBy)
+	}
+	args := slices.Clip(b.args)
+	if b.limit > 0 {
+		q.WriteString(" LIMIT ?")
+		args = append(args, b.limit)
+	}
+	return b.dialect.Bind(q.String()), args
+}
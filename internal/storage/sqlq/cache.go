@@ -0,0 +1,120 @@
+// Package sqlq holds the SQL backends' shared plumbing: a prepared-statement
+// cache and a small SELECT builder for queries with optional filters.
+package sqlq
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// maxStatements caps the cache; queries beyond it run unprepared. Every
+// query in this repo is a constant or built from whitelisted parts, so
+// the cap is a safety net rather than an eviction policy.
+const maxStatements = 256
+
+// Cache prepares each distinct query once per pool and reuses the
+// *sql.Stmt afterwards (database/sql re-prepares it per connection as
+// needed). It is safe for concurrent use and is shared by tenant views.
+type Cache struct {
+	db    *sql.DB
+	mu    sync.RWMutex
+	stmts map[string]*sql.Stmt
+}
+
+func NewCache(db *sql.DB) *Cache {
+	return &Cache{db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+// DB is the underlying pool, for transactions and migrations
+func (c *Cache) DB() *sql.DB {
+	return c.db
+}
+
+// -------------------------------------------------------------
+// Prepare() → The cached statement for query, preparing it on first use
+// -------------------------------------------------------------
+// Returns nil (and no error) once the cache is full; callers fall back to
+// the pool.
+func (c *Cache) Prepare(query string) (*sql.Stmt, error) {
+	c.mu.RLock()
+	stmt, ok := c.stmts[query]
+	c.mu.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+	if len(c.stmts) >= maxStatements {
+		return nil, nil
+	}
+	stmt, err := c.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	c.stmts[query] = stmt
+	return stmt, nil
+}
+
+func (c *Cache) Exec(query string, args ...any) (sql.Result, error) {
+	stmt, err := c.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.db.Exec(query, args...)
+	}
+	return stmt.Exec(args...)
+}
+
+func (c *Cache) Query(query string, args ...any) (*sql.Rows, error) {
+	stmt, err := c.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.db.Query(query, args...)
+	}
+	return stmt.Query(args...)
+}
+
+// QueryContext is Query, cancelled with ctx
+func (c *Cache) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := c.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.db.QueryContext(ctx, query, args...)
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// QueryRow can't carry a prepare error in *sql.Row, so on failure it runs
+// the query unprepared and lets that report the problem.
+func (c *Cache) QueryRow(query string, args ...any) *sql.Row {
+	stmt, err := c.Prepare(query)
+	if err != nil || stmt == nil {
+		return c.db.QueryRow(query, args...)
+	}
+	return stmt.QueryRow(args...)
+}
+
+// Close releases every cached statement
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var first error
+	for query, stmt := range c.stmts {
+		if err := stmt.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(c.stmts, query)
+	}
+	return first
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/clock"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// StatsDays is how far back CreatedPerDay reaches
+const StatsDays = 30
+
+// StatsStore computes dashboard aggregates for the current tenant scope.
+// CreatedPerDay counts by days in loc (the display timezone).
+type StatsStore interface {
+	GetStats(loc *time.Location) (types.Stats, error)
+}
+
+// AgeBuckets are the histogram bins used by every backend (inclusive bounds).
+var AgeBuckets = []types.AgeBucket{
+	{Label: "1-12", Min: 1, Max: 12},
+	{Label: "13-17", Min: 13, Max: 17},
+	{Label: "18-24", Min: 18, Max: 24},
+	{Label: "25-34", Min: 25, Max: 34},
+	{Label: "35-49", Min: 35, Max: 49},
+	{Label: "50-64", Min: 50, Max: 64},
+	{Label: "65+", Min: 65, Max: 100},
+}
+
+// -------------------------------------------------------------
+// AgeBucketCase() → SQL CASE mapping `age` to a bucket label
+// -------------------------------------------------------------
+// Built only from the constants above, so safe to inline in queries.
+func AgeBucketCase(column string) string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, bucket := range AgeBuckets {
+		fmt.Fprintf(&b, " WHEN %s BETWEEN %d AND %d THEN '%s'", column, bucket.Min, bucket.Max, bucket.Label)
+	}
+	b.WriteString(" ELSE 'other' END")
+	return b.String()
+}
+
+// StatsSince is the start of the first day in loc included in CreatedPerDay.
+func StatsSince(now time.Time, loc *time.Location) time.Time {
+	return clock.Day(now, loc).AddDate(0, 0, -(StatsDays - 1))
+}
+
+// -------------------------------------------------------------
+// BuildStats() → Shape raw backend counts into a zero-filled types.Stats
+// -------------------------------------------------------------
+// byDay is keyed by clock.DayLayout dates in loc.
+func BuildStats(total int64, byLabel map[string]int64, byDay map[string]int64, now time.Time, loc *time.Location) types.Stats {
+	stats := types.Stats{TotalStudents: total, Timezone: loc.String()}
+
+	for _, bucket := range AgeBuckets {
+		bucket.Count = byLabel[bucket.Label]
+		stats.ByAge = append(stats.ByAge, bucket)
+	}
+
+	day := StatsSince(now, loc)
+	for i := 0; i < StatsDays; i++ {
+		date := day.Format(clock.DayLayout)
+		stats.CreatedPerDay = append(stats.CreatedPerDay, types.DayCount{Date: date, Count: byDay[date]})
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return stats
+}
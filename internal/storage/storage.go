@@ -1,10 +1,169 @@
 package storage
 
-import "github.com/manish-npx/go-student-api/internal/types"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// DefaultTenantID owns every record created before tenancy was enabled
+// (and all records when it stays disabled).
+const DefaultTenantID int64 = 1
+
+// ErrStudentNotFound is wrapped by GetStudentById and GetStudentByIdFields
+// when the student doesn't exist, is trashed or belongs to another tenant
+var ErrStudentNotFound = errors.New("no student found")
+
+// ErrEmailTaken is wrapped by UpdateStudentById when another student of the
+// tenant already has the new email
+var ErrEmailTaken = errors.New("email already exists")
 
 type Storage interface {
-	CreateStudent(name string, email string, age int) (int64, error)
+	CreateStudent(student types.Student) (int64, error)
 	GetStudentById(id int64) (types.Student, error)
 	GetStudents() ([]types.Student, error)
-	UpdateStudentById(id int64, name string, email string, age int) (types.Student, error)
+	// GetStudentsIter calls fn for every student whose custom values equal
+	// filter's (nil for all), in id order, stopping at fn's first error or
+	// when ctx is done. Unlike GetStudents it never holds the whole result:
+	// rows are read in batches of IterBatchSize, with no query open while
+	// fn runs, so fn may use the storage itself.
+	GetStudentsIter(ctx context.Context, filter map[string]any, fn func(types.Student) error) error
+	// UpdateStudentById replaces every field of the student
+	UpdateStudentById(id int64, student types.Student) (types.Student, error)
+	// DeleteStudentById soft-deletes: the row is hidden until restored or purged
+	DeleteStudentById(id int64) error
+}
+
+// TrashStore exposes soft-deleted students.
+type TrashStore interface {
+	GetDeletedStudents() ([]types.Student, error)
+	RestoreStudentById(id int64) (types.Student, error)
+	// PurgeDeletedBefore hard-deletes rows soft-deleted before cutoff,
+	// across all tenants, and returns what was removed
+	PurgeDeletedBefore(cutoff time.Time) (Purged, error)
+}
+
+// Purged is what PurgeDeletedBefore removed. The students' files live in
+// the blob store, which the caller cleans up (see trash.Purge).
+type Purged struct {
+	StudentIDs []int64
+	// DocumentKeys are the blob keys of the purged students' documents
+	DocumentKeys []string
+}
+
+// TenantScoper returns a view of the backend where every query is
+// restricted to one tenant. Backends start scoped to DefaultTenantID.
+type TenantScoper interface {
+	ForTenant(tenantID int64) Storage
+}
+
+// TenantStore manages the tenants table itself (not tenant-scoped).
+type TenantStore interface {
+	CreateTenant(slug string, name string) (int64, error)
+	GetTenantBySlug(slug string) (types.Tenant, error)
+	GetTenants() ([]types.Tenant, error)
+}
+
+// DocumentStore keeps attachment records; the files themselves live in a
+// blob.Store. Queries are tenant-scoped like student queries.
+type DocumentStore interface {
+	CreateDocument(doc types.Document) (int64, error)
+	GetDocuments(studentID int64) ([]types.Document, error)
+	// GetStudentDocuments returns the documents of each student, oldest
+	// first, in one query; students without documents are left out
+	GetStudentDocuments(studentIDs []int64) (map[int64][]types.Document, error)
+	GetDocumentById(studentID int64, id int64) (types.Document, error)
+	DeleteDocumentById(studentID int64, id int64) error
+}
+
+// JobStore persists the background job queue (not tenant-scoped).
+type JobStore interface {
+	EnqueueJob(job types.Job) (int64, error)
+	// ClaimJob atomically marks the oldest due queued job as running and
+	// returns it; ok is false when nothing is due
+	ClaimJob(now time.Time) (job types.Job, ok bool, err error)
+	CompleteJob(id int64) error
+	// FailJob requeues the job at retryAt, or marks it failed when retryAt is nil
+	FailJob(id int64, lastError string, retryAt *time.Time) error
+	// RequeueJob puts a failed job back in the queue with a fresh attempt budget
+	RequeueJob(id int64) (types.Job, error)
+	// ResetRunningJobs requeues jobs left running by a crashed process
+	ResetRunningJobs() (int64, error)
+	GetJobById(id int64) (types.Job, error)
+	// GetJobs lists newest first; empty status means every status
+	GetJobs(status string, limit int) ([]types.Job, error)
+	// PurgeFinishedJobs deletes succeeded and failed jobs finished before
+	// cutoff, payloads (email bodies included) with them
+	PurgeFinishedJobs(cutoff time.Time) (int64, error)
+}
+
+// WebhookStore keeps webhook subscriptions and their delivery history.
+// Queries are tenant-scoped like student queries.
+type WebhookStore interface {
+	CreateWebhook(hook types.Webhook) (int64, error)
+	GetWebhooks() ([]types.Webhook, error)
+	GetWebhookById(id int64) (types.Webhook, error)
+	// DeleteWebhookById also removes the webhook's delivery history
+	DeleteWebhookById(id int64) error
+	CreateWebhookDelivery(delivery types.WebhookDelivery) (int64, error)
+	GetWebhookDeliveryById(id int64) (types.WebhookDelivery, error)
+	// UpdateWebhookDelivery saves status, attempts, response and error
+	UpdateWebhookDelivery(delivery types.WebhookDelivery) error
+	// GetWebhookDeliveries lists newest first
+	GetWebhookDeliveries(webhookID int64, limit int) ([]types.WebhookDelivery, error)
+}
+
+// APIKeyStore keeps API keys by hash (not tenant-scoped).
+type APIKeyStore interface {
+	CreateAPIKey(key types.APIKey) (int64, error)
+	// GetAPIKeyByHash also returns revoked and expired keys; callers check
+	GetAPIKeyByHash(hash string) (types.APIKey, error)
+	GetAPIKeyById(id int64) (types.APIKey, error)
+	GetAPIKeys() ([]types.APIKey, error)
+	// RevokeAPIKey stamps revoked_at and revokes the refresh tokens issued
+	// for the key; an already revoked key is an error
+	RevokeAPIKey(id int64) (types.APIKey, error)
+}
+
+// TokenStore keeps refresh tokens by hash and the revocation list of
+// access tokens (not tenant-scoped).
+type TokenStore interface {
+	CreateRefreshToken(token types.RefreshToken) (int64, error)
+	GetRefreshTokenByHash(hash string) (types.RefreshToken, error)
+	// UseRefreshToken marks the token used; false when it already was (a replay)
+	UseRefreshToken(id int64) (bool, error)
+	// RevokeRefreshFamily revokes every token rotated from the same login
+	RevokeRefreshFamily(family string) error
+	// RevokeAccessToken lists jti as revoked until expiresAt
+	RevokeAccessToken(jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	// PurgeExpiredTokens drops refresh tokens and revocations expired before now
+	PurgeExpiredTokens(now time.Time) (int64, error)
+}
+
+// QuotaStore counts requests per subject and UTC day and keeps per-subject
+// limit overrides (not tenant-scoped).
+type QuotaStore interface {
+	// AddQuotaUsage counts one request (and a write when write) and returns the day's totals
+	AddQuotaUsage(subject, day string, write bool) (types.QuotaUsage, error)
+	GetQuotaUsage(subject, day string) (types.QuotaUsage, error)
+	// GetQuotaLimit returns ok=false when subject has no override
+	GetQuotaLimit(subject string) (types.QuotaLimit, bool, error)
+	GetQuotaLimits() ([]types.QuotaLimit, error)
+	SetQuotaLimit(limit types.QuotaLimit) error
+	// DeleteQuotaLimit returns subject to the defaults; false when it had no override
+	DeleteQuotaLimit(subject string) (bool, error)
+	// PurgeQuotaUsage drops the counters of days before day
+	PurgeQuotaUsage(day string) (int64, error)
+}
+
+// EncryptionStore re-seals encrypted student columns after a key rotation
+// (not tenant-scoped).
+type EncryptionStore interface {
+	// ReencryptStudents seals every value not yet under the active key,
+	// including plain values stored before encryption was enabled, and
+	// returns how many rows changed
+	ReencryptStudents() (int64, error)
 }
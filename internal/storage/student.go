@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/fieldcrypt"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Sealed student columns, as bound into their values: each is sealed for
+// its column and the student's public_id (see fieldcrypt.Seal)
+const (
+	SealedEmail   = "students.email"
+	SealedPhone   = "students.phone"
+	SealedAddress = "students.address"
+)
+
+// StudentRow is a student as the SQL backends write it: PII sealed, the
+// address as JSON and age derived from date_of_birth.
+type StudentRow struct {
+	Name  string
+	Email string
+	// EmailHash is the blind index of the email; nil without encryption
+	EmailHash   *string
+	Age         int
+	Phone       string
+	DateOfBirth string
+	Gender      string
+	Address     string
+	// Custom is the custom attributes as a JSON object ("{}" when none)
+	Custom string
+}
+
+// -------------------------------------------------------------
+// SealStudent() → Column values for an INSERT or UPDATE of student
+// -------------------------------------------------------------
+// Empty optional fields stay empty strings rather than sealed blanks.
+// student.PublicID must be set: the sealed values are bound to it.
+func SealStudent(c *fieldcrypt.Cipher, student types.Student) (StudentRow, error) {
+	student.DeriveAge(time.Now())
+	row := StudentRow{
+		Name:        student.Name,
+		EmailHash:   c.Index("email", student.Email),
+		Age:         student.Age,
+		DateOfBirth: student.DateOfBirth,
+		Gender:      student.Gender,
+	}
+
+	var err error
+	if row.Email, err = c.Seal(SealedEmail, student.PublicID, student.Email); err != nil {
+		return StudentRow{}, err
+	}
+	if student.Phone != "" {
+		if row.Phone, err = c.Seal(SealedPhone, student.PublicID, student.Phone); err != nil {
+			return StudentRow{}, err
+		}
+	}
+	if row.Custom, err = CustomJSON(student.Custom); err != nil {
+		return StudentRow{}, err
+	}
+	if student.Address != nil {
+		address, err := json.Marshal(student.Address)
+		if err != nil {
+			return StudentRow{}, err
+		}
+		if row.Address, err = c.Seal(SealedAddress, student.PublicID, string(address)); err != nil {
+			return StudentRow{}, err
+		}
+	}
+	return row, nil
+}
+
+// EmailField is a Scan target that opens the email column into st.Email;
+// like PhoneField and AddressField it reads st.PublicID, so public_id must
+// be selected before it
+func EmailField(c *fieldcrypt.Cipher, st *types.Student) sql.Scanner {
+	return c.Field(SealedEmail, publicID(st), &st.Email)
+}
+
+// PhoneField is a Scan target that opens the phone column into st.Phone
+func PhoneField(c *fieldcrypt.Cipher, st *types.Student) sql.Scanner {
+	return c.Field(SealedPhone, publicID(st), &st.Phone)
+}
+
+// AddressField is a Scan target that opens the address column into
+// st.Address (nil when the student has no address)
+func AddressField(c *fieldcrypt.Cipher, st *types.Student) sql.Scanner {
+	return &addressField{c: c, st: st}
+}
+
+// publicID reads st.PublicID when the sealed column is scanned
+func publicID(st *types.Student) func() string {
+	return func() string { return st.PublicID }
+}
+
+type addressField struct {
+	c  *fieldcrypt.Cipher
+	st *types.Student
+}
+
+func (f *addressField) Scan(src any) error {
+	var plain string
+	if err := f.c.Field(SealedAddress, publicID(f.st), &plain).Scan(src); err != nil {
+		return err
+	}
+	if plain == "" {
+		f.st.Address = nil
+		return nil
+	}
+	var address types.Address
+	if err := json.Unmarshal([]byte(plain), &address); err != nil {
+		return fmt.Errorf("malformed stored address: %w", err)
+	}
+	f.st.Address = &address
+	return nil
+}
+
+// CustomJSON encodes custom attributes for the custom column
+func CustomJSON(values map[string]any) (string, error) {
+	if len(values) == 0 {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("encode custom fields failed: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// CustomValues is a Scan target that decodes the custom column into dst
+// (nil when the student has no custom attributes)
+func CustomValues(dst *map[string]any) sql.Scanner {
+	return &customValues{dst: dst}
+}
+
+type customValues struct {
+	dst *map[string]any
+}
+
+func (f *customValues) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	case nil:
+		*f.dst = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported custom column type %T", src)
+	}
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return fmt.Errorf("malformed stored custom fields: %w", err)
+	}
+	if len(values) == 0 {
+		values = nil
+	}
+	*f.dst = values
+	return nil
+}
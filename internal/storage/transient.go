@@ -0,0 +1,30 @@
+package storage
+
+import "errors"
+
+// ErrTransient marks an error worth retrying: the same call may well
+// succeed in a moment. Wrap it (%w) to mark an error as transient.
+var ErrTransient = errors.New("transient storage error")
+
+// TransientStore tells apart the driver errors worth retrying (a
+// serialization failure, a dropped connection) from ones that would only
+// fail again.
+type TransientStore interface {
+	// IsTransient reports whether err means the call had no effect and
+	// may succeed if made again
+	IsTransient(err error) bool
+}
+
+// -------------------------------------------------------------
+// IsTransient() → err is ErrTransient or backend classifies it as transient
+// -------------------------------------------------------------
+func IsTransient(backend Storage, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrTransient) {
+		return true
+	}
+	classifier, ok := As[TransientStore](backend)
+	return ok && classifier.IsTransient(err)
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// ErrStudentTrashed means the email belongs to a soft-deleted student; it
+// must be restored (or purged) before the email can be used again.
+var ErrStudentTrashed = errors.New("a student with this email is in the trash")
+
+// UpsertStore inserts or updates a student keyed by email in one
+// statement, for roster syncs that don't track ids.
+type UpsertStore interface {
+	// UpsertStudentByEmail returns the stored student and whether it was
+	// created (false: an existing one was updated)
+	UpsertStudentByEmail(student types.Student) (types.Student, bool, error)
+}
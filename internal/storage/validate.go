@@ -0,0 +1,10 @@
+package storage
+
+// UniquenessStore answers a write's uniqueness checks without making the
+// write, for validate-only requests that must not persist anything.
+type UniquenessStore interface {
+	// EmailTaken reports whether another student of the tenant, trashed
+	// ones included, already has the email; exceptID is the student being
+	// updated (0 when creating)
+	EmailTaken(email string, exceptID int64) (bool, error)
+}
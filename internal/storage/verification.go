@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Verification token outcomes
+var (
+	// ErrVerificationInvalid means no token has this hash: it never
+	// existed, was used, was replaced by a newer one or its student is gone
+	ErrVerificationInvalid = errors.New("unknown or already used verification link")
+	ErrVerificationExpired = errors.New("verification link has expired, ask for a new one")
+)
+
+// VerificationStore keeps the email verification tokens sent to students
+// and their verified flag. Tokens are created and students listed
+// tenant-scoped like student queries; VerifyStudent finds a token whatever
+// the tenant, since the link in the email carries none.
+type VerificationStore interface {
+	// CreateVerificationToken stores a token for one of the tenant's live
+	// students, replacing any earlier one of theirs
+	CreateVerificationToken(token types.VerificationToken) (int64, error)
+	// VerifyStudent uses up the token with this hash and marks its student
+	// verified. An expired token is removed too and gives
+	// ErrVerificationExpired; an unknown one ErrVerificationInvalid.
+	VerifyStudent(hash string, now time.Time) (types.VerificationToken, error)
+	// GetStudentsByVerified returns live students with id > afterID whose
+	// verified flag equals verified, ordered by id; limit 0 means no limit
+	GetStudentsByVerified(verified bool, afterID int64, limit int) ([]types.Student, error)
+}
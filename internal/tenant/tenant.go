@@ -0,0 +1,44 @@
+// Package tenant carries the resolved tenant through the request context
+// and hands handlers a storage view restricted to it.
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+type ctxKey struct{}
+
+func WithTenant(ctx context.Context, t types.Tenant) context.Context {
+	return context.WithValue(ctx, ctxKey{}, t)
+}
+
+func FromContext(ctx context.Context) (types.Tenant, bool) {
+	t, ok := ctx.Value(ctxKey{}).(types.Tenant)
+	return t, ok
+}
+
+// -------------------------------------------------------------
+// Scope() → Storage restricted to the request's tenant
+// -------------------------------------------------------------
+// Without a tenant in ctx (tenancy disabled) the backend's default scope is
+// used, which is storage.DefaultTenantID. Events the view records in the
+// outbox carry the request's trace, so webhooks still join it.
+func Scope(ctx context.Context, s storage.Storage) storage.Storage {
+	if t, ok := FromContext(ctx); ok {
+		if scoper, ok := storage.As[storage.TenantScoper](s); ok {
+			s = scoper.ForTenant(t.ID)
+		}
+	}
+	if outbox, ok := storage.As[storage.OutboxStore](s); ok {
+		if trace := httpclient.TraceFrom(ctx); trace != (httpclient.Trace{}) {
+			encoded, _ := json.Marshal(trace)
+			s = outbox.WithTrace(encoded)
+		}
+	}
+	return s
+}
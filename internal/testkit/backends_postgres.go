@@ -0,0 +1,45 @@
+//go:build postgres
+
+package testkit
+
+import (
+	"testing"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/postgres"
+)
+
+// The postgres backend runs against the server the PG_* variables point
+// at. Its tables are emptied before every benchmark and scenario, so use a
+// scratch database.
+func init() {
+	extraBackends = append(extraBackends, Backend{Name: "postgres", Open: openPostgres})
+}
+
+// openPostgres connects with the PG_* variables and empties every table
+// but the default tenant, skipping tb when PG_HOST is unset
+func openPostgres(tb testing.TB) storage.Storage {
+	tb.Helper()
+	var pg config.Postgres
+	if err := cleanenv.ReadEnv(&pg); err != nil {
+		tb.Fatalf("read PG_* env: %v", err)
+	}
+	if pg.Host == "" {
+		tb.Skip("PG_HOST not set")
+	}
+	store, err := postgres.New(config.Config{Postgres: pg})
+	if err != nil {
+		tb.Fatalf("open postgres: %v", err)
+	}
+	tb.Cleanup(func() { store.DB.Close() })
+	if _, err := store.DB.Exec(`TRUNCATE students, guardians, api_keys, refresh_tokens, revoked_tokens, jobs, webhooks, webhook_deliveries,
+		outbox, audit_log, custom_fields, invoices, quota_limits, quota_usage RESTART IDENTITY CASCADE`); err != nil {
+		tb.Fatalf("truncate tables: %v", err)
+	}
+	if _, err := store.DB.Exec(`DELETE FROM tenants WHERE id <> 1`); err != nil {
+		tb.Fatalf("delete tenants: %v", err)
+	}
+	return store
+}
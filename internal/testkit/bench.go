@@ -0,0 +1,251 @@
+package testkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/memory"
+	"github.com/manish-npx/go-student-api/internal/storage/sqlite"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// Benchmark suites for the storage backends and the handlers, to compare
+// against before merging a change. Like the fuzz targets, Go only runs
+// them from _test.go files; bench_test.go wires each one up:
+//
+//	func BenchmarkGetStudents(b *testing.B) { testkit.BenchmarkGetStudents(b) }
+//
+// Run them with go test -run '^$' -bench . -benchmem ./internal/testkit,
+// saving the output for benchstat.
+
+// Backend opens an empty storage backend for a benchmark or a scenario and
+// closes it on cleanup.
+type Backend struct {
+	Name string
+	Open func(tb testing.TB) storage.Storage
+}
+
+// extraBackends holds the backends added by build tags (postgres)
+var extraBackends []Backend
+
+// -------------------------------------------------------------
+// Backends() → memory, sqlite and, built with -tags postgres, postgres
+// -------------------------------------------------------------
+func Backends() []Backend {
+	backends := []Backend{
+		{Name: "memory", Open: func(testing.TB) storage.Storage { return memory.New() }},
+		{Name: "sqlite", Open: openSqlite},
+	}
+	return append(backends, extraBackends...)
+}
+
+// openBackend opens the backend called name, failing tb when there's none
+func openBackend(tb testing.TB, name string) storage.Storage {
+	tb.Helper()
+	for _, backend := range Backends() {
+		if backend.Name == name {
+			return backend.Open(tb)
+		}
+	}
+	tb.Fatalf("no %q storage backend", name)
+	return nil
+}
+
+// openSqlite opens a database file in a temp dir, with the default pragmas
+func openSqlite(tb testing.TB) storage.Storage {
+	tb.Helper()
+	store, err := sqlite.New(config.Config{
+		StoragePath: filepath.Join(tb.TempDir(), "bench.db"),
+		Sqlite:      config.Sqlite{JournalMode: "wal", BusyTimeout: 5e9, ForeignKeys: true, Synchronous: "normal", MaxReaders: 4},
+	})
+	if err != nil {
+		tb.Fatalf("open sqlite: %v", err)
+	}
+	tb.Cleanup(func() { store.Db.Close() })
+	return store
+}
+
+// benchStudent is the i-th of a set of distinct valid students
+func benchStudent(i int) types.Student {
+	return types.Student{
+		Name:        fmt.Sprintf("Student %d", i),
+		Email:       fmt.Sprintf("student%d@example.com", i),
+		DateOfBirth: BornYearsAgo(10 + i%60),
+		Phone:       "+14155550123",
+	}
+}
+
+// seedStudents stores n students, failing tb on the first error
+func seedStudents(tb testing.TB, store storage.Storage, n int) {
+	tb.Helper()
+	for i := range n {
+		if _, err := store.CreateStudent(benchStudent(i)); err != nil {
+			tb.Fatalf("seed student %d: %v", i, err)
+		}
+	}
+}
+
+// 🧩 BenchmarkCreateStudent inserts one student per op on every backend.
+func BenchmarkCreateStudent(b *testing.B) {
+	for _, backend := range Backends() {
+		b.Run(backend.Name, func(b *testing.B) {
+			store := backend.Open(b)
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				if _, err := store.CreateStudent(benchStudent(i)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// 🧩 BenchmarkGetStudents loads the whole table of 10k and 100k students
+// per op on every backend, and one 100-student keyset page of it.
+func BenchmarkGetStudents(b *testing.B) {
+	for _, backend := range Backends() {
+		for _, rows := range []int{10_000, 100_000} {
+			// seeded once: sub-benchmarks run several times to settle b.N
+			store := backend.Open(b)
+			seedStudents(b, store, rows)
+			pages, _ := storage.As[storage.PageStore](store)
+
+			b.Run(fmt.Sprintf("%s/%d/all", backend.Name, rows), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					students, err := store.GetStudents()
+					if err != nil || len(students) != rows {
+						b.Fatalf("got %d students, %v; want %d", len(students), err, rows)
+					}
+				}
+			})
+			if pages == nil {
+				continue
+			}
+			b.Run(fmt.Sprintf("%s/%d/page", backend.Name, rows), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; b.Loop(); i++ {
+					// pages from all over the table, not just the cached first one
+					after := int64(i*100) % int64(rows-100)
+					if _, err := pages.GetStudentsAfter(after, 100); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// 🧩 BenchmarkHandlers serves requests through the full router (every
+// middleware included) on the in-memory backend, without a network in
+// between: the list of 1000 students, a page of 100, get and create.
+func BenchmarkHandlers(b *testing.B) {
+	cfg := Config()
+	cfg.Pagination.MaxLimit = 1000
+	srv := NewServerWithConfig(b, cfg)
+	seedStudents(b, srv.Storage, 1000)
+	handler := srv.Config.Handler
+
+	serve := func(b *testing.B, method, target, body string, want int) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			b.Fatalf("%s %s = %d, want %d: %s", method, target, rec.Code, want, rec.Body)
+		}
+	}
+
+	// the whole list first, while it holds just the seeded 1000: streamed
+	// in id order, loaded and encoded at once when sorted by name
+	b.Run("list", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			serve(b, http.MethodGet, "/api/students", "", http.StatusOK)
+		}
+	})
+	b.Run("list-sorted", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			serve(b, http.MethodGet, "/api/students?sort=name", "", http.StatusOK)
+		}
+	})
+	b.Run("page", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			serve(b, http.MethodGet, "/api/students?limit=100", "", http.StatusOK)
+		}
+	})
+	b.Run("get", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; b.Loop(); i++ {
+			serve(b, http.MethodGet, fmt.Sprintf("/api/student/%d", i%1000+1), "", http.StatusOK)
+		}
+	})
+	// counted across calls: b.Run calls create again (b.N=1 first) on the
+	// same server
+	created := 0
+	b.Run("create", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			created++
+			body := fmt.Sprintf(`{"name":"Bench %d","email":"bench%d@example.com","date_of_birth":"2010-05-01"}`, created, created)
+			serve(b, http.MethodPost, "/api/student", body, http.StatusCreated)
+		}
+	})
+}
+
+// 🧩 BenchmarkWriteJson measures the response path alone: a page of 100
+// students with their links, an error problem and a pre-marshaled one,
+// written to a ResponseWriter that keeps nothing.
+func BenchmarkWriteJson(b *testing.B) {
+	students := make([]types.Student, 100)
+	for i := range students {
+		students[i] = benchStudent(i)
+		students[i].ID = int64(i + 1)
+	}
+	page := links.New("").Students(students)
+	problem := response.GeneralError(errors.New("not found"))
+	static := response.NewStaticProblem(http.StatusNotFound, "not found")
+
+	b.Run("page", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			response.WriteJson(discardWriter{}, http.StatusOK, page)
+		}
+	})
+	b.Run("problem", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			response.WriteJson(discardWriter{}, http.StatusNotFound, problem)
+		}
+	})
+	b.Run("static", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			static.Write(discardWriter{})
+		}
+	})
+}
+
+// discardHeader is the one set of headers every discardWriter shares
+var discardHeader = http.Header{}
+
+// discardWriter is a ResponseWriter that drops the body, so benchmarks
+// count the encoder's allocations rather than a recorder's
+type discardWriter struct{}
+
+func (discardWriter) Header() http.Header         { return discardHeader }
+func (discardWriter) WriteHeader(int)             {}
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,15 @@
+package testkit_test
+
+import (
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/testkit"
+)
+
+func BenchmarkCreateStudent(b *testing.B) { testkit.BenchmarkCreateStudent(b) }
+
+func BenchmarkGetStudents(b *testing.B) { testkit.BenchmarkGetStudents(b) }
+
+func BenchmarkHandlers(b *testing.B) { testkit.BenchmarkHandlers(b) }
+
+func BenchmarkWriteJson(b *testing.B) { testkit.BenchmarkWriteJson(b) }
@@ -0,0 +1,11 @@
+package testkit_test
+
+import (
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/testkit"
+)
+
+func TestEndpoints(t *testing.T) {
+	testkit.RunScenarios(t, testkit.EndpointScenarios())
+}
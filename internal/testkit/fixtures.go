@@ -0,0 +1,87 @@
+package testkit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// 🧩 ValidStudent returns a payload that passes every validation rule
+func ValidStudent() types.Student {
+	return types.Student{Name: "Ada Lovelace", Email: "ada@example.com", Age: 28, DateOfBirth: BornYearsAgo(28)}
+}
+
+// 🧩 OtherStudent is a second valid payload that does not clash with ValidStudent
+func OtherStudent() types.Student {
+	return types.Student{Name: "Alan Turing", Email: "alan@example.com", Age: 41, DateOfBirth: BornYearsAgo(41)}
+}
+
+// 🧩 Students returns n distinct valid students (unique emails)
+func Students(n int) []types.Student {
+	students := make([]types.Student, 0, n)
+	for i := 1; i <= n; i++ {
+		students = append(students, types.Student{
+			Name:        fmt.Sprintf("Student %d", i),
+			Email:       fmt.Sprintf("student%d@example.com", i),
+			Age:         18 + i%40,
+			DateOfBirth: BornYearsAgo(18 + i%40),
+		})
+	}
+	return students
+}
+
+// 🧩 BornYearsAgo is a date_of_birth that makes a student `age` years old today
+func BornYearsAgo(age int) string {
+	return time.Now().UTC().AddDate(-age, 0, -1).Format(types.DateLayout)
+}
+
+// -------------------------------------------------------------
+// Seed() → Insert students straight into storage and return them with IDs
+// -------------------------------------------------------------
+func Seed(t testing.TB, store storage.Storage, students ...types.Student) []types.Student {
+	t.Helper()
+
+	seeded := make([]types.Student, 0, len(students))
+	for _, student := range students {
+		id, err := store.CreateStudent(student)
+		if err != nil {
+			t.Fatalf("seed student %s: %v", student.Email, err)
+		}
+		student.ID = id
+		seeded = append(seeded, student)
+	}
+	return seeded
+}
+
+// 🧩 As is storage.As for tests: store's optional capability T, failing t
+// when the backend doesn't have it
+func As[T any](t testing.TB, store storage.Storage) T {
+	t.Helper()
+
+	capability, ok := storage.As[T](store)
+	if !ok {
+		var zero T
+		t.Fatalf("storage backend has no %T", &zero)
+	}
+	return capability
+}
+
+// 🧩 PNG is a valid 1x1 transparent PNG for photo uploads
+func PNG() []byte {
+	return []byte{
+		0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d,
+		0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+		0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4, 0x89, 0x00, 0x00, 0x00,
+		0x0d, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x00, 0x01, 0x00, 0x00,
+		0x05, 0x00, 0x01, 0x0d, 0x0a, 0x2d, 0xb4, 0x00, 0x00, 0x00, 0x00, 0x49,
+		0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+	}
+}
+
+// 🧩 PDF is a minimal file that sniffs as application/pdf
+func PDF() []byte {
+	return []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+}
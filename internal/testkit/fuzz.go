@@ -0,0 +1,133 @@
+package testkit
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/http/bind"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Fuzz targets for the code that reads untrusted input. Go only runs fuzz
+// functions declared in _test.go files; fuzz_test.go wires each one up:
+//
+//	func FuzzStudentJSON(f *testing.F) { testkit.FuzzStudentJSON(f) }
+//
+// Run one with go test -fuzz=FuzzStudentJSON ./internal/testkit. The seeds
+// run as plain tests with go test.
+
+// 🧩 FuzzStudentJSON posts arbitrary bodies to POST /api/student and
+// PUT /api/students/by-email/{email} through the real router: every one
+// must be answered, never with a 5xx.
+func FuzzStudentJSON(f *testing.F) {
+	f.Add([]byte(`{"name":"Ada Lovelace","email":"ada@example.com","date_of_birth":"2000-12-10"}`))
+	f.Add([]byte(`{"name":"Ada","email":"ada@example.com","date_of_birth":"2000-12-10","phone":"+14155550123","gender":"female","address":{"line1":"1 Main St","city":"London","postal_code":"N1","country":"GB"}}`))
+	f.Add([]byte(`{"name":"Ada","email":"a@b","date_of_birth":"0000-00-00","custom":{"house":[1,{"x":null}]}}`))
+	f.Add([]byte(`{"email":"@","date_of_birth":"9999-12-31","age":-1}`))
+	f.Add([]byte(`[{"name":"Ada"}]`))
+	f.Add([]byte(`{"name":"\u0000","email":"x@x.x","address":null}`))
+	f.Add([]byte(`{`))
+	f.Add([]byte(``))
+
+	srv := NewServer(f)
+	handler := srv.Config.Handler
+	f.Fuzz(func(t *testing.T, body []byte) {
+		for _, target := range []struct{ method, path string }{
+			{http.MethodPost, "/api/student"},
+			{http.MethodPost, "/api/student?validate_only=true"},
+			{http.MethodPut, "/api/students/by-email/ada@example.com"},
+		} {
+			req := httptest.NewRequest(target.method, target.path, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code >= 500 {
+				t.Fatalf("%s %s with %q = %d: %s", target.method, target.path, body, rec.Code, rec.Body)
+			}
+		}
+	})
+}
+
+// 🧩 FuzzQuery feeds arbitrary query strings to the bind parsers: values
+// they accept must respect their bounds, and anything else is a 400 error,
+// never a panic.
+func FuzzQuery(f *testing.F) {
+	f.Add("limit=20&cursor=abc&sort=-name&fields=id,name&ids=1,2,3&verified=true&older_than=720h&format=csv")
+	f.Add("limit=-1&sort=-&fields=,,&ids=,&verified=maybe&older_than=-1h")
+	f.Add("limit=99999999999999999999&ids=9223372036854775808,0,-1")
+	f.Add("ids=1,1,1,1,2&fields=id,id,email")
+	f.Add("%zz&limit=%00&=&&")
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/students", nil)
+		req.URL.RawQuery = raw
+		q := bind.NewQuery(req)
+
+		if limit := q.Int("limit", 20, 1, 100); limit < 1 || limit > 100 {
+			t.Fatalf("limit = %d, want 1 to 100", limit)
+		}
+		q.Bool("verified", false)
+		q.Duration("older_than", 0, 0)
+		q.String("cursor", "")
+		if format := q.OneOf("format", "csv", "csv", "json"); format != "csv" && format != "json" {
+			t.Fatalf("format = %q, want csv or json", format)
+		}
+		if sort := q.Sort("sort", bind.Sort{Field: "id"}, "id", "name"); sort.Field != "id" && sort.Field != "name" {
+			t.Fatalf("sort = %+v, want id or name", sort)
+		}
+		fields := q.Fields("fields", "id", "name", "email")
+		for i, field := range fields {
+			if !slices.Contains([]string{"id", "name", "email"}, field) || slices.Contains(fields[:i], field) {
+				t.Fatalf("fields = %v, want distinct allowed fields", fields)
+			}
+		}
+		ids := q.IDs("ids", 100)
+		if len(ids) > 100 {
+			t.Fatalf("got %d ids, want at most 100", len(ids))
+		}
+		for i, id := range ids {
+			if id < 1 || slices.Contains(ids[:i], id) {
+				t.Fatalf("ids = %v, want distinct positive ids", ids)
+			}
+		}
+		_ = q.Err()
+	})
+}
+
+// 🧩 FuzzCursor decodes arbitrary ?cursor= tokens: a token that decodes
+// must point at a valid id, and every cursor a page built from it hands
+// out must decode again.
+func FuzzCursor(f *testing.F) {
+	f.Add("")
+	f.Add(storage.EncodeCursor(42))
+	f.Add(storage.EncodeBeforeCursor(7))
+	f.Add(storage.LastCursor())
+	f.Add(storage.EncodeCursor(math.MaxInt64))
+	f.Add("eyJpZCI6LTF9")
+	f.Add("not base64!")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		at, err := storage.DecodeCursor(token)
+		if err != nil {
+			return
+		}
+		if at.ID < 0 {
+			t.Fatalf("cursor %q decoded to id %d", token, at.ID)
+		}
+
+		rows := []types.Student{{ID: 1}, {ID: 2}, {ID: 3}}
+		for _, students := range [][]types.Student{nil, rows[:1], rows} {
+			page := storage.BuildPage(students, 2, at)
+			for _, next := range []string{page.NextCursor, page.PrevCursor} {
+				if _, err := storage.DecodeCursor(next); err != nil {
+					t.Fatalf("page at %+v hands out cursor %q that doesn't decode: %v", at, next, err)
+				}
+			}
+		}
+	})
+}
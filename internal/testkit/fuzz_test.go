@@ -0,0 +1,13 @@
+package testkit_test
+
+import (
+	"testing"
+
+	"github.com/manish-npx/go-student-api/internal/testkit"
+)
+
+func FuzzStudentJSON(f *testing.F) { testkit.FuzzStudentJSON(f) }
+
+func FuzzQuery(f *testing.F) { testkit.FuzzQuery(f) }
+
+func FuzzCursor(f *testing.F) { testkit.FuzzCursor(f) }
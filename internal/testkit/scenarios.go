@@ -0,0 +1,3728 @@
+package testkit
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/chaos"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/featureflag"
+	"github.com/manish-npx/go-student-api/internal/hashid"
+	"github.com/manish-npx/go-student-api/internal/http/handlers/student"
+	"github.com/manish-npx/go-student-api/internal/http/links"
+	"github.com/manish-npx/go-student-api/internal/http/middleware"
+	"github.com/manish-npx/go-student-api/internal/http/registry"
+	"github.com/manish-npx/go-student-api/internal/metrics"
+	"github.com/manish-npx/go-student-api/internal/paging"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/memory"
+	"github.com/manish-npx/go-student-api/internal/storage/mocks"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/ulid"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/webhook"
+	"github.com/manish-npx/go-student-api/pkg/client"
+)
+
+// Scenario is one request against a freshly seeded server.
+// Path may contain a single %d which is replaced with the seeded student's ID.
+type Scenario struct {
+	Name       string
+	Method     string
+	Path       string
+	Body       any
+	WantStatus int
+	// Check runs extra assertions after the status code matched
+	Check func(t testing.TB, srv *Server, res *Response)
+	// Backends limits the scenario to these storage backends (names from
+	// Backends); it runs on every one when empty
+	Backends []string
+}
+
+// 🧩 EndpointScenarios covers every route, happy path and error paths.
+// Forks that add routes can append their own cases and reuse RunScenarios.
+func EndpointScenarios() []Scenario {
+	return []Scenario{
+		// GET /healthz, /readyz
+		{
+			Name: "liveness probe", Method: http.MethodGet, Path: "/healthz",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "status", "ok")
+			},
+		},
+		{
+			Name: "readiness probe", Method: http.MethodGet, Path: "/readyz",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "status", "ok")
+			},
+		},
+
+		// GET /api/version
+		{
+			Name: "version", Method: http.MethodGet, Path: "/api/version",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var info types.Version
+				res.DecodeJSON(t, &info)
+				if info.Version == "" || info.GoVersion == "" || info.Storage != srv.Backend {
+					t.Fatalf("version = %+v, want a version on the %s backend", info, srv.Backend)
+				}
+				// only the SQL backends have a schema
+				if (info.SchemaVersion == 0) != (srv.Backend == "memory") {
+					t.Fatalf("schema version = %d on the %s backend", info.SchemaVersion, srv.Backend)
+				}
+			},
+		},
+
+		// POST /api/student
+		{
+			Name: "create student", Method: http.MethodPost, Path: "/api/student",
+			Body:       OtherStudent(),
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "success", true)
+				res.AssertHeader(t, "Content-Type", "application/json")
+
+				mail := srv.AwaitMail(t, 1)[0]
+				if mail.To != OtherStudent().Email || !strings.Contains(mail.Subject, OtherStudent().Name) {
+					t.Fatalf("welcome email = %+v", mail)
+				}
+			},
+		},
+		{
+			Name: "create with empty body", Method: http.MethodPost, Path: "/api/student",
+			Body: "", WantStatus: http.StatusBadRequest,
+			Check: errorContains("empty body"),
+		},
+		{
+			Name: "create with malformed JSON", Method: http.MethodPost, Path: "/api/student",
+			Body: `{"name":`, WantStatus: http.StatusBadRequest,
+			Check: errorContains("invalid JSON"),
+		},
+		{
+			Name: "create with missing fields", Method: http.MethodPost, Path: "/api/student",
+			Body: map[string]any{"name": "No Email"}, WantStatus: http.StatusBadRequest,
+			Check: errorContains("field Email is required"),
+		},
+		{
+			Name: "create with invalid email", Method: http.MethodPost, Path: "/api/student",
+			Body:       map[string]any{"name": "Bad", "email": "not-an-email", "date_of_birth": BornYearsAgo(20)},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("field Email is invalid"),
+		},
+		{
+			Name: "create with invalid phone", Method: http.MethodPost, Path: "/api/student",
+			Body:       map[string]any{"name": "Bad", "email": "bad@example.com", "date_of_birth": BornYearsAgo(20), "phone": "555-0123"},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("field Phone is invalid"),
+		},
+		{
+			Name: "create without date of birth", Method: http.MethodPost, Path: "/api/student",
+			Body:       map[string]any{"name": "Bad", "email": "bad@example.com", "age": 20},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("field DateOfBirth is required"),
+		},
+		{
+			Name: "create with out-of-range age", Method: http.MethodPost, Path: "/api/student",
+			Body:       map[string]any{"name": "Bad", "email": "bad@example.com", "date_of_birth": BornYearsAgo(120)},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("want 1 to 100"),
+		},
+		{
+			Name: "writes follow the deployment's validation policy", Method: http.MethodPost, Path: "/api/student",
+			Body:       OtherStudent(),
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Validation = config.Validation{
+					MinAge: 11, MaxAge: 19, RequirePhone: true,
+					EmailDomains: []string{"School.edu", "mailinator.com"}, BlockedEmailDomains: []string{"alumni.school.edu"}, BlockDisposableEmails: true,
+				}
+				srv := NewServerWithConfig(t, cfg)
+
+				pupil := map[string]any{"name": "Pat Pupil", "email": "pat@school.edu", "date_of_birth": BornYearsAgo(15), "phone": "+14155550123"}
+				var created struct {
+					ID int64 `json:"id"`
+				}
+				srv.Do(t, http.MethodPost, "/api/student", pupil).AssertStatus(t, http.StatusCreated).DecodeJSON(t, &created)
+
+				for field, value := range map[string]any{"date_of_birth": BornYearsAgo(20), "phone": ""} {
+					bad := maps.Clone(pupil)
+					bad[field] = value
+					srv.Do(t, http.MethodPost, "/api/student", bad).AssertStatus(t, http.StatusBadRequest)
+				}
+				// emails the domain rules turn away are a 422
+				for email, reason := range map[string]string{
+					"pat@gmail.com":             "not on an allowed domain",
+					"pat@old.alumni.school.edu": "on a blocked domain",
+					"pat@mailinator.com":        "on a disposable email domain",
+				} {
+					bad := maps.Clone(pupil)
+					bad["email"] = email
+					srv.Do(t, http.MethodPost, "/api/student", bad).
+						AssertStatus(t, http.StatusUnprocessableEntity).
+						AssertErrorContains(t, reason)
+				}
+				srv.Do(t, http.MethodPost, "/api/student", map[string]any{"name": "Sub Domain", "email": "sub@mail.school.edu", "date_of_birth": BornYearsAgo(12), "phone": "+14155550124"}).
+					AssertStatus(t, http.StatusCreated)
+
+				// updates are held to it too
+				pupil["phone"] = ""
+				srv.Do(t, http.MethodPut, fmt.Sprintf("/api/student/%d", created.ID), pupil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "phone is required")
+			},
+		},
+		{
+			Name: "create with incomplete address", Method: http.MethodPost, Path: "/api/student",
+			Body:       map[string]any{"name": "Bad", "email": "bad@example.com", "date_of_birth": BornYearsAgo(20), "address": map[string]any{"line1": "1 High St", "country": "XX"}},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("field City is required"),
+		},
+		{
+			Name: "create with profile derives age", Method: http.MethodPost, Path: "/api/student",
+			Body: map[string]any{
+				"name": "Grace Hopper", "email": "grace@example.com", "age": 99,
+				"date_of_birth": BornYearsAgo(33), "phone": "+14155550123", "gender": "female",
+				"address": map[string]any{"line1": "1 High St", "city": "Arlington", "postal_code": "22201", "country": "US"},
+			},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var created struct {
+					ID int64 `json:"id"`
+				}
+				res.DecodeJSON(t, &created)
+				var student types.Student
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/student/%d", created.ID), nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &student)
+				if student.Age != 33 || student.Phone != "+14155550123" || student.Gender != "female" {
+					t.Fatalf("student = %+v, want derived age 33 and the profile fields", student)
+				}
+				if student.Address == nil || student.Address.City != "Arlington" || student.Address.Country != "US" {
+					t.Fatalf("address = %+v, want the stored address", student.Address)
+				}
+			},
+		},
+		{
+			Name: "create with duplicate email", Method: http.MethodPost, Path: "/api/student",
+			Body: ValidStudent(), WantStatus: http.StatusBadRequest,
+		},
+		{
+			// the SQL backends' unique index is case-sensitive; memory matches it
+			Name: "create with the email in another case", Method: http.MethodPost, Path: "/api/student",
+			Body: func() types.Student {
+				s := ValidStudent()
+				s.Email = strings.ToUpper(s.Email)
+				return s
+			}(), WantStatus: http.StatusCreated,
+		},
+
+		// GET /api/student/{id}
+		{
+			Name: "get student", Method: http.MethodGet, Path: "/api/student/%d",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "email", ValidStudent().Email)
+
+				var student types.StudentResource
+				res.DecodeJSON(t, &student)
+				self := fmt.Sprintf("/api/student/%d", student.ID)
+				if student.Links["self"].Href != self || student.Links["delete"].Method != http.MethodDelete {
+					t.Fatalf("_links = %+v, want self/delete on %s", student.Links, self)
+				}
+			},
+		},
+		{
+			Name: "get with non-numeric id", Method: http.MethodGet, Path: "/api/student/abc",
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("invalid id"),
+		},
+		{
+			Name: "get missing student", Method: http.MethodGet, Path: "/api/student/999999",
+			WantStatus: http.StatusNotFound,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				res.AssertErrorContains(t, "student not found")
+				if strings.Contains(string(res.Body), "999999") {
+					t.Fatalf("404 body %s names the id", res.Body)
+				}
+			},
+		},
+
+		// GET /api/students
+		{
+			Name: "list students", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var list []map[string]any
+				res.DecodeJSON(t, &list)
+				if len(list) != 1 {
+					t.Fatalf("len(list) = %d, want 1", len(list))
+				}
+			},
+		},
+		{
+			Name: "list students with a cache policy", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Caching = config.Caching{Writes: "no-store", Rules: []config.CacheRule{
+					{Route: "GET /api/students", CacheControl: "private, max-age=30"},
+				}}
+				srv := NewServerWithConfig(t, cfg)
+				id := Seed(t, srv.Storage, ValidStudent())[0].ID
+
+				list := srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK)
+				list.AssertHeader(t, "Cache-Control", "private, max-age=30")
+				if list.Header.Get("Expires") == "" || !slices.Contains(list.Header.Values("Vary"), "X-API-Key") {
+					t.Fatalf("cacheable list headers = %v, want Expires and Vary on credentials", list.Header)
+				}
+
+				// reads without a rule get none; writes and errors are never stored
+				if got := srv.Do(t, http.MethodGet, fmt.Sprintf("/api/student/%d", id), nil).Header.Get("Cache-Control"); got != "" {
+					t.Fatalf("Cache-Control without a rule = %q, want none", got)
+				}
+				srv.Do(t, http.MethodGet, "/api/students?limit=0", nil).AssertStatus(t, http.StatusBadRequest).AssertHeader(t, "Cache-Control", "no-store")
+				srv.Do(t, http.MethodPost, "/api/student", OtherStudent()).AssertStatus(t, http.StatusCreated).AssertHeader(t, "Cache-Control", "no-store")
+			},
+		},
+
+		{
+			Name: "concurrent identical lists are collapsed per tenant", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Tenancy.Enabled = true
+				cfg.Collapse = config.Collapse{Enabled: true, Routes: []string{"GET /api/students"}}
+				srv := NewServerWithConfig(t, cfg)
+				acme, err := As[storage.TenantStore](t, srv.Storage).CreateTenant("acme", "Acme High")
+				if err != nil {
+					t.Fatal(err)
+				}
+				Seed(t, srv.Storage, ValidStudent())
+				Seed(t, As[storage.TenantScoper](t, srv.Storage).ForTenant(acme), OtherStudent(), Students(1)[0])
+
+				// the same list under two tenants must never share a response
+				var wg sync.WaitGroup
+				for i := range 20 {
+					slug, want := "default", 1
+					if i%2 == 1 {
+						slug, want = "acme", 2
+					}
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/students?sort=name&fields=id,name", nil)
+						req.Header.Set("X-Tenant", slug)
+						var list []map[string]any
+						srv.Send(t, req).AssertStatus(t, http.StatusOK).DecodeJSON(t, &list)
+						if len(list) != want {
+							t.Errorf("tenant %s got %d students, want %d", slug, len(list), want)
+						}
+					}()
+				}
+				wg.Wait()
+			},
+		},
+
+		{
+			Name: "list students by cursor", Method: http.MethodGet, Path: "/api/students?limit=2",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				Seed(t, srv.Storage, Students(3)...)
+
+				var seen []float64
+				next := ""
+				for {
+					page := srv.Do(t, http.MethodGet, "/api/students?limit=2&cursor="+next, nil).AssertStatus(t, http.StatusOK)
+					var body struct {
+						Data       []map[string]any `json:"data"`
+						NextCursor string           `json:"next_cursor"`
+					}
+					page.DecodeJSON(t, &body)
+					for _, student := range body.Data {
+						seen = append(seen, student["id"].(float64))
+					}
+					if body.NextCursor == "" {
+						break
+					}
+					next = body.NextCursor
+				}
+				if len(seen) != 4 {
+					t.Fatalf("paged ids = %v, want 4 students", seen)
+				}
+			},
+		},
+		{
+			Name: "list follows page links", Method: http.MethodGet, Path: "/api/students?limit=2",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				Seed(t, srv.Storage, Students(4)...)
+
+				type page struct {
+					Data  []types.StudentResource `json:"data"`
+					Links types.Links             `json:"_links"`
+				}
+				ids := func(p page) []int64 {
+					var out []int64
+					for _, student := range p.Data {
+						out = append(out, student.ID)
+					}
+					return out
+				}
+				follow := func(from page, rel string) page {
+					link, ok := from.Links[rel]
+					if !ok {
+						t.Fatalf("no %q link in %+v", rel, from.Links)
+					}
+					var next page
+					srv.Do(t, http.MethodGet, link.Href, nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &next)
+					return next
+				}
+
+				var first page
+				srv.Do(t, http.MethodGet, "/api/students?limit=2", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &first)
+				if _, ok := first.Links["prev"]; ok || first.Links["next"].Href == "" {
+					t.Fatalf("first page links = %+v, want next and no prev", first.Links)
+				}
+
+				last := follow(first, "last")
+				if _, ok := last.Links["next"]; ok || len(last.Data) != 2 {
+					t.Fatalf("last page = %v %+v, want 2 rows and no next", ids(last), last.Links)
+				}
+				middle := follow(last, "prev")
+				if back := follow(middle, "prev"); len(back.Data) != 1 || back.Data[0].ID != first.Data[0].ID {
+					t.Fatalf("walking back ended at %v, want [%d]", ids(back), first.Data[0].ID)
+				}
+				if again := follow(middle, "next"); !slices.Equal(ids(again), ids(last)) {
+					t.Fatalf("prev then next = %v, want %v", ids(again), ids(last))
+				}
+			},
+		},
+		{
+			Name: "batch get by ids", Method: http.MethodGet, Path: "/api/students?ids=%d,999999",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var batch types.StudentBatch
+				res.DecodeJSON(t, &batch)
+				if len(batch.Data) != 1 || batch.Data[0].Email != ValidStudent().Email || !slices.Equal(batch.Missing, []int64{999999}) {
+					t.Fatalf("batch = %+v, want the seeded student and 999999 missing", batch)
+				}
+
+				other := Seed(t, srv.Storage, OtherStudent())[0].ID
+				srv.Do(t, http.MethodPost, "/api/students/batch-get", map[string]any{"ids": []int64{other, batch.Data[0].ID, other}}).
+					AssertStatus(t, http.StatusOK).DecodeJSON(t, &batch)
+				if len(batch.Data) != 2 || batch.Data[0].ID != other || len(batch.Missing) != 0 {
+					t.Fatalf("posted batch = %+v, want both students in request order", batch)
+				}
+			},
+		},
+		{
+			Name: "batch get with invalid ids", Method: http.MethodGet, Path: "/api/students?ids=1,abc",
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("ids must be a comma-separated list of positive ids"),
+		},
+		{
+			Name: "batch get without ids", Method: http.MethodPost, Path: "/api/students/batch-get",
+			Body:       map[string]any{"ids": []int64{}},
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "list with invalid cursor", Method: http.MethodGet, Path: "/api/students?cursor=not-a-cursor",
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("invalid cursor"),
+		},
+		{
+			Name: "list with limit out of range", Method: http.MethodGet, Path: "/api/students?limit=0",
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("limit must be between"),
+		},
+		{
+			Name: "list with limit above pagination.max_limit", Method: http.MethodGet, Path: "/api/students?limit=101",
+			WantStatus: http.StatusBadRequest,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				res.AssertErrorContains(t, "between 1 and 100")
+
+				// 🔢 Tighter limits also cap the unpaged list
+				cfg := Config()
+				cfg.Pagination = config.Pagination{DefaultLimit: 1, MaxLimit: 2}
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, Students(3)...)
+
+				var page types.Page[types.StudentResource]
+				srv.Do(t, http.MethodGet, "/api/students?cursor=", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &page)
+				if len(page.Data) != 1 || page.Limit != 1 {
+					t.Fatalf("default page = %d rows, limit %d; want 1", len(page.Data), page.Limit)
+				}
+				srv.Do(t, http.MethodGet, "/api/students?limit=3", nil).AssertStatus(t, http.StatusBadRequest)
+				srv.Do(t, http.MethodGet, "/api/students", nil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "more than 2 students")
+
+				// 🔄 Reloaded limits apply to the next request
+				srv.Paging.Apply(config.Pagination{DefaultLimit: 2, MaxLimit: 3})
+				srv.Do(t, http.MethodGet, "/api/students?limit=3", nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, "/api/students?cursor=", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &page)
+				if len(page.Data) != 2 || page.Limit != 2 {
+					t.Fatalf("default page after reload = %d rows, limit %d; want 2", len(page.Data), page.Limit)
+				}
+			},
+		},
+		{
+			Name: "list sorted by age descending", Method: http.MethodGet, Path: "/api/students?sort=-age",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				Seed(t, srv.Storage, OtherStudent())
+				var students []types.Student
+				srv.Do(t, http.MethodGet, "/api/students?sort=-age", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &students)
+				if len(students) != 2 || students[0].Age < students[1].Age {
+					t.Fatalf("not sorted by -age: %+v", students)
+				}
+			},
+		},
+		{
+			Name: "list compressed with gzip", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				// tiny bodies stay plain
+				if res.Header.Get("Content-Encoding") != "" {
+					t.Fatalf("small list was compressed: %v", res.Header)
+				}
+
+				Seed(t, srv.Storage, Students(40)...)
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/students", nil)
+				req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+				big := srv.Send(t, req).AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Encoding", "gzip").
+					AssertHeader(t, "Vary", "Accept-Encoding")
+
+				zr, err := gzip.NewReader(bytes.NewReader(big.Body))
+				if err != nil {
+					t.Fatalf("gzip body: %v", err)
+				}
+				var students []types.Student
+				if err := json.NewDecoder(zr).Decode(&students); err != nil || len(students) != 41 {
+					t.Fatalf("decoded %d students: %v", len(students), err)
+				}
+			},
+		},
+		{
+			Name: "camelCase JSON with X-JSON-Naming", Method: http.MethodGet, Path: "/api/student/1",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertHeader(t, "X-JSON-Naming", "snake_case")
+				camel := func(method, path, body string) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+					req.Header.Set("X-JSON-Naming", "camelCase")
+					if body != "" {
+						req.Header.Set("Content-Type", "application/json")
+					}
+					return srv.Send(t, req)
+				}
+
+				created := camel(http.MethodPost, "/api/student", `{"name":"Camel Case","email":"camel@example.com","dateOfBirth":"`+BornYearsAgo(30)+`"}`).
+					AssertStatus(t, http.StatusCreated).
+					AssertHeader(t, "X-JSON-Naming", "camelCase")
+				var body struct {
+					Student map[string]any `json:"student"`
+				}
+				created.DecodeJSON(t, &body)
+				if body.Student["dateOfBirth"] != BornYearsAgo(30) || body.Student["_links"] == nil || body.Student["date_of_birth"] != nil {
+					t.Fatalf("camelCase student = %v", body.Student)
+				}
+				id := int64(body.Student["id"].(float64))
+
+				page := camel(http.MethodGet, "/api/students?limit=1&fields=id,date_of_birth", "").
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "hasMore", true)
+				var sparse struct {
+					Data       []map[string]any `json:"data"`
+					NextCursor string           `json:"nextCursor"`
+				}
+				page.DecodeJSON(t, &sparse)
+				if len(sparse.Data) != 1 || sparse.Data[0]["dateOfBirth"] == nil || sparse.NextCursor == "" {
+					t.Fatalf("camelCase page = %s", page.Body)
+				}
+
+				// renamed before compression (a list long enough to compress)
+				for i := range 5 {
+					Seed(t, srv.Storage, types.Student{Name: "Camel", Email: fmt.Sprintf("camel%d@example.com", i), Age: 20, DateOfBirth: BornYearsAgo(20)})
+				}
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/students", nil)
+				req.Header.Set("X-JSON-Naming", "camelCase")
+				req.Header.Set("Accept-Encoding", "gzip")
+				big := srv.Send(t, req).AssertStatus(t, http.StatusOK).AssertHeader(t, "Content-Encoding", "gzip")
+				zr, err := gzip.NewReader(bytes.NewReader(big.Body))
+				if err != nil {
+					t.Fatalf("gzip body: %v", err)
+				}
+				var list []map[string]any
+				if err := json.NewDecoder(zr).Decode(&list); err != nil || len(list) == 0 || list[0]["dateOfBirth"] == nil {
+					t.Fatalf("compressed camelCase list: %v %v", err, list)
+				}
+
+				camel(http.MethodGet, "/api/invoices/999999", "").
+					AssertStatus(t, http.StatusNotFound).
+					AssertErrorContains(t, "no invoice found")
+				req, _ = http.NewRequest(http.MethodGet, srv.URL+"/api/student/1", nil)
+				req.Header.Set("X-JSON-Naming", "kebab-case")
+				srv.Send(t, req).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "X-JSON-Naming must be snake_case or camelCase")
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d", id), nil).AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "XML and MessagePack by Accept", Method: http.MethodGet, Path: "/api/student/1",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertHeader(t, "Content-Type", "application/json")
+				accept := func(path, accept string) *Response {
+					req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+					req.Header.Set("Accept", accept)
+					return srv.Send(t, req)
+				}
+
+				xmlRes := accept("/api/student/1", "application/xml;q=0.9, application/json;q=0.5").
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "application/xml")
+				var student struct {
+					XMLName xml.Name `xml:"response"`
+					ID      int64    `xml:"id"`
+					Name    string   `xml:"name"`
+					Self    string   `xml:"_links>self>href"`
+				}
+				if err := xml.Unmarshal(xmlRes.Body, &student); err != nil || student.ID != 1 || student.Name != "Ada Lovelace" || student.Self != "/api/student/1" {
+					t.Fatalf("XML student %+v: %v\n%s", student, err, xmlRes.Body)
+				}
+
+				// camelCase keys carry over; a list is <response><item>...
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/students", nil)
+				req.Header.Set("Accept", "text/xml")
+				req.Header.Set("X-JSON-Naming", "camelCase")
+				list := srv.Send(t, req).AssertStatus(t, http.StatusOK)
+				if !bytes.Contains(list.Body, []byte("<response><item><id>1</id>")) || !bytes.Contains(list.Body, []byte("<dateOfBirth>")) {
+					t.Fatalf("XML list = %s", list.Body)
+				}
+
+				packed := accept("/api/student/1", "application/msgpack").
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "application/msgpack")
+				// a map, then "id": 1 and "name": "Ada Lovelace" as fixstr/fixint
+				if packed.Body[0]&0xf0 != 0x80 || !bytes.HasPrefix(packed.Body[1:], []byte("\xa2id\x01\xa4name\xacAda Lovelace")) {
+					t.Fatalf("MessagePack student = % x", packed.Body)
+				}
+
+				// errors are negotiated too; unknown types and */* stay JSON
+				accept("/api/invoices/999999", "application/xml").
+					AssertStatus(t, http.StatusNotFound).
+					AssertHeader(t, "Content-Type", "application/xml")
+				accept("/api/student/1", "text/html, */*;q=0.1").AssertHeader(t, "Content-Type", "application/json")
+				accept("/api/student/1", "application/*").AssertHeader(t, "Content-Type", "application/json")
+			},
+		},
+		{
+			Name: "stream students as NDJSON", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				// more than one 500-row batch and pagination.max_limit
+				Seed(t, srv.Storage, Students(600)...)
+				stream := func(path string, header ...string) *Response {
+					req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+					req.Header.Set("Accept", "application/x-ndjson")
+					for i := 0; i+1 < len(header); i += 2 {
+						req.Header.Set(header[i], header[i+1])
+					}
+					return srv.Send(t, req)
+				}
+
+				full := stream("/api/students").
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "application/x-ndjson")
+				lines := bytes.Split(bytes.TrimSuffix(full.Body, []byte("\n")), []byte("\n"))
+				if len(lines) != 601 {
+					t.Fatalf("streamed %d lines, want 601", len(lines))
+				}
+				var prev int64
+				for i, line := range lines {
+					var student types.StudentResource
+					if err := json.Unmarshal(line, &student); err != nil || student.ID <= prev || student.Links["self"].Href == "" {
+						t.Fatalf("line %d = %s: %v", i, line, err)
+					}
+					prev = student.ID
+				}
+
+				// sparse, camelCase lines
+				sparse := stream("/api/students?fields=id,date_of_birth", "X-JSON-Naming", "camelCase").AssertStatus(t, http.StatusOK)
+				if first, _, _ := bytes.Cut(sparse.Body, []byte("\n")); !bytes.HasPrefix(first, []byte(`{"dateOfBirth":`)) {
+					t.Fatalf("sparse camelCase line = %s", first)
+				}
+
+				stream("/api/students?sort=name").
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "ids, limit, cursor and sort don't apply")
+			},
+		},
+		{
+			Name: "the unpaged list streams in id order, the same JSON as when buffered", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Pagination.MaxLimit = 1000
+				srv := NewServerWithConfig(t, cfg)
+				if body := srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK).Body; string(body) != "[]\n" {
+					t.Fatalf("empty list = %q, want []", body)
+				}
+
+				// more than one 500-row batch
+				Seed(t, srv.Storage, Students(600)...)
+				var streamed, sorted []json.RawMessage
+				srv.Do(t, http.MethodGet, "/api/students?include=invoices", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "application/json").
+					AssertHeader(t, student.TotalCountHeader, "600").
+					DecodeJSON(t, &streamed)
+				// sorted the other way, the list is loaded whole and encoded at once
+				srv.Do(t, http.MethodGet, "/api/students?include=invoices&sort=-id", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &sorted)
+				slices.Reverse(sorted)
+				if len(streamed) != 600 || !slices.EqualFunc(streamed, sorted, func(a, b json.RawMessage) bool { return bytes.Equal(a, b) }) {
+					t.Fatalf("streamed %d students, not the %d the buffered list holds", len(streamed), len(sorted))
+				}
+
+				var sparse []map[string]any
+				srv.Do(t, http.MethodGet, "/api/students?fields=id,name", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &sparse)
+				if len(sparse) != 600 || len(sparse[0]) != 2 || sparse[599]["id"] != float64(600) {
+					t.Fatalf("sparse list = %d rows, first %v", len(sparse), sparse[0])
+				}
+
+				// a failure after the first batch went out leaves the array open
+				store := mocks.New()
+				Seed(t, store.Memory, Students(600)...)
+				list := student.GetList(store.Storage(), customfield.New(nil), links.New(""), paging.New(cfg.Pagination), featureflag.New(cfg.FeatureFlags))
+				store.FailNext("GetStudentInvoices", nil, errors.New("disk I/O error"))
+				rec := httptest.NewRecorder()
+				list(rec, httptest.NewRequest(http.MethodGet, "/api/students?include=invoices", nil))
+				if rec.Code != http.StatusOK || !bytes.HasPrefix(rec.Body.Bytes(), []byte("[{")) || json.Valid(rec.Body.Bytes()) {
+					t.Fatalf("list failing mid-stream = %d, valid JSON %v", rec.Code, json.Valid(rec.Body.Bytes()))
+				}
+				store.FailNext("GetStudentInvoices", errors.New("disk I/O error"))
+				rec = httptest.NewRecorder()
+				list(rec, httptest.NewRequest(http.MethodGet, "/api/students?include=invoices", nil))
+				if rec.Code != http.StatusInternalServerError {
+					t.Fatalf("list failing before the first row = %d, want 500", rec.Code)
+				}
+
+				// rows added after the count are held to max_limit too
+				cfg.Pagination.MaxLimit = 2
+				list = student.GetList(staleCount{store.Storage()}, customfield.New(nil), links.New(""), paging.New(cfg.Pagination), featureflag.New(cfg.FeatureFlags))
+				rec = httptest.NewRecorder()
+				list(rec, httptest.NewRequest(http.MethodGet, "/api/students", nil))
+				if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "more than 2 students") || rec.Header().Get(student.TotalCountHeader) != "" {
+					t.Fatalf("list growing past max_limit = %d %s", rec.Code, rec.Body)
+				}
+			},
+		},
+		{
+			Name: "feature flags gate NDJSON streaming", Method: http.MethodGet, Path: "/api/admin/feature-flags",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				var state types.FeatureFlags
+				res.DecodeJSON(t, &state)
+				if len(state.Flags) == 0 || state.Flags[0].Name != "students.ndjson" || !state.Flags[0].Enabled || state.Flags[0].Source != "default" {
+					t.Fatalf("default flags = %+v", state.Flags)
+				}
+
+				cfg := Config()
+				cfg.FeatureFlags.Flags = map[string]config.FeatureFlag{"students.ndjson": {Enabled: false}}
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, Students(2)...)
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/students", nil)
+				req.Header.Set("Accept", "application/x-ndjson")
+				srv.Send(t, req).
+					AssertStatus(t, http.StatusNotAcceptable).
+					AssertErrorContains(t, "NDJSON streaming is not enabled")
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK)
+
+				srv.Do(t, http.MethodGet, "/api/admin/feature-flags", nil).DecodeJSON(t, &state)
+				if state.Flags[0].Enabled || state.Flags[0].Source != "config" {
+					t.Fatalf("configured flags = %+v", state.Flags)
+				}
+			},
+		},
+		{
+			Name: "admin port keeps the operator surface off the public one", Method: http.MethodGet, Path: "/api/admin/stats",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.HttpServer.AdminAddr = "127.0.0.1:0"
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, ValidStudent())
+				admin := func(path string) *Response {
+					req, _ := http.NewRequest(http.MethodGet, srv.Admin.URL+path, nil)
+					return srv.Send(t, req)
+				}
+
+				for _, path := range []string{"/api/admin/stats", "/api/admin/./stats", "/healthz", "/readyz"} {
+					srv.Do(t, http.MethodGet, path, nil).AssertStatus(t, http.StatusNotFound)
+					admin(path).AssertStatus(t, http.StatusOK)
+				}
+				// the student API is on both, for the dashboard
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK)
+				admin("/api/students").AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "maintenance mode turns clients away", Method: http.MethodPut, Path: "/api/admin/maintenance",
+			Body:       map[string]any{"enabled": true, "message": "restoring a backup", "retry_after": "2m"},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "enabled", true)
+				srv.Do(t, http.MethodGet, "/api/students", nil).
+					AssertStatus(t, http.StatusServiceUnavailable).
+					AssertHeader(t, "Retry-After", "120").
+					AssertErrorContains(t, "down for maintenance: restoring a backup")
+				srv.Do(t, http.MethodPost, "/api/student", ValidStudent()).AssertStatus(t, http.StatusServiceUnavailable)
+
+				// operators and probes still get through
+				srv.Do(t, http.MethodGet, "/api/admin/maintenance", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "retry_after", "2m0s")
+				srv.Do(t, http.MethodGet, "/api/admin/stats", nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, "/healthz", nil).AssertStatus(t, http.StatusOK)
+
+				srv.Do(t, http.MethodPut, "/api/admin/maintenance", map[string]any{"enabled": true, "retry_after": "soon"}).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "retry_after must be a positive duration")
+				srv.Do(t, http.MethodPut, "/api/admin/maintenance", map[string]any{"enabled": false}).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "enabled", false)
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "list with unknown sort field", Method: http.MethodGet, Path: "/api/students?sort=password",
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("sort must be one of"),
+		},
+		{
+			Name: "list with sparse fields", Method: http.MethodGet, Path: "/api/students?fields=name,email&sort=-age",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var students []map[string]any
+				res.DecodeJSON(t, &students)
+				if len(students) != 1 || len(students[0]) != 2 || students[0]["name"] != ValidStudent().Name {
+					t.Fatalf("sparse list = %v, want only name and email", students)
+				}
+
+				var page struct {
+					Data []map[string]any `json:"data"`
+				}
+				srv.Do(t, http.MethodGet, "/api/students?limit=1&fields=age", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &page)
+				if len(page.Data) != 1 || len(page.Data[0]) != 1 || page.Data[0]["age"] == nil {
+					t.Fatalf("sparse page = %v, want only age", page.Data)
+				}
+			},
+		},
+		{
+			Name: "get with sparse fields", Method: http.MethodGet, Path: "/api/student/%d?fields=id,name",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var student map[string]any
+				res.DecodeJSON(t, &student)
+				if len(student) != 2 || student["id"] == nil || student["name"] != ValidStudent().Name {
+					t.Fatalf("sparse student = %v, want id and name", student)
+				}
+			},
+		},
+		{
+			Name: "get with unknown field", Method: http.MethodGet, Path: "/api/student/%d?fields=name,password",
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("fields must list fields from"),
+		},
+
+		// POST /api/student?validate_only=true
+		{
+			Name: "validate create without saving", Method: http.MethodPost, Path: "/api/student?validate_only=true",
+			Body:       OtherStudent(),
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "valid", true)
+				var body struct {
+					Student types.Student `json:"student"`
+				}
+				res.DecodeJSON(t, &body)
+				if body.Student.ID != 0 || body.Student.Age != 41 {
+					t.Fatalf("validated student = %+v, want no id and the derived age", body.Student)
+				}
+				if students, _ := srv.Storage.GetStudents(); len(students) != 1 {
+					t.Fatalf("students after validate_only = %d, want only the seeded one", len(students))
+				}
+				srv.Do(t, http.MethodPost, "/api/student", OtherStudent()).AssertStatus(t, http.StatusCreated)
+			},
+		},
+		{
+			Name: "validate create with taken email", Method: http.MethodPost, Path: "/api/student?validate_only=true",
+			Body: ValidStudent(), WantStatus: http.StatusConflict,
+			Check: errorContains("already exists"),
+		},
+		{
+			Name: "validate create with invalid fields", Method: http.MethodPost, Path: "/api/student?validate_only=1",
+			Body: map[string]any{"name": "No Email"}, WantStatus: http.StatusBadRequest,
+			Check: errorContains("field Email is required"),
+		},
+		{
+			Name: "validate create with invalid flag", Method: http.MethodPost, Path: "/api/student?validate_only=maybe",
+			Body: OtherStudent(), WantStatus: http.StatusBadRequest,
+			Check: errorContains("validate_only"),
+		},
+
+		// PUT /api/students/by-email/{email}
+		{
+			Name: "upsert creates then updates", Method: http.MethodPut, Path: "/api/students/by-email/" + OtherStudent().Email,
+			Body:       map[string]any{"name": OtherStudent().Name, "date_of_birth": OtherStudent().DateOfBirth},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "created", true)
+				srv.AwaitMail(t, 1)
+
+				path := "/api/students/by-email/" + OtherStudent().Email
+				again := srv.Do(t, http.MethodPut, path, map[string]any{"name": "Alan M. Turing", "date_of_birth": BornYearsAgo(42)}).AssertStatus(t, http.StatusOK)
+				again.AssertJSONField(t, "created", false)
+				var body struct {
+					ID      int64         `json:"id"`
+					Student types.Student `json:"student"`
+				}
+				again.DecodeJSON(t, &body)
+				if body.Student.Name != "Alan M. Turing" || body.Student.Age != 42 {
+					t.Fatalf("upserted student = %+v, want the new name and age", body.Student)
+				}
+
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d", body.ID), nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPut, path, map[string]any{"name": "Alan", "date_of_birth": BornYearsAgo(41)}).AssertStatus(t, http.StatusConflict)
+			},
+		},
+		{
+			Name: "upsert with mismatched email", Method: http.MethodPut, Path: "/api/students/by-email/" + OtherStudent().Email,
+			Body:       map[string]any{"name": "Alan", "email": "someone.else@example.com", "date_of_birth": BornYearsAgo(41)},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("does not match"),
+		},
+
+		// PUT /api/student/{id}
+		{
+			Name: "update student", Method: http.MethodPut, Path: "/api/student/%d",
+			Body:       map[string]any{"name": "Ada King", "email": "ada.king@example.com", "date_of_birth": BornYearsAgo(29)},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "success", true)
+			},
+		},
+		{
+			Name: "update with empty body", Method: http.MethodPut, Path: "/api/student/%d",
+			Body: "", WantStatus: http.StatusBadRequest,
+			Check: errorContains("empty body"),
+		},
+		{
+			Name: "update with non-numeric id", Method: http.MethodPut, Path: "/api/student/abc",
+			Body: ValidStudent(), WantStatus: http.StatusBadRequest,
+			Check: errorContains("invalid id"),
+		},
+		{
+			Name: "update missing student", Method: http.MethodPut, Path: "/api/student/999999",
+			Body: ValidStudent(), WantStatus: http.StatusNotFound,
+			Check: errorContains("student not found"),
+		},
+		{
+			Name: "update with a taken email", Method: http.MethodPut, Path: "/api/student/%d",
+			Body:       map[string]any{"name": "Ada King", "email": "ada.king@example.com", "date_of_birth": BornYearsAgo(29)},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				// the write answers what validate_only does, also when the
+				// owner of the email is in the trash
+				other := Seed(t, srv.Storage, OtherStudent())[0]
+				srv.Do(t, http.MethodPut, "/api/student/1", OtherStudent()).
+					AssertStatus(t, http.StatusConflict).
+					AssertErrorContains(t, "already exists")
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d", other.ID), nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPut, "/api/student/1", OtherStudent()).AssertStatus(t, http.StatusConflict)
+				if student, _ := srv.Storage.GetStudentById(1); student.Email != "ada.king@example.com" {
+					t.Fatalf("student after refused update = %+v, want it unchanged", student)
+				}
+			},
+		},
+
+		// PUT /api/student/{id}?validate_only=true
+		{
+			Name: "validate update without saving", Method: http.MethodPut, Path: "/api/student/%d?validate_only=true",
+			Body:       map[string]any{"name": "Ada King", "email": "ada@example.com", "date_of_birth": BornYearsAgo(29)},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "valid", true)
+				if student, _ := srv.Storage.GetStudentById(1); student.Name != ValidStudent().Name {
+					t.Fatalf("student after validate_only = %+v, want it unchanged", student)
+				}
+
+				// another student's email is taken, also when it is in the trash
+				other := Seed(t, srv.Storage, OtherStudent())[0]
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d", other.ID), nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPut, "/api/student/1?validate_only=true", OtherStudent()).AssertStatus(t, http.StatusConflict)
+			},
+		},
+		{
+			Name: "validate update of missing student", Method: http.MethodPut, Path: "/api/student/999999?validate_only=true",
+			Body: OtherStudent(), WantStatus: http.StatusNotFound,
+			Check: errorContains("student not found"),
+		},
+
+		// DELETE /api/student/{id} + trash
+		{
+			Name: "delete student", Method: http.MethodDelete, Path: "/api/student/%d",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				srv.Do(t, http.MethodGet, "/api/student/1", nil).AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodGet, "/api/students/trash", nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPost, "/api/student/1/restore", nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, "/api/student/1", nil).AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "delete missing student", Method: http.MethodDelete, Path: "/api/student/999999",
+			WantStatus: http.StatusNotFound,
+			Check:      errorContains("no student found"),
+		},
+		{
+			Name: "delete with non-numeric id", Method: http.MethodDelete, Path: "/api/student/abc",
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "restore student not in trash", Method: http.MethodPost, Path: "/api/student/%d/restore",
+			WantStatus: http.StatusNotFound,
+			Check:      errorContains("no deleted student"),
+		},
+		{
+			Name: "list empty trash", Method: http.MethodGet, Path: "/api/students/trash",
+			WantStatus: http.StatusOK,
+		},
+
+		// PATCH/DELETE /api/students/bulk
+		{
+			Name: "bulk update students", Method: http.MethodPatch, Path: "/api/students/bulk",
+			Body:       map[string]any{"ids": []int64{1, 999999, 1}, "changes": map[string]any{"gender": "undisclosed", "phone": "+14155550123"}},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var body struct {
+					Updated int                `json:"updated"`
+					Failed  int                `json:"failed"`
+					Results []types.BulkResult `json:"results"`
+				}
+				res.DecodeJSON(t, &body)
+				if body.Updated != 1 || body.Failed != 1 || len(body.Results) != 2 ||
+					body.Results[0].Status != types.BulkUpdated || body.Results[1].Status != types.BulkNotFound {
+					t.Fatalf("bulk update = %+v", body)
+				}
+
+				var student types.Student
+				srv.Do(t, http.MethodGet, "/api/student/1", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &student)
+				if student.Gender != "undisclosed" || student.Phone != "+14155550123" || student.Name != ValidStudent().Name {
+					t.Fatalf("student after bulk update = %+v", student)
+				}
+			},
+		},
+		{
+			Name: "bulk update with an invalid student", Method: http.MethodPatch, Path: "/api/students/bulk",
+			Body:       map[string]any{"ids": []int64{1}, "changes": map[string]any{"date_of_birth": BornYearsAgo(120)}},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "failed", float64(1))
+				if !strings.Contains(string(res.Body), `"status":"invalid"`) || !strings.Contains(string(res.Body), "want 1 to 100") {
+					t.Fatalf("bulk update = %s", res.Body)
+				}
+
+				// 🧩 custom values merge, and null removes one
+				srv.Do(t, http.MethodPost, "/api/admin/custom-fields", map[string]any{"key": "house", "type": "string"}).
+					AssertStatus(t, http.StatusCreated)
+				srv.Do(t, http.MethodPost, "/api/admin/custom-fields", map[string]any{"key": "locker", "type": "number"}).
+					AssertStatus(t, http.StatusCreated)
+				srv.Do(t, http.MethodPatch, "/api/students/bulk", map[string]any{"ids": []int64{1}, "changes": map[string]any{"custom": map[string]any{"house": "red", "locker": 7}}}).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "updated", float64(1))
+				srv.Do(t, http.MethodPatch, "/api/students/bulk", map[string]any{"ids": []int64{1}, "changes": map[string]any{"custom": map[string]any{"locker": nil}}}).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "updated", float64(1))
+
+				var student types.Student
+				srv.Do(t, http.MethodGet, "/api/student/1", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &student)
+				if len(student.Custom) != 1 || student.Custom["house"] != "red" || student.DateOfBirth != ValidStudent().DateOfBirth {
+					t.Fatalf("student after bulk updates = %+v", student)
+				}
+			},
+		},
+		{
+			Name: "bulk update of name refused", Method: http.MethodPatch, Path: "/api/students/bulk",
+			Body:       map[string]any{"ids": []int64{1}, "changes": map[string]any{"name": "Everyone"}},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("unknown field"),
+		},
+		{
+			Name: "bulk update without changes", Method: http.MethodPatch, Path: "/api/students/bulk",
+			Body:       map[string]any{"ids": []int64{1}, "changes": map[string]any{}},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("changes must set"),
+		},
+		{
+			Name: "bulk delete students", Method: http.MethodDelete, Path: "/api/students/bulk",
+			Body:       map[string]any{"ids": []int64{1, 2, 999999}},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "deleted", float64(1))
+				res.AssertJSONField(t, "failed", float64(2))
+
+				var trash []types.Student
+				srv.Do(t, http.MethodGet, "/api/students/trash", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &trash)
+				if len(trash) != 1 || trash[0].ID != 1 {
+					t.Fatalf("trash after bulk delete = %+v", trash)
+				}
+			},
+		},
+		{
+			Name: "bulk delete without ids", Method: http.MethodDelete, Path: "/api/students/bulk",
+			Body:       map[string]any{"ids": []int64{}},
+			WantStatus: http.StatusBadRequest,
+		},
+
+		// POST/GET /api/student/{id}/photo
+		{
+			Name: "upload and get photo", Method: http.MethodGet, Path: "/api/student/%d/photo",
+			WantStatus: http.StatusNotFound,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertErrorContains(t, "has no photo")
+
+				srv.Upload(t, "/api/student/1/photo", "photo", "ada.png", PNG()).
+					AssertStatus(t, http.StatusCreated).
+					AssertJSONField(t, "content_type", "image/png")
+
+				photo := srv.Do(t, http.MethodGet, "/api/student/1/photo", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "image/png")
+				etag := photo.Header.Get("ETag")
+				if etag == "" || photo.Header.Get("Cache-Control") == "" {
+					t.Fatalf("photo response lacks caching headers: %v", photo.Header)
+				}
+
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/student/1/photo", nil)
+				req.Header.Set("If-None-Match", etag)
+				srv.Send(t, req).AssertStatus(t, http.StatusNotModified)
+			},
+		},
+		{
+			Name: "upload photo of unsupported type", Method: http.MethodPost, Path: "/api/student/%d/photo",
+			Body: map[string]any{"photo": "not multipart"}, WantStatus: http.StatusBadRequest,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertErrorContains(t, "multipart/form-data")
+				srv.Upload(t, "/api/student/1/photo", "photo", "notes.txt", []byte("hello")).
+					AssertStatus(t, http.StatusUnsupportedMediaType)
+				srv.Upload(t, "/api/student/1/photo", "photo", "big.png", append(PNG(), make([]byte, 1<<20)...)).
+					AssertStatus(t, http.StatusRequestEntityTooLarge)
+			},
+		},
+		{
+			Name: "upload photo for missing student", Method: http.MethodPost, Path: "/api/student/999999/photo",
+			WantStatus: http.StatusNotFound,
+		},
+
+		// /api/student/{id}/documents
+		{
+			Name: "upload, list, download and delete a document", Method: http.MethodGet, Path: "/api/student/%d/documents",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var empty []map[string]any
+				res.DecodeJSON(t, &empty)
+				if len(empty) != 0 {
+					t.Fatalf("documents = %v, want none", empty)
+				}
+
+				created := srv.UploadForm(t, "/api/student/1/documents", map[string]string{"kind": "transcript"}, "file", "transcript.pdf", PDF()).
+					AssertStatus(t, http.StatusCreated).
+					AssertJSONField(t, "content_type", "application/pdf").
+					AssertJSONField(t, "kind", "transcript")
+				var doc struct {
+					ID  int64  `json:"id"`
+					URL string `json:"url"`
+				}
+				created.DecodeJSON(t, &doc)
+
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/student/1/documents/%d", doc.ID), nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "filename", "transcript.pdf")
+				srv.Do(t, http.MethodGet, doc.URL, nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "application/pdf")
+
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/1/documents/%d", doc.ID), nil).
+					AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/student/1/documents/%d", doc.ID), nil).
+					AssertStatus(t, http.StatusNotFound)
+			},
+		},
+		{
+			Name: "upload document with invalid kind", Method: http.MethodPost, Path: "/api/student/%d/documents",
+			Body: "", WantStatus: http.StatusBadRequest,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				srv.UploadForm(t, "/api/student/1/documents", map[string]string{"kind": "selfie"}, "file", "x.pdf", PDF()).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "field Kind is invalid")
+				srv.UploadForm(t, "/api/student/1/documents", map[string]string{"kind": "other"}, "file", "x.txt", []byte("plain text")).
+					AssertStatus(t, http.StatusUnsupportedMediaType)
+			},
+		},
+		{
+			Name: "documents of missing student", Method: http.MethodGet, Path: "/api/student/999999/documents",
+			WantStatus: http.StatusNotFound,
+		},
+
+		// /api/guardians, /api/student/{id}/guardians
+		{
+			Name: "create a guardian, link siblings and include guardians", Method: http.MethodPost, Path: "/api/guardians",
+			Body:       map[string]any{"name": "Jane Doe", "email": "jane@example.com", "phone": "+14155550123"},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var jane types.Guardian
+				res.AssertJSONField(t, "name", "Jane Doe").DecodeJSON(t, &jane)
+				var john types.Guardian
+				srv.Do(t, http.MethodPost, "/api/guardians", map[string]any{"name": "John Doe"}).
+					AssertStatus(t, http.StatusCreated).DecodeJSON(t, &john)
+				sibling := Seed(t, srv.Storage, types.Student{Name: "Sam Doe", Email: "sam.doe@example.com", Age: 12, DateOfBirth: BornYearsAgo(12)})[0]
+
+				link := func(studentID, guardianID int64, relationship string) {
+					srv.Do(t, http.MethodPut, fmt.Sprintf("/api/student/%d/guardians/%d", studentID, guardianID), map[string]any{"relationship": relationship}).
+						AssertStatus(t, http.StatusOK).
+						AssertJSONField(t, "relationship", relationship)
+				}
+				link(1, jane.ID, "guardian")
+				link(1, jane.ID, "mother") // relinking changes the relationship
+				link(1, john.ID, "father")
+				link(sibling.ID, jane.ID, "mother")
+
+				var ada types.StudentResource
+				srv.Do(t, http.MethodGet, "/api/student/1?include=guardians", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &ada)
+				if len(ada.Guardians) != 2 || ada.Guardians[0].ID != jane.ID || ada.Guardians[0].Relationship != "mother" ||
+					ada.Guardians[0].Phone != "+14155550123" || ada.Guardians[1].Relationship != "father" {
+					t.Fatalf("guardians = %+v", ada.Guardians)
+				}
+				var sparse struct {
+					Guardians []types.StudentGuardian `json:"guardians"`
+				}
+				srv.Do(t, http.MethodGet, "/api/student/1?fields=id,name&include=guardians", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "name", "Ada Lovelace").
+					DecodeJSON(t, &sparse)
+				if len(sparse.Guardians) != 2 || sparse.Guardians[1].Name != "John Doe" {
+					t.Fatalf("sparse guardians = %+v", sparse.Guardians)
+				}
+				var plain types.StudentResource
+				srv.Do(t, http.MethodGet, "/api/student/1", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &plain)
+				if plain.Guardians != nil {
+					t.Fatalf("guardians without include = %+v", plain.Guardians)
+				}
+
+				var withStudents types.Guardian
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/guardians/%d", jane.ID), nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &withStudents)
+				if len(withStudents.Students) != 2 || withStudents.Students[0].StudentID != 1 || withStudents.Students[1].StudentID != sibling.ID {
+					t.Fatalf("students of guardian = %+v", withStudents.Students)
+				}
+
+				srv.Do(t, http.MethodPut, fmt.Sprintf("/api/guardians/%d", john.ID), map[string]any{"name": "John A. Doe", "email": "john@example.com"}).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "email", "john@example.com")
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/1/guardians/%d", john.ID), nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/1/guardians/%d", john.ID), nil).AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/guardians/%d", jane.ID), nil).AssertStatus(t, http.StatusOK)
+
+				var left []types.StudentGuardian
+				srv.Do(t, http.MethodGet, "/api/student/1/guardians", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &left)
+				if len(left) != 0 {
+					t.Fatalf("guardians after unlink and delete = %+v", left)
+				}
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/guardians/%d", john.ID), nil).AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "invalid guardian", Method: http.MethodPost, Path: "/api/guardians",
+			Body:       map[string]any{"name": "", "email": "not-an-email", "phone": "555"},
+			WantStatus: http.StatusBadRequest,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertErrorContains(t, "Name").
+					AssertErrorContains(t, "Email").
+					AssertErrorContains(t, "Phone")
+				srv.Do(t, http.MethodPut, "/api/student/1/guardians/999999", map[string]any{"relationship": "mother"}).
+					AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodPut, "/api/student/1/guardians/1", map[string]any{"relationship": "aunt"}).
+					AssertStatus(t, http.StatusBadRequest)
+				srv.Do(t, http.MethodGet, "/api/student/1?include=grades", nil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "include must list fields from guardians, documents, invoices")
+				srv.Do(t, http.MethodGet, "/api/guardians/999999", nil).
+					AssertStatus(t, http.StatusNotFound)
+			},
+		},
+
+		// ?include= on student lists, pages and batches
+		{
+			Name: "include related resources in student lists", Method: http.MethodGet, Path: "/api/students?limit=100&include=guardians,invoices,documents",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var page types.Page[types.StudentResource]
+				res.DecodeJSON(t, &page)
+				for _, student := range page.Data {
+					if student.Guardians == nil || student.Invoices == nil || student.Documents == nil {
+						t.Fatalf("student %d lacks an included relation: %+v", student.ID, student)
+					}
+				}
+
+				seeded := Seed(t, srv.Storage,
+					types.Student{Name: "Mia Include", Email: "mia.include@example.com", Age: 15, DateOfBirth: BornYearsAgo(15)},
+					types.Student{Name: "Leo Include", Email: "leo.include@example.com", Age: 13, DateOfBirth: BornYearsAgo(13)},
+				)
+				mia, leo := seeded[0].ID, seeded[1].ID
+				var parent types.Guardian
+				srv.Do(t, http.MethodPost, "/api/guardians", map[string]any{"name": "Pat Include"}).
+					AssertStatus(t, http.StatusCreated).DecodeJSON(t, &parent)
+				srv.Do(t, http.MethodPut, fmt.Sprintf("/api/student/%d/guardians/%d", mia, parent.ID), map[string]any{"relationship": "parent"}).
+					AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/invoices", leo), map[string]any{"amount": 900, "currency": "EUR", "due_date": "2020-01-31"}).
+					AssertStatus(t, http.StatusCreated)
+
+				var batch types.StudentBatch
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/students?ids=%d,%d&include=guardians,invoices", mia, leo), nil).
+					AssertStatus(t, http.StatusOK).DecodeJSON(t, &batch)
+				if len(batch.Data) != 2 || len(batch.Data[0].Guardians) != 1 || len(batch.Data[0].Invoices) != 0 ||
+					len(batch.Data[1].Guardians) != 0 || len(batch.Data[1].Invoices) != 1 || !batch.Data[1].Invoices[0].Overdue {
+					t.Fatalf("batch with includes = %+v", batch.Data)
+				}
+				srv.Do(t, http.MethodPost, "/api/students/batch-get?include=guardians", map[string]any{"ids": []int64{mia}}).
+					AssertStatus(t, http.StatusOK).DecodeJSON(t, &batch)
+				if len(batch.Data) != 1 || len(batch.Data[0].Guardians) != 1 || batch.Data[0].Guardians[0].Relationship != "parent" {
+					t.Fatalf("batch-get with includes = %+v", batch.Data)
+				}
+
+				var sparse []map[string]any
+				srv.Do(t, http.MethodGet, "/api/students?fields=name&include=invoices&sort=-age", nil).
+					AssertStatus(t, http.StatusOK).DecodeJSON(t, &sparse)
+				for _, student := range sparse {
+					if _, ok := student["invoices"].([]any); !ok || len(student) != 2 {
+						t.Fatalf("sparse student with includes = %v", student)
+					}
+				}
+
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/guardians/%d", parent.ID), nil).AssertStatus(t, http.StatusOK)
+			},
+		},
+
+		// /api/student/{id}/invoices, /api/invoices
+		{
+			Name: "bill a student, pay the invoice and report overdue ones", Method: http.MethodPost, Path: "/api/student/%d/invoices",
+			Body:       map[string]any{"amount": 12500, "currency": "EUR", "description": "Autumn term", "due_date": "2020-09-30"},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var late types.Invoice
+				res.AssertJSONField(t, "status", types.InvoiceOpen).
+					AssertJSONField(t, "overdue", true).
+					DecodeJSON(t, &late)
+				var due types.Invoice
+				srv.Do(t, http.MethodPost, "/api/student/1/invoices", map[string]any{"amount": 900, "currency": "EUR", "due_date": "2020-10-31"}).
+					AssertStatus(t, http.StatusCreated).DecodeJSON(t, &due)
+				srv.Do(t, http.MethodPost, "/api/student/1/invoices", map[string]any{"amount": 4000, "currency": "USD", "due_date": "2999-01-01"}).
+					AssertStatus(t, http.StatusCreated).
+					AssertJSONField(t, "overdue", false)
+
+				var report types.OverdueReport
+				srv.Do(t, http.MethodGet, "/api/invoices/overdue", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &report)
+				if report.Count != 2 || report.Invoices[0].ID != late.ID || report.Totals["EUR"] != 13400 || report.Totals["USD"] != 0 {
+					t.Fatalf("overdue report = %+v", report)
+				}
+				srv.Do(t, http.MethodGet, "/api/invoices/overdue?as_of=2020-10-01", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &report)
+				if report.Count != 1 || report.Invoices[0].ID != late.ID {
+					t.Fatalf("overdue as of 2020-10-01 = %+v", report)
+				}
+
+				// paying is idempotent per reference
+				pay := fmt.Sprintf("/api/invoices/%d/pay", late.ID)
+				srv.Do(t, http.MethodPost, pay, map[string]any{"payment_reference": "pay-001"}).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "status", types.InvoicePaid).
+					AssertJSONField(t, "overdue", false)
+				srv.Do(t, http.MethodPost, pay, map[string]any{"payment_reference": "pay-001"}).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "payment_reference", "pay-001")
+				srv.Do(t, http.MethodPost, pay, map[string]any{"payment_reference": "pay-002"}).
+					AssertStatus(t, http.StatusConflict).
+					AssertErrorContains(t, "already paid")
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/invoices/%d/pay", due.ID), map[string]any{"payment_reference": "pay-001"}).
+					AssertStatus(t, http.StatusConflict).
+					AssertErrorContains(t, "payment reference already used")
+
+				srv.Do(t, http.MethodGet, "/api/invoices/overdue", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &report)
+				if report.Count != 1 || report.Invoices[0].ID != due.ID {
+					t.Fatalf("overdue after payment = %+v", report)
+				}
+				var list []types.Invoice
+				srv.Do(t, http.MethodGet, "/api/student/1/invoices", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &list)
+				if len(list) != 3 || list[0].ID != late.ID || list[0].PaidAt == nil {
+					t.Fatalf("invoices = %+v", list)
+				}
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/invoices/%d", due.ID), nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "amount", float64(900))
+			},
+		},
+		{
+			Name: "invalid invoice", Method: http.MethodPost, Path: "/api/student/%d/invoices",
+			Body:       map[string]any{"amount": 0, "currency": "eur", "due_date": "30/09/2026"},
+			WantStatus: http.StatusBadRequest,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertErrorContains(t, "Amount").
+					AssertErrorContains(t, "Currency").
+					AssertErrorContains(t, "DueDate")
+				srv.Do(t, http.MethodGet, "/api/invoices/overdue?as_of=soon", nil).
+					AssertStatus(t, http.StatusBadRequest)
+				srv.Do(t, http.MethodPost, "/api/invoices/999999/pay", map[string]any{"payment_reference": "x"}).
+					AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodPost, "/api/invoices/1/pay", map[string]any{}).
+					AssertStatus(t, http.StatusBadRequest)
+			},
+		},
+		{
+			Name: "invoices of missing student", Method: http.MethodGet, Path: "/api/student/999999/invoices",
+			WantStatus: http.StatusNotFound,
+		},
+
+		// GDPR export and erasure
+		{
+			Name: "export student data", Method: http.MethodGet, Path: "/api/student/%d/data-export",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var bundle struct {
+					Student  types.Student      `json:"student"`
+					AuditLog []types.AuditEntry `json:"audit_log"`
+				}
+				res.DecodeJSON(t, &bundle)
+				if bundle.Student.Email != ValidStudent().Email || len(bundle.AuditLog) != 1 || bundle.AuditLog[0].Action != types.AuditDataExported {
+					t.Fatalf("data export = %+v", bundle)
+				}
+
+				srv.Upload(t, "/api/student/1/photo", "photo", "ada.png", PNG()).AssertStatus(t, http.StatusCreated)
+				srv.UploadForm(t, "/api/student/1/documents", map[string]string{"kind": "transcript"}, "file", "transcript.pdf", PDF()).
+					AssertStatus(t, http.StatusCreated)
+				bundled := srv.Do(t, http.MethodGet, "/api/student/1/data-export?format=zip", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "application/zip")
+				archive, err := zip.NewReader(bytes.NewReader(bundled.Body), int64(len(bundled.Body)))
+				if err != nil {
+					t.Fatalf("data export zip: %v", err)
+				}
+				var files []string
+				for _, f := range archive.File {
+					files = append(files, f.Name)
+				}
+				if !slices.Equal(files, []string{"data.json", "photo.png", "documents/1-transcript.pdf"}) {
+					t.Fatalf("data export zip files = %v", files)
+				}
+			},
+		},
+		{
+			Name: "export data with unknown format", Method: http.MethodGet, Path: "/api/student/%d/data-export?format=xml",
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "erase student", Method: http.MethodDelete, Path: "/api/student/%d/erase",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var trash []types.Student
+				srv.Do(t, http.MethodGet, "/api/students/trash", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &trash)
+				if len(trash) != 1 || trash[0].Name != "Erased student" {
+					t.Fatalf("trash after erase = %+v", trash)
+				}
+
+				var bundle struct {
+					Student  types.Student      `json:"student"`
+					AuditLog []types.AuditEntry `json:"audit_log"`
+				}
+				srv.Do(t, http.MethodGet, "/api/student/1/data-export", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &bundle)
+				if bundle.Student.Name != "Erased student" || strings.Contains(bundle.Student.Email, ValidStudent().Email) {
+					t.Fatalf("erased student = %+v", bundle.Student)
+				}
+				if len(bundle.AuditLog) != 2 || bundle.AuditLog[0].Action != types.AuditErased {
+					t.Fatalf("audit log = %+v", bundle.AuditLog)
+				}
+
+				// erasing again is allowed (trash included) and the email is free
+				srv.Do(t, http.MethodDelete, "/api/student/1/erase", nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPost, "/api/student", ValidStudent()).AssertStatus(t, http.StatusCreated)
+			},
+		},
+		{
+			Name: "erase student deletes its email jobs", Method: http.MethodDelete, Path: "/api/student/%d/erase",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				jobs := As[storage.JobStore](t, srv.Storage)
+				other := Seed(t, srv.Storage, OtherStudent())[0]
+				enqueue := func(to string) int64 {
+					payload, _ := json.Marshal(map[string]string{"To": to, "Subject": "Welcome", "Text": "Hello " + to})
+					id, err := jobs.EnqueueJob(types.Job{Type: storage.EmailJobType, Payload: payload, MaxAttempts: 1, RunAt: time.Now()})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return id
+				}
+				enqueue(other.Email)
+				if err := jobs.CompleteJob(enqueue(other.Email)); err != nil {
+					t.Fatal(err)
+				}
+				kept := enqueue("someone@example.com")
+
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d/erase", other.ID), nil).AssertStatus(t, http.StatusOK)
+				left, err := jobs.GetJobs("", 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(left) != 1 || left[0].ID != kept {
+					t.Fatalf("jobs after erase = %+v, want only the one to someone else", left)
+				}
+
+				// finished jobs go with jobs.purge, queued ones stay
+				if err := jobs.CompleteJob(enqueue("done@example.com")); err != nil {
+					t.Fatal(err)
+				}
+				if purged, err := jobs.PurgeFinishedJobs(time.Now().Add(time.Second)); err != nil || purged != 1 {
+					t.Fatalf("PurgeFinishedJobs = %d, %v; want 1", purged, err)
+				}
+				if left, _ := jobs.GetJobs("", 10); len(left) != 1 || left[0].ID != kept {
+					t.Fatalf("jobs after purge = %+v", left)
+				}
+			},
+		},
+		{
+			Name: "erase missing student", Method: http.MethodDelete, Path: "/api/student/999999/erase",
+			WantStatus: http.StatusNotFound,
+		},
+
+		// Exports
+		{
+			Name: "export students as csv", Method: http.MethodPost, Path: "/api/exports?format=csv",
+			WantStatus: http.StatusAccepted,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var exp types.Export
+				res.DecodeJSON(t, &exp)
+				res.AssertHeader(t, "Location", fmt.Sprintf("/api/exports/%d", exp.ID))
+
+				// export again once there are students to write
+				seeded := Seed(t, srv.Storage, Students(3)...)
+				srv.Do(t, http.MethodPost, "/api/exports", nil).AssertStatus(t, http.StatusAccepted).DecodeJSON(t, &exp)
+
+				srv.AwaitJob(t, exp.ID)
+				status := srv.Do(t, http.MethodGet, fmt.Sprintf("/api/exports/%d", exp.ID), nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "status", types.JobSucceeded)
+				status.DecodeJSON(t, &exp)
+				if exp.URL != fmt.Sprintf("/api/exports/%d/download", exp.ID) {
+					t.Fatalf("export url = %q", exp.URL)
+				}
+
+				file := srv.Do(t, http.MethodGet, exp.URL, nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "text/csv; charset=utf-8")
+				if !strings.HasPrefix(string(file.Body), "id,name,email,age,phone,date_of_birth,gender,address_line1,") {
+					t.Fatalf("export body = %q", file.Body)
+				}
+				for _, s := range seeded {
+					if !strings.Contains(string(file.Body), s.Email) {
+						t.Errorf("export is missing %s", s.Email)
+					}
+				}
+			},
+		},
+		{
+			Name: "export with unknown format", Method: http.MethodPost, Path: "/api/exports?format=xlsx",
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("format must be one of"),
+		},
+		{
+			Name: "get missing export", Method: http.MethodGet, Path: "/api/exports/999999",
+			WantStatus: http.StatusNotFound,
+		},
+
+		// Admin
+		{
+			Name: "purge trash", Method: http.MethodPost, Path: "/api/admin/purge?older_than=0s",
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name: "purge with invalid duration", Method: http.MethodPost, Path: "/api/admin/purge?older_than=soon&async=maybe",
+			WantStatus: http.StatusBadRequest,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertErrorContains(t, "older_than must be a duration").
+					AssertErrorContains(t, "async must be true or false")
+			},
+		},
+		{
+			Name: "re-encrypt on a backend without encryption", Method: http.MethodPost, Path: "/api/admin/encryption/reencrypt",
+			WantStatus: http.StatusNotImplemented,
+			Check:      errorContains("field encryption not supported"),
+			Backends:   []string{"memory"},
+		},
+		{
+			Name: "re-encrypt with encryption disabled", Method: http.MethodPost, Path: "/api/admin/encryption/reencrypt",
+			WantStatus: http.StatusNotImplemented,
+			Check:      errorContains("encryption.enabled"),
+			Backends:   []string{"sqlite", "postgres"},
+		},
+		{
+			Name: "snapshot students and diff two snapshots", Method: http.MethodPost, Path: "/api/admin/snapshots",
+			WantStatus: http.StatusAccepted,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var empty types.Snapshot
+				res.DecodeJSON(t, &empty)
+				res.AssertHeader(t, "Location", fmt.Sprintf("/api/admin/snapshots/%d", empty.ID))
+				srv.AwaitJob(t, empty.ID)
+
+				seeded := Seed(t, srv.Storage, Students(3)...)
+				var before, after types.Snapshot
+				srv.Do(t, http.MethodPost, "/api/admin/snapshots", nil).AssertStatus(t, http.StatusAccepted).DecodeJSON(t, &before)
+				srv.AwaitJob(t, before.ID)
+
+				// one student each added, removed and renamed
+				renamed := seeded[0]
+				renamed.Name = "Renamed Student"
+				srv.Do(t, http.MethodPut, fmt.Sprintf("/api/student/%d", renamed.ID), renamed).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d", seeded[1].ID), nil).AssertStatus(t, http.StatusOK)
+				added := Seed(t, srv.Storage, types.Student{Name: "Grace Hopper", Email: "grace@example.com", Age: 85, DateOfBirth: BornYearsAgo(85)})[0]
+				srv.Do(t, http.MethodPost, "/api/admin/snapshots", nil).AssertStatus(t, http.StatusAccepted).DecodeJSON(t, &after)
+				srv.AwaitJob(t, after.ID)
+
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/admin/snapshots/%d", after.ID), nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "status", types.JobSucceeded)
+				var list []types.Snapshot
+				srv.Do(t, http.MethodGet, "/api/admin/snapshots", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &list)
+				if len(list) != 3 || list[0].ID != after.ID {
+					t.Fatalf("snapshots = %+v", list)
+				}
+
+				var diff types.SnapshotDiff
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/admin/snapshots/%d/%d/diff", empty.ID, before.ID), nil).
+					AssertStatus(t, http.StatusOK).DecodeJSON(t, &diff)
+				if len(diff.Added) != 3 || len(diff.Removed) != 0 || len(diff.Changed) != 0 {
+					t.Fatalf("diff from empty = %+v", diff)
+				}
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/admin/snapshots/%d/%d/diff", before.ID, after.ID), nil).
+					AssertStatus(t, http.StatusOK).DecodeJSON(t, &diff)
+				if len(diff.Added) != 1 || diff.Added[0].ID != added.ID {
+					t.Errorf("added = %+v", diff.Added)
+				}
+				if len(diff.Removed) != 1 || diff.Removed[0].ID != seeded[1].ID {
+					t.Errorf("removed = %+v", diff.Removed)
+				}
+				if len(diff.Changed) != 1 || diff.Changed[0].ID != renamed.ID || !slices.Equal(diff.Changed[0].Fields, []string{"name"}) ||
+					diff.Changed[0].Before.Name != seeded[0].Name || diff.Changed[0].After.Name != "Renamed Student" {
+					t.Errorf("changed = %+v", diff.Changed)
+				}
+
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/admin/snapshots/%d/999999/diff", before.ID), nil).
+					AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodGet, "/api/admin/snapshots/1/x/diff", nil).
+					AssertStatus(t, http.StatusBadRequest)
+			},
+		},
+		{
+			Name: "snapshot of an unknown tenant", Method: http.MethodPost, Path: "/api/admin/snapshots?tenant=nope",
+			WantStatus: http.StatusNotFound,
+			Check:      errorContains("unknown tenant"),
+		},
+		{
+			Name: "backup on a backend without backups", Method: http.MethodGet, Path: "/api/admin/backup",
+			WantStatus: http.StatusNotImplemented,
+			Check:      errorContains("backups not supported"),
+			Backends:   []string{"memory"},
+		},
+		{
+			Name: "backup of a sqlite database", Method: http.MethodGet, Path: "/api/admin/backup",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				if !bytes.HasPrefix(res.Body, []byte("SQLite format 3\x00")) {
+					t.Fatalf("backup starts %q, want a sqlite database file", res.Body[:min(len(res.Body), 16)])
+				}
+			},
+			Backends: []string{"sqlite"},
+		},
+		{
+			Name: "purge trash as a job", Method: http.MethodPost, Path: "/api/admin/purge?older_than=0s&async=true",
+			WantStatus: http.StatusAccepted,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var body struct {
+					JobID int64 `json:"job_id"`
+				}
+				res.DecodeJSON(t, &body)
+				if job := srv.AwaitJob(t, body.JobID); job.Status != types.JobSucceeded {
+					t.Fatalf("purge job = %+v", job)
+				}
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/admin/jobs/%d", body.JobID), nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "type", "trash.purge")
+				srv.Do(t, http.MethodGet, "/api/admin/jobs?status=succeeded", nil).
+					AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/admin/jobs/%d/retry", body.JobID), nil).
+					AssertStatus(t, http.StatusNotFound)
+			},
+		},
+		{
+			Name: "create API key", Method: http.MethodPost, Path: "/api/admin/api-keys",
+			Body:       map[string]any{"name": "nightly sync", "scopes": []string{"read", "write"}},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var key types.APIKey
+				res.DecodeJSON(t, &key)
+				if !strings.HasPrefix(key.Key, "sk_") || !strings.HasPrefix(key.Key, key.Prefix) {
+					t.Fatalf("created key = %+v", key)
+				}
+
+				list := srv.Do(t, http.MethodGet, "/api/admin/api-keys", nil).AssertStatus(t, http.StatusOK)
+				if strings.Contains(string(list.Body), key.Key) {
+					t.Fatalf("listing leaks the key: %s", list.Body)
+				}
+
+				path := fmt.Sprintf("/api/admin/api-keys/%d", key.ID)
+				srv.Do(t, http.MethodDelete, path, nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodDelete, path, nil).AssertStatus(t, http.StatusNotFound)
+			},
+		},
+		{
+			Name: "create API key with unknown scope", Method: http.MethodPost, Path: "/api/admin/api-keys",
+			Body:       map[string]any{"name": "sync", "scopes": []string{"everything"}},
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "API keys required when auth is enabled", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				// a long-lived auth cache: revoking must still take effect at once
+				cfg.Auth = config.Auth{Enabled: true, BootstrapKey: strings.Repeat("b", 32), Cache: config.AuthCache{Size: 100, TTL: time.Hour}}
+				srv := NewServerWithConfig(t, cfg)
+				send := func(method, path, key string, body any) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					if key != "" {
+						req.Header.Set("X-API-Key", key)
+					}
+					return srv.Send(t, req)
+				}
+
+				send(http.MethodGet, "/api/students", "", nil).AssertStatus(t, http.StatusUnauthorized)
+				send(http.MethodGet, "/api/students", "sk_guess", nil).AssertStatus(t, http.StatusUnauthorized)
+
+				var key types.APIKey
+				send(http.MethodPost, "/api/admin/api-keys", cfg.Auth.BootstrapKey, map[string]any{"name": "reports", "scopes": []string{"read"}}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &key)
+
+				send(http.MethodGet, "/api/students", key.Key, nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodPost, "/api/student", key.Key, ValidStudent()).
+					AssertStatus(t, http.StatusForbidden).
+					AssertErrorContains(t, "lack the write scope")
+				send(http.MethodGet, "/api/admin/api-keys", key.Key, nil).AssertStatus(t, http.StatusForbidden)
+
+				send(http.MethodDelete, fmt.Sprintf("/api/admin/api-keys/%d", key.ID), cfg.Auth.BootstrapKey, nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/students", key.Key, nil).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "revoked")
+			},
+		},
+		{
+			Name: "bearer tokens rotate and revoke", Method: http.MethodPost, Path: "/api/auth/refresh",
+			Body:       map[string]any{"refresh_token": "rt_unknown"},
+			WantStatus: http.StatusNotImplemented,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Auth = config.Auth{
+					Enabled: true, BootstrapKey: strings.Repeat("b", 32),
+					TokenSecret: strings.Repeat("s", 32), AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour,
+					Cache: config.AuthCache{Size: 100, TTL: time.Hour},
+				}
+				srv := NewServerWithConfig(t, cfg)
+				send := func(method, path string, headers map[string]string, body any) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					for k, v := range headers {
+						req.Header.Set(k, v)
+					}
+					return srv.Send(t, req)
+				}
+				bearer := func(pair types.TokenPair) map[string]string {
+					return map[string]string{"Authorization": "Bearer " + pair.AccessToken}
+				}
+
+				var key types.APIKey
+				send(http.MethodPost, "/api/admin/api-keys", map[string]string{"X-API-Key": cfg.Auth.BootstrapKey}, map[string]any{"name": "etl", "scopes": []string{"read"}}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &key)
+
+				var first, second types.TokenPair
+				send(http.MethodPost, "/api/auth/token", map[string]string{"X-API-Key": key.Key}, nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &first)
+				send(http.MethodGet, "/api/students", bearer(first), nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodPost, "/api/student", bearer(first), ValidStudent()).AssertStatus(t, http.StatusForbidden)
+				send(http.MethodGet, "/api/students", map[string]string{"Authorization": "Bearer " + first.AccessToken + "x"}, nil).
+					AssertStatus(t, http.StatusUnauthorized)
+
+				send(http.MethodPost, "/api/auth/refresh", nil, map[string]any{"refresh_token": first.RefreshToken}).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &second)
+				if second.RefreshToken == first.RefreshToken {
+					t.Fatal("refresh token was not rotated")
+				}
+
+				// replaying the used token revokes the whole session
+				send(http.MethodPost, "/api/auth/refresh", nil, map[string]any{"refresh_token": first.RefreshToken}).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "already used")
+				send(http.MethodPost, "/api/auth/refresh", nil, map[string]any{"refresh_token": second.RefreshToken}).
+					AssertStatus(t, http.StatusUnauthorized)
+
+				send(http.MethodPost, "/api/auth/logout", bearer(second), nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/students", bearer(second), nil).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "revoked")
+
+				// revoking the key ends its live sessions: access and refresh tokens alike
+				var third types.TokenPair
+				send(http.MethodPost, "/api/auth/token", map[string]string{"X-API-Key": key.Key}, nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &third)
+				send(http.MethodGet, "/api/students", bearer(third), nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodDelete, fmt.Sprintf("/api/admin/api-keys/%d", key.ID), map[string]string{"X-API-Key": cfg.Auth.BootstrapKey}, nil).
+					AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/students", bearer(third), nil).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "revoked")
+				send(http.MethodPost, "/api/auth/refresh", nil, map[string]any{"refresh_token": third.RefreshToken}).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "revoked")
+			},
+		},
+		{
+			Name: "OIDC ID tokens map roles to scopes", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				idp := NewIdP(t)
+				cfg := Config()
+				cfg.Auth = config.Auth{Enabled: true, OIDC: config.OIDC{
+					Issuer: idp.Issuer, ClientID: "student-api", RolesClaim: "groups",
+					Roles: map[string]string{"teachers": "read,write", "it-admins": "admin"},
+				}}
+				srv := NewServerWithConfig(t, cfg)
+				send := func(method, path, bearer string, body any) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					req.Header.Set("Authorization", "Bearer "+bearer)
+					return srv.Send(t, req)
+				}
+				token := func(overrides map[string]any) string {
+					claims := map[string]any{
+						"iss": idp.Issuer, "aud": "student-api", "sub": "u1", "email": "t@school.edu",
+						"groups": []string{"teachers"}, "exp": time.Now().Add(time.Hour).Unix(),
+					}
+					for k, v := range overrides {
+						claims[k] = v
+					}
+					return idp.Sign(t, claims)
+				}
+
+				send(http.MethodGet, "/api/students", token(nil), nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodPost, "/api/student", token(nil), ValidStudent()).AssertStatus(t, http.StatusCreated)
+				send(http.MethodGet, "/api/admin/api-keys", token(nil), nil).AssertStatus(t, http.StatusForbidden)
+				send(http.MethodGet, "/api/admin/api-keys", token(map[string]any{"groups": "it-admins"}), nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/students", token(map[string]any{"groups": []string{"parents"}}), nil).AssertStatus(t, http.StatusForbidden)
+
+				send(http.MethodGet, "/api/students", token(map[string]any{"aud": "other-app"}), nil).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "not issued for this client")
+				send(http.MethodGet, "/api/students", token(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}), nil).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "expired")
+				send(http.MethodGet, "/api/students", token(nil)+"x", nil).AssertStatus(t, http.StatusUnauthorized)
+			},
+		},
+		{
+			Name: "credentials reach only their own tenant", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				idp := NewIdP(t)
+				cfg := Config()
+				cfg.Tenancy.Enabled = true
+				cfg.Auth = config.Auth{
+					Enabled: true, BootstrapKey: strings.Repeat("b", 32),
+					TokenSecret: strings.Repeat("s", 32), AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour,
+					OIDC: config.OIDC{Issuer: idp.Issuer, ClientID: "student-api", TenantClaim: "school", DefaultScopes: []string{"read"}},
+				}
+				srv := NewServerWithConfig(t, cfg)
+				send := func(method, path, tenant string, headers map[string]string, body any) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					if tenant != "" {
+						req.Header.Set("X-Tenant", tenant)
+					}
+					for k, v := range headers {
+						req.Header.Set(k, v)
+					}
+					return srv.Send(t, req)
+				}
+				admin := map[string]string{"X-API-Key": cfg.Auth.BootstrapKey}
+
+				var acme types.Tenant
+				send(http.MethodPost, "/api/admin/tenants", "", admin, map[string]any{"slug": "acme", "name": "Acme"}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &acme)
+				var ownKey, acmeKey types.APIKey
+				send(http.MethodPost, "/api/admin/api-keys", "", admin, map[string]any{"name": "own", "scopes": []string{"read"}}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &ownKey)
+				send(http.MethodPost, "/api/admin/api-keys", "", admin, map[string]any{"name": "acme", "scopes": []string{"read"}, "tenant_id": acme.ID}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &acmeKey)
+				if ownKey.TenantID != storage.DefaultTenantID || acmeKey.TenantID != acme.ID {
+					t.Fatalf("key tenants = %d, %d; want %d, %d", ownKey.TenantID, acmeKey.TenantID, storage.DefaultTenantID, acme.ID)
+				}
+				send(http.MethodPost, "/api/admin/api-keys", "", admin, map[string]any{"name": "nowhere", "scopes": []string{"read"}, "tenant_id": 999}).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "unknown tenant_id 999")
+
+				// a key, the tokens it's exchanged for, an OIDC identity: each
+				// works in its tenant and is refused in the other, whatever
+				// X-Tenant says
+				var pair types.TokenPair
+				send(http.MethodPost, "/api/auth/token", "", map[string]string{"X-API-Key": acmeKey.Key}, nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &pair)
+				identity := func(claims map[string]any) map[string]string {
+					claims["iss"], claims["aud"], claims["sub"], claims["exp"] = idp.Issuer, "student-api", "u1", time.Now().Add(time.Hour).Unix()
+					return map[string]string{"Authorization": "Bearer " + idp.Sign(t, claims)}
+				}
+				for _, c := range []struct {
+					name    string
+					headers map[string]string
+					home    string
+				}{
+					{"default key", map[string]string{"X-API-Key": ownKey.Key}, "default"},
+					{"acme key", map[string]string{"X-API-Key": acmeKey.Key}, "acme"},
+					{"acme token", map[string]string{"Authorization": "Bearer " + pair.AccessToken}, "acme"},
+					{"acme identity", identity(map[string]any{"school": "ACME"}), "acme"},
+				} {
+					for _, tenant := range []string{"default", "acme"} {
+						res := send(http.MethodGet, "/api/students", tenant, c.headers, nil)
+						if tenant == c.home {
+							res.AssertStatus(t, http.StatusOK)
+							continue
+						}
+						res.AssertStatus(t, http.StatusForbidden).AssertErrorContains(t, "not valid for tenant")
+					}
+				}
+				send(http.MethodGet, "/api/students", "default", identity(map[string]any{}), nil).AssertStatus(t, http.StatusForbidden)
+
+				// the bootstrap key is the operator's: every tenant
+				send(http.MethodGet, "/api/students", "acme", admin, nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/students", "default", admin, nil).AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "admin dashboard disabled", Method: http.MethodGet, Path: "/admin/",
+			WantStatus: http.StatusNotFound,
+		},
+		{
+			Name: "admin dashboard served without credentials", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.AdminUI.Enabled = true
+				cfg.Tenancy.Enabled = true
+				cfg.Auth = config.Auth{Enabled: true, BootstrapKey: strings.Repeat("b", 32)}
+				srv := NewServerWithConfig(t, cfg)
+
+				page := srv.Do(t, http.MethodGet, "/admin/", nil).AssertStatus(t, http.StatusOK)
+				if !strings.Contains(page.Header.Get("Content-Type"), "text/html") || !strings.Contains(string(page.Body), "app.js") {
+					t.Fatalf("dashboard page = %s %q", page.Header.Get("Content-Type"), page.Body)
+				}
+				if page.Header.Get("Content-Security-Policy") == "" {
+					t.Fatal("dashboard served without a CSP")
+				}
+				srv.Do(t, http.MethodGet, "/admin/app.js", nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusUnauthorized)
+			},
+		},
+		{
+			Name: "admin dashboard preloads its assets", Method: http.MethodGet, Path: "/admin/",
+			WantStatus: http.StatusNotFound,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.AdminUI.Enabled = true
+				srv := NewServerWithConfig(t, cfg)
+
+				want := []string{"</admin/style.css>; rel=preload; as=style", "</admin/app.js>; rel=preload; as=script"}
+				page := srv.Do(t, http.MethodGet, "/admin/", nil).AssertStatus(t, http.StatusOK)
+				if got := page.Header.Values("Link"); !slices.Equal(got, want) {
+					t.Fatalf("dashboard Link = %q, want %q", got, want)
+				}
+				if got := srv.Do(t, http.MethodGet, "/admin/app.js", nil).Header.Values("Link"); len(got) != 0 {
+					t.Fatalf("asset Link = %q, want none", got)
+				}
+			},
+		},
+		{
+			Name: "student writes log ids, not emails", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, _ *Response) {
+				var logs syncBuffer
+				prev := slog.Default()
+				slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+				defer slog.SetDefault(prev)
+
+				update := OtherStudent()
+				update.Email = "other.renamed@example.com"
+				srv.Do(t, http.MethodPost, "/api/student", OtherStudent()).AssertStatus(t, http.StatusCreated)
+				srv.Do(t, http.MethodPut, "/api/student/1", update).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPut, "/api/students/by-email/"+ValidStudent().Email, ValidStudent()).AssertStatus(t, http.StatusCreated)
+
+				// the notifier logs recipients on its own; this is about the handlers
+				var writes []string
+				for _, line := range strings.Split(logs.String(), "\n") {
+					if strings.Contains(line, "public_id=") {
+						writes = append(writes, line)
+					}
+				}
+				if len(writes) != 3 {
+					t.Fatalf("logs = %s, want the public id once per write", logs.String())
+				}
+				for _, line := range writes {
+					if strings.Contains(line, "@example.com") {
+						t.Fatalf("write log %q names an email", line)
+					}
+				}
+			},
+		},
+		{
+			Name: "debug payload logging redacts PII", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				var logs syncBuffer
+				prev := slog.Default()
+				slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+				defer slog.SetDefault(prev)
+
+				cfg := Config()
+				cfg.Env = "dev"
+				cfg.Logger.Payloads = config.Payloads{Header: "X-Debug-Payloads", SampleRate: 1, MaxBytes: 4096}
+				srv := NewServerWithConfig(t, cfg)
+
+				srv.Do(t, http.MethodPost, "/api/student", ValidStudent()).AssertStatus(t, http.StatusCreated)
+				if strings.Contains(logs.String(), "Payload") {
+					t.Fatal("payload logged without the debug header")
+				}
+
+				req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/student", encodeBody(t, OtherStudent()))
+				req.Header.Set("X-Debug-Payloads", "1")
+				srv.Send(t, req).AssertStatus(t, http.StatusCreated)
+
+				var out string
+				for _, line := range strings.Split(logs.String(), "\n") {
+					if strings.Contains(line, "Payload") {
+						out += line
+					}
+				}
+				if !strings.Contains(out, "Payload") || !strings.Contains(out, "status=201") || !strings.Contains(out, OtherStudent().Name) {
+					t.Fatalf("payload not logged: %s", out)
+				}
+				if strings.Contains(out, OtherStudent().Email) || !strings.Contains(out, "[REDACTED]") {
+					t.Fatalf("email not redacted: %s", out)
+				}
+			},
+		},
+		{
+			Name: "access log in JSON, common and combined format", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Auth = config.Auth{Enabled: true, BootstrapKey: strings.Repeat("b", 32)}
+				srv := NewServerWithConfig(t, cfg)
+				var key types.APIKey
+				req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/admin/api-keys", encodeBody(t, map[string]any{"name": "logs", "scopes": []string{"read"}}))
+				req.Header.Set("X-API-Key", cfg.Auth.BootstrapKey)
+				srv.Send(t, req).AssertStatus(t, http.StatusCreated).DecodeJSON(t, &key)
+
+				// served in process, so each line is written by the time the
+				// recorder returns
+				serve := func(format, apiKey string) (*httptest.ResponseRecorder, string) {
+					var out bytes.Buffer
+					req := httptest.NewRequest(http.MethodGet, "/api/students?limit=1", nil)
+					req.Header.Set("User-Agent", `evil"agent`+"\n")
+					req.Header.Set("Referer", "https://school.example/")
+					if apiKey != "" {
+						req.Header.Set("X-API-Key", apiKey)
+					}
+					rec := httptest.NewRecorder()
+					middleware.AccessLog(format, &out)(srv.Config.Handler).ServeHTTP(rec, req)
+					return rec, out.String()
+				}
+
+				rec, line := serve("combined", key.Key)
+				prefix := fmt.Sprintf(`192.0.2.1 - key:%d [`, key.ID)
+				suffix := fmt.Sprintf(`] "GET /api/students?limit=1 HTTP/1.1" 200 %d "https://school.example/" "evil\"agent\x0a"`+"\n", rec.Body.Len())
+				if rec.Code != http.StatusOK || !strings.HasPrefix(line, prefix) || !strings.HasSuffix(line, suffix) {
+					t.Fatalf("combined line = %q, want %q…%q", line, prefix, suffix)
+				}
+				if _, err := time.Parse(`02/Jan/2006:15:04:05 -0700`, line[len(prefix):len(line)-len(suffix)]); err != nil {
+					t.Fatalf("combined line timestamp: %v", err)
+				}
+
+				rec, line = serve("common", "")
+				suffix = fmt.Sprintf(`] "GET /api/students?limit=1 HTTP/1.1" 401 %d`+"\n", rec.Body.Len())
+				if !strings.HasPrefix(line, "192.0.2.1 - - [") || !strings.HasSuffix(line, suffix) {
+					t.Fatalf("common line = %q, want an anonymous 401", line)
+				}
+
+				rec, line = serve("json", key.Key)
+				var entry struct {
+					Msg       string `json:"msg"`
+					RequestID string `json:"request_id"`
+					User      string `json:"user"`
+					URI       string `json:"uri"`
+					Status    int    `json:"status"`
+					Bytes     int    `json:"bytes"`
+					UserAgent string `json:"user_agent"`
+				}
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("json line %q: %v", line, err)
+				}
+				if entry.Msg != "access" || entry.RequestID != rec.Header().Get("X-Request-ID") || entry.RequestID == "" ||
+					entry.User != fmt.Sprintf("key:%d", key.ID) || entry.URI != "/api/students?limit=1" ||
+					entry.Status != http.StatusOK || entry.Bytes != rec.Body.Len() || entry.UserAgent != "evil\"agent\n" {
+					t.Fatalf("json line = %s", line)
+				}
+			},
+		},
+		{
+			Name: "quotas disabled", Method: http.MethodGet, Path: "/api/admin/quotas",
+			WantStatus: http.StatusNotImplemented,
+			Check:      errorContains("quotas are disabled"),
+		},
+		{
+			Name: "daily quotas per API key", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Auth = config.Auth{Enabled: true, BootstrapKey: strings.Repeat("b", 32)}
+				cfg.Quotas = config.Quotas{Enabled: true, DailyRequests: 3, DailyWrites: 1}
+				srv := NewServerWithConfig(t, cfg)
+				send := func(method, path, key string, body any) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					req.Header.Set("X-API-Key", key)
+					return srv.Send(t, req)
+				}
+				admin := cfg.Auth.BootstrapKey
+
+				var key types.APIKey
+				send(http.MethodPost, "/api/admin/api-keys", admin, map[string]any{"name": "sync", "scopes": []string{"read", "write"}}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &key)
+				subject := fmt.Sprintf("/api/admin/quotas/key:%d", key.ID)
+
+				send(http.MethodGet, "/api/students", key.Key, nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "X-RateLimit-Limit", "3").
+					AssertHeader(t, "X-RateLimit-Remaining", "2")
+				send(http.MethodPost, "/api/student", key.Key, ValidStudent()).
+					AssertStatus(t, http.StatusCreated).
+					AssertHeader(t, "X-RateLimit-Writes-Remaining", "0")
+				send(http.MethodPost, "/api/student", key.Key, OtherStudent()).
+					AssertStatus(t, http.StatusTooManyRequests).
+					AssertErrorContains(t, "daily write quota of 1 exceeded")
+				res := send(http.MethodGet, "/api/students", key.Key, nil).AssertStatus(t, http.StatusTooManyRequests)
+				if res.Header.Get("Retry-After") == "" {
+					t.Fatal("429 without Retry-After")
+				}
+
+				var q types.Quota
+				send(http.MethodGet, subject, admin, nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &q)
+				if q.Usage.Requests != 4 || q.Usage.Writes != 2 || q.Custom {
+					t.Fatalf("quota = %+v", q)
+				}
+
+				// raising the limit lets the key through again
+				send(http.MethodPut, subject, admin, map[string]any{"daily_requests": 0, "daily_writes": 10}).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "custom", true)
+				send(http.MethodPost, "/api/student", key.Key, OtherStudent()).AssertStatus(t, http.StatusCreated)
+				send(http.MethodPut, subject, admin, map[string]any{"daily_requests": 5}).AssertStatus(t, http.StatusBadRequest)
+				send(http.MethodGet, "/api/admin/quotas/nobody", admin, nil).AssertStatus(t, http.StatusBadRequest)
+
+				send(http.MethodDelete, subject, admin, nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodDelete, subject, admin, nil).AssertStatus(t, http.StatusNotFound)
+				send(http.MethodGet, "/api/students", key.Key, nil).AssertStatus(t, http.StatusTooManyRequests)
+			},
+		},
+		{
+			Name: "list jobs with invalid status", Method: http.MethodGet, Path: "/api/admin/jobs?status=done",
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "get missing job", Method: http.MethodGet, Path: "/api/admin/jobs/999999",
+			WantStatus: http.StatusNotFound,
+		},
+		{
+			Name: "list schedules", Method: http.MethodGet, Path: "/api/admin/schedules",
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name: "runtime diagnostics", Method: http.MethodGet, Path: "/api/admin/runtime",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var info types.Runtime
+				res.DecodeJSON(t, &info)
+				if info.Goroutines < 1 || info.GoVersion == "" || info.Memory.Sys == 0 {
+					t.Fatalf("runtime = %+v, want goroutines, version and memory", info)
+				}
+				// only the SQL backends have connection pools
+				if (len(info.Pools) == 0) != (srv.Backend == "memory") {
+					t.Fatalf("db_pools = %v on the %s backend", info.Pools, srv.Backend)
+				}
+			},
+		},
+		{
+			Name: "query plans need an SQL backend", Method: http.MethodGet, Path: "/api/admin/explain/students?limit=20",
+			WantStatus: http.StatusNotImplemented,
+			Backends:   []string{"memory"},
+		},
+		{
+			Name: "query plans of the student list", Method: http.MethodGet, Path: "/api/admin/explain/students?limit=20",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var body types.QueryPlan
+				res.DecodeJSON(t, &body)
+				if body.Backend != srv.Backend || len(body.Plan) == 0 {
+					t.Fatalf("explain = %+v, want a %s plan", body, srv.Backend)
+				}
+			},
+			Backends: []string{"sqlite", "postgres"},
+		},
+		{
+			Name: "index checks need an SQL backend", Method: http.MethodGet, Path: "/api/admin/indexes",
+			WantStatus: http.StatusNotImplemented,
+			Backends:   []string{"memory"},
+		},
+		{
+			Name: "index checks find every expected index", Method: http.MethodGet, Path: "/api/admin/indexes",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var body types.IndexReport
+				res.DecodeJSON(t, &body)
+				if body.Backend != srv.Backend || body.Missing != 0 {
+					t.Fatalf("indexes = %+v, want none missing on %s", body, srv.Backend)
+				}
+			},
+			Backends: []string{"sqlite", "postgres"},
+		},
+		{
+			Name: "pprof only when debug is enabled", Method: http.MethodGet, Path: "/debug/pprof/",
+			WantStatus: http.StatusNotFound,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Debug = config.Debug{Enabled: true}
+				cfg.Auth = config.Auth{Enabled: true, BootstrapKey: strings.Repeat("b", 32)}
+				srv := NewServerWithConfig(t, cfg)
+
+				srv.Do(t, http.MethodGet, "/debug/vars", nil).AssertStatus(t, http.StatusUnauthorized)
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/debug/pprof/goroutine?debug=1", nil)
+				req.Header.Set("X-API-Key", strings.Repeat("b", 32))
+				srv.Send(t, req).AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "storage metrics for prometheus", Method: http.MethodGet, Path: "/api/student/999999",
+			WantStatus: http.StatusNotFound,
+			Check: func(t testing.TB, srv *Server, _ *Response) {
+				res := srv.Do(t, http.MethodGet, "/metrics", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", metrics.ContentType)
+				body := string(res.Body)
+				for _, want := range []string{
+					`student_api_storage_calls_total{backend="%s",method="GetStudentById"} 1`,
+					`student_api_storage_errors_total{backend="%s",method="GetStudentById"} 1`,
+					`student_api_storage_call_duration_seconds_bucket{backend="%s",method="GetStudentById",le="+Inf"} 1`,
+					`student_api_storage_call_duration_seconds_count{backend="%s",method="GetStudentById"} 1`,
+				} {
+					if want = fmt.Sprintf(want, srv.Backend); !strings.Contains(body, want) {
+						t.Fatalf("metrics missing %q:\n%s", want, body)
+					}
+				}
+
+				// optional capabilities (TrashStore here) are counted too
+				srv.Do(t, http.MethodGet, "/api/students/trash", nil).AssertStatus(t, http.StatusOK)
+				body = string(srv.Do(t, http.MethodGet, "/metrics", nil).Body)
+				if !strings.Contains(body, `method="GetDeletedStudents"} 1`) {
+					t.Fatalf("trash listing not counted:\n%s", body)
+				}
+
+				cfg := Config()
+				cfg.Metrics.Enabled = false
+				NewServerWithConfig(t, cfg).Do(t, http.MethodGet, "/metrics", nil).AssertStatus(t, http.StatusNotFound)
+			},
+		},
+		{
+			Name: "chaos fails and slows the chosen storage calls in dev only", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Env = "dev"
+				cfg.Chaos = config.Chaos{Enabled: true, ErrorRate: 1, Methods: []string{"GetStudents"}}
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, ValidStudent())
+				// sorted by name, the list loads every row with GetStudents
+				srv.Do(t, http.MethodGet, "/api/students?sort=name", nil).
+					AssertStatus(t, http.StatusInternalServerError).
+					AssertErrorContains(t, chaos.ErrInjected.Error())
+				srv.Do(t, http.MethodGet, "/api/student/1", nil).AssertStatus(t, http.StatusOK)
+				if !strings.Contains(string(srv.Do(t, http.MethodGet, "/metrics", nil).Body), `student_api_storage_errors_total{backend="memory",method="GetStudents"} 1`) {
+					t.Fatal("injected fault not counted in the metrics")
+				}
+
+				cfg.Chaos = config.Chaos{Enabled: true, Latency: 50 * time.Millisecond, Methods: []string{"GetStudentById"}}
+				start := time.Now()
+				NewServerWithConfig(t, cfg).Do(t, http.MethodGet, "/api/student/1", nil)
+				if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+					t.Fatalf("request took %s, want the 50ms injected latency", elapsed)
+				}
+
+				// outside dev the same settings do nothing
+				cfg.Env = "test"
+				cfg.Chaos.ErrorRate = 1
+				NewServerWithConfig(t, cfg).Do(t, http.MethodGet, "/api/students?sort=name", nil).AssertStatus(t, http.StatusOK)
+			},
+		},
+		{
+			Name: "a route's timeout answers 503 while a slow backend is still working", Method: http.MethodPost, Path: "/api/students/batch-get",
+			Body:       map[string]any{"ids": []int{1}},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				store := memory.New()
+				Seed(t, store, ValidStudent())
+				slow := chaos.New(config.Chaos{Enabled: true, Latency: time.Second, Methods: []string{"GetStudentsByIds"}}, "dev").Wrap(store)
+				reg := registry.New()
+				reg.HandleFunc("POST /api/students/batch-get", student.BatchGet(slow, links.New("")), registry.Meta{Timeout: 50 * time.Millisecond})
+
+				rec := httptest.NewRecorder()
+				start := time.Now()
+				middleware.Routes(reg)(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/students/batch-get", strings.NewReader(`{"ids":[1]}`)))
+				if took := time.Since(start); took >= time.Second {
+					t.Fatalf("batch-get took %s, want the 50ms route timeout", took)
+				}
+				if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Content-Type") != response.ProblemJSON || !strings.Contains(rec.Body.String(), "took too long") {
+					t.Fatalf("timed out batch-get = %d %s %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+				}
+			},
+		},
+		{
+			Name: "slow storage calls are logged redacted and counted", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				var logs syncBuffer
+				prev := slog.Default()
+				slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+				defer slog.SetDefault(prev)
+
+				cfg := Config()
+				cfg.Env = "dev"
+				cfg.Metrics.SlowQueryThreshold = 20 * time.Millisecond
+				cfg.Chaos = config.Chaos{Enabled: true, Latency: 30 * time.Millisecond, Methods: []string{"GetStudentsWhere"}}
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, ValidStudent())
+				srv.Do(t, http.MethodPost, "/api/admin/custom-fields", map[string]any{"key": "house", "type": "string"}).
+					AssertStatus(t, http.StatusCreated)
+
+				srv.Do(t, http.MethodGet, "/api/students?custom.house=gryffindor&sort=name", nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, "/api/student/1", nil).AssertStatus(t, http.StatusOK)
+
+				body := string(srv.Do(t, http.MethodGet, "/metrics", nil).Body)
+				for _, want := range []string{
+					`student_api_storage_slow_queries_total{backend="memory",method="GetStudentsWhere"} 1`,
+					`student_api_storage_slow_queries_total{backend="memory",method="GetStudentById"} 0`,
+				} {
+					if !strings.Contains(body, want) {
+						t.Fatalf("metrics missing %q:\n%s", want, body)
+					}
+				}
+				line := logs.String()
+				if !strings.Contains(line, "Slow storage call") || !strings.Contains(line, "method=GetStudentsWhere") ||
+					!strings.Contains(line, "backend=memory") || !strings.Contains(line, "{house}") || strings.Contains(line, "gryffindor") {
+					t.Fatalf("slow call not logged with redacted args:\n%s", line)
+				}
+			},
+		},
+		{
+			Name: "transient write errors are retried, reads are not", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Env = "dev"
+				cfg.Chaos = config.Chaos{Enabled: true, ErrorRate: 1, Methods: []string{"CreateStudent", "GetStudents", "DeleteStudentById"}}
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, ValidStudent())
+
+				// the create handler answers every storage error with 400
+				srv.Do(t, http.MethodPost, "/api/student", OtherStudent()).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, chaos.ErrInjected.Error())
+				srv.Do(t, http.MethodGet, "/api/students?sort=name", nil).AssertStatus(t, http.StatusInternalServerError)
+				body := string(srv.Do(t, http.MethodGet, "/metrics", nil).Body)
+				for _, want := range []string{
+					`student_api_storage_calls_total{backend="memory",method="CreateStudent"} 3`,
+					`student_api_storage_calls_total{backend="memory",method="GetStudents"} 1`,
+				} {
+					if !strings.Contains(body, want) {
+						t.Fatalf("metrics missing %q:\n%s", want, body)
+					}
+				}
+
+				cfg.StorageRetry.Enabled = false
+				srv = NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, ValidStudent())
+				// like "not found", any storage error of a delete answers 404
+				srv.Do(t, http.MethodDelete, "/api/student/1", nil).AssertStatus(t, http.StatusNotFound)
+				if body := string(srv.Do(t, http.MethodGet, "/metrics", nil).Body); !strings.Contains(body, `method="DeleteStudentById"} 1`) {
+					t.Fatalf("delete tried more than once with retries disabled:\n%s", body)
+				}
+			},
+		},
+		{
+			Name: "list webhooks", Method: http.MethodGet, Path: "/api/admin/webhooks",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				// 🪝 Subscribe a local receiver, then update the seeded student
+				received := make(chan *http.Request, 1)
+				bodies := make(chan []byte, 1)
+				receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					received <- r
+					bodies <- body
+				}))
+				defer receiver.Close()
+
+				var hook types.Webhook
+				srv.Do(t, http.MethodPost, "/api/admin/webhooks", map[string]any{"url": receiver.URL, "events": []string{"student.updated"}}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &hook)
+				if hook.Secret == "" {
+					t.Fatal("created webhook has no secret")
+				}
+				srv.Do(t, http.MethodPut, "/api/student/1", OtherStudent()).AssertStatus(t, http.StatusOK)
+
+				select {
+				case r := <-received:
+					body := <-bodies
+					want := webhook.Sign(hook.Secret, r.Header.Get(webhook.HeaderTimestamp), body)
+					if r.Header.Get(webhook.HeaderEvent) != "student.updated" || r.Header.Get(webhook.HeaderSignature) != want {
+						t.Fatalf("delivery headers = %v", r.Header)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatal("webhook was not delivered")
+				}
+
+				path := fmt.Sprintf("/api/admin/webhooks/%d/deliveries", hook.ID)
+				deadline := time.Now().Add(2 * time.Second)
+				for {
+					var deliveries []types.WebhookDelivery
+					srv.Do(t, http.MethodGet, path, nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &deliveries)
+					if len(deliveries) == 1 && deliveries[0].Status == types.DeliverySucceeded {
+						break
+					}
+					if time.Now().After(deadline) {
+						t.Fatalf("deliveries = %+v", deliveries)
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			},
+		},
+		{
+			Name: "create webhook with invalid event", Method: http.MethodPost, Path: "/api/admin/webhooks",
+			Body:       map[string]any{"url": "https://example.com/hook", "events": []string{"student.exploded"}},
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "create webhook with non-http url", Method: http.MethodPost, Path: "/api/admin/webhooks",
+			Body:       map[string]any{"url": "ftp://example.com/hook", "events": []string{"*"}},
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "delete missing webhook", Method: http.MethodDelete, Path: "/api/admin/webhooks/999999",
+			WantStatus: http.StatusNotFound,
+		},
+		{
+			Name: "request ids are forwarded to webhooks", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				if res.Header.Get("X-Request-ID") == "" {
+					t.Fatal("response has no X-Request-ID")
+				}
+
+				// 🪪 The delivery joins the caller's trace as a new span
+				received := make(chan http.Header, 1)
+				receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					received <- r.Header
+				}))
+				defer receiver.Close()
+				srv.Do(t, http.MethodPost, "/api/admin/webhooks", map[string]any{"url": receiver.URL, "events": []string{"student.updated"}}).
+					AssertStatus(t, http.StatusCreated)
+
+				const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+				req, _ := http.NewRequest(http.MethodPut, srv.URL+"/api/student/1", encodeBody(t, OtherStudent()))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("X-Request-ID", "req-1338")
+				req.Header.Set("traceparent", parent)
+				srv.Send(t, req).AssertStatus(t, http.StatusOK).AssertHeader(t, "X-Request-ID", "req-1338")
+
+				select {
+				case h := <-received:
+					tp := h.Get("traceparent")
+					if h.Get("X-Request-ID") != "req-1338" || !strings.HasPrefix(tp, parent[:36]) || tp == parent {
+						t.Fatalf("delivery headers = %v", h)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatal("webhook was not delivered")
+				}
+			},
+		},
+		{
+			Name: "webhooks are relayed from the outbox", Method: http.MethodGet, Path: "/api/admin/webhooks",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				received := make(chan webhook.Envelope, 4)
+				receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var envelope webhook.Envelope
+					json.NewDecoder(r.Body).Decode(&envelope)
+					received <- envelope
+				}))
+				defer receiver.Close()
+				srv.Do(t, http.MethodPost, "/api/admin/webhooks", map[string]any{"url": receiver.URL, "events": []string{"student.created", "student.deleted"}}).
+					AssertStatus(t, http.StatusCreated)
+
+				// 📤 A rejected write leaves no event; one made straight through
+				// storage (as if the process died after committing) still goes out
+				srv.Do(t, http.MethodPost, "/api/student", ValidStudent()).AssertStatus(t, http.StatusBadRequest)
+				id := Seed(t, srv.Storage, OtherStudent())[0].ID
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d", id), nil).AssertStatus(t, http.StatusOK)
+
+				var events []webhook.Envelope
+				for range 2 {
+					select {
+					case envelope := <-received:
+						events = append(events, envelope)
+					case <-time.After(2 * time.Second):
+						t.Fatalf("events delivered = %+v, want 2", events)
+					}
+				}
+				created, _ := events[0].Data.(map[string]any)
+				deleted, _ := events[1].Data.(map[string]any)
+				if events[0].Event != "student.created" || created["email"] != OtherStudent().Email || created["id"] != float64(id) {
+					t.Fatalf("first event = %+v", events[0])
+				}
+				if events[1].Event != "student.deleted" || deleted["id"] != float64(id) || events[1].ID <= events[0].ID {
+					t.Fatalf("second event = %+v", events[1])
+				}
+			},
+		},
+		{
+			Name: "create tenant", Method: http.MethodPost, Path: "/api/admin/tenants",
+			Body:       map[string]any{"slug": "acme", "name": "Acme High"},
+			WantStatus: http.StatusCreated,
+		},
+		{
+			Name: "create tenant with invalid slug", Method: http.MethodPost, Path: "/api/admin/tenants",
+			Body:       map[string]any{"slug": "ACME", "name": "Acme High"},
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "list tenants", Method: http.MethodGet, Path: "/api/admin/tenants",
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name: "admin stats", Method: http.MethodGet, Path: "/api/admin/stats",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "total_students", float64(1))
+			},
+		},
+		{
+			Name: "admin stats for unknown tenant", Method: http.MethodGet, Path: "/api/admin/stats?tenant=nope",
+			WantStatus: http.StatusNotFound,
+		},
+		{
+			Name: "admin stats count days in the display timezone", Method: http.MethodGet, Path: "/api/admin/stats?tz=Pacific/Kiritimati",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				// UTC+14: the seeded student was created on today's date there
+				var stats types.Stats
+				res.DecodeJSON(t, &stats)
+				kiritimati, _ := time.LoadLocation("Pacific/Kiritimati")
+				last := stats.CreatedPerDay[len(stats.CreatedPerDay)-1]
+				if stats.Timezone != "Pacific/Kiritimati" || last.Date != time.Now().In(kiritimati).Format("2006-01-02") || last.Count != 1 {
+					t.Fatalf("stats in %s end with %+v, want today there with 1", stats.Timezone, last)
+				}
+				srv.Do(t, http.MethodGet, "/api/admin/stats", nil).AssertStatus(t, http.StatusOK).AssertJSONField(t, "timezone", "UTC")
+				srv.Do(t, http.MethodGet, "/api/admin/stats?tz=Mars/Olympus_Mons", nil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "unknown timezone")
+			},
+		},
+
+		// Custom fields
+		{
+			Name: "define custom field", Method: http.MethodPost, Path: "/api/admin/custom-fields",
+			Body:       map[string]any{"key": "enrollment_year", "type": "number"},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "source", "api")
+
+				// 🧩 Values are typed, filterable and keys must be defined
+				for year, name := range map[int]string{2023: "Ada Custom", 2024: "Alan Custom"} {
+					body := map[string]any{
+						"name": name, "email": strings.ReplaceAll(strings.ToLower(name), " ", ".") + "@example.com",
+						"date_of_birth": BornYearsAgo(20), "custom": map[string]any{"enrollment_year": year},
+					}
+					srv.Do(t, http.MethodPost, "/api/student", body).AssertStatus(t, http.StatusCreated)
+				}
+				var students []types.Student
+				srv.Do(t, http.MethodGet, "/api/students?custom.enrollment_year=2024", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &students)
+				if len(students) != 1 || students[0].Name != "Alan Custom" || students[0].Custom["enrollment_year"] != float64(2024) {
+					t.Fatalf("filtered list = %+v", students)
+				}
+
+				var page types.Page[types.StudentResource]
+				srv.Do(t, http.MethodGet, "/api/students?limit=1&custom.enrollment_year=2023", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &page)
+				if len(page.Data) != 1 || page.HasMore {
+					t.Fatalf("filtered page = %+v", page)
+				}
+
+				srv.Do(t, http.MethodGet, "/api/students?custom.enrollment_year=soon", nil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "must be a number")
+				srv.Do(t, http.MethodGet, "/api/students?custom.house=red", nil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "not a defined custom field")
+
+				bad := ValidStudent()
+				bad.Email = "typed@example.com"
+				bad.Custom = map[string]any{"enrollment_year": "2024", "house": "red"}
+				srv.Do(t, http.MethodPost, "/api/student", bad).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "custom field enrollment_year must be a number,custom field house is not defined")
+			},
+		},
+		{
+			Name: "define duplicate custom field", Method: http.MethodPost, Path: "/api/admin/custom-fields",
+			Body:       map[string]any{"key": "house", "type": "string"},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				srv.Do(t, http.MethodPost, "/api/admin/custom-fields", map[string]any{"key": "house", "type": "number"}).
+					AssertStatus(t, http.StatusConflict)
+			},
+		},
+		{
+			Name: "define custom field with invalid key", Method: http.MethodPost, Path: "/api/admin/custom-fields",
+			Body:       map[string]any{"key": "House Colour", "type": "string"},
+			WantStatus: http.StatusBadRequest,
+			Check:      errorContains("lower snake_case"),
+		},
+		{
+			Name: "define custom field with unknown type", Method: http.MethodPost, Path: "/api/admin/custom-fields",
+			Body:       map[string]any{"key": "house", "type": "colour"},
+			WantStatus: http.StatusBadRequest,
+		},
+		{
+			Name: "required custom field from config", Method: http.MethodGet, Path: "/api/admin/custom-fields",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				var defs []types.CustomField
+				res.DecodeJSON(t, &defs)
+				if len(defs) != 0 {
+					t.Fatalf("custom fields = %+v, want none", defs)
+				}
+
+				cfg := Config()
+				cfg.CustomFields = []config.CustomField{{Key: "student_number", Type: types.CustomString, Required: true}}
+				srv := NewServerWithConfig(t, cfg)
+
+				srv.Do(t, http.MethodPost, "/api/student", ValidStudent()).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "custom field student_number is required")
+
+				student := ValidStudent()
+				student.Custom = map[string]any{"student_number": "S-001"}
+				srv.Do(t, http.MethodPost, "/api/student", student).AssertStatus(t, http.StatusCreated)
+
+				srv.Do(t, http.MethodGet, "/api/admin/custom-fields", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &defs)
+				if len(defs) != 1 || defs[0].Source != "config" || !defs[0].Required {
+					t.Fatalf("custom fields = %+v", defs)
+				}
+				srv.Do(t, http.MethodDelete, "/api/admin/custom-fields/student_number", nil).
+					AssertStatus(t, http.StatusConflict)
+			},
+		},
+		{
+			Name: "delete custom field", Method: http.MethodPost, Path: "/api/admin/custom-fields",
+			Body:       map[string]any{"key": "house", "type": "string"},
+			WantStatus: http.StatusCreated,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				srv.Do(t, http.MethodDelete, "/api/admin/custom-fields/house", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "key", "house")
+				srv.Do(t, http.MethodDelete, "/api/admin/custom-fields/house", nil).
+					AssertStatus(t, http.StatusNotFound)
+			},
+		},
+
+		// Roster sync
+		{
+			Name: "list sync connectors", Method: http.MethodGet, Path: "/api/admin/sync",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				if strings.TrimSpace(string(res.Body)) != "[]" {
+					t.Fatalf("connectors = %s, want []", res.Body)
+				}
+				srv.Do(t, http.MethodPost, "/api/admin/sync/nope", nil).AssertStatus(t, http.StatusNotFound)
+			},
+		},
+		{
+			Name: "sync roster from a rest source", Method: http.MethodGet, Path: "/api/admin/sync",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				ada := ValidStudent()
+				ada.Gender = "female"
+				pages := map[string]any{
+					"/roster": map[string]any{
+						"data":   []types.Student{ada, OtherStudent()},
+						"_links": map[string]any{"next": map[string]any{"href": "/roster?page=2"}},
+					},
+					"/roster?page=2": []map[string]any{
+						{"name": "No Email", "date_of_birth": BornYearsAgo(20)},
+						{"name": "Alan Again", "email": OtherStudent().Email, "date_of_birth": BornYearsAgo(30)},
+					},
+				}
+				sis := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("Authorization") != "Bearer sis-token" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					json.NewEncoder(w).Encode(pages[r.URL.RequestURI()])
+				}))
+				t.Cleanup(sis.Close)
+
+				cfg := Config()
+				cfg.Sync.Connectors = []config.SyncConnector{{Name: "district", Type: "rest", URL: sis.URL + "/roster", Token: "sis-token"}}
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, ValidStudent())
+
+				// 🔍 A dry run reports without writing
+				var report types.SyncReport
+				srv.Do(t, http.MethodPost, "/api/admin/sync/district?dry_run=true", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &report)
+				if report.Fetched != 4 || report.Created != 1 || report.Updated != 1 || report.Failed != 2 || !report.DryRun {
+					t.Fatalf("dry run report = %+v", report)
+				}
+				if report.Changes[0].Action != types.SyncUpdate || !slices.Equal(report.Changes[0].Fields, []string{"gender"}) {
+					t.Fatalf("dry run changes = %+v", report.Changes)
+				}
+				var students []types.Student
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &students)
+				if len(students) != 1 || students[0].Gender != "" {
+					t.Fatalf("dry run wrote students: %+v", students)
+				}
+
+				srv.Do(t, http.MethodPost, "/api/admin/sync/district", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &report)
+				if report.Created != 1 || report.Updated != 1 {
+					t.Fatalf("sync report = %+v", report)
+				}
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &students)
+				if len(students) != 2 || students[0].Gender != "female" || students[1].Name != OtherStudent().Name {
+					t.Fatalf("students after sync = %+v", students)
+				}
+				srv.AwaitMail(t, 1)
+
+				// 🔁 Running again finds nothing to do
+				srv.Do(t, http.MethodPost, "/api/admin/sync/district", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &report)
+				if report.Unchanged != 2 || report.Created+report.Updated != 0 {
+					t.Fatalf("second sync report = %+v", report)
+				}
+
+				var connectors []types.SyncConnector
+				srv.Do(t, http.MethodGet, "/api/admin/sync", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &connectors)
+				if len(connectors) != 1 || connectors[0].LastRun == nil || connectors[0].LastRun.Unchanged != 2 {
+					t.Fatalf("connectors = %+v", connectors)
+				}
+			},
+		},
+		{
+			Name: "sync roster from a csv file", Method: http.MethodGet, Path: "/api/admin/sync",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				path := t.TempDir() + "/roster.csv"
+				roster := "id,name,email,date_of_birth,address_line1,address_city,address_postal_code,address_country,custom\n" +
+					"7,Grace Hopper,grace@example.com," + BornYearsAgo(30) + ",1 Navy Way,Arlington,22201,US,\"{\"\"house\"\":\"\"red\"\"}\"\n"
+				if err := os.WriteFile(path, []byte(roster), 0o600); err != nil {
+					t.Fatal(err)
+				}
+
+				cfg := Config()
+				cfg.CustomFields = []config.CustomField{{Key: "house", Type: types.CustomString}}
+				cfg.Sync.Connectors = []config.SyncConnector{{Name: "files", Type: "csv", URL: path}}
+				srv := NewServerWithConfig(t, cfg)
+
+				var report types.SyncReport
+				srv.Do(t, http.MethodPost, "/api/admin/sync/files", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &report)
+				if report.Created != 1 || report.Failed != 0 {
+					t.Fatalf("sync report = %+v", report)
+				}
+				var students []types.Student
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &students)
+				if len(students) != 1 || students[0].Address == nil || students[0].Address.City != "Arlington" || students[0].Custom["house"] != "red" {
+					t.Fatalf("students after sync = %+v", students)
+				}
+
+				// 📭 A missing file changes nothing
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+				srv.Do(t, http.MethodPost, "/api/admin/sync/files", nil).AssertStatus(t, http.StatusBadGateway)
+			},
+		},
+
+		// Email verification
+		{
+			Name: "students verify their email with the emailed link", Method: http.MethodGet, Path: "/api/verify?token=abc",
+			WantStatus: http.StatusNotImplemented,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				res.AssertErrorContains(t, "verification.enabled")
+
+				cfg := Config()
+				cfg.HttpServer.BaseURL = "https://api.example.com"
+				cfg.Verification = config.Verification{Enabled: true, TTL: time.Hour}
+				srv := NewServerWithConfig(t, cfg)
+
+				var created struct{ ID int64 }
+				srv.Do(t, http.MethodPost, "/api/student", ValidStudent()).AssertStatus(t, http.StatusCreated).DecodeJSON(t, &created)
+				// the link in the newest verification email once n were sent
+				link := func(n int) string {
+					t.Helper()
+					sent := srv.AwaitMail(t, n)
+					for i := len(sent) - 1; i >= 0; i-- {
+						if _, after, ok := strings.Cut(sent[i].Text, "https://api.example.com/api/verify?token="); ok {
+							return "/api/verify?token=" + strings.Fields(after)[0]
+						}
+					}
+					t.Fatalf("no verification link in %d emails", len(sent))
+					return ""
+				}
+				verified := func(want bool) {
+					t.Helper()
+					var student types.Student
+					srv.Do(t, http.MethodGet, fmt.Sprintf("/api/student/%d", created.ID), nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &student)
+					var listed []types.Student
+					srv.Do(t, http.MethodGet, fmt.Sprintf("/api/students?verified=%t", want), nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &listed)
+					if student.Verified != want || len(listed) != 1 || listed[0].ID != created.ID {
+						t.Fatalf("verified = %v, listed under verified=%t: %+v", student.Verified, want, listed)
+					}
+				}
+
+				// welcome plus verification email
+				first := link(2)
+				verified(false)
+				srv.Do(t, http.MethodGet, first, nil).AssertStatus(t, http.StatusOK).AssertJSONField(t, "success", true)
+				srv.Do(t, http.MethodGet, first, nil).AssertStatus(t, http.StatusNotFound)
+				verified(true)
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/verify", created.ID), nil).AssertStatus(t, http.StatusConflict)
+				srv.Do(t, http.MethodGet, "/api/students?verified=true&limit=1", nil).AssertStatus(t, http.StatusOK).AssertJSONField(t, "has_more", false)
+				srv.Do(t, http.MethodGet, "/api/students?verified=true&ids=1", nil).AssertStatus(t, http.StatusBadRequest)
+
+				// a new email has to be verified again; only the newest link works
+				moved := ValidStudent()
+				moved.Email = "ada@lovelace.example.com"
+				srv.Do(t, http.MethodPut, fmt.Sprintf("/api/student/%d", created.ID), moved).AssertStatus(t, http.StatusOK)
+				verified(false)
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/verify", created.ID), nil).AssertStatus(t, http.StatusAccepted)
+				stale := link(3)
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/verify", created.ID), nil).AssertStatus(t, http.StatusAccepted)
+				fresh := link(4)
+				srv.Do(t, http.MethodGet, stale, nil).AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodGet, fresh, nil).AssertStatus(t, http.StatusOK)
+				verified(true)
+			},
+		},
+
+		// Student portal
+		{
+			Name: "students sign in to the portal and see only their own record", Method: http.MethodPost, Path: "/api/me/login",
+			Body:       map[string]any{"email": "ada@example.com", "password": "correct horse battery"},
+			WantStatus: http.StatusNotImplemented,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				res.AssertErrorContains(t, "portal.enabled")
+
+				cfg := Config()
+				cfg.Portal = config.Portal{Enabled: true, SessionTTL: time.Hour, MinPasswordLength: 10, MaxFailedLogins: 3, Lockout: time.Minute}
+				srv := NewServerWithConfig(t, cfg)
+				seeded := Seed(t, srv.Storage, ValidStudent(), OtherStudent())
+				ada, password := seeded[0], "correct horse battery"
+				send := func(method, path, bearer string, body any) *Response {
+					t.Helper()
+					req, err := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					if err != nil {
+						t.Fatalf("build request: %v", err)
+					}
+					if bearer != "" {
+						req.Header.Set("Authorization", "Bearer "+bearer)
+					}
+					return srv.Send(t, req)
+				}
+				login := func(password string) *Response {
+					t.Helper()
+					return send(http.MethodPost, "/api/me/login", "", map[string]any{"email": ada.Email, "password": password})
+				}
+				signIn := func() string {
+					t.Helper()
+					var session struct {
+						AccessToken string `json:"access_token"`
+						StudentID   int64  `json:"student_id"`
+					}
+					login(password).AssertStatus(t, http.StatusOK).DecodeJSON(t, &session)
+					if session.AccessToken == "" || session.StudentID != ada.ID {
+						t.Fatalf("login = %+v, want a token for student %d", session, ada.ID)
+					}
+					return session.AccessToken
+				}
+
+				// staff set the first password
+				passwordPath := fmt.Sprintf("/api/student/%d/password", ada.ID)
+				send(http.MethodPut, passwordPath, "", map[string]any{"password": "short"}).AssertStatus(t, http.StatusBadRequest)
+				send(http.MethodPut, "/api/student/999/password", "", map[string]any{"password": password}).AssertStatus(t, http.StatusNotFound)
+				send(http.MethodPut, passwordPath, "", map[string]any{"password": password}).AssertStatus(t, http.StatusOK)
+
+				// unknown emails and wrong passwords look alike
+				send(http.MethodPost, "/api/me/login", "", map[string]any{"email": "nobody@example.com", "password": password}).
+					AssertStatus(t, http.StatusUnauthorized).AssertErrorContains(t, "wrong email or password")
+				login("wrong password!").AssertStatus(t, http.StatusUnauthorized).AssertErrorContains(t, "wrong email or password")
+
+				token := signIn()
+				send(http.MethodGet, "/api/me", "", nil).AssertStatus(t, http.StatusUnauthorized)
+				send(http.MethodGet, "/api/me", "not-a-session", nil).AssertStatus(t, http.StatusUnauthorized)
+				var me types.Student
+				send(http.MethodGet, "/api/me", token, nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &me)
+				if me.ID != ada.ID || me.Email != ada.Email {
+					t.Fatalf("GET /api/me = %+v, want student %d", me, ada.ID)
+				}
+
+				// a password change ends every session
+				send(http.MethodPut, "/api/me/password", token, map[string]any{"current_password": "wrong password!", "password": "a new passphrase"}).
+					AssertStatus(t, http.StatusUnauthorized)
+				send(http.MethodPut, "/api/me/password", token, map[string]any{"current_password": password, "password": "a new passphrase"}).
+					AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/me", token, nil).AssertStatus(t, http.StatusUnauthorized)
+				password = "a new passphrase"
+
+				// three failures in a row lock the login until staff reset it
+				for range 3 {
+					login("wrong password!").AssertStatus(t, http.StatusUnauthorized)
+				}
+				login(password).AssertStatus(t, http.StatusTooManyRequests)
+				send(http.MethodPut, passwordPath, "", map[string]any{"password": password}).AssertStatus(t, http.StatusOK)
+
+				token = signIn()
+				send(http.MethodPost, "/api/me/logout", token, nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/me", token, nil).AssertStatus(t, http.StatusUnauthorized)
+
+				// removing the login ends its sessions too
+				token = signIn()
+				send(http.MethodDelete, passwordPath, "", nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodDelete, passwordPath, "", nil).AssertStatus(t, http.StatusNotFound)
+				send(http.MethodGet, "/api/me", token, nil).AssertStatus(t, http.StatusUnauthorized)
+				login(password).AssertStatus(t, http.StatusUnauthorized)
+			},
+		},
+
+		// Share links
+		{
+			Name: "staff share a read-only student or document through a signed link", Method: http.MethodPost, Path: "/api/student/%d/share",
+			WantStatus: http.StatusNotImplemented,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				res.AssertErrorContains(t, "sharing.secret")
+
+				cfg := Config()
+				cfg.HttpServer.BaseURL = "https://api.example.com"
+				cfg.Sharing = config.Sharing{Secret: strings.Repeat("k", 32), DefaultTTL: time.Hour, MaxTTL: 24 * time.Hour}
+				srv := NewServerWithConfig(t, cfg)
+				seeded := Seed(t, srv.Storage, ValidStudent(), OtherStudent())
+				ada := seeded[0]
+				// the path of the link in a share response
+				share := func(res *Response) string {
+					t.Helper()
+					var link struct {
+						URL       string    `json:"url"`
+						ExpiresAt time.Time `json:"expires_at"`
+					}
+					res.AssertStatus(t, http.StatusCreated).DecodeJSON(t, &link)
+					path, ok := strings.CutPrefix(link.URL, "https://api.example.com")
+					if !ok || !strings.HasPrefix(path, "/api/shared/") || link.ExpiresAt.IsZero() {
+						t.Fatalf("share = %+v, want a link under https://api.example.com/api/shared/", link)
+					}
+					return path
+				}
+
+				studentLink := share(srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/share?ttl=2h", ada.ID), nil))
+				srv.Do(t, http.MethodGet, studentLink, nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "email", ada.Email)
+				srv.Do(t, http.MethodGet, studentLink+"x", nil).AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodGet, "/api/shared/garbage", nil).AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/share?ttl=48h", ada.ID), nil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "max_ttl")
+				srv.Do(t, http.MethodPost, "/api/student/999/share", nil).AssertStatus(t, http.StatusNotFound)
+
+				var doc struct{ ID int64 }
+				srv.UploadForm(t, fmt.Sprintf("/api/student/%d/documents", ada.ID), map[string]string{"kind": "transcript"}, "file", "transcript.pdf", PDF()).
+					AssertStatus(t, http.StatusCreated).DecodeJSON(t, &doc)
+				srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/documents/%d/share", seeded[1].ID, doc.ID), nil).
+					AssertStatus(t, http.StatusNotFound)
+				docLink := share(srv.Do(t, http.MethodPost, fmt.Sprintf("/api/student/%d/documents/%d/share", ada.ID, doc.ID), nil))
+				srv.Do(t, http.MethodGet, docLink, nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "Content-Type", "application/pdf").
+					AssertHeader(t, "Cache-Control", "private, no-store")
+
+				// links stop working once their student is trashed
+				srv.Do(t, http.MethodDelete, fmt.Sprintf("/api/student/%d", ada.ID), nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, studentLink, nil).AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodGet, docLink, nil).AssertStatus(t, http.StatusNotFound)
+			},
+		},
+
+		// Bot challenges
+		{
+			Name: "anonymous registrations need a challenge token and an empty honeypot", Method: http.MethodGet, Path: "/api/version",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				siteverify := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					r.ParseForm()
+					ok := r.Form.Get("secret") == "site-secret" && r.Form.Get("response") == "human"
+					json.NewEncoder(w).Encode(map[string]any{"success": ok})
+				}))
+				defer siteverify.Close()
+
+				cfg := Config()
+				cfg.Challenge = config.Challenge{
+					Provider: "turnstile", Secret: "site-secret", VerifyURL: siteverify.URL,
+					Header: "X-Captcha-Token", Honeypot: "website", Routes: []string{"POST /api/student"},
+				}
+				srv := NewServerWithConfig(t, cfg)
+				register := func(student types.Student, captcha, website string) *Response {
+					t.Helper()
+					body := map[string]any{"name": student.Name, "email": student.Email, "age": student.Age, "date_of_birth": student.DateOfBirth}
+					if website != "" {
+						body["website"] = website
+					}
+					req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/student", encodeBody(t, body))
+					if captcha != "" {
+						req.Header.Set("X-Captcha-Token", captcha)
+					}
+					return srv.Send(t, req)
+				}
+
+				register(ValidStudent(), "", "").AssertStatus(t, http.StatusBadRequest).AssertErrorContains(t, "X-Captcha-Token")
+				register(ValidStudent(), "robot", "").AssertStatus(t, http.StatusForbidden)
+				register(ValidStudent(), "human", "https://spam.example.com").AssertStatus(t, http.StatusBadRequest).AssertErrorContains(t, "rejected")
+				register(ValidStudent(), "human", "").AssertStatus(t, http.StatusCreated).AssertJSONField(t, "success", true)
+				// other routes aren't challenged
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK)
+
+				// staff Auth let in aren't challenged either
+				cfg.Auth = config.Auth{Enabled: true, BootstrapKey: strings.Repeat("b", 32)}
+				staff := NewServerWithConfig(t, cfg)
+				req, _ := http.NewRequest(http.MethodPost, staff.URL+"/api/student", encodeBody(t, OtherStudent()))
+				req.Header.Set("X-API-Key", strings.Repeat("b", 32))
+				staff.Send(t, req).AssertStatus(t, http.StatusCreated)
+			},
+		},
+
+		// Public ids
+		{
+			Name: "students have a ULID public id that student routes take in place of the id", Method: http.MethodGet, Path: "/api/student/%d",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				var student types.Student
+				res.DecodeJSON(t, &student)
+				if !ulid.Valid(student.PublicID) {
+					t.Fatalf("public_id = %q, want a ULID", student.PublicID)
+				}
+				byPublicID := "/api/student/" + student.PublicID
+
+				srv.Do(t, http.MethodGet, byPublicID, nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "email", student.Email)
+				srv.Do(t, http.MethodGet, strings.ToLower(byPublicID), nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodGet, "/api/student/"+ulid.New(), nil).AssertStatus(t, http.StatusNotFound)
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/student/%d?fields=public_id", student.ID), nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "public_id", student.PublicID)
+
+				// input can't set it and updates keep it
+				update := ValidStudent()
+				update.Name, update.PublicID = "Ada L.", ulid.New()
+				var updated struct{ Student types.Student }
+				srv.Do(t, http.MethodPut, byPublicID, update).AssertStatus(t, http.StatusOK).DecodeJSON(t, &updated)
+				if updated.Student.ID != student.ID || updated.Student.PublicID != student.PublicID {
+					t.Fatalf("updated = %d/%s, want %d/%s", updated.Student.ID, updated.Student.PublicID, student.ID, student.PublicID)
+				}
+
+				// trashed students are still found, to be restored
+				srv.Do(t, http.MethodDelete, byPublicID, nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPost, byPublicID+"/restore", nil).AssertStatus(t, http.StatusOK)
+
+				var other types.Student
+				srv.Do(t, http.MethodPost, "/api/student", OtherStudent()).AssertStatus(t, http.StatusCreated)
+				srv.Do(t, http.MethodGet, fmt.Sprintf("/api/student/%d", student.ID+1), nil).DecodeJSON(t, &other)
+				if other.PublicID == "" || other.PublicID == student.PublicID {
+					t.Fatalf("public ids %q and %q, want two different ones", student.PublicID, other.PublicID)
+				}
+			},
+		},
+
+		// Enumeration resistance
+		{
+			Name: "hashids replace numeric ids and every 404 looks alike", Method: http.MethodGet, Path: "/api/student/%d",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Enumeration = config.Enumeration{UniformNotFound: true, NotFoundDelay: 50 * time.Millisecond, HashIDSalt: "pepper-and-salt-0123", HashIDMinLength: 8}
+				srv := NewServerWithConfig(t, cfg)
+				Seed(t, srv.Storage, ValidStudent())
+
+				var list []struct {
+					ID    string `json:"id"`
+					Links struct {
+						Self struct{ Href string }
+					} `json:"_links"`
+				}
+				srv.Do(t, http.MethodGet, "/api/students", nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &list)
+				if len(list) != 1 || len(list[0].ID) < 8 || list[0].Links.Self.Href != "/api/student/"+list[0].ID {
+					t.Fatalf("students = %+v, want one with a hashid and a link using it", list)
+				}
+				hashed := list[0].ID
+
+				srv.Do(t, http.MethodGet, "/api/student/"+hashed, nil).
+					AssertStatus(t, http.StatusOK).
+					AssertJSONField(t, "id", hashed)
+				srv.Do(t, http.MethodGet, "/api/students?ids="+hashed, nil).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPost, "/api/students/batch-get", map[string]any{"ids": []string{hashed}}).AssertStatus(t, http.StatusOK)
+				srv.Do(t, http.MethodPost, "/api/students/batch-get", map[string]any{"ids": []int{1}}).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertErrorContains(t, "must be a hashid")
+
+				// numeric ids, missing records (a well-formed hashid too) and unknown routes get the same 404, slowly
+				missing := hashid.New(cfg.Enumeration.HashIDSalt, cfg.Enumeration.HashIDMinLength).Encode(999)
+				var bodies []string
+				for _, path := range []string{"/api/student/1", "/api/student/" + missing, "/api/student/" + hashed + "/documents/" + hashed, "/api/nope"} {
+					start := time.Now()
+					res := srv.Do(t, http.MethodGet, path, nil).AssertStatus(t, http.StatusNotFound)
+					if took := time.Since(start); took < 50*time.Millisecond {
+						t.Fatalf("GET %s took %s, want at least 50ms", path, took)
+					}
+					bodies = append(bodies, string(res.Body))
+				}
+				for _, body := range bodies[1:] {
+					if body != bodies[0] {
+						t.Fatalf("404 bodies differ: %q", bodies)
+					}
+				}
+				if strings.Contains(bodies[1], "999") {
+					t.Fatalf("404 body %s names the decoded id", bodies[1])
+				}
+			},
+		},
+
+		// Impersonation
+		{
+			Name: "admins impersonate staff and students for a limited time", Method: http.MethodPost, Path: "/api/admin/impersonations",
+			Body:       map[string]any{"subject": "user:alice", "reason": "ticket 42"},
+			WantStatus: http.StatusNotImplemented,
+			Check: func(t testing.TB, _ *Server, res *Response) {
+				res.AssertErrorContains(t, "auth.token_secret")
+
+				cfg := Config()
+				cfg.Auth = config.Auth{
+					Enabled: true, BootstrapKey: strings.Repeat("b", 32),
+					TokenSecret: strings.Repeat("s", 32), AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour,
+					Impersonation: config.Impersonation{TTL: time.Minute, MaxTTL: time.Hour},
+				}
+				cfg.Portal = config.Portal{Enabled: true, SessionTTL: time.Hour, MinPasswordLength: 10, MaxFailedLogins: 3, Lockout: time.Minute}
+				srv := NewServerWithConfig(t, cfg)
+				ada := Seed(t, srv.Storage, ValidStudent())[0]
+				send := func(method, path string, headers map[string]string, body any) *Response {
+					t.Helper()
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					for k, v := range headers {
+						req.Header.Set(k, v)
+					}
+					return srv.Send(t, req)
+				}
+				admin := map[string]string{"X-API-Key": cfg.Auth.BootstrapKey}
+				impersonate := func(body map[string]any, want int) types.Impersonation {
+					t.Helper()
+					var imp types.Impersonation
+					res := send(http.MethodPost, "/api/admin/impersonations", admin, body).AssertStatus(t, want)
+					if want == http.StatusCreated {
+						res.DecodeJSON(t, &imp)
+					}
+					return imp
+				}
+				bearer := func(imp types.Impersonation) map[string]string {
+					return map[string]string{"Authorization": "Bearer " + imp.AccessToken}
+				}
+
+				var key types.APIKey
+				send(http.MethodPost, "/api/admin/api-keys", admin, map[string]any{"name": "teacher", "scopes": []string{"read", "write"}}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &key)
+				subject := fmt.Sprintf("key:%d", key.ID)
+
+				// 🚧 scoped, never admin, and no longer than max_ttl
+				impersonate(map[string]any{"subject": subject}, http.StatusBadRequest)
+				impersonate(map[string]any{"subject": subject, "reason": "ticket 42", "scopes": []string{"admin"}}, http.StatusBadRequest)
+				impersonate(map[string]any{"subject": subject, "reason": "ticket 42", "ttl": "2h"}, http.StatusBadRequest)
+				impersonate(map[string]any{"subject": "key:999", "reason": "ticket 42"}, http.StatusNotFound)
+				impersonate(map[string]any{"subject": "teacher", "reason": "ticket 42"}, http.StatusBadRequest)
+
+				// 🎭 acting as the key: read only, and the audit trail names the admin
+				staff := impersonate(map[string]any{"subject": subject, "reason": "ticket 42"}, http.StatusCreated)
+				if staff.Subject != subject || staff.Impersonator != "admin" || time.Until(staff.ExpiresAt) > time.Minute {
+					t.Fatalf("impersonation = %+v, want %s for a minute", staff, subject)
+				}
+				send(http.MethodGet, "/api/students", bearer(staff), nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodPost, "/api/student", bearer(staff), OtherStudent()).AssertStatus(t, http.StatusForbidden)
+				send(http.MethodGet, "/api/admin/stats", bearer(staff), nil).AssertStatus(t, http.StatusForbidden)
+				send(http.MethodGet, fmt.Sprintf("/api/student/%d/data-export", ada.ID), bearer(staff), nil).AssertStatus(t, http.StatusOK)
+
+				// 🎓 acting as a student works on the portal only
+				student := impersonate(map[string]any{"subject": fmt.Sprintf("student:%d", ada.ID), "reason": "can't see grades"}, http.StatusCreated)
+				var me types.Student
+				send(http.MethodGet, "/api/me", bearer(student), nil).AssertStatus(t, http.StatusOK).DecodeJSON(t, &me)
+				if me.ID != ada.ID {
+					t.Fatalf("me = %+v, want student %d", me, ada.ID)
+				}
+				send(http.MethodGet, "/api/students", bearer(student), nil).AssertStatus(t, http.StatusUnauthorized)
+				send(http.MethodGet, "/api/me", bearer(staff), nil).AssertStatus(t, http.StatusUnauthorized)
+				send(http.MethodPost, "/api/me/logout", bearer(student), nil).AssertStatus(t, http.StatusForbidden)
+
+				privacy, _ := storage.As[storage.PrivacyStore](srv.Storage)
+				entries, err := privacy.GetAuditEntries(ada.ID)
+				if err != nil {
+					t.Fatalf("audit entries: %v", err)
+				}
+				if len(entries) != 2 ||
+					entries[0].Action != types.AuditDataExported || entries[0].Actor != subject || entries[0].Impersonator != "admin" ||
+					entries[1].Action != types.AuditImpersonated || entries[1].Actor != "admin" {
+					t.Fatalf("audit entries = %+v, want the impersonated export and the impersonation", entries)
+				}
+
+				// ⏹️ ended early, the tokens stop working
+				send(http.MethodDelete, "/api/admin/impersonations/"+student.ID, admin, nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/me", bearer(student), nil).AssertStatus(t, http.StatusUnauthorized).AssertErrorContains(t, "revoked")
+				send(http.MethodPost, "/api/auth/logout", bearer(staff), nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/students", bearer(staff), nil).AssertStatus(t, http.StatusUnauthorized)
+			},
+		},
+		{
+			Name: "impersonation tokens reach only their own tenant", Method: http.MethodGet, Path: "/api/students",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Tenancy.Enabled = true
+				cfg.Auth = config.Auth{
+					Enabled: true, BootstrapKey: strings.Repeat("b", 32),
+					TokenSecret: strings.Repeat("s", 32), AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour,
+					Impersonation: config.Impersonation{TTL: time.Minute, MaxTTL: time.Hour},
+				}
+				cfg.Portal = config.Portal{Enabled: true, SessionTTL: time.Hour, MinPasswordLength: 10, MaxFailedLogins: 3, Lockout: time.Minute}
+				srv := NewServerWithConfig(t, cfg)
+				send := func(method, path, tenant string, headers map[string]string, body any) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					if tenant != "" {
+						req.Header.Set("X-Tenant", tenant)
+					}
+					for k, v := range headers {
+						req.Header.Set(k, v)
+					}
+					return srv.Send(t, req)
+				}
+				admin := map[string]string{"X-API-Key": cfg.Auth.BootstrapKey}
+				impersonate := func(query string, body map[string]any, want int) map[string]string {
+					t.Helper()
+					body["reason"] = "ticket 42"
+					res := send(http.MethodPost, "/api/admin/impersonations"+query, "", admin, body).AssertStatus(t, want)
+					if want != http.StatusCreated {
+						return nil
+					}
+					var imp types.Impersonation
+					res.DecodeJSON(t, &imp)
+					return map[string]string{"Authorization": "Bearer " + imp.AccessToken}
+				}
+
+				var acme types.Tenant
+				send(http.MethodPost, "/api/admin/tenants", "", admin, map[string]any{"slug": "acme", "name": "Acme"}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &acme)
+				var acmeKey types.APIKey
+				send(http.MethodPost, "/api/admin/api-keys", "", admin, map[string]any{"name": "acme", "scopes": []string{"read"}, "tenant_id": acme.ID}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &acmeKey)
+				ada := Seed(t, As[storage.TenantScoper](t, srv.Storage).ForTenant(acme.ID), ValidStudent())[0]
+				keySubject := map[string]any{"subject": fmt.Sprintf("key:%d", acmeKey.ID)}
+
+				// 🏫 a key's impersonation takes the key's tenant; users' and
+				// students' the ?tenant= one
+				impersonate("?tenant=default", keySubject, http.StatusBadRequest)
+				impersonate("?tenant=nowhere", map[string]any{"subject": "user:alice"}, http.StatusNotFound)
+				impersonate("", map[string]any{"subject": fmt.Sprintf("student:%d", ada.ID)}, http.StatusNotFound)
+				for name, headers := range map[string]map[string]string{
+					"key":  impersonate("", keySubject, http.StatusCreated),
+					"user": impersonate("?tenant=acme", map[string]any{"subject": "user:alice"}, http.StatusCreated),
+				} {
+					if res := send(http.MethodGet, "/api/students", "acme", headers, nil); res.StatusCode != http.StatusOK {
+						t.Fatalf("%s impersonation in its tenant = %d", name, res.StatusCode)
+					}
+					send(http.MethodGet, "/api/students", "default", headers, nil).
+						AssertStatus(t, http.StatusForbidden).
+						AssertErrorContains(t, "not valid for tenant")
+				}
+
+				student := impersonate("?tenant=acme", map[string]any{"subject": fmt.Sprintf("student:%d", ada.ID)}, http.StatusCreated)
+				send(http.MethodGet, "/api/me", "acme", student, nil).AssertStatus(t, http.StatusOK)
+				send(http.MethodGet, "/api/me", "default", student, nil).
+					AssertStatus(t, http.StatusUnauthorized).
+					AssertErrorContains(t, "another tenant")
+			},
+		},
+
+		// Errors
+		{
+			Name: "errors are problem details listing the invalid fields", Method: http.MethodPost, Path: "/api/student",
+			Body: map[string]any{"name": "No Email"}, WantStatus: http.StatusBadRequest,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				type problemBody struct {
+					Type   string                `json:"type"`
+					Title  string                `json:"title"`
+					Status int                   `json:"status"`
+					Detail string                `json:"detail"`
+					Errors []response.FieldError `json:"errors"`
+				}
+				var problem problemBody
+				res.AssertHeader(t, "Content-Type", response.ProblemJSON).DecodeJSON(t, &problem)
+				if problem.Type != "about:blank" || problem.Title != "Bad Request" || problem.Status != http.StatusBadRequest {
+					t.Fatalf("problem = %+v, want about:blank, Bad Request, 400", problem)
+				}
+				if len(problem.Errors) != 2 || problem.Errors[0].Field != "Email" || problem.Errors[1].Field != "DateOfBirth" {
+					t.Fatalf("errors = %+v, want Email and DateOfBirth", problem.Errors)
+				}
+
+				// 🔎 query parameters and custom values are listed the same way
+				problem = problemBody{}
+				srv.Do(t, http.MethodPost, "/api/admin/purge?older_than=soon&async=maybe", nil).
+					AssertStatus(t, http.StatusBadRequest).
+					AssertHeader(t, "Content-Type", response.ProblemJSON).
+					DecodeJSON(t, &problem)
+				if problem.Status != http.StatusBadRequest || len(problem.Errors) != 2 || problem.Errors[0].Field != "older_than" || problem.Errors[1].Field != "async" {
+					t.Fatalf("problem = %+v, want older_than and async errors", problem)
+				}
+				student := ValidStudent()
+				student.Custom = map[string]any{"house": "red"}
+				problem = problemBody{}
+				srv.Do(t, http.MethodPost, "/api/student", student).
+					AssertStatus(t, http.StatusBadRequest).
+					DecodeJSON(t, &problem)
+				if len(problem.Errors) != 1 || problem.Errors[0].Field != "custom.house" || problem.Errors[0].Message != "custom field house is not defined" {
+					t.Fatalf("errors = %+v, want custom.house", problem.Errors)
+				}
+
+				// 🚫 so are errors without fields
+				problem = problemBody{}
+				srv.Do(t, http.MethodGet, "/api/student/999", nil).
+					AssertHeader(t, "Content-Type", response.ProblemJSON).
+					DecodeJSON(t, &problem)
+				if problem.Detail == "" || problem.Errors != nil {
+					t.Fatalf("problem = %+v, want a detail and no field errors", problem)
+				}
+			},
+		},
+		{
+			Name: "bodies over http_server.max_body_bytes are refused", Method: http.MethodPost, Path: "/api/student",
+			Body: `{"name":"` + strings.Repeat("a", 1<<20) + `"}`, WantStatus: http.StatusRequestEntityTooLarge,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertErrorContains(t, "larger than 1048576 bytes")
+
+				// a chunked body can't declare its length; it fails to read past the limit
+				body := io.MultiReader(strings.NewReader(`{"name":"`), strings.NewReader(strings.Repeat("a", 1<<20)), strings.NewReader(`"}`))
+				req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/student", body)
+				req.Header.Set("Content-Type", "application/json")
+				srv.Send(t, req).AssertStatus(t, http.StatusBadRequest).AssertErrorContains(t, "request body too large")
+			},
+		},
+		{
+			Name: "storage failures answer 500 and bad requests never reach storage", Method: http.MethodGet, Path: "/api/students/count",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				store := mocks.New()
+				count := student.Count(store.Storage(), nil)
+				get := func(path string) int {
+					rec := httptest.NewRecorder()
+					count(rec, httptest.NewRequest(http.MethodGet, path, nil))
+					return rec.Code
+				}
+
+				store.FailNext("CountStudents", errors.New("disk I/O error"), nil)
+				if got := get("/api/students/count"); got != http.StatusInternalServerError {
+					t.Fatalf("count with a failing store = %d, want 500", got)
+				}
+				if got := get("/api/students/count?verified=true"); got != http.StatusOK {
+					t.Fatalf("count after the failure = %d, want 200", got)
+				}
+				store.AssertCalled(t, "CountStudents", 2)
+				verified := true
+				store.AssertCalledWith(t, "CountStudents", storage.StudentQuery{Verified: &verified})
+
+				store.Reset()
+				if got := get("/api/students/count?verified=maybe"); got != http.StatusBadRequest {
+					t.Fatalf("count?verified=maybe = %d, want 400", got)
+				}
+				store.AssertNotCalled(t, "CountStudents")
+			},
+		},
+
+		// Counting
+		{
+			Name: "lists count their students in the backend", Method: http.MethodGet, Path: "/api/students/count",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, _ *Response) {
+				srv.Do(t, http.MethodPost, "/api/admin/custom-fields", map[string]any{"key": "house", "type": "string"}).
+					AssertStatus(t, http.StatusCreated)
+				students := Students(5)
+				for i := range students {
+					students[i].Custom = map[string]any{"house": []string{"red", "blue"}[i%2]}
+				}
+				Seed(t, srv.Storage, students...)
+
+				var count struct {
+					Count int64 `json:"count"`
+				}
+				srv.Do(t, http.MethodGet, "/api/students/count?custom.house=red", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &count)
+				if count.Count != 3 {
+					t.Fatalf("count = %d, want 3 red students", count.Count)
+				}
+
+				// the scenario's own student makes six
+				srv.Do(t, http.MethodGet, "/api/students?limit=2", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "X-Total-Count", "6")
+				srv.Do(t, http.MethodGet, "/api/students?custom.house=blue", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "X-Total-Count", "2")
+				res := srv.Do(t, http.MethodHead, "/api/students?verified=false", nil).
+					AssertStatus(t, http.StatusOK).
+					AssertHeader(t, "X-Total-Count", "6")
+				if len(res.Body) != 0 {
+					t.Fatalf("HEAD answered a body: %s", res.Body)
+				}
+				srv.Do(t, http.MethodGet, "/api/students/count?verified=true&custom.house=red", nil).
+					AssertStatus(t, http.StatusBadRequest)
+			},
+		},
+
+		// Email domain migration
+		{
+			Name: "email domains are migrated with conflicts left alone", Method: http.MethodPost, Path: "/api/admin/email-domains/migrate?dry_run=true",
+			Body:       map[string]any{"from": "old.edu", "to": "new.edu"},
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, res *Response) {
+				res.AssertJSONField(t, "matched", float64(0))
+				students := Seed(t, srv.Storage,
+					types.Student{Name: "Ann Old", Email: "ann@old.edu", DateOfBirth: BornYearsAgo(15)},
+					types.Student{Name: "Cat Old", Email: "cat@Old.edu", DateOfBirth: BornYearsAgo(15)},
+					types.Student{Name: "Cat New", Email: "cat@new.edu", DateOfBirth: BornYearsAgo(15)},
+				)
+
+				var migration types.EmailDomainMigration
+				srv.Do(t, http.MethodPost, "/api/admin/email-domains/migrate", map[string]any{"from": "OLD.edu", "to": "new.edu"}).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &migration)
+				if migration.Moved != 1 || migration.Conflicts != 1 || migration.Changes[1].ConflictID != students[2].ID {
+					t.Fatalf("migration = %+v, want ann moved and cat in conflict with %d", migration, students[2].ID)
+				}
+				moved, _ := srv.Storage.GetStudentById(students[0].ID)
+				kept, _ := srv.Storage.GetStudentById(students[1].ID)
+				if moved.Email != "ann@new.edu" || kept.Email != "cat@Old.edu" {
+					t.Fatalf("emails = %s and %s, want ann@new.edu and cat@Old.edu", moved.Email, kept.Email)
+				}
+				entries, _ := As[storage.PrivacyStore](t, srv.Storage).GetAuditEntries(students[0].ID)
+				if len(entries) != 1 || entries[0].Action != types.AuditEmailDomainMigrated {
+					t.Fatalf("audit entries = %+v, want one %s", entries, types.AuditEmailDomainMigrated)
+				}
+
+				srv.Do(t, http.MethodPost, "/api/admin/email-domains/migrate", map[string]any{"from": "new.edu", "to": "new.edu"}).
+					AssertStatus(t, http.StatusBadRequest)
+				srv.Do(t, http.MethodPost, "/api/admin/email-domains/migrate", map[string]any{"from": "new.edu", "to": "not a domain"}).
+					AssertStatus(t, http.StatusBadRequest)
+			},
+		},
+
+		// Reports
+		{
+			Name: "reports group students by age and birthday", Method: http.MethodGet, Path: "/api/reports/birthdays?month=2",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, _ *Response) {
+				Seed(t, srv.Storage,
+					types.Student{Name: "Val One", Email: "val.one@example.com", DateOfBirth: "2010-02-14"},
+					types.Student{Name: "Val Two", Email: "val.two@example.com", DateOfBirth: "2011-02-14"},
+					types.Student{Name: "Leap Day", Email: "leap.day@example.com", DateOfBirth: "2012-02-29"},
+					types.Student{Name: "Kid", Email: "kid@example.com", DateOfBirth: BornYearsAgo(8)},
+				)
+
+				var birthdays types.BirthdayReport
+				srv.Do(t, http.MethodGet, "/api/reports/birthdays?month=2", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &birthdays)
+				if birthdays.Total != 3 || len(birthdays.Days) != 29 {
+					t.Fatalf("birthdays = %+v, want 3 over 29 days", birthdays)
+				}
+				if birthdays.Days[13].Count != 2 || birthdays.Days[28].Count != 1 {
+					t.Fatalf("days = %+v, want 2 on the 14th and 1 on the 29th", birthdays.Days)
+				}
+
+				// the scenario's own student is 28
+				var ages types.AgeDistribution
+				srv.Do(t, http.MethodGet, "/api/reports/age-distribution", nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &ages)
+				if ages.Total != 5 || ages.Buckets[0].Count != 1 {
+					t.Fatalf("ages = %+v, want 5 students, 1 aged 1-12", ages)
+				}
+
+				srv.Do(t, http.MethodGet, "/api/reports/birthdays?month=13", nil).
+					AssertStatus(t, http.StatusBadRequest)
+			},
+		},
+
+		// Go client
+		{
+			Name: "the Go client drives the API", Method: http.MethodGet, Path: "/api/version",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, srv *Server, _ *Response) {
+				ctx := context.Background()
+				c, err := client.New(srv.URL)
+				if err != nil {
+					t.Fatalf("client.New: %v", err)
+				}
+
+				created, err := c.CreateStudent(ctx, types.Student{Name: "Grace Hopper", Email: "grace@example.com", DateOfBirth: BornYearsAgo(17)})
+				if err != nil || created.ID == 0 || created.PublicID == "" || created.Age != 17 {
+					t.Fatalf("CreateStudent = %+v, %v; want an id, public_id and age 17", created, err)
+				}
+				created.Phone = "+14155550123"
+				if updated, err := c.UpdateStudent(ctx, created.ID, created.Student); err != nil || updated.Phone != created.Phone {
+					t.Fatalf("UpdateStudent = %+v, %v; want the new phone", updated, err)
+				}
+				if _, isNew, err := c.UpsertStudent(ctx, types.Student{Name: "Alan Turing", Email: "alan@example.com", DateOfBirth: BornYearsAgo(16)}); err != nil || !isNew {
+					t.Fatalf("UpsertStudent = %v, %v; want created", isNew, err)
+				}
+
+				// the seeded student, grace and alan, two a page
+				var names []string
+				for s, err := range c.Students(ctx, client.StudentFilter{}, 2) {
+					if err != nil {
+						t.Fatalf("Students: %v", err)
+					}
+					names = append(names, s.Name)
+				}
+				if n, err := c.CountStudents(ctx, client.StudentFilter{}); err != nil || n != 3 || len(names) != 3 {
+					t.Fatalf("CountStudents = %d, %v and Students = %v; want 3 of each", n, err, names)
+				}
+
+				if err := c.DeleteStudent(ctx, created.ID); err != nil {
+					t.Fatalf("DeleteStudent: %v", err)
+				}
+				if trash, err := c.Trash(ctx); err != nil || len(trash) != 1 || trash[0].ID != created.ID {
+					t.Fatalf("Trash = %+v, %v; want grace", trash, err)
+				}
+				if restored, err := c.RestoreStudent(ctx, created.ID); err != nil || restored.ID != created.ID {
+					t.Fatalf("RestoreStudent = %+v, %v", restored, err)
+				}
+
+				// invalid fields come back listed
+				var apiErr *client.Error
+				_, err = c.CreateStudent(ctx, types.Student{Name: "No Email", DateOfBirth: BornYearsAgo(12)})
+				if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest || len(apiErr.Errors) == 0 || apiErr.Errors[0].Field != "Email" {
+					t.Fatalf("CreateStudent without email = %v, want a 400 listing Email", err)
+				}
+
+				// a GET that meets a 503 is repeated; a POST isn't
+				var calls sync.Map
+				flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if n, _ := calls.LoadOrStore(r.Method, 0); n.(int) == 0 {
+						calls.Store(r.Method, 1)
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					srv.Config.Handler.ServeHTTP(w, r)
+				}))
+				defer flaky.Close()
+				c, _ = client.New(flaky.URL, client.WithRetries(2, time.Millisecond))
+				if _, err := c.Version(ctx); err != nil {
+					t.Fatalf("Version through one 503 = %v, want it retried", err)
+				}
+				if _, err := c.CreateStudent(ctx, ValidStudent()); client.StatusOf(err) != http.StatusServiceUnavailable {
+					t.Fatalf("CreateStudent through one 503 = %v, want the 503", err)
+				}
+			},
+		},
+
+		// Router level
+		{
+			Name: "method not allowed", Method: http.MethodPatch, Path: "/api/student/%d",
+			WantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			Name: "unknown route", Method: http.MethodGet, Path: "/api/nope",
+			WantStatus: http.StatusNotFound,
+		},
+		{
+			Name: "routes declare their own auth and quota class", Method: http.MethodGet, Path: "/api/version",
+			WantStatus: http.StatusOK,
+			Check: func(t testing.TB, _ *Server, _ *Response) {
+				cfg := Config()
+				cfg.Auth = config.Auth{Enabled: true, BootstrapKey: strings.Repeat("b", 32)}
+				srv := NewServerWithConfig(t, cfg)
+				send := func(method, path, key string, body any) *Response {
+					req, _ := http.NewRequest(method, srv.URL+path, encodeBody(t, body))
+					if key != "" {
+						req.Header.Set("X-API-Key", key)
+					}
+					return srv.Send(t, req)
+				}
+
+				// public: the handler itself answers, without credentials
+				send(http.MethodPost, "/api/auth/token", "", map[string]any{}).
+					AssertStatus(t, http.StatusNotImplemented).
+					AssertErrorContains(t, "token_secret")
+				send(http.MethodGet, "/api/students", "", nil).AssertStatus(t, http.StatusUnauthorized)
+
+				var key types.APIKey
+				send(http.MethodPost, "/api/admin/api-keys", cfg.Auth.BootstrapKey, map[string]any{"name": "reports", "scopes": []string{"read"}}).
+					AssertStatus(t, http.StatusCreated).
+					DecodeJSON(t, &key)
+				// a POST, but declared a read
+				send(http.MethodPost, "/api/students/batch-get", key.Key, map[string]any{"ids": []int64{1}}).AssertStatus(t, http.StatusOK)
+
+				var routes []types.Route
+				send(http.MethodGet, "/api/admin/routes", key.Key, nil).AssertStatus(t, http.StatusForbidden)
+				send(http.MethodGet, "/api/admin/routes", cfg.Auth.BootstrapKey, nil).
+					AssertStatus(t, http.StatusOK).
+					DecodeJSON(t, &routes)
+				found := map[string]types.Route{}
+				for _, route := range routes {
+					found[route.Pattern] = route
+				}
+				if !found["POST /api/auth/token"].Public || found["GET /api/students"].Public {
+					t.Fatalf("routes = %+v, want only the auth routes public", routes)
+				}
+				if batch := found["POST /api/students/batch-get"]; batch.Scope != types.ScopeRead || batch.Timeout != "30s" {
+					t.Fatalf("batch-get route = %+v, want read scope and a 30s timeout", batch)
+				}
+			},
+		},
+	}
+}
+
+// -------------------------------------------------------------
+// RunScenarios() → Each scenario gets its own server seeded with ValidStudent(),
+// once per storage backend
+// -------------------------------------------------------------
+func RunScenarios(t *testing.T, scenarios []Scenario) {
+	t.Helper()
+
+	for _, backend := range Backends() {
+		t.Run(backend.Name, func(t *testing.T) {
+			for _, sc := range scenarios {
+				if len(sc.Backends) > 0 && !slices.Contains(sc.Backends, backend.Name) {
+					continue
+				}
+				t.Run(sc.Name, func(t *testing.T) {
+					cfg := Config()
+					cfg.DBType = backend.Name
+					srv := NewServerWithConfig(t, cfg)
+					seeded := Seed(t, srv.Storage, ValidStudent())
+
+					path := sc.Path
+					if strings.Contains(path, "%d") {
+						path = fmt.Sprintf(path, seeded[0].ID)
+					}
+
+					res := srv.Do(t, sc.Method, path, sc.Body)
+					res.AssertStatus(t, sc.WantStatus)
+
+					if sc.Check != nil {
+						sc.Check(t, srv, res)
+					}
+				})
+			}
+		})
+	}
+}
+
+// staleCount counts fewer students than the backend then lists, like a
+// count taken just before rows were added
+type staleCount struct{ storage.Storage }
+
+func (staleCount) CountStudents(storage.StudentQuery) (int64, error) { return 1, nil }
+
+func errorContains(substr string) func(testing.TB, *Server, *Response) {
+	return func(t testing.TB, _ *Server, res *Response) {
+		res.AssertErrorContains(t, substr)
+	}
+}
@@ -0,0 +1,381 @@
+// Package testkit spins up the full API router against a storage backend
+// (in-memory unless the config picks another of Backends) and offers small
+// helpers for driving it from tests.
+//
+//	srv := testkit.NewServer(t)
+//	srv.Do(t, http.MethodPost, "/api/student", testkit.ValidStudent()).
+//		AssertStatus(t, http.StatusCreated)
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/challenge"
+	"github.com/manish-npx/go-student-api/internal/chaos"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/export"
+	"github.com/manish-npx/go-student-api/internal/featureflag"
+	"github.com/manish-npx/go-student-api/internal/http/routes"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/maintenance"
+	"github.com/manish-npx/go-student-api/internal/metrics"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/oidc"
+	"github.com/manish-npx/go-student-api/internal/outbox"
+	"github.com/manish-npx/go-student-api/internal/paging"
+	"github.com/manish-npx/go-student-api/internal/portal"
+	"github.com/manish-npx/go-student-api/internal/quota"
+	"github.com/manish-npx/go-student-api/internal/roster"
+	"github.com/manish-npx/go-student-api/internal/share"
+	"github.com/manish-npx/go-student-api/internal/snapshot"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/storage/retry"
+	"github.com/manish-npx/go-student-api/internal/token"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+	"github.com/manish-npx/go-student-api/internal/validate"
+	"github.com/manish-npx/go-student-api/internal/verification"
+	"github.com/manish-npx/go-student-api/internal/webhook"
+)
+
+// Server wraps an httptest.Server running the real router.
+// Storage, Blobs and Mail are exposed so tests can seed or inspect data directly.
+type Server struct {
+	*httptest.Server
+	// Storage is the backend itself, under the decorators the router sees
+	Storage storage.Storage
+	// Backend names the storage backend (config db_type)
+	Backend string
+	Blobs   *blob.Memory
+	// Mail records every email the API sends
+	Mail *notify.Memory
+	// Admin is the admin listener; nil unless http_server.admin_address is set
+	Admin *httptest.Server
+	// Paging holds the list limits; Apply stands in for a config reload
+	Paging *paging.Limits
+}
+
+// 🧩 NewServer starts the router on a random port and closes it on cleanup
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	return NewServerWithConfig(t, Config())
+}
+
+// 🧩 NewServerWithConfig is NewServer with a caller-tweaked config; its
+// DBType names the backend, one of Backends
+func NewServerWithConfig(t testing.TB, cfg *config.Config) *Server {
+	t.Helper()
+
+	store := openBackend(t, cfg.DBType)
+	// the policy is process-wide, as in main; each server brings its own
+	validate.Apply(cfg.Validation)
+	// handlers see the store through the chaos, metrics, retry and auth cache decorators, as in main
+	storeMetrics := metrics.NewStorage(cfg.Metrics, cfg.DBType)
+	backend := retry.Wrap(cfg.StorageRetry, storeMetrics.Wrap(chaos.New(cfg.Chaos, cfg.Env).Wrap(store)))
+	backend = storage.CacheAuth(backend, cfg.Auth.Cache.Size, cfg.Auth.Cache.TTL)
+	blobs := blob.NewMemory()
+	mail := &notify.Memory{}
+	notifier := notify.NewWithSender(mail, cfg.Notify)
+	queue := jobs.New(backend, cfg.Jobs)
+	jobs.RegisterDefaults(queue, backend, blobs, notifier)
+	hooks := webhook.New(backend, queue, cfg.Webhooks)
+	exporter := export.New(backend, queue, blobs)
+	snapshots := snapshot.New(backend, queue, blobs)
+	tokens := token.New(cfg.Auth, backend)
+	idp := oidc.New(cfg.Auth.OIDC)
+	quotas := quota.New(cfg.Quotas, backend)
+	syncer, err := roster.New(cfg, backend, notifier)
+	if err != nil {
+		t.Fatalf("roster sync: %v", err)
+	}
+	relay := outbox.New(backend, hooks, cfg.Outbox)
+	limits := paging.New(cfg.Pagination)
+	queue.Start(context.Background())
+	relay.Start(context.Background())
+
+	deps := routes.Deps{Storage: backend, Blobs: blobs, Notifier: notifier, Jobs: queue, Webhooks: hooks, Exports: exporter, Snapshots: snapshots, Tokens: tokens, OIDC: idp, Challenge: challenge.New(cfg.Challenge), Quotas: quotas, Portal: portal.New(cfg.Portal), Sharer: share.New(cfg.Sharing, cfg.HttpServer.BaseURL), Verifier: verification.New(cfg.Verification, cfg.HttpServer.BaseURL, notifier), Sync: syncer, Metrics: storeMetrics, Maintenance: maintenance.New(cfg.Maintenance), Flags: featureflag.New(cfg.FeatureFlags), Paging: limits}
+	// both listeners on random ports when the config splits them
+	var srv, admin *httptest.Server
+	if cfg.HttpServer.AdminAddr != "" {
+		public, adminHandler := routes.Split(cfg, deps)
+		srv, admin = httptest.NewServer(public), httptest.NewServer(adminHandler)
+	} else {
+		srv = httptest.NewServer(routes.New(cfg, deps))
+	}
+	t.Cleanup(func() {
+		srv.Close()
+		if admin != nil {
+			admin.Close()
+		}
+		relay.Stop(context.Background())
+		queue.Stop(context.Background())
+		notifier.Close(context.Background())
+	})
+
+	return &Server{Server: srv, Storage: store, Backend: cfg.DBType, Blobs: blobs, Mail: mail, Admin: admin, Paging: limits}
+}
+
+// 🧩 Config is the minimal valid config the test router runs with.
+// Tweak the returned value (e.g. enable tenancy) for NewServerWithConfig.
+func Config() *config.Config {
+	return &config.Config{
+		Env:        "test",
+		DBType:     "memory",
+		HttpServer: config.HttpServer{Addr: "127.0.0.1:0", MaxBodyBytes: 1 << 20},
+		Logger:     config.Logger{Level: "info"},
+		Metrics:    config.Metrics{Enabled: true},
+		Tenancy:    config.Tenancy{Header: "X-Tenant"},
+		Compression: config.Compression{
+			Enabled: true, MinSize: 1024, Level: -1,
+			ContentTypes: []string{"application/json", "application/problem+json", "application/xml", "application/x-ndjson", "text/csv"},
+		},
+		JSONNaming:  config.JSONNaming{Default: "snake_case", Header: "X-JSON-Naming"},
+		Pagination:  config.Pagination{DefaultLimit: 20, MaxLimit: 100},
+		Validation:  config.Validation{MinAge: 1, MaxAge: 100},
+		Maintenance: config.Maintenance{RetryAfter: time.Minute},
+		Blob:        config.Blob{Driver: "memory", PresignExpiry: 15 * time.Minute},
+		Photos:      config.Photos{MaxBytes: 1 << 20},
+		Documents:   config.Documents{MaxBytes: 2 << 20},
+		Notify:      config.Notify{Provider: "memory", From: "School <noreply@example.com>", Workers: 1, QueueSize: 10, MaxAttempts: 1},
+		Webhooks:    config.Webhooks{Timeout: 5 * time.Second},
+		Outbox:      config.Outbox{PollInterval: 10 * time.Millisecond, BatchSize: 100, Lease: time.Second},
+		Jobs:        config.Jobs{Enabled: true, Workers: 1, PollInterval: 10 * time.Millisecond, MaxAttempts: 1, Backoff: time.Millisecond, Timeout: 5 * time.Second},
+		StorageRetry: config.StorageRetry{
+			Enabled: true, MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond,
+		},
+	}
+}
+
+// Response is a fully-read HTTP response, safe to inspect many times.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// -------------------------------------------------------------
+// Do() → Send a request; body may be nil, a string/[]byte (sent raw) or any
+// value (encoded as JSON)
+// -------------------------------------------------------------
+func (s *Server) Do(t testing.TB, method, path string, body any) *Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, encodeBody(t, body))
+	if err != nil {
+		t.Fatalf("build request %s %s: %v", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return s.Send(t, req)
+}
+
+// -------------------------------------------------------------
+// Send() → Execute a hand-built request (custom headers etc.)
+// -------------------------------------------------------------
+func (s *Server) Send(t testing.TB, req *http.Request) *Response {
+	t.Helper()
+
+	res, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("read body of %s %s: %v", req.Method, req.URL.Path, err)
+	}
+
+	return &Response{StatusCode: res.StatusCode, Header: res.Header, Body: data}
+}
+
+// -------------------------------------------------------------
+// Upload() → POST a single file as multipart/form-data
+// -------------------------------------------------------------
+func (s *Server) Upload(t testing.TB, path, field, filename string, data []byte) *Response {
+	t.Helper()
+	return s.UploadForm(t, path, nil, field, filename, data)
+}
+
+// -------------------------------------------------------------
+// UploadForm() → Upload with extra text fields (e.g. a document kind)
+// -------------------------------------------------------------
+func (s *Server) UploadForm(t testing.TB, path string, fields map[string]string, field, filename string, data []byte) *Response {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	part, err := form.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatalf("build multipart body: %v", err)
+	}
+	part.Write(data)
+	form.Close()
+
+	req, err := http.NewRequest(http.MethodPost, s.URL+path, &body)
+	if err != nil {
+		t.Fatalf("build request POST %s: %v", path, err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return s.Send(t, req)
+}
+
+// -------------------------------------------------------------
+// AwaitMail() → Wait (up to 2s) until n emails were sent; emails are async
+// -------------------------------------------------------------
+func (s *Server) AwaitMail(t testing.TB, n int) []notify.Message {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		sent := s.Mail.Sent()
+		if len(sent) >= n {
+			return sent
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d emails, want %d", len(sent), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// -------------------------------------------------------------
+// AwaitJob() → Wait (up to 2s) until job id is no longer queued or running
+// -------------------------------------------------------------
+func (s *Server) AwaitJob(t testing.TB, id int64) types.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, err := As[storage.JobStore](t, s.Storage).GetJobById(id)
+		if err != nil {
+			t.Fatalf("job %d: %v", id, err)
+		}
+		if job.Status == types.JobSucceeded || job.Status == types.JobFailed {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %d still %s", id, job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func encodeBody(t testing.TB, body any) io.Reader {
+	t.Helper()
+
+	switch b := body.(type) {
+	case nil:
+		return nil
+	case string:
+		return strings.NewReader(b)
+	case []byte:
+		return bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("encode request body: %v", err)
+		}
+		return bytes.NewReader(data)
+	}
+}
+
+// syncBuffer collects log output: the server's handlers and job workers
+// write to it while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// -------------------------------------------------------------
+// Assertions
+// -------------------------------------------------------------
+
+// AssertStatus fails the test when the status code differs.
+func (r *Response) AssertStatus(t testing.TB, want int) *Response {
+	t.Helper()
+
+	if r.StatusCode != want {
+		t.Fatalf("status = %d, want %d (body: %s)", r.StatusCode, want, r.Body)
+	}
+	return r
+}
+
+// AssertHeader fails the test when the header value differs.
+func (r *Response) AssertHeader(t testing.TB, key, want string) *Response {
+	t.Helper()
+
+	if got := r.Header.Get(key); got != want {
+		t.Fatalf("header %s = %q, want %q", key, got, want)
+	}
+	return r
+}
+
+// DecodeJSON unmarshals the body into v.
+func (r *Response) DecodeJSON(t testing.TB, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("decode JSON body: %v (body: %s)", err, r.Body)
+	}
+}
+
+// AssertJSONField checks a top-level field of a JSON object body.
+// Numbers are compared after JSON decoding, so pass float64 for them.
+func (r *Response) AssertJSONField(t testing.TB, field string, want any) *Response {
+	t.Helper()
+
+	var obj map[string]any
+	r.DecodeJSON(t, &obj)
+
+	got, ok := obj[field]
+	if !ok {
+		t.Fatalf("field %q missing from body: %s", field, r.Body)
+	}
+	if got != want {
+		t.Fatalf("field %q = %v (%T), want %v (%T)", field, got, got, want, want)
+	}
+	return r
+}
+
+// AssertErrorContains checks the `detail` of a response.Problem body.
+func (r *Response) AssertErrorContains(t testing.TB, substr string) *Response {
+	t.Helper()
+
+	var problem response.Problem
+	r.DecodeJSON(t, &problem)
+
+	if !strings.Contains(problem.Detail, substr) {
+		t.Fatalf("detail = %q, want it to contain %q", problem.Detail, substr)
+	}
+	return r
+}
@@ -0,0 +1,306 @@
+// Package token exchanges API keys for short-lived bearer access tokens
+// (HS256 JWTs) plus rotating refresh tokens, and checks both against the
+// revocation state in storage.
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+var (
+	ErrInvalid  = errors.New("invalid token")
+	ErrExpired  = errors.New("token has expired")
+	ErrRevoked  = errors.New("token has been revoked")
+	ErrReplayed = errors.New("refresh token was already used; every token of this session is now revoked")
+	// ErrTooLong is an impersonation asked for beyond auth.impersonation.max_ttl
+	ErrTooLong = errors.New("impersonation ttl exceeds auth.impersonation.max_ttl")
+)
+
+// header is the fixed JOSE header; tokens with any other header are rejected
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// IsLocal reports whether raw looks like one of our access tokens rather
+// than, say, an identity provider's ID token
+func IsLocal(raw string) bool {
+	return strings.HasPrefix(raw, header+".")
+}
+
+// Claims are the access token payload.
+type Claims struct {
+	// KeyID is the API key the session was started with
+	KeyID  int64    `json:"kid"`
+	Scopes []string `json:"scopes"`
+	// Family ties the token to its refresh token chain (for logout)
+	Family string `json:"fam"`
+	// Subject is whom an impersonation token acts as ("key:<id>",
+	// "user:<sub>" or "student:<id>") and Impersonator the admin it was
+	// issued to. TenantID is the only tenant the token reaches: its API
+	// key's, or the impersonated subject's.
+	Subject      string `json:"sub,omitempty"`
+	Impersonator string `json:"imp,omitempty"`
+	TenantID     int64  `json:"tid,omitempty"`
+	ID           string `json:"jti"`
+	IssuedAt     int64  `json:"iat"`
+	ExpiresAt    int64  `json:"exp"`
+}
+
+// Issuer signs and verifies tokens.
+// A nil *Issuer (no auth.token_secret) means bearer tokens are disabled.
+type Issuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	// impersonation bounds impersonation tokens
+	impersonation config.Impersonation
+	tokens        storage.TokenStore
+	keys          storage.APIKeyStore
+}
+
+// -------------------------------------------------------------
+// New() → Issuer, or nil when tokens are disabled or unsupported
+// -------------------------------------------------------------
+func New(cfg config.Auth, backend storage.Storage) *Issuer {
+	tokens, ok := storage.As[storage.TokenStore](backend)
+	keys, hasKeys := storage.As[storage.APIKeyStore](backend)
+	if cfg.TokenSecret == "" || !ok || !hasKeys {
+		return nil
+	}
+	return &Issuer{
+		secret:        []byte(cfg.TokenSecret),
+		accessTTL:     cfg.AccessTokenTTL,
+		refreshTTL:    cfg.RefreshTokenTTL,
+		impersonation: impersonation(cfg.Impersonation),
+		tokens:        tokens,
+		keys:          keys,
+	}
+}
+
+// impersonation is cfg, or the defaults for hand-built configs; load
+// validates it
+func impersonation(cfg config.Impersonation) config.Impersonation {
+	if cfg.TTL <= 0 || cfg.MaxTTL < cfg.TTL {
+		return config.Impersonation{TTL: 15 * time.Minute, MaxTTL: time.Hour}
+	}
+	return cfg
+}
+
+// -------------------------------------------------------------
+// Issue() → Start a session for an API key (new refresh family)
+// -------------------------------------------------------------
+func (i *Issuer) Issue(key types.APIKey) (types.TokenPair, error) {
+	if err := apikey.Check(key, time.Now()); err != nil {
+		return types.TokenPair{}, err
+	}
+	return i.pair(key, randomHex(16))
+}
+
+// -------------------------------------------------------------
+// Impersonate() → Access token acting as imp.Subject for imp.Impersonator
+// -------------------------------------------------------------
+// The token has imp.Scopes and lives imp.ExpiresAt's distance from now:
+// auth.impersonation.ttl when it is zero, never beyond max_ttl
+// (ErrTooLong). No refresh token: when it expires the admin asks again.
+// The returned imp has its ID, AccessToken and ExpiresAt set.
+func (i *Issuer) Impersonate(imp types.Impersonation, tenantID int64, ttl time.Duration) (types.Impersonation, error) {
+	if ttl == 0 {
+		ttl = i.impersonation.TTL
+	}
+	if ttl < 0 || ttl > i.impersonation.MaxTTL {
+		return types.Impersonation{}, fmt.Errorf("%w (%s)", ErrTooLong, i.impersonation.MaxTTL)
+	}
+	now := time.Now()
+	claims := Claims{
+		Scopes:       imp.Scopes,
+		Subject:      imp.Subject,
+		Impersonator: imp.Impersonator,
+		TenantID:     tenantID,
+		ID:           randomHex(16),
+		IssuedAt:     now.Unix(),
+		ExpiresAt:    now.Add(ttl).Unix(),
+	}
+	access, err := i.signClaims(claims)
+	if err != nil {
+		return types.Impersonation{}, err
+	}
+	imp.ID, imp.AccessToken, imp.TokenType = claims.ID, access, "Bearer"
+	imp.ExpiresAt = time.Unix(claims.ExpiresAt, 0).UTC()
+	return imp, nil
+}
+
+// -------------------------------------------------------------
+// EndImpersonation() → Revoke an impersonation token by its id
+// -------------------------------------------------------------
+// Tokens can't outlive max_ttl, so the deny-list entry doesn't either.
+func (i *Issuer) EndImpersonation(id string) error {
+	return i.tokens.RevokeAccessToken(id, time.Now().Add(i.impersonation.MaxTTL))
+}
+
+// -------------------------------------------------------------
+// Refresh() → Use up a refresh token and issue the next pair
+// -------------------------------------------------------------
+// Replaying a used refresh token revokes its whole family: either the
+// client or an attacker holds a stolen copy, and we can't tell which.
+func (i *Issuer) Refresh(refresh string) (types.TokenPair, error) {
+	stored, err := i.tokens.GetRefreshTokenByHash(apikey.Hash(refresh))
+	if err != nil {
+		return types.TokenPair{}, ErrInvalid
+	}
+	if stored.RevokedAt != nil {
+		return types.TokenPair{}, ErrRevoked
+	}
+	if !time.Now().Before(stored.ExpiresAt) {
+		return types.TokenPair{}, ErrExpired
+	}
+
+	fresh, err := i.tokens.UseRefreshToken(stored.ID)
+	if err != nil {
+		return types.TokenPair{}, err
+	}
+	if !fresh {
+		if err := i.tokens.RevokeRefreshFamily(stored.Family); err != nil {
+			return types.TokenPair{}, err
+		}
+		return types.TokenPair{}, ErrReplayed
+	}
+
+	// the key may have been revoked (or narrowed) since the last refresh
+	key, err := i.keys.GetAPIKeyById(stored.APIKeyID)
+	if err != nil {
+		return types.TokenPair{}, ErrInvalid
+	}
+	if err := apikey.Check(key, time.Now()); err != nil {
+		return types.TokenPair{}, err
+	}
+	return i.pair(key, stored.Family)
+}
+
+// -------------------------------------------------------------
+// Verify() → Claims of a valid, unexpired, unrevoked access token
+// -------------------------------------------------------------
+// Its API key must still be valid too.
+func (i *Issuer) Verify(access string) (Claims, error) {
+	parts := strings.Split(access, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return Claims{}, ErrInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, i.sign(parts[0]+"."+parts[1])) {
+		return Claims{}, ErrInvalid
+	}
+
+	var claims Claims
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(payload, &claims) != nil {
+		return Claims{}, ErrInvalid
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrExpired
+	}
+
+	revoked, err := i.tokens.IsAccessTokenRevoked(claims.ID)
+	if err != nil {
+		return Claims{}, fmt.Errorf("check token revocation: %w", err)
+	}
+	if revoked {
+		return Claims{}, ErrRevoked
+	}
+
+	// the key may have been revoked (or expired) since the token was issued;
+	// impersonation tokens carry no key
+	if claims.KeyID != 0 {
+		key, err := i.keys.GetAPIKeyById(claims.KeyID)
+		if err != nil {
+			return Claims{}, ErrInvalid
+		}
+		if err := apikey.Check(key, time.Now()); err != nil {
+			return Claims{}, err
+		}
+	}
+	return claims, nil
+}
+
+// -------------------------------------------------------------
+// Logout() → Revoke the access token and its refresh family
+// -------------------------------------------------------------
+func (i *Issuer) Logout(claims Claims) error {
+	if err := i.tokens.RevokeAccessToken(claims.ID, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		return err
+	}
+	if claims.Family == "" {
+		// an impersonation token has no refresh chain
+		return nil
+	}
+	return i.tokens.RevokeRefreshFamily(claims.Family)
+}
+
+// pair signs an access token and stores the next refresh token of family
+func (i *Issuer) pair(key types.APIKey, family string) (types.TokenPair, error) {
+	now := time.Now()
+	claims := Claims{
+		KeyID:     key.ID,
+		Scopes:    key.Scopes,
+		TenantID:  key.TenantID,
+		Family:    family,
+		ID:        randomHex(16),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(i.accessTTL).Unix(),
+	}
+	access, err := i.signClaims(claims)
+	if err != nil {
+		return types.TokenPair{}, err
+	}
+
+	refresh := "rt_" + randomHex(32)
+	_, err = i.tokens.CreateRefreshToken(types.RefreshToken{
+		APIKeyID:  key.ID,
+		Family:    family,
+		Hash:      apikey.Hash(refresh),
+		ExpiresAt: now.Add(i.refreshTTL),
+	})
+	if err != nil {
+		return types.TokenPair{}, err
+	}
+
+	return types.TokenPair{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(i.accessTTL.Seconds()),
+		RefreshToken: refresh,
+	}, nil
+}
+
+// signClaims encodes and signs an access token
+func (i *Issuer) signClaims(claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(i.sign(unsigned)), nil
+}
+
+func (i *Issuer) sign(data string) []byte {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,195 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/storage/memory"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+func newIssuer(t *testing.T, accessTTL time.Duration) (*Issuer, *memory.Memory, types.APIKey) {
+	t.Helper()
+	store := memory.New()
+	_, _, hash := apikey.Generate()
+	key := types.APIKey{Name: "ci", Scopes: []string{types.ScopeRead}, TenantID: 1, Hash: hash}
+	id, err := store.CreateAPIKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.ID = id
+	i := New(config.Auth{TokenSecret: "a test secret", AccessTokenTTL: accessTTL, RefreshTokenTTL: time.Hour}, store)
+	if i == nil {
+		t.Fatal("New returned nil with a secret and a token store")
+	}
+	return i, store, key
+}
+
+func TestNewDisabled(t *testing.T) {
+	if New(config.Auth{}, memory.New()) != nil {
+		t.Error("New without auth.token_secret should be nil")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	i, _, key := newIssuer(t, time.Minute)
+	pair, err := i.Issue(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsLocal(pair.AccessToken) || !strings.HasPrefix(pair.RefreshToken, "rt_") {
+		t.Fatalf("pair = %+v", pair)
+	}
+	claims, err := i.Verify(pair.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.KeyID != key.ID || claims.TenantID != 1 || claims.Family == "" {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	other := New(config.Auth{TokenSecret: "another secret", AccessTokenTTL: time.Minute}, memoryWithKey(t, key))
+	parts := strings.Split(pair.AccessToken, ".")
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "garbage", token: "not.a.token"},
+		{name: "two parts", token: parts[0] + "." + parts[1]},
+		{name: "other header", token: "eyJhbGciOiJub25lIn0." + parts[1] + "." + parts[2]},
+		{name: "tampered payload", token: parts[0] + "." + parts[1] + "x." + parts[2]},
+		{name: "bad signature", token: parts[0] + "." + parts[1] + ".AAAA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := i.Verify(tt.token); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Verify = %v, want ErrInvalid", err)
+			}
+		})
+	}
+	if _, err := other.Verify(pair.AccessToken); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Verify with another secret = %v, want ErrInvalid", err)
+	}
+}
+
+// memoryWithKey is a store that knows key, for a second issuer
+func memoryWithKey(t *testing.T, key types.APIKey) *memory.Memory {
+	store := memory.New()
+	if _, err := store.CreateAPIKey(key); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestExpiredAndRevoked(t *testing.T) {
+	i, store, key := newIssuer(t, -time.Second)
+	pair, err := i.Issue(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.Verify(pair.AccessToken); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify of an expired token = %v, want ErrExpired", err)
+	}
+
+	i.accessTTL = time.Minute
+	pair, _ = i.Issue(key)
+	claims, err := i.Verify(pair.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := i.Logout(claims); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.Verify(pair.AccessToken); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Verify after logout = %v, want ErrRevoked", err)
+	}
+	if _, err := i.Refresh(pair.RefreshToken); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Refresh after logout = %v, want ErrRevoked", err)
+	}
+
+	// a revoked key takes its tokens with it
+	pair, _ = i.Issue(key)
+	if _, err := store.RevokeAPIKey(key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.Verify(pair.AccessToken); !errors.Is(err, apikey.ErrRevoked) {
+		t.Errorf("Verify after key revocation = %v, want apikey.ErrRevoked", err)
+	}
+}
+
+// Using a refresh token twice revokes the whole family
+func TestRefreshReplay(t *testing.T) {
+	i, _, key := newIssuer(t, time.Minute)
+	first, err := i.Issue(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := i.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token not rotated")
+	}
+
+	if _, err := i.Refresh(first.RefreshToken); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("replayed Refresh = %v, want ErrReplayed", err)
+	}
+	if _, err := i.Refresh(second.RefreshToken); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Refresh of the family's newest token = %v, want ErrRevoked", err)
+	}
+	if _, err := i.Refresh("rt_unknown"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Refresh of an unknown token = %v, want ErrInvalid", err)
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	i, _, _ := newIssuer(t, time.Minute)
+	imp := types.Impersonation{Subject: "student:7", Impersonator: "key:1", Scopes: []string{types.ScopeRead}}
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		want    time.Duration
+		wantErr error
+	}{
+		{name: "default ttl", ttl: 0, want: 15 * time.Minute},
+		{name: "shorter ttl", ttl: time.Minute, want: time.Minute},
+		{name: "beyond max_ttl", ttl: 2 * time.Hour, wantErr: ErrTooLong},
+		{name: "negative ttl", ttl: -time.Minute, wantErr: ErrTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := i.Impersonate(imp, 2, tt.ttl)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Impersonate = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if left := time.Until(got.ExpiresAt); left > tt.want || left < tt.want-2*time.Second {
+				t.Errorf("expires in %s, want %s", left, tt.want)
+			}
+			claims, err := i.Verify(got.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Subject != imp.Subject || claims.TenantID != 2 || claims.KeyID != 0 {
+				t.Errorf("claims = %+v", claims)
+			}
+			if err := i.EndImpersonation(got.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := i.Verify(got.AccessToken); !errors.Is(err, ErrRevoked) {
+				t.Errorf("Verify after EndImpersonation = %v, want ErrRevoked", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Package trash purges soft-deleted students; the scheduler runs it periodically.
+package trash
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/blob"
+	"github.com/manish-npx/go-student-api/internal/storage"
+)
+
+// -------------------------------------------------------------
+// Purge() → Hard-delete everything soft-deleted more than retention ago
+// -------------------------------------------------------------
+// The students' photos and documents go from the blob store too, after
+// the rows: a file that fails to delete is logged, never left with a row
+// pointing at it.
+func Purge(ctx context.Context, store storage.TrashStore, blobs blob.Store, retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+
+	purged, err := store.PurgeDeletedBefore(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	// 🗑️ Their files
+	for _, id := range purged.StudentIDs {
+		deleteBlob(ctx, blobs, blob.PhotoKey(id))
+	}
+	for _, key := range purged.DocumentKeys {
+		deleteBlob(ctx, blobs, key)
+	}
+
+	slog.Info("🗑️ Purged soft-deleted students",
+		slog.Int("count", len(purged.StudentIDs)),
+		slog.Time("cutoff", cutoff),
+	)
+	return int64(len(purged.StudentIDs)), nil
+}
+
+// deleteBlob removes key; most students have no photo, so a missing one is fine
+func deleteBlob(ctx context.Context, blobs blob.Store, key string) {
+	if err := blobs.Delete(ctx, key); err != nil && !errors.Is(err, blob.ErrNotFound) {
+		slog.Error("Error deleting purged student's file", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
@@ -1,8 +1,874 @@
 package types
 
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+)
+
 type Student struct {
 	ID    int64  `json:"id"`
 	Name  string `json:"name" validate:"required"`
 	Email string `json:"email" validate:"required,email"`
-	Age   int    `json:"age" validate:"required,gte=1,lte=100"`
+	// Age is derived from DateOfBirth and ignored on input. Students saved
+	// before date_of_birth existed keep the age they were given.
+	Age int `json:"age"`
+	// Phone is in E.164 form, e.g. +14155550123
+	Phone       string `json:"phone,omitempty" validate:"omitempty,e164"`
+	DateOfBirth string `json:"date_of_birth,omitempty" validate:"required,datetime=2006-01-02"`
+	Gender      string `json:"gender,omitempty" validate:"omitempty,oneof=female male non_binary other undisclosed"`
+	// Address is optional; when given, its required fields must be set
+	Address *Address `json:"address,omitempty"`
+	// Custom holds the deployment's extra attributes (see CustomField),
+	// checked against their definitions rather than struct tags
+	Custom map[string]any `json:"custom,omitempty"`
+	// Verified is set once the student follows the link emailed to them and
+	// cleared when their email changes; ignored on input
+	Verified bool `json:"verified"`
+	// PublicID is a ULID given by the storage layer on create, for clients
+	// that shouldn't be able to guess or count ids; ignored on input
+	PublicID string `json:"public_id,omitempty"`
+}
+
+// Address is a student's postal address.
+type Address struct {
+	Line1      string `json:"line1" validate:"required,max=200"`
+	Line2      string `json:"line2,omitempty" validate:"max=200"`
+	City       string `json:"city" validate:"required,max=100"`
+	Region     string `json:"region,omitempty" validate:"max=100"`
+	PostalCode string `json:"postal_code" validate:"required,max=20"`
+	// Country is an ISO 3166-1 alpha-2 code, e.g. GB
+	Country string `json:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+// DateLayout is how dates (date_of_birth) are written in JSON and stored
+const DateLayout = "2006-01-02"
+
+// AgeOn returns the age in whole years on now's UTC day for a DateLayout
+// birth date; ok is false when dob is empty or malformed
+func AgeOn(dob string, now time.Time) (age int, ok bool) {
+	born, err := time.Parse(DateLayout, dob)
+	if err != nil {
+		return 0, false
+	}
+	now = now.UTC()
+	age = now.Year() - born.Year()
+	if now.Month() < born.Month() || (now.Month() == born.Month() && now.Day() < born.Day()) {
+		age--
+	}
+	return age, true
+}
+
+// DeriveAge sets Age from DateOfBirth (left alone when there is none)
+func (s *Student) DeriveAge(now time.Time) {
+	if age, ok := AgeOn(s.DateOfBirth, now); ok {
+		s.Age = age
+	}
+}
+
+// Custom field types
+const (
+	CustomString  = "string"
+	CustomNumber  = "number"
+	CustomBoolean = "boolean"
+	// CustomDate values are YYYY-MM-DD strings (DateLayout)
+	CustomDate = "date"
+)
+
+// CustomField defines an extra student attribute, kept under the
+// student's "custom" object. Source is "config" or "api".
+type CustomField struct {
+	Key       string     `json:"key" validate:"required,max=63"`
+	Type      string     `json:"type" validate:"required,oneof=string number boolean date"`
+	Required  bool       `json:"required"`
+	Source    string     `json:"source,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+var customKey = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ValidCustomFieldKey reports whether key is lower snake_case (up to 63
+// characters), which keeps it safe inside JSON paths and query parameters
+func ValidCustomFieldKey(key string) bool {
+	return len(key) <= 63 && customKey.MatchString(key)
+}
+
+// Tenant is one school/organisation; every student belongs to exactly one.
+type Tenant struct {
+	ID   int64  `json:"id"`
+	Slug string `json:"slug" validate:"required,lowercase,max=63"`
+	Name string `json:"name" validate:"required"`
+}
+
+// Stats is the aggregate view served to admin dashboards.
+type Stats struct {
+	TotalStudents int64       `json:"total_students"`
+	ByAge         []AgeBucket `json:"by_age"`
+	// CreatedPerDay has one entry per day (oldest first), zero-filled
+	CreatedPerDay []DayCount `json:"created_per_day"`
+	// Timezone the days are in (time.display_timezone or ?tz)
+	Timezone string `json:"timezone"`
+}
+
+type AgeBucket struct {
+	Label string `json:"label"`
+	Min   int    `json:"min"`
+	Max   int    `json:"max"`
+	Count int64  `json:"count"`
+}
+
+// AgeDistribution is the age histogram served at
+// /api/reports/age-distribution.
+type AgeDistribution struct {
+	Total   int64       `json:"total"`
+	Buckets []AgeBucket `json:"buckets"`
+	// Other counts the students outside every bucket
+	Other int64 `json:"other"`
+}
+
+// BirthdayReport counts the students born in one month, per day of it.
+type BirthdayReport struct {
+	Month int   `json:"month"`
+	Total int64 `json:"total"`
+	// Days has every day of the month (29 for February), zero-filled
+	Days []DayOfMonthCount `json:"days"`
+}
+
+type DayOfMonthCount struct {
+	Day   int   `json:"day"`
+	Count int64 `json:"count"`
+}
+
+type DayCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD (UTC)
+	Count int64  `json:"count"`
+}
+
+// Page is one page of a cursor-paginated listing.
+type Page[T any] struct {
+	Data []T `json:"data"`
+	// NextCursor is opaque; pass it back as ?cursor= to get the next page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// PrevCursor, likewise, fetches the page before this one
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Limit      int    `json:"limit"`
+	// Links holds self / first / last / next / prev page URLs
+	Links Links `json:"_links,omitempty"`
+}
+
+// Link is one HATEOAS link; Method is omitted for plain GETs.
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"`
+}
+
+// Links maps a relation name (self, next, update, ...) to its link.
+type Links map[string]Link
+
+// StudentResource is a student as the API serves it: the record plus _links.
+type StudentResource struct {
+	Student
+	Links Links `json:"_links"`
+	// Related resources, set when asked for with ?include=
+	Guardians []StudentGuardian `json:"guardians,omitzero"`
+	Documents []Document        `json:"documents,omitzero"`
+	Invoices  []Invoice         `json:"invoices,omitzero"`
+}
+
+// StudentBatch answers a batch lookup: found students in the order asked,
+// plus the ids that don't exist (or aren't visible to the tenant).
+type StudentBatch struct {
+	Data    []StudentResource `json:"data"`
+	Missing []int64           `json:"missing"`
+}
+
+// BatchRequest is the body of POST /api/students/batch-get.
+type BatchRequest struct {
+	IDs []int64 `json:"ids" validate:"required,min=1,max=100,dive,gt=0"`
+}
+
+// BulkUpdateRequest is the body of PATCH /api/students/bulk.
+type BulkUpdateRequest struct {
+	IDs     []int64        `json:"ids" validate:"required,min=1,max=1000,dive,gt=0"`
+	Changes StudentChanges `json:"changes"`
+}
+
+// StudentChanges is a partial student: only the fields present change.
+// Name and email identify a student, so they can't be changed in bulk.
+type StudentChanges struct {
+	Phone       *string  `json:"phone,omitempty"`
+	DateOfBirth *string  `json:"date_of_birth,omitempty"`
+	Gender      *string  `json:"gender,omitempty"`
+	Address     *Address `json:"address,omitempty"`
+	// Custom is merged into the student's values; null removes a key
+	Custom map[string]any `json:"custom,omitempty"`
+}
+
+// Empty reports whether the changes would leave a student as it is
+func (c StudentChanges) Empty() bool {
+	return c.Phone == nil && c.DateOfBirth == nil && c.Gender == nil && c.Address == nil && len(c.Custom) == 0
+}
+
+// Apply returns student with the changes made
+func (c StudentChanges) Apply(student Student) Student {
+	if c.Phone != nil {
+		student.Phone = *c.Phone
+	}
+	if c.DateOfBirth != nil {
+		student.DateOfBirth = *c.DateOfBirth
+	}
+	if c.Gender != nil {
+		student.Gender = *c.Gender
+	}
+	if c.Address != nil {
+		address := *c.Address
+		student.Address = &address
+	}
+	if len(c.Custom) > 0 {
+		custom := make(map[string]any, len(student.Custom)+len(c.Custom))
+		for key, value := range student.Custom {
+			custom[key] = value
+		}
+		for key, value := range c.Custom {
+			if value == nil {
+				delete(custom, key)
+			} else {
+				custom[key] = value
+			}
+		}
+		student.Custom = custom
+	}
+	return student
+}
+
+// BulkDeleteRequest is the body of DELETE /api/students/bulk.
+type BulkDeleteRequest struct {
+	IDs []int64 `json:"ids" validate:"required,min=1,max=1000,dive,gt=0"`
+}
+
+// Bulk item statuses
+const (
+	BulkUpdated  = "updated"
+	BulkDeleted  = "deleted"
+	BulkNotFound = "not_found"
+	// BulkInvalid means the changes would leave the student invalid
+	BulkInvalid = "invalid"
+)
+
+// BulkResult is the outcome for one id of a bulk request.
+type BulkResult struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Student is the stored record after an update, for webhooks
+	Student *Student `json:"-"`
+}
+
+// Roster sync actions
+const (
+	SyncCreate = "create"
+	SyncUpdate = "update"
+	SyncFailed = "failed"
+)
+
+// SyncReport describes one roster sync run. In a dry run the counts and
+// changes are what the run would have done; nothing is written.
+type SyncReport struct {
+	Connector  string    `json:"connector"`
+	Tenant     string    `json:"tenant,omitempty"`
+	DryRun     bool      `json:"dry_run"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Fetched    int       `json:"fetched"`
+	Created    int       `json:"created"`
+	Updated    int       `json:"updated"`
+	Unchanged  int       `json:"unchanged"`
+	Failed     int       `json:"failed"`
+	// Changes lists every create, update and failure; unchanged students
+	// are only counted
+	Changes []SyncChange `json:"changes"`
+	// Error is set when the roster could not be fetched at all
+	Error string `json:"error,omitempty"`
+}
+
+// Email domain migration outcomes
+const (
+	DomainMoved = "moved"
+	// DomainConflict means another student already holds the new email
+	DomainConflict = "conflict"
+)
+
+// EmailDomainMigration describes one rewrite of student emails from one
+// domain to another. In a dry run the counts and changes are what the run
+// would have done; nothing is written.
+type EmailDomainMigration struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Tenant    string `json:"tenant,omitempty"`
+	DryRun    bool   `json:"dry_run"`
+	Matched   int    `json:"matched"`
+	Moved     int    `json:"moved"`
+	Conflicts int    `json:"conflicts"`
+	// Changes lists every matched student, in id order
+	Changes []EmailDomainChange `json:"changes"`
+}
+
+// EmailDomainChange is what a migration did (or would do) to one student.
+type EmailDomainChange struct {
+	ID       int64  `json:"id"`
+	Email    string `json:"email"`
+	NewEmail string `json:"new_email"`
+	Status   string `json:"status"`
+	// ConflictID is the student already holding NewEmail
+	ConflictID int64 `json:"conflict_id,omitempty"`
+}
+
+// SyncChange is what a sync did (or would do) to one roster row.
+type SyncChange struct {
+	Email  string `json:"email"`
+	Action string `json:"action"`
+	ID     int64  `json:"id,omitempty"`
+	// Fields names what an update changes, e.g. ["gender","address"]
+	Fields []string `json:"fields,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// SyncConnector is a configured roster source as the admin API lists it.
+type SyncConnector struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Tenant  string      `json:"tenant,omitempty"`
+	LastRun *SyncReport `json:"last_run,omitempty"`
+}
+
+// StudentPage is a page of full student records.
+type StudentPage = Page[Student]
+
+// Document is a file attached to a student (transcript, ID scan, ...).
+// The bytes live in the blob store under BlobKey.
+type Document struct {
+	ID          int64     `json:"id"`
+	StudentID   int64     `json:"student_id"`
+	Kind        string    `json:"kind" validate:"required,oneof=transcript id_scan other"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	BlobKey     string    `json:"-"`
+	// URL is filled in per response: presigned when the blob store supports it
+	URL string `json:"url,omitempty"`
+}
+
+// Guardian is a parent or other contact of one or more students. Email and
+// phone are sealed like the student's when field encryption is on.
+type Guardian struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" validate:"required,max=200"`
+	Email     string    `json:"email,omitempty" validate:"omitempty,email"`
+	Phone     string    `json:"phone,omitempty" validate:"omitempty,e164"`
+	CreatedAt time.Time `json:"created_at"`
+	// Students is filled in by GET /api/guardians/{id}
+	Students []GuardianLink `json:"students,omitempty"`
+}
+
+// GuardianLink is one student of a guardian
+type GuardianLink struct {
+	StudentID    int64  `json:"student_id"`
+	Relationship string `json:"relationship"`
+}
+
+// StudentGuardian is a guardian as seen from one of their students
+type StudentGuardian struct {
+	Guardian
+	Relationship string `json:"relationship"`
+}
+
+// GuardianRelationship is the body of PUT /api/student/{id}/guardians/{guardianId}
+type GuardianRelationship struct {
+	Relationship string `json:"relationship" validate:"required,oneof=mother father parent guardian grandparent sibling other"`
+}
+
+// Invoice statuses; an open invoice due before today is also Overdue
+const (
+	InvoiceOpen = "open"
+	InvoicePaid = "paid"
+)
+
+// Invoice is a fee billed to a student. Amount is in minor units (cents).
+type Invoice struct {
+	ID          int64  `json:"id"`
+	StudentID   int64  `json:"student_id"`
+	Amount      int64  `json:"amount" validate:"gt=0"`
+	Currency    string `json:"currency" validate:"required,len=3,uppercase"`
+	Description string `json:"description,omitempty" validate:"max=200"`
+	DueDate     string `json:"due_date" validate:"required,datetime=2006-01-02"`
+	Status      string `json:"status"`
+	// PaymentReference identifies the payment that settled the invoice;
+	// unique per tenant
+	PaymentReference string     `json:"payment_reference,omitempty"`
+	PaidAt           *time.Time `json:"paid_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	// Overdue is derived per response (see DeriveOverdue) and ignored on input
+	Overdue bool `json:"overdue"`
+}
+
+// DeriveOverdue sets Overdue: still open and due before now's UTC date
+func (inv *Invoice) DeriveOverdue(now time.Time) {
+	inv.Overdue = inv.Status == InvoiceOpen && inv.DueDate < now.UTC().Format(DateLayout)
+}
+
+// InvoicePayment is the body of POST /api/invoices/{id}/pay
+type InvoicePayment struct {
+	Reference string `json:"payment_reference" validate:"required,max=100"`
+}
+
+// OverdueReport lists the open invoices due before AsOf
+type OverdueReport struct {
+	AsOf  string `json:"as_of"`
+	Count int    `json:"count"`
+	// Totals sums the outstanding amounts per currency
+	Totals   map[string]int64 `json:"totals"`
+	Invoices []Invoice        `json:"invoices"`
+}
+
+// Job statuses
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is one unit of background work (send an email, purge the trash, ...).
+type Job struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	// RunAt is the earliest time a worker may pick the job (retries push it back)
+	RunAt      time.Time  `json:"run_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Snapshot is a point-in-time copy of a tenant's students in the blob
+// store, for audits; its ID is the ID of the job that takes it.
+type Snapshot struct {
+	ID int64 `json:"id"`
+	// Status is the job's: queued, running, succeeded or failed
+	Status     string     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// SnapshotDiff is what changed between two snapshots, matched by student id.
+type SnapshotDiff struct {
+	From    int64            `json:"from"`
+	To      int64            `json:"to"`
+	Added   []Student        `json:"added"`
+	Removed []Student        `json:"removed"`
+	Changed []SnapshotChange `json:"changed"`
+}
+
+// SnapshotChange is a student found in both snapshots with different fields.
+type SnapshotChange struct {
+	ID int64 `json:"id"`
+	// Fields names what differs, e.g. ["email","address"]
+	Fields []string `json:"fields"`
+	Before Student  `json:"before"`
+	After  Student  `json:"after"`
+}
+
+// Export is a background dump of a tenant's students to the blob store;
+// its ID is the ID of the job that writes it.
+type Export struct {
+	ID     int64  `json:"id"`
+	Format string `json:"format"`
+	// Status is the job's: queued, running, succeeded or failed
+	Status     string     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// URL is set once the file is ready: presigned when the blob store supports it
+	URL string `json:"url,omitempty"`
+}
+
+// ScheduledTask reports one scheduler entry and its run counters.
+type ScheduledTask struct {
+	Name     string `json:"name"`
+	Task     string `json:"task"`
+	Schedule string `json:"schedule"`
+	Running  bool   `json:"running"`
+	Runs     int64  `json:"runs"`
+	Failures int64  `json:"failures"`
+	// Skipped counts runs dropped because the previous one was still going
+	Skipped      int64      `json:"skipped"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	NextRun      time.Time  `json:"next_run"`
+}
+
+// Maintenance is the maintenance-mode switch: while enabled, every
+// non-admin request answers 503 with Retry-After.
+type Maintenance struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+	// RetryAfter is the Retry-After sent with the 503, e.g. "5m0s"
+	RetryAfter string `json:"retry_after"`
+	// Since is when it was last switched on; unset while disabled
+	Since *time.Time `json:"since,omitempty"`
+}
+
+// FeatureFlags lists every known feature flag with its effective rule
+type FeatureFlags struct {
+	Flags []FeatureFlag `json:"flags"`
+	// Remote is the flag service; unset without feature_flags.remote.url
+	Remote *FeatureFlagsRemote `json:"remote,omitempty"`
+}
+
+type FeatureFlag struct {
+	Name    string   `json:"name"`
+	Enabled bool     `json:"enabled"`
+	Rollout int      `json:"rollout"`
+	Tenants []string `json:"tenants,omitempty"`
+	// Source is where the rule came from: default, config or remote
+	Source string `json:"source"`
+}
+
+type FeatureFlagsRemote struct {
+	URL string `json:"url"`
+	// SyncedAt is the last successful fetch
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+	// Error is why the latest fetch failed; empty when it worked
+	Error string `json:"error,omitempty"`
+}
+
+// Webhook is an external URL subscribed to student lifecycle events.
+// Secret signs every delivery; it is only returned when the webhook is created.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url" validate:"required,http_url"`
+	Events    []string  `json:"events" validate:"required,min=1,dive,oneof=* student.created student.updated student.deleted student.restored student.erased"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent (or being sent) to one webhook.
+type WebhookDelivery struct {
+	ID        int64           `json:"id"`
+	WebhookID int64           `json:"webhook_id"`
+	Event     string          `json:"event"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	// ResponseStatus is the HTTP status of the last attempt (0 = no response)
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// Student lifecycle events, recorded in the outbox by every student mutation
+const (
+	EventStudentCreated  = "student.created"
+	EventStudentUpdated  = "student.updated"
+	EventStudentDeleted  = "student.deleted"
+	EventStudentRestored = "student.restored"
+	EventStudentErased   = "student.erased"
+)
+
+// OutboxEvent is a student event committed with its change and waiting to
+// be relayed to webhooks.
+type OutboxEvent struct {
+	ID       int64  `json:"id"`
+	TenantID int64  `json:"tenant_id"`
+	Event    string `json:"event"`
+	// Payload is the event's data: the student, or {"id"} for deletes and erasures
+	Payload json.RawMessage `json:"payload"`
+	// Trace is the httpclient.Trace of the request that made the change
+	Trace     json.RawMessage `json:"trace,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// API key scopes
+const (
+	// ScopeRead allows GET and HEAD outside /api/admin
+	ScopeRead = "read"
+	// ScopeWrite allows every other method outside /api/admin
+	ScopeWrite = "write"
+	// ScopeAdmin allows everything, /api/admin included
+	ScopeAdmin = "admin"
+)
+
+// APIKey lets a machine client call the API without a user session.
+// Only a SHA-256 hash is stored; Key is returned once, when it is created.
+type APIKey struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=read write admin"`
+	// TenantID is the only tenant whose students the key reaches (the
+	// default tenant when created without one)
+	TenantID int64 `json:"tenant_id"`
+	// Prefix is the start of the key, enough to tell keys apart in listings
+	Prefix    string     `json:"prefix"`
+	Key       string     `json:"key,omitempty"`
+	Hash      string     `json:"-"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TokenPair is what /api/auth/token and /api/auth/refresh hand out.
+type TokenPair struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresIn is the access token's lifetime in seconds
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Impersonation is what POST /api/admin/impersonations hands out: a bearer
+// token acting as Subject ("key:<id>", "user:<sub>" or "student:<id>") on
+// behalf of Impersonator, until ExpiresAt. It can't be refreshed.
+type Impersonation struct {
+	// ID is the token's jti, for DELETE /api/admin/impersonations/{id}
+	ID           string    `json:"id"`
+	AccessToken  string    `json:"access_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Subject      string    `json:"subject"`
+	Impersonator string    `json:"impersonator"`
+	Scopes       []string  `json:"scopes,omitempty"`
+	Reason       string    `json:"reason"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// RefreshToken is stored by hash. Every refresh uses one up and issues the
+// next in the same Family; replaying a used one revokes the whole family.
+type RefreshToken struct {
+	ID        int64
+	APIKeyID  int64
+	Family    string
+	Hash      string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// VerificationToken is stored by hash; the token itself only travels in
+// the link emailed to the student.
+type VerificationToken struct {
+	ID        int64
+	TenantID  int64
+	StudentID int64
+	Hash      string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// StudentCredential is a student's self-service portal password
+type StudentCredential struct {
+	StudentID    int64
+	TenantID     int64
+	PasswordHash string
+	// FailedLogins in a row; reaching portal.max_failed_logins sets LockedUntil
+	FailedLogins int
+	LockedUntil  *time.Time
+	UpdatedAt    time.Time
+}
+
+// PortalSession is a signed-in student. Only the hash of its bearer token
+// is stored.
+type PortalSession struct {
+	ID        int64
+	TenantID  int64
+	StudentID int64
+	Hash      string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// QuotaLimit overrides the configured daily quotas for one subject.
+// Subjects are "key:<id>" (API keys and their bearer tokens) or
+// "user:<sub>" (OIDC users); 0 means unlimited.
+type QuotaLimit struct {
+	Subject       string    `json:"subject"`
+	DailyRequests int64     `json:"daily_requests" validate:"min=0"`
+	DailyWrites   int64     `json:"daily_writes" validate:"min=0"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// QuotaUsage counts a subject's requests on one UTC day (YYYY-MM-DD).
+type QuotaUsage struct {
+	Day      string `json:"day"`
+	Requests int64  `json:"requests"`
+	Writes   int64  `json:"writes"`
+}
+
+// Quota is a subject's effective limits plus today's usage.
+type Quota struct {
+	Subject       string `json:"subject"`
+	DailyRequests int64  `json:"daily_requests"`
+	DailyWrites   int64  `json:"daily_writes"`
+	// Custom is false while the subject gets the quotas.* defaults
+	Custom bool       `json:"custom"`
+	Usage  QuotaUsage `json:"usage"`
+}
+
+// Audit actions
+const (
+	AuditDataExported = "student.data_exported"
+	AuditErased       = "student.erased"
+	// AuditImpersonated is an impersonation token issued for the student
+	AuditImpersonated = "student.impersonated"
+	// AuditEmailDomainMigrated is the student's email moved to another
+	// domain by an admin
+	AuditEmailDomainMigrated = "student.email_domain_migrated"
+)
+
+// AuditEntry records a privacy-relevant action on a student (GDPR export,
+// erasure, an admin impersonating them or rewriting their email). Entries outlive the student row,
+// so they survive a purge.
+type AuditEntry struct {
+	ID        int64  `json:"id"`
+	StudentID int64  `json:"student_id"`
+	Action    string `json:"action"`
+	// Actor is the auth subject ("key:<id>" or "user:<sub>"); empty when auth is off
+	Actor string `json:"actor,omitempty"`
+	// Impersonator is the admin subject behind an impersonation token the
+	// action was taken with; empty otherwise
+	Impersonator string          `json:"impersonator,omitempty"`
+	Detail       json.RawMessage `json:"detail,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// Health states
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// Health is what the storage health monitor last saw, served by /readyz.
+type Health struct {
+	// Status is degraded once failure_threshold pings in a row failed
+	Status string `json:"status"`
+	// Failures counts the failed pings since the last success
+	Failures int `json:"failures"`
+	// Reconnects counts the reconnect attempts since the process started
+	Reconnects    int        `json:"reconnects"`
+	LastError     string     `json:"last_error,omitempty"`
+	CheckedAt     *time.Time `json:"checked_at,omitempty"`
+	DegradedSince *time.Time `json:"degraded_since,omitempty"`
+}
+
+// PoolStats is a snapshot of a database/sql connection pool.
+type PoolStats struct {
+	// MaxOpen is the pool's limit (0: unlimited)
+	MaxOpen int `json:"max_open"`
+	Open    int `json:"open"`
+	InUse   int `json:"in_use"`
+	Idle    int `json:"idle"`
+	// WaitCount and WaitDuration add up the waits for a free connection
+	WaitCount         int64  `json:"wait_count"`
+	WaitDuration      string `json:"wait_duration"`
+	MaxIdleClosed     int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed int64  `json:"max_lifetime_closed"`
+}
+
+// QueryPlan is how the database runs one query, as EXPLAIN shows it.
+type QueryPlan struct {
+	Backend string `json:"backend"`
+	// Explain is the EXPLAIN statement Query was prefixed with
+	Explain string `json:"explain"`
+	Query   string `json:"query"`
+	// Plan has one line per plan node, indented by depth
+	Plan []string `json:"plan"`
+}
+
+// IndexCheck is one index the student queries rely on.
+type IndexCheck struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+	// For names the queries it serves
+	For string `json:"for"`
+	// Index is the index found serving it, empty when Missing
+	Index   string `json:"index,omitempty"`
+	Missing bool   `json:"missing"`
+}
+
+// IndexReport is the result of checking a backend's indexes.
+type IndexReport struct {
+	Backend string       `json:"backend"`
+	Missing int          `json:"missing"`
+	Indexes []IndexCheck `json:"indexes"`
+}
+
+// Runtime is a snapshot of the process for production debugging.
+type Runtime struct {
+	GoVersion  string        `json:"go_version"`
+	Goroutines int           `json:"goroutines"`
+	CPUs       int           `json:"cpus"`
+	GOMAXPROCS int           `json:"gomaxprocs"`
+	StartedAt  time.Time     `json:"started_at"`
+	Uptime     string        `json:"uptime"`
+	Memory     RuntimeMemory `json:"memory"`
+	// Pools is keyed "primary", then by replica host; empty without SQL
+	Pools map[string]PoolStats `json:"db_pools"`
+}
+
+// RuntimeMemory is the useful part of runtime.MemStats, in bytes.
+type RuntimeMemory struct {
+	Alloc       uint64     `json:"alloc"`
+	TotalAlloc  uint64     `json:"total_alloc"`
+	Sys         uint64     `json:"sys"`
+	HeapAlloc   uint64     `json:"heap_alloc"`
+	HeapInuse   uint64     `json:"heap_inuse"`
+	HeapObjects uint64     `json:"heap_objects"`
+	StackInuse  uint64     `json:"stack_inuse"`
+	NumGC       uint32     `json:"num_gc"`
+	PauseTotal  string     `json:"gc_pause_total"`
+	LastGC      *time.Time `json:"last_gc,omitempty"`
+}
+
+// Version identifies the running binary and the schema it works on.
+type Version struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	// Dirty means the binary was built from uncommitted changes
+	Dirty     bool   `json:"dirty,omitempty"`
+	GoVersion string `json:"go_version"`
+	// Storage is the db_type in use
+	Storage string `json:"storage"`
+	// SchemaVersion is the last applied migration; 0 for the memory backend
+	SchemaVersion int `json:"schema_version,omitempty"`
+}
+
+// Route is one registered route and what it declares to the middleware
+type Route struct {
+	Pattern string `json:"pattern"`
+	// Public routes need no credentials
+	Public bool `json:"public"`
+	// Scope the credentials must grant; empty is read or write by method
+	Scope string `json:"scope,omitempty"`
+	// Quota is the charge class; empty is read or write by method
+	Quota   string `json:"quota,omitempty"`
+	Timeout string `json:"timeout,omitempty"`
 }
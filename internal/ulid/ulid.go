@@ -0,0 +1,74 @@
+// Package ulid makes ULIDs (https://github.com/ulid/spec): 26-character,
+// case-insensitive IDs of a 48-bit millisecond timestamp and 80 random bits
+// in Crockford base32. They sort by creation time like the numeric ids but
+// can't be guessed or counted through, so they are what external clients
+// get to see.
+package ulid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// Length of a ULID in characters
+const Length = 26
+
+// Crockford's base32 alphabet: no I, L, O or U
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// -------------------------------------------------------------
+// New() → ULID for the current time
+// -------------------------------------------------------------
+func New() string {
+	return At(time.Now())
+}
+
+// -------------------------------------------------------------
+// At() → ULID whose timestamp part is t
+// -------------------------------------------------------------
+// Used to backfill rows created before public ids, so they keep sorting
+// by created_at.
+func At(t time.Time) string {
+	var id [16]byte
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(id[:6], ms[2:])
+	// crypto/rand never fails (it panics instead)
+	_, _ = rand.Read(id[6:])
+
+	// 128 bits as 26 five-bit groups, the first one holding only 3
+	var out [Length]byte
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := Length - 1; i >= 0; i-- {
+		out[i] = alphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// -------------------------------------------------------------
+// Valid() → Whether s is a well-formed ULID (any case)
+// -------------------------------------------------------------
+func Valid(s string) bool {
+	// the first character holds 3 bits: 7 at most
+	if len(s) != Length || s[0] > '7' {
+		return false
+	}
+	for _, c := range Normalize(s) {
+		if !strings.ContainsRune(alphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// -------------------------------------------------------------
+// Normalize() → s in the canonical upper case, as stored
+// -------------------------------------------------------------
+func Normalize(s string) string {
+	return strings.ToUpper(s)
+}
@@ -0,0 +1,72 @@
+package ulid
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAt(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		// the first 10 characters are the millisecond timestamp
+		wantTime string
+	}{
+		{name: "epoch", t: time.UnixMilli(0), wantTime: "0000000000"},
+		{name: "one millisecond", t: time.UnixMilli(1), wantTime: "0000000001"},
+		{name: "spec example", t: time.UnixMilli(1469918176385), wantTime: "01ARYZ6S41"},
+		{name: "last millisecond", t: time.UnixMilli(1<<48 - 1), wantTime: "7ZZZZZZZZZ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := At(tt.t)
+			if !strings.HasPrefix(id, tt.wantTime) {
+				t.Errorf("At(%d) = %s, want timestamp %s", tt.t.UnixMilli(), id, tt.wantTime)
+			}
+			if !Valid(id) {
+				t.Errorf("At(%d) = %s is not valid", tt.t.UnixMilli(), id)
+			}
+		})
+	}
+}
+
+func TestNewSortsAndDiffers(t *testing.T) {
+	earlier := At(time.Now().Add(-time.Millisecond))
+	a, b := New(), New()
+	if a == b {
+		t.Fatalf("two ULIDs are equal: %s", a)
+	}
+	if earlier >= a || earlier >= b {
+		t.Errorf("%s (earlier) doesn't sort before %s and %s", earlier, a, b)
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: "01ARZ3NDEKTSV4RRFFQ69G5FAV", want: true},
+		{s: "01arz3ndektsv4rrffq69g5fav", want: true},
+		{s: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", want: true},
+		{s: "8ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{s: "01ARZ3NDEKTSV4RRFFQ69G5FA"},
+		{s: "01ARZ3NDEKTSV4RRFFQ69G5FAVX"},
+		{s: "01ARZ3NDEKTSV4RRFFQ69G5FAU"},
+		{s: "01ARZ3NDEKTSV4RRFFQ69G5FA-"},
+		{s: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := Valid(tt.s); got != tt.want {
+				t.Errorf("Valid(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+	if got := Normalize("01arz3ndektsv4rrffq69g5fav"); got != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("Normalize = %s", got)
+	}
+}
@@ -0,0 +1,113 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// drainTimeout caps how long Drain waits for accepted connections to send
+// their request; a client that connects and stays silent isn't waited for
+const drainTimeout = 2 * time.Second
+
+// served is one server started through Serve
+type served struct {
+	ln   net.Listener
+	done chan struct{}
+
+	mu sync.Mutex
+	// fresh are connections accepted whose first request isn't read yet
+	fresh map[net.Conn]struct{}
+}
+
+func (s *served) track(c net.Conn, state http.ConnState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == http.StateNew {
+		s.fresh[c] = struct{}{}
+	} else {
+		delete(s.fresh, c)
+	}
+}
+
+func (s *served) pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.fresh)
+}
+
+// -------------------------------------------------------------
+// Serve() → srv.Serve(ln), for a server Drain can hand over cleanly
+// -------------------------------------------------------------
+// http.Server.Shutdown drops a connection it accepted but hasn't read a
+// request from yet; Drain waits those out first. Returns
+// http.ErrServerClosed once drained, like Shutdown would.
+func (u *Upgrader) Serve(srv *http.Server, ln net.Listener) error {
+	s := &served{ln: ln, done: make(chan struct{}), fresh: map[net.Conn]struct{}{}}
+	next := srv.ConnState
+	srv.ConnState = func(c net.Conn, state http.ConnState) {
+		s.track(c, state)
+		if next != nil {
+			next(c, state)
+		}
+	}
+
+	u.mu.Lock()
+	u.served = append(u.served, s)
+	u.mu.Unlock()
+
+	err := srv.Serve(ln)
+	close(s.done)
+	if errors.Is(err, net.ErrClosed) && u.draining.Load() {
+		return http.ErrServerClosed
+	}
+	return err
+}
+
+// -------------------------------------------------------------
+// Drain() → Stop accepting, then let accepted connections send their request
+// -------------------------------------------------------------
+// Call before Shutdown on the servers. After an upgrade the sockets stay
+// open in the new process, which accepts everything that arrives from now
+// on, so no client is turned away.
+func (u *Upgrader) Drain(ctx context.Context) {
+	u.draining.Store(true)
+	u.mu.Lock()
+	servers := u.served
+	u.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, drainTimeout)
+	defer cancel()
+
+	handedOver := false
+	select {
+	case <-u.exit:
+		handedOver = true
+	default:
+	}
+	for _, s := range servers {
+		// the new process listens on the same socket file
+		if ul, ok := s.ln.(*net.UnixListener); ok && handedOver {
+			ul.SetUnlinkOnClose(false)
+		}
+		s.ln.Close()
+	}
+	for _, s := range servers {
+		// Serve has returned, so every accepted connection is tracked
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return
+		}
+		for s.pending() > 0 {
+			select {
+			case <-time.After(10 * time.Millisecond):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Address schemes besides host:port
+const (
+	// SchemeUnix is a unix domain socket: unix:///run/student-api.sock
+	SchemeUnix = "unix://"
+	// SchemeSystemd is a socket passed by systemd socket activation:
+	// systemd:// for the only one, systemd://<FileDescriptorName> to pick
+	SchemeSystemd = "systemd://"
+)
+
+// -------------------------------------------------------------
+// ParseAddr() → Network and address of a listen address
+// -------------------------------------------------------------
+// host:port is TCP, unix://<path> a unix socket; systemd:// addresses have
+// no network of their own and come back as "systemd".
+func ParseAddr(addr string) (network, address string, err error) {
+	switch {
+	case strings.HasPrefix(addr, SchemeUnix):
+		path := strings.TrimPrefix(addr, SchemeUnix)
+		if path == "" {
+			return "", "", fmt.Errorf("%q: unix socket path is empty", addr)
+		}
+		return "unix", path, nil
+	case strings.HasPrefix(addr, SchemeSystemd):
+		return "systemd", strings.TrimPrefix(addr, SchemeSystemd), nil
+	case strings.Contains(addr, "://"):
+		return "", "", fmt.Errorf("%q: unknown scheme, use host:port, unix://<path> or systemd://[name]", addr)
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", "", fmt.Errorf("%q: %w", addr, err)
+	}
+	return "tcp", addr, nil
+}
+
+// listen opens a new socket for addr
+func (u *Upgrader) listen(addr string, socketMode fs.FileMode) (net.Listener, error) {
+	network, address, err := ParseAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	switch network {
+	case "systemd":
+		return u.activated(address)
+	case "unix":
+		if err := removeStaleSocket(address); err != nil {
+			return nil, err
+		}
+		ln, err := net.Listen("unix", address)
+		if err != nil {
+			return nil, err
+		}
+		if socketMode != 0 {
+			if err := os.Chmod(address, socketMode); err != nil {
+				ln.Close()
+				return nil, fmt.Errorf("chmod %s: %w", address, err)
+			}
+		}
+		return ln, nil
+	}
+	return net.Listen(network, address)
+}
+
+// removeStaleSocket deletes a socket file left behind by a process that
+// died without closing it; a socket something still listens on is kept
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%s: another process is listening on it", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.Remove(path)
+}
+
+// -------------------------------------------------------------
+// systemd socket activation
+// -------------------------------------------------------------
+
+// systemd passes sockets from fd 3 on, LISTEN_FDS of them, for LISTEN_PID
+const listenFDsStart = 3
+
+// takeActivated reads the sockets systemd passed to this process, by
+// FileDescriptorName (or their position when unnamed), and clears the
+// variables so nothing this process starts mistakes them for its own
+func takeActivated() map[string]*os.File {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	files := make(map[string]*os.File, n)
+	for i := range n {
+		name := strconv.Itoa(i)
+		if i < len(names) && names[i] != "" && names[i] != "unknown" {
+			name = names[i]
+		}
+		files[name] = os.NewFile(uintptr(listenFDsStart+i), "systemd socket "+name)
+	}
+	return files
+}
+
+// activated is the systemd socket called name, or the only one when name
+// is empty
+func (u *Upgrader) activated(name string) (net.Listener, error) {
+	if len(u.systemd) == 0 {
+		return nil, errors.New("no sockets passed by systemd (LISTEN_FDS); is the .socket unit enabled?")
+	}
+	if name == "" {
+		if len(u.systemd) > 1 {
+			return nil, fmt.Errorf("systemd passed %d sockets, pick one with systemd://<FileDescriptorName>", len(u.systemd))
+		}
+		for only := range u.systemd {
+			name = only
+		}
+	}
+	f, ok := u.systemd[name]
+	if !ok {
+		return nil, fmt.Errorf("systemd passed no socket named %q", name)
+	}
+	delete(u.systemd, name)
+	defer f.Close()
+	return net.FileListener(f)
+}
@@ -0,0 +1,301 @@
+// Package upgrade restarts the server in place without dropping requests.
+// On SIGUSR2 the binary is started again (from its path on disk, so a
+// freshly deployed build) and handed the listening sockets. The old process
+// keeps serving until the new one reports it is up, then stops accepting
+// and lets its in-flight requests finish. If the new process fails to come
+// up, the old one carries on as if nothing happened.
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+)
+
+// Environment of a process started by an upgrade
+const (
+	// envListeners lists the inherited addresses, comma separated; the
+	// first is file descriptor 3, the next 4, ...
+	envListeners = "STUDENT_API_LISTENERS"
+	// envReadyFD is the pipe the new process writes to once it serves
+	envReadyFD = "STUDENT_API_READY_FD"
+)
+
+// readyTimeout is how long the old process waits for the new one
+const readyTimeout = time.Minute
+
+// errNotSocket marks a listener without a socket to hand over
+var errNotSocket = errors.New("not backed by a socket")
+
+// Upgrader holds the listening sockets. Safe for concurrent use.
+type Upgrader struct {
+	// binary is the executable started on upgrade
+	binary     string
+	pidFile    string
+	socketMode fs.FileMode
+
+	mu sync.Mutex
+	// inherited are the sockets passed by the previous process, by address
+	inherited map[string]*os.File
+	// systemd are the sockets of systemd socket activation, by name
+	systemd map[string]*os.File
+	// listeners are the sockets handed on to the next process
+	listeners map[string]net.Listener
+	order     []string
+	// ready tells the previous process this one serves
+	ready     *os.File
+	upgrading bool
+	// served are the servers started through Serve, for Drain
+	served   []*served
+	draining atomic.Bool
+
+	exit     chan struct{}
+	exitOnce sync.Once
+}
+
+// -------------------------------------------------------------
+// New() → Upgrader with the sockets the previous process passed, if any
+// -------------------------------------------------------------
+// http_server.pid_file, when set, gets this process' pid once Ready runs,
+// so scripts and supervisors can find the process to signal after an
+// upgrade. Sockets passed by systemd socket activation are taken too.
+func New(cfg config.HttpServer) (*Upgrader, error) {
+	binary, err := exec.LookPath(os.Args[0])
+	if err == nil {
+		binary, err = filepath.Abs(binary)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locate own binary: %w", err)
+	}
+	var socketMode fs.FileMode
+	if cfg.SocketMode != "" {
+		mode, err := strconv.ParseUint(cfg.SocketMode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("http_server.socket_mode: %w", err)
+		}
+		socketMode = fs.FileMode(mode)
+	}
+
+	u := &Upgrader{
+		binary:     binary,
+		pidFile:    cfg.PIDFile,
+		socketMode: socketMode,
+		inherited:  map[string]*os.File{},
+		systemd:    takeActivated(),
+		listeners:  map[string]net.Listener{},
+		exit:       make(chan struct{}),
+	}
+	if addrs := os.Getenv(envListeners); addrs != "" {
+		for i, addr := range strings.Split(addrs, ",") {
+			u.inherited[addr] = os.NewFile(uintptr(3+i), "listener "+addr)
+		}
+	}
+	if fd, err := strconv.Atoi(os.Getenv(envReadyFD)); err == nil {
+		u.ready = os.NewFile(uintptr(fd), "upgrade ready")
+	}
+	// not for whatever this process starts itself
+	os.Unsetenv(envListeners)
+	os.Unsetenv(envReadyFD)
+	return u, nil
+}
+
+// -------------------------------------------------------------
+// Listen() → Listener on addr: the inherited one, or a new socket
+// -------------------------------------------------------------
+// addr is host:port, unix://<path> or systemd://[name] (see ParseAddr).
+func (u *Upgrader) Listen(addr string) (net.Listener, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var ln net.Listener
+	var err error
+	if f, ok := u.inherited[addr]; ok {
+		delete(u.inherited, addr)
+		ln, err = net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("inherited listener %s: %w", addr, err)
+		}
+		slog.Info("🔁 Listening on inherited socket", slog.String("address", addr))
+	} else if ln, err = u.listen(addr, u.socketMode); err != nil {
+		return nil, err
+	}
+	// our unix socket files go when we close them, unless handed over
+	if ul, ok := ln.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(strings.HasPrefix(addr, SchemeUnix))
+	}
+
+	u.listeners[addr] = ln
+	u.order = append(u.order, addr)
+	return ln, nil
+}
+
+// -------------------------------------------------------------
+// Ready() → This process serves: release the previous one, write the pid file
+// -------------------------------------------------------------
+// Inherited sockets nothing asked for (an address dropped from the
+// config) are closed.
+func (u *Upgrader) Ready() error {
+	u.mu.Lock()
+	for addr, f := range u.inherited {
+		slog.Warn("⚠️ Closing inherited socket no longer configured", slog.String("address", addr))
+		f.Close()
+	}
+	u.inherited = map[string]*os.File{}
+	for name, f := range u.systemd {
+		slog.Warn("⚠️ Closing systemd socket no address asked for", slog.String("name", name))
+		f.Close()
+	}
+	u.systemd = nil
+	ready := u.ready
+	u.ready = nil
+	u.mu.Unlock()
+
+	if u.pidFile != "" {
+		if err := os.WriteFile(u.pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+			return fmt.Errorf("write pid file: %w", err)
+		}
+	}
+	if ready != nil {
+		defer ready.Close()
+		if _, err := ready.Write([]byte{1}); err != nil {
+			return fmt.Errorf("signal previous process: %w", err)
+		}
+	}
+	return nil
+}
+
+// Exit is closed once a new process took over; shut down as for SIGTERM
+func (u *Upgrader) Exit() <-chan struct{} {
+	return u.exit
+}
+
+// Stop removes the pid file unless a newer process already wrote its own
+func (u *Upgrader) Stop() {
+	if u.pidFile == "" {
+		return
+	}
+	if pid, err := os.ReadFile(u.pidFile); err == nil && strings.TrimSpace(string(pid)) == strconv.Itoa(os.Getpid()) {
+		os.Remove(u.pidFile)
+	}
+}
+
+// -------------------------------------------------------------
+// Watch() → Upgrade on every upgrade signal until ctx is cancelled
+// -------------------------------------------------------------
+// The signal is SIGUSR2; platforms without it never upgrade.
+func (u *Upgrader) Watch(ctx context.Context) {
+	if len(upgradeSignals) == 0 {
+		return
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, upgradeSignals...)
+
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-u.exit:
+				return
+			case <-sig:
+				if err := u.Upgrade(); err != nil {
+					slog.Error("❌ Upgrade failed, this process keeps serving", slog.String("error", err.Error()))
+				}
+			}
+		}
+	}()
+}
+
+// -------------------------------------------------------------
+// Upgrade() → Start the binary again on our sockets and wait until it serves
+// -------------------------------------------------------------
+// On success Exit is closed. The new process gets the same arguments and
+// environment, so it reads the same config file.
+func (u *Upgrader) Upgrade() error {
+	u.mu.Lock()
+	if u.upgrading {
+		u.mu.Unlock()
+		return errors.New("an upgrade is already running")
+	}
+	u.upgrading = true
+	files := make([]*os.File, 0, len(u.order)+1)
+	for _, addr := range u.order {
+		f, err := socketFile(u.listeners[addr])
+		if err != nil {
+			u.mu.Unlock()
+			closeAll(files)
+			u.done()
+			return fmt.Errorf("listener %s: %w", addr, err)
+		}
+		files = append(files, f)
+	}
+	addrs := strings.Join(u.order, ",")
+	u.mu.Unlock()
+	defer u.done()
+	defer closeAll(files)
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyR.Close()
+
+	slog.Info("🔁 Upgrading: starting new process", slog.String("binary", u.binary), slog.String("listeners", addrs))
+	cmd := exec.Command(u.binary, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(os.Environ(),
+		envListeners+"="+addrs,
+		envReadyFD+"="+strconv.Itoa(3+len(files)),
+	)
+	err = cmd.Start()
+	readyW.Close()
+	if err != nil {
+		return fmt.Errorf("start %s: %w", u.binary, err)
+	}
+
+	// 📨 One byte once the child serves; EOF without it means it died
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	readyR.SetReadDeadline(time.Now().Add(readyTimeout))
+	if n, err := readyR.Read(make([]byte, 1)); n != 1 {
+		if os.IsTimeout(err) {
+			cmd.Process.Kill()
+			return fmt.Errorf("new process not ready within %s", readyTimeout)
+		}
+		return fmt.Errorf("new process exited before it was ready: %v", <-exited)
+	}
+
+	slog.Info("✅ Upgrade done, handing over", slog.Int("new_pid", cmd.Process.Pid))
+	u.exitOnce.Do(func() { close(u.exit) })
+	return nil
+}
+
+// done clears the upgrading mark
+func (u *Upgrader) done() {
+	u.mu.Lock()
+	u.upgrading = false
+	u.mu.Unlock()
+}
+
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
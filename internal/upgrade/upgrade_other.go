@@ -0,0 +1,17 @@
+//go:build !unix
+
+package upgrade
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// upgradeSignals is empty where SIGUSR2 doesn't exist
+var upgradeSignals []os.Signal
+
+// socketFile: passing sockets to a child needs a unix
+func socketFile(net.Listener) (*os.File, error) {
+	return nil, errors.New("not supported on this platform")
+}
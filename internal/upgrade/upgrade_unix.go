@@ -0,0 +1,38 @@
+//go:build unix
+
+package upgrade
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// upgradeSignals make Watch start an upgrade
+var upgradeSignals = []os.Signal{syscall.SIGUSR2}
+
+// socketFile duplicates the socket of ln for a child process. Unlike
+// TCPListener.File it leaves the socket non-blocking, which this process
+// still needs if the child never comes up.
+func socketFile(ln net.Listener) (*os.File, error) {
+	sc, ok := ln.(syscall.Conn)
+	if !ok {
+		return nil, errNotSocket
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var dup int
+	var dupErr error
+	err = raw.Control(func(fd uintptr) {
+		dup, dupErr = syscall.Dup(int(fd))
+	})
+	if err == nil {
+		err = dupErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(dup), ln.Addr().String()), nil
+}
@@ -0,0 +1,103 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/http/pattern"
+)
+
+// CacheRule sets the Cache-Control value for one route pattern.
+type CacheRule struct {
+	// Route is a ServeMux pattern: "GET /api/students", "GET /api/student/{id}"
+	Route string
+	Value string
+}
+
+// CachePolicy decides the Cache-Control header of each response. Rules
+// are route patterns, matched as the router matches them.
+type CachePolicy struct {
+	routes *pattern.Set
+	values map[string]string
+	reads  string
+	writes string
+	vary   []string
+}
+
+// -------------------------------------------------------------
+// NewCachePolicy() → Policy over rules; an invalid or clashing route is an error
+// -------------------------------------------------------------
+// reads applies to GET/HEAD without a rule, writes to other methods and to
+// errors ("" sends nothing). Cacheable responses vary on the vary headers
+// (credentials, tenant), so shared caches never mix up callers.
+func NewCachePolicy(reads, writes string, rules []CacheRule, vary ...string) (*CachePolicy, error) {
+	values := make(map[string]string, len(rules))
+	routes := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		values[rule.Route] = rule.Value
+		routes = append(routes, rule.Route)
+	}
+	set, err := pattern.NewSet(routes...)
+	if err != nil {
+		return nil, fmt.Errorf("cache rule: %w", err)
+	}
+	return &CachePolicy{routes: set, values: values, reads: reads, writes: writes, vary: vary}, nil
+}
+
+// For returns the Cache-Control value for a response to r with status.
+// Only successful (2xx) and 304 responses use a rule.
+func (p *CachePolicy) For(r *http.Request, status int) string {
+	if status >= http.StatusBadRequest || (status >= http.StatusMultipleChoices && status != http.StatusNotModified) {
+		return p.writes
+	}
+	if route, ok := p.routes.Match(r); ok {
+		return p.values[route]
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return p.reads
+	}
+	return p.writes
+}
+
+// -------------------------------------------------------------
+// Apply() → Cache-Control (+ Expires, Vary) unless the handler set its own
+// -------------------------------------------------------------
+func (p *CachePolicy) Apply(h http.Header, r *http.Request, status int) {
+	if h.Get("Cache-Control") != "" {
+		return
+	}
+	value := p.For(r, status)
+	if value == "" {
+		return
+	}
+	h.Set("Cache-Control", value)
+	if strings.Contains(value, "no-store") {
+		return
+	}
+
+	// Expires for HTTP/1.0 caches that don't read max-age
+	if maxAge, ok := directive(value, "max-age"); ok {
+		if seconds, err := strconv.Atoi(maxAge); err == nil {
+			h.Set("Expires", time.Now().Add(time.Duration(seconds)*time.Second).UTC().Format(http.TimeFormat))
+		}
+	}
+	for _, header := range p.vary {
+		if header != "" {
+			h.Add("Vary", header)
+		}
+	}
+}
+
+// directive returns the value of name=value in a Cache-Control header
+func directive(header, name string) (string, bool) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,238 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// Encoder renders a response body in one media type. Bodies are built for
+// JSON, so encoders get the JSON form of the value: the same keys, and
+// omitempty fields left out.
+type Encoder interface {
+	// ContentType is sent as the response's Content-Type
+	ContentType() string
+	Encode(w io.Writer, v any) error
+}
+
+// mediaEncoder is an Encoder and the Accept media type that selects it
+type mediaEncoder struct {
+	mediaType string
+	encoder   Encoder
+}
+
+// encoders are tried in order; JSON comes first, so it wins ties
+var encoders = []mediaEncoder{
+	{"application/json", JSON},
+	{"application/xml", XML},
+	{"text/xml", XML},
+	{"application/msgpack", MessagePack},
+	{"application/x-msgpack", MessagePack},
+	{"application/vnd.msgpack", MessagePack},
+}
+
+// -------------------------------------------------------------
+// RegisterEncoder() → Serve mediaType with enc (call before serving)
+// -------------------------------------------------------------
+// Registering a media type again replaces its encoder.
+func RegisterEncoder(mediaType string, enc Encoder) {
+	for i, e := range encoders {
+		if e.mediaType == mediaType {
+			encoders[i].encoder = enc
+			return
+		}
+	}
+	encoders = append(encoders, mediaEncoder{mediaType, enc})
+}
+
+// -------------------------------------------------------------
+// Negotiate() → The encoder the Accept header prefers
+// -------------------------------------------------------------
+// Highest q wins, then the type listed first, then JSON. No header, */*
+// or only types nobody encodes get JSON: clients that never asked for a
+// format keep getting what they always got.
+func Negotiate(accept string) Encoder {
+	type rank struct {
+		q   float64
+		pos int
+	}
+	var (
+		best     Encoder = JSON
+		bestRank         = rank{q: 0, pos: 1 << 30}
+	)
+	ranges := parseAccept(accept)
+	for _, e := range encoders {
+		// the most specific matching range sets q (RFC 9110 12.5.1)
+		r, specificity := rank{q: -1}, -1
+		for pos, ar := range ranges {
+			if ar.matches(e.mediaType) && ar.specificity > specificity {
+				r, specificity = rank{q: ar.q, pos: pos}, ar.specificity
+			}
+		}
+		if r.q <= 0 {
+			continue
+		}
+		if r.q > bestRank.q || (r.q == bestRank.q && r.pos < bestRank.pos) {
+			best, bestRank = e.encoder, r
+		}
+	}
+	return best
+}
+
+// NDJSON is newline-delimited JSON, one value per line. It is streamed by
+// the handlers that support it rather than encoded from a finished body.
+const NDJSON = "application/x-ndjson"
+
+// -------------------------------------------------------------
+// WantsNDJSON() → Whether the Accept header prefers NDJSON
+// -------------------------------------------------------------
+// Only an explicit application/x-ndjson counts, and only when no other
+// type is listed with a higher q; */* never selects it.
+func WantsNDJSON(accept string) bool {
+	q, others := 0.0, 0.0
+	for _, ar := range parseAccept(accept) {
+		if ar.specificity == 2 && ar.mainType+"/"+ar.subType == NDJSON {
+			q = max(q, ar.q)
+		} else {
+			others = max(others, ar.q)
+		}
+	}
+	return q > 0 && q >= others
+}
+
+// acceptRange is one entry of an Accept header
+type acceptRange struct {
+	mainType, subType string
+	q                 float64
+	// specificity is 0 for */*, 1 for type/*, 2 for type/subtype
+	specificity int
+}
+
+func (ar acceptRange) matches(mediaType string) bool {
+	mainType, subType, _ := strings.Cut(mediaType, "/")
+	switch ar.specificity {
+	case 0:
+		return true
+	case 1:
+		return ar.mainType == mainType
+	default:
+		return ar.mainType == mainType && ar.subType == subType
+	}
+}
+
+// parseAccept reads "application/xml;q=0.9, */*;q=0.1"; malformed entries are skipped
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		mainType, subType, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+		ar := acceptRange{mainType: mainType, subType: subType, q: 1, specificity: 2}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			ar.q = q
+		}
+		switch {
+		case mainType == "*":
+			ar.specificity = 0
+		case subType == "*":
+			ar.specificity = 1
+		}
+		ranges = append(ranges, ar)
+	}
+	return ranges
+}
+
+// JSON is the API's native encoding
+var JSON Encoder = jsonEncoder{}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string { return "application/json" }
+
+func (jsonEncoder) Encode(w io.Writer, v any) error {
+	jb, err := encodeJSON(v)
+	if err != nil {
+		return err
+	}
+	defer jb.release()
+	_, err = w.Write(jb.buf.Bytes())
+	return err
+}
+
+// -------------------------------------------------------------
+// jsonTree() → v as it would be sent in JSON, keys kept in order
+// -------------------------------------------------------------
+// Objects become object, arrays []any, numbers json.Number; the rest are
+// string, bool or nil. A json.RawMessage is parsed as it is.
+func jsonTree(v any) (any, error) {
+	raw, ok := v.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	tree, err := decodeTree(dec)
+	if err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return tree, nil
+}
+
+// object is a JSON object with its keys in order
+type object []member
+
+type member struct {
+	key   string
+	value any
+}
+
+func decodeTree(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeTree(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key.(string), value})
+		}
+		_, err := dec.Token() // }
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := decodeTree(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token() // ]
+		return list, err
+	case json.Delim('}'), json.Delim(']'):
+		return nil, errors.New("unexpected end of container")
+	}
+	return tok, nil
+}
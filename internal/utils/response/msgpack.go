@@ -0,0 +1,134 @@
+package response
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// MessagePack renders the JSON form of a body as MessagePack
+// (https://msgpack.org): objects become maps, numbers the smallest int
+// that holds them, or a float64.
+var MessagePack Encoder = msgpackEncoder{}
+
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) ContentType() string { return "application/msgpack" }
+
+func (msgpackEncoder) Encode(w io.Writer, v any) error {
+	tree, err := jsonTree(v)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if err := writeMsgpack(bw, tree); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func writeMsgpack(w *bufio.Writer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		w.WriteByte(0xc0)
+	case bool:
+		if v {
+			w.WriteByte(0xc3)
+		} else {
+			w.WriteByte(0xc2)
+		}
+	case string:
+		writeMsgpackHeader(w, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		w.WriteString(v)
+	case json.Number:
+		return writeMsgpackNumber(w, v)
+	case []any:
+		writeMsgpackHeader(w, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := writeMsgpack(w, item); err != nil {
+				return err
+			}
+		}
+	case object:
+		writeMsgpackHeader(w, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, m := range v {
+			if err := writeMsgpack(w, m.key); err != nil {
+				return err
+			}
+			if err := writeMsgpack(w, m.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unexpected %T", v)
+	}
+	return nil
+}
+
+// writeMsgpackHeader writes the type and length of a string, array or
+// map: the fix form below fixMax, else the 8- (when the type has one),
+// 16- or 32-bit length form
+func writeMsgpackHeader(w *bufio.Writer, n int, fix byte, fixMax int, len8, len16, len32 byte) {
+	switch {
+	case n < fixMax:
+		w.WriteByte(fix | byte(n))
+	case len8 != 0 && n <= math.MaxUint8:
+		w.Write([]byte{len8, byte(n)})
+	case n <= math.MaxUint16:
+		w.WriteByte(len16)
+		w.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		w.WriteByte(len32)
+		w.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+func writeMsgpackNumber(w *bufio.Writer, n json.Number) error {
+	if i, err := n.Int64(); err == nil {
+		switch {
+		case i >= 0 && i <= math.MaxInt8:
+			w.WriteByte(byte(i)) // positive fixint
+		case i < 0 && i >= -32:
+			w.WriteByte(byte(int8(i))) // negative fixint
+		case i >= 0 && i <= math.MaxUint8:
+			w.Write([]byte{0xcc, byte(i)})
+		case i >= 0 && i <= math.MaxUint16:
+			w.WriteByte(0xcd)
+			w.Write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+		case i >= 0 && i <= math.MaxUint32:
+			w.WriteByte(0xce)
+			w.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		case i >= 0:
+			w.WriteByte(0xcf)
+			w.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+		case i >= math.MinInt8:
+			w.Write([]byte{0xd0, byte(int8(i))})
+		case i >= math.MinInt16:
+			w.WriteByte(0xd1)
+			w.Write(binary.BigEndian.AppendUint16(nil, uint16(int16(i))))
+		case i >= math.MinInt32:
+			w.WriteByte(0xd2)
+			w.Write(binary.BigEndian.AppendUint32(nil, uint32(int32(i))))
+		default:
+			w.WriteByte(0xd3)
+			w.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+		}
+		return nil
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		w.WriteByte(0xcf)
+		w.Write(binary.BigEndian.AppendUint64(nil, u))
+		return nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return fmt.Errorf("msgpack: %w", err)
+	}
+	w.WriteByte(0xcb)
+	w.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	return nil
+}
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// jsonBuffer is a buffer with a JSON encoder writing into it, reused
+// across responses so the hot endpoints don't grow a fresh one per request
+type jsonBuffer struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+// maxPooledBuffer caps the buffers kept for reuse: one big response (a
+// full unpaged list) shouldn't pin its memory in the pool
+const maxPooledBuffer = 1 << 20
+
+var jsonBuffers = sync.Pool{New: func() any {
+	jb := &jsonBuffer{}
+	jb.enc = json.NewEncoder(&jb.buf)
+	// keep & in link hrefs readable instead of \u0026
+	jb.enc.SetEscapeHTML(false)
+	return jb
+}}
+
+// -------------------------------------------------------------
+// encodeJSON() → Pooled buffer holding v as JSON, with a newline
+// -------------------------------------------------------------
+// Hand it back with release once its bytes are written.
+func encodeJSON(v any) (*jsonBuffer, error) {
+	jb := jsonBuffers.Get().(*jsonBuffer)
+	if err := jb.enc.Encode(v); err != nil {
+		// the encoder may be left mid-value; let the GC have it
+		return nil, err
+	}
+	return jb, nil
+}
+
+func (jb *jsonBuffer) release() {
+	if jb.buf.Cap() > maxPooledBuffer {
+		return
+	}
+	jb.buf.Reset()
+	jsonBuffers.Put(jb)
+}
@@ -1,52 +1,255 @@
 package response
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
-type Response struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+// ProblemJSON is the media type of error bodies (RFC 7807)
+const ProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body, the shape of every error
+// response:
+//
+//	{"type":"about:blank","title":"Bad Request","status":400,
+//	 "detail":"field Email is required",
+//	 "errors":[{"field":"Email","message":"field Email is required"}]}
+//
+// Extensions are written as members of the object next to the standard
+// ones; field errors go under "errors".
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
 }
 
-const (
-	StatusOk    = "OK"
-	StatusError = "ERROR"
-)
+// MarshalJSON writes the standard members first, in RFC order, then the
+// extensions sorted by key; an extension can't override a standard member
+func (p Problem) MarshalJSON() ([]byte, error) {
+	// a pooled encoder, released only on success (a failed one may be left
+	// mid-value); like WriteJson, it leaves & and < in details readable
+	jb := jsonBuffers.Get().(*jsonBuffer)
+	buf, enc := &jb.buf, jb.enc
+	buf.WriteByte('{')
+	member := func(key string, value any) error {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		enc.Encode(key)
+		buf.Truncate(buf.Len() - 1)
+		buf.WriteByte(':')
+		if err := enc.Encode(value); err != nil {
+			return fmt.Errorf("problem member %s: %w", key, err)
+		}
+		buf.Truncate(buf.Len() - 1)
+		return nil
+	}
+	standard := []struct {
+		key   string
+		value any
+		set   bool
+	}{
+		{"type", p.Type, true},
+		{"title", p.Title, true},
+		{"status", p.Status, true},
+		{"detail", p.Detail, p.Detail != ""},
+		{"instance", p.Instance, p.Instance != ""},
+	}
+	for _, m := range standard {
+		if !m.set {
+			continue
+		}
+		if err := member(m.key, m.value); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(p.Extensions)) {
+		switch key {
+		case "type", "title", "status", "detail", "instance":
+			continue
+		}
+		if err := member(key, p.Extensions[key]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	body := bytes.Clone(buf.Bytes())
+	jb.release()
+	return body, nil
+}
+
+// UnmarshalJSON reads the standard members and keeps the rest as
+// Extensions
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*p = Problem{}
+	for key, raw := range obj {
+		var err error
+		switch key {
+		case "type":
+			err = json.Unmarshal(raw, &p.Type)
+		case "title":
+			err = json.Unmarshal(raw, &p.Title)
+		case "status":
+			err = json.Unmarshal(raw, &p.Status)
+		case "detail":
+			err = json.Unmarshal(raw, &p.Detail)
+		case "instance":
+			err = json.Unmarshal(raw, &p.Instance)
+		default:
+			var value any
+			err = json.Unmarshal(raw, &value)
+			if p.Extensions == nil {
+				p.Extensions = map[string]any{}
+			}
+			p.Extensions[key] = value
+		}
+		if err != nil {
+			return fmt.Errorf("problem member %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// FieldError is one invalid field of a request, listed under a problem's
+// "errors" extension
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is an error made of every invalid field of a request.
+// GeneralError lists the field errors of any error that has them through
+// a FieldErrors() method, like this type and bind.Errors.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+	}
+	return strings.Join(msgs, ",")
+}
+
+func (e FieldErrors) FieldErrors() []FieldError {
+	return e
+}
 
 func WriteJson(w http.ResponseWriter, status int, data any) error {
-	w.Header().Set("Content-Type", "application/json")
+	contentType := "application/json"
+	if p, ok := data.(Problem); ok {
+		// the status and title come from the response code
+		p.Status = status
+		if p.Title == "" {
+			p.Title = http.StatusText(status)
+		}
+		data, contentType = p, ProblemJSON
+	}
+
+	// encoded before the headers go out, so a value that can't be
+	// marshaled answers 500 rather than a 200 cut short
+	jb, err := encodeJSON(data)
+	if err != nil {
+		slog.Error("Error encoding response", slog.String("error", err.Error()))
+		errEncoding.Write(w)
+		return err
+	}
+	defer jb.release()
+
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
-	return json.NewEncoder(w).Encode(data)
+	_, err = w.Write(jb.buf.Bytes())
+	return err
+}
 
+// StaticProblem is a problem body marshaled once, for errors answered
+// often and always in the same words: 404s, bad API keys, a backend
+// that is down. Writing one encodes nothing.
+type StaticProblem struct {
+	status int
+	body   []byte
 }
 
-func GeneralError(err error) Response {
-	return Response{
-		Status: StatusError,
-		Error:  err.Error(),
+var errEncoding = NewStaticProblem(http.StatusInternalServerError, "failed to encode response")
+
+// -------------------------------------------------------------
+// NewStaticProblem() → StaticProblem for status with detail
+// -------------------------------------------------------------
+// Declare it in a package var: it panics if the problem can't be
+// marshaled.
+func NewStaticProblem(status int, detail string) StaticProblem {
+	p := GeneralError(errors.New(detail))
+	p.Status, p.Title = status, http.StatusText(status)
+	jb, err := encodeJSON(p)
+	if err != nil {
+		panic(fmt.Sprintf("static problem %q: %v", detail, err))
 	}
+	defer jb.release()
+	return StaticProblem{status: status, body: bytes.Clone(jb.buf.Bytes())}
+}
+
+// String is the marshaled problem, for APIs that take a body as text
+// (http.TimeoutHandler)
+func (sp StaticProblem) String() string {
+	return string(sp.body)
 }
 
-func ValidationError(errs validator.ValidationErrors) Response {
+// Write sends the problem, as WriteJson would
+func (sp StaticProblem) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ProblemJSON)
+	w.WriteHeader(sp.status)
+	_, err := w.Write(sp.body)
+	return err
+}
 
-	var errMsg []string
+// -------------------------------------------------------------
+// GeneralError() → Problem with err as its detail
+// -------------------------------------------------------------
+// WriteJson fills in the status and title. Field errors carried by err
+// (see FieldErrors) are listed under "errors".
+func GeneralError(err error) Problem {
+	p := Problem{Type: "about:blank", Detail: err.Error()}
+	var fielded interface{ FieldErrors() []FieldError }
+	if errors.As(err, &fielded) {
+		p.Extensions = map[string]any{"errors": fielded.FieldErrors()}
+	}
+	return p
+}
+
+// -------------------------------------------------------------
+// ValidationError() → Problem listing each failed struct tag
+// -------------------------------------------------------------
+func ValidationError(errs validator.ValidationErrors) Problem {
+	return GeneralError(ValidationFields(errs))
+}
+
+// -------------------------------------------------------------
+// ValidationFields() → FieldErrors of failed struct tags
+// -------------------------------------------------------------
+func ValidationFields(errs validator.ValidationErrors) FieldErrors {
+	fields := make(FieldErrors, 0, len(errs))
 	for _, err := range errs {
 		switch err.ActualTag() {
 		case "required":
-			errMsg = append(errMsg, fmt.Sprintf("field %s is required", err.Field()))
+			fields = append(fields, FieldError{Field: err.Field(), Message: fmt.Sprintf("field %s is required", err.Field())})
 		default:
-			errMsg = append(errMsg, fmt.Sprintf("field %s is invalid", err.Field()))
+			fields = append(fields, FieldError{Field: err.Field(), Message: fmt.Sprintf("field %s is invalid", err.Field())})
 		}
 	}
-
-	return Response{
-		Status: StatusError,
-		Error:  strings.Join(errMsg, ","),
-	}
+	return fields
 }
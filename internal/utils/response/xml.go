@@ -0,0 +1,91 @@
+package response
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// XML renders the JSON form of a body as XML:
+//
+//	{"id":1,"guardians":[{"name":"Jane"}]}
+//	→ <response><id>1</id><guardians><item><name>Jane</name></item></guardians></response>
+//
+// Keys become elements (a key that isn't a valid element name becomes
+// <field name="...">), array entries become <item>, null an empty element.
+var XML Encoder = xmlEncoder{}
+
+type xmlEncoder struct{}
+
+func (xmlEncoder) ContentType() string { return "application/xml" }
+
+func (xmlEncoder) Encode(w io.Writer, v any) error {
+	tree, err := jsonTree(v)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	if err := writeXML(bw, "response", tree); err != nil {
+		return err
+	}
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+func writeXML(w *bufio.Writer, name string, v any) error {
+	open, closing := "<"+name+">", "</"+name+">"
+	if !isXMLName(name) {
+		var escaped strings.Builder
+		if err := xml.EscapeText(&escaped, []byte(name)); err != nil {
+			return err
+		}
+		open, closing = `<field name="`+escaped.String()+`">`, "</field>"
+	}
+
+	w.WriteString(open)
+	switch v := v.(type) {
+	case object:
+		for _, m := range v {
+			if err := writeXML(w, m.key, m.value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := writeXML(w, "item", item); err != nil {
+				return err
+			}
+		}
+	case string:
+		if err := xml.EscapeText(w, []byte(v)); err != nil {
+			return err
+		}
+	case json.Number:
+		w.WriteString(v.String())
+	case bool:
+		fmt.Fprint(w, v)
+	}
+	w.WriteString(closing)
+	return nil
+}
+
+// isXMLName reports whether s can be an element name as it is
+func isXMLName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+// Package validate holds the checks every student write goes through,
+// whether it arrives as an API body, a bulk change or a synced roster row.
+package validate
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/customfield"
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// ErrEmailRejected marks a student email the policy turns away: its
+// domain isn't allowed, is blocked or disposable, or can't receive mail.
+// Handlers answer it with 422.
+var ErrEmailRejected = errors.New("email rejected")
+
+// policy is the deployment's validation config; until Apply runs it is
+// the 1–100 age range with nothing else required
+var policy atomic.Pointer[config.Validation]
+
+var defaultPolicy = config.Validation{MinAge: 1, MaxAge: 100}
+
+//go:embed disposable.txt
+var disposableList string
+
+// disposable holds the domains in disposable.txt
+var disposable = parseDomains(disposableList)
+
+// -------------------------------------------------------------
+// Apply() → Hold later student writes to cfg; safe to call again on reload
+// -------------------------------------------------------------
+func Apply(cfg config.Validation) error {
+	cfg.EmailDomains = lowerDomains(cfg.EmailDomains)
+	cfg.BlockedEmailDomains = lowerDomains(cfg.BlockedEmailDomains)
+	policy.Store(&cfg)
+	return nil
+}
+
+// current is the policy in force
+func current() config.Validation {
+	if p := policy.Load(); p != nil {
+		return *p
+	}
+	return defaultPolicy
+}
+
+// -------------------------------------------------------------
+// Student() → Check a student about to be stored
+// -------------------------------------------------------------
+// Runs the struct tags in `types.Student`, then the deployment's policy:
+// the age derived from date_of_birth has to land in [min_age, max_age],
+// the phone may be required and the email's domain has to pass the allow,
+// block and disposable lists (ErrEmailRejected otherwise). Last it checks
+// the custom values against defs (cleaning them up in place). The error
+// reads as a 400 (422 for ErrEmailRejected) message.
+func Student(defs []types.CustomField, student *types.Student) error {
+	if err := validator.New().Struct(student); err != nil {
+		return response.ValidationFields(err.(validator.ValidationErrors))
+	}
+
+	p := current()
+	student.DeriveAge(time.Now())
+	if student.Age < p.MinAge || student.Age > p.MaxAge {
+		return fmt.Errorf("date_of_birth %s gives age %d, want %d to %d", student.DateOfBirth, student.Age, p.MinAge, p.MaxAge)
+	}
+	if p.RequirePhone && student.Phone == "" {
+		return errors.New("phone is required")
+	}
+	if err := emailDomain(p, student.Email); err != nil {
+		return err
+	}
+
+	var err error
+	student.Custom, err = customfield.Check(defs, student.Custom)
+	return err
+}
+
+// -------------------------------------------------------------
+// EmailMX() → Check a new student's email domain can receive mail
+// -------------------------------------------------------------
+// Only with validation.check_email_mx. A domain DNS says doesn't exist,
+// has neither MX nor address records, or publishes a null MX (RFC 7505)
+// is ErrEmailRejected. A lookup that fails otherwise or runs past
+// mx_timeout is logged and lets the email through, so a DNS outage doesn't
+// stop enrolment.
+func EmailMX(ctx context.Context, email string) error {
+	p := current()
+	if !p.CheckEmailMX {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.MXTimeout)
+	defer cancel()
+	domain := domainOf(email)
+
+	records, err := net.DefaultResolver.LookupMX(ctx, domain)
+	if err == nil && len(records) == 1 && records[0].Host == "." {
+		return fmt.Errorf("%w: %s does not accept mail (null MX)", ErrEmailRejected, email)
+	}
+	if err == nil && len(records) > 0 {
+		return nil
+	}
+	if err != nil && !isNotFound(err) {
+		slog.Warn("📭 MX lookup failed, letting the email through", slog.String("domain", domain), slog.String("error", err.Error()))
+		return nil
+	}
+
+	// no MX: mail goes to the domain's own address (RFC 5321 §5.1)
+	if _, err := net.DefaultResolver.LookupHost(ctx, domain); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("%w: %s has no mail servers", ErrEmailRejected, email)
+		}
+		slog.Warn("📭 MX lookup failed, letting the email through", slog.String("domain", domain), slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// CheckEmailDomain checks email against the allow, block and disposable
+// lists of the policy in force, as Student does (ErrEmailRejected)
+func CheckEmailDomain(email string) error {
+	return emailDomain(current(), email)
+}
+
+// emailDomain checks email's domain against the allow, block and
+// disposable lists of p
+func emailDomain(p config.Validation, email string) error {
+	domain := domainOf(email)
+	if len(p.EmailDomains) > 0 && !onDomain(domain, func(d string) bool { return slices.Contains(p.EmailDomains, d) }) {
+		return fmt.Errorf("%w: %s is not on an allowed domain (%s)", ErrEmailRejected, email, strings.Join(p.EmailDomains, ", "))
+	}
+	if onDomain(domain, func(d string) bool { return slices.Contains(p.BlockedEmailDomains, d) }) {
+		return fmt.Errorf("%w: %s is on a blocked domain", ErrEmailRejected, email)
+	}
+	if p.BlockDisposableEmails && onDomain(domain, func(d string) bool { return disposable[d] }) {
+		return fmt.Errorf("%w: %s is on a disposable email domain", ErrEmailRejected, email)
+	}
+	return nil
+}
+
+// onDomain reports whether listed holds domain or one of its parents
+func onDomain(domain string, listed func(string) bool) bool {
+	for {
+		if listed(domain) {
+			return true
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			return false
+		}
+		domain = parent
+	}
+}
+
+// domainOf is the lower-cased part of email after its last @
+func domainOf(email string) string {
+	return strings.ToLower(email[strings.LastIndexByte(email, '@')+1:])
+}
+
+func lowerDomains(domains []string) []string {
+	lowered := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		lowered = append(lowered, strings.ToLower(strings.TrimSpace(domain)))
+	}
+	return lowered
+}
+
+// parseDomains reads one domain per line, skipping blanks and # comments
+func parseDomains(list string) map[string]bool {
+	domains := make(map[string]bool)
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			domains[strings.ToLower(line)] = true
+		}
+	}
+	return domains
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
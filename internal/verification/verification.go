@@ -0,0 +1,94 @@
+// Package verification emails students a one-time link proving they own
+// their address, and marks them verified when they follow it.
+package verification
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/apikey"
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/notify"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// ErrUnsupported means the storage backend keeps no verification tokens
+var ErrUnsupported = errors.New("email verification not supported by this storage backend")
+
+// Verifier sends verification links and checks them.
+// A nil *Verifier (verification disabled) sends nothing.
+type Verifier struct {
+	ttl      time.Duration
+	link     string
+	notifier *notify.Notifier
+}
+
+// -------------------------------------------------------------
+// New() → Verifier, or nil when verification or email is disabled
+// -------------------------------------------------------------
+// baseURL is http_server.base_url; links point at <baseURL>/api/verify.
+func New(cfg config.Verification, baseURL string, notifier *notify.Notifier) *Verifier {
+	if !cfg.Enabled || notifier == nil {
+		return nil
+	}
+	return &Verifier{
+		ttl:      cfg.TTL,
+		link:     strings.TrimRight(baseURL, "/") + "/api/verify",
+		notifier: notifier,
+	}
+}
+
+// -------------------------------------------------------------
+// Send() → Store a fresh token for student and queue its email
+// -------------------------------------------------------------
+// store is tenant-scoped; any earlier link of the student stops working.
+func (v *Verifier) Send(ctx context.Context, store storage.Storage, student types.Student) error {
+	if v == nil {
+		return nil
+	}
+	tokens, ok := storage.As[storage.VerificationStore](store)
+	if !ok {
+		return ErrUnsupported
+	}
+
+	raw := randomHex(24)
+	expiresAt := time.Now().UTC().Add(v.ttl)
+	if _, err := tokens.CreateVerificationToken(types.VerificationToken{
+		StudentID: student.ID,
+		Hash:      apikey.Hash(raw),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	v.notifier.Verify(ctx, student, v.link+"?"+url.Values{"token": {raw}}.Encode(), expiresAt)
+	return nil
+}
+
+// -------------------------------------------------------------
+// Verify() → Use up the token from a link and verify its student
+// -------------------------------------------------------------
+// Returns storage.ErrVerificationInvalid or storage.ErrVerificationExpired
+// for links that can't be used.
+func (v *Verifier) Verify(store storage.Storage, raw string) (types.VerificationToken, error) {
+	tokens, ok := storage.As[storage.VerificationStore](store)
+	if !ok {
+		return types.VerificationToken{}, ErrUnsupported
+	}
+	if raw == "" {
+		return types.VerificationToken{}, storage.ErrVerificationInvalid
+	}
+	return tokens.VerifyStudent(apikey.Hash(raw), time.Now().UTC())
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
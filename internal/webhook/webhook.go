@@ -0,0 +1,244 @@
+// Package webhook notifies subscribed URLs of student lifecycle events.
+// Events come from the outbox relay; each becomes one delivery row per
+// matching webhook. Deliveries are sent by the job queue, so failures retry
+// with exponential backoff and survive restarts.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/config"
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+	"github.com/manish-npx/go-student-api/internal/jobs"
+	"github.com/manish-npx/go-student-api/internal/storage"
+	"github.com/manish-npx/go-student-api/internal/tenant"
+	"github.com/manish-npx/go-student-api/internal/types"
+)
+
+// Student lifecycle events
+const (
+	StudentCreated  = types.EventStudentCreated
+	StudentUpdated  = types.EventStudentUpdated
+	StudentDeleted  = types.EventStudentDeleted
+	StudentRestored = types.EventStudentRestored
+	// StudentErased carries only the id: the data is gone (GDPR erasure)
+	StudentErased = types.EventStudentErased
+)
+
+// TypeDeliver is the job type that sends one delivery
+const TypeDeliver = "webhook.deliver"
+
+// Headers set on every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature is "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Envelope is the JSON body POSTed to subscribers
+type Envelope struct {
+	// ID is the outbox event's id: the same for every delivery of one event,
+	// so subscribers can drop the rare duplicate
+	ID        int64     `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// deliverPayload is the job payload; deliveries are tenant-scoped rows
+type deliverPayload struct {
+	TenantID   int64 `json:"tenant_id"`
+	DeliveryID int64 `json:"delivery_id"`
+	// Trace is the request that raised the event, forwarded to subscribers
+	Trace httpclient.Trace `json:"trace"`
+}
+
+// Dispatcher turns events into deliveries and hands them to the job queue.
+// A nil *Dispatcher (no job queue) drops events.
+type Dispatcher struct {
+	backend storage.Storage
+	queue   *jobs.Queue
+	client  *http.Client
+}
+
+// -------------------------------------------------------------
+// New() → Dispatcher registered as the webhook.deliver job handler
+// -------------------------------------------------------------
+// Returns nil when there is no job queue or the backend has no webhook tables.
+func New(backend storage.Storage, queue *jobs.Queue, cfg config.Webhooks) *Dispatcher {
+	if _, ok := storage.As[storage.WebhookStore](backend); !ok || queue == nil {
+		return nil
+	}
+	d := &Dispatcher{
+		backend: backend,
+		queue:   queue,
+		// no client retries: failed deliveries are retried by the job queue
+		client: httpclient.New(httpclient.Options{Timeout: cfg.Timeout, UserAgent: "go-student-api-webhooks"}),
+	}
+	queue.Register(TypeDeliver, d.deliver)
+	return d
+}
+
+// -------------------------------------------------------------
+// Publish() → Queue an event for every subscribed webhook of its tenant
+// -------------------------------------------------------------
+// Called by the outbox relay. An error leaves the event in the outbox to be
+// published again, so webhooks already queued for it may get it twice.
+func (d *Dispatcher) Publish(ctx context.Context, event types.OutboxEvent) error {
+	if d == nil {
+		return nil
+	}
+	hooks, ok := storage.As[storage.WebhookStore](tenant.Scope(tenant.WithTenant(ctx, types.Tenant{ID: event.TenantID}), d.backend))
+	if !ok {
+		return nil
+	}
+
+	// 🪪 Deliveries join the trace of the request that made the change
+	var trace httpclient.Trace
+	if len(event.Trace) > 0 {
+		if err := json.Unmarshal(event.Trace, &trace); err != nil {
+			slog.Warn("⚠️ Ignoring malformed event trace", slog.Int64("event_id", event.ID), slog.String("error", err.Error()))
+		}
+	}
+
+	subscribed, err := hooks.GetWebhooks()
+	if err != nil {
+		return fmt.Errorf("load webhooks: %w", err)
+	}
+
+	var body []byte
+	for _, hook := range subscribed {
+		if !Subscribed(hook, event.Event) {
+			continue
+		}
+		if body == nil {
+			envelope := Envelope{ID: event.ID, Event: event.Event, CreatedAt: event.CreatedAt.UTC(), Data: event.Payload}
+			if body, err = json.Marshal(envelope); err != nil {
+				return fmt.Errorf("encode webhook payload: %w", err)
+			}
+		}
+
+		id, err := hooks.CreateWebhookDelivery(types.WebhookDelivery{
+			WebhookID: hook.ID,
+			Event:     event.Event,
+			Payload:   body,
+			Status:    types.DeliveryPending,
+		})
+		if err == nil {
+			_, err = d.queue.Enqueue(TypeDeliver, deliverPayload{TenantID: event.TenantID, DeliveryID: id, Trace: trace})
+		}
+		if err != nil {
+			return fmt.Errorf("queue delivery to webhook %d: %w", hook.ID, err)
+		}
+	}
+	return nil
+}
+
+// Subscribed reports whether hook wants event ("*" matches everything)
+func Subscribed(hook types.Webhook, event string) bool {
+	return slices.Contains(hook.Events, event) || slices.Contains(hook.Events, "*")
+}
+
+// Sign computes the HeaderSignature value for a delivery
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// deliver is the webhook.deliver job: one HTTP attempt, recorded on the row
+func (d *Dispatcher) deliver(ctx context.Context, job types.Job) error {
+	var payload deliverPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode delivery payload: %w", err))
+	}
+
+	scoped := d.backend
+	if scoper, ok := storage.As[storage.TenantScoper](d.backend); ok {
+		scoped = scoper.ForTenant(payload.TenantID)
+	}
+	hooks := scoped.(storage.WebhookStore)
+
+	delivery, err := hooks.GetWebhookDeliveryById(payload.DeliveryID)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	hook, err := hooks.GetWebhookById(delivery.WebhookID)
+	if err != nil {
+		// the webhook was deleted after the event was queued
+		return jobs.Permanent(err)
+	}
+
+	status, sendErr := d.send(httpclient.WithTrace(ctx, payload.Trace), hook, delivery)
+
+	delivery.Attempts = job.Attempts
+	delivery.ResponseStatus = status
+	delivery.LastError = ""
+	switch {
+	case sendErr == nil:
+		now := time.Now().UTC()
+		delivery.Status = types.DeliverySucceeded
+		delivery.DeliveredAt = &now
+	case job.Attempts >= job.MaxAttempts || jobs.IsPermanent(sendErr):
+		delivery.Status = types.DeliveryFailed
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.Status = types.DeliveryPending
+		delivery.LastError = sendErr.Error()
+	}
+	if err := hooks.UpdateWebhookDelivery(delivery); err != nil {
+		slog.Error("❌ Recording webhook delivery failed", slog.Int64("delivery_id", delivery.ID), slog.String("error", err.Error()))
+	}
+	return sendErr
+}
+
+// send POSTs the signed payload; any non-2xx answer is an error
+func (d *Dispatcher) send(ctx context.Context, hook types.Webhook, delivery types.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, jobs.Permanent(fmt.Errorf("build request: %w", err))
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// drain a little so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("endpoint answered %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// -------------------------------------------------------------
+// Login() → Trade the API key for bearer tokens the client then manages
+// -------------------------------------------------------------
+// Needs WithAPIKey and auth.token_secret on the server. Later calls send
+// the access token; when it has expired (401) the client refreshes the
+// pair once and repeats the call.
+func (c *Client) Login(ctx context.Context) (TokenPair, error) {
+	if c.apiKey == "" {
+		return TokenPair{}, errors.New("client: Login needs WithAPIKey")
+	}
+	var pair TokenPair
+	res, err := c.send(ctx, http.MethodPost, "/api/auth/token", nil, nil, "")
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if err := decode(res, &pair); err != nil {
+		return TokenPair{}, err
+	}
+	c.setTokens(&pair)
+	return pair, nil
+}
+
+// -------------------------------------------------------------
+// Logout() → Revoke the access token and its refresh tokens
+// -------------------------------------------------------------
+// Later calls go back to the API key.
+func (c *Client) Logout(ctx context.Context) error {
+	if err := c.Do(ctx, http.MethodPost, "/api/auth/logout", nil, nil, nil); err != nil {
+		return err
+	}
+	c.setTokens(nil)
+	return nil
+}
+
+func (c *Client) accessToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tokens == nil {
+		return ""
+	}
+	return c.tokens.AccessToken
+}
+
+func (c *Client) canRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens != nil && c.tokens.RefreshToken != ""
+}
+
+func (c *Client) setTokens(pair *TokenPair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = pair
+}
+
+// refresh trades the refresh token for a new pair, unless another call
+// already replaced the expired access token. Holding mu keeps two calls
+// from spending the same refresh token: a replayed one revokes the session.
+func (c *Client) refresh(ctx context.Context, expired string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tokens == nil || c.tokens.AccessToken != expired {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"refresh_token": c.tokens.RefreshToken})
+	if err != nil {
+		return err
+	}
+	res, err := c.send(ctx, http.MethodPost, "/api/auth/refresh", nil, body, "")
+	if err != nil {
+		return err
+	}
+	var pair TokenPair
+	if err := decode(res, &pair); err != nil {
+		// the session is gone; fall back to the API key
+		c.tokens = nil
+		return err
+	}
+	c.tokens = &pair
+	return nil
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoginRefreshesExpiredTokensOnce(t *testing.T) {
+	var mu sync.Mutex
+	var refreshes int
+	valid := map[string]bool{"a1": true}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/auth/token":
+			if r.Header.Get("X-API-Key") != "k" {
+				http.Error(w, "no key", http.StatusUnauthorized)
+				return
+			}
+			writeJSON(w, http.StatusOK, TokenPair{AccessToken: "a1", TokenType: "Bearer", RefreshToken: "r1"})
+		case "/api/auth/refresh":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["refresh_token"] != "r1" {
+				http.Error(w, "spent", http.StatusUnauthorized)
+				return
+			}
+			refreshes++
+			valid["a2"] = true
+			writeJSON(w, http.StatusOK, TokenPair{AccessToken: "a2", TokenType: "Bearer", RefreshToken: "r2"})
+		default:
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !valid[token] {
+				http.Error(w, "expired", http.StatusUnauthorized)
+				return
+			}
+			writeJSON(w, http.StatusOK, Student{ID: 1})
+		}
+	}, WithAPIKey("k"))
+
+	ctx := context.Background()
+	if _, err := c.Login(ctx); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := c.GetStudent(ctx, 1); err != nil {
+		t.Fatalf("GetStudent: %v", err)
+	}
+
+	// the access token expires: the next call refreshes and is repeated
+	mu.Lock()
+	delete(valid, "a1")
+	mu.Unlock()
+	if _, err := c.GetStudent(ctx, 1); err != nil {
+		t.Fatalf("GetStudent after expiry: %v", err)
+	}
+	if _, err := c.GetStudent(ctx, 1); err != nil {
+		t.Fatalf("GetStudent with the new token: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if refreshes != 1 || c.accessToken() != "a2" {
+		t.Fatalf("refreshes = %d, token %q; want 1, a2", refreshes, c.accessToken())
+	}
+}
+
+func TestLoginNeedsAnAPIKey(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})
+	if _, err := c.Login(context.Background()); err == nil {
+		t.Fatal("Login without an API key = nil error")
+	}
+}
+
+func TestFailedRefreshFallsBackToTheAPIKey(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/auth/token":
+			writeJSON(w, http.StatusOK, TokenPair{AccessToken: "a1", TokenType: "Bearer", RefreshToken: "r1"})
+		case r.URL.Path == "/api/auth/refresh":
+			http.Error(w, "revoked", http.StatusUnauthorized)
+		case r.Header.Get("X-API-Key") == "k" && r.Header.Get("Authorization") == "":
+			writeJSON(w, http.StatusOK, Student{ID: 1})
+		default:
+			http.Error(w, "expired", http.StatusUnauthorized)
+		}
+	}, WithAPIKey("k"))
+
+	ctx := context.Background()
+	if _, err := c.Login(ctx); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := c.GetStudent(ctx, 1); StatusOf(err) != http.StatusUnauthorized {
+		t.Fatalf("GetStudent with a revoked session = %v, want 401", err)
+	}
+	if _, err := c.GetStudent(ctx, 1); err != nil {
+		t.Fatalf("GetStudent with the API key: %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+// Package client is a typed Go client for the student API, for other Go
+// services that call it. Methods take a context, send the tenant and
+// credentials on every request, retry transient failures of requests safe
+// to repeat and return failures as *Error, the API's RFC 7807 problem.
+//
+//	c, err := client.New("https://students.example.com",
+//		client.WithAPIKey(os.Getenv("STUDENT_API_KEY")),
+//		client.WithTenant("acme"))
+//	student, err := c.GetStudent(ctx, 42)
+//
+// Records are the API's own types (client.Student is types.Student), so
+// they encode exactly as the server reads them. Endpoints without a method
+// here can be called through Do.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/go-student-api/internal/httpclient"
+)
+
+// Client calls one deployment of the API. It is safe for concurrent use.
+type Client struct {
+	base      *url.URL
+	http      *http.Client
+	opts      httpclient.Options
+	apiKey    string
+	tenant    string
+	tenantHdr string
+
+	// mu guards the bearer tokens Login hands out
+	mu     sync.Mutex
+	tokens *TokenPair
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithAPIKey sends key as X-API-Key (auth.enabled deployments).
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken sends token as Authorization: Bearer, e.g. one obtained
+// elsewhere; it isn't refreshed. Use Login to have the client manage tokens.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.tokens = &TokenPair{AccessToken: token, TokenType: "Bearer"} }
+}
+
+// WithTenant names the tenant (school) every request acts on.
+func WithTenant(slug string) Option {
+	return func(c *Client) { c.tenant = slug }
+}
+
+// WithTenantHeader changes the header the tenant is sent in, for
+// deployments that set tenancy.header (default X-Tenant).
+func WithTenantHeader(name string) Option {
+	return func(c *Client) { c.tenantHdr = name }
+}
+
+// WithRetries repeats a request that failed with a network error, 429,
+// 502, 503 or 504 up to n times (default 2), starting backoff apart and
+// doubling, or after the server's Retry-After. Only GET, HEAD, PUT and
+// DELETE are repeated: a repeated POST could create a record twice.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) { c.opts.Retries, c.opts.Backoff = n, backoff }
+}
+
+// WithTimeout bounds each call, retries included (default 30s).
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.opts.Timeout = d }
+}
+
+// WithUserAgent sets the User-Agent sent with each request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.opts.UserAgent = ua }
+}
+
+// WithHTTPClient sends requests through hc as is, in place of the
+// client's own retrying one (WithRetries, WithTimeout and WithUserAgent no
+// longer apply).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// -------------------------------------------------------------
+// New() → Client for the API at baseURL, e.g. https://students.example.com
+// -------------------------------------------------------------
+func New(baseURL string, opts ...Option) (*Client, error) {
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: base URL: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("client: base URL %q must be http or https", baseURL)
+	}
+
+	c := &Client{
+		base:      base,
+		tenantHdr: "X-Tenant",
+		opts:      httpclient.Options{Retries: 2, UserAgent: "go-student-api-client"},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.http == nil {
+		c.http = httpclient.New(c.opts)
+	}
+	return c, nil
+}
+
+// Error is a failed call: the RFC 7807 problem the API answered with.
+// Key on Status and the Field of Errors, not on the wording of Detail.
+type Error struct {
+	Status   int    `json:"status"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+	// Errors lists the invalid fields of the request, when it had any
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("student api: %d %s", e.Status, e.Title)
+	}
+	return fmt.Sprintf("student api: %d %s: %s", e.Status, e.Title, e.Detail)
+}
+
+// StatusOf is the HTTP status of an *Error in err's chain, 0 when the call
+// failed before the API answered.
+func StatusOf(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	return 0
+}
+
+// -------------------------------------------------------------
+// Do() → Call any endpoint: in is sent as JSON, the answer decoded into out
+// -------------------------------------------------------------
+// path is below the base URL, e.g. "/api/admin/stats". in and out may be
+// nil. A 4xx or 5xx answer is returned as *Error.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("client: encode %s %s: %w", method, path, err)
+		}
+	}
+
+	token := c.accessToken()
+	res, err := c.send(ctx, method, path, query, body, token)
+	if err != nil {
+		return err
+	}
+
+	// 🔑 An expired access token is refreshed once and the call repeated
+	if res.StatusCode == http.StatusUnauthorized && token != "" && c.canRefresh() {
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		if err := c.refresh(ctx, token); err != nil {
+			return err
+		}
+		if res, err = c.send(ctx, method, path, query, body, c.accessToken()); err != nil {
+			return err
+		}
+	}
+	return decode(res, out)
+}
+
+// send makes one call (its retries included) with the client's headers,
+// authenticated by the bearer token when there is one, else the API key
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body []byte, token string) (*http.Response, error) {
+	// path comes escaped (url.PathEscape), so it is joined as text
+	u, err := url.Parse(c.base.String() + path)
+	if err != nil {
+		return nil, fmt.Errorf("client: %s %s: %w", method, path, err)
+	}
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		// a bytes.Reader lets the transport replay the body on a retry
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: %s %s: %w", method, path, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	// keys as documented, whatever the deployment's json_naming.default
+	req.Header.Set("X-JSON-Naming", "snake_case")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.tenant != "" {
+		req.Header.Set(c.tenantHdr, c.tenant)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: %s %s: %w", method, path, err)
+	}
+	return res, nil
+}
+
+// decode reads res into out (skipped when nil) and closes it; a 4xx or
+// 5xx answer is returned as *Error
+func decode(res *http.Response, out any) error {
+	defer res.Body.Close()
+	if res.StatusCode >= 400 {
+		return decodeError(res)
+	}
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decode %s %s: %w", res.Request.Method, res.Request.URL.Path, err)
+	}
+	return nil
+}
+
+// decodeError reads a problem body; anything else keeps just the status
+func decodeError(res *http.Response) error {
+	apiErr := &Error{}
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if json.Unmarshal(data, apiErr) != nil || apiErr.Status == 0 {
+		apiErr = &Error{Detail: strings.TrimSpace(string(data))}
+	}
+	apiErr.Status = res.StatusCode
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(res.StatusCode)
+	}
+	return apiErr
+}
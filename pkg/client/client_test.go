@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient points a client at handler, retrying without delay
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL+"/", append([]Option{WithRetries(2, time.Millisecond)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestNewRejectsNonHTTPBaseURL(t *testing.T) {
+	for _, base := range []string{"ftp://example.com", "example.com", "://"} {
+		if _, err := New(base); err == nil {
+			t.Errorf("New(%q) = nil error, want one", base)
+		}
+	}
+}
+
+func TestSendsTenantCredentialsAndNaming(t *testing.T) {
+	var got http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		if r.URL.Path != "/api/student/7" {
+			t.Errorf("path = %s, want /api/student/7", r.URL.Path)
+		}
+		writeJSON(w, http.StatusOK, Student{ID: 7, Name: "Ada"})
+	}, WithAPIKey("k"), WithTenant("acme"), WithTenantHeader("X-School"), WithUserAgent("sis-sync"))
+
+	s, err := c.GetStudent(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetStudent: %v", err)
+	}
+	if s.ID != 7 || s.Name != "Ada" {
+		t.Fatalf("student = %+v", s)
+	}
+	for header, want := range map[string]string{
+		"X-API-Key":     "k",
+		"X-School":      "acme",
+		"X-Tenant":      "",
+		"X-JSON-Naming": "snake_case",
+		"Accept":        "application/json",
+		"User-Agent":    "sis-sync",
+		"Authorization": "",
+	} {
+		if got.Get(header) != want {
+			t.Errorf("%s = %q, want %q", header, got.Get(header), want)
+		}
+	}
+}
+
+func TestBearerTokenReplacesAPIKey(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t" || r.Header.Get("X-API-Key") != "" {
+			t.Errorf("credentials = %q / %q, want just the bearer token", r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}, WithAPIKey("k"), WithBearerToken("t"))
+
+	if err := c.DeleteStudent(context.Background(), 1); err != nil {
+		t.Fatalf("DeleteStudent: %v", err)
+	}
+}
+
+func TestProblemsBecomeErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/student/1" {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type":"about:blank","title":"Bad Request","status":400,"detail":"validation failed",` +
+				`"errors":[{"field":"email","message":"email is required"}]}`))
+			return
+		}
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	})
+
+	_, err := c.UpdateStudent(context.Background(), 1, Student{})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v (%T), want *Error", err, err)
+	}
+	if apiErr.Status != http.StatusBadRequest || apiErr.Detail != "validation failed" ||
+		len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "email" {
+		t.Fatalf("problem = %+v", apiErr)
+	}
+
+	// a body that isn't a problem keeps the status and the text
+	_, err = c.CreateStudent(context.Background(), Student{})
+	if StatusOf(err) != http.StatusBadGateway || !errors.As(err, &apiErr) ||
+		apiErr.Title != "Bad Gateway" || apiErr.Detail != "upstream down" {
+		t.Fatalf("err = %v, want a 502 with the body as detail", err)
+	}
+	if StatusOf(errors.New("dial tcp: refused")) != 0 {
+		t.Fatal("StatusOf a network error != 0")
+	}
+}
+
+func TestRetriesOnlyRequestsSafeToRepeat(t *testing.T) {
+	var gets, posts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if gets.Add(1) < 3 {
+				http.Error(w, "busy", http.StatusServiceUnavailable)
+				return
+			}
+			writeJSON(w, http.StatusOK, Student{ID: 1})
+		default:
+			posts.Add(1)
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	})
+
+	if _, err := c.GetStudent(context.Background(), 1); err != nil {
+		t.Fatalf("GetStudent after two 503s: %v", err)
+	}
+	if gets.Load() != 3 {
+		t.Fatalf("GET sent %d times, want 3", gets.Load())
+	}
+
+	if _, err := c.CreateStudent(context.Background(), Student{Name: "Ada"}); StatusOf(err) != http.StatusServiceUnavailable {
+		t.Fatalf("CreateStudent err = %v, want the 503", err)
+	}
+	if posts.Load() != 1 {
+		t.Fatalf("POST sent %d times, want 1", posts.Load())
+	}
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// CreateGuardian saves a guardian (POST /api/guardians).
+func (c *Client) CreateGuardian(ctx context.Context, g Guardian) (Guardian, error) {
+	var created Guardian
+	err := c.Do(ctx, http.MethodPost, "/api/guardians", nil, g, &created)
+	return created, err
+}
+
+// ListGuardians lists the tenant's guardians by name (GET /api/guardians).
+func (c *Client) ListGuardians(ctx context.Context) ([]Guardian, error) {
+	var list []Guardian
+	err := c.Do(ctx, http.MethodGet, "/api/guardians", nil, nil, &list)
+	return list, err
+}
+
+// GetGuardian fetches a guardian with the students they are linked to
+// (GET /api/guardians/{id}).
+func (c *Client) GetGuardian(ctx context.Context, id int64) (Guardian, error) {
+	var g Guardian
+	err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/guardians/%d", id), nil, nil, &g)
+	return g, err
+}
+
+// UpdateGuardian replaces a guardian's name, email and phone
+// (PUT /api/guardians/{id}).
+func (c *Client) UpdateGuardian(ctx context.Context, id int64, g Guardian) (Guardian, error) {
+	var updated Guardian
+	err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/guardians/%d", id), nil, g, &updated)
+	return updated, err
+}
+
+// DeleteGuardian deletes a guardian and unlinks them from every student
+// (DELETE /api/guardians/{id}).
+func (c *Client) DeleteGuardian(ctx context.Context, id int64) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/guardians/%d", id), nil, nil, nil)
+}
+
+// StudentGuardians lists a student's guardians
+// (GET /api/student/{id}/guardians).
+func (c *Client) StudentGuardians(ctx context.Context, studentID int64) ([]StudentGuardian, error) {
+	var list []StudentGuardian
+	err := c.Do(ctx, http.MethodGet, studentPath(studentID, "/guardians"), nil, nil, &list)
+	return list, err
+}
+
+// LinkGuardian links a guardian to a student as relationship (mother,
+// father, parent, guardian, grandparent, sibling or other); linking again
+// changes the relationship.
+func (c *Client) LinkGuardian(ctx context.Context, studentID, guardianID int64, relationship string) (GuardianLink, error) {
+	var link GuardianLink
+	body := map[string]string{"relationship": relationship}
+	err := c.Do(ctx, http.MethodPut, studentPath(studentID, fmt.Sprintf("/guardians/%d", guardianID)), nil, body, &link)
+	return link, err
+}
+
+// UnlinkGuardian unlinks a guardian from a student; the guardian stays.
+func (c *Client) UnlinkGuardian(ctx context.Context, studentID, guardianID int64) error {
+	return c.Do(ctx, http.MethodDelete, studentPath(studentID, fmt.Sprintf("/guardians/%d", guardianID)), nil, nil, nil)
+}
+
+// CreateInvoice bills a student (POST /api/student/{id}/invoices); amount
+// is in minor units.
+func (c *Client) CreateInvoice(ctx context.Context, studentID int64, inv Invoice) (Invoice, error) {
+	var created Invoice
+	err := c.Do(ctx, http.MethodPost, studentPath(studentID, "/invoices"), nil, inv, &created)
+	return created, err
+}
+
+// StudentInvoices lists a student's invoices, oldest first
+// (GET /api/student/{id}/invoices).
+func (c *Client) StudentInvoices(ctx context.Context, studentID int64) ([]Invoice, error) {
+	var list []Invoice
+	err := c.Do(ctx, http.MethodGet, studentPath(studentID, "/invoices"), nil, nil, &list)
+	return list, err
+}
+
+// GetInvoice fetches one invoice (GET /api/invoices/{id}).
+func (c *Client) GetInvoice(ctx context.Context, id int64) (Invoice, error) {
+	var inv Invoice
+	err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/invoices/%d", id), nil, nil, &inv)
+	return inv, err
+}
+
+// PayInvoice marks an invoice paid by the payment reference
+// (POST /api/invoices/{id}/pay); paying again with the same reference is a
+// no-op.
+func (c *Client) PayInvoice(ctx context.Context, id int64, reference string) (Invoice, error) {
+	var inv Invoice
+	body := map[string]string{"payment_reference": reference}
+	err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/invoices/%d/pay", id), nil, body, &inv)
+	return inv, err
+}
+
+// OverdueInvoices reports the open invoices due before asOf (YYYY-MM-DD,
+// "" for today) with totals per currency (GET /api/invoices/overdue).
+func (c *Client) OverdueInvoices(ctx context.Context, asOf string) (OverdueReport, error) {
+	q := url.Values{}
+	if asOf != "" {
+		q.Set("as_of", asOf)
+	}
+	var report OverdueReport
+	err := c.Do(ctx, http.MethodGet, "/api/invoices/overdue", q, nil, &report)
+	return report, err
+}
+
+// AgeDistribution counts the students per age bucket
+// (GET /api/reports/age-distribution).
+func (c *Client) AgeDistribution(ctx context.Context) (AgeDistribution, error) {
+	var dist AgeDistribution
+	err := c.Do(ctx, http.MethodGet, "/api/reports/age-distribution", nil, nil, &dist)
+	return dist, err
+}
+
+// Birthdays counts the students born in month (1-12), per day of it
+// (GET /api/reports/birthdays).
+func (c *Client) Birthdays(ctx context.Context, month int) (BirthdayReport, error) {
+	var report BirthdayReport
+	q := url.Values{"month": {strconv.Itoa(month)}}
+	err := c.Do(ctx, http.MethodGet, "/api/reports/birthdays", q, nil, &report)
+	return report, err
+}
+
+// StartExport queues a dump of the tenant's students as format (csv or
+// json) (POST /api/exports); poll it with GetExport.
+func (c *Client) StartExport(ctx context.Context, format string) (Export, error) {
+	var exp Export
+	err := c.Do(ctx, http.MethodPost, "/api/exports", url.Values{"format": {format}}, nil, &exp)
+	return exp, err
+}
+
+// GetExport reports an export's status; URL is set once the file is ready
+// (GET /api/exports/{id}).
+func (c *Client) GetExport(ctx context.Context, id int64) (Export, error) {
+	var exp Export
+	err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/exports/%d", id), nil, nil, &exp)
+	return exp, err
+}
+
+// CreateTenant adds a tenant (POST /api/admin/tenants; admin scope).
+func (c *Client) CreateTenant(ctx context.Context, t Tenant) (Tenant, error) {
+	var created Tenant
+	err := c.Do(ctx, http.MethodPost, "/api/admin/tenants", nil, t, &created)
+	return created, err
+}
+
+// ListTenants lists every tenant (GET /api/admin/tenants; admin scope).
+func (c *Client) ListTenants(ctx context.Context) ([]Tenant, error) {
+	var list []Tenant
+	err := c.Do(ctx, http.MethodGet, "/api/admin/tenants", nil, nil, &list)
+	return list, err
+}
+
+// MigrateEmailDomain moves the emails of tenant's students from one
+// domain to another (POST /api/admin/email-domains/migrate; admin scope).
+// With dryRun nothing is written. tenant "" is the default tenant.
+func (c *Client) MigrateEmailDomain(ctx context.Context, tenant, from, to string, dryRun bool) (EmailDomainMigration, error) {
+	q := url.Values{"dry_run": {strconv.FormatBool(dryRun)}}
+	if tenant != "" {
+		q.Set("tenant", tenant)
+	}
+	var result EmailDomainMigration
+	body := map[string]string{"from": from, "to": to}
+	err := c.Do(ctx, http.MethodPost, "/api/admin/email-domains/migrate", q, body, &result)
+	return result, err
+}
+
+// Version reports the server's build and schema version (GET /api/version).
+func (c *Client) Version(ctx context.Context) (Version, error) {
+	var v Version
+	err := c.Do(ctx, http.MethodGet, "/api/version", nil, nil, &v)
+	return v, err
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// studentWrite is the answer to a create, update, upsert or restore
+type studentWrite struct {
+	Student StudentResource `json:"student"`
+	Created bool            `json:"created"`
+}
+
+// CreateStudent creates s (POST /api/student) and returns it as stored,
+// with its id and public_id.
+func (c *Client) CreateStudent(ctx context.Context, s Student) (StudentResource, error) {
+	var res studentWrite
+	err := c.Do(ctx, http.MethodPost, "/api/student", nil, s, &res)
+	return res.Student, err
+}
+
+// ValidateStudent runs every check of a create without saving s
+// (?validate_only=true); a taken email is a 409 *Error.
+func (c *Client) ValidateStudent(ctx context.Context, s Student) error {
+	return c.Do(ctx, http.MethodPost, "/api/student", url.Values{"validate_only": {"true"}}, s, nil)
+}
+
+// GetStudent fetches one student (GET /api/student/{id}).
+func (c *Client) GetStudent(ctx context.Context, id int64) (StudentResource, error) {
+	var s StudentResource
+	err := c.Do(ctx, http.MethodGet, studentPath(id, ""), nil, nil, &s)
+	return s, err
+}
+
+// GetStudents fetches up to 100 students in one call
+// (POST /api/students/batch-get); ids that don't exist come back in Missing.
+func (c *Client) GetStudents(ctx context.Context, ids []int64) (StudentBatch, error) {
+	var batch StudentBatch
+	err := c.Do(ctx, http.MethodPost, "/api/students/batch-get", nil, map[string][]int64{"ids": ids}, &batch)
+	return batch, err
+}
+
+// ListStudents fetches every student matching f in one call
+// (GET /api/students). The server refuses lists longer than
+// pagination.max_limit; walk those with Students instead.
+func (c *Client) ListStudents(ctx context.Context, f StudentFilter) ([]StudentResource, error) {
+	var list []StudentResource
+	err := c.Do(ctx, http.MethodGet, "/api/students", f.query(), nil, &list)
+	return list, err
+}
+
+// ListStudentsPage fetches one page of up to limit students matching f,
+// from cursor on ("" for the first page).
+func (c *Client) ListStudentsPage(ctx context.Context, f StudentFilter, limit int, cursor string) (Page[StudentResource], error) {
+	q := f.query()
+	q.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	var page Page[StudentResource]
+	err := c.Do(ctx, http.MethodGet, "/api/students", q, nil, &page)
+	return page, err
+}
+
+// -------------------------------------------------------------
+// Students() → Every student matching f, fetched limit at a time
+// -------------------------------------------------------------
+// Iteration stops at the first error, which is yielded with a zero student.
+//
+//	for s, err := range c.Students(ctx, client.StudentFilter{}, 100) { ... }
+func (c *Client) Students(ctx context.Context, f StudentFilter, limit int) iter.Seq2[StudentResource, error] {
+	return func(yield func(StudentResource, error) bool) {
+		cursor := ""
+		for {
+			page, err := c.ListStudentsPage(ctx, f, limit, cursor)
+			if err != nil {
+				yield(StudentResource{}, err)
+				return
+			}
+			for _, s := range page.Data {
+				if !yield(s, nil) {
+					return
+				}
+			}
+			if !page.HasMore {
+				return
+			}
+			cursor = page.NextCursor
+		}
+	}
+}
+
+// CountStudents counts the students matching f (GET /api/students/count).
+func (c *Client) CountStudents(ctx context.Context, f StudentFilter) (int64, error) {
+	var res struct {
+		Count int64 `json:"count"`
+	}
+	err := c.Do(ctx, http.MethodGet, "/api/students/count", f.query(), nil, &res)
+	return res.Count, err
+}
+
+// UpdateStudent replaces every field of a student (PUT /api/student/{id}).
+func (c *Client) UpdateStudent(ctx context.Context, id int64, s Student) (StudentResource, error) {
+	var res studentWrite
+	err := c.Do(ctx, http.MethodPut, studentPath(id, ""), nil, s, &res)
+	return res.Student, err
+}
+
+// UpsertStudent creates or updates the student with s.Email
+// (PUT /api/students/by-email/{email}); created tells which it was.
+func (c *Client) UpsertStudent(ctx context.Context, s Student) (student StudentResource, created bool, err error) {
+	var res studentWrite
+	err = c.Do(ctx, http.MethodPut, "/api/students/by-email/"+url.PathEscape(s.Email), nil, s, &res)
+	return res.Student, res.Created, err
+}
+
+// DeleteStudent moves a student to the trash (DELETE /api/student/{id}).
+func (c *Client) DeleteStudent(ctx context.Context, id int64) error {
+	return c.Do(ctx, http.MethodDelete, studentPath(id, ""), nil, nil, nil)
+}
+
+// RestoreStudent takes a student back out of the trash
+// (POST /api/student/{id}/restore).
+func (c *Client) RestoreStudent(ctx context.Context, id int64) (StudentResource, error) {
+	var res studentWrite
+	err := c.Do(ctx, http.MethodPost, studentPath(id, "/restore"), nil, nil, &res)
+	return res.Student, err
+}
+
+// Trash lists the soft-deleted students (GET /api/students/trash).
+func (c *Client) Trash(ctx context.Context) ([]Student, error) {
+	var list []Student
+	err := c.Do(ctx, http.MethodGet, "/api/students/trash", nil, nil, &list)
+	return list, err
+}
+
+// BulkUpdateStudents applies changes to up to 1000 students in one
+// transaction (PATCH /api/students/bulk); a failed id doesn't stop the rest.
+func (c *Client) BulkUpdateStudents(ctx context.Context, ids []int64, changes StudentChanges) (BulkResponse, error) {
+	var res BulkResponse
+	body := map[string]any{"ids": ids, "changes": changes}
+	err := c.Do(ctx, http.MethodPatch, "/api/students/bulk", nil, body, &res)
+	return res, err
+}
+
+// BulkDeleteStudents moves up to 1000 students to the trash in one
+// transaction (DELETE /api/students/bulk).
+func (c *Client) BulkDeleteStudents(ctx context.Context, ids []int64) (BulkResponse, error) {
+	var res BulkResponse
+	err := c.Do(ctx, http.MethodDelete, "/api/students/bulk", nil, map[string][]int64{"ids": ids}, &res)
+	return res, err
+}
+
+// SendVerification emails a student a new verification link
+// (POST /api/student/{id}/verify); 409 when they are already verified.
+func (c *Client) SendVerification(ctx context.Context, id int64) error {
+	return c.Do(ctx, http.MethodPost, studentPath(id, "/verify"), nil, nil, nil)
+}
+
+// studentPath is /api/student/{id} followed by sub
+func studentPath(id int64, sub string) string {
+	return fmt.Sprintf("/api/student/%d%s", id, sub)
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestStudentsWalksEveryPage(t *testing.T) {
+	all := []StudentResource{{Student: Student{ID: 1}}, {Student: Student{ID: 2}}, {Student: Student{ID: 3}}}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "2" || q.Get("custom.house") != "gryffindor" || q.Get("verified") != "true" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		from, _ := strconv.Atoi(q.Get("cursor"))
+		to := min(from+2, len(all))
+		page := Page[StudentResource]{Data: all[from:to], Limit: 2, HasMore: to < len(all)}
+		if page.HasMore {
+			page.NextCursor = strconv.Itoa(to)
+		}
+		writeJSON(w, http.StatusOK, page)
+	})
+
+	verified := true
+	filter := StudentFilter{Custom: map[string]string{"house": "gryffindor"}, Verified: &verified}
+	var ids []int64
+	for s, err := range c.Students(context.Background(), filter, 2) {
+		if err != nil {
+			t.Fatalf("Students: %v", err)
+		}
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Fatalf("ids = %v, want [1 2 3]", ids)
+	}
+}
+
+func TestStudentsStopsAtTheFirstError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			writeJSON(w, http.StatusOK, Page[StudentResource]{Data: []StudentResource{{Student: Student{ID: 1}}}, HasMore: true, NextCursor: "x"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"title":"Bad Request","status":400,"detail":"invalid cursor"}`))
+	})
+
+	var seen, failed int
+	for _, err := range c.Students(context.Background(), StudentFilter{}, 1) {
+		if err != nil {
+			failed++
+			if StatusOf(err) != http.StatusBadRequest {
+				t.Fatalf("err = %v, want the 400", err)
+			}
+			continue
+		}
+		seen++
+	}
+	if seen != 1 || failed != 1 {
+		t.Fatalf("seen %d students and %d errors, want 1 and 1", seen, failed)
+	}
+}
+
+func TestUpsertEscapesTheEmail(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/api/students/by-email/ada+1@example.com" {
+			t.Errorf("%s %s", r.Method, r.URL.EscapedPath())
+		}
+		writeJSON(w, http.StatusCreated, studentWrite{Student: StudentResource{Student: Student{ID: 9}}, Created: true})
+	})
+
+	s, created, err := c.UpsertStudent(context.Background(), Student{Email: "ada+1@example.com"})
+	if err != nil || !created || s.ID != 9 {
+		t.Fatalf("UpsertStudent = %+v, %v, %v", s, created, err)
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/manish-npx/go-student-api/internal/types"
+	"github.com/manish-npx/go-student-api/internal/utils/response"
+)
+
+// The API's records, under names other modules can import.
+type (
+	Student         = types.Student
+	Address         = types.Address
+	StudentResource = types.StudentResource
+	StudentBatch    = types.StudentBatch
+	StudentChanges  = types.StudentChanges
+	BulkResult      = types.BulkResult
+	Page[T any]     = types.Page[T]
+	Link            = types.Link
+	Links           = types.Links
+
+	Guardian        = types.Guardian
+	GuardianLink    = types.GuardianLink
+	StudentGuardian = types.StudentGuardian
+	Invoice         = types.Invoice
+	OverdueReport   = types.OverdueReport
+
+	AgeDistribution = types.AgeDistribution
+	AgeBucket       = types.AgeBucket
+	BirthdayReport  = types.BirthdayReport
+	DayOfMonthCount = types.DayOfMonthCount
+	Export          = types.Export
+
+	Tenant               = types.Tenant
+	EmailDomainMigration = types.EmailDomainMigration
+	EmailDomainChange    = types.EmailDomainChange
+	TokenPair            = types.TokenPair
+	Version              = types.Version
+
+	FieldError = response.FieldError
+)
+
+// BulkResponse answers PATCH and DELETE /api/students/bulk: how many
+// students were updated or deleted, how many failed, and a result per id.
+type BulkResponse struct {
+	Updated int          `json:"updated"`
+	Deleted int          `json:"deleted"`
+	Failed  int          `json:"failed"`
+	Results []BulkResult `json:"results"`
+}
+
+// StudentFilter narrows lists and counts of students.
+type StudentFilter struct {
+	// Custom matches custom field values, e.g. {"enrollment_year": "2024"}
+	Custom map[string]string
+	// Verified keeps just the verified (or unverified) students
+	Verified *bool
+}
+
+// query is f as GET /api/students parameters
+func (f StudentFilter) query() url.Values {
+	q := url.Values{}
+	for key, value := range f.Custom {
+		q.Set("custom."+key, value)
+	}
+	if f.Verified != nil {
+		q.Set("verified", strconv.FormatBool(*f.Verified))
+	}
+	return q
+}